@@ -184,6 +184,7 @@ type InstanceServer interface {
 	UpdateInstances(state api.InstancesPut, ETag string) (op Operation, err error)
 	RebuildInstance(instanceName string, req api.InstanceRebuildPost) (op Operation, err error)
 	RebuildInstanceFromImage(source ImageServer, image api.Image, instanceName string, req api.InstanceRebuildPost) (op RemoteOperation, err error)
+	PromoteInstance(name string) (op Operation, err error)
 	GetInstanceUEFIVars(name string) (instanceUEFI *api.InstanceUEFIVars, ETag string, err error)
 	UpdateInstanceUEFIVars(name string, instanceUEFI api.InstanceUEFIVars, ETag string) (err error)
 
@@ -214,11 +215,13 @@ type InstanceServer interface {
 	GetInstanceBackupNames(instanceName string) (names []string, err error)
 	GetInstanceBackups(instanceName string) (backups []api.InstanceBackup, err error)
 	GetInstanceBackup(instanceName string, name string) (backup *api.InstanceBackup, ETag string, err error)
+	GetInstanceBackupInfo(instanceName string, name string) (info *api.BackupInfo, err error)
 	CreateInstanceBackup(instanceName string, backup api.InstanceBackupsPost) (op Operation, err error)
 	RenameInstanceBackup(instanceName string, name string, backup api.InstanceBackupPost) (op Operation, err error)
 	DeleteInstanceBackup(instanceName string, name string) (op Operation, err error)
 	GetInstanceBackupFile(instanceName string, name string, req *BackupFileRequest) (resp *BackupFileResponse, err error)
 	CreateInstanceFromBackup(args InstanceBackupArgs) (op Operation, err error)
+	CheckInstanceBackupFile(args InstanceBackupArgs) (info *api.BackupInfo, err error)
 
 	GetInstanceState(name string) (state *api.InstanceState, ETag string, err error)
 	UpdateInstanceState(name string, state api.InstanceStatePut, ETag string) (op Operation, err error)
@@ -227,6 +230,10 @@ type InstanceServer interface {
 	GetInstanceLogfile(name string, filename string) (content io.ReadCloser, err error)
 	DeleteInstanceLogfile(name string, filename string) (err error)
 
+	GetInstanceConsoleRecordings(name string) (recordings []string, err error)
+	GetInstanceConsoleRecording(name string, filename string) (content io.ReadCloser, err error)
+	DeleteInstanceConsoleRecording(name string, filename string) (err error)
+
 	GetInstanceMetadata(name string) (metadata *api.ImageMetadata, ETag string, err error)
 	UpdateInstanceMetadata(name string, metadata api.ImageMetadata, ETag string) (err error)
 
@@ -278,6 +285,7 @@ type InstanceServer interface {
 	GetNetworkLoadBalancerAddresses(networkName string) ([]string, error)
 	GetNetworkLoadBalancers(networkName string) ([]api.NetworkLoadBalancer, error)
 	GetNetworkLoadBalancer(networkName string, listenAddress string) (forward *api.NetworkLoadBalancer, ETag string, err error)
+	GetNetworkLoadBalancerState(networkName string, listenAddress string) (*api.NetworkLoadBalancerState, error)
 	CreateNetworkLoadBalancer(networkName string, forward api.NetworkLoadBalancersPost) error
 	UpdateNetworkLoadBalancer(networkName string, listenAddress string, forward api.NetworkLoadBalancerPut, ETag string) (err error)
 	DeleteNetworkLoadBalancer(networkName string, listenAddress string) (err error)
@@ -296,11 +304,22 @@ type InstanceServer interface {
 	GetNetworkACLsAllProjects() (acls []api.NetworkACL, err error)
 	GetNetworkACL(name string) (acl *api.NetworkACL, ETag string, err error)
 	GetNetworkACLLogfile(name string) (log io.ReadCloser, err error)
+	GetNetworkACLLogStream(name string) (stream io.ReadCloser, err error)
+	GetNetworkACLState(name string) (state *api.NetworkACLState, err error)
 	CreateNetworkACL(acl api.NetworkACLsPost) (err error)
 	UpdateNetworkACL(name string, acl api.NetworkACLPut, ETag string) (err error)
 	RenameNetworkACL(name string, acl api.NetworkACLPost) (err error)
 	DeleteNetworkACL(name string) (err error)
 
+	// Network address set functions ("network_address_sets" API extension)
+	GetNetworkAddressSetNames() (names []string, err error)
+	GetNetworkAddressSets() (sets []api.NetworkAddressSet, err error)
+	GetNetworkAddressSet(name string) (set *api.NetworkAddressSet, ETag string, err error)
+	CreateNetworkAddressSet(set api.NetworkAddressSetsPost) (err error)
+	UpdateNetworkAddressSet(name string, set api.NetworkAddressSetPut, ETag string) (err error)
+	RenameNetworkAddressSet(name string, set api.NetworkAddressSetPost) (err error)
+	DeleteNetworkAddressSet(name string) (err error)
+
 	// Network allocations functions ("network_allocations" API extension)
 	GetNetworkAllocations(allProjects bool) (allocations []api.NetworkAllocations, err error)
 
@@ -355,6 +374,8 @@ type InstanceServer interface {
 	GetStoragePools() (pools []api.StoragePool, err error)
 	GetStoragePool(name string) (pool *api.StoragePool, ETag string, err error)
 	GetStoragePoolResources(name string) (resources *api.ResourcesStoragePool, err error)
+	GetStoragePoolUsage(name string) (usage *api.StoragePoolUsage, err error)
+	GetStoragePoolUsageAllProjects(name string) (usage *api.StoragePoolUsage, err error)
 	CreateStoragePool(pool api.StoragePoolsPost) (err error)
 	UpdateStoragePool(name string, pool api.StoragePoolPut, ETag string) (err error)
 	DeleteStoragePool(name string) (err error)
@@ -390,6 +411,7 @@ type InstanceServer interface {
 	CreateStoragePoolVolume(pool string, volume api.StorageVolumesPost) (op Operation, err error)
 	UpdateStoragePoolVolume(pool string, volType string, name string, volume api.StorageVolumePut, ETag string) (op Operation, err error)
 	RenameStoragePoolVolume(pool string, volType string, name string, volume api.StorageVolumePost) (op Operation, err error)
+	PromoteStoragePoolVolume(pool string, volType string, name string) (op Operation, err error)
 	DeleteStoragePoolVolume(pool string, volType string, name string) (op Operation, err error)
 	CopyStoragePoolVolume(pool string, source InstanceServer, sourcePool string, volume api.StorageVolume, args *StoragePoolVolumeCopyArgs) (op RemoteOperation, err error)
 	MoveStoragePoolVolume(pool string, source InstanceServer, sourcePool string, volume api.StorageVolume, args *StoragePoolVolumeMoveArgs) (op RemoteOperation, err error)
@@ -439,6 +461,8 @@ type InstanceServer interface {
 	DeleteClusterGroup(name string) error
 	UpdateClusterGroup(name string, group api.ClusterGroupPut, ETag string) error
 	GetClusterGroup(name string) (*api.ClusterGroup, string, error)
+	GetClusterRebalance() (*api.ClusterRebalance, error)
+	RebalanceCluster() (op Operation, err error)
 
 	// Warning functions
 	GetWarningUUIDs() (uuids []string, err error)
@@ -495,6 +519,14 @@ type InstanceServer interface {
 	DeletePlacementGroup(placementGroupName string) error
 	RenamePlacementGroup(placementGroupName string, placementGroupPost api.PlacementGroupPost) error
 
+	// Backup target functions ("backup_targets" API extension)
+	GetBackupTargetNames() (names []string, err error)
+	GetBackupTargets() (targets []api.BackupTarget, err error)
+	GetBackupTarget(name string) (target *api.BackupTarget, ETag string, err error)
+	CreateBackupTarget(target api.BackupTargetsPost) (err error)
+	UpdateBackupTarget(name string, target api.BackupTargetPut, ETag string) (err error)
+	DeleteBackupTarget(name string) (err error)
+
 	// Internal functions (for internal use)
 	RawQuery(method string, path string, data any, queryETag string) (resp *api.Response, ETag string, err error)
 	RawWebsocket(path string) (conn *websocket.Conn, err error)
@@ -693,6 +725,9 @@ type StoragePoolVolumeMoveArgs struct {
 
 	// API extension: storage_volume_project_move
 	Project string
+
+	// API extension: storage_volume_live_move
+	Live bool
 }
 
 // The StoragePoolVolumeBackupArgs struct is used when creating a storage volume from a backup.
@@ -703,6 +738,12 @@ type StoragePoolVolumeBackupArgs struct {
 
 	// Name to import backup as
 	Name string
+
+	// PEM encoded private key to decrypt an encrypted backup with
+	Identity []byte
+
+	// Passphrase to decrypt an encrypted backup with
+	Passphrase string
 }
 
 // The InstanceBackupArgs struct is used when creating a instance from a backup.
@@ -718,6 +759,15 @@ type InstanceBackupArgs struct {
 
 	// If set, it would override devices
 	Devices map[string]map[string]string
+
+	// Parent backups of an incremental backup, oldest first
+	ParentFiles []io.Reader
+
+	// PEM encoded private key to decrypt an encrypted backup with
+	Identity []byte
+
+	// Passphrase to decrypt an encrypted backup with
+	Passphrase string
 }
 
 // The InstanceCopyArgs struct is used to pass additional options during instance copy.
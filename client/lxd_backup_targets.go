@@ -0,0 +1,101 @@
+package lxd
+
+import (
+	"net/http"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// GetBackupTargetNames returns a list of backup target names.
+func (r *ProtocolLXD) GetBackupTargetNames() ([]string, error) {
+	err := r.CheckExtension("backup_targets")
+	if err != nil {
+		return nil, err
+	}
+
+	urls := []string{}
+	baseURL := api.NewURL().Path("backup-targets").String()
+	_, err = r.queryStruct(http.MethodGet, baseURL, nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	return urlsToResourceNames(baseURL, urls...)
+}
+
+// GetBackupTargets returns a list of backup targets.
+func (r *ProtocolLXD) GetBackupTargets() ([]api.BackupTarget, error) {
+	err := r.CheckExtension("backup_targets")
+	if err != nil {
+		return nil, err
+	}
+
+	targets := []api.BackupTarget{}
+	_, err = r.queryStruct(http.MethodGet, api.NewURL().Path("backup-targets").WithQuery("recursion", "1").String(), nil, "", &targets)
+	if err != nil {
+		return nil, err
+	}
+
+	return targets, nil
+}
+
+// GetBackupTarget returns a backup target entry for the provided name.
+func (r *ProtocolLXD) GetBackupTarget(name string) (*api.BackupTarget, string, error) {
+	err := r.CheckExtension("backup_targets")
+	if err != nil {
+		return nil, "", err
+	}
+
+	target := api.BackupTarget{}
+	etag, err := r.queryStruct(http.MethodGet, api.NewURL().Path("backup-targets", name).String(), nil, "", &target)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &target, etag, nil
+}
+
+// CreateBackupTarget defines a new backup target using the provided struct.
+func (r *ProtocolLXD) CreateBackupTarget(target api.BackupTargetsPost) error {
+	err := r.CheckExtension("backup_targets")
+	if err != nil {
+		return err
+	}
+
+	_, _, err = r.query(http.MethodPost, api.NewURL().Path("backup-targets").String(), target, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateBackupTarget updates the backup target to match the provided struct.
+func (r *ProtocolLXD) UpdateBackupTarget(name string, target api.BackupTargetPut, ETag string) error {
+	err := r.CheckExtension("backup_targets")
+	if err != nil {
+		return err
+	}
+
+	_, _, err = r.query(http.MethodPut, api.NewURL().Path("backup-targets", name).String(), target, ETag)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteBackupTarget deletes an existing backup target.
+func (r *ProtocolLXD) DeleteBackupTarget(name string) error {
+	err := r.CheckExtension("backup_targets")
+	if err != nil {
+		return err
+	}
+
+	_, _, err = r.query(http.MethodDelete, api.NewURL().Path("backup-targets", name).String(), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
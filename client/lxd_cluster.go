@@ -355,3 +355,34 @@ func (r *ProtocolLXD) GetClusterGroup(name string) (*api.ClusterGroup, string, e
 
 	return &group, etag, nil
 }
+
+// GetClusterRebalance returns the instance moves that would be performed to rebalance the cluster, without performing them.
+func (r *ProtocolLXD) GetClusterRebalance() (*api.ClusterRebalance, error) {
+	err := r.CheckExtension("cluster_rebalance")
+	if err != nil {
+		return nil, err
+	}
+
+	rebalance := api.ClusterRebalance{}
+	_, err = r.queryStruct(http.MethodGet, api.NewURL().Path("cluster", "rebalance").String(), nil, "", &rebalance)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rebalance, nil
+}
+
+// RebalanceCluster moves instances between cluster members to fix placement group violations and load imbalance.
+func (r *ProtocolLXD) RebalanceCluster() (Operation, error) {
+	err := r.CheckExtension("cluster_rebalance")
+	if err != nil {
+		return nil, err
+	}
+
+	op, _, err := r.queryOperation(http.MethodPost, api.NewURL().Path("cluster", "rebalance").String(), nil, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
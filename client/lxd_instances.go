@@ -3,10 +3,12 @@ package lxd
 import (
 	"bufio"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net"
 	"net/http"
 	"net/url"
@@ -345,6 +347,27 @@ func (r *ProtocolLXD) RebuildInstance(instanceName string, instance api.Instance
 	return r.rebuildInstance(instanceName, instance)
 }
 
+// PromoteInstance turns a replica created by storage pool replication into a regular instance.
+func (r *ProtocolLXD) PromoteInstance(name string) (Operation, error) {
+	err := r.CheckExtension("storage_replication")
+	if err != nil {
+		return nil, err
+	}
+
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the request
+	op, _, err := r.queryOperation(http.MethodPost, path+"/"+url.PathEscape(name)+"/promote", nil, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
 // GetInstancesFull returns a list of instances including snapshots, backups and state.
 func (r *ProtocolLXD) GetInstancesFull(instanceType api.InstanceType) ([]api.InstanceFull, error) {
 	instances := []api.InstanceFull{}
@@ -579,29 +602,8 @@ func (r *ProtocolLXD) GetInstanceFull(name string) (*api.InstanceFull, string, e
 	return &instance, etag, nil
 }
 
-// CreateInstanceFromBackup is a convenience function to make it easier to
-// create a instance from a backup.
-func (r *ProtocolLXD) CreateInstanceFromBackup(args InstanceBackupArgs) (Operation, error) {
-	err := r.CheckExtension("container_backup")
-	if err != nil {
-		return nil, err
-	}
-
-	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
-	if err != nil {
-		return nil, err
-	}
-
-	if args.PoolName == "" && args.Name == "" && len(args.Devices) == 0 {
-		// Send the request
-		op, _, err := r.queryOperation(http.MethodPost, path, args.BackupFile, "", true)
-		if err != nil {
-			return nil, err
-		}
-
-		return op, nil
-	}
-
+// instanceBackupRequest prepares the HTTP request that uploads the backup file(s) in args to the given path.
+func (r *ProtocolLXD) instanceBackupRequest(path string, args InstanceBackupArgs) (*http.Request, error) {
 	if args.PoolName != "" {
 		err := r.CheckExtension("container_backup_override_pool")
 		if err != nil {
@@ -617,12 +619,73 @@ func (r *ProtocolLXD) CreateInstanceFromBackup(args InstanceBackupArgs) (Operati
 	}
 
 	if len(args.Devices) > 0 {
-		err = r.CheckExtension("import_instance_devices")
+		err := r.CheckExtension("import_instance_devices")
 		if err != nil {
 			return nil, fmt.Errorf("Cannot use device override: %w", err)
 		}
 	}
 
+	if len(args.Identity) > 0 || args.Passphrase != "" {
+		err := r.CheckExtension("backup_encryption")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	body := args.BackupFile
+	contentType := "application/octet-stream"
+
+	// Incremental backups are sent along with their parent backups as multipart data.
+	if len(args.ParentFiles) > 0 {
+		err := r.CheckExtension("backup_incremental")
+		if err != nil {
+			return nil, err
+		}
+
+		pr, pw := io.Pipe()
+		w := multipart.NewWriter(pw)
+
+		go func() {
+			var ioErr error
+			defer func() {
+				cerr := w.Close()
+				if ioErr == nil && cerr != nil {
+					ioErr = cerr
+				}
+
+				_ = pw.CloseWithError(ioErr)
+			}()
+
+			// Parent backups, oldest first.
+			var fw io.Writer
+			for _, parentFile := range args.ParentFiles {
+				fw, ioErr = w.CreateFormField("parent")
+				if ioErr != nil {
+					return
+				}
+
+				_, ioErr = io.Copy(fw, parentFile)
+				if ioErr != nil {
+					return
+				}
+			}
+
+			// The backup itself.
+			fw, ioErr = w.CreateFormField("backup")
+			if ioErr != nil {
+				return
+			}
+
+			_, ioErr = io.Copy(fw, args.BackupFile)
+			if ioErr != nil {
+				return
+			}
+		}()
+
+		body = pr
+		contentType = w.FormDataContentType()
+	}
+
 	// Prepare the HTTP request
 	reqURL, err := r.setQueryAttributes(r.httpBaseURL.String() + "/1.0" + path)
 
@@ -630,12 +693,12 @@ func (r *ProtocolLXD) CreateInstanceFromBackup(args InstanceBackupArgs) (Operati
 		return nil, err
 	}
 
-	req, err := http.NewRequest(http.MethodPost, reqURL, args.BackupFile)
+	req, err := http.NewRequest(http.MethodPost, reqURL, body)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Type", contentType)
 
 	if args.PoolName != "" {
 		req.Header.Set("X-LXD-pool", args.PoolName)
@@ -645,6 +708,14 @@ func (r *ProtocolLXD) CreateInstanceFromBackup(args InstanceBackupArgs) (Operati
 		req.Header.Set("X-LXD-name", args.Name)
 	}
 
+	if len(args.Identity) > 0 {
+		req.Header.Set("X-LXD-backup-identity", base64.StdEncoding.EncodeToString(args.Identity))
+	}
+
+	if args.Passphrase != "" {
+		req.Header.Set("X-LXD-backup-passphrase", args.Passphrase)
+	}
+
 	if len(args.Devices) > 0 {
 		devProps := url.Values{}
 
@@ -660,6 +731,37 @@ func (r *ProtocolLXD) CreateInstanceFromBackup(args InstanceBackupArgs) (Operati
 		req.Header.Set("X-LXD-devices", devProps.Encode())
 	}
 
+	return req, nil
+}
+
+// CreateInstanceFromBackup is a convenience function to make it easier to
+// create a instance from a backup.
+func (r *ProtocolLXD) CreateInstanceFromBackup(args InstanceBackupArgs) (Operation, error) {
+	err := r.CheckExtension("container_backup")
+	if err != nil {
+		return nil, err
+	}
+
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, err
+	}
+
+	if args.PoolName == "" && args.Name == "" && len(args.Devices) == 0 && len(args.ParentFiles) == 0 {
+		// Send the request
+		op, _, err := r.queryOperation(http.MethodPost, path, args.BackupFile, "", true)
+		if err != nil {
+			return nil, err
+		}
+
+		return op, nil
+	}
+
+	req, err := r.instanceBackupRequest(path, args)
+	if err != nil {
+		return nil, err
+	}
+
 	// Send the request
 	resp, err := r.DoHTTP(req)
 	if err != nil {
@@ -690,6 +792,49 @@ func (r *ProtocolLXD) CreateInstanceFromBackup(args InstanceBackupArgs) (Operati
 	return &op, nil
 }
 
+// CheckInstanceBackupFile uploads a backup file without restoring it.
+// The server verifies the backup and returns information about its contents.
+func (r *ProtocolLXD) CheckInstanceBackupFile(args InstanceBackupArgs) (*api.BackupInfo, error) {
+	err := r.CheckExtension("backup_info")
+	if err != nil {
+		return nil, err
+	}
+
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := r.instanceBackupRequest(path, args)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("X-LXD-dry-run", "true")
+
+	// Send the request
+	resp, err := r.DoHTTP(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	// Handle errors
+	response, _, err := lxdParseResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	info := api.BackupInfo{}
+	err = response.MetadataAsStruct(&info)
+	if err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
+
 // CreateInstance requests that LXD creates a new instance.
 func (r *ProtocolLXD) CreateInstance(instance api.InstancesPost) (Operation, error) {
 	path, _, err := r.instanceTypeToPath(instance.Type)
@@ -706,6 +851,13 @@ func (r *ProtocolLXD) CreateInstance(instance api.InstancesPost) (Operation, err
 		}
 	}
 
+	if instance.Source.Type == api.SourceTypeBackup {
+		err := r.CheckExtension("backup_targets")
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Send the request
 	op, _, err := r.queryOperation(http.MethodPost, path, instance, "", true)
 	if err != nil {
@@ -2459,6 +2611,95 @@ func (r *ProtocolLXD) DeleteInstanceLogfile(name string, filename string) error
 	return nil
 }
 
+// GetInstanceConsoleRecordings returns a list of console session recordings for the instance.
+func (r *ProtocolLXD) GetInstanceConsoleRecordings(name string) ([]string, error) {
+	err := r.CheckExtension("console_sessions")
+	if err != nil {
+		return nil, err
+	}
+
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch the raw URL values.
+	urls := []string{}
+	baseURL := path + "/" + url.PathEscape(name) + "/logs/console-recordings"
+	_, err = r.queryStruct(http.MethodGet, baseURL, nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse it.
+	return urlsToResourceNames(baseURL, urls...)
+}
+
+// GetInstanceConsoleRecording returns the content of the requested console session recording.
+//
+// Note that it's the caller's responsibility to close the returned ReadCloser.
+func (r *ProtocolLXD) GetInstanceConsoleRecording(name string, filename string) (io.ReadCloser, error) {
+	err := r.CheckExtension("console_sessions")
+	if err != nil {
+		return nil, err
+	}
+
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prepare the HTTP request
+	url := r.httpBaseURL.String() + "/1.0" + path + "/" + url.PathEscape(name) + "/logs/console-recordings/" + url.PathEscape(filename)
+
+	url, err = r.setQueryAttributes(url)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the request
+	resp, err := r.DoHTTP(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the return value for a cleaner error
+	if resp.StatusCode != http.StatusOK {
+		_, _, err := lxdParseResponse(resp)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resp.Body, nil
+}
+
+// DeleteInstanceConsoleRecording deletes the requested console session recording.
+func (r *ProtocolLXD) DeleteInstanceConsoleRecording(name string, filename string) error {
+	err := r.CheckExtension("console_sessions")
+	if err != nil {
+		return err
+	}
+
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return err
+	}
+
+	// Send the request
+	_, _, err = r.query(http.MethodDelete, path+"/"+url.PathEscape(name)+"/logs/console-recordings/"+url.PathEscape(filename), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // getInstanceExecOutputLogFile returns the content of the requested exec logfile.
 //
 // Note that it's the caller's responsibility to close the returned ReadCloser.
@@ -2709,6 +2950,20 @@ func (r *ProtocolLXD) ConsoleInstance(instanceName string, console api.InstanceC
 		}
 	}
 
+	if console.Type == "vnc" {
+		err = r.CheckExtension("console_vnc_type")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if console.Observer {
+		err = r.CheckExtension("console_sessions")
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Send the request
 	useEventListener := r.CheckExtension("operation_wait") != nil
 	op, _, err := r.queryOperation(http.MethodPost, path+"/"+url.PathEscape(instanceName)+"/console", console, "", useEventListener)
@@ -2812,6 +3067,13 @@ func (r *ProtocolLXD) ConsoleInstanceDynamic(instanceName string, console api.In
 		}
 	}
 
+	if console.Type == "vnc" {
+		err = r.CheckExtension("console_vnc_type")
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	// Send the request.
 	op, _, err := r.queryOperation(http.MethodPost, path+"/"+url.PathEscape(instanceName)+"/console", console, "", true)
 	if err != nil {
@@ -3023,6 +3285,27 @@ func (r *ProtocolLXD) GetInstanceBackup(instanceName string, name string) (*api.
 	return &backup, etag, nil
 }
 
+// GetInstanceBackupInfo verifies the backup file of a backup and returns information about its contents.
+func (r *ProtocolLXD) GetInstanceBackupInfo(instanceName string, name string) (*api.BackupInfo, error) {
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.CheckExtension("backup_info")
+	if err != nil {
+		return nil, err
+	}
+
+	info := api.BackupInfo{}
+	_, err = r.queryStruct(http.MethodGet, path+"/"+url.PathEscape(instanceName)+"/backups/"+url.PathEscape(name)+"/info", nil, "", &info)
+	if err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
+
 // CreateInstanceBackup requests that LXD creates a new backup for the instance.
 func (r *ProtocolLXD) CreateInstanceBackup(instanceName string, backup api.InstanceBackupsPost) (Operation, error) {
 	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
@@ -3035,6 +3318,27 @@ func (r *ProtocolLXD) CreateInstanceBackup(instanceName string, backup api.Insta
 		return nil, err
 	}
 
+	if backup.Target != "" {
+		err = r.CheckExtension("backup_targets")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if backup.Parent != "" {
+		err = r.CheckExtension("backup_incremental")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(backup.EncryptionRecipients) > 0 || backup.EncryptionPassphrase != "" {
+		err = r.CheckExtension("backup_encryption")
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Send the request
 	op, _, err := r.queryOperation(http.MethodPost, path+"/"+url.PathEscape(instanceName)+"/backups", backup, "", true)
 	if err != nil {
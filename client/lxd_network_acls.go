@@ -118,6 +118,63 @@ func (r *ProtocolLXD) GetNetworkACLLogfile(name string) (io.ReadCloser, error) {
 	return resp.Body, err
 }
 
+// GetNetworkACLLogStream returns a reader following the entries logged by the ACL rules.
+// The entries are JSON encoded api.NetworkACLLogEntry objects, one per line.
+//
+// Note that it's the caller's responsibility to close the returned ReadCloser.
+func (r *ProtocolLXD) GetNetworkACLLogStream(name string) (io.ReadCloser, error) {
+	err := r.CheckExtension("network_acl_log_follow")
+	if err != nil {
+		return nil, err
+	}
+
+	// Prepare the HTTP request
+	url := r.httpBaseURL.String() + "/1.0/network-acls/" + url.PathEscape(name) + "/log?follow=true"
+	url, err = r.setQueryAttributes(url)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the request
+	resp, err := r.DoHTTP(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the return value for a cleaner error
+	if resp.StatusCode != http.StatusOK {
+		_, _, err := lxdParseResponse(resp)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resp.Body, err
+}
+
+// GetNetworkACLState returns the counters of the rules of the network ACL.
+func (r *ProtocolLXD) GetNetworkACLState(name string) (*api.NetworkACLState, error) {
+	err := r.CheckExtension("network_acl_state")
+	if err != nil {
+		return nil, err
+	}
+
+	state := api.NetworkACLState{}
+
+	// Fetch the raw value.
+	_, err = r.queryStruct(http.MethodGet, "/network-acls/"+url.PathEscape(name)+"/state", nil, "", &state)
+	if err != nil {
+		return nil, err
+	}
+
+	return &state, nil
+}
+
 // CreateNetworkACL defines a new network ACL using the provided struct.
 func (r *ProtocolLXD) CreateNetworkACL(acl api.NetworkACLsPost) error {
 	err := r.CheckExtension("network_acl")
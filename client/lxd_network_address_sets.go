@@ -0,0 +1,127 @@
+package lxd
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// GetNetworkAddressSetNames returns a list of network address set names.
+func (r *ProtocolLXD) GetNetworkAddressSetNames() ([]string, error) {
+	err := r.CheckExtension("network_address_sets")
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch the raw URL values.
+	urls := []string{}
+	baseURL := "/network-address-sets"
+	_, err = r.queryStruct(http.MethodGet, baseURL, nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse it.
+	return urlsToResourceNames(baseURL, urls...)
+}
+
+// GetNetworkAddressSets returns a list of network address set structs.
+func (r *ProtocolLXD) GetNetworkAddressSets() ([]api.NetworkAddressSet, error) {
+	err := r.CheckExtension("network_address_sets")
+	if err != nil {
+		return nil, err
+	}
+
+	sets := []api.NetworkAddressSet{}
+
+	// Fetch the raw value.
+	_, err = r.queryStruct(http.MethodGet, "/network-address-sets?recursion=1", nil, "", &sets)
+	if err != nil {
+		return nil, err
+	}
+
+	return sets, nil
+}
+
+// GetNetworkAddressSet returns a network address set entry for the provided name.
+func (r *ProtocolLXD) GetNetworkAddressSet(name string) (*api.NetworkAddressSet, string, error) {
+	err := r.CheckExtension("network_address_sets")
+	if err != nil {
+		return nil, "", err
+	}
+
+	set := api.NetworkAddressSet{}
+
+	// Fetch the raw value.
+	etag, err := r.queryStruct(http.MethodGet, "/network-address-sets/"+url.PathEscape(name), nil, "", &set)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &set, etag, nil
+}
+
+// CreateNetworkAddressSet defines a new network address set using the provided struct.
+func (r *ProtocolLXD) CreateNetworkAddressSet(set api.NetworkAddressSetsPost) error {
+	err := r.CheckExtension("network_address_sets")
+	if err != nil {
+		return err
+	}
+
+	// Send the request.
+	_, _, err = r.query(http.MethodPost, "/network-address-sets", set, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateNetworkAddressSet updates the network address set to match the provided struct.
+func (r *ProtocolLXD) UpdateNetworkAddressSet(name string, set api.NetworkAddressSetPut, ETag string) error {
+	err := r.CheckExtension("network_address_sets")
+	if err != nil {
+		return err
+	}
+
+	// Send the request.
+	_, _, err = r.query(http.MethodPut, "/network-address-sets/"+url.PathEscape(name), set, ETag)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RenameNetworkAddressSet renames an existing network address set entry.
+func (r *ProtocolLXD) RenameNetworkAddressSet(name string, set api.NetworkAddressSetPost) error {
+	err := r.CheckExtension("network_address_sets")
+	if err != nil {
+		return err
+	}
+
+	// Send the request.
+	_, _, err = r.query(http.MethodPost, "/network-address-sets/"+url.PathEscape(name), set, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteNetworkAddressSet deletes an existing network address set.
+func (r *ProtocolLXD) DeleteNetworkAddressSet(name string) error {
+	err := r.CheckExtension("network_address_sets")
+	if err != nil {
+		return err
+	}
+
+	// Send the request.
+	_, _, err = r.query(http.MethodDelete, "/network-address-sets/"+url.PathEscape(name), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -65,6 +65,25 @@ func (r *ProtocolLXD) GetNetworkLoadBalancer(networkName string, listenAddress s
 	return &loadBalancer, etag, nil
 }
 
+// GetNetworkLoadBalancerState returns the state of a network load balancer, including the health of its backends.
+func (r *ProtocolLXD) GetNetworkLoadBalancerState(networkName string, listenAddress string) (*api.NetworkLoadBalancerState, error) {
+	err := r.CheckExtension("network_load_balancer_health_check")
+	if err != nil {
+		return nil, err
+	}
+
+	loadBalancerState := api.NetworkLoadBalancerState{}
+
+	// Fetch the raw value.
+	u := api.NewURL().Path("networks", networkName, "load-balancers", listenAddress, "state")
+	_, err = r.queryStruct(http.MethodGet, u.String(), nil, "", &loadBalancerState)
+	if err != nil {
+		return nil, err
+	}
+
+	return &loadBalancerState, nil
+}
+
 // CreateNetworkLoadBalancer defines a new network load balancer using the provided struct.
 func (r *ProtocolLXD) CreateNetworkLoadBalancer(networkName string, loadBalancer api.NetworkLoadBalancersPost) error {
 	err := r.CheckExtension("network_load_balancer")
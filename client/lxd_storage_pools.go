@@ -137,3 +137,39 @@ func (r *ProtocolLXD) GetStoragePoolResources(name string) (*api.ResourcesStorag
 
 	return &res, nil
 }
+
+// GetStoragePoolUsage gets the disk usage of the volumes of the project on the storage pool.
+func (r *ProtocolLXD) GetStoragePoolUsage(name string) (*api.StoragePoolUsage, error) {
+	err := r.CheckExtension("storage_pool_usage")
+	if err != nil {
+		return nil, err
+	}
+
+	usage := api.StoragePoolUsage{}
+
+	// Fetch the raw value
+	_, err = r.queryStruct(http.MethodGet, "/storage-pools/"+url.PathEscape(name)+"/usage", nil, "", &usage)
+	if err != nil {
+		return nil, err
+	}
+
+	return &usage, nil
+}
+
+// GetStoragePoolUsageAllProjects gets the disk usage of the volumes of all projects on the storage pool.
+func (r *ProtocolLXD) GetStoragePoolUsageAllProjects(name string) (*api.StoragePoolUsage, error) {
+	err := r.CheckExtension("storage_pool_usage")
+	if err != nil {
+		return nil, err
+	}
+
+	usage := api.StoragePoolUsage{}
+
+	// Fetch the raw value
+	_, err = r.queryStruct(http.MethodGet, "/storage-pools/"+url.PathEscape(name)+"/usage?all-projects=true", nil, "", &usage)
+	if err != nil {
+		return nil, err
+	}
+
+	return &usage, nil
+}
@@ -1,6 +1,7 @@
 package lxd
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
@@ -818,6 +819,15 @@ func (r *ProtocolLXD) MoveStoragePoolVolume(pool string, source InstanceServer,
 		req.Project = args.Project
 	}
 
+	if args.Live {
+		err := r.CheckExtension("storage_volume_live_move")
+		if err != nil {
+			return nil, err
+		}
+
+		req.Live = true
+	}
+
 	// Send the request
 	op, _, err := r.queryOperation(http.MethodPost, "/storage-pools/"+url.PathEscape(sourcePool)+"/volumes/"+url.PathEscape(volume.Type)+"/"+url.PathEscape(volume.Name), req, "", true)
 	if err != nil {
@@ -927,6 +937,24 @@ func (r *ProtocolLXD) RenameStoragePoolVolume(pool string, volType string, name
 	return op, nil
 }
 
+// PromoteStoragePoolVolume turns a custom volume replica created by storage pool replication into a regular custom volume.
+func (r *ProtocolLXD) PromoteStoragePoolVolume(pool string, volType string, name string) (Operation, error) {
+	err := r.CheckExtension("storage_replication")
+	if err != nil {
+		return nil, err
+	}
+
+	path := api.NewURL().Path("storage-pools", pool, "volumes", volType, name, "promote")
+
+	// Send the request
+	op, _, err := r.queryOperation(http.MethodPost, path.String(), nil, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
 // GetStoragePoolVolumeBackupNames returns a list of volume backup names.
 func (r *ProtocolLXD) GetStoragePoolVolumeBackupNames(pool string, volName string) ([]string, error) {
 	err := r.CheckExtension("custom_volume_backup")
@@ -988,6 +1016,20 @@ func (r *ProtocolLXD) CreateStoragePoolVolumeBackup(pool string, volName string,
 		return nil, err
 	}
 
+	if backup.Target != "" {
+		err = r.CheckExtension("backup_targets")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(backup.EncryptionRecipients) > 0 || backup.EncryptionPassphrase != "" {
+		err = r.CheckExtension("backup_encryption")
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Send the request
 	op, _, err := r.queryOperation(http.MethodPost, "/storage-pools/"+url.PathEscape(pool)+"/volumes/custom/"+url.PathEscape(volName)+"/backups", backup, "", true)
 	if err != nil {
@@ -1118,6 +1160,14 @@ func (r *ProtocolLXD) createStoragePoolVolumeFromFile(pool string, args StorageP
 		req.Header.Set("X-LXD-type", fileType)
 	}
 
+	if len(args.Identity) > 0 {
+		req.Header.Set("X-LXD-backup-identity", base64.StdEncoding.EncodeToString(args.Identity))
+	}
+
+	if args.Passphrase != "" {
+		req.Header.Set("X-LXD-backup-passphrase", args.Passphrase)
+	}
+
 	// Send the request.
 	resp, err := r.DoHTTP(req)
 	if err != nil {
@@ -1190,5 +1240,12 @@ func (r *ProtocolLXD) CreateStoragePoolVolumeFromBackup(pool string, args Storag
 		}
 	}
 
+	if len(args.Identity) > 0 || args.Passphrase != "" {
+		err = r.CheckExtension("backup_encryption")
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return r.createStoragePoolVolumeFromFile(pool, args, "")
 }
@@ -76,6 +76,9 @@ var topLevelInstanceServerResourceNameFuncs = map[string]func(server lxd.Instanc
 	"network_acl": func(server lxd.InstanceServer) ([]string, error) {
 		return server.GetNetworkACLNames()
 	},
+	"network_address_set": func(server lxd.InstanceServer) ([]string, error) {
+		return server.GetNetworkAddressSetNames()
+	},
 	"network_zone": func(server lxd.InstanceServer) ([]string, error) {
 		return server.GetNetworkZoneNames()
 	},
@@ -865,6 +868,50 @@ func (g *cmdGlobal) cmpNetworkACLConfigs(aclName string) ([]string, cobra.ShellC
 	return results, cobra.ShellCompDirectiveNoFileComp
 }
 
+// cmpNetworkAddressSetConfigs provides shell completion for network address set configs.
+// It takes an address set name and returns a list of address set configs along with a shell completion directive.
+func (g *cmdGlobal) cmpNetworkAddressSetConfigs(addressSetName string) ([]string, cobra.ShellCompDirective) {
+	// Parse remote
+	resources, err := g.ParseServers(addressSetName)
+	if err != nil || len(resources) == 0 {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	resource := resources[0]
+	client := resource.server
+
+	addressSet, _, err := client.GetNetworkAddressSet(resource.name)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	results := make([]string, 0, len(addressSet.Config))
+	for k := range addressSet.Config {
+		results = append(results, k)
+	}
+
+	return results, cobra.ShellCompDirectiveNoFileComp
+}
+
+// cmpNetworkAddressSetAddresses provides shell completion for the addresses of a network address set.
+func (g *cmdGlobal) cmpNetworkAddressSetAddresses(addressSetName string) ([]string, cobra.ShellCompDirective) {
+	// Parse remote
+	resources, err := g.ParseServers(addressSetName)
+	if err != nil || len(resources) == 0 {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	resource := resources[0]
+	client := resource.server
+
+	addressSet, _, err := client.GetNetworkAddressSet(resource.name)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	return addressSet.Addresses, cobra.ShellCompDirectiveNoFileComp
+}
+
 // cmpNetworkACLRuleProperties provides shell completion for network ACL rule properties.
 // It returns a list of network ACL rules provided by `networkACLRuleJSONStructFieldMap()“ along with a shell completion directive.
 func (g *cmdGlobal) cmpNetworkACLRuleProperties() ([]string, cobra.ShellCompDirective) {
@@ -32,6 +32,7 @@ type cmdConsole struct {
 
 	flagShowLog bool
 	flagType    string
+	flagObserve bool
 }
 
 func (c *cmdConsole) command() *cobra.Command {
@@ -46,7 +47,8 @@ as well as retrieve past log entries from it.`))
 
 	cmd.RunE = c.run
 	cmd.Flags().BoolVar(&c.flagShowLog, "show-log", false, i18n.G("Retrieve the container's console log"))
-	cmd.Flags().StringVarP(&c.flagType, "type", "t", "console", i18n.G("Type of connection to establish: 'console' for serial console, 'vga' for SPICE graphical output")+"``")
+	cmd.Flags().BoolVar(&c.flagObserve, "observe", false, i18n.G("Watch the active console session without sending input"))
+	cmd.Flags().StringVarP(&c.flagType, "type", "t", "console", i18n.G("Type of connection to establish: 'console' for serial console, 'vga' for SPICE graphical output, 'vnc' for VNC graphical output")+"``")
 
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return c.global.cmpTopLevelResource("instance", toComplete)
@@ -113,7 +115,7 @@ func (c *cmdConsole) run(cmd *cobra.Command, args []string) error {
 	}
 
 	// Validate flags.
-	if !slices.Contains([]string{"console", "vga"}, c.flagType) {
+	if !slices.Contains([]string{"console", "vga", "vnc"}, c.flagType) {
 		return fmt.Errorf(i18n.G("Unknown output type %q"), c.flagType)
 	}
 
@@ -128,6 +130,10 @@ func (c *cmdConsole) run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if c.flagObserve && c.flagType != "console" {
+		return errors.New(i18n.G("The --observe flag is only supported by the 'console' output type"))
+	}
+
 	// Show the current log if requested
 	if c.flagShowLog {
 		if c.flagType != "console" {
@@ -165,7 +171,7 @@ func (c *cmdConsole) runConsole(d lxd.InstanceServer, name string) error {
 	switch c.flagType {
 	case "console":
 		return c.console(d, name)
-	case "vga":
+	case "vga", "vnc":
 		return c.vga(d, name)
 	}
 
@@ -193,9 +199,10 @@ func (c *cmdConsole) console(d lxd.InstanceServer, name string) error {
 
 	// Prepare the remote console
 	req := api.InstanceConsolePost{
-		Width:  width,
-		Height: height,
-		Type:   "console",
+		Width:    width,
+		Height:   height,
+		Type:     "console",
+		Observer: c.flagObserve,
 	}
 
 	consoleDisconnect := make(chan bool)
@@ -257,7 +264,13 @@ func (c *cmdConsole) vga(d lxd.InstanceServer, name string) error {
 
 	// Prepare the remote console.
 	req := api.InstanceConsolePost{
-		Type: "vga",
+		Type: c.flagType,
+	}
+
+	// The graphical output is either provided over SPICE or VNC.
+	scheme := "spice"
+	if c.flagType == "vnc" {
+		scheme = "vnc"
 	}
 
 	chDisconnect := make(chan bool)
@@ -272,7 +285,7 @@ func (c *cmdConsole) vga(d lxd.InstanceServer, name string) error {
 	var socket string
 	var listener net.Listener
 	if runtime.GOOS != "windows" {
-		// Create a temporary unix socket mirroring the instance's spice socket.
+		// Create a temporary unix socket mirroring the instance's spice or vnc socket.
 		if !shared.PathExists(conf.ConfigPath("sockets")) {
 			err := os.MkdirAll(conf.ConfigPath("sockets"), 0700)
 			if err != nil {
@@ -281,7 +294,7 @@ func (c *cmdConsole) vga(d lxd.InstanceServer, name string) error {
 		}
 
 		// Generate a random file name.
-		path, err := os.CreateTemp(conf.ConfigPath("sockets"), "*.spice")
+		path, err := os.CreateTemp(conf.ConfigPath("sockets"), "*."+scheme)
 		if err != nil {
 			return err
 		}
@@ -302,7 +315,7 @@ func (c *cmdConsole) vga(d lxd.InstanceServer, name string) error {
 
 		defer func() { _ = os.Remove(path.Name()) }()
 
-		socket = "spice+unix://" + path.Name()
+		socket = scheme + "+unix://" + path.Name()
 	} else {
 		listener, err = net.Listen("tcp", "127.0.0.1:0")
 		if err != nil {
@@ -314,7 +327,7 @@ func (c *cmdConsole) vga(d lxd.InstanceServer, name string) error {
 			return errors.New("Failed to get TCP listen address")
 		}
 
-		socket = "spice://127.0.0.1:" + strconv.Itoa(addr.Port)
+		socket = scheme + "://127.0.0.1:" + strconv.Itoa(addr.Port)
 	}
 
 	// Clean everything up when the viewer is done.
@@ -361,9 +374,12 @@ func (c *cmdConsole) vga(d lxd.InstanceServer, name string) error {
 		}
 	}()
 
-	// Use either spicy or remote-viewer if available.
+	// Use either spicy or remote-viewer if available, spicy only supports SPICE.
 	remoteViewer := c.findCommand("remote-viewer")
-	spicy := c.findCommand("spicy")
+	spicy := ""
+	if c.flagType == "vga" {
+		spicy = c.findCommand("spicy")
+	}
 
 	if remoteViewer != "" || spicy != "" {
 		var cmd *exec.Cmd
@@ -399,8 +415,14 @@ func (c *cmdConsole) vga(d lxd.InstanceServer, name string) error {
 			_ = cmd.Process.Kill()
 		}()
 	} else {
-		fmt.Println(i18n.G("LXD automatically uses either spicy or remote-viewer when present."))
-		fmt.Println(i18n.G("As neither could be found, the raw SPICE socket can be found at:"))
+		if c.flagType == "vnc" {
+			fmt.Println(i18n.G("LXD automatically uses remote-viewer when present."))
+			fmt.Println(i18n.G("As it couldn't be found, the raw VNC socket can be found at:"))
+		} else {
+			fmt.Println(i18n.G("LXD automatically uses either spicy or remote-viewer when present."))
+			fmt.Println(i18n.G("As neither could be found, the raw SPICE socket can be found at:"))
+		}
+
 		fmt.Printf("  %s\n", socket)
 
 		// Wait for all connections to complete.
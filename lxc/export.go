@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net/url"
@@ -24,6 +25,10 @@ type cmdExport struct {
 	flagOptimizedStorage     bool
 	flagCompressionAlgorithm string
 	flagExportVersion        string
+	flagParent               string
+	flagVerify               bool
+	flagRecipient            []string
+	flagPassphrase           bool
 }
 
 func (c *cmdExport) command() *cobra.Command {
@@ -34,7 +39,13 @@ func (c *cmdExport) command() *cobra.Command {
 		`Export instances as backup tarballs.`))
 	cmd.Example = cli.FormatSection("", i18n.G(
 		`lxc export u1 backup0.tar.gz
-    Download a backup tarball of the u1 instance.`))
+    Download a backup tarball of the u1 instance.
+
+lxc export u1 incremental.tar.gz --optimized-storage --parent auto0
+    Download an incremental backup of the u1 instance containing only the changes since its auto0 backup.
+
+lxc export u1 backup0.tar.gz.enc --recipient backup.pub
+    Download a backup of the u1 instance encrypted for the age recipient in backup.pub.`))
 
 	cmd.RunE = c.run
 	cmd.Flags().BoolVar(&c.flagInstanceOnly, "instance-only", false,
@@ -44,6 +55,10 @@ func (c *cmdExport) command() *cobra.Command {
 	cmd.Flags().StringVar(&c.flagCompressionAlgorithm, "compression", "", i18n.G("Compression algorithm to use (none for uncompressed)")+"``")
 	cmd.Flags().StringVar(&c.flagExportVersion, "export-version", "",
 		i18n.G("Use a different metadata format version than the latest one supported by the server (to support imports on older LXD versions)")+"``")
+	cmd.Flags().BoolVar(&c.flagVerify, "verify", false, i18n.G("Verify the integrity of the backup before downloading it"))
+	cmd.Flags().StringVar(&c.flagParent, "parent", "", i18n.G("Create an incremental backup based on the given stored backup of the instance")+"``")
+	cmd.Flags().StringArrayVar(&c.flagRecipient, "recipient", nil, i18n.G("Encrypt the backup for the age recipients in the given file (can be repeated)")+"``")
+	cmd.Flags().BoolVar(&c.flagPassphrase, "passphrase", false, i18n.G("Encrypt the backup with a passphrase"))
 
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
 		if len(args) > 0 {
@@ -78,6 +93,25 @@ func (c *cmdExport) run(cmd *cobra.Command, args []string) error {
 
 	instanceOnly := c.flagInstanceOnly
 
+	encrypted := len(c.flagRecipient) > 0 || c.flagPassphrase
+	if encrypted && c.flagVerify {
+		return errors.New(i18n.G("Encrypted backups can't be verified on the server"))
+	}
+
+	if len(c.flagRecipient) > 0 && c.flagPassphrase {
+		return errors.New(i18n.G("--recipient and --passphrase can't be combined"))
+	}
+
+	recipients, err := readBackupRecipients(c.flagRecipient)
+	if err != nil {
+		return err
+	}
+
+	passphrase := ""
+	if c.flagPassphrase {
+		passphrase = c.global.asker.AskPassword(i18n.G("Backup passphrase: "))
+	}
+
 	req := api.InstanceBackupsPost{
 		Name:                 "",
 		ExpiresAt:            time.Now().Add(24 * time.Hour),
@@ -85,6 +119,9 @@ func (c *cmdExport) run(cmd *cobra.Command, args []string) error {
 		InstanceOnly:         instanceOnly,
 		OptimizedStorage:     c.flagOptimizedStorage,
 		CompressionAlgorithm: c.flagCompressionAlgorithm,
+		Parent:               c.flagParent,
+		EncryptionRecipients: recipients,
+		EncryptionPassphrase: passphrase,
 	}
 
 	req.Version, err = getExportVersion(d, c.flagExportVersion)
@@ -163,6 +200,14 @@ func (c *cmdExport) run(cmd *cobra.Command, args []string) error {
 		}
 	}()
 
+	if c.flagVerify {
+		_, err = d.GetInstanceBackupInfo(name, backupName)
+		if err != nil {
+			_ = os.Remove(targetName)
+			return fmt.Errorf("Verify instance backup: %w", err)
+		}
+	}
+
 	// Prepare the download request.
 	// Assign the renderer to a new variable to not interfer with the old one.
 	exportProgress := cli.ProgressRenderer{
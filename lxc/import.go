@@ -1,6 +1,9 @@
 package main
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
@@ -9,6 +12,7 @@ import (
 
 	"github.com/canonical/lxd/client"
 	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
 	cli "github.com/canonical/lxd/shared/cmd"
 	"github.com/canonical/lxd/shared/i18n"
 	"github.com/canonical/lxd/shared/ioprogress"
@@ -18,8 +22,12 @@ import (
 type cmdImport struct {
 	global *cmdGlobal
 
-	flagStorage string
-	flagDevice  []string
+	flagStorage    string
+	flagDevice     []string
+	flagParent     []string
+	flagDryRun     bool
+	flagIdentity   string
+	flagPassphrase bool
 }
 
 func (c *cmdImport) command() *cobra.Command {
@@ -30,11 +38,24 @@ func (c *cmdImport) command() *cobra.Command {
 		`Import backups of instances including their snapshots.`))
 	cmd.Example = cli.FormatSection("", i18n.G(
 		`lxc import backup0.tar.gz
-    Create a new instance using backup0.tar.gz as the source.`))
+    Create a new instance using backup0.tar.gz as the source.
+
+lxc import backup2.tar.gz --parent backup0.tar.gz --parent backup1.tar.gz
+    Create a new instance from the incremental backup backup2.tar.gz and its parent backups.
+
+lxc import backup0.tar.gz --dry-run
+    Verify backup0.tar.gz and show its contents without creating an instance.
+
+lxc import backup0.tar.gz.enc --identity backup.key
+    Create a new instance from the encrypted backup0.tar.gz.enc using the age identity in backup.key.`))
 
 	cmd.RunE = c.run
 	cmd.Flags().StringVarP(&c.flagStorage, "storage", "s", "", i18n.G("Storage pool name")+"``")
 	cmd.Flags().StringArrayVarP(&c.flagDevice, "device", "d", nil, i18n.G("New key/value to apply to a specific device")+"``")
+	cmd.Flags().BoolVar(&c.flagDryRun, "dry-run", false, i18n.G("Verify the backup and show its contents without creating an instance"))
+	cmd.Flags().StringArrayVar(&c.flagParent, "parent", nil, i18n.G("Parent backup file of an incremental backup (can be repeated, oldest first)")+"``")
+	cmd.Flags().StringVar(&c.flagIdentity, "identity", "", i18n.G("File with the age identity to decrypt an encrypted backup with")+"``")
+	cmd.Flags().BoolVar(&c.flagPassphrase, "passphrase", false, i18n.G("Decrypt an encrypted backup with a passphrase"))
 
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
 		if len(args) > 1 {
@@ -88,6 +109,23 @@ func (c *cmdImport) run(cmd *cobra.Command, args []string) error {
 
 	resource := resources[0]
 
+	var identity []byte
+	if c.flagIdentity != "" {
+		identity, err = os.ReadFile(shared.HostPathFollow(c.flagIdentity))
+		if err != nil {
+			return err
+		}
+	}
+
+	passphrase := ""
+	if c.flagPassphrase {
+		if srcFile == "-" {
+			return errors.New(i18n.G("Can't ask for a passphrase when reading the backup from standard input"))
+		}
+
+		passphrase = c.global.asker.AskPasswordOnce(i18n.G("Backup passphrase: "))
+	}
+
 	var file *os.File
 	if srcFile == "-" {
 		file = os.Stdin
@@ -106,6 +144,18 @@ func (c *cmdImport) run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	parentFiles := make([]io.Reader, 0, len(c.flagParent))
+	for _, parentPath := range c.flagParent {
+		parentFile, err := os.Open(shared.HostPathFollow(parentPath))
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = parentFile.Close() }()
+
+		parentFiles = append(parentFiles, parentFile)
+	}
+
 	progress := cli.ProgressRenderer{
 		Format: i18n.G("Importing instance: %s"),
 		Quiet:  c.global.flagQuiet,
@@ -126,9 +176,23 @@ func (c *cmdImport) run(cmd *cobra.Command, args []string) error {
 				},
 			},
 		},
-		PoolName: c.flagStorage,
-		Name:     instanceName,
-		Devices:  deviceMap,
+		PoolName:    c.flagStorage,
+		Name:        instanceName,
+		Devices:     deviceMap,
+		ParentFiles: parentFiles,
+		Identity:    identity,
+		Passphrase:  passphrase,
+	}
+
+	if c.flagDryRun {
+		info, err := resource.server.CheckInstanceBackupFile(createArgs)
+		progress.Done("")
+		if err != nil {
+			return err
+		}
+
+		c.printInfo(info)
+		return nil
 	}
 
 	op, err := resource.server.CreateInstanceFromBackup(createArgs)
@@ -147,3 +211,35 @@ func (c *cmdImport) run(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// printInfo prints the contents of a verified backup.
+func (c *cmdImport) printInfo(info *api.BackupInfo) {
+	fmt.Printf(i18n.G("Name: %s")+"\n", info.Name)
+	if info.Backup != "" {
+		fmt.Printf(i18n.G("Backup: %s")+"\n", info.Backup)
+	}
+
+	fmt.Printf(i18n.G("Type: %s")+"\n", info.Type)
+	fmt.Printf(i18n.G("Storage driver: %s")+"\n", info.Backend)
+	fmt.Printf(i18n.G("Storage pool: %s")+"\n", info.Pool)
+	fmt.Printf(i18n.G("Optimized: %v")+"\n", info.OptimizedStorage)
+	if info.Parent != "" {
+		fmt.Printf(i18n.G("Parent: %s")+"\n", info.Parent)
+	}
+
+	if len(info.Snapshots) > 0 {
+		fmt.Println(i18n.G("Snapshots:"))
+		for _, snapName := range info.Snapshots {
+			fmt.Printf("  - %s\n", snapName)
+		}
+	}
+
+	if len(info.Volumes) > 0 {
+		fmt.Println(i18n.G("Volumes:"))
+		for _, vol := range info.Volumes {
+			fmt.Printf("  - %s (%s)\n", vol.Name, vol.Type)
+		}
+	}
+
+	fmt.Printf(i18n.G("Checksums verified: %v")+"\n", info.Verified)
+}
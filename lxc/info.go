@@ -554,6 +554,10 @@ func (c *cmdInfo) instanceInfo(d lxd.InstanceServer, name string, showLog bool)
 			memoryInfo.WriteString(fmt.Sprintf("    %s: %s\n", i18n.G("Memory (peak)"), units.GetByteSizeStringIEC(inst.State.Memory.UsagePeak, 2)))
 		}
 
+		if inst.State.Memory.Hotplugged != 0 {
+			memoryInfo.WriteString(fmt.Sprintf("    %s: %s\n", i18n.G("Memory (hotplugged)"), units.GetByteSizeStringIEC(inst.State.Memory.Hotplugged, 2)))
+		}
+
 		if inst.State.Memory.SwapUsage != 0 {
 			memoryInfo.WriteString(fmt.Sprintf("    %s: %s\n", i18n.G("Swap (current)"), units.GetByteSizeStringIEC(inst.State.Memory.SwapUsage, 2)))
 		}
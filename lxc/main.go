@@ -230,6 +230,10 @@ For help with any of those, simply call them with --help.`))
 	pauseCmd := cmdPause{global: &globalCmd}
 	app.AddCommand(pauseCmd.command())
 
+	// promote sub-command
+	promoteCmd := cmdPromote{global: &globalCmd}
+	app.AddCommand(promoteCmd.command())
+
 	// publish sub-command
 	publishCmd := cmdPublish{global: &globalCmd}
 	app.AddCommand(publishCmd.command())
@@ -104,6 +104,10 @@ func (c *cmdNetwork) command() *cobra.Command {
 	networkACLCmd := cmdNetworkACL{global: c.global}
 	cmd.AddCommand(networkACLCmd.command())
 
+	// Address set
+	networkAddressSetCmd := cmdNetworkAddressSet{global: c.global}
+	cmd.AddCommand(networkAddressSetCmd.command())
+
 	// Forward
 	networkForwardCmd := cmdNetworkForward{global: c.global}
 	cmd.AddCommand(networkForwardCmd.command())
@@ -999,6 +1003,42 @@ func (c *cmdNetworkInfo) run(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  %s: %s\n", i18n.G("Chassis"), state.OVN.Chassis)
 	}
 
+	// WireGuard information.
+	if state.WireGuard != nil {
+		fmt.Println("")
+		fmt.Println(i18n.G("WireGuard:"))
+		fmt.Printf("  %s: %s\n", i18n.G("Public key"), state.WireGuard.PublicKey)
+		fmt.Printf("  %s: %d\n", i18n.G("Listen port"), state.WireGuard.ListenPort)
+
+		peerNames := make([]string, 0, len(state.WireGuard.Peers))
+		for peerName := range state.WireGuard.Peers {
+			peerNames = append(peerNames, peerName)
+		}
+
+		sort.Strings(peerNames)
+
+		if len(peerNames) > 0 {
+			fmt.Printf("  %s:\n", i18n.G("Peers"))
+		}
+
+		for _, peerName := range peerNames {
+			peer := state.WireGuard.Peers[peerName]
+
+			fmt.Printf("    %s:\n", peerName)
+
+			if peer.Endpoint != "" {
+				fmt.Printf("      %s: %s\n", i18n.G("Endpoint"), peer.Endpoint)
+			}
+
+			if !peer.LatestHandshake.IsZero() {
+				fmt.Printf("      %s: %s\n", i18n.G("Latest handshake"), peer.LatestHandshake.Local().Format("2006/01/02 15:04 MST"))
+			}
+
+			fmt.Printf("      %s: %s\n", i18n.G("Bytes received"), units.GetByteSizeString(peer.BytesReceived, 2))
+			fmt.Printf("      %s: %s\n", i18n.G("Bytes sent"), units.GetByteSizeString(peer.BytesSent, 2))
+		}
+	}
+
 	return nil
 }
 
@@ -1185,9 +1225,16 @@ func (c *cmdNetworkListLeases) run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	const layout = "2006/01/02 15:04 MST"
+
 	data := [][]string{}
 	for _, lease := range leases {
-		entry := []string{lease.Hostname, lease.Hwaddr, lease.Address, strings.ToUpper(lease.Type)}
+		expiresAt := ""
+		if lease.ExpiresAt != nil {
+			expiresAt = lease.ExpiresAt.Local().Format(layout)
+		}
+
+		entry := []string{lease.Hostname, lease.Hwaddr, lease.Address, strings.ToUpper(lease.Type), expiresAt}
 		if resource.server.IsClustered() {
 			entry = append(entry, lease.Location)
 		}
@@ -1202,6 +1249,7 @@ func (c *cmdNetworkListLeases) run(cmd *cobra.Command, args []string) error {
 		i18n.G("MAC ADDRESS"),
 		i18n.G("IP ADDRESS"),
 		i18n.G("TYPE"),
+		i18n.G("EXPIRES AT"),
 	}
 
 	if resource.server.IsClustered() {
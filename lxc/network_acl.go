@@ -1,10 +1,12 @@
 package main
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"maps"
+	"net"
 	"os"
 	"reflect"
 	"sort"
@@ -246,15 +248,22 @@ func (c *cmdNetworkACLShow) run(cmd *cobra.Command, args []string) error {
 type cmdNetworkACLShowLog struct {
 	global     *cmdGlobal
 	networkACL *cmdNetworkACL
+
+	flagFollow bool
 }
 
 func (c *cmdNetworkACLShowLog) command() *cobra.Command {
 	cmd := &cobra.Command{}
 	cmd.Use = usage("show-log", i18n.G("[<remote>:]<ACL>"))
+	cmd.Aliases = []string{"log"}
 	cmd.Short = i18n.G("Show network ACL log")
-	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Show network ACL log"))
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(`Show network ACL log
+
+With --follow, the new entries are shown as they get logged along with the instance they relate to.`))
 	cmd.RunE = c.run
 
+	cmd.Flags().BoolVarP(&c.flagFollow, "follow", "f", false, i18n.G("Follow the new log entries"))
+
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) == 0 {
 			return c.global.cmpTopLevelResource("network_acl", toComplete)
@@ -284,6 +293,10 @@ func (c *cmdNetworkACLShowLog) run(cmd *cobra.Command, args []string) error {
 		return errors.New(i18n.G("Missing network ACL name"))
 	}
 
+	if c.flagFollow {
+		return c.follow(resource)
+	}
+
 	// Get the ACL log.
 	log, err := resource.server.GetNetworkACLLogfile(resource.name)
 	if err != nil {
@@ -296,6 +309,53 @@ func (c *cmdNetworkACLShowLog) run(cmd *cobra.Command, args []string) error {
 	return err
 }
 
+func (c *cmdNetworkACLShowLog) follow(resource remoteResource) error {
+	stream, err := resource.server.GetNetworkACLLogStream(resource.name)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = stream.Close() }()
+
+	decoder := json.NewDecoder(stream)
+	for {
+		entry := api.NetworkACLLogEntry{}
+		err := decoder.Decode(&entry)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		source := entry.Source
+		if entry.SourcePort != "" {
+			source = net.JoinHostPort(entry.Source, entry.SourcePort)
+		}
+
+		destination := entry.Destination
+		if entry.DestinationPort != "" {
+			destination = net.JoinHostPort(entry.Destination, entry.DestinationPort)
+		}
+
+		instance := "-"
+		if entry.Instance != "" {
+			instance = entry.Instance
+			if entry.Project != "" && entry.Project != api.ProjectDefaultName {
+				instance = entry.Project + "/" + instance
+			}
+		}
+
+		fields := []string{entry.Time.Local().Format("2006/01/02 15:04:05 MST")}
+		if entry.Location != "" {
+			fields = append(fields, entry.Location)
+		}
+
+		fields = append(fields, instance, fmt.Sprintf("%s/%d", entry.Direction, entry.Rule), entry.Action, entry.Protocol, source, "->", destination)
+
+		fmt.Println(strings.Join(fields, " "))
+	}
+}
+
 // Get.
 type cmdNetworkACLGet struct {
 	global     *cmdGlobal
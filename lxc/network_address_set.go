@@ -0,0 +1,894 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v2"
+
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	cli "github.com/canonical/lxd/shared/cmd"
+	"github.com/canonical/lxd/shared/i18n"
+	"github.com/canonical/lxd/shared/termios"
+)
+
+type cmdNetworkAddressSet struct {
+	global *cmdGlobal
+}
+
+func (c *cmdNetworkAddressSet) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("address-set")
+	cmd.Short = i18n.G("Manage network address sets")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(`Manage network address sets
+
+Address sets are named lists of IP addresses and subnets that can be referenced
+in the source and destination of network ACL rules using $<name>.`))
+
+	// List.
+	networkAddressSetListCmd := cmdNetworkAddressSetList{global: c.global, networkAddressSet: c}
+	cmd.AddCommand(networkAddressSetListCmd.command())
+
+	// Show.
+	networkAddressSetShowCmd := cmdNetworkAddressSetShow{global: c.global, networkAddressSet: c}
+	cmd.AddCommand(networkAddressSetShowCmd.command())
+
+	// Get.
+	networkAddressSetGetCmd := cmdNetworkAddressSetGet{global: c.global, networkAddressSet: c}
+	cmd.AddCommand(networkAddressSetGetCmd.command())
+
+	// Create.
+	networkAddressSetCreateCmd := cmdNetworkAddressSetCreate{global: c.global, networkAddressSet: c}
+	cmd.AddCommand(networkAddressSetCreateCmd.command())
+
+	// Set.
+	networkAddressSetSetCmd := cmdNetworkAddressSetSet{global: c.global, networkAddressSet: c}
+	cmd.AddCommand(networkAddressSetSetCmd.command())
+
+	// Unset.
+	networkAddressSetUnsetCmd := cmdNetworkAddressSetUnset{global: c.global, networkAddressSet: c, networkAddressSetSet: &networkAddressSetSetCmd}
+	cmd.AddCommand(networkAddressSetUnsetCmd.command())
+
+	// Edit.
+	networkAddressSetEditCmd := cmdNetworkAddressSetEdit{global: c.global, networkAddressSet: c}
+	cmd.AddCommand(networkAddressSetEditCmd.command())
+
+	// Rename.
+	networkAddressSetRenameCmd := cmdNetworkAddressSetRename{global: c.global, networkAddressSet: c}
+	cmd.AddCommand(networkAddressSetRenameCmd.command())
+
+	// Delete.
+	networkAddressSetDeleteCmd := cmdNetworkAddressSetDelete{global: c.global, networkAddressSet: c}
+	cmd.AddCommand(networkAddressSetDeleteCmd.command())
+
+	// Add/Remove addresses.
+	networkAddressSetAddressCmd := cmdNetworkAddressSetAddress{global: c.global, networkAddressSet: c}
+	cmd.AddCommand(networkAddressSetAddressCmd.commandAdd())
+	cmd.AddCommand(networkAddressSetAddressCmd.commandRemove())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// List.
+type cmdNetworkAddressSetList struct {
+	global            *cmdGlobal
+	networkAddressSet *cmdNetworkAddressSet
+
+	flagFormat string
+}
+
+func (c *cmdNetworkAddressSetList) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", i18n.G("[<remote>:]"))
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List available network address sets")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("List available network address sets"))
+
+	cmd.RunE = c.run
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G("Format (csv|json|table|yaml|compact)")+"``")
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemotes(toComplete, ":", true, instanceServerRemoteCompletionFilters(*c.global.conf)...)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdNetworkAddressSetList) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	remote := ""
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.ParseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name != "" {
+		return errors.New(i18n.G("Filtering isn't supported yet"))
+	}
+
+	addressSets, err := resource.server.GetNetworkAddressSets()
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	for _, addressSet := range addressSets {
+		details := []string{
+			addressSet.Name,
+			addressSet.Description,
+			strconv.Itoa(len(addressSet.Addresses)),
+			strconv.Itoa(len(addressSet.UsedBy)),
+		}
+
+		data = append(data, details)
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{
+		i18n.G("NAME"),
+		i18n.G("DESCRIPTION"),
+		i18n.G("ADDRESSES"),
+		i18n.G("USED BY"),
+	}
+
+	return cli.RenderTable(c.flagFormat, header, data, addressSets)
+}
+
+// Show.
+type cmdNetworkAddressSetShow struct {
+	global            *cmdGlobal
+	networkAddressSet *cmdNetworkAddressSet
+}
+
+func (c *cmdNetworkAddressSetShow) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("show", i18n.G("[<remote>:]<address-set>"))
+	cmd.Short = i18n.G("Show network address set configurations")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Show network address set configurations"))
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("network_address_set", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdNetworkAddressSetShow) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing network address set name"))
+	}
+
+	// Show the network address set config.
+	addressSet, _, err := resource.server.GetNetworkAddressSet(resource.name)
+	if err != nil {
+		return err
+	}
+
+	sort.Strings(addressSet.UsedBy)
+
+	data, err := yaml.Marshal(&addressSet)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
+
+// Get.
+type cmdNetworkAddressSetGet struct {
+	global            *cmdGlobal
+	networkAddressSet *cmdNetworkAddressSet
+
+	flagIsProperty bool
+}
+
+func (c *cmdNetworkAddressSetGet) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("get", i18n.G("[<remote>:]<address-set> <key>"))
+	cmd.Short = i18n.G("Get values for network address set configuration keys")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Get values for network address set configuration keys"))
+
+	cmd.Flags().BoolVarP(&c.flagIsProperty, "property", "p", false, i18n.G("Get the key as a network address set property"))
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("network_address_set", toComplete)
+		}
+
+		if len(args) == 1 {
+			return c.global.cmpNetworkAddressSetConfigs(args[0])
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdNetworkAddressSetGet) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing network address set name"))
+	}
+
+	resp, _, err := resource.server.GetNetworkAddressSet(resource.name)
+	if err != nil {
+		return err
+	}
+
+	if c.flagIsProperty {
+		w := resp.Writable()
+		res, err := getFieldByJSONTag(&w, args[1])
+		if err != nil {
+			return fmt.Errorf(i18n.G("The property %q does not exist on the network address set %q: %v"), args[1], resource.name, err)
+		}
+
+		fmt.Printf("%v\n", res)
+	} else {
+		for k, v := range resp.Config {
+			if k == args[1] {
+				fmt.Printf("%s\n", v)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Create.
+type cmdNetworkAddressSetCreate struct {
+	global            *cmdGlobal
+	networkAddressSet *cmdNetworkAddressSet
+
+	flagDescription string
+}
+
+func (c *cmdNetworkAddressSetCreate) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("create", i18n.G("[<remote>:]<address-set> [<address>...] [key=value...]"))
+	cmd.Short = i18n.G("Create new network address sets")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Create new network address sets"))
+	cmd.Example = cli.FormatSection("", i18n.G(`lxc network address-set create trusted 192.0.2.10 198.51.100.0/24 2001:db8::/64
+
+lxc network address-set create trusted < config.yaml
+    Create network address set with configuration from config.yaml`))
+
+	cmd.Flags().StringVar(&c.flagDescription, "description", "", i18n.G("Address set description")+"``")
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("network_address_set", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdNetworkAddressSetCreate) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, -1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing network address set name"))
+	}
+
+	// If stdin isn't a terminal, read yaml from it.
+	var addressSetPut api.NetworkAddressSetPut
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		err = yaml.UnmarshalStrict(contents, &addressSetPut)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Create the network address set.
+	addressSet := api.NetworkAddressSetsPost{
+		NetworkAddressSetPost: api.NetworkAddressSetPost{
+			Name: resource.name,
+		},
+		NetworkAddressSetPut: addressSetPut,
+	}
+
+	if c.flagDescription != "" {
+		addressSet.Description = c.flagDescription
+	}
+
+	if addressSet.Config == nil {
+		addressSet.Config = map[string]string{}
+	}
+
+	// Arguments are either key=value configuration pairs or addresses.
+	for i := 1; i < len(args); i++ {
+		entry := strings.SplitN(args[i], "=", 2)
+		if len(entry) < 2 {
+			addressSet.Addresses = append(addressSet.Addresses, args[i])
+			continue
+		}
+
+		addressSet.Config[entry[0]] = entry[1]
+	}
+
+	err = resource.server.CreateNetworkAddressSet(addressSet)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Network address set %s created")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Set.
+type cmdNetworkAddressSetSet struct {
+	global            *cmdGlobal
+	networkAddressSet *cmdNetworkAddressSet
+
+	flagIsProperty bool
+}
+
+func (c *cmdNetworkAddressSetSet) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("set", i18n.G("[<remote>:]<address-set> <key>=<value>..."))
+	cmd.Short = i18n.G("Set network address set configuration keys")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Set network address set configuration keys"))
+
+	cmd.Flags().BoolVarP(&c.flagIsProperty, "property", "p", false, i18n.G("Set the key as a network address set property"))
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("network_address_set", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdNetworkAddressSetSet) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, -1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing network address set name"))
+	}
+
+	// Get the network address set.
+	addressSet, etag, err := resource.server.GetNetworkAddressSet(resource.name)
+	if err != nil {
+		return err
+	}
+
+	// Set the keys.
+	keys, err := getConfig(args[1:]...)
+	if err != nil {
+		return err
+	}
+
+	writable := addressSet.Writable()
+	if c.flagIsProperty {
+		if cmd.Name() == "unset" {
+			for k := range keys {
+				err := unsetFieldByJSONTag(&writable, k)
+				if err != nil {
+					return fmt.Errorf(i18n.G("Error unsetting property: %v"), err)
+				}
+			}
+		} else {
+			err := unpackKVToWritable(&writable, keys)
+			if err != nil {
+				return fmt.Errorf(i18n.G("Error setting properties: %v"), err)
+			}
+		}
+	} else {
+		if writable.Config == nil {
+			writable.Config = map[string]string{}
+		}
+
+		maps.Copy(writable.Config, keys)
+	}
+
+	return resource.server.UpdateNetworkAddressSet(resource.name, writable, etag)
+}
+
+// Unset.
+type cmdNetworkAddressSetUnset struct {
+	global               *cmdGlobal
+	networkAddressSet    *cmdNetworkAddressSet
+	networkAddressSetSet *cmdNetworkAddressSetSet
+
+	flagIsProperty bool
+}
+
+func (c *cmdNetworkAddressSetUnset) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("unset", i18n.G("[<remote>:]<address-set> <key>"))
+	cmd.Short = i18n.G("Unset network address set configuration keys")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Unset network address set configuration keys"))
+	cmd.RunE = c.run
+
+	cmd.Flags().BoolVarP(&c.flagIsProperty, "property", "p", false, i18n.G("Unset the key as a network address set property"))
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("network_address_set", toComplete)
+		}
+
+		if len(args) == 1 {
+			return c.global.cmpNetworkAddressSetConfigs(args[0])
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdNetworkAddressSetUnset) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	c.networkAddressSetSet.flagIsProperty = c.flagIsProperty
+
+	args = append(args, "")
+	return c.networkAddressSetSet.run(cmd, args)
+}
+
+// Edit.
+type cmdNetworkAddressSetEdit struct {
+	global            *cmdGlobal
+	networkAddressSet *cmdNetworkAddressSet
+}
+
+func (c *cmdNetworkAddressSetEdit) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("edit", i18n.G("[<remote>:]<address-set>"))
+	cmd.Short = i18n.G("Edit network address set configurations as YAML")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Edit network address set configurations as YAML"))
+
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("network_address_set", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdNetworkAddressSetEdit) helpTemplate() string {
+	return i18n.G(
+		`### This is a YAML representation of the network address set.
+### Any line starting with a '# will be ignored.
+###
+### An example would look like:
+### name: trusted
+### description: Trusted hosts
+### addresses:
+### - 192.0.2.10
+### - 198.51.100.0/24
+### - 2001:db8::/64
+### config:
+###  user.foo: bah
+###
+### Note that only the description, addresses and configuration keys can be changed.`)
+}
+
+func (c *cmdNetworkAddressSetEdit) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing network address set name"))
+	}
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		// Allow output of `lxc network address-set show` command to be passed in here, but only take the
+		// contents of the NetworkAddressSetPut fields when updating. The other fields are silently discarded.
+		newdata := api.NetworkAddressSet{}
+		err = yaml.UnmarshalStrict(contents, &newdata)
+		if err != nil {
+			return err
+		}
+
+		return resource.server.UpdateNetworkAddressSet(resource.name, newdata.Writable(), "")
+	}
+
+	// Get the current config.
+	addressSet, etag, err := resource.server.GetNetworkAddressSet(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&addressSet)
+	if err != nil {
+		return err
+	}
+
+	// Spawn the editor.
+	content, err := shared.TextEditor("", []byte(c.helpTemplate()+"\n\n"+string(data)))
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Parse the text received from the editor.
+		newdata := api.NetworkAddressSet{} // We show the full address set info, but only send the writable fields.
+		err = yaml.UnmarshalStrict(content, &newdata)
+		if err == nil {
+			err = resource.server.UpdateNetworkAddressSet(resource.name, newdata.Writable(), etag)
+		}
+
+		// Respawn the editor.
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.G("Config parsing error: %s")+"\n", err)
+			fmt.Println(i18n.G("Press enter to open the editor again or ctrl+c to abort change"))
+
+			_, err := os.Stdin.Read(make([]byte, 1))
+			if err != nil {
+				return err
+			}
+
+			content, err = shared.TextEditor("", content)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		break
+	}
+
+	return nil
+}
+
+// Rename.
+type cmdNetworkAddressSetRename struct {
+	global            *cmdGlobal
+	networkAddressSet *cmdNetworkAddressSet
+}
+
+func (c *cmdNetworkAddressSetRename) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("rename", i18n.G("[<remote>:]<address-set> <new-name>"))
+	cmd.Aliases = []string{"mv"}
+	cmd.Short = i18n.G("Rename network address sets")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Rename network address sets"))
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("network_address_set", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdNetworkAddressSetRename) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing network address set name"))
+	}
+
+	// Rename the network address set.
+	err = resource.server.RenameNetworkAddressSet(resource.name, api.NetworkAddressSetPost{Name: args[1]})
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Network address set %s renamed to %s")+"\n", resource.name, args[1])
+	}
+
+	return nil
+}
+
+// Delete.
+type cmdNetworkAddressSetDelete struct {
+	global            *cmdGlobal
+	networkAddressSet *cmdNetworkAddressSet
+}
+
+func (c *cmdNetworkAddressSetDelete) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("delete", i18n.G("[<remote>:]<address-set>"))
+	cmd.Aliases = []string{"rm"}
+	cmd.Short = i18n.G("Delete network address sets")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Delete network address sets"))
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("network_address_set", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdNetworkAddressSetDelete) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing network address set name"))
+	}
+
+	// Delete the network address set.
+	err = resource.server.DeleteNetworkAddressSet(resource.name)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Network address set %s deleted")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Add/Remove addresses.
+type cmdNetworkAddressSetAddress struct {
+	global            *cmdGlobal
+	networkAddressSet *cmdNetworkAddressSet
+}
+
+func (c *cmdNetworkAddressSetAddress) commandAdd() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("add", i18n.G("[<remote>:]<address-set> <address>..."))
+	cmd.Short = i18n.G("Add addresses to a network address set")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Add addresses to a network address set"))
+	cmd.RunE = c.runAdd
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("network_address_set", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdNetworkAddressSetAddress) runAdd(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, -1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing network address set name"))
+	}
+
+	// Get the network address set.
+	addressSet, etag, err := resource.server.GetNetworkAddressSet(resource.name)
+	if err != nil {
+		return err
+	}
+
+	writable := addressSet.Writable()
+	for _, address := range args[1:] {
+		if slices.Contains(writable.Addresses, address) {
+			return fmt.Errorf(i18n.G("Address %q already exists in the network address set"), address)
+		}
+
+		writable.Addresses = append(writable.Addresses, address)
+	}
+
+	return resource.server.UpdateNetworkAddressSet(resource.name, writable, etag)
+}
+
+func (c *cmdNetworkAddressSetAddress) commandRemove() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("remove", i18n.G("[<remote>:]<address-set> <address>..."))
+	cmd.Short = i18n.G("Remove addresses from a network address set")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Remove addresses from a network address set"))
+	cmd.RunE = c.runRemove
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("network_address_set", toComplete)
+		}
+
+		return c.global.cmpNetworkAddressSetAddresses(args[0])
+	}
+
+	return cmd
+}
+
+func (c *cmdNetworkAddressSetAddress) runRemove(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, -1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing network address set name"))
+	}
+
+	// Get the network address set.
+	addressSet, etag, err := resource.server.GetNetworkAddressSet(resource.name)
+	if err != nil {
+		return err
+	}
+
+	writable := addressSet.Writable()
+	for _, address := range args[1:] {
+		if !slices.Contains(writable.Addresses, address) {
+			return fmt.Errorf(i18n.G("Address %q not found in the network address set"), address)
+		}
+
+		writable.Addresses = slices.DeleteFunc(writable.Addresses, func(existing string) bool { return existing == address })
+	}
+
+	return resource.server.UpdateNetworkAddressSet(resource.name, writable, etag)
+}
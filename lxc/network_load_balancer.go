@@ -43,6 +43,10 @@ func (c *cmdNetworkLoadBalancer) command() *cobra.Command {
 	networkLoadBalancerShowCmd := cmdNetworkLoadBalancerShow{global: c.global, networkLoadBalancer: c}
 	cmd.AddCommand(networkLoadBalancerShowCmd.command())
 
+	// Info.
+	networkLoadBalancerInfoCmd := cmdNetworkLoadBalancerInfo{global: c.global, networkLoadBalancer: c}
+	cmd.AddCommand(networkLoadBalancerInfoCmd.command())
+
 	// Create.
 	networkLoadBalancerCreateCmd := cmdNetworkLoadBalancerCreate{global: c.global, networkLoadBalancer: c}
 	cmd.AddCommand(networkLoadBalancerCreateCmd.command())
@@ -247,6 +251,97 @@ func (c *cmdNetworkLoadBalancerShow) run(cmd *cobra.Command, args []string) erro
 	return nil
 }
 
+// Info.
+type cmdNetworkLoadBalancerInfo struct {
+	global              *cmdGlobal
+	networkLoadBalancer *cmdNetworkLoadBalancer
+}
+
+func (c *cmdNetworkLoadBalancerInfo) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("info", i18n.G("[<remote>:]<network> <listen_address>"))
+	cmd.Short = i18n.G("Get current load balancer status")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Get current load balancer status, including the health of its backends"))
+	cmd.RunE = c.run
+
+	cmd.Flags().StringVar(&c.networkLoadBalancer.flagTarget, "target", "", i18n.G("Cluster member name")+"``")
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("network", toComplete)
+		}
+
+		if len(args) == 1 {
+			return c.global.cmpNetworkLoadBalancers(args[0])
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdNetworkLoadBalancerInfo) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing network name"))
+	}
+
+	if args[1] == "" {
+		return errors.New(i18n.G("Missing listen address"))
+	}
+
+	client := resource.server
+
+	// If a target was specified, use the load balancer on the given member.
+	if c.networkLoadBalancer.flagTarget != "" {
+		client = client.UseTarget(c.networkLoadBalancer.flagTarget)
+	}
+
+	// Get the load balancer state.
+	lbState, err := client.GetNetworkLoadBalancerState(resource.name, args[1])
+	if err != nil {
+		return err
+	}
+
+	backendNames := make([]string, 0, len(lbState.BackendHealth))
+	for backendName := range lbState.BackendHealth {
+		backendNames = append(backendNames, backendName)
+	}
+
+	sort.Strings(backendNames)
+
+	fmt.Println(i18n.G("Backend health:"))
+	for _, backendName := range backendNames {
+		backend := lbState.BackendHealth[backendName]
+
+		fmt.Printf("  %s (%s):\n", backendName, backend.Address)
+		if len(backend.Ports) == 0 {
+			fmt.Println("    " + i18n.G("No active ports"))
+			continue
+		}
+
+		for _, port := range backend.Ports {
+			fmt.Printf("    %s/%d: %s\n", port.Protocol, port.Port, port.Status)
+		}
+	}
+
+	return nil
+}
+
 // Create.
 type cmdNetworkLoadBalancerCreate struct {
 	global              *cmdGlobal
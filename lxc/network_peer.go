@@ -232,9 +232,16 @@ type cmdNetworkPeerCreate struct {
 
 func (c *cmdNetworkPeerCreate) command() *cobra.Command {
 	cmd := &cobra.Command{}
-	cmd.Use = usage("create", i18n.G("[<remote>:]<network> <peer_name> <[target project/]target_network> [key=value...]"))
+	cmd.Use = usage("create", i18n.G("[<remote>:]<network> <peer_name> [<[target project/]target_network>] [key=value...]"))
 	cmd.Short = i18n.G("Create new network peering")
-	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Create new network peering"))
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(`Create new network peering
+
+The target network is required for OVN networks and isn't used by the WireGuard peers of bridge networks.`))
+	cmd.Example = cli.FormatSection("", i18n.G(`lxc network peer create ovn1 peer1 ovn2
+    Create a peering between the ovn1 and ovn2 OVN networks.
+
+lxc network peer create lxdbr0 site2 wireguard.public_key=<key> wireguard.endpoint=198.51.100.10:51820 wireguard.routes=10.2.0.0/24
+    Create a WireGuard peer on the lxdbr0 bridge network.`))
 	cmd.RunE = c.run
 
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -250,7 +257,7 @@ func (c *cmdNetworkPeerCreate) command() *cobra.Command {
 
 func (c *cmdNetworkPeerCreate) run(cmd *cobra.Command, args []string) error {
 	// Quick checks.
-	exit, err := c.global.CheckArgs(cmd, args, 3, -1)
+	exit, err := c.global.CheckArgs(cmd, args, 2, -1)
 	if exit {
 		return err
 	}
@@ -271,18 +278,23 @@ func (c *cmdNetworkPeerCreate) run(cmd *cobra.Command, args []string) error {
 		return errors.New(i18n.G("Missing peer name"))
 	}
 
-	if args[2] == "" {
-		return errors.New(i18n.G("Missing target network"))
-	}
+	// The target network is optional and can't contain "=", unlike the key/value pairs that follow it.
+	var targetProject, targetNetwork string
+	configArgs := args[2:]
+	if len(args) > 2 && !strings.Contains(args[2], "=") {
+		if args[2] == "" {
+			return errors.New(i18n.G("Missing target network"))
+		}
 
-	targetParts := strings.SplitN(args[2], "/", 2)
+		targetParts := strings.SplitN(args[2], "/", 2)
+		if len(targetParts) == 2 {
+			targetProject = targetParts[0]
+			targetNetwork = targetParts[1]
+		} else {
+			targetNetwork = targetParts[0]
+		}
 
-	var targetProject, targetNetwork string
-	if len(targetParts) == 2 {
-		targetProject = targetParts[0]
-		targetNetwork = targetParts[1]
-	} else {
-		targetNetwork = targetParts[0]
+		configArgs = args[3:]
 	}
 
 	// If stdin isn't a terminal, read yaml from it.
@@ -304,10 +316,10 @@ func (c *cmdNetworkPeerCreate) run(cmd *cobra.Command, args []string) error {
 	}
 
 	// Get config filters from arguments.
-	for i := 3; i < len(args); i++ {
-		entry := strings.SplitN(args[i], "=", 2)
+	for _, arg := range configArgs {
+		entry := strings.SplitN(arg, "=", 2)
 		if len(entry) < 2 {
-			return fmt.Errorf(i18n.G("Bad key/value pair: %s"), args[i])
+			return fmt.Errorf(i18n.G("Bad key/value pair: %s"), arg)
 		}
 
 		peerPut.Config[entry[0]] = entry[1]
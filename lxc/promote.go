@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	cli "github.com/canonical/lxd/shared/cmd"
+	"github.com/canonical/lxd/shared/i18n"
+)
+
+// Promote.
+type cmdPromote struct {
+	global *cmdGlobal
+}
+
+func (c *cmdPromote) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("promote", i18n.G("[<remote>:]<instance>"))
+	cmd.Short = i18n.G("Promote instance replicas")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Promote instance replicas
+
+Replicas created by storage pool replication can't be started until they are promoted.
+Once promoted, the instance is no longer refreshed from its source.`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`lxc promote c1
+    Promote the replica "c1" so that it can be started.`))
+
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		return c.global.cmpTopLevelResource("instance", toComplete)
+	}
+
+	return cmd
+}
+
+func (c *cmdPromote) run(cmd *cobra.Command, args []string) error {
+	conf := c.global.conf
+
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	remote, name, err := conf.ParseRemote(args[0])
+	if err != nil {
+		return err
+	}
+
+	d, err := conf.GetInstanceServer(remote)
+	if err != nil {
+		return err
+	}
+
+	op, err := d.PromoteInstance(name)
+	if err != nil {
+		return err
+	}
+
+	err = op.Wait()
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Instance %s promoted")+"\n", name)
+	}
+
+	return nil
+}
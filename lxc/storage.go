@@ -71,6 +71,10 @@ func (c *cmdStorage) command() *cobra.Command {
 	storageUnsetCmd := cmdStorageUnset{global: c.global, storage: c, storageSet: &storageSetCmd}
 	cmd.AddCommand(storageUnsetCmd.command())
 
+	// Usage
+	storageUsageCmd := cmdStorageUsage{global: c.global, storage: c}
+	cmd.AddCommand(storageUsageCmd.command())
+
 	// Bucket
 	storageBucketCmd := cmdStorageBucket{global: c.global}
 	cmd.AddCommand(storageBucketCmd.command())
@@ -970,3 +974,156 @@ func (c *cmdStorageUnset) run(cmd *cobra.Command, args []string) error {
 	args = append(args, "")
 	return c.storageSet.run(cmd, args)
 }
+
+// Usage.
+type cmdStorageUsage struct {
+	global  *cmdGlobal
+	storage *cmdStorage
+
+	flagAllProjects bool
+	flagBytes       bool
+	flagFormat      string
+}
+
+func (c *cmdStorageUsage) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("usage", i18n.G("[<remote>:]<pool>"))
+	cmd.Short = i18n.G("Show the disk usage of storage pool volumes")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Show the disk usage of storage pool volumes
+
+The used and provisioned space of every instance and custom volume and of
+their snapshots is listed, followed by the totals of each project and the
+project disk limits on the pool.`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`lxc storage usage default
+    Show the disk usage of the volumes of the current project on pool "default"
+
+lxc storage usage default --all-projects --format=yaml
+    Show the disk usage of the volumes of all projects on pool "default" in YAML`))
+
+	cmd.Flags().BoolVar(&c.flagAllProjects, "all-projects", false, i18n.G("Display the disk usage of all projects"))
+	cmd.Flags().BoolVar(&c.flagBytes, "bytes", false, i18n.G("Show the used and provisioned space in bytes"))
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G("Format (csv|json|table|yaml|compact)")+"``")
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("storage_pool", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdStorageUsage) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing pool name"))
+	}
+
+	// Get the usage
+	var poolUsage *api.StoragePoolUsage
+	if c.flagAllProjects {
+		poolUsage, err = resource.server.GetStoragePoolUsageAllProjects(resource.name)
+	} else {
+		poolUsage, err = resource.server.GetStoragePoolUsage(resource.name)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// Structured formats render the whole usage at once.
+	if c.flagFormat == cli.TableFormatJSON || c.flagFormat == cli.TableFormatYAML {
+		return cli.RenderTable(c.flagFormat, nil, nil, poolUsage)
+	}
+
+	formatSize := func(size int64) string {
+		if c.flagBytes {
+			return strconv.FormatInt(size, 10)
+		}
+
+		return units.GetByteSizeStringIEC(size, 2)
+	}
+
+	// formatUsage shows the size unless retrieving the usage failed.
+	formatUsage := func(size int64, usageErr string) string {
+		if usageErr != "" {
+			return i18n.G("ERROR")
+		}
+
+		return formatSize(size)
+	}
+
+	formatLimit := func(limit *int64) string {
+		if limit == nil {
+			return ""
+		}
+
+		if *limit < 0 {
+			return "-"
+		}
+
+		return formatSize(*limit)
+	}
+
+	volumesData := [][]string{}
+	for _, vol := range poolUsage.Volumes {
+		volumesData = append(volumesData, []string{vol.Project, vol.Type, vol.Name, vol.Location, formatUsage(vol.UsedBytes, vol.Error), formatUsage(vol.ProvisionedBytes, vol.Error)})
+
+		for _, snap := range vol.Snapshots {
+			snapName := vol.Name + shared.SnapshotDelimiter + snap.Name
+			volumesData = append(volumesData, []string{vol.Project, vol.Type, snapName, vol.Location, formatUsage(snap.UsedBytes, snap.Error), formatUsage(snap.ProvisionedBytes, snap.Error)})
+		}
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(volumesData))
+
+	volumesHeader := []string{
+		i18n.G("PROJECT"),
+		i18n.G("TYPE"),
+		i18n.G("NAME"),
+		i18n.G("LOCATION"),
+		i18n.G("USED"),
+		i18n.G("PROVISIONED"),
+	}
+
+	err = cli.RenderTable(c.flagFormat, volumesHeader, volumesData, poolUsage.Volumes)
+	if err != nil {
+		return err
+	}
+
+	projectsData := [][]string{}
+	for _, project := range poolUsage.Projects {
+		projectsData = append(projectsData, []string{project.Name, formatSize(project.UsedBytes), formatSize(project.ProvisionedBytes), formatLimit(project.LimitBytes)})
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(projectsData))
+
+	projectsHeader := []string{
+		i18n.G("PROJECT"),
+		i18n.G("USED"),
+		i18n.G("PROVISIONED"),
+		i18n.G("LIMIT"),
+	}
+
+	fmt.Println()
+
+	return cli.RenderTable(c.flagFormat, projectsHeader, projectsData, poolUsage.Projects)
+}
@@ -386,6 +386,7 @@ type cmdStorageVolumeCopy struct {
 	flagVolumeOnly    bool
 	flagTargetProject string
 	flagRefresh       bool
+	flagLive          bool
 }
 
 func (c *cmdStorageVolumeCopy) command() *cobra.Command {
@@ -548,6 +549,7 @@ func (c *cmdStorageVolumeCopy) run(cmd *cobra.Command, args []string) error {
 		args.Mode = mode
 		args.VolumeOnly = false
 		args.Project = c.flagTargetProject
+		args.Live = c.flagLive
 
 		op, err = dstServer.MoveStoragePoolVolume(dstVolPool, srcServer, srcVolPool, *srcVol, args)
 		if err != nil {
@@ -1904,6 +1906,7 @@ func (c *cmdStorageVolumeMove) command() *cobra.Command {
 	cmd.Flags().StringVar(&c.storage.flagTarget, "target", "", i18n.G("Cluster member name")+"``")
 	cmd.Flags().StringVar(&c.storageVolume.flagDestinationTarget, "destination-target", "", i18n.G("Destination cluster member name")+"``")
 	cmd.Flags().StringVar(&c.storageVolumeCopy.flagTargetProject, "target-project", "", i18n.G("Move to a project different from the source")+"``")
+	cmd.Flags().BoolVar(&c.storageVolumeCopy.flagLive, "live", false, i18n.G("Move the volume while in use by running instances"))
 	cmd.RunE = c.run
 
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -2690,6 +2693,8 @@ type cmdStorageVolumeExport struct {
 	flagOptimizedStorage     bool
 	flagCompressionAlgorithm string
 	flagExportVersion        string
+	flagRecipient            []string
+	flagPassphrase           bool
 }
 
 func (c *cmdStorageVolumeExport) command() *cobra.Command {
@@ -2706,6 +2711,8 @@ func (c *cmdStorageVolumeExport) command() *cobra.Command {
 	cmd.Flags().StringVar(&c.flagExportVersion, "export-version", "",
 		i18n.G("Use a different metadata format version than the latest one supported by the server (to support imports on older LXD versions)")+"``")
 	cmd.Flags().StringVar(&c.storage.flagTarget, "target", "", i18n.G("Cluster member name")+"``")
+	cmd.Flags().StringArrayVar(&c.flagRecipient, "recipient", nil, i18n.G("Encrypt the backup for the age recipients in the given file (can be repeated)")+"``")
+	cmd.Flags().BoolVar(&c.flagPassphrase, "passphrase", false, i18n.G("Encrypt the backup with a passphrase"))
 	cmd.RunE = c.run
 
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -2755,12 +2762,28 @@ func (c *cmdStorageVolumeExport) run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("Failed to create storage volume backup for volume %q: %w", volName, errors.New(i18n.G("Only \"custom\" volumes can be exported")))
 	}
 
+	if len(c.flagRecipient) > 0 && c.flagPassphrase {
+		return errors.New(i18n.G("--recipient and --passphrase can't be combined"))
+	}
+
+	recipients, err := readBackupRecipients(c.flagRecipient)
+	if err != nil {
+		return err
+	}
+
+	passphrase := ""
+	if c.flagPassphrase {
+		passphrase = c.global.asker.AskPassword(i18n.G("Backup passphrase: "))
+	}
+
 	req := api.StoragePoolVolumeBackupsPost{
 		Name:                 "",
 		ExpiresAt:            time.Now().Add(24 * time.Hour),
 		VolumeOnly:           volumeOnly,
 		OptimizedStorage:     c.flagOptimizedStorage,
 		CompressionAlgorithm: c.flagCompressionAlgorithm,
+		EncryptionRecipients: recipients,
+		EncryptionPassphrase: passphrase,
 	}
 
 	req.Version, err = getExportVersion(d, c.flagExportVersion)
@@ -2863,7 +2886,9 @@ type cmdStorageVolumeImport struct {
 	storage       *cmdStorage
 	storageVolume *cmdStorageVolume
 
-	flagType string
+	flagType       string
+	flagIdentity   string
+	flagPassphrase bool
 }
 
 func (c *cmdStorageVolumeImport) command() *cobra.Command {
@@ -2881,6 +2906,8 @@ func (c *cmdStorageVolumeImport) command() *cobra.Command {
 - backup: custom volume backup (default option)
 - iso: iso image, will be imported as iso volume
 - tar: tarball, will be imported as custom filesystem volume`)+"``")
+	cmd.Flags().StringVar(&c.flagIdentity, "identity", "", i18n.G("File with the age identity to decrypt an encrypted backup with")+"``")
+	cmd.Flags().BoolVar(&c.flagPassphrase, "passphrase", false, i18n.G("Decrypt an encrypted backup with a passphrase"))
 
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) == 0 {
@@ -2961,6 +2988,19 @@ func (c *cmdStorageVolumeImport) run(cmd *cobra.Command, args []string) error {
 		return errors.New("Importing tar archives requires a volume name to be set")
 	}
 
+	var identity []byte
+	if c.flagIdentity != "" {
+		identity, err = os.ReadFile(shared.HostPathFollow(c.flagIdentity))
+		if err != nil {
+			return err
+		}
+	}
+
+	passphrase := ""
+	if c.flagPassphrase {
+		passphrase = c.global.asker.AskPasswordOnce(i18n.G("Backup passphrase: "))
+	}
+
 	progress := cli.ProgressRenderer{
 		Format: i18n.G("Importing custom volume: %s"),
 		Quiet:  c.global.flagQuiet,
@@ -2976,7 +3016,9 @@ func (c *cmdStorageVolumeImport) run(cmd *cobra.Command, args []string) error {
 				},
 			},
 		},
-		Name: volName,
+		Name:       volName,
+		Identity:   identity,
+		Passphrase: passphrase,
 	}
 
 	var op lxd.Operation
@@ -457,6 +457,21 @@ func getExportVersion(d lxd.InstanceServer, flag string) (uint32, error) {
 	return 0, nil
 }
 
+// readBackupRecipients reads the PEM encoded public keys to encrypt a backup for from the given files.
+func readBackupRecipients(paths []string) ([]string, error) {
+	recipients := make([]string, 0, len(paths))
+	for _, path := range paths {
+		content, err := os.ReadFile(shared.HostPathFollow(path))
+		if err != nil {
+			return nil, fmt.Errorf(i18n.G("Failed reading recipient %q: %w"), path, err)
+		}
+
+		recipients = append(recipients, string(content))
+	}
+
+	return recipients, nil
+}
+
 // newLocationHeaderTransportWrapper returns a new transport wrapper that can be used to inspect the `Location` header
 // upon the response of a resource creation request to LXD.
 func newLocationHeaderTransportWrapper() (*locationHeaderTransport, func(transport *http.Transport) lxd.HTTPTransporter) {
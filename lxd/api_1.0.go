@@ -53,6 +53,8 @@ var api10Cmd = APIEndpoint{
 var api10 = []APIEndpoint{
 	api10Cmd,
 	api10ResourcesCmd,
+	backupTargetCmd,
+	backupTargetsCmd,
 	certificateCmd,
 	certificatesCmd,
 	clusterCmd,
@@ -62,8 +64,10 @@ var api10 = []APIEndpoint{
 	clusterNodeStateCmd,
 	clusterNodesCmd,
 	clusterCertificateCmd,
+	clusterRebalanceCmd,
 	instanceBackupCmd,
 	instanceBackupExportCmd,
+	instanceBackupInfoCmd,
 	instanceBackupsCmd,
 	instanceCmd,
 	instanceConsoleCmd,
@@ -71,12 +75,15 @@ var api10 = []APIEndpoint{
 	instanceFileCmd,
 	instanceExecOutputCmd,
 	instanceExecOutputsCmd,
+	instanceConsoleRecordingCmd,
+	instanceConsoleRecordingsCmd,
 	instanceLogCmd,
 	instanceLogsCmd,
 	instanceMetadataCmd,
 	instanceMetadataTemplatesCmd,
 	instancesCmd,
 	instanceRebuildCmd,
+	instancePromoteCmd,
 	instanceSFTPCmd,
 	instanceSnapshotCmd,
 	instanceSnapshotsCmd,
@@ -98,10 +105,14 @@ var api10 = []APIEndpoint{
 	networkACLCmd,
 	networkACLsCmd,
 	networkACLLogCmd,
+	networkACLStateCmd,
+	networkAddressSetCmd,
+	networkAddressSetsCmd,
 	networkAllocationsCmd,
 	networkForwardCmd,
 	networkForwardsCmd,
 	networkLoadBalancerCmd,
+	networkLoadBalancerStateCmd,
 	networkLoadBalancersCmd,
 	networkPeerCmd,
 	networkPeersCmd,
@@ -120,6 +131,7 @@ var api10 = []APIEndpoint{
 	projectStateCmd,
 	storagePoolCmd,
 	storagePoolResourcesCmd,
+	storagePoolUsageCmd,
 	storagePoolsCmd,
 	storagePoolBucketsCmd,
 	storagePoolBucketCmd,
@@ -130,6 +142,7 @@ var api10 = []APIEndpoint{
 	storagePoolVolumeSnapshotTypeCmd,
 	storagePoolVolumesTypeCmd,
 	storagePoolVolumeTypeCmd,
+	storagePoolVolumeTypePromoteCmd,
 	storagePoolVolumeTypeCustomBackupsCmd,
 	storagePoolVolumeTypeCustomBackupCmd,
 	storagePoolVolumeTypeCustomBackupExportCmd,
@@ -1112,7 +1125,7 @@ func doAPI10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 			fallthrough
 		case "core.bgp_routerid":
 			bgpChanged = true
-		case "core.dns_address":
+		case "core.dns_address", "core.dns_authoritative":
 			dnsChanged = true
 		case "core.syslog_socket":
 			syslogSocketChanged = true
@@ -1227,6 +1240,8 @@ func doAPI10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 	if dnsChanged {
 		address := newNodeConfig.DNSAddress()
 
+		s.DNS.SetAuthoritative(newNodeConfig.DNSAuthoritative())
+
 		err := s.DNS.Reconfigure(address)
 		if err != nil {
 			return fmt.Errorf("Failed reconfiguring DNS: %w", err)
@@ -288,6 +288,20 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 	wg.Wait()
 	close(instMetricsCh)
 
+	// Add the storage volume usage metrics gathered in the background.
+	projectNamesToFetch := make([]string, 0, len(projectsToFetch))
+	for _, filter := range projectsToFetch {
+		projectNamesToFetch = append(projectNamesToFetch, *filter.Project)
+	}
+
+	for projectName, storageMetrics := range storagePoolUsageMetrics(projectNamesToFetch) {
+		if newMetrics[projectName] == nil {
+			newMetrics[projectName] = metrics.NewMetricSet(nil)
+		}
+
+		newMetrics[projectName].Merge(storageMetrics)
+	}
+
 	// Put the new data in the global cache and in response.
 	metricsCacheLock.Lock()
 
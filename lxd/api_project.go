@@ -229,7 +229,7 @@ func projectsGet(d *Daemon, r *http.Request) response.Response {
 }
 
 // projectUsedBy returns a list of URLs for all instances, images, profiles,
-// storage volumes, storage buckets, networks, acls, and placement groups that use this project.
+// storage volumes, storage buckets, networks, acls, address sets, and placement groups that use this project.
 func projectUsedBy(ctx context.Context, tx *db.ClusterTx, project *dbCluster.Project) ([]string, error) {
 	reportedEntityTypes := []entity.Type{
 		entity.TypeInstance,
@@ -238,6 +238,7 @@ func projectUsedBy(ctx context.Context, tx *db.ClusterTx, project *dbCluster.Pro
 		entity.TypeStorageVolume,
 		entity.TypeNetwork,
 		entity.TypeNetworkACL,
+		entity.TypeNetworkAddressSet,
 		entity.TypeStorageBucket,
 		entity.TypePlacementGroup,
 	}
@@ -1155,6 +1156,8 @@ func doProjectForceDelete(ctx context.Context, s *state.State, projectName strin
 				return 1 // Delete instances first.
 			case entity.TypeProfile:
 				return 2 // Delete profiles after instances to avoid "profile is currently in use" errors.
+			case entity.TypeNetworkAddressSet:
+				return 4 // Delete address sets after the network ACLs that reference them.
 			default:
 				return 3 // Everything else can be deleted in any order.
 			}
@@ -1444,6 +1447,14 @@ func projectValidateConfig(s *state.State, config map[string]string, defaultNetw
 		//  type: string
 		//  shortdesc: Compression algorithm to use for backups
 		"backups.compression_algorithm": validate.IsCompressionAlgorithm,
+		// lxdmeta:generate(entities=project; group=specific; key=cluster.rebalance)
+		// Whether instances in this project can be moved between cluster members by cluster rebalancing.
+		// See {config:option}`server-cluster:cluster.rebalance.interval` for more information.
+		// ---
+		//  type: bool
+		//  defaultdesc: `false`
+		//  shortdesc: Whether to allow cluster rebalancing to move instances
+		"cluster.rebalance": validate.Optional(validate.IsBool),
 		// lxdmeta:generate(entities=project; group=features; key=features.profiles)
 		//
 		// ---
@@ -0,0 +1,137 @@
+// Package asciicast records terminal sessions in the asciicast v2 format.
+package asciicast
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Version is the asciicast format version written by Writer.
+const Version = 2
+
+// Header is the first line of an asciicast recording.
+type Header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Writer writes the events of a terminal session to an asciicast recording.
+type Writer struct {
+	w     io.Writer
+	start time.Time
+	now   func() time.Time
+	mu    sync.Mutex
+
+	// Incomplete UTF-8 sequences held back from the output and input events.
+	pendingOutput []byte
+	pendingInput  []byte
+}
+
+// NewWriter writes the recording header to w and returns a Writer for the session events.
+// The width and height of the header default to 80x24 if unset.
+func NewWriter(w io.Writer, header Header) (*Writer, error) {
+	return newWriter(w, header, time.Now)
+}
+
+func newWriter(w io.Writer, header Header, now func() time.Time) (*Writer, error) {
+	start := now()
+
+	header.Version = Version
+
+	if header.Width <= 0 || header.Height <= 0 {
+		header.Width = 80
+		header.Height = 24
+	}
+
+	if header.Timestamp == 0 {
+		header.Timestamp = start.Unix()
+	}
+
+	err := writeLine(w, header)
+	if err != nil {
+		return nil, fmt.Errorf("Failed writing recording header: %w", err)
+	}
+
+	return &Writer{w: w, start: start, now: now}, nil
+}
+
+// Output records data written to the terminal.
+// UTF-8 sequences split across calls are held back until they are complete.
+func (w *Writer) Output(data []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.dataEvent("o", &w.pendingOutput, data)
+}
+
+// Input records data typed into the terminal.
+// UTF-8 sequences split across calls are held back until they are complete.
+func (w *Writer) Input(data []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.dataEvent("i", &w.pendingInput, data)
+}
+
+// Resize records a change of the terminal size.
+func (w *Writer) Resize(width int, height int) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.event("r", fmt.Sprintf("%dx%d", width, height))
+}
+
+// dataEvent writes an event for the complete UTF-8 sequences of data, keeping any trailing incomplete sequence
+// in pending. Must be called with the lock held.
+func (w *Writer) dataEvent(code string, pending *[]byte, data []byte) error {
+	data = append(*pending, data...)
+	data, *pending = splitIncompleteRune(data)
+	if len(data) == 0 {
+		return nil
+	}
+
+	return w.event(code, string(data))
+}
+
+// event writes a single event. Must be called with the lock held.
+func (w *Writer) event(code string, data string) error {
+	elapsed := math.Round(w.now().Sub(w.start).Seconds()*1e6) / 1e6
+
+	return writeLine(w.w, []any{elapsed, code, data})
+}
+
+func writeLine(w io.Writer, v any) error {
+	line, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(line, '\n'))
+	return err
+}
+
+// splitIncompleteRune splits off a trailing incomplete UTF-8 sequence.
+func splitIncompleteRune(data []byte) (complete []byte, rest []byte) {
+	for i := 1; i < utf8.UTFMax && i <= len(data); i++ {
+		if !utf8.RuneStart(data[len(data)-i]) {
+			continue
+		}
+
+		if !utf8.FullRune(data[len(data)-i:]) {
+			return data[:len(data)-i], data[len(data)-i:]
+		}
+
+		break
+	}
+
+	return data, nil
+}
@@ -0,0 +1,70 @@
+package asciicast
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	now := start
+
+	buf := &bytes.Buffer{}
+	w, err := newWriter(buf, Header{Title: "c1"}, func() time.Time { return now })
+	require.NoError(t, err)
+
+	now = start.Add(1500 * time.Millisecond)
+	require.NoError(t, w.Output([]byte("hello\r\n")))
+
+	// The euro sign is split across two reads.
+	now = start.Add(2 * time.Second)
+	require.NoError(t, w.Output([]byte{'a', 0xe2, 0x82}))
+	now = start.Add(3 * time.Second)
+	require.NoError(t, w.Output([]byte{0xac}))
+
+	now = start.Add(4 * time.Second)
+	require.NoError(t, w.Resize(120, 40))
+
+	// Input is held back separately from the output.
+	now = start.Add(5 * time.Second)
+	require.NoError(t, w.Input([]byte{'l', 's', 0xc3}))
+	now = start.Add(5500 * time.Millisecond)
+	require.NoError(t, w.Output([]byte("ls")))
+	now = start.Add(6 * time.Second)
+	require.NoError(t, w.Input([]byte{0xa9, '\r'}))
+
+	expected := `{"version":2,"width":80,"height":24,"timestamp":1700000000,"title":"c1"}
+[1.5,"o","hello\r\n"]
+[2,"o","a"]
+[3,"o","€"]
+[4,"r","120x40"]
+[5,"i","ls"]
+[5.5,"o","ls"]
+[6,"i","é\r"]
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestSplitIncompleteRune(t *testing.T) {
+	tests := []struct {
+		data     []byte
+		complete []byte
+		rest     []byte
+	}{
+		{data: []byte("abc"), complete: []byte("abc")},
+		{data: []byte("a€"), complete: []byte("a€")},
+		{data: []byte{'a', 0xe2}, complete: []byte("a"), rest: []byte{0xe2}},
+		{data: []byte{0xf0, 0x9f, 0x98}, complete: []byte{}, rest: []byte{0xf0, 0x9f, 0x98}},
+		{data: []byte{'a', 0x82}, complete: []byte{'a', 0x82}},
+	}
+
+	for _, test := range tests {
+		complete, rest := splitIncompleteRune(test.data)
+		assert.Equal(t, test.complete, complete)
+		assert.Equal(t, test.rest, rest)
+	}
+}
@@ -10,13 +10,13 @@ import (
 type Entitlement string
 
 const (
-	// EntitlementCanView is the "can_view" entitlement. It applies to the following entities: entity.TypeCertificate, entity.TypeAuthGroup, entity.TypeIdentity, entity.TypeIdentityProviderGroup, entity.TypeImage, entity.TypeImageAlias, entity.TypeInstance, entity.TypeNetwork, entity.TypeNetworkACL, entity.TypeNetworkZone, entity.TypePlacementGroup, entity.TypeProfile, entity.TypeProject, entity.TypeStorageBucket, entity.TypeStorageVolume.
+	// EntitlementCanView is the "can_view" entitlement. It applies to the following entities: entity.TypeCertificate, entity.TypeAuthGroup, entity.TypeIdentity, entity.TypeIdentityProviderGroup, entity.TypeImage, entity.TypeImageAlias, entity.TypeInstance, entity.TypeNetwork, entity.TypeNetworkACL, entity.TypeNetworkAddressSet, entity.TypeNetworkZone, entity.TypePlacementGroup, entity.TypeProfile, entity.TypeProject, entity.TypeStorageBucket, entity.TypeStorageVolume.
 	EntitlementCanView Entitlement = "can_view"
 
-	// EntitlementCanEdit is the "can_edit" entitlement. It applies to the following entities: entity.TypeCertificate, entity.TypeAuthGroup, entity.TypeIdentity, entity.TypeIdentityProviderGroup, entity.TypeImage, entity.TypeImageAlias, entity.TypeInstance, entity.TypeNetwork, entity.TypeNetworkACL, entity.TypeNetworkZone, entity.TypePlacementGroup, entity.TypeProfile, entity.TypeProject, entity.TypeServer, entity.TypeStorageBucket, entity.TypeStoragePool, entity.TypeStorageVolume.
+	// EntitlementCanEdit is the "can_edit" entitlement. It applies to the following entities: entity.TypeCertificate, entity.TypeAuthGroup, entity.TypeIdentity, entity.TypeIdentityProviderGroup, entity.TypeImage, entity.TypeImageAlias, entity.TypeInstance, entity.TypeNetwork, entity.TypeNetworkACL, entity.TypeNetworkAddressSet, entity.TypeNetworkZone, entity.TypePlacementGroup, entity.TypeProfile, entity.TypeProject, entity.TypeServer, entity.TypeStorageBucket, entity.TypeStoragePool, entity.TypeStorageVolume.
 	EntitlementCanEdit Entitlement = "can_edit"
 
-	// EntitlementCanDelete is the "can_delete" entitlement. It applies to the following entities: entity.TypeCertificate, entity.TypeAuthGroup, entity.TypeIdentity, entity.TypeIdentityProviderGroup, entity.TypeImage, entity.TypeImageAlias, entity.TypeInstance, entity.TypeNetwork, entity.TypeNetworkACL, entity.TypeNetworkZone, entity.TypePlacementGroup, entity.TypeProfile, entity.TypeProject, entity.TypeStorageBucket, entity.TypeStoragePool, entity.TypeStorageVolume.
+	// EntitlementCanDelete is the "can_delete" entitlement. It applies to the following entities: entity.TypeCertificate, entity.TypeAuthGroup, entity.TypeIdentity, entity.TypeIdentityProviderGroup, entity.TypeImage, entity.TypeImageAlias, entity.TypeInstance, entity.TypeNetwork, entity.TypeNetworkACL, entity.TypeNetworkAddressSet, entity.TypeNetworkZone, entity.TypePlacementGroup, entity.TypeProfile, entity.TypeProject, entity.TypeStorageBucket, entity.TypeStoragePool, entity.TypeStorageVolume.
 	EntitlementCanDelete Entitlement = "can_delete"
 
 	// EntitlementAdmin is the "admin" entitlement. It applies to the following entities: entity.TypeServer.
@@ -211,6 +211,21 @@ const (
 	// EntitlementCanDeleteNetworkZones is the "can_delete_network_zones" entitlement. It applies to the following entities: entity.TypeProject.
 	EntitlementCanDeleteNetworkZones Entitlement = "can_delete_network_zones"
 
+	// EntitlementNetworkAddressSetManager is the "network_address_set_manager" entitlement. It applies to the following entities: entity.TypeProject.
+	EntitlementNetworkAddressSetManager Entitlement = "network_address_set_manager"
+
+	// EntitlementCanCreateNetworkAddressSets is the "can_create_network_address_sets" entitlement. It applies to the following entities: entity.TypeProject.
+	EntitlementCanCreateNetworkAddressSets Entitlement = "can_create_network_address_sets"
+
+	// EntitlementCanViewNetworkAddressSets is the "can_view_network_address_sets" entitlement. It applies to the following entities: entity.TypeProject.
+	EntitlementCanViewNetworkAddressSets Entitlement = "can_view_network_address_sets"
+
+	// EntitlementCanEditNetworkAddressSets is the "can_edit_network_address_sets" entitlement. It applies to the following entities: entity.TypeProject.
+	EntitlementCanEditNetworkAddressSets Entitlement = "can_edit_network_address_sets"
+
+	// EntitlementCanDeleteNetworkAddressSets is the "can_delete_network_address_sets" entitlement. It applies to the following entities: entity.TypeProject.
+	EntitlementCanDeleteNetworkAddressSets Entitlement = "can_delete_network_address_sets"
+
 	// EntitlementProfileManager is the "profile_manager" entitlement. It applies to the following entities: entity.TypeProject.
 	EntitlementProfileManager Entitlement = "profile_manager"
 
@@ -387,6 +402,14 @@ var EntityTypeToEntitlements = map[entity.Type][]Entitlement{
 		// Grants permission to view the network ACL.
 		EntitlementCanView,
 	},
+	entity.TypeNetworkAddressSet: {
+		// Grants permission to edit the network address set.
+		EntitlementCanEdit,
+		// Grants permission to delete the network address set.
+		EntitlementCanDelete,
+		// Grants permission to view the network address set.
+		EntitlementCanView,
+	},
 	entity.TypeNetworkZone: {
 		// Grants permission to edit the network zone.
 		EntitlementCanEdit,
@@ -484,6 +507,16 @@ var EntityTypeToEntitlements = map[entity.Type][]Entitlement{
 		EntitlementCanEditNetworkZones,
 		// Grants permission to delete network zones.
 		EntitlementCanDeleteNetworkZones,
+		// Grants permission to create, view, edit, and delete all network address sets belonging to the project.
+		EntitlementNetworkAddressSetManager,
+		// Grants permission to create network address sets.
+		EntitlementCanCreateNetworkAddressSets,
+		// Grants permission to view network address sets.
+		EntitlementCanViewNetworkAddressSets,
+		// Grants permission to edit network address sets.
+		EntitlementCanEditNetworkAddressSets,
+		// Grants permission to delete network address sets.
+		EntitlementCanDeleteNetworkAddressSets,
 		// Grants permission to create, view, edit, and delete all profiles belonging to the project.
 		EntitlementProfileManager,
 		// Grants permission to create profiles.
@@ -3,11 +3,15 @@ package main
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"time"
 
 	"go.yaml.in/yaml/v2"
@@ -24,9 +28,11 @@ import (
 	"github.com/canonical/lxd/lxd/lifecycle"
 	"github.com/canonical/lxd/lxd/operations"
 	"github.com/canonical/lxd/lxd/project"
+	"github.com/canonical/lxd/lxd/project/limits"
 	"github.com/canonical/lxd/lxd/state"
 	storagePools "github.com/canonical/lxd/lxd/storage"
 	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/lxd/util"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/ioprogress"
@@ -35,8 +41,90 @@ import (
 	"github.com/canonical/lxd/shared/units"
 )
 
+// backupParent identifies the parent backup that an incremental backup is based on.
+type backupParent struct {
+	name string // Name of the parent backup.
+	base string // Latest snapshot included in the parent backup.
+
+	changedBlocks     bool   // Whether the backup only holds the changed blocks of the VM's root disk.
+	changedBlocksPath string // Path of the image holding the changed blocks once they have been backed up.
+}
+
+// backupLoadParent loads the stored parent backup of a new incremental instance backup.
+func backupLoadParent(s *state.State, sourceInst instance.Instance, parentName string) (*backupParent, error) {
+	pool, err := storagePools.LoadByInstance(s, sourceInst)
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading instance storage pool: %w", err)
+	}
+
+	// VMs tracking the changed blocks of their root disk support incremental backups on any storage driver.
+	changedBlocks := !pool.Driver().Info().IncrementalBackups && sourceInst.Type() == instancetype.VM && shared.IsTrue(sourceInst.ExpandedConfig()["backups.changed_block_tracking"])
+
+	if !pool.Driver().Info().IncrementalBackups && !changedBlocks {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Storage driver %q doesn't support incremental backups", pool.Driver().Info().Name)
+	}
+
+	parent, err := instance.BackupLoadByName(s, sourceInst.Project().Name, sourceInst.Name()+shared.SnapshotDelimiter+parentName)
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading parent backup %q: %w", parentName, err)
+	}
+
+	if changedBlocks {
+		if parent.OptimizedStorage() || !parent.InstanceOnly() {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Parent backup %q of a changed block tracking backup must not be optimized or include snapshots", parentName)
+		}
+
+		return &backupParent{
+			name:          parentName,
+			changedBlocks: true,
+		}, nil
+	}
+
+	if !parent.OptimizedStorage() || parent.InstanceOnly() {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Parent backup %q must be an optimized backup including snapshots", parentName)
+	}
+
+	// Read the parent's index to find the snapshots it includes.
+	parentPath := filepath.Join(s.BackupsStoragePath(sourceInst.Project().Name), "instances", project.Instance(sourceInst.Project().Name, parent.Name()))
+	parentFile, err := os.Open(parentPath)
+	if err != nil {
+		return nil, fmt.Errorf("Failed opening parent backup %q: %w", parentName, err)
+	}
+
+	defer func() { _ = parentFile.Close() }()
+
+	encrypted, err := backup.IsEncrypted(parentFile)
+	if err != nil {
+		return nil, fmt.Errorf("Failed reading parent backup %q: %w", parentName, err)
+	}
+
+	if encrypted {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Encrypted backup %q cannot be used as parent", parentName)
+	}
+
+	parentInfo, err := backup.GetInfo(s, parentFile, parentPath)
+	if err != nil {
+		return nil, fmt.Errorf("Failed reading parent backup %q: %w", parentName, err)
+	}
+
+	if parentInfo.Backup != parentName {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Parent backup %q predates incremental backup support", parentName)
+	}
+
+	if len(parentInfo.Snapshots) == 0 {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Parent backup %q doesn't include any snapshots", parentName)
+	}
+
+	return &backupParent{
+		name: parentName,
+		base: parentInfo.Snapshots[len(parentInfo.Snapshots)-1],
+	}, nil
+}
+
 // Create a new backup.
-func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.Instance, version uint32, op *operations.Operation) error {
+// If parent is set, an incremental backup containing only the changes since the parent backup is created.
+// If enc is set, the backup is encrypted.
+func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.Instance, version uint32, parent *backupParent, enc *backup.Encryption, op *operations.Operation) error {
 	l := logger.AddContext(logger.Ctx{"project": sourceInst.Project().Name, "instance": sourceInst.Name(), "name": args.Name})
 	l.Debug("Instance backup started")
 	defer l.Debug("Instance backup finished")
@@ -76,31 +164,10 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 	}
 
 	// Detect compression method.
-	var compress string
 	b.SetCompressionAlgorithm(args.CompressionAlgorithm)
-	if b.CompressionAlgorithm() != "" {
-		compress = b.CompressionAlgorithm()
-	} else {
-		var p *api.Project
-		err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-			project, err := dbCluster.GetProject(ctx, tx.Tx(), sourceInst.Project().Name)
-			if err != nil {
-				return err
-			}
-
-			p, err = project.ToAPI(ctx, tx.Tx())
-
-			return err
-		})
-		if err != nil {
-			return err
-		}
-
-		if p.Config["backups.compression_algorithm"] != "" {
-			compress = p.Config["backups.compression_algorithm"]
-		} else {
-			compress = s.GlobalConfig.BackupsCompressionAlgorithm()
-		}
+	compress, err := backupCompressionAlgorithm(s, sourceInst.Project().Name, b.CompressionAlgorithm())
+	if err != nil {
+		return err
 	}
 
 	// Create the target path if needed.
@@ -128,30 +195,138 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 	defer func() { _ = tarFileWriter.Close() }()
 	revert.Add(func() { _ = os.Remove(target) })
 
-	// Get IDMap to unshift container as the tarball is created.
-	var idmap *idmap.IdmapSet
-	if sourceInst.Type() == instancetype.Container {
-		c, ok := sourceInst.(instance.Container)
-		if !ok {
+	backupProgressWriter := &ioprogress.ProgressWriter{
+		WriteCloser: tarFileWriter,
+		Tracker: &ioprogress.ProgressTracker{
+			Handler: func(value, speed int64) {
+				_ = op.ExtendMetadata(map[string]any{"create_backup_progress": fmt.Sprintf("%s (%s/s)", units.GetByteSizeString(value, 2), units.GetByteSizeString(speed, 2))})
+			},
+		},
+	}
+
+	_, backupName, _ := api.GetParentAndSnapshotName(args.Name)
+
+	vm, isVM := sourceInst.(instance.VM)
+	changedBlockTracking := isVM && b.InstanceOnly() && sourceInst.IsRunning() && shared.IsTrue(sourceInst.ExpandedConfig()["backups.changed_block_tracking"])
+
+	// Back up the changed blocks of the VM's root disk first, so they can be added to the tarball.
+	if parent != nil && parent.changedBlocks {
+		if !isVM {
 			return errors.New("Invalid instance type")
 		}
 
-		idmap, err = c.DiskIdmap()
+		changedBlocksFile, err := os.CreateTemp(backupsPathBase, backup.WorkingDirPrefix+"_")
 		if err != nil {
-			return fmt.Errorf("Error getting container IDMAP: %w", err)
+			return err
 		}
+
+		_ = changedBlocksFile.Close()
+		defer func() { _ = os.Remove(changedBlocksFile.Name()) }()
+
+		err = vm.ChangedBlocksBackup(parent.name, backupName, changedBlocksFile.Name())
+		if err != nil {
+			return err
+		}
+
+		parent.changedBlocksPath = changedBlocksFile.Name()
 	}
 
-	// Create the tarball.
-	tarPipeReader, tarPipeWriter := io.Pipe()
-	defer func() { _ = tarPipeWriter.Close() }() // Ensure that go routine below always ends.
-	tarWriter := instancewriter.NewInstanceTarWriter(tarPipeWriter, idmap)
+	writeTarball := func() error {
+		return backupWriteInstanceTarball(backupProgressWriter, compress, enc, sourceInst, pool, args.Name, b.OptimizedStorage(), !b.InstanceOnly(), parent, version)
+	}
 
-	// Setup tar writer go routine, with optional compression.
-	tarWriterRes := make(chan error)
-	var compressErr error
+	// Full backups of VMs tracking changed blocks start a new checkpoint for incremental backups.
+	if parent == nil && changedBlockTracking {
+		err = vm.ChangedBlocksCheckpoint(backupName, writeTarball)
+	} else {
+		err = writeTarball()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	err = tarFileWriter.Close()
+	if err != nil {
+		return fmt.Errorf("Error closing tar file: %w", err)
+	}
+
+	revert.Success()
+	s.Events.SendLifecycle(sourceInst.Project().Name, lifecycle.InstanceBackupCreated.Event(args.Name, b.Instance(), nil))
+
+	return nil
+}
+
+// backupTargetNextName returns the next available backup name for the parent, based on the objects already stored
+// below the given path elements on the backup target.
+func backupTargetNextName(ctx context.Context, s *state.State, targetName string, parentName string, elems ...string) (string, error) {
+	target, err := backupTargetLoad(ctx, s, targetName)
+	if err != nil {
+		return "", err
+	}
+
+	objectNames, err := target.List(ctx, target.ObjectKey(elems...))
+	if err != nil {
+		return "", err
+	}
+
+	backupNames := make([]string, 0, len(objectNames))
+	for _, objectName := range objectNames {
+		backupNames = append(backupNames, parentName+shared.SnapshotDelimiter+objectName)
+	}
+
+	return backup.NextBackupName(parentName, "backup", backupNames), nil
+}
+
+// backupTargetCheckFree returns an error if an object with the given key already exists on the backup target.
+func backupTargetCheckFree(ctx context.Context, target *backup.Target, key string) error {
+	exists, err := target.Exists(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return api.StatusErrorf(http.StatusConflict, "Backup %q already exists on backup target %q", key, target.Name)
+	}
+
+	return nil
+}
+
+// backupCreateOnTarget streams a new instance backup to the backup target and returns the key of the created object.
+// Backups on a backup target are not recorded in the database.
+func backupCreateOnTarget(s *state.State, target *backup.Target, args db.InstanceBackup, sourceInst instance.Instance, version uint32, enc *backup.Encryption, op *operations.Operation) (string, error) {
+	_, backupName, _ := api.GetParentAndSnapshotName(args.Name)
+	key := target.ObjectKey(sourceInst.Project().Name, "instances", sourceInst.Name(), backupName)
+
+	l := logger.AddContext(logger.Ctx{"project": sourceInst.Project().Name, "instance": sourceInst.Name(), "target": target.Name, "key": key})
+	l.Debug("Instance backup to target started")
+	defer l.Debug("Instance backup to target finished")
+
+	pool, err := storagePools.LoadByInstance(s, sourceInst)
+	if err != nil {
+		return "", fmt.Errorf("Failed loading instance storage pool: %w", err)
+	}
+
+	// Ignore requests for optimized backups when pool driver doesn't support it.
+	optimized := args.OptimizedStorage && pool.Driver().Info().OptimizedBackups
+
+	compress, err := backupCompressionAlgorithm(s, sourceInst.Project().Name, args.CompressionAlgorithm)
+	if err != nil {
+		return "", err
+	}
+
+	err = backupTargetCheckFree(s.ShutdownCtx, target, key)
+	if err != nil {
+		return "", err
+	}
+
+	targetWriter, err := target.Upload(s.ShutdownCtx, key)
+	if err != nil {
+		return "", err
+	}
 
 	backupProgressWriter := &ioprogress.ProgressWriter{
+		WriteCloser: targetWriter,
 		Tracker: &ioprogress.ProgressTracker{
 			Handler: func(value, speed int64) {
 				_ = op.ExtendMetadata(map[string]any{"create_backup_progress": fmt.Sprintf("%s (%s/s)", units.GetByteSizeString(value, 2), units.GetByteSizeString(speed, 2))})
@@ -159,42 +334,142 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 		},
 	}
 
-	go func(resCh chan<- error) {
-		l.Debug("Started backup tarball writer")
-		defer l.Debug("Finished backup tarball writer")
-		if compress != "none" {
-			backupProgressWriter.WriteCloser = tarFileWriter
-			compressErr = compressFile(compress, tarPipeReader, backupProgressWriter)
+	err = backupWriteInstanceTarball(backupProgressWriter, compress, enc, sourceInst, pool, args.Name, optimized, !args.InstanceOnly, nil, version)
+	if err != nil {
+		targetWriter.Abort(err)
+		return "", err
+	}
 
-			// If a compression error occurred, close the tarPipeWriter to end the export.
-			if compressErr != nil {
-				_ = tarPipeWriter.Close()
-			}
-		} else {
-			backupProgressWriter.WriteCloser = tarFileWriter
-			_, err = io.Copy(backupProgressWriter, tarPipeReader)
+	err = targetWriter.Close()
+	if err != nil {
+		return "", err
+	}
+
+	s.Events.SendLifecycle(sourceInst.Project().Name, lifecycle.InstanceBackupCreated.Event(args.Name, sourceInst, map[string]any{"backup_target": target.Name, "backup_object": key}))
+
+	return key, nil
+}
+
+// backupCompressionAlgorithm returns the compression algorithm to use for a new backup in the given project.
+// The requested algorithm takes precedence over the project's and the server's configuration.
+func backupCompressionAlgorithm(s *state.State, projectName string, requested string) (string, error) {
+	if requested != "" {
+		return requested, nil
+	}
+
+	var p *api.Project
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		project, err := dbCluster.GetProject(ctx, tx.Tx(), projectName)
+		if err != nil {
+			return err
 		}
 
-		resCh <- err
-	}(tarWriterRes)
+		p, err = project.ToAPI(ctx, tx.Tx())
 
-	// Write index file.
-	l.Debug("Adding backup index file")
-	err = backupWriteIndex(sourceInst, pool, b.OptimizedStorage(), !b.InstanceOnly(), version, tarWriter)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
 
-	// Check compression errors.
-	if compressErr != nil {
-		return compressErr
+	if p.Config["backups.compression_algorithm"] != "" {
+		return p.Config["backups.compression_algorithm"], nil
 	}
 
-	// Check backupWriteIndex for errors.
+	return s.GlobalConfig.BackupsCompressionAlgorithm(), nil
+}
+
+// backupEncryption returns the encryption of a new backup for the requested recipients and passphrase.
+// It returns nil if the backup isn't to be encrypted.
+func backupEncryption(recipients []string, passphrase string) (*backup.Encryption, error) {
+	if len(recipients) == 0 && passphrase == "" {
+		return nil, nil
+	}
+
+	// The age format doesn't allow combining a passphrase with other recipients.
+	if len(recipients) > 0 && passphrase != "" {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Encryption recipients and passphrase can't be combined")
+	}
+
+	keys, err := backup.ParseRecipients(recipients)
 	if err != nil {
-		return fmt.Errorf("Error writing backup index file: %w", err)
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Invalid encryption recipient: %v", err)
+	}
+
+	return &backup.Encryption{Recipients: keys, Passphrase: passphrase}, nil
+}
+
+// backupDecryption returns the keys to decrypt an uploaded backup with.
+// The identity is passed base64 encoded in the X-LXD-backup-identity header and
+// the passphrase in the X-LXD-backup-passphrase header.
+func backupDecryption(r *http.Request) (*backup.Decryption, error) {
+	dec := &backup.Decryption{Passphrase: r.Header.Get("X-LXD-backup-passphrase")}
+
+	identity := r.Header.Get("X-LXD-backup-identity")
+	if identity != "" {
+		data, err := base64.StdEncoding.DecodeString(identity)
+		if err != nil {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Invalid backup identity: %v", err)
+		}
+
+		dec.Identities, err = backup.ParseIdentities(data)
+		if err != nil {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Invalid backup identity: %v", err)
+		}
+	}
+
+	return dec, nil
+}
+
+// backupWriteTarball writes a backup tarball compressed with the given algorithm to w.
+// If enc is set, the compressed tarball is encrypted.
+// The content of the tarball is added by the fill function.
+func backupWriteTarball(w io.Writer, compress string, enc *backup.Encryption, idmapSet *idmap.IdmapSet, fill func(tarWriter *instancewriter.InstanceTarWriter) error) error {
+	var encWriter io.WriteCloser
+	if enc != nil {
+		var err error
+		encWriter, err = backup.NewEncryptingWriter(w, enc)
+		if err != nil {
+			return fmt.Errorf("Error setting up backup encryption: %w", err)
+		}
+
+		w = encWriter
+	}
+
+	tarPipeReader, tarPipeWriter := io.Pipe()
+	defer func() { _ = tarPipeWriter.Close() }() // Ensure that go routine below always ends.
+	tarWriter := instancewriter.NewInstanceTarWriter(tarPipeWriter, idmapSet)
+	tarWriter.EnableChecksums()
+
+	// Setup tar writer go routine, with optional compression.
+	tarWriterRes := make(chan error, 1)
+	go func() {
+		var err error
+		if compress != "none" {
+			err = compressFile(compress, tarPipeReader, w)
+		} else {
+			_, err = io.Copy(w, tarPipeReader)
+		}
+
+		// If writing the output failed, close the pipe to end the export.
+		_ = tarPipeReader.CloseWithError(err)
+		tarWriterRes <- err
+	}()
+
+	// Output errors are passed back to the writer through the pipe, so they end up in the returned error.
+	err := fill(tarWriter)
+	if err == nil {
+		// The checksums of all written files are added as the last file of the tarball.
+		err = backup.WriteChecksums(tarWriter)
+		if err != nil {
+			err = fmt.Errorf("Error writing backup checksums: %w", err)
+		}
 	}
 
-	err = pool.BackupInstance(sourceInst, tarWriter, b.OptimizedStorage(), !b.InstanceOnly(), version, nil)
 	if err != nil {
-		return fmt.Errorf("Backup create: %w", err)
+		_ = tarPipeWriter.CloseWithError(err)
+		<-tarWriterRes
+		return err
 	}
 
 	// Close off the tarball file.
@@ -214,19 +489,91 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 		return fmt.Errorf("Error writing tarball: %w", err)
 	}
 
-	err = tarFileWriter.Close()
-	if err != nil {
-		return fmt.Errorf("Error closing tar file: %w", err)
+	// Write the last encrypted chunk.
+	if encWriter != nil {
+		err = encWriter.Close()
+		if err != nil {
+			return fmt.Errorf("Error encrypting tarball: %w", err)
+		}
 	}
 
-	revert.Success()
-	s.Events.SendLifecycle(sourceInst.Project().Name, lifecycle.InstanceBackupCreated.Event(args.Name, b.Instance(), nil))
-
 	return nil
 }
 
+// backupWriteInstanceTarball writes a backup tarball of the instance to w.
+// If parent is set, only the changes since the parent backup are written.
+func backupWriteInstanceTarball(w io.Writer, compress string, enc *backup.Encryption, sourceInst instance.Instance, pool storagePools.Pool, backupName string, optimized bool, snapshots bool, parent *backupParent, version uint32) error {
+	// Get IDMap to unshift container as the tarball is created.
+	var idmapSet *idmap.IdmapSet
+	if sourceInst.Type() == instancetype.Container {
+		c, ok := sourceInst.(instance.Container)
+		if !ok {
+			return errors.New("Invalid instance type")
+		}
+
+		var err error
+		idmapSet, err = c.DiskIdmap()
+		if err != nil {
+			return fmt.Errorf("Error getting container IDMAP: %w", err)
+		}
+	}
+
+	return backupWriteTarball(w, compress, enc, idmapSet, func(tarWriter *instancewriter.InstanceTarWriter) error {
+		// Write index file.
+		err := backupWriteIndex(sourceInst, pool, backupName, optimized, snapshots, parent, version, tarWriter)
+		if err != nil {
+			return fmt.Errorf("Error writing backup index file: %w", err)
+		}
+
+		// Incremental backups based on changed block tracking only hold the changed blocks of the root disk.
+		if parent != nil && parent.changedBlocks {
+			fi, err := os.Lstat(parent.changedBlocksPath)
+			if err != nil {
+				return err
+			}
+
+			err = tarWriter.WriteFile(backup.ChangedBlocksFile, parent.changedBlocksPath, fi, false)
+			if err != nil {
+				return fmt.Errorf("Failed writing changed blocks: %w", err)
+			}
+
+			return nil
+		}
+
+		base := ""
+		if parent != nil {
+			base = parent.base
+		}
+
+		err = pool.BackupInstance(sourceInst, tarWriter, optimized, snapshots, base, version, nil)
+		if err != nil {
+			return fmt.Errorf("Backup create: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// backupWriteVolumeTarball writes a backup tarball of the custom volume to w.
+func backupWriteVolumeTarball(w io.Writer, compress string, enc *backup.Encryption, projectName string, volumeName string, pool storagePools.Pool, optimized bool, snapshots bool, version uint32) error {
+	return backupWriteTarball(w, compress, enc, nil, func(tarWriter *instancewriter.InstanceTarWriter) error {
+		// Write index file.
+		err := volumeBackupWriteIndex(projectName, volumeName, pool, optimized, snapshots, version, tarWriter)
+		if err != nil {
+			return fmt.Errorf("Error writing backup index file: %w", err)
+		}
+
+		err = pool.BackupCustomVolume(projectName, volumeName, tarWriter, optimized, snapshots, nil)
+		if err != nil {
+			return fmt.Errorf("Backup create: %w", err)
+		}
+
+		return nil
+	})
+}
+
 // backupWriteIndex generates an index.yaml file and then writes it to the root of the backup tarball.
-func backupWriteIndex(sourceInst instance.Instance, pool storagePools.Pool, optimized bool, snapshots bool, version uint32, tarWriter *instancewriter.InstanceTarWriter) error {
+func backupWriteIndex(sourceInst instance.Instance, pool storagePools.Pool, backupName string, optimized bool, snapshots bool, parent *backupParent, version uint32, tarWriter *instancewriter.InstanceTarWriter) error {
 	// Indicate whether the driver will include a driver-specific optimized header.
 	poolDriverOptimizedHeader := false
 	if optimized {
@@ -260,16 +607,26 @@ func backupWriteIndex(sourceInst instance.Instance, pool storagePools.Pool, opti
 		return fmt.Errorf("Failed to convert backup config to version %d: %w", version, err)
 	}
 
+	_, backupName, _ = api.GetParentAndSnapshotName(backupName)
+
 	indexInfo := backup.Info{
 		Name:             sourceInst.Name(),
+		Backup:           backupName,
 		Pool:             pool.Name(),
 		Backend:          pool.Driver().Info().Name,
 		Type:             backupType,
 		OptimizedStorage: &optimized,
 		OptimizedHeader:  &poolDriverOptimizedHeader,
+		Checksums:        true,
 		Config:           config,
 	}
 
+	if parent != nil {
+		indexInfo.Parent = parent.name
+		indexInfo.Base = parent.base
+		indexInfo.ChangedBlocks = parent.changedBlocks
+	}
+
 	if snapshots {
 		indexInfo.Snapshots = make([]string, 0, len(config.Snapshots))
 		for _, s := range config.Snapshots {
@@ -387,7 +744,7 @@ func pruneExpiredInstanceBackups(ctx context.Context, s *state.State) error {
 	return nil
 }
 
-func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, projectName string, poolName string, volumeName string, version uint32) error {
+func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, projectName string, poolName string, volumeName string, version uint32, enc *backup.Encryption) error {
 	l := logger.AddContext(logger.Ctx{"project": projectName, "storage_volume": volumeName, "name": args.Name})
 	l.Debug("Volume backup started")
 	defer l.Debug("Volume backup finished")
@@ -466,75 +823,65 @@ func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, project
 	defer func() { _ = tarFileWriter.Close() }()
 	revert.Add(func() { _ = os.Remove(target) })
 
-	// Create the tarball.
-	tarPipeReader, tarPipeWriter := io.Pipe()
-	defer func() { _ = tarPipeWriter.Close() }() // Ensure that go routine below always ends.
-	tarWriter := instancewriter.NewInstanceTarWriter(tarPipeWriter, nil)
-
-	// Setup tar writer go routine, with optional compression.
-	tarWriterRes := make(chan error)
-	var compressErr error
-
-	go func(resCh chan<- error) {
-		l.Debug("Started backup tarball writer")
-		defer l.Debug("Finished backup tarball writer")
-		if compress != "none" {
-			compressErr = compressFile(compress, tarPipeReader, tarFileWriter)
+	err = backupWriteVolumeTarball(tarFileWriter, compress, enc, projectName, volumeName, pool, backupRow.OptimizedStorage, !backupRow.VolumeOnly, version)
+	if err != nil {
+		return err
+	}
 
-			// If a compression error occurred, close the tarPipeWriter to end the export.
-			if compressErr != nil {
-				_ = tarPipeWriter.Close()
-			}
-		} else {
-			_, err = io.Copy(tarFileWriter, tarPipeReader)
-		}
+	err = tarFileWriter.Close()
+	if err != nil {
+		return fmt.Errorf("Error closing tar file: %w", err)
+	}
 
-		resCh <- err
-	}(tarWriterRes)
+	revert.Success()
+	return nil
+}
 
-	// Write index file.
-	l.Debug("Adding backup index file")
-	err = volumeBackupWriteIndex(projectName, volumeName, pool, backupRow.OptimizedStorage, !backupRow.VolumeOnly, version, tarWriter)
+// volumeBackupCreateOnTarget streams a new custom volume backup to the backup target and returns the key of the created object.
+// Backups on a backup target are not recorded in the database.
+func volumeBackupCreateOnTarget(s *state.State, target *backup.Target, args db.StoragePoolVolumeBackup, projectName string, poolName string, volumeName string, version uint32, enc *backup.Encryption) (string, error) {
+	_, backupName, _ := api.GetParentAndSnapshotName(args.Name)
+	key := target.ObjectKey(projectName, "volumes", poolName, volumeName, backupName)
 
-	// Check compression errors.
-	if compressErr != nil {
-		return compressErr
-	}
+	l := logger.AddContext(logger.Ctx{"project": projectName, "storage_volume": volumeName, "target": target.Name, "key": key})
+	l.Debug("Volume backup to target started")
+	defer l.Debug("Volume backup to target finished")
 
-	// Check backupWriteIndex for errors.
+	pool, err := storagePools.LoadByName(s, poolName)
 	if err != nil {
-		return fmt.Errorf("Error writing backup index file: %w", err)
+		return "", fmt.Errorf("Failed loading storage pool %q: %w", poolName, err)
 	}
 
-	err = pool.BackupCustomVolume(projectName, volumeName, tarWriter, backupRow.OptimizedStorage, !backupRow.VolumeOnly, nil)
+	// Ignore requests for optimized backups when pool driver doesn't support it.
+	optimized := args.OptimizedStorage && pool.Driver().Info().OptimizedBackups
+
+	compress, err := backupCompressionAlgorithm(s, projectName, args.CompressionAlgorithm)
 	if err != nil {
-		return fmt.Errorf("Backup create: %w", err)
+		return "", err
 	}
 
-	// Close off the tarball file.
-	err = tarWriter.Close()
+	err = backupTargetCheckFree(s.ShutdownCtx, target, key)
 	if err != nil {
-		return fmt.Errorf("Error closing tarball writer: %w", err)
+		return "", err
 	}
 
-	// Close off the tarball pipe writer (this will end the go routine above).
-	err = tarPipeWriter.Close()
+	targetWriter, err := target.Upload(s.ShutdownCtx, key)
 	if err != nil {
-		return fmt.Errorf("Error closing tarball pipe writer: %w", err)
+		return "", err
 	}
 
-	err = <-tarWriterRes
+	err = backupWriteVolumeTarball(targetWriter, compress, enc, projectName, volumeName, pool, optimized, !args.VolumeOnly, version)
 	if err != nil {
-		return fmt.Errorf("Error writing tarball: %w", err)
+		targetWriter.Abort(err)
+		return "", err
 	}
 
-	err = tarFileWriter.Close()
+	err = targetWriter.Close()
 	if err != nil {
-		return fmt.Errorf("Error closing tar file: %w", err)
+		return "", err
 	}
 
-	revert.Success()
-	return nil
+	return key, nil
 }
 
 // volumeBackupWriteIndex generates an index.yaml file and then writes it to the root of the backup tarball.
@@ -567,6 +914,7 @@ func volumeBackupWriteIndex(projectName string, volumeName string, pool storageP
 		Backend:          pool.Driver().Info().Name,
 		OptimizedStorage: &optimized,
 		OptimizedHeader:  &poolDriverOptimizedHeader,
+		Checksums:        true,
 		Type:             backupConfig.TypeCustom,
 		Config:           config,
 	}
@@ -648,3 +996,348 @@ func pruneExpiredStorageVolumeBackups(ctx context.Context, s *state.State) error
 
 	return nil
 }
+
+func autoCreateAndPruneBackupsTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
+	// `f` creates new scheduled instance and custom volume backups and then prunes the oldest ones.
+	f := func(ctx context.Context) {
+		s := stateFunc()
+
+		var instances []instance.Instance
+		var volumes, remoteVolumes []db.StorageVolumeArgs
+		var memberCount int
+		var onlineMemberIDs []int64
+
+		// Get list of instances on the local member that are due to be backed up.
+		filter := dbCluster.InstanceFilter{Node: &s.ServerName}
+
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
+				err := limits.AllowBackupCreation(tx, p.Name)
+				if err != nil {
+					return nil
+				}
+
+				inst, err := instance.Load(s, dbInst, p)
+				if err != nil {
+					return fmt.Errorf("Failed loading instance %q (project %q) for backup task: %w", dbInst.Name, dbInst.Project, err)
+				}
+
+				// Check if instance has backup schedule enabled.
+				schedule := inst.ExpandedConfig()["backups.schedule"]
+				if schedule == "" {
+					return nil
+				}
+
+				// Check if backup is scheduled.
+				if !snapshotIsScheduledNow(schedule, int64(inst.ID())) {
+					return nil
+				}
+
+				logger.Debug("Scheduling auto instance backup", logger.Ctx{"instance": inst.Name(), "project": inst.Project().Name})
+				instances = append(instances, inst)
+
+				return nil
+			}, filter)
+		})
+		if err != nil {
+			logger.Error("Failed getting instance backup schedule info", logger.Ctx{"err": err})
+			return
+		}
+
+		// Get list of custom volumes that are due to be backed up.
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			allVolumes, err := tx.GetStoragePoolVolumesWithType(ctx, dbCluster.StoragePoolVolumeTypeCustom, true)
+			if err != nil {
+				return fmt.Errorf("Failed getting volumes for auto custom volume backup task: %w", err)
+			}
+
+			for _, v := range allVolumes {
+				schedule := v.Config["backups.schedule"]
+				if schedule == "" {
+					continue
+				}
+
+				// Check if backup is scheduled.
+				if !snapshotIsScheduledNow(schedule, v.ID) {
+					continue
+				}
+
+				err = limits.AllowBackupCreation(tx, v.ProjectName)
+				if err != nil {
+					continue
+				}
+
+				if v.NodeID < 0 {
+					// Keep a separate list of remote volumes in order to select a member to
+					// perform the backup later.
+					remoteVolumes = append(remoteVolumes, v)
+				} else {
+					logger.Debug("Scheduling local auto custom volume backup", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName})
+					volumes = append(volumes, v) // Always include local volumes.
+				}
+			}
+
+			if len(remoteVolumes) > 0 {
+				// Get list of cluster members.
+				members, err := tx.GetNodes(ctx)
+				if err != nil {
+					return fmt.Errorf("Failed getting cluster members: %w", err)
+				}
+
+				memberCount = len(members)
+
+				// Filter to online members.
+				for _, member := range members {
+					if member.IsOffline(s.GlobalConfig.OfflineThreshold()) {
+						continue
+					}
+
+					onlineMemberIDs = append(onlineMemberIDs, member.ID)
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			logger.Error("Failed getting custom volume backup schedule info", logger.Ctx{"err": err})
+			return
+		}
+
+		if len(remoteVolumes) > 0 {
+			// Skip backing up remote custom volumes if there are no online members, as we can't be
+			// sure that the cluster isn't partitioned and we may end up creating the backup on
+			// multiple members.
+			if memberCount > 1 && len(onlineMemberIDs) <= 0 {
+				logger.Error("Skipping remote volumes for auto custom volume backup task due to no online members")
+			} else {
+				localMemberID := s.DB.Cluster.GetNodeID()
+
+				for _, v := range remoteVolumes {
+					// If there are multiple cluster members, a stable random member is chosen
+					// to create the backup on. This spreads the load across the online cluster members.
+					if memberCount > 1 {
+						selectedMemberID, err := util.GetStableRandomInt64FromList(v.ID, onlineMemberIDs)
+						if err != nil {
+							logger.Error("Failed scheduling remote auto custom volume backup task", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName, "err": err})
+							continue
+						}
+
+						// Don't back up, if we're not the chosen one.
+						if localMemberID != selectedMemberID {
+							continue
+						}
+					}
+
+					logger.Debug("Scheduling remote auto custom volume backup", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName})
+					volumes = append(volumes, v)
+				}
+			}
+		}
+
+		// Handle instance backup auto creation.
+		if len(instances) > 0 {
+			opRun := func(op *operations.Operation) error {
+				return autoCreateAndPruneInstanceBackups(ctx, s, instances, op)
+			}
+
+			op, err := operations.OperationCreate(context.Background(), s, "", operations.OperationClassTask, operationtype.BackupCreate, nil, nil, opRun, nil, nil)
+			if err != nil {
+				logger.Error("Failed creating scheduled instance backup operation", logger.Ctx{"err": err})
+			} else {
+				logger.Info("Creating scheduled instance backups")
+
+				err = op.Start()
+				if err != nil {
+					logger.Error("Failed starting scheduled instance backup operation", logger.Ctx{"err": err})
+				} else {
+					err = op.Wait(ctx)
+					if err != nil {
+						logger.Error("Failed scheduled instance backups", logger.Ctx{"err": err})
+					} else {
+						logger.Info("Done creating scheduled instance backups")
+					}
+				}
+			}
+		}
+
+		// Handle custom volume backup auto creation.
+		if len(volumes) > 0 {
+			opRun := func(op *operations.Operation) error {
+				return autoCreateAndPruneCustomVolumeBackups(ctx, s, volumes)
+			}
+
+			op, err := operations.OperationCreate(context.Background(), s, "", operations.OperationClassTask, operationtype.CustomVolumeBackupCreate, nil, nil, opRun, nil, nil)
+			if err != nil {
+				logger.Error("Failed creating scheduled volume backup operation", logger.Ctx{"err": err})
+			} else {
+				logger.Info("Creating scheduled volume backups")
+
+				err = op.Start()
+				if err != nil {
+					logger.Error("Failed starting scheduled volume backup operation", logger.Ctx{"err": err})
+				} else {
+					err = op.Wait(ctx)
+					if err != nil {
+						logger.Error("Failed scheduled custom volume backups", logger.Ctx{"err": err})
+					} else {
+						logger.Info("Done creating scheduled volume backups")
+					}
+				}
+			}
+		}
+	}
+
+	first := true
+	schedule := func() (time.Duration, error) {
+		interval := time.Minute
+
+		if first {
+			first = false
+			return interval, task.ErrSkip
+		}
+
+		return interval, nil
+	}
+
+	return f, schedule
+}
+
+// autoCreateAndPruneInstanceBackups creates a scheduled backup of each of the given instances and then deletes
+// their oldest scheduled backups exceeding `backups.retain`.
+func autoCreateAndPruneInstanceBackups(ctx context.Context, s *state.State, instances []instance.Instance, op *operations.Operation) error {
+	for _, inst := range instances {
+		err := ctx.Err()
+		if err != nil {
+			return err // Stop if context is cancelled.
+		}
+
+		config := inst.ExpandedConfig()
+
+		backups, err := inst.Backups()
+		if err != nil {
+			return fmt.Errorf("Failed loading backups of instance %q (project %q): %w", inst.Name(), inst.Project().Name, err)
+		}
+
+		backupNames := make([]string, 0, len(backups)+1)
+		for _, b := range backups {
+			backupNames = append(backupNames, b.Name())
+		}
+
+		now := time.Now()
+		expiry, err := shared.GetExpiry(now, config["backups.expiry"])
+		if err != nil {
+			return fmt.Errorf("Invalid backup expiry for instance %q (project %q): %w", inst.Name(), inst.Project().Name, err)
+		}
+
+		args := db.InstanceBackup{
+			Name:             inst.Name() + shared.SnapshotDelimiter + backup.NextBackupName(inst.Name(), backup.ScheduledBackupPrefix, backupNames),
+			InstanceID:       inst.ID(),
+			CreationDate:     now,
+			ExpiryDate:       expiry,
+			OptimizedStorage: shared.IsTrue(config["backups.optimized"]),
+		}
+
+		err = backupCreate(s, args, inst, backupConfig.DefaultMetadataVersion, nil, nil, op)
+		if err != nil {
+			return fmt.Errorf("Failed creating scheduled backup of instance %q (project %q): %w", inst.Name(), inst.Project().Name, err)
+		}
+
+		if config["backups.retain"] == "" {
+			continue
+		}
+
+		retain, err := strconv.Atoi(config["backups.retain"])
+		if err != nil {
+			return fmt.Errorf("Invalid backup retention for instance %q (project %q): %w", inst.Name(), inst.Project().Name, err)
+		}
+
+		prune := backup.ScheduledBackupsToPrune(inst.Name(), append(backupNames, args.Name), retain)
+		for _, b := range backups {
+			if !slices.Contains(prune, b.Name()) {
+				continue
+			}
+
+			err = b.Delete()
+			if err != nil {
+				return fmt.Errorf("Error deleting instance backup %q: %w", b.Name(), err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// autoCreateAndPruneCustomVolumeBackups creates a scheduled backup of each of the given custom volumes and then
+// deletes their oldest scheduled backups exceeding `backups.retain`.
+func autoCreateAndPruneCustomVolumeBackups(ctx context.Context, s *state.State, volumes []db.StorageVolumeArgs) error {
+	for _, v := range volumes {
+		err := ctx.Err()
+		if err != nil {
+			return err // Stop if context is cancelled.
+		}
+
+		pool, err := storagePools.LoadByName(s, v.PoolName)
+		if err != nil {
+			return fmt.Errorf("Error loading pool for volume %q (project %q, pool %q): %w", v.Name, v.ProjectName, v.PoolName, err)
+		}
+
+		var backups []db.StoragePoolVolumeBackup
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			backups, err = tx.GetStoragePoolVolumeBackups(ctx, v.ProjectName, v.Name, pool.ID())
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("Failed loading backups of volume %q (project %q, pool %q): %w", v.Name, v.ProjectName, v.PoolName, err)
+		}
+
+		backupNames := make([]string, 0, len(backups)+1)
+		for _, b := range backups {
+			backupNames = append(backupNames, b.Name)
+		}
+
+		now := time.Now()
+		expiry, err := shared.GetExpiry(now, v.Config["backups.expiry"])
+		if err != nil {
+			return fmt.Errorf("Invalid backup expiry for volume %q (project %q, pool %q): %w", v.Name, v.ProjectName, v.PoolName, err)
+		}
+
+		args := db.StoragePoolVolumeBackup{
+			Name:             v.Name + shared.SnapshotDelimiter + backup.NextBackupName(v.Name, backup.ScheduledBackupPrefix, backupNames),
+			VolumeID:         v.ID,
+			CreationDate:     now,
+			ExpiryDate:       expiry,
+			OptimizedStorage: shared.IsTrue(v.Config["backups.optimized"]),
+		}
+
+		err = volumeBackupCreate(s, args, v.ProjectName, v.PoolName, v.Name, backupConfig.DefaultMetadataVersion, nil)
+		if err != nil {
+			return fmt.Errorf("Failed creating scheduled backup of volume %q (project %q, pool %q): %w", v.Name, v.ProjectName, v.PoolName, err)
+		}
+
+		s.Events.SendLifecycle(v.ProjectName, lifecycle.StorageVolumeBackupCreated.Event(v.PoolName, dbCluster.StoragePoolVolumeTypeNameCustom, args.Name, v.ProjectName, nil, logger.Ctx{"type": dbCluster.StoragePoolVolumeTypeNameCustom}))
+
+		if v.Config["backups.retain"] == "" {
+			continue
+		}
+
+		retain, err := strconv.Atoi(v.Config["backups.retain"])
+		if err != nil {
+			return fmt.Errorf("Invalid backup retention for volume %q (project %q, pool %q): %w", v.Name, v.ProjectName, v.PoolName, err)
+		}
+
+		prune := backup.ScheduledBackupsToPrune(v.Name, append(backupNames, args.Name), retain)
+		for _, b := range backups {
+			if !slices.Contains(prune, b.Name) {
+				continue
+			}
+
+			volBackup := backup.NewVolumeBackup(s, v.ProjectName, v.PoolName, v.Name, b.ID, b.Name, b.CreationDate, b.ExpiryDate, b.VolumeOnly, b.OptimizedStorage)
+			err = volBackup.Delete()
+			if err != nil {
+				return fmt.Errorf("Error deleting storage volume backup %q: %w", b.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
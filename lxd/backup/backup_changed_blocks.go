@@ -0,0 +1,82 @@
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ChangedBlocksFile is the path of the image holding the changed blocks of a VM's root disk in the tarball of an
+// incremental backup based on changed block tracking.
+const ChangedBlocksFile = "backup/virtual-machine-changed-blocks.qcow2"
+
+// changedBlocksZeroBufferSize is the size of the buffer used to write zeroed extents.
+const changedBlocksZeroBufferSize = 1024 * 1024
+
+// ChangedBlocksExtent represents an extent of a changed blocks image as reported by "qemu-img map --output=json".
+type ChangedBlocksExtent struct {
+	Start   int64  `json:"start"`
+	Length  int64  `json:"length"`
+	Data    bool   `json:"data"`
+	Zero    bool   `json:"zero"`
+	Present *bool  `json:"present,omitempty"`
+	Offset  *int64 `json:"offset,omitempty"`
+}
+
+// ParseChangedBlocksMap parses the output of "qemu-img map --output=json" for a changed blocks image and returns
+// the extents that hold changed blocks. Extents that aren't allocated in the image are left out.
+func ParseChangedBlocksMap(data []byte) ([]ChangedBlocksExtent, error) {
+	var extents []ChangedBlocksExtent
+
+	err := json.Unmarshal(data, &extents)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing changed blocks map: %w", err)
+	}
+
+	changed := make([]ChangedBlocksExtent, 0, len(extents))
+	for _, extent := range extents {
+		if extent.Data {
+			if extent.Offset == nil {
+				return nil, fmt.Errorf("Changed blocks extent at %d has no offset in the image", extent.Start)
+			}
+
+			changed = append(changed, extent)
+		} else if extent.Zero && extent.Present != nil && *extent.Present {
+			// Zeroed blocks are only changes if they are allocated in the image.
+			changed = append(changed, extent)
+		}
+	}
+
+	return changed, nil
+}
+
+// ApplyChangedBlocks writes the changed extents read from the changed blocks image in src to dst.
+func ApplyChangedBlocks(extents []ChangedBlocksExtent, src io.ReaderAt, dst io.WriterAt) error {
+	var zeroes []byte
+
+	for _, extent := range extents {
+		if extent.Data {
+			_, err := io.Copy(io.NewOffsetWriter(dst, extent.Start), io.NewSectionReader(src, *extent.Offset, extent.Length))
+			if err != nil {
+				return fmt.Errorf("Failed writing changed blocks at %d: %w", extent.Start, err)
+			}
+
+			continue
+		}
+
+		if zeroes == nil {
+			zeroes = make([]byte, changedBlocksZeroBufferSize)
+		}
+
+		for pos := int64(0); pos < extent.Length; pos += int64(len(zeroes)) {
+			size := min(int64(len(zeroes)), extent.Length-pos)
+
+			_, err := dst.WriteAt(zeroes[:size], extent.Start+pos)
+			if err != nil {
+				return fmt.Errorf("Failed zeroing changed blocks at %d: %w", extent.Start+pos, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package backup
+
+import (
+	"bytes"
+	"testing"
+)
+
+// testWriterAt is an in-memory io.WriterAt.
+type testWriterAt struct {
+	buf []byte
+}
+
+func (w *testWriterAt) WriteAt(p []byte, off int64) (int, error) {
+	copy(w.buf[off:], p)
+	return len(p), nil
+}
+
+func TestParseChangedBlocksMap(t *testing.T) {
+	data := []byte(`[
+{ "start": 0, "length": 65536, "depth": 0, "present": false, "zero": true, "data": false, "compressed": false},
+{ "start": 65536, "length": 131072, "depth": 0, "present": true, "zero": false, "data": true, "compressed": false, "offset": 327680},
+{ "start": 196608, "length": 65536, "depth": 0, "present": true, "zero": true, "data": false, "compressed": false},
+{ "start": 262144, "length": 65536, "depth": 0, "zero": true, "data": false}
+]`)
+
+	extents, err := ParseChangedBlocksMap(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(extents) != 2 {
+		t.Fatalf("Expected 2 changed extents, got %d", len(extents))
+	}
+
+	if extents[0].Start != 65536 || extents[0].Length != 131072 || !extents[0].Data || *extents[0].Offset != 327680 {
+		t.Errorf("Unexpected data extent: %+v", extents[0])
+	}
+
+	if extents[1].Start != 196608 || extents[1].Length != 65536 || extents[1].Data {
+		t.Errorf("Unexpected zero extent: %+v", extents[1])
+	}
+
+	_, err = ParseChangedBlocksMap([]byte(`[{"start": 0, "length": 512, "data": true, "zero": false}]`))
+	if err == nil {
+		t.Error("Expected error for data extent without offset")
+	}
+
+	_, err = ParseChangedBlocksMap([]byte(`invalid`))
+	if err == nil {
+		t.Error("Expected error for invalid map")
+	}
+}
+
+func TestApplyChangedBlocks(t *testing.T) {
+	src := bytes.Repeat([]byte("s"), 16)
+	dst := &testWriterAt{buf: bytes.Repeat([]byte("d"), 16)}
+
+	offset := int64(4)
+	extents := []ChangedBlocksExtent{
+		{Start: 2, Length: 3, Data: true, Offset: &offset},
+		{Start: 10, Length: 4, Zero: true},
+	}
+
+	err := ApplyChangedBlocks(extents, bytes.NewReader(src), dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []byte("ddsssddddd\x00\x00\x00\x00dd")
+	if !bytes.Equal(dst.buf, expected) {
+		t.Errorf("Expected %q, got %q", expected, dst.buf)
+	}
+}
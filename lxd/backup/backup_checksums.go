@@ -0,0 +1,178 @@
+package backup
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v2"
+
+	"github.com/canonical/lxd/lxd/instancewriter"
+	"github.com/canonical/lxd/lxd/state"
+)
+
+// backupChecksumsPath is the path of the file holding the checksums of all other files in the backup tarball.
+// It is always the last file of the tarball.
+const backupChecksumsPath = "backup/checksums"
+
+// WriteChecksums adds the checksums of all files written to the backup tarball so far as its last file.
+// The file uses the same format as the output of sha256sum.
+func WriteChecksums(tarWriter *instancewriter.InstanceTarWriter) error {
+	var buf bytes.Buffer
+	for _, checksum := range tarWriter.Checksums() {
+		_, err := fmt.Fprintf(&buf, "%s  %s\n", checksum.SHA256, checksum.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	fileInfo := instancewriter.FileInfo{
+		FileName:    backupChecksumsPath,
+		FileSize:    int64(buf.Len()),
+		FileMode:    0644,
+		FileModTime: time.Now(),
+	}
+
+	return tarWriter.WriteFileFromReader(&buf, &fileInfo)
+}
+
+// parseChecksums parses the content of a checksums file into a map of file names to checksums.
+func parseChecksums(r io.Reader) (map[string]string, error) {
+	checksums := map[string]string{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		checksum, name, found := strings.Cut(line, "  ")
+		if !found || len(checksum) != sha256.Size*2 || name == "" {
+			return nil, fmt.Errorf("Invalid checksum line %q", line)
+		}
+
+		checksums[name] = checksum
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return checksums, nil
+}
+
+// verifyTarball reads all files of the tarball and checks them against the checksums file.
+// It returns false for backups whose index doesn't announce a checksums file.
+func verifyTarball(tr *tar.Reader) (bool, error) {
+	var index *Info
+	var expected map[string]string
+	actual := map[string]string{}
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break // End of archive.
+		}
+
+		if err != nil {
+			return false, fmt.Errorf("Failed reading backup: %w", err)
+		}
+
+		if expected != nil {
+			return false, fmt.Errorf("Unexpected file %q after checksums", hdr.Name)
+		}
+
+		if hdr.Name == backupChecksumsPath {
+			expected, err = parseChecksums(tr)
+			if err != nil {
+				return false, fmt.Errorf("Failed reading backup checksums: %w", err)
+			}
+
+			continue
+		}
+
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		h := sha256.New()
+		var w io.Writer = h
+
+		// Keep the index content to find out whether the backup has checksums.
+		var indexData bytes.Buffer
+		if hdr.Name == backupIndexPath {
+			w = io.MultiWriter(h, &indexData)
+		}
+
+		_, err = io.Copy(w, tr)
+		if err != nil {
+			return false, fmt.Errorf("Failed reading %q from backup: %w", hdr.Name, err)
+		}
+
+		actual[hdr.Name] = hex.EncodeToString(h.Sum(nil))
+
+		if hdr.Name == backupIndexPath {
+			index = &Info{}
+			err = yaml.Unmarshal(indexData.Bytes(), index)
+			if err != nil {
+				return false, fmt.Errorf("Failed parsing backup index: %w", err)
+			}
+		}
+	}
+
+	if index == nil {
+		return false, errors.New("Backup index is missing")
+	}
+
+	if expected == nil {
+		// A tarball truncated right before its checksums file is otherwise indistinguishable from a backup
+		// created before checksums were introduced, which can only be checked for readability.
+		if index.Checksums {
+			return false, errors.New("Backup checksums are missing")
+		}
+
+		return false, nil
+	}
+
+	for name, checksum := range actual {
+		expectedChecksum, found := expected[name]
+		if !found {
+			return false, fmt.Errorf("No checksum for %q in backup", name)
+		}
+
+		if checksum != expectedChecksum {
+			return false, fmt.Errorf("Checksum mismatch for %q in backup", name)
+		}
+	}
+
+	for name := range expected {
+		_, found := actual[name]
+		if !found {
+			return false, fmt.Errorf("File %q is missing from backup", name)
+		}
+	}
+
+	return true, nil
+}
+
+// Verify reads the whole backup tarball to detect truncation and checks the checksums of its files.
+// It returns whether the checksums were verified, which is not the case for backups created without them.
+func Verify(s *state.State, r io.ReadSeeker, outputPath string) (bool, error) {
+	tr, cancelFunc, err := TarReader(s, r, outputPath)
+	if err != nil {
+		return false, err
+	}
+
+	defer cancelFunc()
+
+	return verifyTarball(tr)
+}
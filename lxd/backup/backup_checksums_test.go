@@ -0,0 +1,149 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/canonical/lxd/lxd/instancewriter"
+)
+
+// writeTestTarball writes the given files and optionally their checksums to a tarball.
+func writeTestTarball(t *testing.T, files map[string]string, checksums bool) []byte {
+	var buf bytes.Buffer
+	tarWriter := instancewriter.NewInstanceTarWriter(&buf, nil)
+	if checksums {
+		tarWriter.EnableChecksums()
+	}
+
+	for name, content := range files {
+		fileInfo := instancewriter.FileInfo{
+			FileName:    name,
+			FileSize:    int64(len(content)),
+			FileMode:    0644,
+			FileModTime: time.Now(),
+		}
+
+		err := tarWriter.WriteFileFromReader(strings.NewReader(content), &fileInfo)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if checksums {
+		err := WriteChecksums(tarWriter)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := tarWriter.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestVerifyTarball(t *testing.T) {
+	files := map[string]string{
+		"backup/index.yaml":    "name: c1\nchecksums: true\n",
+		"backup/container.bin": strings.Repeat("data", 1024),
+	}
+
+	legacyFiles := map[string]string{
+		"backup/index.yaml":    "name: c1\n",
+		"backup/container.bin": strings.Repeat("data", 1024),
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		data := writeTestTarball(t, files, true)
+		verified, err := verifyTarball(tar.NewReader(bytes.NewReader(data)))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if !verified {
+			t.Error("Expected backup to be verified")
+		}
+	})
+
+	t.Run("Without checksums", func(t *testing.T) {
+		data := writeTestTarball(t, legacyFiles, false)
+		verified, err := verifyTarball(tar.NewReader(bytes.NewReader(data)))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if verified {
+			t.Error("Expected backup without checksums not to be verified")
+		}
+	})
+
+	t.Run("Missing checksums", func(t *testing.T) {
+		data := writeTestTarball(t, files, false)
+		_, err := verifyTarball(tar.NewReader(bytes.NewReader(data)))
+		if err == nil {
+			t.Error("Expected error for backup missing its announced checksums")
+		}
+	})
+
+	t.Run("Truncated before checksums", func(t *testing.T) {
+		data := writeTestTarball(t, files, true)
+		idx := bytes.Index(data, []byte(backupChecksumsPath))
+
+		_, err := verifyTarball(tar.NewReader(bytes.NewReader(data[:idx])))
+		if err == nil {
+			t.Error("Expected error for backup truncated before its checksums")
+		}
+	})
+
+	t.Run("Missing index", func(t *testing.T) {
+		data := writeTestTarball(t, map[string]string{"backup/container.bin": "data"}, false)
+		_, err := verifyTarball(tar.NewReader(bytes.NewReader(data)))
+		if err == nil {
+			t.Error("Expected error for backup without index")
+		}
+	})
+
+	t.Run("Corrupted", func(t *testing.T) {
+		data := writeTestTarball(t, files, true)
+		idx := bytes.Index(data, []byte("datadata"))
+		data[idx] = 'X'
+
+		_, err := verifyTarball(tar.NewReader(bytes.NewReader(data)))
+		if err == nil {
+			t.Error("Expected error for corrupted backup")
+		}
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		data := writeTestTarball(t, files, true)
+		idx := bytes.Index(data, []byte("datadata"))
+
+		_, err := verifyTarball(tar.NewReader(bytes.NewReader(data[:idx+100])))
+		if err == nil {
+			t.Error("Expected error for truncated backup")
+		}
+	})
+}
+
+func TestParseChecksums(t *testing.T) {
+	checksum := strings.Repeat("a", 64)
+
+	checksums, err := parseChecksums(strings.NewReader(checksum + "  backup/index.yaml\n\n" + checksum + "  backup/file with spaces\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(checksums) != 2 || checksums["backup/file with spaces"] != checksum {
+		t.Errorf("Unexpected checksums: %v", checksums)
+	}
+
+	_, err = parseChecksums(strings.NewReader("abc  backup/index.yaml\n"))
+	if err == nil {
+		t.Error("Expected error for invalid checksum")
+	}
+}
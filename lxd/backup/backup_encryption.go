@@ -0,0 +1,184 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"filippo.io/age"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// Encrypted backups use the age file format (https://age-encryption.org/v1), so they can also be
+// decrypted with the age command line tool.
+// A backup is either encrypted for one or more X25519 recipients or for a passphrase, as the age
+// format doesn't allow combining a passphrase with other recipients.
+const encryptionMagic = "age-encryption.org/v1\n"
+
+// Encryption holds the keys a new backup is encrypted for.
+type Encryption struct {
+	Recipients []*age.X25519Recipient
+	Passphrase string
+}
+
+// Decryption holds the keys used to decrypt a backup.
+type Decryption struct {
+	Identities []age.Identity
+	Passphrase string
+}
+
+// ParseRecipients parses age X25519 recipients, as printed by `age-keygen -y`.
+// Every entry can hold several recipients, one per line.
+func ParseRecipients(recipients []string) ([]*age.X25519Recipient, error) {
+	keys := make([]*age.X25519Recipient, 0, len(recipients))
+	for _, recipient := range recipients {
+		parsed, err := age.ParseRecipients(strings.NewReader(recipient))
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing recipient: %w", err)
+		}
+
+		for _, r := range parsed {
+			key, ok := r.(*age.X25519Recipient)
+			if !ok {
+				return nil, errors.New("Recipient must be an age X25519 public key")
+			}
+
+			keys = append(keys, key)
+		}
+	}
+
+	return keys, nil
+}
+
+// ParseIdentities parses all age X25519 identities in data, as written by `age-keygen`.
+func ParseIdentities(data []byte) ([]age.Identity, error) {
+	identities, err := age.ParseIdentities(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing identity: %w", err)
+	}
+
+	return identities, nil
+}
+
+// IsEncrypted returns whether the backup read from r is encrypted.
+// The reader is positioned at the start of the backup afterwards.
+func IsEncrypted(r io.ReadSeeker) (bool, error) {
+	_, err := r.Seek(0, io.SeekStart)
+	if err != nil {
+		return false, err
+	}
+
+	magic := make([]byte, len(encryptionMagic))
+	_, err = io.ReadFull(r, magic)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return false, err
+	}
+
+	_, err = r.Seek(0, io.SeekStart)
+	if err != nil {
+		return false, err
+	}
+
+	return string(magic) == encryptionMagic, nil
+}
+
+// NewEncryptingWriter returns a writer that encrypts all data written to it for the given keys.
+// The writer must be closed to write the last chunk of the payload.
+func NewEncryptingWriter(w io.Writer, enc *Encryption) (io.WriteCloser, error) {
+	if len(enc.Recipients) == 0 && enc.Passphrase == "" {
+		return nil, errors.New("At least one recipient or a passphrase is required for encryption")
+	}
+
+	if len(enc.Recipients) > 0 && enc.Passphrase != "" {
+		return nil, errors.New("A backup can't be encrypted for both recipients and a passphrase")
+	}
+
+	recipients := make([]age.Recipient, 0, len(enc.Recipients))
+	for _, recipient := range enc.Recipients {
+		recipients = append(recipients, recipient)
+	}
+
+	if enc.Passphrase != "" {
+		recipient, err := age.NewScryptRecipient(enc.Passphrase)
+		if err != nil {
+			return nil, err
+		}
+
+		recipients = append(recipients, recipient)
+	}
+
+	return age.Encrypt(w, recipients...)
+}
+
+// decryptingReader turns errors of the age payload into bad request errors.
+type decryptingReader struct {
+	r io.Reader
+}
+
+// Read returns the decrypted payload.
+func (d *decryptingReader) Read(p []byte) (int, error) {
+	n, err := d.r.Read(p)
+	if err != nil && err != io.EOF {
+		return n, api.StatusErrorf(http.StatusBadRequest, "Failed decrypting backup, it is either corrupted or truncated")
+	}
+
+	return n, err
+}
+
+// NewDecryptingReader returns a reader that decrypts the encrypted backup read from r.
+// A backup that can't be decrypted with the given keys results in a bad request error.
+func NewDecryptingReader(r io.Reader, dec *Decryption) (io.Reader, error) {
+	identities := make([]age.Identity, 0, len(dec.Identities)+1)
+	identities = append(identities, dec.Identities...)
+
+	if dec.Passphrase != "" {
+		identity, err := age.NewScryptIdentity(dec.Passphrase)
+		if err != nil {
+			return nil, err
+		}
+
+		identities = append(identities, identity)
+	}
+
+	if len(identities) == 0 {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Backup is encrypted, an identity or passphrase is required")
+	}
+
+	plain, err := age.Decrypt(r, identities...)
+	if err != nil {
+		var noMatch *age.NoIdentityMatchError
+		if errors.As(err, &noMatch) {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Backup is encrypted and none of the given identities or passphrase can decrypt it")
+		}
+
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Failed decrypting backup: %v", err)
+	}
+
+	return &decryptingReader{r: plain}, nil
+}
+
+// DecryptReader returns a reader for the backup read from r, decrypting it if needed.
+// If the backup is encrypted but no keys are given, a bad request error is returned.
+func DecryptReader(r io.Reader, dec *Decryption) (io.Reader, error) {
+	br := bufio.NewReader(r)
+
+	magic, err := br.Peek(len(encryptionMagic))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	if string(magic) != encryptionMagic {
+		return br, nil
+	}
+
+	if dec == nil {
+		dec = &Decryption{}
+	}
+
+	return NewDecryptingReader(br, dec)
+}
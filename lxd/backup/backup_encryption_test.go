@@ -0,0 +1,227 @@
+package backup
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"testing"
+
+	"filippo.io/age"
+)
+
+// encryptionChunkSize is the size of the payload chunks of the age format.
+const encryptionChunkSize = 64 * 1024
+
+// generateIdentity returns a new age X25519 identity and its recipient.
+func generateIdentity(t *testing.T) (string, string) {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return identity.String(), identity.Recipient().String()
+}
+
+// encrypt encrypts data for the given recipients and passphrase.
+func encrypt(t *testing.T, data []byte, recipients []string, passphrase string) []byte {
+	keys, err := ParseRecipients(recipients)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	w, err := NewEncryptingWriter(&buf, &Encryption{Recipients: keys, Passphrase: passphrase})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = w.Write(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+// decrypt decrypts data using the given identity and passphrase.
+func decrypt(t *testing.T, data []byte, identity string, passphrase string) ([]byte, error) {
+	dec := &Decryption{Passphrase: passphrase}
+	if identity != "" {
+		keys, err := ParseIdentities([]byte(identity))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		dec.Identities = keys
+	}
+
+	r, err := DecryptReader(bytes.NewReader(data), dec)
+	if err != nil {
+		return nil, err
+	}
+
+	return io.ReadAll(r)
+}
+
+func TestEncryption(t *testing.T) {
+	identity1, recipient1 := generateIdentity(t)
+	identity2, recipient2 := generateIdentity(t)
+
+	sizes := []int{0, 1, encryptionChunkSize - 1, encryptionChunkSize, encryptionChunkSize + 1, 3 * encryptionChunkSize}
+	for _, size := range sizes {
+		data := make([]byte, size)
+		_, _ = rand.Read(data)
+
+		encrypted := encrypt(t, data, []string{recipient1, recipient2}, "")
+
+		isEncrypted, err := IsEncrypted(bytes.NewReader(encrypted))
+		if err != nil || !isEncrypted {
+			t.Fatalf("Size %d: expected encrypted backup to be detected (err: %v)", size, err)
+		}
+
+		for _, identity := range []string{identity1, identity2} {
+			decrypted, err := decrypt(t, encrypted, identity, "")
+			if err != nil {
+				t.Fatalf("Size %d: unexpected error: %v", size, err)
+			}
+
+			if !bytes.Equal(decrypted, data) {
+				t.Fatalf("Size %d: decrypted data doesn't match", size)
+			}
+		}
+	}
+}
+
+func TestEncryptionPassphrase(t *testing.T) {
+	data := make([]byte, encryptionChunkSize+1)
+	_, _ = rand.Read(data)
+
+	encrypted := encrypt(t, data, nil, "secret")
+
+	decrypted, err := decrypt(t, encrypted, "", "secret")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !bytes.Equal(decrypted, data) {
+		t.Fatal("Decrypted data doesn't match")
+	}
+
+	_, err = decrypt(t, encrypted, "", "wrong")
+	if err == nil {
+		t.Fatal("Expected an error with the wrong passphrase")
+	}
+
+	_, recipient := generateIdentity(t)
+	keys, err := ParseRecipients([]string{recipient})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = NewEncryptingWriter(io.Discard, &Encryption{Recipients: keys, Passphrase: "secret"})
+	if err == nil {
+		t.Fatal("Expected an error when combining recipients and a passphrase")
+	}
+}
+
+func TestEncryptionAgeCompatibility(t *testing.T) {
+	identityStr, recipient := generateIdentity(t)
+	data := []byte("backup content")
+
+	// Backups encrypted by LXD can be decrypted by age.
+	encrypted := encrypt(t, data, []string{recipient}, "")
+	identity, err := age.ParseX25519Identity(identityStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := age.Decrypt(bytes.NewReader(encrypted), identity)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	decrypted, err := io.ReadAll(r)
+	if err != nil || !bytes.Equal(decrypted, data) {
+		t.Fatalf("Decrypted data doesn't match (err: %v)", err)
+	}
+
+	// Files encrypted by age can be restored by LXD, using an identity file with comments.
+	var buf bytes.Buffer
+	w, err := age.Encrypt(&buf, identity.Recipient())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = w.Write(data)
+	_ = w.Close()
+
+	decrypted, err = decrypt(t, buf.Bytes(), "# created: 2026-10-18T00:00:00Z\n# public key: "+recipient+"\n"+identityStr+"\n", "")
+	if err != nil || !bytes.Equal(decrypted, data) {
+		t.Fatalf("Decrypted data doesn't match (err: %v)", err)
+	}
+}
+
+func TestDecryptionFailures(t *testing.T) {
+	identity, recipient := generateIdentity(t)
+	otherIdentity, _ := generateIdentity(t)
+
+	data := make([]byte, 2*encryptionChunkSize+100)
+	_, _ = rand.Read(data)
+	encrypted := encrypt(t, data, []string{recipient}, "")
+
+	t.Run("Wrong identity", func(t *testing.T) {
+		_, err := decrypt(t, encrypted, otherIdentity, "")
+		if err == nil {
+			t.Fatal("Expected an error")
+		}
+	})
+
+	t.Run("Wrong passphrase", func(t *testing.T) {
+		_, err := decrypt(t, encrypted, "", "wrong")
+		if err == nil {
+			t.Fatal("Expected an error")
+		}
+	})
+
+	t.Run("No keys", func(t *testing.T) {
+		_, err := DecryptReader(bytes.NewReader(encrypted), nil)
+		if err == nil {
+			t.Fatal("Expected an error")
+		}
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		for _, size := range []int{len(encrypted) - 1, len(encrypted) - 100, len(encrypted) - encryptionChunkSize - 116} {
+			_, err := decrypt(t, encrypted[:size], identity, "")
+			if err == nil {
+				t.Fatalf("Expected an error when truncated to %d bytes", size)
+			}
+		}
+	})
+
+	t.Run("Corrupted", func(t *testing.T) {
+		corrupted := bytes.Clone(encrypted)
+		corrupted[len(corrupted)/2] ^= 0x01
+
+		_, err := decrypt(t, corrupted, identity, "")
+		if err == nil {
+			t.Fatal("Expected an error")
+		}
+	})
+
+	t.Run("Not encrypted", func(t *testing.T) {
+		decrypted, err := decrypt(t, data, "", "")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if !bytes.Equal(decrypted, data) {
+			t.Fatal("Unencrypted data doesn't match")
+		}
+	})
+}
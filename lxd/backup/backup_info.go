@@ -1,8 +1,10 @@
 package backup
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"slices"
 
 	"go.yaml.in/yaml/v2"
 
@@ -36,6 +38,158 @@ type Info struct {
 	OptimizedHeader  *bool          `json:"optimized_header,omitempty" yaml:"optimized_header,omitempty"` // Optional field to handle older optimized backups that don't have this field.
 	Type             config.Type    `json:"type,omitempty" yaml:"type,omitempty"`                         // Type of backup.
 	Config           *config.Config `json:"config,omitempty" yaml:"config,omitempty"`                     // Equivalent of backup.yaml but embedded in index for quick retrieval.
+	Backup           string         `json:"backup,omitempty" yaml:"backup,omitempty"`                     // Name of the backup itself.
+	Parent           string         `json:"parent,omitempty" yaml:"parent,omitempty"`                     // Name of the parent backup for incremental backups.
+	Base             string         `json:"base,omitempty" yaml:"base,omitempty"`                         // Snapshot of the parent backup that the incremental backup starts from.
+	ChangedBlocks    bool           `json:"changed_blocks,omitempty" yaml:"changed_blocks,omitempty"`     // Whether the incremental backup only holds the changed blocks of a VM's root disk.
+	Checksums        bool           `json:"checksums,omitempty" yaml:"checksums,omitempty"`               // Whether the backup ends with the checksums of its files.
+	Parents          []ParentBackup `json:"-" yaml:"-"`                                                   // Parents is set during import to the chain of parent backups, oldest first.
+}
+
+// ToAPI returns the API representation of the backup information.
+func (b *Info) ToAPI(verified bool) *api.BackupInfo {
+	info := &api.BackupInfo{
+		Name:      b.Name,
+		Backup:    b.Backup,
+		Type:      string(b.Type),
+		Backend:   b.Backend,
+		Pool:      b.Pool,
+		Snapshots: b.Snapshots,
+		Parent:    b.Parent,
+		Volumes:   []api.StorageVolume{},
+		Verified:  verified,
+	}
+
+	if b.OptimizedStorage != nil {
+		info.OptimizedStorage = *b.OptimizedStorage
+	}
+
+	if info.Snapshots == nil {
+		info.Snapshots = []string{}
+	}
+
+	if b.Config != nil {
+		info.Instance = b.Config.Instance
+		for _, vol := range b.Config.Volumes {
+			info.Volumes = append(info.Volumes, vol.StorageVolume)
+		}
+	}
+
+	return info
+}
+
+// ParentBackup represents a parent backup supplied when restoring an incremental backup.
+type ParentBackup struct {
+	Info *Info
+	Data io.ReadSeeker
+}
+
+// IncludedSnapshots returns the snapshots whose data is included in the backup.
+// For an incremental backup these are the snapshots taken after its base snapshot.
+func (b *Info) IncludedSnapshots() []string {
+	if b.Base == "" {
+		return b.Snapshots
+	}
+
+	idx := slices.Index(b.Snapshots, b.Base)
+	if idx < 0 {
+		return nil
+	}
+
+	return b.Snapshots[idx+1:]
+}
+
+// ValidateChain checks that the given backups form a restorable chain, starting with a full backup
+// and followed by incremental backups each based on the one before it.
+func ValidateChain(chain []*Info) error {
+	if len(chain) == 0 {
+		return errors.New("Backup chain is empty")
+	}
+
+	if chain[0].Parent != "" {
+		return fmt.Errorf("Backup %q depends on parent backup %q which wasn't provided", chain[0].Backup, chain[0].Parent)
+	}
+
+	if len(chain) > 1 && chain[len(chain)-1].ChangedBlocks {
+		return validateChangedBlocksChain(chain)
+	}
+
+	for i, b := range chain {
+		if b.OptimizedStorage == nil || !*b.OptimizedStorage {
+			return fmt.Errorf("Backup %q isn't an optimized backup", b.Backup)
+		}
+
+		if i == 0 {
+			continue
+		}
+
+		prev := chain[i-1]
+		if b.Parent == "" {
+			return fmt.Errorf("Backup %q isn't an incremental backup", b.Backup)
+		}
+
+		if b.Parent != prev.Backup {
+			return fmt.Errorf("Backup %q depends on parent backup %q, not %q", b.Backup, b.Parent, prev.Backup)
+		}
+
+		if b.Backend != prev.Backend {
+			return fmt.Errorf("Backup %q uses storage driver %q but its parent uses %q", b.Backup, b.Backend, prev.Backend)
+		}
+
+		if b.Type != prev.Type {
+			return fmt.Errorf("Backup %q is of type %q but its parent is of type %q", b.Backup, b.Type, prev.Type)
+		}
+
+		if len(prev.Snapshots) == 0 || prev.Snapshots[len(prev.Snapshots)-1] != b.Base {
+			return fmt.Errorf("Backup %q is based on snapshot %q which isn't the latest snapshot of its parent", b.Backup, b.Base)
+		}
+
+		idx := slices.Index(b.Snapshots, b.Base)
+		if idx < 0 {
+			return fmt.Errorf("Backup %q doesn't list its base snapshot %q", b.Backup, b.Base)
+		}
+
+		// Snapshots up to the base must have come from the parent backups.
+		for _, snapName := range b.Snapshots[:idx] {
+			if !slices.Contains(prev.Snapshots, snapName) {
+				return fmt.Errorf("Backup %q lists snapshot %q which isn't included in its parent backups", b.Backup, snapName)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateChangedBlocksChain checks that the given backups form a restorable chain of a full VM backup followed by
+// incremental backups holding the changed blocks of the root disk. None of the backups can include snapshots.
+func validateChangedBlocksChain(chain []*Info) error {
+	for i, b := range chain {
+		if b.Type != config.TypeVM {
+			return fmt.Errorf("Backup %q isn't a virtual machine backup", b.Backup)
+		}
+
+		if len(b.Snapshots) > 0 {
+			return fmt.Errorf("Backup %q includes snapshots", b.Backup)
+		}
+
+		if b.OptimizedStorage != nil && *b.OptimizedStorage {
+			return fmt.Errorf("Backup %q is an optimized backup", b.Backup)
+		}
+
+		if i == 0 {
+			continue
+		}
+
+		if !b.ChangedBlocks {
+			return fmt.Errorf("Backup %q isn't a changed block tracking backup", b.Backup)
+		}
+
+		if b.Parent != chain[i-1].Backup {
+			return fmt.Errorf("Backup %q depends on parent backup %q, not %q", b.Backup, b.Parent, chain[i-1].Backup)
+		}
+	}
+
+	return nil
 }
 
 // GetInfo extracts backup information from a given ReadSeeker.
@@ -0,0 +1,150 @@
+package backup
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/canonical/lxd/lxd/backup/config"
+)
+
+func TestInfoIncludedSnapshots(t *testing.T) {
+	tests := []struct {
+		name     string
+		info     Info
+		expected []string
+	}{
+		{
+			name:     "Full backup",
+			info:     Info{Snapshots: []string{"snap0", "snap1"}},
+			expected: []string{"snap0", "snap1"},
+		},
+		{
+			name:     "Incremental backup",
+			info:     Info{Snapshots: []string{"snap0", "snap1", "snap2", "snap3"}, Base: "snap1"},
+			expected: []string{"snap2", "snap3"},
+		},
+		{
+			name:     "Incremental backup without new snapshots",
+			info:     Info{Snapshots: []string{"snap0", "snap1"}, Base: "snap1"},
+			expected: []string{},
+		},
+		{
+			name: "Missing base",
+			info: Info{Snapshots: []string{"snap0"}, Base: "snap1"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.info.IncludedSnapshots()
+			if !slices.Equal(got, test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestValidateChain(t *testing.T) {
+	optimized := true
+	notOptimized := false
+
+	full := &Info{Backup: "backup0", Backend: "zfs", Type: config.TypeContainer, OptimizedStorage: &optimized, Snapshots: []string{"snap0", "snap1"}}
+	incr1 := &Info{Backup: "backup1", Backend: "zfs", Type: config.TypeContainer, OptimizedStorage: &optimized, Snapshots: []string{"snap1", "snap2"}, Parent: "backup0", Base: "snap1"}
+	incr2 := &Info{Backup: "backup2", Backend: "zfs", Type: config.TypeContainer, OptimizedStorage: &optimized, Snapshots: []string{"snap1", "snap2", "snap3"}, Parent: "backup1", Base: "snap2"}
+
+	vmFull := &Info{Backup: "backup0", Backend: "lvm", Type: config.TypeVM, OptimizedStorage: &notOptimized}
+	vmIncr1 := &Info{Backup: "backup1", Backend: "lvm", Type: config.TypeVM, OptimizedStorage: &notOptimized, Parent: "backup0", ChangedBlocks: true}
+	vmIncr2 := &Info{Backup: "backup2", Backend: "ceph", Type: config.TypeVM, OptimizedStorage: &notOptimized, Parent: "backup1", ChangedBlocks: true}
+
+	tests := []struct {
+		name    string
+		chain   []*Info
+		wantErr bool
+	}{
+		{
+			name:  "Single full backup",
+			chain: []*Info{full},
+		},
+		{
+			name:  "Full chain",
+			chain: []*Info{full, incr1, incr2},
+		},
+		{
+			name:    "Empty chain",
+			wantErr: true,
+		},
+		{
+			name:    "Missing full backup",
+			chain:   []*Info{incr1, incr2},
+			wantErr: true,
+		},
+		{
+			name:    "Missing intermediate backup",
+			chain:   []*Info{full, incr2},
+			wantErr: true,
+		},
+		{
+			name:    "Wrong order",
+			chain:   []*Info{full, incr2, incr1},
+			wantErr: true,
+		},
+		{
+			name:    "Non-optimized backup",
+			chain:   []*Info{{Backup: "backup0", Backend: "zfs", OptimizedStorage: &notOptimized}},
+			wantErr: true,
+		},
+		{
+			name:    "Different backend",
+			chain:   []*Info{full, {Backup: "backup1", Backend: "btrfs", Type: config.TypeContainer, OptimizedStorage: &optimized, Snapshots: []string{"snap1"}, Parent: "backup0", Base: "snap1"}},
+			wantErr: true,
+		},
+		{
+			name:    "Base isn't the latest parent snapshot",
+			chain:   []*Info{full, {Backup: "backup1", Backend: "zfs", Type: config.TypeContainer, OptimizedStorage: &optimized, Snapshots: []string{"snap0", "snap2"}, Parent: "backup0", Base: "snap0"}},
+			wantErr: true,
+		},
+		{
+			name:  "Changed blocks chain",
+			chain: []*Info{vmFull, vmIncr1, vmIncr2},
+		},
+		{
+			name:    "Changed blocks missing intermediate backup",
+			chain:   []*Info{vmFull, vmIncr2},
+			wantErr: true,
+		},
+		{
+			name:    "Changed blocks full backup with snapshots",
+			chain:   []*Info{{Backup: "backup0", Backend: "lvm", Type: config.TypeVM, OptimizedStorage: &notOptimized, Snapshots: []string{"snap0"}}, vmIncr1},
+			wantErr: true,
+		},
+		{
+			name:    "Changed blocks container backup",
+			chain:   []*Info{{Backup: "backup0", Backend: "lvm", Type: config.TypeContainer, OptimizedStorage: &notOptimized}, vmIncr1},
+			wantErr: true,
+		},
+		{
+			name:    "Changed blocks optimized full backup",
+			chain:   []*Info{{Backup: "backup0", Backend: "lvm", Type: config.TypeVM, OptimizedStorage: &optimized}, vmIncr1},
+			wantErr: true,
+		},
+		{
+			name:    "Changed blocks mixed with optimized incremental backup",
+			chain:   []*Info{vmFull, {Backup: "backup1", Backend: "lvm", Type: config.TypeVM, OptimizedStorage: &notOptimized, Parent: "backup0"}, vmIncr2},
+			wantErr: true,
+		},
+		{
+			name:    "Snapshot before base missing from parent",
+			chain:   []*Info{full, {Backup: "backup1", Backend: "zfs", Type: config.TypeContainer, OptimizedStorage: &optimized, Snapshots: []string{"other", "snap1"}, Parent: "backup0", Base: "snap1"}},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateChain(test.chain)
+			if (err != nil) != test.wantErr {
+				t.Errorf("Expected error %v, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package backup
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// targetUploadPartSize is the multipart chunk size used when streaming backups of unknown size to a target.
+const targetUploadPartSize = 64 * 1024 * 1024
+
+// Target represents a resolved S3-compatible backup target.
+type Target struct {
+	Name       string
+	Endpoint   string
+	Bucket     string
+	Region     string
+	AccessKey  string
+	SecretKey  string
+	PathPrefix string
+	TLSConfig  *tls.Config
+}
+
+// ObjectKey returns the object key for the given path elements, prefixed by the target's path prefix.
+func (t *Target) ObjectKey(elems ...string) string {
+	return strings.TrimPrefix(path.Join(append([]string{"/", t.PathPrefix}, elems...)...), "/")
+}
+
+// InProject returns whether the object key is stored below the path of the given project on the target.
+func (t *Target) InProject(projectName string, key string) bool {
+	if key == "" || path.Clean(key) != key {
+		return false
+	}
+
+	return strings.HasPrefix(key, t.ObjectKey(projectName)+"/")
+}
+
+// client returns an S3 client for the target.
+func (t *Target) client() (*minio.Client, error) {
+	u, err := url.ParseRequestURI(t.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing backup target endpoint: %w", err)
+	}
+
+	var transport http.RoundTripper
+	if t.TLSConfig != nil {
+		transport = &http.Transport{TLSClientConfig: t.TLSConfig}
+	}
+
+	return minio.New(u.Host, &minio.Options{
+		Creds:        credentials.NewStaticV4(t.AccessKey, t.SecretKey, ""),
+		Secure:       u.Scheme == "https",
+		Region:       t.Region,
+		Transport:    transport,
+		BucketLookup: minio.BucketLookupPath,
+	})
+}
+
+// TargetWriter streams data into an object on a backup target.
+type TargetWriter struct {
+	pipe *io.PipeWriter
+	done chan error
+}
+
+// Write writes data to the object.
+func (w *TargetWriter) Write(p []byte) (int, error) {
+	return w.pipe.Write(p)
+}
+
+// Close finalizes the object and waits for the upload to complete.
+func (w *TargetWriter) Close() error {
+	err := w.pipe.Close()
+	if err != nil {
+		return err
+	}
+
+	return <-w.done
+}
+
+// Abort cancels the upload, leaving no object behind.
+func (w *TargetWriter) Abort(err error) {
+	if err == nil {
+		err = errors.New("Upload aborted")
+	}
+
+	_ = w.pipe.CloseWithError(err)
+	<-w.done
+}
+
+// Upload returns a writer that streams its content into the object with the given key.
+// The object only becomes visible once the writer is closed successfully.
+func (t *Target) Upload(ctx context.Context, key string) (*TargetWriter, error) {
+	client, err := t.client()
+	if err != nil {
+		return nil, err
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+	w := &TargetWriter{
+		pipe: pipeWriter,
+		done: make(chan error, 1),
+	}
+
+	go func() {
+		_, err := client.PutObject(ctx, t.Bucket, key, pipeReader, -1, minio.PutObjectOptions{
+			ContentType: "application/octet-stream",
+			PartSize:    targetUploadPartSize,
+		})
+		if err != nil {
+			err = fmt.Errorf("Failed uploading %q to backup target %q: %w", key, t.Name, err)
+		}
+
+		// Unblock any pending writer if the upload failed early.
+		_ = pipeReader.CloseWithError(err)
+		w.done <- err
+	}()
+
+	return w, nil
+}
+
+// Open returns a reader for the object with the given key.
+func (t *Target) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	client, err := t.client()
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := client.GetObject(ctx, t.Bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("Failed opening %q on backup target %q: %w", key, t.Name, err)
+	}
+
+	// GetObject is lazy, so check the object exists before handing it out.
+	_, err = obj.Stat()
+	if err != nil {
+		_ = obj.Close()
+
+		if minio.ToErrorResponse(err).Code == minio.NoSuchKey {
+			return nil, api.StatusErrorf(http.StatusNotFound, "Backup %q not found on backup target %q", key, t.Name)
+		}
+
+		return nil, fmt.Errorf("Failed opening %q on backup target %q: %w", key, t.Name, err)
+	}
+
+	return obj, nil
+}
+
+// Exists returns whether an object with the given key exists.
+func (t *Target) Exists(ctx context.Context, key string) (bool, error) {
+	client, err := t.client()
+	if err != nil {
+		return false, err
+	}
+
+	_, err = client.StatObject(ctx, t.Bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == minio.NoSuchKey {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("Failed checking %q on backup target %q: %w", key, t.Name, err)
+	}
+
+	return true, nil
+}
+
+// List returns the names of the objects directly below the given key prefix.
+func (t *Target) List(ctx context.Context, prefix string) ([]string, error) {
+	client, err := t.client()
+	if err != nil {
+		return nil, err
+	}
+
+	prefix = strings.TrimSuffix(prefix, "/") + "/"
+
+	var names []string
+	for obj := range client.ListObjects(ctx, t.Bucket, minio.ListObjectsOptions{Prefix: prefix}) {
+		if obj.Err != nil {
+			return nil, fmt.Errorf("Failed listing %q on backup target %q: %w", prefix, t.Name, obj.Err)
+		}
+
+		name := strings.TrimPrefix(obj.Key, prefix)
+		if name == "" || strings.HasSuffix(name, "/") {
+			continue
+		}
+
+		names = append(names, name)
+	}
+
+	return names, nil
+}
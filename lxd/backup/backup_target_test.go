@@ -0,0 +1,94 @@
+package backup
+
+import (
+	"testing"
+)
+
+func TestTargetObjectKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		pathPrefix string
+		elems      []string
+		expected   string
+	}{
+		{
+			name:     "No prefix",
+			elems:    []string{"default", "instances", "c1", "backup0"},
+			expected: "default/instances/c1/backup0",
+		},
+		{
+			name:       "Prefix",
+			pathPrefix: "lxd",
+			elems:      []string{"default", "instances", "c1", "backup0"},
+			expected:   "lxd/default/instances/c1/backup0",
+		},
+		{
+			name:       "Prefix with slashes",
+			pathPrefix: "/backups/lxd01/",
+			elems:      []string{"default", "volumes", "pool1", "vol1", "backup0"},
+			expected:   "backups/lxd01/default/volumes/pool1/vol1/backup0",
+		},
+	}
+
+	for _, test := range tests {
+		target := &Target{PathPrefix: test.pathPrefix}
+		got := target.ObjectKey(test.elems...)
+		if got != test.expected {
+			t.Errorf("%s: expected %q, got %q", test.name, test.expected, got)
+		}
+	}
+}
+
+func TestTargetInProject(t *testing.T) {
+	tests := []struct {
+		name       string
+		pathPrefix string
+		key        string
+		expected   bool
+	}{
+		{
+			name:     "Own project",
+			key:      "p1/instances/c1/backup0",
+			expected: true,
+		},
+		{
+			name:       "Own project with prefix",
+			pathPrefix: "lxd",
+			key:        "lxd/p1/volumes/pool1/vol1/backup0",
+			expected:   true,
+		},
+		{
+			name: "Other project",
+			key:  "p2/instances/c1/backup0",
+		},
+		{
+			name: "Project name prefix",
+			key:  "p10/instances/c1/backup0",
+		},
+		{
+			name:       "Outside of prefix",
+			pathPrefix: "lxd",
+			key:        "p1/instances/c1/backup0",
+		},
+		{
+			name: "Parent directory",
+			key:  "p1/../p2/instances/c1/backup0",
+		},
+		{
+			name: "Project directory",
+			key:  "p1",
+		},
+		{
+			name: "Empty",
+			key:  "",
+		},
+	}
+
+	for _, test := range tests {
+		target := &Target{PathPrefix: test.pathPrefix}
+		got := target.InProject("p1", test.key)
+		if got != test.expected {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, got)
+		}
+	}
+}
@@ -2,9 +2,12 @@ package backup
 
 import (
 	"archive/tar"
+	"cmp"
 	"context"
 	"errors"
 	"io"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/canonical/lxd/lxd/archive"
@@ -54,3 +57,71 @@ func ValidateBackupName(backupName string) (string, error) {
 
 	return backupName, nil
 }
+
+// ScheduledBackupPrefix is the name prefix of backups created by backup schedules.
+const ScheduledBackupPrefix = "auto"
+
+// backupNumber returns the number of the backup with the given full name ("<parent>/<prefix><number>").
+// If the backup doesn't belong to the parent or isn't named with the prefix, then false is returned.
+func backupNumber(parentName string, prefix string, fullName string) (int, bool) {
+	base := parentName + shared.SnapshotDelimiter + prefix
+	if !strings.HasPrefix(fullName, base) {
+		return -1, false
+	}
+
+	num, err := strconv.Atoi(fullName[len(base):])
+	if err != nil || num < 0 {
+		return -1, false
+	}
+
+	return num, true
+}
+
+// NextBackupName returns the next available backup name of the form "<prefix><number>" for the given parent,
+// based on the full names ("<parent>/<backup>") of its existing backups.
+func NextBackupName(parentName string, prefix string, backupNames []string) string {
+	backupNo := 0
+
+	// Iterate over previous backups to autoincrement the backup number.
+	for _, name := range backupNames {
+		num, ok := backupNumber(parentName, prefix, name)
+		if ok && num >= backupNo {
+			backupNo = num + 1
+		}
+	}
+
+	return prefix + strconv.Itoa(backupNo)
+}
+
+// ScheduledBackupsToPrune returns the full names of the oldest scheduled backups of the given parent that
+// exceed the number of scheduled backups to retain.
+func ScheduledBackupsToPrune(parentName string, backupNames []string, retain int) []string {
+	type scheduledBackup struct {
+		name string
+		num  int
+	}
+
+	var scheduled []scheduledBackup
+	for _, name := range backupNames {
+		num, ok := backupNumber(parentName, ScheduledBackupPrefix, name)
+		if ok {
+			scheduled = append(scheduled, scheduledBackup{name: name, num: num})
+		}
+	}
+
+	if retain < 0 || len(scheduled) <= retain {
+		return nil
+	}
+
+	// Backup numbers are allocated incrementally, so the lowest numbers are the oldest backups.
+	slices.SortFunc(scheduled, func(a scheduledBackup, b scheduledBackup) int {
+		return cmp.Compare(a.num, b.num)
+	})
+
+	prune := make([]string, 0, len(scheduled)-retain)
+	for _, b := range scheduled[:len(scheduled)-retain] {
+		prune = append(prune, b.name)
+	}
+
+	return prune
+}
@@ -0,0 +1,80 @@
+package backup
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNextBackupName(t *testing.T) {
+	tests := []struct {
+		name        string
+		prefix      string
+		backupNames []string
+		expected    string
+	}{
+		{
+			name:     "No backups",
+			prefix:   "backup",
+			expected: "backup0",
+		},
+		{
+			name:        "Existing backups",
+			prefix:      "backup",
+			backupNames: []string{"c1/backup0", "c1/backup3", "c1/backup1"},
+			expected:    "backup4",
+		},
+		{
+			name:        "Ignores other names and parents",
+			prefix:      "backup",
+			backupNames: []string{"c1/backup0", "c1/auto7", "c1/backupfoo", "c2/backup5", "c10/backup9"},
+			expected:    "backup1",
+		},
+		{
+			name:        "Scheduled backups",
+			prefix:      ScheduledBackupPrefix,
+			backupNames: []string{"c1/backup0", "c1/auto2"},
+			expected:    "auto3",
+		},
+	}
+
+	for _, test := range tests {
+		actual := NextBackupName("c1", test.prefix, test.backupNames)
+		if actual != test.expected {
+			t.Errorf("%s: Unexpected backup name: %q != %q", test.name, actual, test.expected)
+		}
+	}
+}
+
+func TestScheduledBackupsToPrune(t *testing.T) {
+	tests := []struct {
+		name        string
+		backupNames []string
+		retain      int
+		expected    []string
+	}{
+		{
+			name:        "Below retention",
+			backupNames: []string{"c1/auto0", "c1/auto1"},
+			retain:      2,
+		},
+		{
+			name:        "Above retention",
+			backupNames: []string{"c1/auto10", "c1/auto2", "c1/auto9", "c1/auto3"},
+			retain:      2,
+			expected:    []string{"c1/auto2", "c1/auto3"},
+		},
+		{
+			name:        "Ignores manual backups",
+			backupNames: []string{"c1/backup0", "c1/backup1", "c1/auto4", "c1/auto5", "c2/auto0"},
+			retain:      1,
+			expected:    []string{"c1/auto4"},
+		},
+	}
+
+	for _, test := range tests {
+		actual := ScheduledBackupsToPrune("c1", test.backupNames, test.retain)
+		if !slices.Equal(actual, test.expected) {
+			t.Errorf("%s: Unexpected backups to prune: %v != %v", test.name, actual, test.expected)
+		}
+	}
+}
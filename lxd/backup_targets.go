@@ -0,0 +1,718 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/backup"
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/lifecycle"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	storagePools "github.com/canonical/lxd/lxd/storage"
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/validate"
+	"github.com/canonical/lxd/shared/version"
+)
+
+var backupTargetsCmd = APIEndpoint{
+	Path:        "backup-targets",
+	MetricsType: entity.TypeServer,
+
+	Get:  APIEndpointAction{Handler: backupTargetsGet, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
+	Post: APIEndpointAction{Handler: backupTargetsPost, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
+}
+
+var backupTargetCmd = APIEndpoint{
+	Path:        "backup-targets/{name}",
+	MetricsType: entity.TypeServer,
+
+	Delete: APIEndpointAction{Handler: backupTargetDelete, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
+	Get:    APIEndpointAction{Handler: backupTargetGet, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
+	Put:    APIEndpointAction{Handler: backupTargetPut, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
+	Patch:  APIEndpointAction{Handler: backupTargetPut, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
+}
+
+// API endpoints.
+
+// swagger:operation GET /1.0/backup-targets backup-targets backup_targets_get
+//
+//	Get the backup targets
+//
+//	Returns a list of backup targets (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/backup-targets/offsite",
+//	              "/1.0/backup-targets/local"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/backup-targets?recursion=1 backup-targets backup_targets_get_recursion1
+//
+//	Get the backup targets
+//
+//	Returns a list of backup targets (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of backup targets
+//	          items:
+//	            $ref: "#/definitions/BackupTarget"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func backupTargetsGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+	recursion := util.IsRecursionRequest(r)
+
+	var apiTargets []*api.BackupTarget
+	var targetURLs []string
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		targets, err := cluster.GetBackupTargets(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		for _, target := range targets {
+			if !recursion {
+				targetURLs = append(targetURLs, api.NewURL().Path(version.APIVersion, "backup-targets", target.Name).String())
+				continue
+			}
+
+			apiTarget, err := target.ToAPI(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			backupTargetRedact(apiTarget)
+			apiTargets = append(apiTargets, apiTarget)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !recursion {
+		return response.SyncResponse(true, targetURLs)
+	}
+
+	return response.SyncResponse(true, apiTargets)
+}
+
+// swagger:operation POST /1.0/backup-targets backup-targets backup_targets_post
+//
+//	Add a backup target
+//
+//	Creates a new backup target.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: backupTarget
+//	    description: The new backup target
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/BackupTargetsPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func backupTargetsPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := api.BackupTargetsPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = validate.IsDeviceName(req.Name)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = backupTargetValidateConfig(req.Config)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		id, err := cluster.CreateBackupTarget(ctx, tx.Tx(), cluster.BackupTarget{
+			Name:        req.Name,
+			Description: req.Description,
+		})
+		if err != nil {
+			return err
+		}
+
+		return cluster.CreateBackupTargetConfig(ctx, tx.Tx(), id, req.Config)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	lc := lifecycle.BackupTargetCreated.Event(req.Name, request.CreateRequestor(r.Context()), nil)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation DELETE /1.0/backup-targets/{name} backup-targets backup_target_delete
+//
+//	Delete the backup target
+//
+//	Removes the backup target. Objects already stored on the target are left untouched.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func backupTargetDelete(d *Daemon, r *http.Request) response.Response {
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	s := d.State()
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return cluster.DeleteBackupTarget(ctx, tx.Tx(), name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.BackupTargetDeleted.Event(name, request.CreateRequestor(r.Context()), nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation GET /1.0/backup-targets/{name} backup-targets backup_target_get
+//
+//	Get the backup target
+//
+//	Gets a specific backup target.
+//	The access and secret keys are never included.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Backup target
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/BackupTarget"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func backupTargetGet(d *Daemon, r *http.Request) response.Response {
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	s := d.State()
+
+	var target *api.BackupTarget
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbTarget, err := cluster.GetBackupTarget(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		target, err = dbTarget.ToAPI(ctx, tx.Tx())
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	backupTargetRedact(target)
+
+	return response.SyncResponseETag(true, target, target)
+}
+
+// swagger:operation PATCH /1.0/backup-targets/{name} backup-targets backup_target_patch
+//
+//	Partially update the backup target
+//
+//	Updates a subset of the backup target configuration.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: backupTarget
+//	    description: Backup target configuration
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/BackupTargetPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation PUT /1.0/backup-targets/{name} backup-targets backup_target_put
+//
+//	Update the backup target
+//
+//	Updates the entire backup target configuration.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: backupTarget
+//	    description: Backup target configuration
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/BackupTargetPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func backupTargetPut(d *Daemon, r *http.Request) response.Response {
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.BackupTargetPut{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	s := d.State()
+
+	var existing *api.BackupTarget
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbTarget, err := cluster.GetBackupTarget(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		existing, err = dbTarget.ToAPI(ctx, tx.Tx())
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// The ETag is computed from the redacted target that is returned by GET.
+	redacted := *existing
+	redacted.Config = maps.Clone(existing.Config)
+	backupTargetRedact(&redacted)
+
+	err = util.EtagCheck(r, redacted)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// The credentials are never returned to clients, so keep the existing ones unless new ones are provided.
+	if req.Config != nil {
+		for _, key := range backupTargetSecretKeys {
+			_, ok := req.Config[key]
+			if !ok && existing.Config[key] != "" {
+				req.Config[key] = existing.Config[key]
+			}
+		}
+	}
+
+	if r.Method == http.MethodPatch {
+		if req.Description == "" {
+			req.Description = existing.Description
+		}
+
+		if req.Config == nil {
+			req.Config = existing.Config
+		} else {
+			for k, v := range existing.Config {
+				_, ok := req.Config[k]
+				if !ok {
+					req.Config[k] = v
+				}
+			}
+		}
+	}
+
+	err = backupTargetValidateConfig(req.Config)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		id, err := cluster.GetBackupTargetID(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		err = cluster.UpdateBackupTarget(ctx, tx.Tx(), name, cluster.BackupTarget{
+			Name:        name,
+			Description: req.Description,
+		})
+		if err != nil {
+			return err
+		}
+
+		return cluster.UpdateBackupTargetConfig(ctx, tx.Tx(), id, req.Config)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.BackupTargetUpdated.Event(name, request.CreateRequestor(r.Context()), nil))
+
+	return response.EmptySyncResponse
+}
+
+// backupTargetSecretKeys are the backup target configuration keys holding credentials.
+var backupTargetSecretKeys = []string{"s3.access_key", "s3.secret_key"}
+
+// backupTargetRedact removes the credentials from the backup target. They are never returned by the API.
+func backupTargetRedact(target *api.BackupTarget) {
+	for _, key := range backupTargetSecretKeys {
+		delete(target.Config, key)
+	}
+}
+
+// backupTargetValidateConfig validates the configuration keys/values for backup targets.
+func backupTargetValidateConfig(config map[string]string) error {
+	backupTargetConfigKeys := map[string]func(value string) error{
+		// lxdmeta:generate(entities=backup-target; group=common; key=s3.endpoint)
+		// URL of the S3-compatible endpoint, for example, `https://s3.example.com`.
+		// Cannot be combined with `storage.pool`.
+		// ---
+		//  type: string
+		//  shortdesc: S3 endpoint URL
+		"s3.endpoint": validate.Optional(validate.IsRequestURL),
+
+		// lxdmeta:generate(entities=backup-target; group=common; key=s3.bucket)
+		// The bucket must exist already.
+		// ---
+		//  type: string
+		//  shortdesc: Name of the S3 bucket that backups are stored in
+		"s3.bucket": validate.IsAny,
+
+		// lxdmeta:generate(entities=backup-target; group=common; key=s3.region)
+		//
+		// ---
+		//  type: string
+		//  shortdesc: S3 region
+		"s3.region": validate.IsAny,
+
+		// lxdmeta:generate(entities=backup-target; group=common; key=s3.access_key)
+		// This key is never returned by the API.
+		// ---
+		//  type: string
+		//  shortdesc: S3 access key
+		"s3.access_key": validate.IsAny,
+
+		// lxdmeta:generate(entities=backup-target; group=common; key=s3.secret_key)
+		// This key is never returned by the API.
+		// ---
+		//  type: string
+		//  shortdesc: S3 secret key
+		"s3.secret_key": validate.IsAny,
+
+		// lxdmeta:generate(entities=backup-target; group=common; key=s3.path_prefix)
+		// Backups are stored below this path in the bucket, in `<project>/instances/<instance>/` or
+		// `<project>/volumes/<pool>/<volume>/`.
+		// ---
+		//  type: string
+		//  shortdesc: Path prefix for backup objects
+		"s3.path_prefix": validate.IsAny,
+
+		// lxdmeta:generate(entities=backup-target; group=common; key=s3.ca_certificate)
+		// PEM-encoded certificate that is used to validate the endpoint's TLS certificate instead of
+		// the system CAs.
+		// ---
+		//  type: string
+		//  shortdesc: CA certificate for the S3 endpoint
+		"s3.ca_certificate": validate.Optional(validate.IsX509Certificate),
+
+		// lxdmeta:generate(entities=backup-target; group=common; key=storage.pool)
+		// Use a storage bucket managed by LXD as the backup target instead of an external S3 endpoint.
+		// The endpoint and credentials are taken from the storage bucket.
+		// Cannot be combined with `s3.endpoint`.
+		// ---
+		//  type: string
+		//  shortdesc: Storage pool of the storage bucket to use
+		"storage.pool": validate.Optional(validate.IsAny),
+
+		// lxdmeta:generate(entities=backup-target; group=common; key=storage.bucket)
+		// The bucket must have a key with the `admin` role.
+		// Buckets on local storage pools must be located on the cluster member that runs the backup or restore.
+		// ---
+		//  type: string
+		//  shortdesc: Name of the storage bucket to use
+		"storage.bucket": validate.IsAny,
+
+		// lxdmeta:generate(entities=backup-target; group=common; key=storage.project)
+		//
+		// ---
+		//  type: string
+		//  defaultdesc: `default`
+		//  shortdesc: Project of the storage bucket to use
+		"storage.project": validate.Optional(validate.IsAny),
+	}
+
+	for k, v := range config {
+		validator, ok := backupTargetConfigKeys[k]
+		if !ok {
+			return fmt.Errorf("Invalid backup target configuration key %q", k)
+		}
+
+		err := validator(v)
+		if err != nil {
+			return fmt.Errorf("Invalid backup target configuration key %q value: %w", k, err)
+		}
+	}
+
+	if config["storage.pool"] != "" {
+		if config["s3.endpoint"] != "" || config["s3.bucket"] != "" {
+			return errors.New(`"storage.pool" cannot be combined with "s3.endpoint" or "s3.bucket"`)
+		}
+
+		if config["storage.bucket"] == "" {
+			return errors.New(`"storage.bucket" is required when "storage.pool" is set`)
+		}
+
+		return nil
+	}
+
+	if config["s3.endpoint"] == "" || config["s3.bucket"] == "" {
+		return errors.New(`Either "s3.endpoint" and "s3.bucket" or "storage.pool" and "storage.bucket" must be set`)
+	}
+
+	return nil
+}
+
+// backupTargetLoad resolves the named backup target into a [backup.Target] that can be streamed to and from.
+func backupTargetLoad(ctx context.Context, s *state.State, name string) (*backup.Target, error) {
+	var config map[string]string
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		dbTarget, err := cluster.GetBackupTarget(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		config, err = cluster.GetBackupTargetConfig(ctx, tx.Tx(), dbTarget.ID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading backup target %q: %w", name, err)
+	}
+
+	target := &backup.Target{
+		Name:       name,
+		Endpoint:   config["s3.endpoint"],
+		Bucket:     config["s3.bucket"],
+		Region:     config["s3.region"],
+		AccessKey:  config["s3.access_key"],
+		SecretKey:  config["s3.secret_key"],
+		PathPrefix: config["s3.path_prefix"],
+	}
+
+	if config["s3.ca_certificate"] != "" {
+		target.TLSConfig, err = shared.GetTLSConfigMem("", "", config["s3.ca_certificate"], "", false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if config["storage.pool"] == "" {
+		return target, nil
+	}
+
+	// Resolve the endpoint and credentials of an LXD managed storage bucket.
+	pool, err := storagePools.LoadByName(s, config["storage.pool"])
+	if err != nil {
+		return nil, err
+	}
+
+	if !pool.Driver().Info().Buckets {
+		return nil, fmt.Errorf("Storage pool %q does not support buckets", pool.Name())
+	}
+
+	bucketProject := config["storage.project"]
+	if bucketProject == "" {
+		bucketProject = api.ProjectDefaultName
+	}
+
+	memberSpecific := !pool.Driver().Info().Remote
+
+	var keys []*db.StorageBucketKey
+	var bucketName string
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		bucket, err := tx.GetStoragePoolBucket(ctx, pool.ID(), bucketProject, memberSpecific, config["storage.bucket"])
+		if err != nil {
+			return err
+		}
+
+		bucketName = bucket.Name
+
+		keys, err = tx.GetStoragePoolBucketKeys(ctx, bucket.ID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading storage bucket %q for backup target %q: %w", config["storage.bucket"], name, err)
+	}
+
+	for _, key := range keys {
+		if key.Role == "admin" {
+			target.AccessKey = key.AccessKey
+			target.SecretKey = key.SecretKey
+			break
+		}
+	}
+
+	if target.AccessKey == "" {
+		return nil, fmt.Errorf("Storage bucket %q has no key with the admin role", bucketName)
+	}
+
+	u := pool.GetBucketURL(bucketName)
+	if u == nil {
+		return nil, fmt.Errorf("Storage bucket %q is not reachable over S3", bucketName)
+	}
+
+	// The bucket URL points at the bucket itself, split it into endpoint and bucket name.
+	target.Bucket = path.Base(u.Path)
+	u.Path = strings.TrimSuffix(path.Dir(u.Path), "/")
+	target.Endpoint = u.String()
+
+	if memberSpecific {
+		// Local buckets are served by this server's storage buckets listener.
+		target.TLSConfig, err = shared.GetTLSConfigMem("", "", "", string(s.Endpoints.NetworkCert().PublicKey()), false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return target, nil
+}
@@ -5,6 +5,7 @@ type DebugInfo struct {
 	Server   DebugInfoServer   `json:"server" yaml:"server"`
 	Prefixes []DebugInfoPrefix `json:"prefixes" yaml:"prefixes"`
 	Peers    []DebugInfoPeer   `json:"peers" yaml:"peers"`
+	Routes   []DebugInfoRoute  `json:"routes" yaml:"routes"`
 }
 
 // DebugInfoServer exposes the shared listener configuration.
@@ -13,6 +14,7 @@ type DebugInfoServer struct {
 	ASN      uint32 `json:"asn" yaml:"asn"`
 	RouterID string `json:"router_id" yaml:"router_id"`
 	Running  bool   `json:"running" yaml:"running"`
+	Ready    bool   `json:"ready" yaml:"ready"`
 }
 
 // DebugInfoPrefix exposes details on a single BGP prefix.
@@ -20,15 +22,27 @@ type DebugInfoPrefix struct {
 	Owner   string `json:"owner" yaml:"owner"`
 	Prefix  string `json:"prefix" yaml:"prefix"`
 	Nexthop string `json:"nexthop" yaml:"nexthop"`
+	Policy  string `json:"policy" yaml:"policy"`
 }
 
 // DebugInfoPeer exposes details on a single BGP peer.
 type DebugInfoPeer struct {
-	Address  string `json:"address" yaml:"address"`
-	ASN      uint32 `json:"asn" yaml:"asn"`
-	Password string `json:"password" yaml:"password"`
-	Count    int    `json:"count" yaml:"count"`
-	HoldTime uint64 `json:"holdtime" yaml:"holdtime"`
+	Address             string `json:"address" yaml:"address"`
+	ASN                 uint32 `json:"asn" yaml:"asn"`
+	Password            string `json:"password" yaml:"password"`
+	Count               int    `json:"count" yaml:"count"`
+	HoldTime            uint64 `json:"holdtime" yaml:"holdtime"`
+	KeepaliveInterval   uint64 `json:"keepalive" yaml:"keepalive"`
+	GracefulRestart     bool   `json:"graceful_restart" yaml:"graceful_restart"`
+	GracefulRestartTime uint32 `json:"graceful_restart_time" yaml:"graceful_restart_time"`
+}
+
+// DebugInfoRoute exposes details on a single route received from a peer.
+type DebugInfoRoute struct {
+	Prefix    string `json:"prefix" yaml:"prefix"`
+	Nexthop   string `json:"nexthop" yaml:"nexthop"`
+	Peer      string `json:"peer" yaml:"peer"`
+	Installed bool   `json:"installed" yaml:"installed"`
 }
 
 // Debug returns a dump of the current configuration.
@@ -45,6 +59,7 @@ func (s *Server) debug() DebugInfo {
 
 	// Fill in server state.
 	debug.Server.Running = s.bgp != nil
+	debug.Server.Ready = s.ready
 	debug.Server.ASN = s.asn
 	debug.Server.Address = s.address
 	debug.Server.RouterID = s.routerID.String()
@@ -57,7 +72,10 @@ func (s *Server) debug() DebugInfo {
 		entry.ASN = peer.asn
 		entry.Password = peer.password
 		entry.Count = peer.count
-		entry.HoldTime = peer.holdtime
+		entry.HoldTime = peer.opts.HoldTime
+		entry.KeepaliveInterval = peer.opts.KeepaliveInterval
+		entry.GracefulRestart = peer.opts.GracefulRestart
+		entry.GracefulRestartTime = peer.opts.GracefulRestartTime
 
 		debug.Peers = append(debug.Peers, entry)
 	}
@@ -69,9 +87,27 @@ func (s *Server) debug() DebugInfo {
 		entry.Prefix = path.prefix.String()
 		entry.Owner = path.owner
 		entry.Nexthop = path.nexthop.String()
+		entry.Policy = path.policy
 
 		debug.Prefixes = append(debug.Prefixes, entry)
 	}
 
+	// Fill in the received routes.
+	s.importMu.Lock()
+	defer s.importMu.Unlock()
+
+	debug.Routes = []DebugInfoRoute{}
+	for key, route := range s.received {
+		_, installed := s.installed[key]
+
+		entry := DebugInfoRoute{}
+		entry.Prefix = key
+		entry.Nexthop = route.nexthop.String()
+		entry.Peer = route.peer.String()
+		entry.Installed = installed
+
+		debug.Routes = append(debug.Routes, entry)
+	}
+
 	return debug
 }
@@ -0,0 +1,302 @@
+package bgp
+
+import (
+	"context"
+	"maps"
+	"net"
+	"slices"
+
+	bgpAPI "github.com/osrg/gobgp/v3/api"
+
+	"github.com/canonical/lxd/lxd/ip"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// routeProtocol is the routing protocol identifier of the imported routes.
+// It differs from the identifiers used by the routing daemons (such as 186 for bgp), so that LXD only ever
+// removes the routes it installed itself.
+const routeProtocol = "249"
+
+// ImportPolicy represents the routes received from the peers that are installed in the routing table.
+type ImportPolicy struct {
+	Peers    []net.IP    // Peers to accept routes from.
+	Prefixes []net.IPNet // Subnets the accepted routes must be part of (no route is accepted if empty).
+	Device   string      // Interface the next-hops are reached through (any interface if empty).
+	Table    string      // Routing table to install the routes in (main table if empty).
+}
+
+// route represents a route received from a peer.
+type route struct {
+	prefix  net.IPNet
+	nexthop net.IP
+	peer    net.IP
+}
+
+// installedRoute represents a route installed in the routing table.
+type installedRoute struct {
+	route
+
+	device string
+	table  string
+}
+
+// SetImportPolicy sets the named import policy and updates the installed routes.
+// A nil policy removes it.
+func (s *Server) SetImportPolicy(name string, policy *ImportPolicy) {
+	s.importMu.Lock()
+	defer s.importMu.Unlock()
+
+	if policy == nil {
+		delete(s.imports, name)
+	} else {
+		s.imports[name] = *policy
+	}
+
+	s.syncRoutes()
+}
+
+// watch starts watching the best paths to keep track of the routes received from the peers.
+func (s *Server) watch() error {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Remove the routes left over by a previous run, they're installed again as the paths are received.
+	err := routesFlushAll()
+	if err != nil {
+		logger.Warn("Failed removing leftover BGP routes", logger.Ctx{"err": err})
+	}
+
+	req := &bgpAPI.WatchEventRequest{
+		Table: &bgpAPI.WatchEventRequest_Table{
+			Filters: []*bgpAPI.WatchEventRequest_Table_Filter{{
+				Type: bgpAPI.WatchEventRequest_Table_Filter_BEST,
+				Init: true,
+			}},
+		},
+	}
+
+	err = s.bgp.WatchEvent(ctx, req, func(resp *bgpAPI.WatchEventResponse) {
+		s.handlePaths(ctx, resp.GetTable().GetPaths())
+	})
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	s.watchCancel = cancel
+
+	return nil
+}
+
+// unwatch stops watching the best paths and removes the installed routes.
+func (s *Server) unwatch() {
+	if s.watchCancel != nil {
+		s.watchCancel()
+		s.watchCancel = nil
+	}
+
+	s.importMu.Lock()
+	defer s.importMu.Unlock()
+
+	s.received = map[string]route{}
+	s.syncRoutes()
+}
+
+// handlePaths records the changes to the best paths received from the peers.
+func (s *Server) handlePaths(ctx context.Context, paths []*bgpAPI.Path) {
+	s.importMu.Lock()
+	defer s.importMu.Unlock()
+
+	// Ignore the events delivered after the watch was stopped.
+	if ctx.Err() != nil {
+		return
+	}
+
+	for _, path := range paths {
+		prefix := pathPrefix(path)
+		if prefix == nil {
+			continue
+		}
+
+		// The best path may now be withdrawn or one of the paths announced locally.
+		if path.IsWithdraw || !path.IsFromExternal {
+			delete(s.received, prefix.String())
+			continue
+		}
+
+		nexthop := pathNexthop(path)
+		if nexthop == nil {
+			continue
+		}
+
+		s.received[prefix.String()] = route{
+			prefix:  *prefix,
+			nexthop: nexthop,
+			peer:    net.ParseIP(path.NeighborIp),
+		}
+	}
+
+	s.syncRoutes()
+}
+
+// syncRoutes installs the received routes accepted by the import policies and removes the other ones.
+func (s *Server) syncRoutes() {
+	wanted := map[string]installedRoute{}
+	for key, r := range s.received {
+		policy, accepted := s.routeAccepted(r)
+		if accepted {
+			wanted[key] = installedRoute{route: r, device: policy.Device, table: policy.Table}
+		}
+	}
+
+	// Remove the routes no longer wanted or whose next-hop, device or table changed.
+	for key, r := range s.installed {
+		wantedRoute, found := wanted[key]
+		if found && wantedRoute.nexthop.Equal(r.nexthop) && wantedRoute.device == r.device && wantedRoute.table == r.table {
+			continue
+		}
+
+		err := routeFlush(r)
+		if err != nil {
+			logger.Warn("Failed removing BGP route", logger.Ctx{"prefix": key, "nexthop": r.nexthop.String(), "err": err})
+		}
+
+		delete(s.installed, key)
+	}
+
+	// Install the new routes.
+	for key, r := range wanted {
+		_, found := s.installed[key]
+		if found {
+			continue
+		}
+
+		ipRoute := &ip.Route{
+			DevName: r.device,
+			Route:   key,
+			Table:   r.table,
+			Via:     r.nexthop.String(),
+			Proto:   routeProtocol,
+			Family:  routeFamily(r.route),
+		}
+
+		err := ipRoute.Add()
+		if err != nil {
+			logger.Warn("Failed installing BGP route", logger.Ctx{"prefix": key, "nexthop": r.nexthop.String(), "err": err})
+			continue
+		}
+
+		s.installed[key] = r
+	}
+}
+
+// routeAccepted returns the first import policy, by name, that accepts the route and whether there is one.
+// Policies only accept the routes received from their peers and part of their prefixes, so the default route is
+// only accepted if a policy explicitly lists it.
+func (s *Server) routeAccepted(r route) (ImportPolicy, bool) {
+	for _, name := range slices.Sorted(maps.Keys(s.imports)) {
+		policy := s.imports[name]
+		if !slices.ContainsFunc(policy.Peers, r.peer.Equal) {
+			continue
+		}
+
+		routeOnes, routeBits := r.prefix.Mask.Size()
+		for _, subnet := range policy.Prefixes {
+			subnetOnes, subnetBits := subnet.Mask.Size()
+
+			if routeBits == subnetBits && subnet.Contains(r.prefix.IP) && routeOnes >= subnetOnes {
+				return policy, true
+			}
+		}
+	}
+
+	return ImportPolicy{}, false
+}
+
+// routeFamily returns the address family argument of the route.
+func routeFamily(r route) string {
+	if r.prefix.IP.To4() != nil {
+		return ip.FamilyV4
+	}
+
+	return ip.FamilyV6
+}
+
+// routeFlush removes the installed route.
+func routeFlush(r installedRoute) error {
+	ipRoute := &ip.Route{
+		Route:  r.prefix.String(),
+		Table:  r.table,
+		Proto:  routeProtocol,
+		Family: routeFamily(r.route),
+	}
+
+	return ipRoute.Flush()
+}
+
+// routesFlushAll removes the routes installed by LXD from all the routing tables.
+func routesFlushAll() error {
+	for _, family := range []string{ip.FamilyV4, ip.FamilyV6} {
+		ipRoute := &ip.Route{
+			Table:  "all",
+			Proto:  routeProtocol,
+			Family: family,
+		}
+
+		err := ipRoute.Flush()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pathPrefix returns the prefix of the path if it's an IP prefix.
+func pathPrefix(path *bgpAPI.Path) *net.IPNet {
+	nlri, err := path.GetNlri().UnmarshalNew()
+	if err != nil {
+		return nil
+	}
+
+	ipPrefix, ok := nlri.(*bgpAPI.IPAddressPrefix)
+	if !ok {
+		return nil
+	}
+
+	prefixIP := net.ParseIP(ipPrefix.Prefix)
+	if prefixIP == nil {
+		return nil
+	}
+
+	bits := 128
+	if prefixIP.To4() != nil {
+		prefixIP = prefixIP.To4()
+		bits = 32
+	}
+
+	return &net.IPNet{
+		IP:   prefixIP,
+		Mask: net.CIDRMask(int(ipPrefix.PrefixLen), bits),
+	}
+}
+
+// pathNexthop returns the next-hop of the path.
+func pathNexthop(path *bgpAPI.Path) net.IP {
+	for _, pattr := range path.GetPattrs() {
+		attr, err := pattr.UnmarshalNew()
+		if err != nil {
+			continue
+		}
+
+		switch a := attr.(type) {
+		case *bgpAPI.NextHopAttribute:
+			return net.ParseIP(a.NextHop)
+		case *bgpAPI.MpReachNLRIAttribute:
+			if len(a.NextHops) > 0 {
+				return net.ParseIP(a.NextHops[0])
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package bgp
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testSubnet parses a subnet for the tests.
+func testSubnet(t *testing.T, cidr string) net.IPNet {
+	t.Helper()
+
+	_, subnet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return *subnet
+}
+
+func Test_Server_routeAccepted(t *testing.T) {
+	peer1 := net.ParseIP("192.0.2.1")
+	peer2 := net.ParseIP("2001:db8::1")
+
+	s := &Server{
+		imports: map[string]ImportPolicy{
+			"lxdbr1": {
+				Peers:    []net.IP{peer1, peer2},
+				Prefixes: []net.IPNet{testSubnet(t, "10.100.0.0/16"), testSubnet(t, "2001:db8:100::/48")},
+				Device:   "lxdbr1",
+				Table:    "100",
+			},
+			"lxdbr0": {
+				Peers:    []net.IP{peer1},
+				Prefixes: []net.IPNet{testSubnet(t, "10.100.1.0/24")},
+				Device:   "lxdbr0",
+			},
+			"lxdbr2": {
+				Peers: []net.IP{peer1, peer2},
+			},
+			"lxdbr3": {
+				Peers:    []net.IP{peer1},
+				Prefixes: []net.IPNet{testSubnet(t, "0.0.0.0/0")},
+				Device:   "lxdbr3",
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		prefix     string
+		peer       net.IP
+		wantDevice string
+		wantTable  string
+		wantOK     bool
+	}{
+		{
+			name:       "Route within the prefixes",
+			prefix:     "10.100.2.0/24",
+			peer:       peer1,
+			wantDevice: "lxdbr1",
+			wantTable:  "100",
+			wantOK:     true,
+		},
+		{
+			name:       "Policies are checked in order",
+			prefix:     "10.100.1.0/24",
+			peer:       peer1,
+			wantDevice: "lxdbr0",
+			wantOK:     true,
+		},
+		{
+			name:       "IPv6 route",
+			prefix:     "2001:db8:100:1::/64",
+			peer:       peer2,
+			wantDevice: "lxdbr1",
+			wantTable:  "100",
+			wantOK:     true,
+		},
+		{
+			name:       "Default route explicitly allowed",
+			prefix:     "0.0.0.0/0",
+			peer:       peer1,
+			wantDevice: "lxdbr3",
+			wantOK:     true,
+		},
+		{
+			name:   "Default route not allowed",
+			prefix: "::/0",
+			peer:   peer2,
+		},
+		{
+			name:   "Route larger than the prefix",
+			prefix: "10.0.0.0/8",
+			peer:   peer2,
+		},
+		{
+			name:   "Route outside of the prefixes",
+			prefix: "10.200.0.0/24",
+			peer:   peer2,
+		},
+		{
+			name:   "Unknown peer",
+			prefix: "10.100.2.0/24",
+			peer:   net.ParseIP("192.0.2.2"),
+		},
+		{
+			name:   "IPv4-mapped IPv6 route",
+			prefix: "::ffff:10.100.2.0/120",
+			peer:   peer2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, ok := s.routeAccepted(route{prefix: testSubnet(t, tt.prefix), nexthop: tt.peer, peer: tt.peer})
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantDevice, policy.Device)
+			assert.Equal(t, tt.wantTable, policy.Table)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package bgp
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	bgpAPI "github.com/osrg/gobgp/v3/api"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// ExportPolicy represents the additional attributes of the announced prefixes.
+// These let the upstream routers prefer the prefixes announced by one server over another.
+type ExportPolicy struct {
+	Communities []uint32 // Standard communities to attach.
+	MED         uint32   // Multi-exit discriminator (not sent if zero).
+	Prepend     uint32   // Number of additional times the local ASN is prepended to the AS path.
+}
+
+// ParseCommunity parses a standard community in the ASN:VALUE format.
+func ParseCommunity(value string) (uint32, error) {
+	asn, val, found := strings.Cut(value, ":")
+	if !found {
+		return 0, fmt.Errorf("Invalid community %q (must be ASN:VALUE)", value)
+	}
+
+	asnInt, err := strconv.ParseUint(asn, 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid community ASN %q: %w", asn, err)
+	}
+
+	valInt, err := strconv.ParseUint(val, 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid community value %q: %w", val, err)
+	}
+
+	return uint32(asnInt)<<16 | uint32(valInt), nil
+}
+
+// SetExportPolicy sets the named export policy, announcing again the prefixes using it.
+// A nil policy removes it.
+func (s *Server) SetExportPolicy(name string, policy *ExportPolicy) error {
+	// Locking.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	oldPolicy, found := s.policies[name]
+	if policy == nil {
+		if !found {
+			return nil
+		}
+
+		delete(s.policies, name)
+	} else {
+		if found && oldPolicy.MED == policy.MED && oldPolicy.Prepend == policy.Prepend && slices.Equal(oldPolicy.Communities, policy.Communities) {
+			return nil
+		}
+
+		s.policies[name] = *policy
+	}
+
+	// Make a copy of the paths dict to safely iterate (path removal mutates it).
+	paths := map[string]path{}
+	maps.Copy(paths, s.paths)
+
+	// Announce the prefixes using the policy again with the new attributes.
+	for pathUUID, path := range paths {
+		if path.policy != name {
+			continue
+		}
+
+		err := s.removePrefixByUUID(pathUUID)
+		if err != nil {
+			return err
+		}
+
+		err = s.addPrefix(path.prefix, path.nexthop, path.owner, path.policy)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// policyAttributes returns the path attributes of the named export policy.
+func (s *Server) policyAttributes(name string) []*anypb.Any {
+	policy, found := s.policies[name]
+	if !found {
+		return nil
+	}
+
+	attrs := []*anypb.Any{}
+
+	if len(policy.Communities) > 0 {
+		aCommunities, _ := anypb.New(&bgpAPI.CommunitiesAttribute{
+			Communities: policy.Communities,
+		})
+
+		attrs = append(attrs, aCommunities)
+	}
+
+	if policy.MED > 0 {
+		aMED, _ := anypb.New(&bgpAPI.MultiExitDiscAttribute{
+			Med: policy.MED,
+		})
+
+		attrs = append(attrs, aMED)
+	}
+
+	// The local ASN is added once more when sending the prefix to the peers.
+	if policy.Prepend > 0 && s.asn > 0 {
+		numbers := make([]uint32, 0, policy.Prepend)
+		for range policy.Prepend {
+			numbers = append(numbers, s.asn)
+		}
+
+		aASPath, _ := anypb.New(&bgpAPI.AsPathAttribute{
+			Segments: []*bgpAPI.AsSegment{{
+				Type:    bgpAPI.AsSegment_AS_SEQUENCE,
+				Numbers: numbers,
+			}},
+		})
+
+		attrs = append(attrs, aASPath)
+	}
+
+	return attrs
+}
@@ -29,6 +29,17 @@ type Server struct {
 	routerID net.IP
 	paths    map[string]path
 	peers    map[string]peer
+	policies map[string]ExportPolicy
+
+	// Route import state.
+	imports     map[string]ImportPolicy
+	received    map[string]route
+	installed   map[string]installedRoute
+	watchCancel context.CancelFunc
+	importMu    sync.Mutex
+
+	// Whether the sessions of the peers using graceful restart can be established.
+	ready bool
 
 	mu sync.Mutex
 }
@@ -37,22 +48,38 @@ type path struct {
 	owner   string
 	prefix  net.IPNet
 	nexthop net.IP
+	policy  string
 }
 
 type peer struct {
 	address  net.IP
 	asn      uint32
 	password string
-	holdtime uint64
+	opts     PeerOptions
 	count    int
 }
 
+// DefaultHoldTime is the hold time in seconds of the peer sessions that don't set one.
+const DefaultHoldTime = 180
+
+// PeerOptions represents the optional settings of a BGP peer session.
+type PeerOptions struct {
+	HoldTime            uint64 // Hold time in seconds (DefaultHoldTime if zero).
+	KeepaliveInterval   uint64 // Keepalive interval in seconds (a third of the hold time if zero).
+	GracefulRestart     bool   // Whether the peer keeps the routes while the session is re-established.
+	GracefulRestartTime uint32 // Time in seconds the peer keeps the routes for while waiting for the session.
+}
+
 // NewServer returns a new server instance.
 func NewServer() *Server {
 	// Setup new struct.
 	s := &Server{
-		paths: map[string]path{},
-		peers: map[string]peer{},
+		paths:     map[string]path{},
+		peers:     map[string]peer{},
+		policies:  map[string]ExportPolicy{},
+		imports:   map[string]ImportPolicy{},
+		received:  map[string]route{},
+		installed: map[string]installedRoute{},
 	}
 
 	return s
@@ -109,6 +136,17 @@ func (s *Server) start(address string, asn uint32, routerID net.IP) error {
 		return err
 	}
 
+	// Record the address.
+	s.address = address
+	s.asn = asn
+	s.routerID = routerID
+
+	// Watch the best paths to import the received routes.
+	err = s.watch()
+	if err != nil {
+		return err
+	}
+
 	// Copy the path list
 	oldPaths := map[string]path{}
 	maps.Copy(oldPaths, s.paths)
@@ -116,7 +154,7 @@ func (s *Server) start(address string, asn uint32, routerID net.IP) error {
 	// Add existing paths.
 	s.paths = map[string]path{}
 	for _, path := range oldPaths {
-		err := s.addPrefix(path.prefix, path.nexthop, path.owner)
+		err := s.addPrefix(path.prefix, path.nexthop, path.owner, path.policy)
 		if err != nil {
 			logger.Warn("Unable to add prefix to BGP server", logger.Ctx{"prefix": path.prefix.String(), "err": err})
 		}
@@ -129,17 +167,12 @@ func (s *Server) start(address string, asn uint32, routerID net.IP) error {
 	// Add existing peers.
 	s.peers = map[string]peer{}
 	for _, peer := range oldPeers {
-		err := s.addPeer(peer.address, peer.asn, peer.password, peer.holdtime)
+		err := s.addPeer(peer.address, peer.asn, peer.password, peer.opts)
 		if err != nil {
 			return err
 		}
 	}
 
-	// Record the address.
-	s.address = address
-	s.asn = asn
-	s.routerID = routerID
-
 	return nil
 }
 
@@ -165,6 +198,9 @@ func (s *Server) stop() error {
 	// Restore peer list.
 	s.peers = oldPeers
 
+	// Stop importing routes.
+	s.unwatch()
+
 	// Stop the listener.
 	err := s.bgp.StopBgp(context.Background(), &bgpAPI.StopBgpRequest{})
 	if err != nil {
@@ -223,15 +259,16 @@ func (s *Server) configure(address string, asn uint32, routerID net.IP) error {
 }
 
 // AddPrefix adds a new prefix to the BGP server.
-func (s *Server) AddPrefix(subnet net.IPNet, nexthop net.IP, owner string) error {
+// The prefix is announced with the attributes of the named export policy (if any).
+func (s *Server) AddPrefix(subnet net.IPNet, nexthop net.IP, owner string, policy string) error {
 	// Locking.
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	return s.addPrefix(subnet, nexthop, owner)
+	return s.addPrefix(subnet, nexthop, owner, policy)
 }
 
-func (s *Server) addPrefix(subnet net.IPNet, nexthop net.IP, owner string) error {
+func (s *Server) addPrefix(subnet net.IPNet, nexthop net.IP, owner string, policy string) error {
 	// Prepare the prefix.
 	prefixLen, _ := subnet.Mask.Size()
 	prefix := subnet.IP.String()
@@ -245,6 +282,9 @@ func (s *Server) addPrefix(subnet net.IPNet, nexthop net.IP, owner string) error
 		Origin: 0,
 	})
 
+	// Get the attributes from the export policy.
+	policyAttrs := s.policyAttributes(policy)
+
 	// Add the prefix to the server.
 	var pathUUID string
 	if s.bgp != nil {
@@ -258,7 +298,7 @@ func (s *Server) addPrefix(subnet net.IPNet, nexthop net.IP, owner string) error
 				Path: &bgpAPI.Path{
 					Family: &bgpAPI.Family{Afi: bgpAPI.Family_AFI_IP, Safi: bgpAPI.Family_SAFI_UNICAST},
 					Nlri:   nlri,
-					Pattrs: []*anypb.Any{aOrigin, aNextHop},
+					Pattrs: append([]*anypb.Any{aOrigin, aNextHop}, policyAttrs...),
 				},
 			})
 			if err != nil {
@@ -283,7 +323,7 @@ func (s *Server) addPrefix(subnet net.IPNet, nexthop net.IP, owner string) error
 				Path: &bgpAPI.Path{
 					Family: family,
 					Nlri:   nlri,
-					Pattrs: []*anypb.Any{aOrigin, v6Attrs},
+					Pattrs: append([]*anypb.Any{aOrigin, v6Attrs}, policyAttrs...),
 				},
 			})
 			if err != nil {
@@ -302,6 +342,7 @@ func (s *Server) addPrefix(subnet net.IPNet, nexthop net.IP, owner string) error
 		prefix:  subnet,
 		nexthop: nexthop,
 		owner:   owner,
+		policy:  policy,
 	}
 
 	return nil
@@ -378,15 +419,15 @@ func (s *Server) removePrefixByUUID(pathUUID string) error {
 }
 
 // AddPeer adds a new BGP peer.
-func (s *Server) AddPeer(address net.IP, asn uint32, password string, holdTime uint64) error {
+func (s *Server) AddPeer(address net.IP, asn uint32, password string, opts PeerOptions) error {
 	// Locking.
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	return s.addPeer(address, asn, password, holdTime)
+	return s.addPeer(address, asn, password, opts)
 }
 
-func (s *Server) addPeer(address net.IP, asn uint32, password string, holdTime uint64) error {
+func (s *Server) addPeer(address net.IP, asn uint32, password string, opts PeerOptions) error {
 	// Look for an existing peer.
 	bgpPeer, bgpPeerExists := s.peers[address.String()]
 	if bgpPeerExists {
@@ -398,12 +439,43 @@ func (s *Server) addPeer(address net.IP, asn uint32, password string, holdTime u
 			return fmt.Errorf("Peer %q already used but with a different password", address)
 		}
 
+		if bgpPeer.opts != opts {
+			return fmt.Errorf("Peer %q already used but with different session settings", address)
+		}
+
 		// Re-use the existing entry.
 		bgpPeer.count++
 		s.peers[address.String()] = bgpPeer
 		return nil
 	}
 
+	// Add the peer.
+	if s.bgp != nil && !s.peerHeld(opts) {
+		n, err := peerConfig(address, asn, password, opts)
+		if err != nil {
+			return err
+		}
+
+		err = s.bgp.AddPeer(context.Background(), &bgpAPI.AddPeerRequest{Peer: n})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Add the peer to the list.
+	s.peers[address.String()] = peer{
+		address:  address,
+		asn:      asn,
+		password: password,
+		opts:     opts,
+		count:    1,
+	}
+
+	return nil
+}
+
+// peerConfig returns the GoBGP configuration of the peer.
+func peerConfig(address net.IP, asn uint32, password string, opts PeerOptions) (*bgpAPI.Peer, error) {
 	// Setup the configuration.
 	n := &bgpAPI.Peer{
 		// Peer information.
@@ -413,12 +485,6 @@ func (s *Server) addPeer(address net.IP, asn uint32, password string, holdTime u
 			AuthPassword:    password,
 		},
 
-		// Allow for 120s offline before route removal.
-		GracefulRestart: &bgpAPI.GracefulRestart{
-			Enabled:     true,
-			RestartTime: 3600,
-		},
-
 		// Always allow for the maximum multihop.
 		EbgpMultihop: &bgpAPI.EbgpMultihop{
 			Enabled:     true,
@@ -426,21 +492,33 @@ func (s *Server) addPeer(address net.IP, asn uint32, password string, holdTime u
 		},
 	}
 
-	// Add hold time if configured.
-	if holdTime > 0 {
-		n.Timers = &bgpAPI.Timers{
-			Config: &bgpAPI.TimersConfig{
-				HoldTime: holdTime,
-			},
+	// Let the peer keep the routes while LXD restarts.
+	if opts.GracefulRestart {
+		n.GracefulRestart = &bgpAPI.GracefulRestart{
+			Enabled:     true,
+			RestartTime: opts.GracefulRestartTime,
 		}
 	}
 
+	// Setup the timers.
+	holdTime := opts.HoldTime
+	if holdTime == 0 {
+		holdTime = DefaultHoldTime
+	}
+
+	n.Timers = &bgpAPI.Timers{
+		Config: &bgpAPI.TimersConfig{
+			HoldTime:          holdTime,
+			KeepaliveInterval: opts.KeepaliveInterval,
+		},
+	}
+
 	// Setup peer for dual-stack.
 	n.AfiSafis = make([]*bgpAPI.AfiSafi, 0)
 	for _, f := range []string{"ipv4-unicast", "ipv6-unicast"} {
 		rf, err := bgpPacket.GetRouteFamily(f)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		afi, safi := bgpPacket.RouteFamilyToAfiSafi(rf)
@@ -452,36 +530,14 @@ func (s *Server) addPeer(address net.IP, asn uint32, password string, holdTime u
 		n.AfiSafis = append(n.AfiSafis, &bgpAPI.AfiSafi{
 			MpGracefulRestart: &bgpAPI.MpGracefulRestart{
 				Config: &bgpAPI.MpGracefulRestartConfig{
-					Enabled: true,
+					Enabled: opts.GracefulRestart,
 				},
 			},
 			Config: &bgpAPI.AfiSafiConfig{Family: family},
 		})
 	}
 
-	// Add the peer.
-	if s.bgp != nil {
-		err := s.bgp.AddPeer(context.Background(), &bgpAPI.AddPeerRequest{Peer: n})
-		if err != nil {
-			return err
-		}
-	}
-
-	// Add the peer to the list.
-	if bgpPeerExists {
-		bgpPeer.count++
-		s.peers[address.String()] = bgpPeer
-	} else {
-		s.peers[address.String()] = peer{
-			address:  address,
-			asn:      asn,
-			password: password,
-			holdtime: holdTime,
-			count:    1,
-		}
-	}
-
-	return nil
+	return n, nil
 }
 
 // RemovePeer removes a prefix from the BGP server.
@@ -501,7 +557,7 @@ func (s *Server) removePeer(address net.IP) error {
 	}
 
 	// Remove the peer from the BGP server.
-	if s.bgp != nil && bgpPeer.count == 1 {
+	if s.bgp != nil && !s.peerHeld(bgpPeer.opts) && bgpPeer.count == 1 {
 		err := s.bgp.DeletePeer(context.Background(), &bgpAPI.DeletePeerRequest{Address: address.String()})
 		if err != nil {
 			return err
@@ -520,3 +576,46 @@ func (s *Server) removePeer(address net.IP) error {
 
 	return nil
 }
+
+// peerHeld returns whether the session with a peer is held back until Ready is called.
+// Only the peers using graceful restart are held back, as they keep the previous routes in the meantime.
+func (s *Server) peerHeld(opts PeerOptions) bool {
+	return opts.GracefulRestart && !s.ready
+}
+
+// Ready establishes the sessions with the peers using graceful restart.
+// Until then, these peers are only recorded so that the routes they keep during a graceful restart are only
+// refreshed once all the prefixes have been added back.
+func (s *Server) Ready() error {
+	// Locking.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ready {
+		return nil
+	}
+
+	s.ready = true
+
+	if s.bgp == nil {
+		return nil
+	}
+
+	for _, peer := range s.peers {
+		if !peer.opts.GracefulRestart {
+			continue
+		}
+
+		n, err := peerConfig(peer.address, peer.asn, peer.password, peer.opts)
+		if err != nil {
+			return err
+		}
+
+		err = s.bgp.AddPeer(context.Background(), &bgpAPI.AddPeerRequest{Peer: n})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
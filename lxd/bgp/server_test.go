@@ -0,0 +1,94 @@
+package bgp
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Server_addPeer(t *testing.T) {
+	s := NewServer()
+	address := net.ParseIP("192.0.2.1")
+	opts := PeerOptions{HoldTime: 9, KeepaliveInterval: 3}
+
+	require.NoError(t, s.AddPeer(address, 65000, "", opts))
+
+	// Networks sharing the peer must use the same settings.
+	require.NoError(t, s.AddPeer(address, 65000, "", opts))
+	assert.Equal(t, 2, s.peers[address.String()].count)
+
+	err := s.AddPeer(address, 65001, "", opts)
+	assert.ErrorContains(t, err, "differing ASN")
+
+	err = s.AddPeer(address, 65000, "secret", opts)
+	assert.ErrorContains(t, err, "different password")
+
+	err = s.AddPeer(address, 65000, "", PeerOptions{HoldTime: 30})
+	assert.ErrorContains(t, err, "different session settings")
+	assert.Equal(t, opts, s.peers[address.String()].opts)
+
+	// Changing the settings works once the peer isn't shared anymore.
+	require.NoError(t, s.RemovePeer(address))
+	require.NoError(t, s.RemovePeer(address))
+	require.NoError(t, s.AddPeer(address, 65000, "", PeerOptions{HoldTime: 30}))
+	assert.Equal(t, PeerOptions{HoldTime: 30}, s.peers[address.String()].opts)
+}
+
+func Test_Server_peerHeld(t *testing.T) {
+	s := NewServer()
+
+	// Only the sessions using graceful restart are held back until ready.
+	assert.True(t, s.peerHeld(PeerOptions{GracefulRestart: true}))
+	assert.False(t, s.peerHeld(PeerOptions{}))
+
+	require.NoError(t, s.Ready())
+	assert.False(t, s.peerHeld(PeerOptions{GracefulRestart: true}))
+	assert.False(t, s.peerHeld(PeerOptions{}))
+}
+
+func Test_peerConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          PeerOptions
+		wantHoldTime  uint64
+		wantKeepalive uint64
+	}{
+		{
+			name:         "Default hold time",
+			opts:         PeerOptions{},
+			wantHoldTime: DefaultHoldTime,
+		},
+		{
+			name:          "Keepalive with the default hold time",
+			opts:          PeerOptions{KeepaliveInterval: 10},
+			wantHoldTime:  DefaultHoldTime,
+			wantKeepalive: 10,
+		},
+		{
+			name:          "Custom timers",
+			opts:          PeerOptions{HoldTime: 9, KeepaliveInterval: 3},
+			wantHoldTime:  9,
+			wantKeepalive: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := peerConfig(net.ParseIP("192.0.2.1"), 65000, "", tt.opts)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantHoldTime, n.Timers.Config.HoldTime)
+			assert.Equal(t, tt.wantKeepalive, n.Timers.Config.KeepaliveInterval)
+			assert.Nil(t, n.GracefulRestart)
+		})
+	}
+
+	n, err := peerConfig(net.ParseIP("192.0.2.1"), 65000, "", PeerOptions{GracefulRestart: true, GracefulRestartTime: 120})
+	require.NoError(t, err)
+	require.NotNil(t, n.GracefulRestart)
+	assert.Equal(t, uint32(120), n.GracefulRestart.RestartTime)
+	for _, afiSafi := range n.AfiSafis {
+		assert.True(t, afiSafi.MpGracefulRestart.Config.Enabled)
+	}
+}
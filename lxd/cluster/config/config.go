@@ -261,6 +261,23 @@ func (c *Config) ClusterHealingThreshold() time.Duration {
 	return healingThreshold
 }
 
+// ClusterRebalanceInterval returns the interval between automatic cluster rebalancing runs.
+// If automatic rebalancing is disabled, it returns 0.
+func (c *Config) ClusterRebalanceInterval() time.Duration {
+	return time.Duration(c.m.GetInt64("cluster.rebalance.interval")) * time.Minute
+}
+
+// ClusterRebalanceThreshold returns the difference in memory usage (in percent) between cluster members
+// above which instances are moved to rebalance the load.
+func (c *Config) ClusterRebalanceThreshold() int64 {
+	return c.m.GetInt64("cluster.rebalance.threshold")
+}
+
+// ClusterRebalanceBatch returns the maximum number of instances moved concurrently when rebalancing the cluster.
+func (c *Config) ClusterRebalanceBatch() int64 {
+	return c.m.GetInt64("cluster.rebalance.batch")
+}
+
 // Dump current configuration keys and their values. Keys with values matching
 // their defaults are omitted.
 func (c *Config) Dump() map[string]string {
@@ -401,6 +418,36 @@ var ConfigSchema = config.Schema{
 		//  shortdesc: Number of database stand-by members
 		"cluster.max_standby": {Type: config.Int64, Default: "2", Validator: maxStandByValidator},
 
+		// lxdmeta:generate(entities=server; group=cluster; key=cluster.rebalance.interval)
+		// Specify the number of minutes between automatic cluster rebalancing runs.
+		// Only instances in projects with {config:option}`project-specific:cluster.rebalance` enabled are moved.
+		// To disable automatic rebalancing, set this option to `0`.
+		// ---
+		//  type: integer
+		//  scope: global
+		//  defaultdesc: `0`
+		//  shortdesc: Interval between automatic cluster rebalancing runs
+		"cluster.rebalance.interval": {Type: config.Int64, Default: "0", Validator: validate.Optional(validate.IsInRange(0, 525600))},
+
+		// lxdmeta:generate(entities=server; group=cluster; key=cluster.rebalance.threshold)
+		// Specify the difference in memory usage (in percent) between the most and least loaded cluster members
+		// above which instances are moved to rebalance the load.
+		// ---
+		//  type: integer
+		//  scope: global
+		//  defaultdesc: `20`
+		//  shortdesc: Load imbalance that triggers instance moves
+		"cluster.rebalance.threshold": {Type: config.Int64, Default: "20", Validator: validate.Optional(validate.IsInRange(1, 100))},
+
+		// lxdmeta:generate(entities=server; group=cluster; key=cluster.rebalance.batch)
+		// Specify the maximum number of instances that are moved concurrently when rebalancing the cluster.
+		// ---
+		//  type: integer
+		//  scope: global
+		//  defaultdesc: `1`
+		//  shortdesc: Maximum number of concurrent rebalancing moves
+		"cluster.rebalance.batch": {Type: config.Int64, Default: "1", Validator: validate.Optional(validate.IsInRange(1, 100))},
+
 		// lxdmeta:generate(entities=server; group=core; key=core.metrics_authentication)
 		//
 		// ---
@@ -0,0 +1,546 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/placement"
+	"github.com/canonical/lxd/lxd/project/limits"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/revert"
+	"github.com/canonical/lxd/shared/units"
+)
+
+var clusterRebalanceCmd = APIEndpoint{
+	Path:        "cluster/rebalance",
+	MetricsType: entity.TypeClusterMember,
+
+	Get:  APIEndpointAction{Handler: clusterRebalanceGet, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
+	Post: APIEndpointAction{Handler: clusterRebalancePost, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
+}
+
+// swagger:operation GET /1.0/cluster/rebalance cluster cluster_rebalance_get
+//
+//	Get the planned cluster rebalancing moves
+//
+//	Returns the instance moves that a cluster rebalancing run would perform, without performing them.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Cluster rebalancing plan
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/ClusterRebalance"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func clusterRebalanceGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	if !s.ServerClustered {
+		return response.BadRequest(errors.New("This server is not clustered"))
+	}
+
+	moves, err := clusterRebalancePlan(r.Context(), s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, api.ClusterRebalance{Moves: moves})
+}
+
+// swagger:operation POST /1.0/cluster/rebalance cluster cluster_rebalance_post
+//
+//	Rebalance the cluster
+//
+//	Moves instances between cluster members to fix placement group violations and load imbalance.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func clusterRebalancePost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	if !s.ServerClustered {
+		return response.BadRequest(errors.New("This server is not clustered"))
+	}
+
+	run := func(op *operations.Operation) error {
+		return clusterRebalance(context.Background(), s, op)
+	}
+
+	op, err := operations.OperationCreate(r.Context(), s, "", operations.OperationClassTask, operationtype.ClusterRebalance, nil, nil, run, nil, nil)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+func clusterRebalanceTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := stateFunc()
+
+		leaderInfo, err := s.LeaderInfo()
+		if err != nil {
+			logger.Error("Failed determining cluster leader", logger.Ctx{"err": err})
+			return
+		}
+
+		if !leaderInfo.Clustered || !leaderInfo.Leader {
+			return
+		}
+
+		opRun := func(op *operations.Operation) error {
+			return clusterRebalance(ctx, s, op)
+		}
+
+		op, err := operations.OperationCreate(context.Background(), s, "", operations.OperationClassTask, operationtype.ClusterRebalance, nil, nil, opRun, nil, nil)
+		if err != nil {
+			logger.Error("Failed creating cluster rebalance operation", logger.Ctx{"err": err})
+			return
+		}
+
+		err = op.Start()
+		if err != nil {
+			logger.Error("Failed starting cluster rebalance operation", logger.Ctx{"err": err})
+			return
+		}
+
+		err = op.Wait(ctx)
+		if err != nil {
+			logger.Error("Failed rebalancing cluster", logger.Ctx{"err": err})
+			return
+		}
+	}
+
+	// Wait for a full interval before the first run so that restarting the leader doesn't trigger a rebalance.
+	skipNext := true
+	schedule := func() (time.Duration, error) {
+		interval := stateFunc().GlobalConfig.ClusterRebalanceInterval()
+		if interval == 0 {
+			// Check again later in case automatic rebalancing gets enabled.
+			skipNext = true
+			return time.Minute, task.ErrSkip
+		}
+
+		if skipNext {
+			skipNext = false
+			return interval, task.ErrSkip
+		}
+
+		return interval, nil
+	}
+
+	return f, schedule
+}
+
+// clusterRebalance plans and performs the instance moves needed to rebalance the cluster.
+// At most cluster.rebalance.batch instances are moved concurrently.
+func clusterRebalance(ctx context.Context, s *state.State, op *operations.Operation) error {
+	moves, err := clusterRebalancePlan(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	if len(moves) == 0 {
+		return nil
+	}
+
+	logger.Info("Rebalancing cluster", logger.Ctx{"moves": len(moves)})
+	defer logger.Info("Done rebalancing cluster")
+
+	batch := s.GlobalConfig.ClusterRebalanceBatch()
+	if batch < 1 {
+		batch = 1
+	}
+
+	sem := make(chan struct{}, batch)
+	wg := sync.WaitGroup{}
+	var errsMu sync.Mutex
+	var errs []error
+
+	for _, move := range moves {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(move api.ClusterRebalanceMove) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			_ = op.ExtendMetadata(map[string]any{"rebalance_progress": fmt.Sprintf("Moving %q in project %q from %q to %q", move.Instance, move.Project, move.Source, move.Target)})
+
+			err := clusterRebalanceMove(ctx, s, move)
+			if err != nil {
+				logger.Error("Failed moving instance during cluster rebalance", logger.Ctx{"project": move.Project, "instance": move.Instance, "source": move.Source, "target": move.Target, "err": err})
+
+				errsMu.Lock()
+				errs = append(errs, fmt.Errorf("Failed moving instance %q in project %q to %q: %w", move.Instance, move.Project, move.Target, err))
+				errsMu.Unlock()
+			}
+		}(move)
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// clusterRebalanceMove moves a single instance as planned by [clusterRebalancePlan].
+// Instances that can't be live-migrated are stopped before the move and started again afterwards.
+func clusterRebalanceMove(ctx context.Context, s *state.State, move api.ClusterRebalanceMove) error {
+	var sourceMember db.NodeInfo
+	var targetMember db.NodeInfo
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		sourceMember, err = tx.GetNodeByName(ctx, move.Source)
+		if err != nil {
+			return fmt.Errorf("Failed getting cluster member %q: %w", move.Source, err)
+		}
+
+		targetMember, err = tx.GetNodeByName(ctx, move.Target)
+		if err != nil {
+			return fmt.Errorf("Failed getting cluster member %q: %w", move.Target, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	source, err := cluster.Connect(ctx, sourceMember.Address, s.Endpoints.NetworkCert(), s.ServerCert(), true)
+	if err != nil {
+		return fmt.Errorf("Failed connecting to source: %w", err)
+	}
+
+	source = source.UseProject(move.Project)
+
+	apiInst, _, err := source.GetInstance(move.Instance)
+	if err != nil {
+		return fmt.Errorf("Failed getting instance: %w", err)
+	}
+
+	// Skip instances that have been moved since the plan was made.
+	if apiInst.Location != move.Source {
+		return nil
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	isRunning := apiInst.StatusCode == api.Running
+	if isRunning && !move.Live {
+		timeout, err := strconv.Atoi(apiInst.ExpandedConfig["boot.host_shutdown_timeout"])
+		if err != nil {
+			timeout = evacuateHostShutdownDefaultTimeout
+		}
+
+		stopOp, err := source.UpdateInstanceState(move.Instance, api.InstanceStatePut{Action: "stop", Timeout: timeout}, "")
+		if err != nil {
+			return fmt.Errorf("Failed stopping instance: %w", err)
+		}
+
+		err = stopOp.Wait()
+		if err != nil {
+			// On failure, attempt a forceful stop.
+			stopOp, err = source.UpdateInstanceState(move.Instance, api.InstanceStatePut{Action: "stop", Force: true}, "")
+			if err != nil {
+				return fmt.Errorf("Failed stopping instance: %w", err)
+			}
+
+			err = stopOp.Wait()
+			if err != nil && !strings.Contains(err.Error(), "The instance is already stopped") {
+				return fmt.Errorf("Failed stopping instance: %w", err)
+			}
+		}
+
+		// Start the instance again on the source if it can't be moved.
+		reverter.Add(func() {
+			startOp, err := source.UpdateInstanceState(move.Instance, api.InstanceStatePut{Action: "start"}, "")
+			if err == nil {
+				err = startOp.Wait()
+			}
+
+			if err != nil {
+				logger.Warn("Failed starting instance again after failed rebalancing move", logger.Ctx{"project": move.Project, "instance": move.Instance, "member": move.Source, "err": err})
+			}
+		})
+	}
+
+	req := api.InstancePost{
+		Name:      move.Instance,
+		Migration: true,
+		Live:      isRunning && move.Live,
+	}
+
+	migrateOp, err := source.UseTarget(targetMember.Name).MigrateInstance(move.Instance, req)
+	if err != nil {
+		return fmt.Errorf("Migration API failure: %w", err)
+	}
+
+	err = migrateOp.Wait()
+	if err != nil {
+		return fmt.Errorf("Failed waiting for migration to finish: %w", err)
+	}
+
+	// The instance is now on the target, so it must be started there.
+	reverter.Success()
+
+	if !isRunning || move.Live {
+		return nil
+	}
+
+	// Start it back up on target.
+	dest, err := cluster.Connect(ctx, targetMember.Address, s.Endpoints.NetworkCert(), s.ServerCert(), true)
+	if err != nil {
+		return fmt.Errorf("Failed connecting to destination: %w", err)
+	}
+
+	dest = dest.UseProject(move.Project)
+
+	startOp, err := dest.UpdateInstanceState(move.Instance, api.InstanceStatePut{Action: "start"}, "")
+	if err != nil {
+		return fmt.Errorf("Failed starting instance: %w", err)
+	}
+
+	return startOp.Wait()
+}
+
+// clusterRebalancePlan returns the instance moves needed to fix placement group violations and memory usage
+// imbalance between cluster members. Only instances in projects with cluster.rebalance enabled are considered.
+func clusterRebalancePlan(ctx context.Context, s *state.State) ([]api.ClusterRebalanceMove, error) {
+	var allMembers []db.NodeInfo
+	var onlineMembers []db.NodeInfo
+	var dbInstances []db.InstanceArgs
+	projects := make(map[string]api.Project)
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		allMembers, err = tx.GetNodes(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed getting cluster members: %w", err)
+		}
+
+		onlineMembers, err = tx.GetCandidateMembers(ctx, allMembers, nil, "", nil, s.GlobalConfig.OfflineThreshold())
+		if err != nil {
+			return err
+		}
+
+		return tx.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
+			if !shared.IsTrue(p.Config["cluster.rebalance"]) || dbInst.Snapshot {
+				return nil
+			}
+
+			dbInstances = append(dbInstances, dbInst)
+			projects[p.Name] = p
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(dbInstances) == 0 {
+		return nil, nil
+	}
+
+	// Get the memory usage of each online cluster member.
+	members := make([]placement.RebalanceMember, 0, len(onlineMembers))
+	onlineMemberIDs := make(map[int]bool, len(onlineMembers))
+	for _, member := range onlineMembers {
+		client, err := cluster.Connect(ctx, member.Address, s.Endpoints.NetworkCert(), s.ServerCert(), true)
+		if err != nil {
+			return nil, fmt.Errorf("Failed connecting to cluster member %q: %w", member.Name, err)
+		}
+
+		memberState, _, err := client.GetClusterMemberState(member.Name)
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting state of cluster member %q: %w", member.Name, err)
+		}
+
+		members = append(members, placement.RebalanceMember{
+			Info:        member,
+			TotalMemory: memberState.SysInfo.TotalRAM,
+			UsedMemory:  memberState.SysInfo.TotalRAM - min(memberState.SysInfo.TotalRAM, memberState.SysInfo.FreeRAM),
+		})
+
+		onlineMemberIDs[int(member.ID)] = true
+	}
+
+	memberByName := make(map[string]placement.RebalanceMember, len(members))
+	for _, member := range members {
+		memberByName[member.Info.Name] = member
+	}
+
+	// Load the instances outside of the transaction as checking whether they can be migrated may access the database.
+	instances := make([]placement.RebalanceInstance, 0, len(dbInstances))
+	loadedInstances := make([]instance.Instance, 0, len(dbInstances))
+	instanceCounts := make(map[int]int)
+	for _, dbInst := range dbInstances {
+		inst, err := instance.Load(s, dbInst, projects[dbInst.Project])
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading instance %q in project %q: %w", dbInst.Name, dbInst.Project, err)
+		}
+
+		migrate, live := inst.CanMigrate()
+		member, online := memberByName[dbInst.Node]
+
+		rebalanceInst := placement.RebalanceInstance{
+			ID:       dbInst.ID,
+			Name:     dbInst.Name,
+			Project:  dbInst.Project,
+			MemberID: int(member.Info.ID),
+			Movable:  migrate && online,
+			Live:     live,
+		}
+
+		if !online {
+			// Keep the instance to account for it in its placement group.
+			for _, m := range allMembers {
+				if m.Name == dbInst.Node {
+					rebalanceInst.MemberID = int(m.ID)
+					break
+				}
+			}
+		}
+
+		// Estimate the memory usage of the instance from its memory limit.
+		limit := inst.ExpandedConfig()["limits.memory"]
+		if strings.HasSuffix(limit, "%") {
+			percentage, err := strconv.ParseFloat(strings.TrimSuffix(limit, "%"), 64)
+			if err == nil {
+				rebalanceInst.Memory = uint64(float64(member.TotalMemory) * percentage / 100)
+			}
+		} else if limit != "" {
+			bytes, err := units.ParseByteSizeString(limit)
+			if err == nil && bytes > 0 {
+				rebalanceInst.Memory = uint64(bytes)
+			}
+		}
+
+		instances = append(instances, rebalanceInst)
+		loadedInstances = append(loadedInstances, inst)
+		instanceCounts[rebalanceInst.MemberID]++
+	}
+
+	// Instances without a memory limit are assumed to use an equal share of the memory used on their cluster member.
+	for i, inst := range instances {
+		if inst.Memory > 0 || !onlineMemberIDs[inst.MemberID] {
+			continue
+		}
+
+		for _, member := range members {
+			if int(member.Info.ID) == inst.MemberID {
+				instances[i].Memory = member.UsedMemory / uint64(instanceCounts[inst.MemberID])
+				break
+			}
+		}
+	}
+
+	// Get the cluster members each instance may be moved to and load the placement groups of the instances.
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		pgCache := placement.NewCache()
+		groups := make(map[string]*placement.RebalanceGroup)
+		for i, inst := range loadedInstances {
+			// Placement groups and cluster group targets are mutually exclusive, with placement groups taking precedence.
+			instProject := inst.Project()
+			clusterGroupsAllowed := limits.GetRestrictedClusterGroups(&instProject)
+			_, clusterGroupName := limits.TargetDetect(inst.LocalConfig()["volatile.cluster.group"])
+			placementGroupName := inst.ExpandedConfig()["placement.group"]
+			if placementGroupName != "" {
+				clusterGroupName = ""
+			}
+
+			var err error
+			instances[i].Candidates, err = tx.GetCandidateMembers(ctx, allMembers, []int{inst.Architecture()}, clusterGroupName, clusterGroupsAllowed, s.GlobalConfig.OfflineThreshold())
+			if err != nil {
+				return err
+			}
+
+			if placementGroupName == "" {
+				continue
+			}
+
+			key := instProject.Name + "/" + placementGroupName
+			group, ok := groups[key]
+			if !ok {
+				placementGroup, err := pgCache.Get(ctx, tx, placementGroupName, instProject.Name)
+				if err != nil {
+					return err
+				}
+
+				apiPlacementGroup, err := placementGroup.ToAPI(ctx, tx.Tx())
+				if err != nil {
+					return err
+				}
+
+				group, err = placement.NewRebalanceGroup(ctx, tx, *apiPlacementGroup)
+				if err != nil {
+					return err
+				}
+
+				groups[key] = group
+			}
+
+			instances[i].Group = group
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return placement.PlanRebalance(members, instances, float64(s.GlobalConfig.ClusterRebalanceThreshold())), nil
+}
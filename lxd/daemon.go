@@ -1826,6 +1826,8 @@ func (d *Daemon) init() error {
 		logger.Info("Started BGP server")
 	}
 
+	d.dns.SetAuthoritative(d.localConfig.DNSAuthoritative())
+
 	dnsAddress := d.localConfig.DNSAddress()
 	if dnsAddress != "" {
 		err = d.dns.Start(dnsAddress)
@@ -2012,6 +2014,12 @@ func (d *Daemon) init() error {
 		// Prune expired custom volume snapshots and take snapshots of custom volumes (minutely check of configurable cron expression)
 		d.tasks.Add(pruneExpiredAndAutoCreateCustomVolumeSnapshotsTask(d.State))
 
+		// Take backups of instances and custom volumes and prune the oldest ones (minutely check of configurable cron expression)
+		d.tasks.Add(autoCreateAndPruneBackupsTask(d.State))
+
+		// Replicate instances to the target of their storage pool (minutely check of configurable cron expression)
+		d.tasks.Add(autoReplicateInstancesTask(d.State))
+
 		// Remove resolved warnings (daily)
 		d.tasks.Add(pruneResolvedWarningsTask(d.State))
 
@@ -2020,6 +2028,15 @@ func (d *Daemon) init() error {
 
 		// Remove expired tokens (hourly)
 		d.tasks.Add(autoRemoveExpiredTokensTask(d.State))
+
+		// Refresh the cached network zones and notify the DNS peers of changed ones (minutely)
+		d.tasks.Add(networkZonesRefreshTask(d.State))
+
+		// Apply the network ACLs whose time-bound rules became active or inactive (minutely)
+		d.tasks.Add(networkACLTimeBoundRulesTask(d.State))
+
+		// Gather the storage volume usage metrics (every 5 minutes)
+		d.tasks.Add(storagePoolUsageMetricsTask(d.State))
 	}
 
 	// Start all background tasks
@@ -2031,6 +2048,13 @@ func (d *Daemon) init() error {
 	// Restore instances
 	instancesStart(d.State(), instances)
 
+	// Establish the BGP sessions using graceful restart now that the prefixes of the networks and instances
+	// have been added back.
+	err = d.bgp.Ready()
+	if err != nil {
+		logger.Error("Failed establishing BGP sessions", logger.Ctx{"err": err})
+	}
+
 	// Re-balance in case things changed while LXD was down
 	deviceTaskBalance(d.State())
 
@@ -2061,6 +2085,9 @@ func (d *Daemon) startClusterTasks() {
 	// Perform automatic evacuation for offline cluster members
 	d.clusterTasks.Add(autoHealClusterTask(d.State, d.gateway))
 
+	// Rebalance instances across cluster members
+	d.clusterTasks.Add(clusterRebalanceTask(d.State))
+
 	// Remove expired OIDC sessions
 	d.clusterTasks.Add(pruneExpiredOIDCSessionsTask(d.State))
 
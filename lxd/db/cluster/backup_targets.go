@@ -0,0 +1,127 @@
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// Code generation directives.
+//
+//go:generate -command mapper lxd-generate db mapper -t backup_targets.mapper.go
+//go:generate mapper reset -i -b "//go:build linux && cgo && !agent"
+//
+//go:generate mapper stmt -e backup_target objects table=backup_targets
+//go:generate mapper stmt -e backup_target objects-by-ID table=backup_targets
+//go:generate mapper stmt -e backup_target objects-by-Name table=backup_targets
+//go:generate mapper stmt -e backup_target id table=backup_targets
+//go:generate mapper stmt -e backup_target create struct=BackupTarget table=backup_targets
+//go:generate mapper stmt -e backup_target delete-by-Name table=backup_targets
+//go:generate mapper stmt -e backup_target update struct=BackupTarget table=backup_targets
+//
+//go:generate mapper method -i -e backup_target GetMany
+//go:generate mapper method -i -e backup_target GetOne
+//go:generate mapper method -i -e backup_target ID struct=BackupTarget
+//go:generate mapper method -i -e backup_target Exists struct=BackupTarget
+//go:generate mapper method -i -e backup_target Create struct=BackupTarget
+//go:generate mapper method -i -e backup_target DeleteOne-by-Name
+//go:generate mapper method -i -e backup_target Update struct=BackupTarget
+//go:generate goimports -w backup_targets.mapper.go
+//go:generate goimports -w backup_targets.interface.mapper.go
+
+// BackupTarget is the database representation of an [api.BackupTarget].
+type BackupTarget struct {
+	ID          int
+	Name        string `db:"primary=yes"`
+	Description string `db:"coalesce=''"`
+}
+
+// BackupTargetFilter contains fields that can be used to filter results when getting backup targets.
+type BackupTargetFilter struct {
+	ID   *int
+	Name *string
+}
+
+// CreateBackupTargetConfig creates config for a new backup target with the given ID.
+func CreateBackupTargetConfig(ctx context.Context, tx *sql.Tx, backupTargetID int64, config map[string]string) error {
+	q := `INSERT INTO backup_targets_config (backup_target_id, key, value) VALUES(?, ?, ?)`
+
+	stmt, err := tx.Prepare(q)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		err := stmt.Close()
+		if err != nil {
+			logger.Warn("Failed closing statement", logger.Ctx{"query": q, "err": err})
+		}
+	}()
+
+	for k, v := range config {
+		if v == "" {
+			continue
+		}
+
+		_, err = stmt.Exec(backupTargetID, k, v)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdateBackupTargetConfig updates the backup target config with the given ID.
+func UpdateBackupTargetConfig(ctx context.Context, tx *sql.Tx, backupTargetID int64, config map[string]string) error {
+	// Delete current entries.
+	_, err := tx.Exec("DELETE FROM backup_targets_config WHERE backup_target_id=?", backupTargetID)
+	if err != nil {
+		return err
+	}
+
+	// Insert new entries.
+	return CreateBackupTargetConfig(ctx, tx, backupTargetID, config)
+}
+
+// GetBackupTargetConfig returns the config for the backup target with the given ID.
+func GetBackupTargetConfig(ctx context.Context, tx *sql.Tx, backupTargetID int) (map[string]string, error) {
+	q := `SELECT key, value FROM backup_targets_config WHERE backup_target_id=?`
+
+	config := map[string]string{}
+	return config, query.Scan(ctx, tx, q, func(scan func(dest ...any) error) error {
+		var key, value string
+
+		err := scan(&key, &value)
+		if err != nil {
+			return err
+		}
+
+		_, found := config[key]
+		if found {
+			return fmt.Errorf("Duplicate config row found for key %q for backup target ID %d", key, backupTargetID)
+		}
+
+		config[key] = value
+		return nil
+	}, backupTargetID)
+}
+
+// ToAPI converts the [BackupTarget] to an [api.BackupTarget], querying for extra data as necessary.
+func (b *BackupTarget) ToAPI(ctx context.Context, tx *sql.Tx) (*api.BackupTarget, error) {
+	// Get config
+	config, err := GetBackupTargetConfig(ctx, tx, b.ID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting backup target config: %w", err)
+	}
+
+	return &api.BackupTarget{
+		Name:        b.Name,
+		Description: b.Description,
+		Config:      config,
+	}, nil
+}
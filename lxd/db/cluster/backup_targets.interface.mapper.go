@@ -0,0 +1,39 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import (
+	"context"
+	"database/sql"
+)
+
+// BackupTargetGenerated is an interface of generated methods for BackupTarget.
+type BackupTargetGenerated interface {
+	// GetBackupTargets returns all available backup_targets.
+	// generator: backup_target GetMany
+	GetBackupTargets(ctx context.Context, tx *sql.Tx, filters ...BackupTargetFilter) ([]BackupTarget, error)
+
+	// GetBackupTarget returns the backup_target with the given key.
+	// generator: backup_target GetOne
+	GetBackupTarget(ctx context.Context, tx *sql.Tx, name string) (*BackupTarget, error)
+
+	// GetBackupTargetID return the ID of the backup_target with the given key.
+	// generator: backup_target ID
+	GetBackupTargetID(ctx context.Context, tx *sql.Tx, name string) (int64, error)
+
+	// BackupTargetExists checks if a backup_target with the given key exists.
+	// generator: backup_target Exists
+	BackupTargetExists(ctx context.Context, tx *sql.Tx, name string) (bool, error)
+
+	// CreateBackupTarget adds a new backup_target to the database.
+	// generator: backup_target Create
+	CreateBackupTarget(ctx context.Context, tx *sql.Tx, object BackupTarget) (int64, error)
+
+	// DeleteBackupTarget deletes the backup_target matching the given key parameters.
+	// generator: backup_target DeleteOne-by-Name
+	DeleteBackupTarget(ctx context.Context, tx *sql.Tx, name string) error
+
+	// UpdateBackupTarget updates the backup_target matching the given key parameters.
+	// generator: backup_target Update
+	UpdateBackupTarget(ctx context.Context, tx *sql.Tx, name string, object BackupTarget) error
+}
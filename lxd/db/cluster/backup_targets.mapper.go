@@ -0,0 +1,350 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+// The code below was generated by lxd-generate - DO NOT EDIT!
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/shared/api"
+)
+
+var _ = api.ServerEnvironment{}
+
+var backupTargetObjects = RegisterStmt(`
+SELECT backup_targets.id, backup_targets.name, coalesce(backup_targets.description, '')
+  FROM backup_targets
+  ORDER BY backup_targets.name
+`)
+
+var backupTargetObjectsByID = RegisterStmt(`
+SELECT backup_targets.id, backup_targets.name, coalesce(backup_targets.description, '')
+  FROM backup_targets
+  WHERE ( backup_targets.id = ? )
+  ORDER BY backup_targets.name
+`)
+
+var backupTargetObjectsByName = RegisterStmt(`
+SELECT backup_targets.id, backup_targets.name, coalesce(backup_targets.description, '')
+  FROM backup_targets
+  WHERE ( backup_targets.name = ? )
+  ORDER BY backup_targets.name
+`)
+
+var backupTargetID = RegisterStmt(`
+SELECT backup_targets.id FROM backup_targets
+  WHERE backup_targets.name = ?
+`)
+
+var backupTargetCreate = RegisterStmt(`
+INSERT INTO backup_targets (name, description)
+  VALUES (?, ?)
+`)
+
+var backupTargetDeleteByName = RegisterStmt(`
+DELETE FROM backup_targets WHERE name = ?
+`)
+
+var backupTargetUpdate = RegisterStmt(`
+UPDATE backup_targets
+  SET name = ?, description = ?
+ WHERE id = ?
+`)
+
+// getBackupTargets can be used to run handwritten sql.Stmts to return a slice of objects.
+func getBackupTargets(ctx context.Context, stmt *sql.Stmt, args ...any) ([]BackupTarget, error) {
+	objects := make([]BackupTarget, 0)
+
+	dest := func(scan func(dest ...any) error) error {
+		b := BackupTarget{}
+		err := scan(&b.ID, &b.Name, &b.Description)
+		if err != nil {
+			return err
+		}
+
+		objects = append(objects, b)
+
+		return nil
+	}
+
+	err := query.SelectObjects(ctx, stmt, dest, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch from \"backups_targets\" table: %w", err)
+	}
+
+	return objects, nil
+}
+
+// getBackupTargetsRaw can be used to run handwritten query strings to return a slice of objects.
+func getBackupTargetsRaw(ctx context.Context, tx *sql.Tx, sql string, args ...any) ([]BackupTarget, error) {
+	objects := make([]BackupTarget, 0)
+
+	dest := func(scan func(dest ...any) error) error {
+		b := BackupTarget{}
+		err := scan(&b.ID, &b.Name, &b.Description)
+		if err != nil {
+			return err
+		}
+
+		objects = append(objects, b)
+
+		return nil
+	}
+
+	err := query.Scan(ctx, tx, sql, dest, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch from \"backups_targets\" table: %w", err)
+	}
+
+	return objects, nil
+}
+
+// GetBackupTargets returns all available backup_targets.
+// generator: backup_target GetMany
+func GetBackupTargets(ctx context.Context, tx *sql.Tx, filters ...BackupTargetFilter) ([]BackupTarget, error) {
+	var err error
+
+	// Result slice.
+	objects := make([]BackupTarget, 0)
+
+	// Pick the prepared statement and arguments to use based on active criteria.
+	var sqlStmt *sql.Stmt
+	args := []any{}
+	queryParts := [2]string{}
+
+	if len(filters) == 0 {
+		sqlStmt, err = Stmt(tx, backupTargetObjects)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get \"backupTargetObjects\" prepared statement: %w", err)
+		}
+	}
+
+	for i, filter := range filters {
+		if filter.Name != nil && filter.ID == nil {
+			args = append(args, []any{filter.Name}...)
+			if len(filters) == 1 {
+				sqlStmt, err = Stmt(tx, backupTargetObjectsByName)
+				if err != nil {
+					return nil, fmt.Errorf("Failed to get \"backupTargetObjectsByName\" prepared statement: %w", err)
+				}
+
+				break
+			}
+
+			query, err := StmtString(backupTargetObjectsByName)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to get \"backupTargetObjects\" prepared statement: %w", err)
+			}
+
+			parts := strings.SplitN(query, "ORDER BY", 2)
+			if i == 0 {
+				copy(queryParts[:], parts)
+				continue
+			}
+
+			_, where, _ := strings.Cut(parts[0], "WHERE")
+			queryParts[0] += "OR" + where
+		} else if filter.ID != nil && filter.Name == nil {
+			args = append(args, []any{filter.ID}...)
+			if len(filters) == 1 {
+				sqlStmt, err = Stmt(tx, backupTargetObjectsByID)
+				if err != nil {
+					return nil, fmt.Errorf("Failed to get \"backupTargetObjectsByID\" prepared statement: %w", err)
+				}
+
+				break
+			}
+
+			query, err := StmtString(backupTargetObjectsByID)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to get \"backupTargetObjects\" prepared statement: %w", err)
+			}
+
+			parts := strings.SplitN(query, "ORDER BY", 2)
+			if i == 0 {
+				copy(queryParts[:], parts)
+				continue
+			}
+
+			_, where, _ := strings.Cut(parts[0], "WHERE")
+			queryParts[0] += "OR" + where
+		} else if filter.ID == nil && filter.Name == nil {
+			return nil, errors.New("Cannot filter on empty BackupTargetFilter")
+		} else {
+			return nil, errors.New("No statement exists for the given Filter")
+		}
+	}
+
+	// Select.
+	if sqlStmt != nil {
+		objects, err = getBackupTargets(ctx, sqlStmt, args...)
+	} else {
+		queryStr := strings.Join(queryParts[:], "ORDER BY")
+		objects, err = getBackupTargetsRaw(ctx, tx, queryStr, args...)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch from \"backups_targets\" table: %w", err)
+	}
+
+	return objects, nil
+}
+
+// GetBackupTarget returns the backup_target with the given key.
+// generator: backup_target GetOne
+func GetBackupTarget(ctx context.Context, tx *sql.Tx, name string) (*BackupTarget, error) {
+	filter := BackupTargetFilter{}
+	filter.Name = &name
+
+	objects, err := GetBackupTargets(ctx, tx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch from \"backups_targets\" table: %w", err)
+	}
+
+	switch len(objects) {
+	case 0:
+		return nil, api.StatusErrorf(http.StatusNotFound, "BackupTarget not found")
+	case 1:
+		return &objects[0], nil
+	default:
+		return nil, errors.New("More than one \"backups_targets\" entry matches")
+	}
+}
+
+// GetBackupTargetID return the ID of the backup_target with the given key.
+// generator: backup_target ID
+func GetBackupTargetID(ctx context.Context, tx *sql.Tx, name string) (int64, error) {
+	stmt, err := Stmt(tx, backupTargetID)
+	if err != nil {
+		return -1, fmt.Errorf("Failed to get \"backupTargetID\" prepared statement: %w", err)
+	}
+
+	row := stmt.QueryRowContext(ctx, name)
+	var id int64
+	err = row.Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return -1, api.StatusErrorf(http.StatusNotFound, "BackupTarget not found")
+		}
+
+		return -1, fmt.Errorf("Failed to get \"backups_targets\" ID: %w", err)
+	}
+
+	return id, nil
+}
+
+// BackupTargetExists checks if a backup_target with the given key exists.
+// generator: backup_target Exists
+func BackupTargetExists(ctx context.Context, tx *sql.Tx, name string) (bool, error) {
+	_, err := GetBackupTargetID(ctx, tx, name)
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// CreateBackupTarget adds a new backup_target to the database.
+// generator: backup_target Create
+func CreateBackupTarget(ctx context.Context, tx *sql.Tx, object BackupTarget) (int64, error) {
+	args := make([]any, 2)
+
+	// Populate the statement arguments.
+	args[0] = object.Name
+	args[1] = object.Description
+
+	// Prepared statement to use.
+	stmt, err := Stmt(tx, backupTargetCreate)
+	if err != nil {
+		return -1, fmt.Errorf("Failed to get \"backupTargetCreate\" prepared statement: %w", err)
+	}
+
+	// Execute the statement.
+	result, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		if query.IsConflictErr(err) {
+			return -1, api.NewStatusError(http.StatusConflict, "This \"backups_targets\" entry already exists")
+		}
+
+		return -1, fmt.Errorf("Failed to create \"backups_targets\" entry: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return -1, fmt.Errorf("Failed to fetch \"backups_targets\" entry ID: %w", err)
+	}
+
+	return id, nil
+}
+
+// DeleteBackupTarget deletes the backup_target matching the given key parameters.
+// generator: backup_target DeleteOne-by-Name
+func DeleteBackupTarget(ctx context.Context, tx *sql.Tx, name string) error {
+	stmt, err := Stmt(tx, backupTargetDeleteByName)
+	if err != nil {
+		return fmt.Errorf("Failed to get \"backupTargetDeleteByName\" prepared statement: %w", err)
+	}
+
+	result, err := stmt.ExecContext(ctx, name)
+	if err != nil {
+		return fmt.Errorf("Delete \"backups_targets\": %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Fetch affected rows: %w", err)
+	}
+
+	if n == 0 {
+		return api.StatusErrorf(http.StatusNotFound, "BackupTarget not found")
+	} else if n > 1 {
+		return fmt.Errorf("Query deleted %d BackupTarget rows instead of 1", n)
+	}
+
+	return nil
+}
+
+// UpdateBackupTarget updates the backup_target matching the given key parameters.
+// generator: backup_target Update
+func UpdateBackupTarget(ctx context.Context, tx *sql.Tx, name string, object BackupTarget) error {
+	id, err := GetBackupTargetID(ctx, tx, name)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := Stmt(tx, backupTargetUpdate)
+	if err != nil {
+		return fmt.Errorf("Failed to get \"backupTargetUpdate\" prepared statement: %w", err)
+	}
+
+	result, err := stmt.ExecContext(ctx, object.Name, object.Description, id)
+	if err != nil {
+		if query.IsConflictErr(err) {
+			return api.NewStatusError(http.StatusConflict, "A \"backups_targets\" entry already exists with these properties")
+		}
+
+		return fmt.Errorf("Update \"backups_targets\" entry failed: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Fetch affected rows: %w", err)
+	}
+
+	if n != 1 {
+		return fmt.Errorf("Query updated %d rows instead of 1", n)
+	}
+
+	return nil
+}
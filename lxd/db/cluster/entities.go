@@ -110,6 +110,7 @@ var entityTypes = map[entity.Type]entityTypeDBInfo{
 	entity.TypeAuthGroup:             entityTypeAuthGroup{},
 	entity.TypeIdentityProviderGroup: entityTypeIdentityProviderGroup{},
 	entity.TypePlacementGroup:        entityTypePlacementGroup{},
+	entity.TypeNetworkAddressSet:     entityTypeNetworkAddressSet{},
 }
 
 const (
@@ -140,6 +141,7 @@ const (
 	entityTypeCodeIdentityProviderGroup int64 = 23
 	entityTypeCodeIdentity              int64 = 24
 	entityTypeCodePlacementGroup        int64 = 25
+	entityTypeCodeNetworkAddressSet     int64 = 26
 )
 
 var entityTypeByCode = map[int64]EntityType{
@@ -0,0 +1,58 @@
+package cluster
+
+import (
+	"strconv"
+)
+
+// entityTypeNetworkAddressSet implements [entityTypeDBInfo] for an [api.NetworkAddressSet].
+type entityTypeNetworkAddressSet struct {
+	entityTypeCommon
+}
+
+func (e entityTypeNetworkAddressSet) code() int64 {
+	return entityTypeCodeNetworkAddressSet
+}
+
+func (e entityTypeNetworkAddressSet) allURLsQuery() string {
+	return `
+SELECT 
+	` + strconv.Itoa(int(e.code())) + `,
+	networks_address_sets.id,
+	projects.name,
+	'',
+	json_array(networks_address_sets.name)
+FROM networks_address_sets
+JOIN projects ON projects.id = networks_address_sets.project_id
+`
+}
+
+func (e entityTypeNetworkAddressSet) urlByIDQuery() string {
+	return e.allURLsQuery() + " WHERE networks_address_sets.id = ?"
+}
+
+func (e entityTypeNetworkAddressSet) urlsByProjectQuery() string {
+	return e.allURLsQuery() + " WHERE projects.name = ?"
+}
+
+func (e entityTypeNetworkAddressSet) idFromURLQuery() string {
+	return `
+SELECT ?, networks_address_sets.id 
+FROM networks_address_sets
+JOIN projects ON networks_address_sets.project_id = projects.id 
+WHERE projects.name = ? 
+	AND '' = ? 
+	AND networks_address_sets.name = ?`
+}
+
+func (e entityTypeNetworkAddressSet) onDeleteTriggerSQL() (name string, sql string) {
+	name = "on_network_address_set_delete"
+	return name, `
+CREATE TRIGGER ` + name + `
+	AFTER DELETE ON networks_address_sets
+	BEGIN
+	DELETE FROM auth_groups_permissions 
+		WHERE entity_type = ` + strconv.Itoa(int(e.code())) + ` 
+		AND entity_id = OLD.id;
+	END
+`
+}
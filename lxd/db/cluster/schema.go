@@ -29,6 +29,20 @@ CREATE TABLE auth_groups_permissions (
     FOREIGN KEY (auth_group_id) REFERENCES auth_groups (id) ON DELETE CASCADE,
     UNIQUE (auth_group_id, entity_type, entitlement, entity_id)
 );
+CREATE TABLE backup_targets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    UNIQUE (name)
+);
+CREATE TABLE backup_targets_config (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    backup_target_id INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT,
+    UNIQUE (backup_target_id, key),
+    FOREIGN KEY (backup_target_id) REFERENCES backup_targets (id) ON DELETE CASCADE
+);
 CREATE TABLE "cluster_groups" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     name TEXT NOT NULL,
@@ -272,6 +286,23 @@ CREATE TABLE "networks_acls_config" (
     UNIQUE (network_acl_id, key),
     FOREIGN KEY (network_acl_id) REFERENCES "networks_acls" (id) ON DELETE CASCADE
 );
+CREATE TABLE networks_address_sets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    addresses TEXT NOT NULL,
+    UNIQUE (project_id, name),
+    FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE
+);
+CREATE TABLE networks_address_sets_config (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    network_address_set_id INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    UNIQUE (network_address_set_id, key),
+    FOREIGN KEY (network_address_set_id) REFERENCES networks_address_sets (id) ON DELETE CASCADE
+);
 CREATE TABLE "networks_config" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     network_id INTEGER NOT NULL,
@@ -708,5 +739,5 @@ CREATE TABLE "warnings" (
 );
 CREATE UNIQUE INDEX warnings_unique_node_id_project_id_entity_type_code_entity_id_type_code ON warnings(IFNULL(node_id, -1), IFNULL(project_id, -1), entity_type_code, entity_id, type_code);
 
-INSERT INTO schema (version, updated_at) VALUES (78, strftime("%s"))
+INSERT INTO schema (version, updated_at) VALUES (80, strftime("%s"))
 `
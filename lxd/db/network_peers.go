@@ -197,6 +197,7 @@ func (c *ClusterTx) GetNetworkPeer(ctx context.Context, networkID int64, peerNam
 // networkPeerPopulatePeerInfo populates the supplied peer's Status, TargetProject and TargetNetwork fields.
 // It uses the state of the targetPeerNetworkProject and targetPeerNetworkName arguments to decide whether the
 // peering is mutually created and whether to use those values rather than the values contained in the peer.
+// WireGuard peers have no target network and their status depends on their config, which must be populated first.
 func networkPeerPopulatePeerInfo(peer *api.NetworkPeer, targetPeerNetworkProject string, targetPeerNetworkName string) {
 	// Peer has mutual peering from target network.
 	if targetPeerNetworkName != "" && targetPeerNetworkProject != "" {
@@ -215,6 +216,12 @@ func networkPeerPopulatePeerInfo(peer *api.NetworkPeer, targetPeerNetworkProject
 		if peer.TargetNetwork != "" || peer.TargetProject != "" {
 			// Peer isn't linked to a mutual peer on the target network yet but has joining details.
 			peer.Status = api.NetworkStatusPending
+		} else if peer.Config["wireguard.public_key"] != "" {
+			// WireGuard peer of a bridge network with the public key of its remote end.
+			peer.Status = api.NetworkStatusCreated
+		} else if peer.Config["wireguard.routes"] != "" {
+			// WireGuard peer of a bridge network waiting for the public key of its remote end.
+			peer.Status = api.NetworkStatusPending
 		} else {
 			// Peer isn't linked to a mutual peer on the target network yet and has no joining details.
 			// Perhaps it was formely joined (and had its joining details cleared) and subsequently
@@ -284,6 +291,7 @@ func (c *ClusterTx) GetNetworkPeers(ctx context.Context, networkID int64) (map[i
 
 	var err error
 	peers := make(map[int64]*api.NetworkPeer)
+	targetPeerNetworks := make(map[int64][2]string)
 
 	err = query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
 		var peerID = int64(-1)
@@ -296,9 +304,8 @@ func (c *ClusterTx) GetNetworkPeers(ctx context.Context, networkID int64) (map[i
 			return err
 		}
 
-		networkPeerPopulatePeerInfo(&peer, targetPeerNetworkProject, targetPeerNetworkName)
-
 		peers[peerID] = &peer
+		targetPeerNetworks[peerID] = [2]string{targetPeerNetworkProject, targetPeerNetworkName}
 
 		return nil
 	}, networkID)
@@ -306,12 +313,14 @@ func (c *ClusterTx) GetNetworkPeers(ctx context.Context, networkID int64) (map[i
 		return nil, err
 	}
 
-	// Populate config.
+	// Populate config and status.
 	for peerID := range peers {
 		err = networkPeerConfig(ctx, c, peerID, peers[peerID])
 		if err != nil {
 			return nil, err
 		}
+
+		networkPeerPopulatePeerInfo(peers[peerID], targetPeerNetworks[peerID][0], targetPeerNetworks[peerID][1])
 	}
 
 	return peers, nil
@@ -80,6 +80,10 @@ const (
 	ProfileUpdate
 	VolumeUpdate
 	VolumeDelete
+	ClusterRebalance
+	InstanceReplicate
+	InstancePromote
+	VolumePromote
 )
 
 // Description return a human-readable description of the operation type.
@@ -211,6 +215,14 @@ func (t Type) Description() string {
 		return "Healing cluster"
 	case RemoveExpiredOIDCSessions:
 		return "Remove expired OIDC sessions"
+	case ClusterRebalance:
+		return "Rebalancing cluster"
+	case InstanceReplicate:
+		return "Replicating instances"
+	case InstancePromote:
+		return "Promoting instance"
+	case VolumePromote:
+		return "Promoting storage volume"
 	default:
 		return "Executing operation"
 	}
@@ -266,6 +278,8 @@ func (t Type) Permission() (entity.Type, auth.Entitlement) {
 		return entity.TypeInstance, auth.EntitlementCanEdit
 	case InstanceRebuild:
 		return entity.TypeInstance, auth.EntitlementCanEdit
+	case InstancePromote:
+		return entity.TypeInstance, auth.EntitlementCanEdit
 	case SnapshotRestore:
 		return entity.TypeInstance, auth.EntitlementCanEdit
 
@@ -292,6 +306,8 @@ func (t Type) Permission() (entity.Type, auth.Entitlement) {
 		return entity.TypeStorageVolume, auth.EntitlementCanManageBackups
 	case CustomVolumeBackupRestore:
 		return entity.TypeStorageVolume, auth.EntitlementCanEdit
+	case VolumePromote:
+		return entity.TypeStorageVolume, auth.EntitlementCanEdit
 	}
 
 	return "", ""
@@ -912,6 +912,8 @@ var NodeSpecificStorageConfig = []string{
 	"zfs.pool_name",
 	"lvm.thinpool_name",
 	"lvm.vg_name",
+	"replication.target",
+	"replication.target.certificate",
 }
 
 // IsRemoteStorage return whether a given pool is backed by remote storage.
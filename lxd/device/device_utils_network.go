@@ -637,6 +637,9 @@ func bgpAddPrefix(d *deviceCommon, n network.Network, config map[string]string)
 
 	// Add the prefixes.
 	bgpOwner := fmt.Sprint("instance_", d.inst.ID(), "_", d.name)
+
+	// Advertise the prefixes with the export policy of the network.
+	bgpPolicy := fmt.Sprint("network_", n.ID())
 	if config["ipv4.routes.external"] != "" {
 		for _, prefix := range shared.SplitNTrimSpace(config["ipv4.routes.external"], ",", -1, true) {
 			_, prefixNet, err := net.ParseCIDR(prefix)
@@ -644,7 +647,7 @@ func bgpAddPrefix(d *deviceCommon, n network.Network, config map[string]string)
 				return err
 			}
 
-			err = d.state.BGP.AddPrefix(*prefixNet, nexthopV4, bgpOwner)
+			err = d.state.BGP.AddPrefix(*prefixNet, nexthopV4, bgpOwner, bgpPolicy)
 			if err != nil {
 				return err
 			}
@@ -658,7 +661,7 @@ func bgpAddPrefix(d *deviceCommon, n network.Network, config map[string]string)
 				return err
 			}
 
-			err = d.state.BGP.AddPrefix(*prefixNet, nexthopV6, bgpOwner)
+			err = d.state.BGP.AddPrefix(*prefixNet, nexthopV6, bgpOwner, bgpPolicy)
 			if err != nil {
 				return err
 			}
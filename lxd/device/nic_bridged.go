@@ -106,6 +106,20 @@ func (d *nicBridged) validateConfig(instConf instance.ConfigReader) error {
 
 		netConfig := n.Config()
 
+		// Check the static addresses aren't reserved for another MAC address on the network.
+		nicMAC, _ := net.ParseMAC(d.config["hwaddr"])
+		if nicMAC == nil && d.inst != nil {
+			nicMAC, _ = net.ParseMAC(d.volatileGet()["hwaddr"])
+		}
+
+		reservations := dnsmasq.DHCPReservations(netConfig)
+		for _, key := range []string{"ipv4.address", "ipv6.address"} {
+			err := dnsmasq.DHCPReservationConflict(reservations, nicMAC, net.ParseIP(d.config[key]))
+			if err != nil {
+				return api.StatusErrorf(http.StatusConflict, "%w on network %q", err, n.Name())
+			}
+		}
+
 		if d.config["ipv4.address"] != "" {
 			dhcpv4Subnet := n.DHCPv4Subnet()
 
@@ -346,6 +360,41 @@ func (d *nicBridged) validateConfig(instConf instance.ConfigReader) error {
 		return validate.IsNetworkAddressV6(value)
 	}
 
+	// Add the DHCP options validation rules.
+	for k := range d.config {
+		name, ok := strings.CutPrefix(k, "ipv4.dhcp.option.")
+		if !ok {
+			name, ok = strings.CutPrefix(k, "ipv6.dhcp.option.")
+		}
+
+		if !ok {
+			continue
+		}
+
+		if d.network == nil {
+			return fmt.Errorf("Cannot use %q property when the parent is not a managed network", k)
+		}
+
+		// lxdmeta:generate(entities=device-nic-bridged; group=device-conf; key=ipv4.dhcp.option.NAME)
+		// Replace `NAME` with the option number or the `dnsmasq` name of the option, for example `67` or `bootfile-name`.
+		// These options take precedence over the {config:option}`network-bridge-network-conf:ipv4.dhcp.option.NAME` options of the network.
+		// ---
+		//  type: string
+		//  managed: no
+		//  shortdesc: Value of a custom DHCPv4 option sent to the instance
+
+		// lxdmeta:generate(entities=device-nic-bridged; group=device-conf; key=ipv6.dhcp.option.NAME)
+		// Replace `NAME` with the option number or the `dnsmasq` name of the option, for example `31` or `sntp-server`.
+		// These options take precedence over the {config:option}`network-bridge-network-conf:ipv6.dhcp.option.NAME` options of the network.
+		// ---
+		//  type: string
+		//  managed: no
+		//  shortdesc: Value of a custom DHCPv6 option sent to the instance
+		rules[k] = func(value string) error {
+			return dnsmasq.ValidateDHCPOption(name, value)
+		}
+	}
+
 	// Now run normal validation.
 	err := d.config.Validate(rules)
 	if err != nil {
@@ -457,12 +506,23 @@ func (d *nicBridged) validateEnvironment() error {
 // UpdatableFields returns a list of fields that can be updated without triggering a device remove & add.
 func (d *nicBridged) UpdatableFields(oldDevice Type) []string {
 	// Check old and new device types match.
-	_, match := oldDevice.(*nicBridged)
+	oldNIC, match := oldDevice.(*nicBridged)
 	if !match {
 		return []string{}
 	}
 
-	return []string{"limits.ingress", "limits.egress", "limits.max", "limits.priority", "ipv4.routes", "ipv6.routes", "ipv4.routes.external", "ipv6.routes.external", "ipv4.address", "ipv6.address", "security.mac_filtering", "security.ipv4_filtering", "security.ipv6_filtering"}
+	fields := []string{"limits.ingress", "limits.egress", "limits.max", "limits.priority", "ipv4.routes", "ipv6.routes", "ipv4.routes.external", "ipv6.routes.external", "ipv4.address", "ipv6.address", "security.mac_filtering", "security.ipv4_filtering", "security.ipv6_filtering"}
+
+	// DHCP options are applied by rebuilding the dnsmasq entry.
+	for _, config := range []deviceConfig.Device{oldNIC.config, d.config} {
+		for k := range config {
+			if strings.HasPrefix(k, "ipv4.dhcp.option.") || strings.HasPrefix(k, "ipv6.dhcp.option.") {
+				fields = append(fields, k)
+			}
+		}
+	}
+
+	return fields
 }
 
 // Add is run when a device is added to a non-snapshot instance whether or not the instance is running.
@@ -632,21 +692,30 @@ func (d *nicBridged) Start() (*deviceConfig.RunConfig, error) {
 
 		if brNetfilterEnabled {
 			var listenAddresses map[int64]string
+			var loadBalancerListenAddresses map[int64]string
 
 			err = d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 				listenAddresses, err = tx.GetNetworkForwardListenAddresses(ctx, d.network.ID(), true)
+				if err != nil {
+					return fmt.Errorf("Failed loading network forwards: %w", err)
+				}
 
-				return err
+				loadBalancerListenAddresses, err = tx.GetNetworkLoadBalancerListenAddresses(ctx, d.network.ID(), true)
+				if err != nil {
+					return fmt.Errorf("Failed loading network load balancers: %w", err)
+				}
+
+				return nil
 			})
 			if err != nil {
-				return nil, fmt.Errorf("Failed loading network forwards: %w", err)
+				return nil, err
 			}
 
-			// If br_netfilter is enabled and bridge has forwards, we enable hairpin mode on NIC's
-			// bridge port in case any of the forwards target this NIC and the instance attempts to
-			// connect to the forward's listener. Without hairpin mode on the target of the forward
-			// will not be able to connect to the listener.
-			if len(listenAddresses) > 0 {
+			// If br_netfilter is enabled and bridge has forwards or load balancers, we enable hairpin
+			// mode on NIC's bridge port in case any of the forwards target this NIC and the instance
+			// attempts to connect to the forward's listener. Without hairpin mode on the target of the
+			// forward will not be able to connect to the listener.
+			if len(listenAddresses) > 0 || len(loadBalancerListenAddresses) > 0 {
 				link := &ip.Link{Name: saveData["host_name"]}
 				err = link.BridgeLinkSetHairpin(true)
 				if err != nil {
@@ -968,7 +1037,14 @@ func (d *nicBridged) rebuildDnsmasqEntry() error {
 		}
 	}
 
-	err := dnsmasq.UpdateStaticEntry(d.config["parent"], d.inst.Project().Name, d.inst.Name(), d.Name(), d.network.Config(), d.config["hwaddr"], ipv4Address, ipv6Address)
+	// Write the DHCP options of the NIC before the host entry so that the host entry refers to them.
+	options := append(dnsmasq.DHCPOptions(d.config, 4), dnsmasq.DHCPOptions(d.config, 6)...)
+	err := dnsmasq.UpdateOptionsEntry(d.config["parent"], d.inst.Project().Name, d.inst.Name(), d.Name(), options)
+	if err != nil {
+		return err
+	}
+
+	err = dnsmasq.UpdateStaticEntry(d.config["parent"], d.inst.Project().Name, d.inst.Name(), d.Name(), d.network.Config(), d.config["hwaddr"], ipv4Address, ipv6Address)
 	if err != nil {
 		return err
 	}
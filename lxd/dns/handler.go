@@ -50,6 +50,12 @@ func (d *dnsHandler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 
 	// Check that it's a supported request type.
 	if r.Question[0].Qtype != dns.TypeAXFR && r.Question[0].Qtype != dns.TypeIXFR && r.Question[0].Qtype != dns.TypeSOA {
+		// Answer regular queries when acting as an authoritative server.
+		if d.server.authoritative.Load() {
+			d.serveQuery(w, r)
+			return
+		}
+
 		m := new(dns.Msg)
 		m.SetRcode(r, dns.RcodeNotImplemented)
 		err := w.WriteMsg(m)
@@ -82,6 +88,12 @@ func (d *dnsHandler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 	// Load the zone.
 	zone, err := d.server.zoneRetriever(name, r.Question[0].Qtype != dns.TypeSOA)
 	if err != nil {
+		// SOA queries for names within a zone are regular queries.
+		if r.Question[0].Qtype == dns.TypeSOA && d.server.authoritative.Load() {
+			d.serveQuery(w, r)
+			return
+		}
+
 		// On failure, return NXDOMAIN.
 		m := new(dns.Msg)
 		m.SetRcode(r, dns.RcodeNameError)
@@ -95,6 +107,12 @@ func (d *dnsHandler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 
 	// Check access.
 	if !d.isAllowed(zone.Info, ip, r.IsTsig(), w.TsigStatus() == nil) {
+		// Anyone can query the SOA record of a zone when acting as an authoritative server.
+		if r.Question[0].Qtype == dns.TypeSOA && d.server.authoritative.Load() {
+			d.serveQuery(w, r)
+			return
+		}
+
 		// On auth failure, return NXDOMAIN to avoid information leaks.
 		m := new(dns.Msg)
 		m.SetRcode(r, dns.RcodeNameError)
@@ -142,13 +160,42 @@ func (d *dnsHandler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 }
 
 func (d *dnsHandler) isAllowed(zone api.NetworkZone, ip string, tsig *dns.TSIG, tsigStatus bool) bool {
-	type peer struct {
-		address string
-		key     string
+	// Validate access.
+	for peerName, peer := range zonePeers(zone) {
+		peerKeyName := fmt.Sprintf("%s_%s.", zone.Name, peerName)
+
+		if peer.address != "" && ip != peer.address {
+			// Bad IP address.
+			continue
+		}
+
+		if peer.key != "" && (tsig == nil || !tsigStatus) {
+			// Missing or invalid TSIG.
+			continue
+		}
+
+		if peer.key != "" && tsig.Hdr.Name != peerKeyName {
+			// Bad key name (valid TSIG but potentially for another domain).
+			continue
+		}
+
+		// We have a trusted peer.
+		return true
 	}
 
+	return false
+}
+
+// zonePeer represents a DNS server allowed to transfer a zone.
+type zonePeer struct {
+	address string
+	key     string
+}
+
+// zonePeers returns the peers of the zone keyed by peer name.
+func zonePeers(zone api.NetworkZone) map[string]*zonePeer {
 	// Build a list of peers.
-	peers := map[string]*peer{}
+	peers := map[string]*zonePeer{}
 	for k, v := range zone.Config {
 		if !strings.HasPrefix(k, "peers.") {
 			continue
@@ -163,7 +210,7 @@ func (d *dnsHandler) isAllowed(zone api.NetworkZone, ip string, tsig *dns.TSIG,
 		peerName := fields[1]
 
 		if peers[peerName] == nil {
-			peers[peerName] = &peer{}
+			peers[peerName] = &zonePeer{}
 		}
 
 		// Add the correct validation rule for the dynamic field based on last part of key.
@@ -175,28 +222,5 @@ func (d *dnsHandler) isAllowed(zone api.NetworkZone, ip string, tsig *dns.TSIG,
 		}
 	}
 
-	// Validate access.
-	for peerName, peer := range peers {
-		peerKeyName := fmt.Sprintf("%s_%s.", zone.Name, peerName)
-
-		if peer.address != "" && ip != peer.address {
-			// Bad IP address.
-			continue
-		}
-
-		if peer.key != "" && (tsig == nil || !tsigStatus) {
-			// Missing or invalid TSIG.
-			continue
-		}
-
-		if peer.key != "" && tsig.Hdr.Name != peerKeyName {
-			// Bad key name (valid TSIG but potentially for another domain).
-			continue
-		}
-
-		// We have a trusted peer.
-		return true
-	}
-
-	return false
+	return peers
 }
@@ -0,0 +1,218 @@
+package dns
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// Invalidate drops the cached records of the zone, so that regular queries are answered from its new content.
+// The cached zone names are dropped too, to pick up zones being created or deleted.
+func (s *Server) Invalidate(name string) {
+	s.zoneMu.Lock()
+	defer s.zoneMu.Unlock()
+
+	s.zoneGeneration++
+	s.zoneNames = nil
+	delete(s.zoneCache, name)
+}
+
+// Notify tells the peers of the zone that it changed, so that they transfer it again without waiting for its
+// refresh interval. The notifications are sent in the background.
+func (s *Server) Notify(name string) {
+	// Don't answer queries from the old content of the zone.
+	s.Invalidate(name)
+
+	if s.zoneRetriever == nil {
+		return
+	}
+
+	zone, err := s.zoneRetriever(name, false)
+	if err != nil {
+		logger.Warn("Failed loading DNS zone to notify its peers", logger.Ctx{"zone": name, "err": err})
+		return
+	}
+
+	s.notify(zone.Info)
+}
+
+// RefreshZones drops the cached zones whose records changed since they were rendered. This catches the changes to
+// the records generated from the networks using the zones and the changes made on other cluster members.
+// If notify is true, the peers of the zones whose records changed since the last call are notified too.
+func (s *Server) RefreshZones(ctx context.Context, notify bool) error {
+	if s.zoneRetriever == nil || s.db == nil {
+		return nil
+	}
+
+	var zoneNames []string
+
+	err := s.db.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		zones, err := tx.GetNetworkZones(ctx)
+		if err != nil {
+			return err
+		}
+
+		for zoneName := range zones {
+			zoneNames = append(zoneNames, zoneName)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading network zones: %w", err)
+	}
+
+	// Forget about deleted zones.
+	s.zoneMu.Lock()
+	s.zoneGeneration++
+	s.zoneNames = make(map[string]struct{}, len(zoneNames))
+	for _, zoneName := range zoneNames {
+		s.zoneNames[zoneName] = struct{}{}
+	}
+
+	for zoneName := range s.zoneCache {
+		if !slices.Contains(zoneNames, zoneName) {
+			delete(s.zoneCache, zoneName)
+		}
+	}
+
+	for zoneName := range s.zoneDigests {
+		if !slices.Contains(zoneNames, zoneName) {
+			delete(s.zoneDigests, zoneName)
+		}
+	}
+
+	s.zoneMu.Unlock()
+
+	for _, zoneName := range zoneNames {
+		s.zoneMu.Lock()
+		entry, cached := s.zoneCache[zoneName]
+		s.zoneMu.Unlock()
+
+		// Skip rendering the zones that are neither cached nor have any peer to notify.
+		notifyPeers := false
+		if notify {
+			zone, err := s.zoneRetriever(zoneName, false)
+			if err != nil {
+				logger.Warn("Failed loading DNS zone", logger.Ctx{"zone": zoneName, "err": err})
+				continue
+			}
+
+			for _, peer := range zonePeers(zone.Info) {
+				if peer.address != "" {
+					notifyPeers = true
+					break
+				}
+			}
+		}
+
+		if !cached && !notifyPeers {
+			continue
+		}
+
+		zone, err := s.zoneRetriever(zoneName, true)
+		if err != nil {
+			logger.Warn("Failed rendering DNS zone", logger.Ctx{"zone": zoneName, "err": err})
+			continue
+		}
+
+		digest := zoneDigest(zone.Content)
+
+		s.zoneMu.Lock()
+		if cached && s.zoneCache[zoneName] == entry && entry.digest != digest {
+			delete(s.zoneCache, zoneName)
+		}
+
+		oldDigest, found := s.zoneDigests[zoneName]
+		if notifyPeers {
+			s.zoneDigests[zoneName] = digest
+		}
+
+		s.zoneMu.Unlock()
+
+		if notifyPeers && found && oldDigest != digest {
+			s.notify(zone.Info)
+		}
+	}
+
+	return nil
+}
+
+// notify sends a NOTIFY message to each of the zone peers having an address.
+func (s *Server) notify(zone api.NetworkZone) {
+	for peerName, peer := range zonePeers(zone) {
+		if peer.address == "" {
+			continue
+		}
+
+		keyName := ""
+		if peer.key != "" {
+			keyName = fmt.Sprintf("%s_%s.", zone.Name, peerName)
+		}
+
+		go func() {
+			err := notifyPeer(zone.Name, net.JoinHostPort(peer.address, "53"), keyName, peer.key)
+			if err != nil {
+				logger.Warn("Failed notifying DNS zone peer", logger.Ctx{"zone": zone.Name, "peer": peerName, "err": err})
+				return
+			}
+
+			logger.Debug("Notified DNS zone peer", logger.Ctx{"zone": zone.Name, "peer": peerName})
+		}()
+	}
+}
+
+// notifyPeer sends a NOTIFY message for the zone to the DNS server at the address (host and port), signed with the
+// TSIG key if provided.
+func notifyPeer(zoneName string, address string, keyName string, key string) error {
+	m := new(dns.Msg)
+	m.SetNotify(dns.Fqdn(zoneName))
+
+	client := &dns.Client{Net: "udp", Timeout: 5 * time.Second}
+	if key != "" {
+		client.TsigSecret = map[string]string{keyName: key}
+		m.SetTsig(keyName, dns.HmacSHA256, 300, time.Now().Unix())
+	}
+
+	resp, _, err := client.Exchange(m, address)
+	if err != nil {
+		return err
+	}
+
+	if resp.Rcode != dns.RcodeSuccess {
+		return fmt.Errorf("Peer refused the notification: %s", dns.RcodeToString[resp.Rcode])
+	}
+
+	return nil
+}
+
+// zoneDigest returns a digest of the zone records, ignoring the SOA record whose serial changes each time the
+// zone is rendered.
+func zoneDigest(content string) string {
+	lines := []string{}
+	for line := range strings.SplitSeq(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.Contains(line, " IN SOA ") {
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+
+	slices.Sort(lines)
+
+	digest := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+
+	return hex.EncodeToString(digest[:])
+}
@@ -0,0 +1,123 @@
+package dns
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_zoneDigest(t *testing.T) {
+	digest := zoneDigest(testZoneContent)
+
+	// The SOA serial changes each time the zone is rendered.
+	newSerial := `lxd.example.net. 3600 IN SOA lxd.example.net. ns1.lxd.example.net. 1669736999 120 60 86400 30
+c1.lxd.example.net. 300 IN AAAA 2001:db8::125
+c1.lxd.example.net. 300 IN A 192.0.2.125
+
+lxd.example.net. 300 IN NS ns1.lxd.example.net.
+_http._tcp.web.lxd.example.net. 300 IN SRV 0 0 80 c1.lxd.example.net.
+www.lxd.example.net. 300 IN CNAME c1.lxd.example.net.
+lxd.example.net. 3600 IN SOA lxd.example.net. ns1.lxd.example.net. 1669736999 120 60 86400 30
+`
+	assert.Equal(t, digest, zoneDigest(newSerial))
+
+	// Record changes are detected.
+	assert.NotEqual(t, digest, zoneDigest(testZoneContent+"c3.lxd.example.net. 300 IN A 192.0.2.127\n"))
+	assert.NotEqual(t, digest, zoneDigest(testSubZoneContent))
+}
+
+// testNotifyPeer starts a DNS server receiving NOTIFY messages and returns its address and the received messages.
+func testNotifyPeer(t *testing.T, rcode int, tsigSecret map[string]string) (string, <-chan *dns.Msg) {
+	t.Helper()
+
+	received := make(chan *dns.Msg, 1)
+
+	handler := dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+		received <- r
+
+		m := new(dns.Msg)
+		m.SetRcode(r, rcode)
+
+		tsig := r.IsTsig()
+		if tsig != nil {
+			// Reject messages failing the TSIG verification.
+			if w.TsigStatus() != nil {
+				m.SetRcode(r, dns.RcodeNotAuth)
+			}
+
+			m.SetTsig(tsig.Hdr.Name, dns.HmacSHA256, 300, time.Now().Unix())
+		}
+
+		_ = w.WriteMsg(m)
+	})
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	started := make(chan struct{})
+	server := &dns.Server{PacketConn: pc, Handler: handler, TsigSecret: tsigSecret, NotifyStartedFunc: func() { close(started) }}
+	go func() { _ = server.ActivateAndServe() }()
+	t.Cleanup(func() { _ = server.Shutdown() })
+
+	<-started
+
+	return pc.LocalAddr().String(), received
+}
+
+func Test_notifyPeer(t *testing.T) {
+	address, received := testNotifyPeer(t, dns.RcodeSuccess, nil)
+
+	err := notifyPeer("lxd.example.net", address, "", "")
+	require.NoError(t, err)
+
+	msg := <-received
+	assert.Equal(t, dns.OpcodeNotify, msg.Opcode)
+	require.Len(t, msg.Question, 1)
+	assert.Equal(t, "lxd.example.net.", msg.Question[0].Name)
+	assert.Equal(t, dns.TypeSOA, msg.Question[0].Qtype)
+	assert.Nil(t, msg.IsTsig())
+}
+
+func Test_notifyPeerTSIG(t *testing.T) {
+	keyName := "lxd.example.net_ns1."
+	key := "c2VjcmV0LWtleS1mb3ItdGhlLXRlc3Q="
+
+	address, received := testNotifyPeer(t, dns.RcodeSuccess, map[string]string{keyName: key})
+
+	err := notifyPeer("lxd.example.net", address, keyName, key)
+	require.NoError(t, err)
+
+	msg := <-received
+	require.NotNil(t, msg.IsTsig())
+	assert.Equal(t, keyName, msg.IsTsig().Hdr.Name)
+}
+
+func Test_notifyPeerRefused(t *testing.T) {
+	address, _ := testNotifyPeer(t, dns.RcodeRefused, nil)
+
+	err := notifyPeer("lxd.example.net", address, "", "")
+	assert.ErrorContains(t, err, "Peer refused the notification: REFUSED")
+}
+
+func Test_Server_Notify(t *testing.T) {
+	zones := &testZones{
+		contents: map[string]string{"lxd.example.net": testZoneContent},
+		renders:  map[string]int{},
+	}
+
+	s := NewServer(nil, zones.retrieve)
+	testSetZoneNames(s, "lxd.example.net")
+
+	_, _, err := s.zoneRecords("c1.lxd.example.net")
+	require.NoError(t, err)
+	assert.Contains(t, s.zoneCache, "lxd.example.net")
+
+	// Notifying the peers of a zone drops its cached records.
+	s.Notify("lxd.example.net")
+	assert.NotContains(t, s.zoneCache, "lxd.example.net")
+	assert.Nil(t, s.zoneNames)
+}
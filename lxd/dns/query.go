@@ -0,0 +1,221 @@
+package dns
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/miekg/dns"
+
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// zoneCacheEntry represents the records of a rendered zone.
+type zoneCacheEntry struct {
+	records []dns.RR
+	digest  string
+}
+
+// parseZone parses the content of a zone into records.
+func parseZone(content string) ([]dns.RR, error) {
+	records := []dns.RR{}
+
+	zoneRR := dns.NewZoneParser(strings.NewReader(content), "", "")
+	for {
+		rr, ok := zoneRR.Next()
+		if !ok {
+			break
+		}
+
+		records = append(records, rr)
+	}
+
+	return records, zoneRR.Err()
+}
+
+// zoneNamesGet returns the names of all network zones, loading them if they aren't cached.
+func (s *Server) zoneNamesGet() (map[string]struct{}, error) {
+	s.zoneMu.Lock()
+	zoneNames := s.zoneNames
+	generation := s.zoneGeneration
+	s.zoneMu.Unlock()
+
+	if zoneNames != nil || s.db == nil {
+		return zoneNames, nil
+	}
+
+	var zones map[string]string
+
+	err := s.db.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		zones, err = tx.GetNetworkZones(ctx)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	zoneNames = make(map[string]struct{}, len(zones))
+	for zoneName := range zones {
+		zoneNames[zoneName] = struct{}{}
+	}
+
+	// Don't cache the zone names if a zone changed while loading them.
+	s.zoneMu.Lock()
+	if s.zoneGeneration == generation {
+		s.zoneNames = zoneNames
+	}
+
+	s.zoneMu.Unlock()
+
+	return zoneNames, nil
+}
+
+// zoneRecords returns the name and records of the most specific zone the name belongs to.
+// An empty zone name is returned if the name doesn't belong to any zone.
+func (s *Server) zoneRecords(name string) (string, []dns.RR, error) {
+	zoneNames, err := s.zoneNamesGet()
+	if err != nil {
+		return "", nil, err
+	}
+
+	labels := dns.SplitDomainName(name)
+	for i := range labels {
+		zoneName := strings.Join(labels[i:], ".")
+
+		_, found := zoneNames[zoneName]
+		if !found {
+			continue
+		}
+
+		s.zoneMu.Lock()
+		entry, found := s.zoneCache[zoneName]
+		generation := s.zoneGeneration
+		s.zoneMu.Unlock()
+
+		if found {
+			return zoneName, entry.records, nil
+		}
+
+		zone, err := s.zoneRetriever(zoneName, true)
+		if err != nil {
+			if api.StatusErrorCheck(err, http.StatusNotFound) {
+				continue
+			}
+
+			return "", nil, err
+		}
+
+		records, err := parseZone(zone.Content)
+		if err != nil {
+			return "", nil, err
+		}
+
+		// Don't cache the records if the zone changed while rendering it.
+		s.zoneMu.Lock()
+		if s.zoneGeneration == generation {
+			s.zoneCache[zoneName] = &zoneCacheEntry{records: records, digest: zoneDigest(zone.Content)}
+		}
+
+		s.zoneMu.Unlock()
+
+		return zoneName, records, nil
+	}
+
+	return "", nil, nil
+}
+
+// serveQuery answers a regular query from the records of the zone the queried name belongs to.
+func (d *dnsHandler) serveQuery(w dns.ResponseWriter, r *dns.Msg) {
+	question := r.Question[0]
+	name := dns.Fqdn(strings.ToLower(question.Name))
+
+	zoneName, records, err := d.server.zoneRecords(strings.TrimSuffix(name, "."))
+	if err != nil || zoneName == "" {
+		m := new(dns.Msg)
+		if err != nil {
+			logger.Error("Failed loading DNS zone", logger.Ctx{"name": name, "err": err})
+			m.SetRcode(r, dns.RcodeServerFailure)
+		} else {
+			// Refuse queries for names outside of the zones.
+			m.SetRcode(r, dns.RcodeRefused)
+		}
+
+		err := w.WriteMsg(m)
+		if err != nil {
+			logger.Error("Unable to write message", logger.Ctx{"err": err})
+		}
+
+		return
+	}
+
+	// Prepare the response.
+	m := new(dns.Msg)
+	m.SetReply(r)
+	m.Authoritative = true
+
+	var soa dns.RR
+	nameExists := false
+	for _, rr := range records {
+		hdr := rr.Header()
+		if hdr.Rrtype == dns.TypeSOA && soa == nil {
+			soa = rr
+		}
+
+		if !strings.EqualFold(hdr.Name, name) {
+			// Names having records below them exist even without records of their own.
+			if dns.IsSubDomain(name, hdr.Name) {
+				nameExists = true
+			}
+
+			continue
+		}
+
+		nameExists = true
+
+		if hdr.Rrtype != question.Qtype && hdr.Rrtype != dns.TypeCNAME && question.Qtype != dns.TypeANY {
+			continue
+		}
+
+		// The SOA record is repeated at the end of the zone content.
+		if slices.ContainsFunc(m.Answer, func(answer dns.RR) bool { return dns.IsDuplicate(answer, rr) }) {
+			continue
+		}
+
+		m.Answer = append(m.Answer, rr)
+	}
+
+	// Return the SOA record of the zone along with negative answers.
+	if len(m.Answer) == 0 {
+		if !nameExists {
+			m.Rcode = dns.RcodeNameError
+		}
+
+		if soa != nil {
+			m.Ns = append(m.Ns, soa)
+		}
+	}
+
+	// Make sure the response fits in the UDP buffer of the client.
+	_, isUDP := w.RemoteAddr().(*net.UDPAddr)
+	if isUDP {
+		size := dns.MinMsgSize
+		opt := r.IsEdns0()
+		if opt != nil {
+			size = int(opt.UDPSize())
+		}
+
+		m.Truncate(size)
+	}
+
+	err = w.WriteMsg(m)
+	if err != nil {
+		logger.Error("Unable to write message", logger.Ctx{"err": err})
+	}
+}
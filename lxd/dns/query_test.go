@@ -0,0 +1,311 @@
+package dns
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+const testZoneContent = `lxd.example.net. 3600 IN SOA lxd.example.net. ns1.lxd.example.net. 1669736788 120 60 86400 30
+lxd.example.net. 300 IN NS ns1.lxd.example.net.
+c1.lxd.example.net. 300 IN A 192.0.2.125
+c1.lxd.example.net. 300 IN AAAA 2001:db8::125
+_http._tcp.web.lxd.example.net. 300 IN SRV 0 0 80 c1.lxd.example.net.
+www.lxd.example.net. 300 IN CNAME c1.lxd.example.net.
+lxd.example.net. 3600 IN SOA lxd.example.net. ns1.lxd.example.net. 1669736788 120 60 86400 30
+`
+
+const testSubZoneContent = `sub.lxd.example.net. 3600 IN SOA sub.lxd.example.net. ns1.sub.lxd.example.net. 1669736788 120 60 86400 30
+c2.sub.lxd.example.net. 300 IN A 192.0.2.126
+sub.lxd.example.net. 3600 IN SOA sub.lxd.example.net. ns1.sub.lxd.example.net. 1669736788 120 60 86400 30
+`
+
+// testZones holds the content of the zones served by a test server and counts how many times they are rendered.
+type testZones struct {
+	mu       sync.Mutex
+	contents map[string]string
+	renders  map[string]int
+}
+
+// retrieve is the zone retriever of the test server.
+func (z *testZones) retrieve(name string, full bool) (*Zone, error) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+
+	content, found := z.contents[name]
+	if !found {
+		return nil, api.StatusErrorf(http.StatusNotFound, "Network zone not found")
+	}
+
+	zone := &Zone{Info: api.NetworkZone{Name: name}}
+	if full {
+		z.renders[name]++
+		zone.Content = content
+	}
+
+	return zone, nil
+}
+
+// set changes the content of a zone.
+func (z *testZones) set(name string, content string) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+
+	z.contents[name] = content
+}
+
+// renderCount returns how many times the zone was rendered.
+func (z *testZones) renderCount(name string) int {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+
+	return z.renders[name]
+}
+
+// testSetZoneNames adds the zone names to the cached zone names, as they would be loaded from the database.
+func testSetZoneNames(s *Server, names ...string) {
+	s.zoneMu.Lock()
+	defer s.zoneMu.Unlock()
+
+	if s.zoneNames == nil {
+		s.zoneNames = make(map[string]struct{})
+	}
+
+	for _, name := range names {
+		s.zoneNames[name] = struct{}{}
+	}
+}
+
+// testServer returns an authoritative DNS server answering queries for the zones over UDP and its address.
+func testServer(t *testing.T, zones *testZones) (*Server, string) {
+	t.Helper()
+
+	s := NewServer(nil, zones.retrieve)
+	s.SetAuthoritative(true)
+	for name := range zones.contents {
+		testSetZoneNames(s, name)
+	}
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	started := make(chan struct{})
+	server := &dns.Server{PacketConn: pc, Handler: &dnsHandler{server: s}, NotifyStartedFunc: func() { close(started) }}
+	go func() { _ = server.ActivateAndServe() }()
+	t.Cleanup(func() { _ = server.Shutdown() })
+
+	<-started
+
+	return s, pc.LocalAddr().String()
+}
+
+// testQuery sends a query to the DNS server.
+func testQuery(t *testing.T, address string, name string, qtype uint16) *dns.Msg {
+	t.Helper()
+
+	m := new(dns.Msg)
+	m.SetQuestion(name, qtype)
+
+	client := &dns.Client{Net: "udp"}
+	resp, _, err := client.Exchange(m, address)
+	require.NoError(t, err)
+
+	return resp
+}
+
+func Test_dnsHandler_serveQuery(t *testing.T) {
+	zones := &testZones{
+		contents: map[string]string{"lxd.example.net": testZoneContent, "sub.lxd.example.net": testSubZoneContent},
+		renders:  map[string]int{},
+	}
+
+	_, address := testServer(t, zones)
+
+	tests := []struct {
+		name       string
+		qname      string
+		qtype      uint16
+		wantRcode  int
+		wantAnswer []string
+		wantNs     []string
+	}{
+		{
+			name:       "A record",
+			qname:      "c1.lxd.example.net.",
+			qtype:      dns.TypeA,
+			wantRcode:  dns.RcodeSuccess,
+			wantAnswer: []string{"c1.lxd.example.net.\t300\tIN\tA\t192.0.2.125"},
+		},
+		{
+			name:       "Case insensitive name",
+			qname:      "C1.LXD.example.net.",
+			qtype:      dns.TypeAAAA,
+			wantRcode:  dns.RcodeSuccess,
+			wantAnswer: []string{"c1.lxd.example.net.\t300\tIN\tAAAA\t2001:db8::125"},
+		},
+		{
+			name:       "SRV record",
+			qname:      "_http._tcp.web.lxd.example.net.",
+			qtype:      dns.TypeSRV,
+			wantRcode:  dns.RcodeSuccess,
+			wantAnswer: []string{"_http._tcp.web.lxd.example.net.\t300\tIN\tSRV\t0 0 80 c1.lxd.example.net."},
+		},
+		{
+			name:       "CNAME record",
+			qname:      "www.lxd.example.net.",
+			qtype:      dns.TypeA,
+			wantRcode:  dns.RcodeSuccess,
+			wantAnswer: []string{"www.lxd.example.net.\t300\tIN\tCNAME\tc1.lxd.example.net."},
+		},
+		{
+			name:       "SOA record isn't repeated",
+			qname:      "lxd.example.net.",
+			qtype:      dns.TypeSOA,
+			wantRcode:  dns.RcodeSuccess,
+			wantAnswer: []string{"lxd.example.net.\t3600\tIN\tSOA\tlxd.example.net. ns1.lxd.example.net. 1669736788 120 60 86400 30"},
+		},
+		{
+			name:      "No record of the type",
+			qname:     "c1.lxd.example.net.",
+			qtype:     dns.TypeTXT,
+			wantRcode: dns.RcodeSuccess,
+			wantNs:    []string{"lxd.example.net.\t3600\tIN\tSOA\tlxd.example.net. ns1.lxd.example.net. 1669736788 120 60 86400 30"},
+		},
+		{
+			name:      "Name with records below it",
+			qname:     "_tcp.web.lxd.example.net.",
+			qtype:     dns.TypeA,
+			wantRcode: dns.RcodeSuccess,
+			wantNs:    []string{"lxd.example.net.\t3600\tIN\tSOA\tlxd.example.net. ns1.lxd.example.net. 1669736788 120 60 86400 30"},
+		},
+		{
+			name:      "Unknown name",
+			qname:     "c3.lxd.example.net.",
+			qtype:     dns.TypeA,
+			wantRcode: dns.RcodeNameError,
+			wantNs:    []string{"lxd.example.net.\t3600\tIN\tSOA\tlxd.example.net. ns1.lxd.example.net. 1669736788 120 60 86400 30"},
+		},
+		{
+			name:       "Most specific zone",
+			qname:      "c2.sub.lxd.example.net.",
+			qtype:      dns.TypeA,
+			wantRcode:  dns.RcodeSuccess,
+			wantAnswer: []string{"c2.sub.lxd.example.net.\t300\tIN\tA\t192.0.2.126"},
+		},
+		{
+			name:      "Outside of the zones",
+			qname:     "example.com.",
+			qtype:     dns.TypeA,
+			wantRcode: dns.RcodeRefused,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := testQuery(t, address, tt.qname, tt.qtype)
+			assert.Equal(t, dns.RcodeToString[tt.wantRcode], dns.RcodeToString[resp.Rcode])
+
+			answer := []string{}
+			for _, rr := range resp.Answer {
+				answer = append(answer, rr.String())
+			}
+
+			ns := []string{}
+			for _, rr := range resp.Ns {
+				ns = append(ns, rr.String())
+			}
+
+			if tt.wantAnswer == nil {
+				tt.wantAnswer = []string{}
+			}
+
+			if tt.wantNs == nil {
+				tt.wantNs = []string{}
+			}
+
+			assert.Equal(t, tt.wantAnswer, answer)
+			assert.Equal(t, tt.wantNs, ns)
+
+			if tt.wantRcode != dns.RcodeRefused {
+				assert.True(t, resp.Authoritative)
+			}
+		})
+	}
+}
+
+func Test_dnsHandler_serveQueryNotAuthoritative(t *testing.T) {
+	zones := &testZones{
+		contents: map[string]string{"lxd.example.net": testZoneContent},
+		renders:  map[string]int{},
+	}
+
+	s, address := testServer(t, zones)
+	s.SetAuthoritative(false)
+
+	resp := testQuery(t, address, "c1.lxd.example.net.", dns.TypeA)
+	assert.Equal(t, dns.RcodeNotImplemented, resp.Rcode)
+	assert.Equal(t, 0, zones.renderCount("lxd.example.net"))
+}
+
+func Test_Server_zoneRecordsCache(t *testing.T) {
+	zones := &testZones{
+		contents: map[string]string{"lxd.example.net": testZoneContent},
+		renders:  map[string]int{},
+	}
+
+	s, address := testServer(t, zones)
+
+	// The zone is only rendered once.
+	for range 3 {
+		resp := testQuery(t, address, "c1.lxd.example.net.", dns.TypeA)
+		assert.Len(t, resp.Answer, 1)
+	}
+
+	assert.Equal(t, 1, zones.renderCount("lxd.example.net"))
+
+	// Names outside of the zones don't render anything.
+	resp := testQuery(t, address, "c1.example.com.", dns.TypeA)
+	assert.Equal(t, dns.RcodeRefused, resp.Rcode)
+	assert.Equal(t, 1, zones.renderCount("lxd.example.net"))
+
+	// Changing the zone drops the cached records.
+	zones.set("lxd.example.net", testZoneContent+"c3.lxd.example.net. 300 IN A 192.0.2.127\n")
+	s.Invalidate("lxd.example.net")
+	testSetZoneNames(s, "lxd.example.net") // Loaded from the database otherwise.
+
+	resp = testQuery(t, address, "c3.lxd.example.net.", dns.TypeA)
+	assert.Equal(t, dns.RcodeSuccess, resp.Rcode)
+	assert.Len(t, resp.Answer, 1)
+	assert.Equal(t, 2, zones.renderCount("lxd.example.net"))
+}
+
+func Test_Server_zoneRecordsGeneration(t *testing.T) {
+	zones := &testZones{
+		contents: map[string]string{"lxd.example.net": testZoneContent},
+		renders:  map[string]int{},
+	}
+
+	s := NewServer(nil, nil)
+	testSetZoneNames(s, "lxd.example.net")
+
+	// A zone changing while it is rendered isn't cached.
+	s.zoneRetriever = func(name string, full bool) (*Zone, error) {
+		s.Invalidate(name)
+		testSetZoneNames(s, name)
+
+		return zones.retrieve(name, full)
+	}
+
+	zoneName, records, err := s.zoneRecords("c1.lxd.example.net")
+	require.NoError(t, err)
+	assert.Equal(t, "lxd.example.net", zoneName)
+	assert.NotEmpty(t, records)
+	assert.Empty(t, s.zoneCache)
+}
@@ -3,6 +3,7 @@ package dns
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 
 	"github.com/miekg/dns"
 
@@ -25,7 +26,16 @@ type Server struct {
 	zoneRetriever ZoneRetriever
 
 	// Internal state (to handle reconfiguration).
-	address string
+	address       string
+	authoritative atomic.Bool
+
+	// Zone names and rendered zones used to answer regular queries and zone digests used to detect changes.
+	// The generation is increased each time a zone changes, to avoid caching a zone rendered before the change.
+	zoneNames      map[string]struct{}
+	zoneCache      map[string]*zoneCacheEntry
+	zoneDigests    map[string]string
+	zoneGeneration uint64
+	zoneMu         sync.Mutex
 
 	mu sync.Mutex
 }
@@ -33,10 +43,22 @@ type Server struct {
 // NewServer returns a new server instance.
 func NewServer(db *db.Cluster, retriever ZoneRetriever) *Server {
 	// Setup new struct.
-	s := &Server{db: db, zoneRetriever: retriever}
+	s := &Server{
+		db:            db,
+		zoneRetriever: retriever,
+		zoneCache:     make(map[string]*zoneCacheEntry),
+		zoneDigests:   make(map[string]string),
+	}
+
 	return s
 }
 
+// SetAuthoritative sets whether the server answers regular queries for the records of the network zones in
+// addition to zone transfers.
+func (s *Server) SetAuthoritative(enabled bool) {
+	s.authoritative.Store(enabled)
+}
+
 // Start sets up the DNS listener.
 func (s *Server) Start(address string) error {
 	// Locking.
@@ -351,6 +351,21 @@ func AllocateTask(opts *Options, f func(*Transaction) error) error {
 		if err != nil {
 			return err
 		}
+
+		// Don't allocate the addresses reserved for the hosts that aren't instances.
+		for _, reservation := range dnsmasq.DHCPReservations(opts.Network.Config()) {
+			if reservation.IPv4 != nil {
+				var IPKey [4]byte
+				copy(IPKey[:], reservation.IPv4.To4())
+				t.allocationsDHCPv4[IPKey] = dnsmasq.DHCPAllocation{IP: reservation.IPv4, MAC: reservation.MAC}
+			}
+
+			if reservation.IPv6 != nil {
+				var IPKey [16]byte
+				copy(IPKey[:], reservation.IPv6.To16())
+				t.allocationsDHCPv6[IPKey] = dnsmasq.DHCPAllocation{IP: reservation.IPv6, MAC: reservation.MAC}
+			}
+		}
 	}
 
 	// Run the supplied allocation function.
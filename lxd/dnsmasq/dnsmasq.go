@@ -2,9 +2,15 @@ package dnsmasq
 
 import (
 	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"net"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -25,6 +31,14 @@ type DHCPAllocation struct {
 	MAC            net.HardwareAddr
 }
 
+// DHCPReservation represents a static DHCP allocation configured on a network for a host that isn't an instance.
+type DHCPReservation struct {
+	Name string
+	MAC  net.HardwareAddr
+	IPv4 net.IP
+	IPv6 net.IP
+}
+
 // ConfigMutex used to coordinate access to the dnsmasq config files.
 var ConfigMutex sync.Mutex
 
@@ -46,11 +60,17 @@ func UpdateStaticEntry(network string, projectName string, instanceName string,
 		line += "," + project.DNS(projectName, instanceName)
 	}
 
+	deviceStaticFileName := StaticAllocationFileName(projectName, instanceName, deviceName)
+
+	// Tag the host so that the DHCP options of the device apply to it.
+	if shared.PathExists(DHCPOptionsPath(network, deviceStaticFileName)) {
+		line = hwaddr + ",set:" + dhcpOptionsTag(deviceStaticFileName) + strings.TrimPrefix(line, hwaddr)
+	}
+
 	if line == hwaddr {
 		return nil
 	}
 
-	deviceStaticFileName := StaticAllocationFileName(projectName, instanceName, deviceName)
 	err := os.WriteFile(shared.VarPath("networks", network, "dnsmasq.hosts", deviceStaticFileName), []byte(line+"\n"), 0644)
 	if err != nil {
 		return err
@@ -59,7 +79,7 @@ func UpdateStaticEntry(network string, projectName string, instanceName string,
 	return nil
 }
 
-// RemoveStaticEntry removes a single dhcp-host line for a network/instance combination.
+// RemoveStaticEntry removes a single dhcp-host line (and the DHCP options) for a network/instance combination.
 func RemoveStaticEntry(network string, projectName string, instanceName string, deviceName string) error {
 	deviceStaticFileName := StaticAllocationFileName(projectName, instanceName, deviceName)
 	err := os.Remove(shared.VarPath("networks", network, "dnsmasq.hosts", deviceStaticFileName))
@@ -67,9 +87,193 @@ func RemoveStaticEntry(network string, projectName string, instanceName string,
 		return err
 	}
 
+	err = os.Remove(DHCPOptionsPath(network, deviceStaticFileName))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateOptionsEntry writes the dhcp-option lines for a network/instance combination.
+// The options only apply to the host once its dhcp-host line is rebuilt using UpdateStaticEntry.
+// Passing no options removes the existing options of the device.
+func UpdateOptionsEntry(network string, projectName string, instanceName string, deviceName string, options []string) error {
+	deviceStaticFileName := StaticAllocationFileName(projectName, instanceName, deviceName)
+	optionsPath := DHCPOptionsPath(network, deviceStaticFileName)
+
+	if len(options) == 0 {
+		err := os.Remove(optionsPath)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		return nil
+	}
+
+	tag := dhcpOptionsTag(deviceStaticFileName)
+
+	var sb strings.Builder
+	for _, option := range options {
+		sb.WriteString("tag:" + tag + "," + option + "\n")
+	}
+
+	err := os.MkdirAll(shared.VarPath("networks", network, "dnsmasq.opts"), 0755)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(optionsPath, []byte(sb.String()), 0644)
+}
+
+// DHCPOptionsPath returns the path to the DHCP options file of an instance device.
+func DHCPOptionsPath(network string, deviceStaticFileName string) string {
+	return shared.VarPath("networks", network, "dnsmasq.opts", deviceStaticFileName)
+}
+
+// dhcpOptionsTag returns the dnsmasq tag used to apply the DHCP options of an instance device.
+// The file name is hashed as dnsmasq tags cannot contain all the characters allowed in device names.
+func dhcpOptionsTag(deviceStaticFileName string) string {
+	hash := sha256.Sum256([]byte(deviceStaticFileName))
+
+	return "lxd-" + hex.EncodeToString(hash[:8])
+}
+
+// DHCPOptions returns the dnsmasq dhcp-option values for the "ipv{n}.dhcp.option.NAME" keys of the config.
+// The options are sorted by name so that the result is stable.
+func DHCPOptions(config map[string]string, ipVersion uint) []string {
+	prefix := fmt.Sprintf("ipv%d.dhcp.option.", ipVersion)
+
+	names := []string{}
+	for k := range config {
+		name, ok := strings.CutPrefix(k, prefix)
+		if ok && config[k] != "" {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	options := make([]string, 0, len(names))
+	for _, name := range names {
+		option := name
+		if ipVersion == 6 {
+			option = "option6:" + name
+		} else if !isDHCPOptionNumber(name) {
+			option = "option:" + name
+		}
+
+		options = append(options, option+","+config[prefix+name])
+	}
+
+	return options
+}
+
+// ValidateDHCPOption validates the name and value of a "ipv{n}.dhcp.option.NAME" key.
+// The name is either the option number or the dnsmasq name of the option (such as "ntp-server").
+func ValidateDHCPOption(name string, value string) error {
+	if name == "" {
+		return errors.New("Missing DHCP option name")
+	}
+
+	if !isDHCPOptionNumber(name) {
+		for _, r := range name {
+			if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+				return fmt.Errorf("Invalid DHCP option name %q", name)
+			}
+		}
+	}
+
+	if strings.ContainsAny(value, "\n\r") {
+		return errors.New("DHCP option values cannot contain line breaks")
+	}
+
+	return nil
+}
+
+// DHCPReservations returns the reservations defined by the "dhcp.reservations.NAME.*" keys of the network config.
+// The reservations are sorted by name and the ones without a valid MAC address are skipped.
+func DHCPReservations(config map[string]string) []DHCPReservation {
+	names := []string{}
+	for k := range config {
+		name, ok := strings.CutPrefix(k, "dhcp.reservations.")
+		if !ok || !strings.HasSuffix(name, ".hwaddr") {
+			continue
+		}
+
+		names = append(names, strings.TrimSuffix(name, ".hwaddr"))
+	}
+
+	sort.Strings(names)
+
+	reservations := make([]DHCPReservation, 0, len(names))
+	for _, name := range names {
+		prefix := "dhcp.reservations." + name + "."
+
+		mac, err := net.ParseMAC(config[prefix+"hwaddr"])
+		if err != nil {
+			continue
+		}
+
+		reservations = append(reservations, DHCPReservation{
+			Name: name,
+			MAC:  mac,
+			IPv4: net.ParseIP(config[prefix+"ipv4.address"]).To4(),
+			IPv6: net.ParseIP(config[prefix+"ipv6.address"]),
+		})
+	}
+
+	return reservations
+}
+
+// DHCPReservationConflict returns an error if the IP address is reserved for another MAC address.
+// Any reservation of the IP address conflicts if the MAC address is nil.
+func DHCPReservationConflict(reservations []DHCPReservation, mac net.HardwareAddr, ip net.IP) error {
+	if ip == nil {
+		return nil
+	}
+
+	for _, reservation := range reservations {
+		if !ip.Equal(reservation.IPv4) && !ip.Equal(reservation.IPv6) {
+			continue
+		}
+
+		if mac != nil && bytes.Equal(mac, reservation.MAC) {
+			continue
+		}
+
+		return fmt.Errorf("IP address %q is reserved by DHCP reservation %q", ip.String(), reservation.Name)
+	}
+
 	return nil
 }
 
+// DHCPReservationHost returns the dnsmasq dhcp-host value of a reservation.
+func DHCPReservationHost(reservation DHCPReservation, netConfig map[string]string) string {
+	host := reservation.MAC.String()
+
+	if reservation.IPv4 != nil {
+		host += "," + reservation.IPv4.String()
+	}
+
+	if reservation.IPv6 != nil {
+		host += ",[" + reservation.IPv6.String() + "]"
+	}
+
+	if netConfig["dns.mode"] == "" || netConfig["dns.mode"] == "managed" {
+		host += "," + reservation.Name
+	}
+
+	return host
+}
+
+// isDHCPOptionNumber returns whether the DHCP option name is an option number.
+func isDHCPOptionNumber(name string) bool {
+	_, err := strconv.ParseUint(name, 10, 16)
+
+	return err == nil
+}
+
 // Kill kills dnsmasq for a particular network (or optionally reloads it).
 func Kill(name string, reload bool) error {
 	pidPath := shared.VarPath("networks", name, "dnsmasq.pid")
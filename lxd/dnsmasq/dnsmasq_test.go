@@ -1,6 +1,7 @@
 package dnsmasq
 
 import (
+	"net"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -13,3 +14,105 @@ func Test_staticAllocationFileName(t *testing.T) {
 	fileName := StaticAllocationFileName(projectName, instanceName, deviceName)
 	assert.Equal(t, "test.project_test-instance.test-.--_----.device", fileName)
 }
+
+func Test_DHCPOptions(t *testing.T) {
+	config := map[string]string{
+		"ipv4.dhcp.option.ntp-server":    "192.0.2.1,192.0.2.2",
+		"ipv4.dhcp.option.42":            "192.0.2.3",
+		"ipv4.dhcp.option.domain-search": "",
+		"ipv6.dhcp.option.sntp-server":   "2001:db8::1",
+		"ipv4.dhcp.expiry":               "1h",
+	}
+
+	assert.Equal(t, []string{"42,192.0.2.3", "option:ntp-server,192.0.2.1,192.0.2.2"}, DHCPOptions(config, 4))
+	assert.Equal(t, []string{"option6:sntp-server,2001:db8::1"}, DHCPOptions(config, 6))
+}
+
+func Test_ValidateDHCPOption(t *testing.T) {
+	assert.NoError(t, ValidateDHCPOption("42", "192.0.2.1"))
+	assert.NoError(t, ValidateDHCPOption("bootfile-name", "pxelinux.0"))
+	assert.Error(t, ValidateDHCPOption("", "value"))
+	assert.Error(t, ValidateDHCPOption("option:ntp-server", "192.0.2.1"))
+	assert.Error(t, ValidateDHCPOption("ntp-server", "192.0.2.1\nbogus"))
+}
+
+func Test_DHCPReservations(t *testing.T) {
+	config := map[string]string{
+		"dhcp.reservations.printer.hwaddr":       "00:16:3e:00:00:02",
+		"dhcp.reservations.printer.ipv4.address": "10.0.0.20",
+		"dhcp.reservations.nas.hwaddr":           "00:16:3E:00:00:01",
+		"dhcp.reservations.nas.ipv4.address":     "10.0.0.10",
+		"dhcp.reservations.nas.ipv6.address":     "fd42::10",
+		"dhcp.reservations.broken.ipv4.address":  "10.0.0.30",
+	}
+
+	reservations := DHCPReservations(config)
+	assert.Len(t, reservations, 2)
+	assert.Equal(t, "nas", reservations[0].Name)
+	assert.Equal(t, "printer", reservations[1].Name)
+	assert.Nil(t, reservations[1].IPv6)
+
+	assert.Equal(t, "00:16:3e:00:00:01,10.0.0.10,[fd42::10],nas", DHCPReservationHost(reservations[0], map[string]string{}))
+	assert.Equal(t, "00:16:3e:00:00:02,10.0.0.20", DHCPReservationHost(reservations[1], map[string]string{"dns.mode": "none"}))
+}
+
+func Test_DHCPReservationConflict(t *testing.T) {
+	reservations := DHCPReservations(map[string]string{
+		"dhcp.reservations.nas.hwaddr":       "00:16:3e:00:00:01",
+		"dhcp.reservations.nas.ipv4.address": "10.0.0.10",
+		"dhcp.reservations.nas.ipv6.address": "fd42::10",
+	})
+
+	reservedMAC, _ := net.ParseMAC("00:16:3e:00:00:01")
+	otherMAC, _ := net.ParseMAC("00:16:3e:00:00:02")
+
+	tests := []struct {
+		name    string
+		mac     net.HardwareAddr
+		ip      string
+		wantErr string
+	}{
+		{
+			name: "Unreserved address",
+			mac:  otherMAC,
+			ip:   "10.0.0.11",
+		},
+		{
+			name: "No static address",
+			mac:  otherMAC,
+		},
+		{
+			name: "Reserved for the same MAC address",
+			mac:  reservedMAC,
+			ip:   "10.0.0.10",
+		},
+		{
+			name:    "Reserved IPv4 address",
+			mac:     otherMAC,
+			ip:      "10.0.0.10",
+			wantErr: `IP address "10.0.0.10" is reserved by DHCP reservation "nas"`,
+		},
+		{
+			name:    "Reserved IPv6 address in another form",
+			mac:     otherMAC,
+			ip:      "fd42:0:0::10",
+			wantErr: `IP address "fd42::10" is reserved by DHCP reservation "nas"`,
+		},
+		{
+			name:    "Unknown MAC address",
+			ip:      "10.0.0.10",
+			wantErr: `IP address "10.0.0.10" is reserved by DHCP reservation "nas"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := DHCPReservationConflict(reservations, tt.mac, net.ParseIP(tt.ip))
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -258,6 +258,25 @@ func (d placementGroupDeleter) Delete(ctx context.Context, s *state.State, ref e
 	return nil
 }
 
+type networkAddressSetDeleter struct{}
+
+// Delete deletes a network address set.
+func (d networkAddressSetDeleter) Delete(ctx context.Context, s *state.State, ref entity.Reference) error {
+	name := ref.Name()
+
+	err := s.Authorizer.CheckPermission(ctx, ref.URL(), auth.EntitlementCanDelete)
+	if err != nil {
+		return err
+	}
+
+	err = doNetworkAddressSetDelete(ctx, s, name, ref.ProjectName)
+	if err != nil {
+		return fmt.Errorf("Failed deleting network address set %q: %w", name, err)
+	}
+
+	return nil
+}
+
 // getEntityDeleter returns a deleter implementation for the given entity type.
 func getEntityDeleter(t entity.Type) (entityDeleter, error) {
 	switch t {
@@ -279,6 +298,8 @@ func getEntityDeleter(t entity.Type) (entityDeleter, error) {
 		return profileDeleter{}, nil
 	case entity.TypePlacementGroup:
 		return placementGroupDeleter{}, nil
+	case entity.TypeNetworkAddressSet:
+		return networkAddressSetDeleter{}, nil
 	default:
 		return nil, fmt.Errorf("Unsupported entity type %q", t)
 	}
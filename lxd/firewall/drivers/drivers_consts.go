@@ -12,9 +12,10 @@ type FeatureOpts struct {
 
 // SNATOpts specify how SNAT rules are setup.
 type SNATOpts struct {
-	Append      bool       // Append rules (has no effect if driver doesn't support it).
-	Subnet      *net.IPNet // Subnet of source network used to identify candidate traffic.
-	SNATAddress net.IP     // SNAT IP address to use. If nil then MASQUERADE is used.
+	Append            bool       // Append rules (has no effect if driver doesn't support it).
+	Subnet            *net.IPNet // Subnet of source network used to identify candidate traffic.
+	SNATAddress       net.IP     // SNAT IP address to use. If nil then MASQUERADE is used.
+	ExcludeInterfaces []string   // Output interfaces whose traffic isn't translated (such as routed tunnels).
 }
 
 // Opts for setting up the firewall.
@@ -32,6 +33,7 @@ type ACLRule struct {
 	Action          string
 	Log             bool   // Whether or not to log matched packets.
 	LogName         string // Log label name (requires Log be true).
+	Comment         string // Identifier of the rule used to retrieve its counters (optional).
 	Source          string
 	Destination     string
 	Protocol        string
@@ -41,6 +43,18 @@ type ACLRule struct {
 	ICMPCode        string
 }
 
+// AddressSet represents a named set of addresses that ACL rules can match using the "$<Name>" subject.
+type AddressSet struct {
+	Name      string   // Base name of the set (a suffix is added for each IP family).
+	Addresses []string // IP addresses and CIDR subnets of either IP family.
+}
+
+// ACLRuleCounters represents the traffic matched by the firewall rules generated from an ACL rule.
+type ACLRuleCounters struct {
+	Packets uint64
+	Bytes   uint64
+}
+
 // AddressForward represents a NAT address forward.
 type AddressForward struct {
 	ListenAddress net.IP
@@ -49,3 +63,17 @@ type AddressForward struct {
 	ListenPorts   []uint64
 	TargetPorts   []uint64
 }
+
+// LoadBalancerTarget represents a load balancer backend address and port.
+type LoadBalancerTarget struct {
+	Address net.IP
+	Port    uint64
+}
+
+// LoadBalancer represents a NAT load balancer listen port and the backends its connections are spread across.
+type LoadBalancer struct {
+	ListenAddress net.IP
+	Protocol      string
+	ListenPort    uint64
+	Targets       []LoadBalancerTarget
+}
@@ -128,11 +128,11 @@ func (d Nftables) Compat() (bool, error) {
 
 // nftGenericItem represents some common fields amongst the different nftables types.
 type nftGenericItem struct {
-	itemType string // Type of item (table, chain or rule). Populated by LXD.
+	itemType string // Type of item (table, chain, set or rule). Populated by LXD.
 	Family   string `json:"family"` // Family of item (ip, ip6, bridge etc).
 	Table    string `json:"table"`  // Table the item belongs to (for chains and rules).
 	Chain    string `json:"chain"`  // Chain the item belongs to (for rules).
-	Name     string `json:"name"`   // Name of item (for tables and chains).
+	Name     string `json:"name"`   // Name of item (for tables, chains and sets).
 }
 
 // nftParseRuleset parses the ruleset and returns the generic parts as a slice of items.
@@ -166,9 +166,13 @@ func (d Nftables) nftParseRuleset() ([]nftGenericItem, error) {
 		rule, foundRule := item["rule"]
 		chain, foundChain := item["chain"]
 		table, foundTable := item["table"]
+		set, foundSet := item["set"]
 		if foundRule {
 			rule.itemType = "rule"
 			items = append(items, rule)
+		} else if foundSet {
+			set.itemType = "set"
+			items = append(items, set)
 		} else if foundChain {
 			chain.itemType = "chain"
 			items = append(items, chain)
@@ -369,6 +373,7 @@ func (d Nftables) NetworkClear(networkName string, _ bool, _ []uint) error {
 		"fwd", "pstrt", "in", "out", // Chains used for network operation rules.
 		"aclin", "aclout", "aclfwd", "acl", // Chains used by ACL rules.
 		"fwdprert", "fwdout", "fwdpstrt", // Chains used by Address Forward rules.
+		"lbprert", "lbout", "lbpstrt", // Chains used by Load Balancer rules.
 		"egress", // Chains added for limits.priority option
 	}
 
@@ -704,8 +709,14 @@ func (d Nftables) InstanceClearNetPrio(projectName string, instanceName string,
 
 // NetworkApplyACLRules applies ACL rules to the existing firewall chains.
 func (d Nftables) NetworkApplyACLRules(networkName string, rules []ACLRule) error {
-	nftRules := make([]string, 0)
+	// Split the rules referencing address sets so each named set is matched on its own.
+	expandedRules := make([]ACLRule, 0, len(rules))
 	for _, rule := range rules {
+		expandedRules = append(expandedRules, aclRuleExpandAddressSets(rule)...)
+	}
+
+	nftRules := make([]string, 0)
+	for _, rule := range expandedRules {
 		// First try generating rules with IPv4 or IP agnostic criteria.
 		nftRule, partial, err := d.aclRuleCriteriaToRules(networkName, 4, &rule)
 		if err != nil {
@@ -852,6 +863,11 @@ func (d Nftables) aclRuleCriteriaToRules(networkName string, ipVersion uint, rul
 		}
 	}
 
+	// Count the matched packets so they can be reported per rule.
+	if rule.Comment != "" {
+		args = append(args, "counter")
+	}
+
 	// Handle logging.
 	if rule.Log {
 		args = append(args, "log")
@@ -870,19 +886,161 @@ func (d Nftables) aclRuleCriteriaToRules(networkName string, ipVersion uint, rul
 
 	args = append(args, action)
 
+	if rule.Comment != "" {
+		args = append(args, "comment", `"`+rule.Comment+`"`)
+	}
+
 	return strings.Join(args, " "), isPartialRule, nil
 }
 
+// NetworkACLRuleCounters returns the counters of the ACL rules applied to the network, indexed by rule comment.
+// The counters of the rules generated for both IP families from the same ACL rule are added together.
+func (d Nftables) NetworkACLRuleCounters(networkName string) (map[string]ACLRuleCounters, error) {
+	out, err := shared.RunCommandContext(context.TODO(), "nft", "--json", "list", "chain", "inet", nftablesNamespace, "acl"+nftablesChainSeparator+networkName)
+	if err != nil {
+		return nil, fmt.Errorf("Failed listing ACL rules of network %q: %w", networkName, err)
+	}
+
+	counters, err := nftablesACLRuleCounters(out)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing ACL rules of network %q: %w", networkName, err)
+	}
+
+	return counters, nil
+}
+
+// nftablesACLRuleCounters parses the JSON output of "nft --json list chain" and returns the counters of the
+// rules, indexed by rule comment.
+func nftablesACLRuleCounters(out string) (map[string]ACLRuleCounters, error) {
+	// This only extracts the comment and counter of the rules, see man libnftables-json for more info.
+	v := &struct {
+		Nftables []struct {
+			Rule *struct {
+				Comment string `json:"comment"`
+				Expr    []struct {
+					Counter *struct {
+						Packets uint64 `json:"packets"`
+						Bytes   uint64 `json:"bytes"`
+					} `json:"counter"`
+				} `json:"expr"`
+			} `json:"rule"`
+		} `json:"nftables"`
+	}{}
+
+	err := json.Unmarshal([]byte(out), v)
+	if err != nil {
+		return nil, err
+	}
+
+	counters := map[string]ACLRuleCounters{}
+	for _, item := range v.Nftables {
+		if item.Rule == nil || item.Rule.Comment == "" {
+			continue
+		}
+
+		for _, expr := range item.Rule.Expr {
+			if expr.Counter == nil {
+				continue
+			}
+
+			ruleCounters := counters[item.Rule.Comment]
+			ruleCounters.Packets += expr.Counter.Packets
+			ruleCounters.Bytes += expr.Counter.Bytes
+			counters[item.Rule.Comment] = ruleCounters
+		}
+	}
+
+	return counters, nil
+}
+
+// NetworkApplyAddressSets creates the named sets used by ACL rules and atomically replaces their contents.
+func (d Nftables) NetworkApplyAddressSets(sets []AddressSet) error {
+	nftSets := make([]map[string]string, 0, len(sets)*2)
+	for _, set := range sets {
+		for _, ipVersion := range []uint{4, 6} {
+			setType := "ipv4_addr"
+			if ipVersion == 6 {
+				setType = "ipv6_addr"
+			}
+
+			nftSets = append(nftSets, map[string]string{
+				"name":      addressSetFamilyName(set.Name, ipVersion),
+				"type":      setType,
+				"addresses": strings.Join(addressSetFamilyAddresses(set, ipVersion), ", "),
+			})
+		}
+	}
+
+	tplFields := map[string]any{
+		"namespace": nftablesNamespace,
+		"family":    "inet",
+		"sets":      nftSets,
+	}
+
+	config := &strings.Builder{}
+	err := nftablesNetAddressSets.Execute(config, tplFields)
+	if err != nil {
+		return fmt.Errorf("Failed running %q template: %w", nftablesNetAddressSets.Name(), err)
+	}
+
+	err = shared.RunCommandWithFds(context.TODO(), strings.NewReader(config.String()), nil, "nft", "-f", "-")
+	if err != nil {
+		return fmt.Errorf("Failed applying address sets: %w", err)
+	}
+
+	return nil
+}
+
+// NetworkDeleteAddressSets removes the named sets of the address sets if they exist.
+func (d Nftables) NetworkDeleteAddressSets(names []string) error {
+	ruleset, err := d.nftParseRuleset()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		for _, ipVersion := range []uint{4, 6} {
+			setName := addressSetFamilyName(name, ipVersion)
+
+			for _, item := range ruleset {
+				if item.itemType != "set" || item.Family != "inet" || item.Table != nftablesNamespace || item.Name != setName {
+					continue
+				}
+
+				_, err = shared.RunCommandContext(context.TODO(), "nft", "delete", "set", "inet", nftablesNamespace, setName)
+				if err != nil {
+					return fmt.Errorf("Failed deleting nftables set %q: %w", setName, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
 // aclRuleSubjectToACLMatch converts direction (source/destination) and subject criteria list into xtables args.
 // Returns nil if none of the subjects are appropriate for the ipVersion.
 func (d Nftables) aclRuleSubjectToACLMatch(direction string, ipVersion uint, subjectCriteria ...string) ([]string, bool, error) {
 	fieldParts := make([]string, 0, len(subjectCriteria))
 
 	partial := false
+	setName := ""
 
 	// For each criterion check if value looks like IP CIDR.
 	for _, subjectCriterion := range subjectCriteria {
-		if validate.IsNetworkRange(subjectCriterion) == nil {
+		if strings.HasPrefix(subjectCriterion, "$") {
+			// Subject is an IP family specific address set reference.
+			if ipVersion != subjectIPVersion(subjectCriterion) {
+				partial = true
+				continue // Skip subjects that are not for the ipVersion we are looking for.
+			}
+
+			if setName != "" || len(subjectCriteria) > 1 {
+				return nil, false, fmt.Errorf("Address set %q cannot be combined with other subjects", subjectCriterion)
+			}
+
+			setName = strings.TrimPrefix(subjectCriterion, "$")
+		} else if validate.IsNetworkRange(subjectCriterion) == nil {
 			criterionParts := strings.SplitN(subjectCriterion, "-", 2)
 
 			if len(criterionParts) <= 1 {
@@ -927,12 +1085,16 @@ func (d Nftables) aclRuleSubjectToACLMatch(direction string, ipVersion uint, sub
 		}
 	}
 
-	if len(fieldParts) > 0 {
-		ipFamily := "ip"
-		if ipVersion == 6 {
-			ipFamily = "ip6"
-		}
+	ipFamily := "ip"
+	if ipVersion == 6 {
+		ipFamily = "ip6"
+	}
 
+	if setName != "" {
+		return []string{ipFamily, direction, "@" + setName}, partial, nil
+	}
+
+	if len(fieldParts) > 0 {
 		return []string{ipFamily, direction, "{" + strings.Join(fieldParts, ",") + "}"}, partial, nil
 	}
 
@@ -1089,3 +1251,109 @@ func (d Nftables) NetworkApplyForwards(networkName string, rules []AddressForwar
 
 	return nil
 }
+
+// NetworkApplyLoadBalancers apply network load balancers. Connections to each listen port are spread across its
+// targets in turn using a numgen map.
+func (d Nftables) NetworkApplyLoadBalancers(networkName string, loadBalancers []LoadBalancer) error {
+	// Remove chains if no load balancers.
+	if len(loadBalancers) == 0 {
+		err := d.removeChains([]string{"inet"}, networkName, "lbprert", "lbout", "lbpstrt")
+		if err != nil {
+			return fmt.Errorf("Failed clearing nftables load balancer rules for network %q: %w", networkName, err)
+		}
+
+		return nil
+	}
+
+	config, err := nftablesLoadBalancersConfig(networkName, loadBalancers)
+	if err != nil {
+		return err
+	}
+
+	err = shared.RunCommandWithFds(context.TODO(), strings.NewReader(config), nil, "nft", "-f", "-")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// nftablesLoadBalancersConfig returns the nftables rules of the network load balancers.
+func nftablesLoadBalancersConfig(networkName string, loadBalancers []LoadBalancer) (string, error) {
+	var dnatRules []map[string]any
+	var snatRules []map[string]any
+
+	// Used to only add one masquerade rule per target.
+	snatTargets := make(map[string]struct{})
+
+	for i, lb := range loadBalancers {
+		// Validate the load balancer.
+		if lb.ListenAddress == nil {
+			return "", fmt.Errorf("Invalid load balancer %d, listen address is required", i)
+		}
+
+		if lb.Protocol == "" || lb.ListenPort == 0 {
+			return "", fmt.Errorf("Invalid load balancer %d, protocol and listen port are required", i)
+		}
+
+		if len(lb.Targets) == 0 {
+			return "", fmt.Errorf("Invalid load balancer %d, at least one target is required", i)
+		}
+
+		ipFamily := "ip"
+		if lb.ListenAddress.To4() == nil {
+			ipFamily = "ip6"
+		}
+
+		targetMap := make([]string, 0, len(lb.Targets))
+		for targetIndex, target := range lb.Targets {
+			if target.Address == nil || target.Port == 0 {
+				return "", fmt.Errorf("Invalid load balancer %d, target address and port are required", i)
+			}
+
+			targetHost := target.Address.String()
+			targetPort := strconv.FormatUint(target.Port, 10)
+			targetMap = append(targetMap, strconv.Itoa(targetIndex)+" : "+targetHost+" . "+targetPort)
+
+			snatKey := lb.Protocol + "/" + targetHost + "/" + targetPort
+			_, found := snatTargets[snatKey]
+			if found {
+				continue
+			}
+
+			snatTargets[snatKey] = struct{}{}
+			snatRules = append(snatRules, map[string]any{
+				"ipFamily":   ipFamily,
+				"protocol":   lb.Protocol,
+				"targetHost": targetHost,
+				"targetPort": targetPort,
+			})
+		}
+
+		dnatRules = append(dnatRules, map[string]any{
+			"ipFamily":      ipFamily,
+			"protocol":      lb.Protocol,
+			"listenAddress": lb.ListenAddress.String(),
+			"listenPort":    lb.ListenPort,
+			"targetCount":   len(lb.Targets),
+			"targetMap":     strings.Join(targetMap, ", "),
+		})
+	}
+
+	tplFields := map[string]any{
+		"namespace":      nftablesNamespace,
+		"chainSeparator": nftablesChainSeparator,
+		"family":         "inet",
+		"label":          networkName,
+		"dnatRules":      dnatRules,
+		"snatRules":      snatRules,
+	}
+
+	config := &strings.Builder{}
+	err := nftablesNetLoadBalancers.Execute(config, tplFields)
+	if err != nil {
+		return "", fmt.Errorf("Failed running %q template: %w", nftablesNetLoadBalancers.Name(), err)
+	}
+
+	return config.String(), nil
+}
@@ -36,9 +36,9 @@ chain pstrt{{.chainSeparator}}{{.networkName}} {
 	# It's important to check for both the destination address and the output interface
 	# to not falsely snat/masquerade multicast traffic whose destination address it outside of the subnet.
 	# In case br_netfilter is loaded on the host multicast traffic also traverses the postrouting chain.
-	{{$ipFamily}} saddr {{$config.Subnet}} {{$ipFamily}} daddr != {{$config.Subnet}} oifname != {{$.networkName}} snat {{$config.SNATAddress}}
+	{{$ipFamily}} saddr {{$config.Subnet}} {{$ipFamily}} daddr != {{$config.Subnet}} oifname != { {{$.networkName}}{{range $config.ExcludeInterfaces}}, {{.}}{{end}} } snat {{$config.SNATAddress}}
 	{{else -}}
-	{{$ipFamily}} saddr {{$config.Subnet}} {{$ipFamily}} daddr != {{$config.Subnet}} oifname != {{$.networkName}} masquerade
+	{{$ipFamily}} saddr {{$config.Subnet}} {{$ipFamily}} daddr != {{$config.Subnet}} oifname != { {{$.networkName}}{{range $config.ExcludeInterfaces}}, {{.}}{{end}} } masquerade
 	{{- end}}
 	{{- end}}
 }
@@ -121,6 +121,39 @@ table {{.family}} {{.namespace}} {
 }
 `))
 
+var nftablesNetLoadBalancers = template.Must(template.New("nftablesNetLoadBalancers").Parse(`
+add table {{.family}} {{.namespace}}
+add chain {{.family}} {{.namespace}} lbprert{{.chainSeparator}}{{.label}} {type nat hook prerouting priority -100; policy accept;}
+add chain {{.family}} {{.namespace}} lbout{{.chainSeparator}}{{.label}} {type nat hook output priority -100; policy accept;}
+add chain {{.family}} {{.namespace}} lbpstrt{{.chainSeparator}}{{.label}} {type nat hook postrouting priority 100; policy accept;}
+flush chain {{.family}} {{.namespace}} lbprert{{.chainSeparator}}{{.label}}
+flush chain {{.family}} {{.namespace}} lbout{{.chainSeparator}}{{.label}}
+flush chain {{.family}} {{.namespace}} lbpstrt{{.chainSeparator}}{{.label}}
+
+table {{.family}} {{.namespace}} {
+	chain lbprert{{.chainSeparator}}{{.label}} {
+		type nat hook prerouting priority -100; policy accept;
+		{{- range .dnatRules}}
+		{{.ipFamily}} daddr {{.listenAddress}} {{.protocol}} dport {{.listenPort}} dnat {{.ipFamily}} to numgen inc mod {{.targetCount}} map { {{.targetMap}} }
+		{{- end}}
+	}
+
+	chain lbout{{.chainSeparator}}{{.label}} {
+		type nat hook output priority -100; policy accept;
+		{{- range .dnatRules}}
+		{{.ipFamily}} daddr {{.listenAddress}} {{.protocol}} dport {{.listenPort}} dnat {{.ipFamily}} to numgen inc mod {{.targetCount}} map { {{.targetMap}} }
+		{{- end}}
+	}
+
+	chain lbpstrt{{.chainSeparator}}{{.label}} {
+		type nat hook postrouting priority 100; policy accept;
+		{{- range .snatRules}}
+		{{.ipFamily}} saddr {{.targetHost}} {{.ipFamily}} daddr {{.targetHost}} {{.protocol}} dport {{.targetPort}} masquerade
+		{{- end}}
+	}
+}
+`))
+
 var nftablesNetACLSetup = template.Must(template.New("nftablesNetACLSetup").Parse(`
 add table {{.family}} {{.namespace}}
 add chain {{.family}} {{.namespace}} acl{{.chainSeparator}}{{.networkName}}
@@ -186,6 +219,19 @@ table {{.family}} {{.namespace}} {
 }
 `))
 
+// nftablesNetAddressSets defines the named sets matched by ACL rules referencing address sets.
+// Each set is flushed and refilled in the same transaction so rules never see a partially updated set.
+var nftablesNetAddressSets = template.Must(template.New("nftablesNetAddressSets").Parse(`
+add table {{.family}} {{.namespace}}
+{{- range .sets}}
+add set {{$.family}} {{$.namespace}} {{.name}} { type {{.type}}; flags interval; auto-merge; }
+flush set {{$.family}} {{$.namespace}} {{.name}}
+{{- if .addresses}}
+add element {{$.family}} {{$.namespace}} {{.name}} { {{.addresses}} }
+{{- end}}
+{{- end}}
+`))
+
 // nftablesInstanceBridgeFilter defines the rules needed for MAC, IPv4 and IPv6 bridge security filtering.
 // To prevent instances from using IPs that are different from their assigned IPs we use ARP and NDP filtering
 // to prevent neighbour advertisements that are not allowed. However in order for DHCPv4 & DHCPv6 to work back to
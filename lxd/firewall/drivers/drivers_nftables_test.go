@@ -0,0 +1,110 @@
+package drivers
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_nftablesLoadBalancersConfig(t *testing.T) {
+	loadBalancers := []LoadBalancer{
+		{
+			ListenAddress: net.ParseIP("192.0.2.1"),
+			Protocol:      "tcp",
+			ListenPort:    80,
+			Targets: []LoadBalancerTarget{
+				{Address: net.ParseIP("10.0.0.10"), Port: 8080},
+				{Address: net.ParseIP("10.0.0.11"), Port: 8080},
+			},
+		},
+		{
+			ListenAddress: net.ParseIP("192.0.2.1"),
+			Protocol:      "tcp",
+			ListenPort:    81,
+			Targets: []LoadBalancerTarget{
+				{Address: net.ParseIP("10.0.0.10"), Port: 8080},
+			},
+		},
+		{
+			ListenAddress: net.ParseIP("2001:db8::1"),
+			Protocol:      "udp",
+			ListenPort:    53,
+			Targets: []LoadBalancerTarget{
+				{Address: net.ParseIP("fd42::10"), Port: 53},
+			},
+		},
+	}
+
+	config, err := nftablesLoadBalancersConfig("lxdbr0", loadBalancers)
+	assert.NoError(t, err)
+
+	// Connections are spread across the targets of each listen port.
+	assert.Contains(t, config, "ip daddr 192.0.2.1 tcp dport 80 dnat ip to numgen inc mod 2 map { 0 : 10.0.0.10 . 8080, 1 : 10.0.0.11 . 8080 }")
+	assert.Contains(t, config, "ip daddr 192.0.2.1 tcp dport 81 dnat ip to numgen inc mod 1 map { 0 : 10.0.0.10 . 8080 }")
+	assert.Contains(t, config, "ip6 daddr 2001:db8::1 udp dport 53 dnat ip6 to numgen inc mod 1 map { 0 : fd42::10 . 53 }")
+
+	// Hairpin connections are masqueraded once per target.
+	assert.Contains(t, config, "ip saddr 10.0.0.10 ip daddr 10.0.0.10 tcp dport 8080 masquerade")
+	assert.Contains(t, config, "ip saddr 10.0.0.11 ip daddr 10.0.0.11 tcp dport 8080 masquerade")
+	assert.Contains(t, config, "ip6 saddr fd42::10 ip6 daddr fd42::10 udp dport 53 masquerade")
+	assert.Equal(t, 3, strings.Count(config, "masquerade"))
+
+	// The chains of the network are recreated.
+	assert.Contains(t, config, "flush chain inet lxd lbprert.lxdbr0")
+}
+
+func Test_nftablesLoadBalancersConfigInvalid(t *testing.T) {
+	tests := []struct {
+		name         string
+		loadBalancer LoadBalancer
+		wantErr      string
+	}{
+		{
+			name:         "Missing listen address",
+			loadBalancer: LoadBalancer{Protocol: "tcp", ListenPort: 80, Targets: []LoadBalancerTarget{{Address: net.ParseIP("10.0.0.10"), Port: 80}}},
+			wantErr:      "listen address is required",
+		},
+		{
+			name:         "Missing listen port",
+			loadBalancer: LoadBalancer{ListenAddress: net.ParseIP("192.0.2.1"), Protocol: "tcp", Targets: []LoadBalancerTarget{{Address: net.ParseIP("10.0.0.10"), Port: 80}}},
+			wantErr:      "protocol and listen port are required",
+		},
+		{
+			name:         "Missing targets",
+			loadBalancer: LoadBalancer{ListenAddress: net.ParseIP("192.0.2.1"), Protocol: "tcp", ListenPort: 80},
+			wantErr:      "at least one target is required",
+		},
+		{
+			name:         "Missing target port",
+			loadBalancer: LoadBalancer{ListenAddress: net.ParseIP("192.0.2.1"), Protocol: "tcp", ListenPort: 80, Targets: []LoadBalancerTarget{{Address: net.ParseIP("10.0.0.10")}}},
+			wantErr:      "target address and port are required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := nftablesLoadBalancersConfig("lxdbr0", []LoadBalancer{tt.loadBalancer})
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func Test_nftablesACLRuleCounters(t *testing.T) {
+	out := `{"nftables": [{"metainfo": {"version": "1.0.9", "release_name": "Old Doc Yak #3", "json_schema_version": 1}}, {"chain": {"family": "inet", "table": "lxd", "name": "acl.lxdbr0", "handle": 5}}, {"rule": {"family": "inet", "table": "lxd", "chain": "acl.lxdbr0", "handle": 10, "comment": "lxd_acl12-ingress-0", "expr": [{"match": {"op": "==", "left": {"payload": {"protocol": "ip", "field": "saddr"}}, "right": "192.0.2.1"}}, {"counter": {"packets": 2, "bytes": 168}}, {"accept": null}]}}, {"rule": {"family": "inet", "table": "lxd", "chain": "acl.lxdbr0", "handle": 11, "comment": "lxd_acl12-ingress-0", "expr": [{"match": {"op": "==", "left": {"payload": {"protocol": "ip6", "field": "saddr"}}, "right": "2001:db8::1"}}, {"counter": {"packets": 3, "bytes": 312}}, {"accept": null}]}}, {"rule": {"family": "inet", "table": "lxd", "chain": "acl.lxdbr0", "handle": 12, "comment": "lxd_acl12-egress-1", "expr": [{"counter": {"packets": 0, "bytes": 0}}, {"drop": null}]}}, {"rule": {"family": "inet", "table": "lxd", "chain": "acl.lxdbr0", "handle": 13, "expr": [{"counter": {"packets": 7, "bytes": 700}}, {"reject": null}]}}]}`
+
+	counters, err := nftablesACLRuleCounters(out)
+	require.NoError(t, err)
+
+	want := map[string]ACLRuleCounters{
+		"lxd_acl12-ingress-0": {Packets: 5, Bytes: 480},
+		"lxd_acl12-egress-1":  {},
+	}
+
+	assert.Equal(t, want, counters)
+
+	_, err = nftablesACLRuleCounters("not json")
+	assert.Error(t, err)
+}
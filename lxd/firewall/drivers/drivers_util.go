@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"net"
 	"strconv"
+	"strings"
+
+	"github.com/canonical/lxd/shared"
 )
 
 // portRangesFromSlice checks if adjacent indices in the given slice contain consecutive
@@ -147,3 +150,106 @@ func subnetPrefixHex(ipNet *net.IPNet) (string, error) {
 
 	return hexStr[:ones/4], nil
 }
+
+// addressSetFamilyName returns the name of the firewall set holding the addresses of the given IP version.
+func addressSetFamilyName(name string, ipVersion uint) string {
+	return name + "_ipv" + strconv.FormatUint(uint64(ipVersion), 10)
+}
+
+// addressSetFamilyAddresses returns the addresses of the set that belong to the given IP version.
+func addressSetFamilyAddresses(set AddressSet, ipVersion uint) []string {
+	addresses := make([]string, 0, len(set.Addresses))
+	for _, address := range set.Addresses {
+		if subjectIPVersion(address) == ipVersion {
+			addresses = append(addresses, address)
+		}
+	}
+
+	return addresses
+}
+
+// subjectIPVersion returns the IP version of an IP address, CIDR subnet, IP range or IP family specific
+// address set reference. Returns 0 if the version cannot be determined.
+func subjectIPVersion(subject string) uint {
+	if strings.HasPrefix(subject, "$") {
+		switch {
+		case strings.HasSuffix(subject, "_ipv4"):
+			return 4
+		case strings.HasSuffix(subject, "_ipv6"):
+			return 6
+		}
+
+		return 0
+	}
+
+	ip := net.ParseIP(strings.SplitN(subject, "-", 2)[0])
+	if ip == nil {
+		ip, _, _ = net.ParseCIDR(subject)
+	}
+
+	if ip == nil {
+		return 0
+	}
+
+	if ip.To4() == nil {
+		return 6
+	}
+
+	return 4
+}
+
+// aclRuleExpandAddressSets splits a rule that references address sets ("$<name>" subjects) into rules whose
+// source and destination contain either subjects of a single IP family or a single IP family specific address
+// set reference ("$<name>_ipv4" or "$<name>_ipv6"). This is needed as a named set cannot be combined with other
+// subjects in a single match. Rules without address set references are returned unchanged.
+func aclRuleExpandAddressSets(rule ACLRule) []ACLRule {
+	if !strings.Contains(rule.Source, "$") && !strings.Contains(rule.Destination, "$") {
+		return []ACLRule{rule}
+	}
+
+	// subjectGroups returns the groups of subjects of the field to match for the IP version.
+	subjectGroups := func(field string, ipVersion uint) []string {
+		if field == "" {
+			return []string{""}
+		}
+
+		var literals []string
+		var groups []string
+
+		for _, subject := range shared.SplitNTrimSpace(field, ",", -1, false) {
+			if strings.HasPrefix(subject, "$") {
+				groups = append(groups, addressSetFamilyName(subject, ipVersion))
+			} else if subjectIPVersion(subject) == ipVersion {
+				literals = append(literals, subject)
+			}
+		}
+
+		if len(literals) > 0 {
+			groups = append(groups, strings.Join(literals, ","))
+		}
+
+		return groups
+	}
+
+	ipVersions := []uint{4, 6}
+	switch rule.Protocol {
+	case "icmp4":
+		ipVersions = []uint{4}
+	case "icmp6":
+		ipVersions = []uint{6}
+	}
+
+	var rules []ACLRule
+	for _, ipVersion := range ipVersions {
+		for _, source := range subjectGroups(rule.Source, ipVersion) {
+			for _, destination := range subjectGroups(rule.Destination, ipVersion) {
+				expandedRule := rule
+				expandedRule.Source = source
+				expandedRule.Destination = destination
+				rules = append(rules, expandedRule)
+			}
+		}
+	}
+
+	return rules
+}
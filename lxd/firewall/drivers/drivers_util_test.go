@@ -145,3 +145,46 @@ func Test_getOptimisedSNATRanges(t *testing.T) {
 		assert.Equal(t, tt.expected, actual)
 	}
 }
+
+func Test_aclRuleExpandAddressSets(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     ACLRule
+		expected []ACLRule
+	}{
+		{
+			name:     "No address set",
+			rule:     ACLRule{Source: "192.0.2.0/24", Destination: "2001:db8::1"},
+			expected: []ACLRule{{Source: "192.0.2.0/24", Destination: "2001:db8::1"}},
+		},
+		{
+			name: "Address set only",
+			rule: ACLRule{Source: "$trusted"},
+			expected: []ACLRule{
+				{Source: "$trusted_ipv4"},
+				{Source: "$trusted_ipv6"},
+			},
+		},
+		{
+			name: "Address set mixed with addresses",
+			rule: ACLRule{Source: "$trusted,192.0.2.1,198.51.100.0/24", Destination: "$servers"},
+			expected: []ACLRule{
+				{Source: "$trusted_ipv4", Destination: "$servers_ipv4"},
+				{Source: "192.0.2.1,198.51.100.0/24", Destination: "$servers_ipv4"},
+				{Source: "$trusted_ipv6", Destination: "$servers_ipv6"},
+			},
+		},
+		{
+			name: "ICMPv6 rule",
+			rule: ACLRule{Protocol: "icmp6", Destination: "$trusted"},
+			expected: []ACLRule{
+				{Protocol: "icmp6", Destination: "$trusted_ipv6"},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		log.Printf("Running test #%d: %s", i, tt.name)
+		assert.Equal(t, tt.expected, aclRuleExpandAddressSets(tt.rule))
+	}
+}
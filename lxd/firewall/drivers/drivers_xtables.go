@@ -160,6 +160,11 @@ func (d Xtables) networkForwardIPTablesComment(networkName string) string {
 	return "LXD network-forward " + networkName
 }
 
+// networkLoadBalancerIPTablesComment returns the iptables comment that is added to network load balancer rules.
+func (d Xtables) networkLoadBalancerIPTablesComment(networkName string) string {
+	return "LXD network-load-balancer " + networkName
+}
+
 // networkSetupNICFilteringChain creates the NIC filtering chain if it doesn't exist, and adds the jump rules to
 // the INPUT and FORWARD filter chains. Must be called after networkSetupForwardingPolicy so that the rules are
 // prepended before the default fowarding policy rules.
@@ -336,7 +341,8 @@ func (d Xtables) networkSetupForwardingPolicy(networkName string, ipVersion uint
 
 // networkSetupOutboundNAT configures outbound NAT.
 // If srcIP is non-nil then SNAT is used with the specified address, otherwise MASQUERADE mode is used.
-func (d Xtables) networkSetupOutboundNAT(networkName string, subnet *net.IPNet, srcIP net.IP, appendRule bool) error {
+// Traffic leaving through the excluded interfaces isn't translated.
+func (d Xtables) networkSetupOutboundNAT(networkName string, subnet *net.IPNet, srcIP net.IP, appendRule bool, excludeInterfaces []string) error {
 	family := uint(4)
 	if subnet.IP.To4() == nil {
 		family = 6
@@ -362,6 +368,14 @@ func (d Xtables) networkSetupOutboundNAT(networkName string, subnet *net.IPNet,
 	comment := d.networkIPTablesComment(networkName)
 
 	if appendRule {
+		// The exclusions must come before the translation rule.
+		for _, iface := range excludeInterfaces {
+			err := d.iptablesAppend(family, comment, "nat", "POSTROUTING", "-s", subnet.String(), "-o", iface, "-j", "RETURN")
+			if err != nil {
+				return err
+			}
+		}
+
 		err := d.iptablesAppend(family, comment, "nat", "POSTROUTING", args...)
 		if err != nil {
 			return err
@@ -371,6 +385,14 @@ func (d Xtables) networkSetupOutboundNAT(networkName string, subnet *net.IPNet,
 		if err != nil {
 			return err
 		}
+
+		// The exclusions are prepended after the translation rule so they come before it.
+		for _, iface := range excludeInterfaces {
+			err := d.iptablesPrepend(family, comment, "nat", "POSTROUTING", "-s", subnet.String(), "-o", iface, "-j", "RETURN")
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
@@ -454,14 +476,14 @@ func (d Xtables) networkSetupDHCPv4Checksum(networkName string) error {
 // NetworkSetup configure network firewall.
 func (d Xtables) NetworkSetup(networkName string, ipv4Address net.IP, ipv6Address net.IP, opts Opts) error {
 	if opts.SNATV4 != nil {
-		err := d.networkSetupOutboundNAT(networkName, opts.SNATV4.Subnet, opts.SNATV4.SNATAddress, opts.SNATV4.Append)
+		err := d.networkSetupOutboundNAT(networkName, opts.SNATV4.Subnet, opts.SNATV4.SNATAddress, opts.SNATV4.Append, opts.SNATV4.ExcludeInterfaces)
 		if err != nil {
 			return err
 		}
 	}
 
 	if opts.SNATV6 != nil {
-		err := d.networkSetupOutboundNAT(networkName, opts.SNATV6.Subnet, opts.SNATV6.SNATAddress, opts.SNATV6.Append)
+		err := d.networkSetupOutboundNAT(networkName, opts.SNATV6.Subnet, opts.SNATV6.SNATAddress, opts.SNATV6.Append, opts.SNATV6.ExcludeInterfaces)
 		if err != nil {
 			return err
 		}
@@ -525,6 +547,12 @@ func (d Xtables) NetworkSetup(networkName string, ipv4Address net.IP, ipv6Addres
 func (d Xtables) NetworkApplyACLRules(networkName string, rules []ACLRule) error {
 	chain := iptablesChainACLFilterPrefix + "_" + networkName
 
+	// Split the rules referencing address sets so each ipset is matched on its own.
+	expandedRules := make([]ACLRule, 0, len(rules))
+	for _, rule := range rules {
+		expandedRules = append(expandedRules, aclRuleExpandAddressSets(rule)...)
+	}
+
 	// Parse rules for both IP families before applying either family of rules.
 	iptCmdRules := make(map[string][][]string)
 	for _, ipVersion := range []uint{4, 6} {
@@ -534,7 +562,7 @@ func (d Xtables) NetworkApplyACLRules(networkName string, rules []ACLRule) error
 		}
 
 		iptRules := make([][]string, 0)
-		for _, rule := range rules {
+		for _, rule := range expandedRules {
 			actionArgs, logArgs, err := d.aclRuleCriteriaToArgs(networkName, ipVersion, &rule)
 			if err != nil {
 				return err
@@ -589,6 +617,61 @@ func (d Xtables) NetworkApplyACLRules(networkName string, rules []ACLRule) error
 	return nil
 }
 
+// NetworkACLRuleCounters returns the counters of the ACL rules applied to the network, indexed by rule comment.
+// The counters of the IPv4 and IPv6 rules generated from the same ACL rule are added together.
+func (d Xtables) NetworkACLRuleCounters(networkName string) (map[string]ACLRuleCounters, error) {
+	chain := iptablesChainACLFilterPrefix + "_" + networkName
+	counters := map[string]ACLRuleCounters{}
+
+	for _, cmd := range []string{"iptables", "ip6tables"} {
+		out, err := shared.RunCommandContext(context.TODO(), cmd, "-w", "-t", "filter", "-L", chain, "-n", "-v", "-x")
+		if err != nil {
+			return nil, fmt.Errorf("Failed listing %q chain %q in table %q: %w", cmd, chain, "filter", err)
+		}
+
+		xtablesACLRuleCounters(out, counters)
+	}
+
+	return counters, nil
+}
+
+// xtablesACLRuleCounters parses the output of "iptables -L -n -v -x" and adds the counters of the rules to
+// counters, indexed by rule comment.
+func xtablesACLRuleCounters(out string, counters map[string]ACLRuleCounters) {
+	for line := range strings.SplitSeq(out, "\n") {
+		// The comment is shown at the end of the rule as "/* comment */".
+		_, comment, found := strings.Cut(line, "/* ")
+		if !found {
+			continue
+		}
+
+		comment, _, found = strings.Cut(comment, " */")
+		if !found {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		packets, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		bytes, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		ruleCounters := counters[comment]
+		ruleCounters.Packets += packets
+		ruleCounters.Bytes += bytes
+		counters[comment] = ruleCounters
+	}
+}
+
 // aclRuleCriteriaToArgs converts an ACL rule into an set of arguments for an xtables rule.
 // Returns the arguments to use for the action command and separately the arguments for logging if enabled.
 // Returns nil arguments if the rule is not appropriate for the ipVersion.
@@ -692,7 +775,14 @@ func (d Xtables) aclRuleCriteriaToArgs(networkName string, ipVersion uint, rule
 		action = "accept"
 	}
 
-	actionArgs = append(args, "-j", strings.ToUpper(action))
+	actionArgs = slices.Clone(args)
+
+	// Identify the rule so its counters can be retrieved.
+	if rule.Comment != "" {
+		actionArgs = append(actionArgs, "-m", "comment", "--comment", rule.Comment)
+	}
+
+	actionArgs = append(actionArgs, "-j", strings.ToUpper(action))
 
 	// Handle logging.
 	if rule.Log {
@@ -714,6 +804,24 @@ func (d Xtables) aclRuleSubjectToACLMatch(direction string, ipVersion uint, subj
 
 	// For each criterion check if value looks like IP CIDR.
 	for _, subjectCriterion := range subjectCriteria {
+		if strings.HasPrefix(subjectCriterion, "$") {
+			// Subject is an IP family specific address set reference.
+			if ipVersion != subjectIPVersion(subjectCriterion) {
+				continue // Skip subjects that not for the xtables tool we are using.
+			}
+
+			if len(subjectCriteria) > 1 {
+				return nil, fmt.Errorf("Address set %q cannot be combined with other subjects", subjectCriterion)
+			}
+
+			flag := "src"
+			if direction == "destination" {
+				flag = "dst"
+			}
+
+			return []string{"-m", "set", "--match-set", strings.TrimPrefix(subjectCriterion, "$"), flag}, nil
+		}
+
 		ip := net.ParseIP(subjectCriterion)
 		if ip == nil {
 			ip, _, _ = net.ParseCIDR(subjectCriterion)
@@ -742,6 +850,63 @@ func (d Xtables) aclRuleSubjectToACLMatch(direction string, ipVersion uint, subj
 	return nil, nil // No subjects suitable for ipVersion.
 }
 
+// NetworkApplyAddressSets creates the ipsets used by ACL rules and atomically replaces their contents.
+// The new contents are loaded into a temporary ipset which is then swapped with the one referenced by the rules.
+func (d Xtables) NetworkApplyAddressSets(sets []AddressSet) error {
+	var input strings.Builder
+
+	for _, set := range sets {
+		for _, ipVersion := range []uint{4, 6} {
+			family := "inet"
+			if ipVersion == 6 {
+				family = "inet6"
+			}
+
+			setName := addressSetFamilyName(set.Name, ipVersion)
+			tmpName := setName + ".tmp"
+
+			fmt.Fprintf(&input, "create %s hash:net family %s -exist\n", setName, family)
+			fmt.Fprintf(&input, "create %s hash:net family %s -exist\n", tmpName, family)
+			fmt.Fprintf(&input, "flush %s\n", tmpName)
+
+			for _, address := range addressSetFamilyAddresses(set, ipVersion) {
+				fmt.Fprintf(&input, "add %s %s -exist\n", tmpName, address)
+			}
+
+			fmt.Fprintf(&input, "swap %s %s\n", tmpName, setName)
+			fmt.Fprintf(&input, "destroy %s\n", tmpName)
+		}
+	}
+
+	err := shared.RunCommandWithFds(context.TODO(), strings.NewReader(input.String()), nil, "ipset", "restore")
+	if err != nil {
+		return fmt.Errorf("Failed applying address sets: %w", err)
+	}
+
+	return nil
+}
+
+// NetworkDeleteAddressSets removes the ipsets of the address sets if they exist.
+func (d Xtables) NetworkDeleteAddressSets(names []string) error {
+	for _, name := range names {
+		for _, ipVersion := range []uint{4, 6} {
+			setName := addressSetFamilyName(name, ipVersion)
+
+			_, err := shared.RunCommandContext(context.TODO(), "ipset", "list", "-name", setName)
+			if err != nil {
+				continue // Set doesn't exist.
+			}
+
+			_, err = shared.RunCommandContext(context.TODO(), "ipset", "destroy", setName)
+			if err != nil {
+				return fmt.Errorf("Failed deleting ipset %q: %w", setName, err)
+			}
+		}
+	}
+
+	return nil
+}
+
 // aclRulePortToACLMatch converts protocol (tcp/udp), direction (sports/dports) and port criteria list into
 // xtables args.
 func (d Xtables) aclRulePortToACLMatch(direction string, portCriteria ...string) []string {
@@ -765,10 +930,11 @@ func (d Xtables) NetworkClear(networkName string, remove bool, ipVersions []uint
 	comments := []string{
 		d.networkIPTablesComment(networkName),
 		d.networkForwardIPTablesComment(networkName),
+		d.networkLoadBalancerIPTablesComment(networkName),
 	}
 
 	for _, ipVersion := range ipVersions {
-		// Clear any rules associated to the network and network address forwards.
+		// Clear any rules associated to the network, network address forwards and load balancers.
 		err := d.iptablesClear(ipVersion, comments, "filter", "mangle", "nat")
 		if err != nil {
 			return err
@@ -1665,3 +1831,118 @@ func (d Xtables) NetworkApplyForwards(networkName string, rules []AddressForward
 	reverter.Success()
 	return nil
 }
+
+// NetworkApplyLoadBalancers apply network load balancers. Connections to each listen port are spread across its
+// targets in turn using the statistic match in nth mode.
+func (d Xtables) NetworkApplyLoadBalancers(networkName string, loadBalancers []LoadBalancer) error {
+	// Validate all load balancers first.
+	for i, lb := range loadBalancers {
+		if lb.ListenAddress == nil {
+			return fmt.Errorf("Invalid load balancer %d, listen address is required", i)
+		}
+
+		if lb.Protocol == "" || lb.ListenPort == 0 {
+			return fmt.Errorf("Invalid load balancer %d, protocol and listen port are required", i)
+		}
+
+		if len(lb.Targets) == 0 {
+			return fmt.Errorf("Invalid load balancer %d, at least one target is required", i)
+		}
+
+		for _, target := range lb.Targets {
+			if target.Address == nil || target.Port == 0 {
+				return fmt.Errorf("Invalid load balancer %d, target address and port are required", i)
+			}
+		}
+	}
+
+	comment := d.networkLoadBalancerIPTablesComment(networkName)
+
+	clearNetworkLoadBalancers := func() error {
+		for _, ipVersion := range []uint{4, 6} {
+			err := d.iptablesClear(ipVersion, []string{comment}, "nat")
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	// Clear any load balancer rules associated to the network.
+	err := clearNetworkLoadBalancers()
+	if err != nil {
+		return err
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// Clear all network load balancers if we fail, otherwise the load balancers are only partially applied.
+	reverter.Add(func() {
+		err := clearNetworkLoadBalancers()
+		if err != nil {
+			logger.Error("Failed to clear firewall rules after failing to apply network load balancers", logger.Ctx{"network_name": networkName, "err": err})
+		}
+	})
+
+	// Used to only add one masquerade rule per target.
+	snatTargets := make(map[string]struct{})
+
+	for _, lb := range loadBalancers {
+		ipVersion := uint(4)
+		if lb.ListenAddress.To4() == nil {
+			ipVersion = 6
+		}
+
+		listenAddressStr := lb.ListenAddress.String()
+		listenPortStr := strconv.FormatUint(lb.ListenPort, 10)
+		targetsLen := len(lb.Targets)
+
+		// Rules are prepended, so add them from the last target to the first. Each rule matches every nth new
+		// connection that wasn't matched by the rules of the previous targets, the last target gets the rest.
+		for i := targetsLen - 1; i >= 0; i-- {
+			target := lb.Targets[i]
+			targetAddressStr := target.Address.String()
+			targetPortStr := strconv.FormatUint(target.Port, 10)
+
+			targetDest := targetAddressStr + ":" + targetPortStr
+			if ipVersion == 6 {
+				targetDest = "[" + targetAddressStr + "]:" + targetPortStr
+			}
+
+			var statisticArgs []string
+			if i < targetsLen-1 {
+				statisticArgs = []string{"-m", "statistic", "--mode", "nth", "--every", strconv.Itoa(targetsLen - i), "--packet", "0"}
+			}
+
+			for _, chain := range []string{"PREROUTING", "OUTPUT"} {
+				args := []string{"-p", lb.Protocol, "--destination", listenAddressStr, "--dport", listenPortStr}
+				args = append(args, statisticArgs...)
+				args = append(args, "-j", "DNAT", "--to-destination", targetDest)
+
+				err := d.iptablesPrepend(ipVersion, comment, "nat", chain, args...)
+				if err != nil {
+					return err
+				}
+			}
+
+			// instance <-> instance.
+			// Requires instance's bridge port has hairpin mode enabled when br_netfilter is loaded.
+			snatKey := lb.Protocol + "/" + targetAddressStr + "/" + targetPortStr
+			_, found := snatTargets[snatKey]
+			if found {
+				continue
+			}
+
+			snatTargets[snatKey] = struct{}{}
+			err := d.iptablesPrepend(ipVersion, comment, "nat", "POSTROUTING", "-p", lb.Protocol, "--source", targetAddressStr, "--destination", targetAddressStr, "--dport", targetPortStr, "-j", "MASQUERADE")
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	reverter.Success()
+	return nil
+}
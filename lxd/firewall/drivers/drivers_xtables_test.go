@@ -0,0 +1,33 @@
+package drivers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_xtablesACLRuleCounters(t *testing.T) {
+	ipv4Out := `Chain lxd_acl_lxdbr0 (2 references)
+    pkts      bytes target     prot opt in     out     source               destination
+       2      168 ACCEPT     0    --  *      *       192.0.2.1            0.0.0.0/0            /* lxd_acl12-ingress-0 */
+       1       84 LOG        0    --  *      *       0.0.0.0/0            0.0.0.0/0            LOG flags 0 level 4 prefix "lxd_acl12-egress-1 "
+       1       84 DROP       0    --  *      *       0.0.0.0/0            0.0.0.0/0            /* lxd_acl12-egress-1 */
+       7      700 REJECT     0    --  *      *       0.0.0.0/0            0.0.0.0/0            reject-with icmp-port-unreachable
+`
+
+	ipv6Out := `Chain lxd_acl_lxdbr0 (2 references)
+    pkts      bytes target     prot opt in     out     source               destination
+       3      312 ACCEPT     0    --  *      *       2001:db8::1          ::/0                 /* lxd_acl12-ingress-0 */
+`
+
+	counters := map[string]ACLRuleCounters{}
+	xtablesACLRuleCounters(ipv4Out, counters)
+	xtablesACLRuleCounters(ipv6Out, counters)
+
+	want := map[string]ACLRuleCounters{
+		"lxd_acl12-ingress-0": {Packets: 5, Bytes: 480},
+		"lxd_acl12-egress-1":  {Packets: 1, Bytes: 84},
+	}
+
+	assert.Equal(t, want, counters)
+}
@@ -14,7 +14,11 @@ type Firewall interface {
 	NetworkSetup(networkName string, ip4Address net.IP, ip6Address net.IP, opts drivers.Opts) error
 	NetworkClear(networkName string, remove bool, ipVersions []uint) error
 	NetworkApplyACLRules(networkName string, rules []drivers.ACLRule) error
+	NetworkACLRuleCounters(networkName string) (map[string]drivers.ACLRuleCounters, error)
+	NetworkApplyAddressSets(sets []drivers.AddressSet) error
+	NetworkDeleteAddressSets(names []string) error
 	NetworkApplyForwards(networkName string, rules []drivers.AddressForward) error
+	NetworkApplyLoadBalancers(networkName string, loadBalancers []drivers.LoadBalancer) error
 
 	InstanceSetupBridgeFilter(projectName string, instanceName string, deviceName string, parentName string, hostName string, hwAddr string, IPv4Nets []*net.IPNet, IPv6Nets []*net.IPNet, parentManaged bool) error
 	InstanceClearBridgeFilter(projectName string, instanceName string, deviceName string, parentName string, hostName string, hwAddr string, IPv4Nets []*net.IPNet, IPv6Nets []*net.IPNet) error
@@ -31,6 +31,7 @@ import (
 	"github.com/canonical/lxd/lxd/lifecycle"
 	"github.com/canonical/lxd/lxd/locking"
 	"github.com/canonical/lxd/lxd/maas"
+	"github.com/canonical/lxd/lxd/network"
 	"github.com/canonical/lxd/lxd/operations"
 	"github.com/canonical/lxd/lxd/project"
 	"github.com/canonical/lxd/lxd/state"
@@ -402,6 +403,11 @@ func (d *common) ExecOutputPath() string {
 	return filepath.Join(d.Path(), "exec-output")
 }
 
+// ConsoleRecordingPath returns the instance's console recording path.
+func (d *common) ConsoleRecordingPath() string {
+	return filepath.Join(d.LogPath(), "console-recordings")
+}
+
 // RootfsPath returns the instance's rootfs path.
 func (d *common) RootfsPath() string {
 	return filepath.Join(d.Path(), "rootfs")
@@ -1593,6 +1599,10 @@ func (d *common) validateStartup(statusCode api.StatusCode) error {
 		return api.StatusErrorf(http.StatusServiceUnavailable, "Storage pool %q unavailable on this server", rootDiskConf["pool"])
 	}
 
+	if d.localConfig["volatile.replica.source"] != "" {
+		return api.StatusErrorf(http.StatusBadRequest, "Instance is a replica of %q and must be promoted before starting", d.localConfig["volatile.replica.source"])
+	}
+
 	// Must happen before creating operation Start lock to avoid the status check returning Stopped due to the
 	// existence of a Start operation lock.
 	err = d.isStartableStatusCode(statusCode)
@@ -1755,6 +1765,40 @@ func (d *common) recordLastState() error {
 	})
 }
 
+// notifyServiceRecords tells the DNS server about the change of the service records the instance publishes, if
+// any of the changed config keys is a `user.dns.srv.*` key. This is done for each forward zone of the managed
+// networks the instance's NICs are connected to, so that the zones don't wait for their next refresh.
+func (d *common) notifyServiceRecords(changedKeys []string) {
+	if d.state.DNS == nil || !slices.ContainsFunc(changedKeys, func(key string) bool { return strings.HasPrefix(key, "user.dns.srv.") }) {
+		return
+	}
+
+	networkProjectName := project.NetworkProjectFromRecord(&d.project)
+
+	zoneNames := []string{}
+	for _, dev := range d.expandedDevices.Sorted() {
+		if dev.Config["type"] != "nic" || dev.Config["network"] == "" {
+			continue
+		}
+
+		n, err := network.LoadByName(d.state, networkProjectName, dev.Config["network"])
+		if err != nil {
+			d.logger.Warn("Failed loading network to publish service records", logger.Ctx{"network": dev.Config["network"], "err": err})
+			continue
+		}
+
+		for _, zoneName := range shared.SplitNTrimSpace(n.Config()["dns.zone.forward"], ",", -1, true) {
+			if !slices.Contains(zoneNames, zoneName) {
+				zoneNames = append(zoneNames, zoneName)
+			}
+		}
+	}
+
+	for _, zoneName := range zoneNames {
+		d.state.DNS.Notify(zoneName)
+	}
+}
+
 func (d *common) setCoreSched(pids []int) error {
 	if !d.state.OS.CoreScheduling {
 		return nil
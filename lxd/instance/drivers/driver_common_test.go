@@ -0,0 +1,64 @@
+package drivers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	deviceConfig "github.com/canonical/lxd/lxd/device/config"
+	"github.com/canonical/lxd/shared/api"
+)
+
+func Test_validateStartup(t *testing.T) {
+	devices := deviceConfig.Devices{"root": {"type": "disk", "path": "/", "pool": "default"}}
+
+	tests := []struct {
+		name       string
+		config     map[string]string
+		statusCode api.StatusCode
+		wantErr    bool
+		wantStatus int
+	}{
+		{
+			name:       "Stopped",
+			config:     map[string]string{},
+			statusCode: api.Stopped,
+		},
+		{
+			name:       "Running",
+			config:     map[string]string{},
+			statusCode: api.Running,
+			wantErr:    true,
+		},
+		{
+			name:       "Replica",
+			config:     map[string]string{"volatile.replica.source": "lxd01/default/c1"},
+			statusCode: api.Stopped,
+			wantErr:    true,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Promoted replica",
+			config:     map[string]string{"volatile.replica.source": ""},
+			statusCode: api.Stopped,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &common{expandedDevices: devices, localConfig: tt.config}
+
+			err := d.validateStartup(tt.statusCode)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.Error(t, err)
+			if tt.wantStatus != 0 {
+				assert.True(t, api.StatusErrorCheck(err, tt.wantStatus))
+			}
+		})
+	}
+}
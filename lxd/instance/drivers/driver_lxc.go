@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"math"
 	"net"
 	"net/http"
@@ -2496,6 +2497,9 @@ func (d *lxc) onStart(_ map[string]string) error {
 		return err
 	}
 
+	// Publish the service records of the instance now that it runs.
+	d.notifyServiceRecords(slices.Collect(maps.Keys(d.expandedConfig)))
+
 	// Trigger a scheduler rebalance after DB changes made.
 	cgroup.TaskSchedulerTrigger(d.dbType, d.name, "started")
 
@@ -2923,6 +2927,9 @@ func (d *lxc) onStop(args map[string]string) error {
 		d.logger.Error("Failed recording last power state", logger.Ctx{"err": err})
 	}
 
+	// Withdraw the service records of the instance.
+	d.notifyServiceRecords(slices.Collect(maps.Keys(d.expandedConfig)))
+
 	go func(d *lxc, target string, op *operationlock.InstanceOperation) {
 		d.fromHook = false
 		err = nil
@@ -4541,6 +4548,11 @@ func (d *lxc) Update(args db.InstanceArgs, userRequested bool) error {
 		return fmt.Errorf("Failed to write backup file: %w", err)
 	}
 
+	// Publish the changed service records.
+	if isRunning {
+		d.notifyServiceRecords(changedConfig)
+	}
+
 	// Send devlxd notifications
 	if isRunning {
 		// Config changes (only for user.* keys
@@ -14,6 +14,7 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"maps"
 	"net"
 	"net/http"
 	"net/url"
@@ -92,6 +93,13 @@ const QEMUDefaultCPUCores = 1
 // QEMUDefaultMemSize is the default memory size for VMs if no limit specified.
 const QEMUDefaultMemSize = "1GiB"
 
+// qemuMemoryHotplugSlots is the number of memory slots reserved for memory hotplug.
+const qemuMemoryHotplugSlots = 16
+
+// qemuMemoryHotplugAlignMB is the alignment (in MiB) of hotplugged memory devices.
+// This matches the Linux memory block size on x86_64 so hotplugged memory can be fully onlined by the guest.
+const qemuMemoryHotplugAlignMB = 128
+
 // qemuSerialChardevName is used to communicate state via qmp between Qemu and LXD.
 const qemuSerialChardevName = "qemu_serial-chardev"
 
@@ -110,6 +118,25 @@ const qemuDeviceNamePrefix = "lxd_"
 // qemuDeviceNameMaxLength used to indicate the maximum length of a qemu block node name and device tags.
 const qemuDeviceNameMaxLength = 31
 
+// qemuDeviceMirrorSuffix is the suffix of the alternate block node name used when mirroring a disk device.
+const qemuDeviceMirrorSuffix = "-mirror"
+
+// qemuChangedBlocksBitmapName is the name of the dirty bitmap tracking the changed blocks of the root disk.
+const qemuChangedBlocksBitmapName = "lxd-changed-blocks"
+
+// qemuChangedBlocksNodeName is the name of the block node the changed blocks of the root disk are backed up to.
+const qemuChangedBlocksNodeName = "lxd_changed_blocks"
+
+// qemuChangedBlocksBackupTimeout is the maximum time a changed blocks backup may take before it is cancelled.
+const qemuChangedBlocksBackupTimeout = time.Hour
+
+// qemuChangedBlocksStoreNodeName is the name of the block node the changed blocks bitmap is stored in while the VM
+// is stopped.
+const qemuChangedBlocksStoreNodeName = "lxd_changed_blocks_store"
+
+// qemuChangedBlocksGranularity is the number of bytes covered by each bit of the changed blocks bitmap.
+const qemuChangedBlocksGranularity = 64 * 1024
+
 // qemuMigrationNBDExportName is the name of the disk device export by the migration NBD server.
 const qemuMigrationNBDExportName = "lxd_root"
 
@@ -431,6 +458,12 @@ func (d *qemu) getMonitorEventHandler() func(event string, data map[string]any)
 				target = "reboot"
 			}
 
+			// QEMU is left paused when the guest shuts down while changed blocks are tracked, so that the
+			// bitmap can be stored before it exits.
+			if (entry == "guest-shutdown" || entry == "guest-reset") && shared.IsTrue(d.expandedConfig["backups.changed_block_tracking"]) {
+				d.changedBlocksShutdown()
+			}
+
 			if entry == qmp.EventVMShutdownReasonDisconnect {
 				d.logger.Warn("Instance stopped", logger.Ctx{"target": target, "reason": data["reason"]})
 			} else {
@@ -486,6 +519,51 @@ func (d *qemu) unmount() error {
 	return nil
 }
 
+// deleteMovedRootVolume deletes the root volume the VM was started from if its root disk was moved to another
+// pool while running. The firmware variables are carried over as the VM kept using the previous volume's copy.
+func (d *qemu) deleteMovedRootVolume() error {
+	poolName := d.localConfig["volatile.move.source_pool"]
+	if poolName == "" {
+		return nil
+	}
+
+	pool, err := storagePools.LoadByName(d.state, poolName)
+	if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return err
+	}
+
+	currentPool, err := d.getStoragePool()
+	if err != nil {
+		return err
+	}
+
+	// Never delete the volume in use, for example after the instance was moved back to that pool.
+	if pool != nil && pool.Name() != currentPool.Name() {
+		volStorageName := project.Instance(d.project.Name, d.name)
+		oldNvramPath := filepath.Join(storageDrivers.GetVolumeMountPath(poolName, storageDrivers.VolumeTypeVM, volStorageName), "qemu.nvram")
+
+		oldNvramTarget, err := filepath.EvalSymlinks(oldNvramPath)
+		if err == nil {
+			nvramTarget, err := filepath.EvalSymlinks(d.nvramPath())
+			if err != nil {
+				return fmt.Errorf("Failed resolving firmware variables path: %w", err)
+			}
+
+			err = shared.FileCopy(oldNvramTarget, nvramTarget)
+			if err != nil {
+				return fmt.Errorf("Failed copying firmware variables: %w", err)
+			}
+		}
+
+		err = pool.DeleteMovedInstance(d, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return d.VolatileSet(map[string]string{"volatile.move.source_pool": ""})
+}
+
 // generateAgentCert creates the necessary server key and certificate if needed.
 func (d *qemu) generateAgentCert() (agentCert string, agentKey string, clientCert string, clientKey string, err error) {
 	agentCertFile := filepath.Join(d.Path(), "agent.crt")
@@ -621,11 +699,20 @@ func (d *qemu) onStop(target string) error {
 		d.logger.Error("Failed recording last power state", logger.Ctx{"err": err})
 	}
 
+	// Withdraw the service records of the instance.
+	d.notifyServiceRecords(slices.Collect(maps.Keys(d.expandedConfig)))
+
 	// Cleanup.
 	d.cleanupDevices() // Must be called before unmount.
 	_ = os.Remove(d.pidFilePath())
 	_ = os.Remove(d.monitorPath())
 
+	// Now that QEMU released it, delete the root volume left behind by a live move to another pool.
+	err = d.deleteMovedRootVolume()
+	if err != nil {
+		d.logger.Error("Failed deleting previous root volume", logger.Ctx{"err": err})
+	}
+
 	// Stop the storage for the instance.
 	err = d.unmount()
 	if err != nil && !errors.Is(err, storageDrivers.ErrInUse) {
@@ -1072,6 +1159,40 @@ func (d *qemu) validateStartup(stateful bool, statusCode api.StatusCode) error {
 		return errors.New("Instance is protected from being started")
 	}
 
+	// Check memory hotplug constraints.
+	if d.expandedConfig["limits.memory.hotplug"] != "" {
+		if !d.architectureSupportsMemoryHotplug() {
+			return fmt.Errorf("Memory hotplug isn't supported on %q", d.architectureName)
+		}
+
+		if shared.IsTrue(d.expandedConfig["limits.memory.hugepages"]) {
+			return errors.New("limits.memory.hotplug cannot be used with limits.memory.hugepages")
+		}
+
+		if shared.IsTrue(d.expandedConfig["migration.stateful"]) {
+			return errors.New("limits.memory.hotplug cannot be used with migration.stateful")
+		}
+
+		memSize := d.expandedConfig["limits.memory"]
+		if memSize == "" {
+			memSize = QEMUDefaultMemSize
+		}
+
+		memSizeBytes, err := parseMemoryStr(memSize)
+		if err != nil {
+			return fmt.Errorf("limits.memory invalid: %w", err)
+		}
+
+		maxMemSizeBytes, err := units.ParseByteSizeString(d.expandedConfig["limits.memory.hotplug"])
+		if err != nil {
+			return fmt.Errorf("limits.memory.hotplug invalid: %w", err)
+		}
+
+		if maxMemSizeBytes < memSizeBytes {
+			return errors.New("limits.memory.hotplug cannot be smaller than limits.memory")
+		}
+	}
+
 	return nil
 }
 
@@ -1176,6 +1297,12 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 
 	revert.Add(func() { _ = d.unmount() })
 
+	// Clean up after a live move to another pool if the VM wasn't stopped cleanly.
+	err = d.deleteMovedRootVolume()
+	if err != nil {
+		d.logger.Warn("Failed deleting previous root volume", logger.Ctx{"err": err})
+	}
+
 	// Define a set of files to open and pass their file descriptors to QEMU command.
 	fdFiles := make([]*os.File, 0)
 
@@ -1523,6 +1650,7 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 		"-sandbox", "on,obsolete=deny,elevateprivileges=allow,spawn=allow,resourcecontrol=deny",
 		"-readconfig", confFile,
 		"-spice", d.spiceCmdlineConfig(),
+		"-vnc", d.vncCmdlineConfig(),
 		"-pidfile", d.pidFilePath(),
 		"-D", d.LogFilePath(),
 	}
@@ -1763,6 +1891,13 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 		}
 	}
 
+	// Start tracking the changed blocks of the root disk before the guest can write to it.
+	err = d.setupChangedBlockTracking(monitor)
+	if err != nil {
+		op.Done(err)
+		return fmt.Errorf("Failed setting up changed block tracking: %w", err)
+	}
+
 	// Due to a bug in QEMU, devices added using QMP's device_add command do not have their bootindex option
 	// respected (even if added before emuation is started). To workaround this we must reset the VM in order
 	// for it to rebuild its boot config and to take into account the devices bootindex settings.
@@ -1784,6 +1919,11 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 		"panic":    "pause",    // Pause on panics to allow investigation.
 	}
 
+	// Keep QEMU around once the guest shut down so that the changed blocks bitmap can be stored.
+	if shared.IsTrue(d.expandedConfig["backups.changed_block_tracking"]) {
+		actions["shutdown"] = "pause"
+	}
+
 	err = monitor.SetAction(actions)
 	if err != nil {
 		op.Done(err)
@@ -1831,6 +1971,9 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 
 	revert.Success()
 
+	// Publish the service records of the instance now that it runs.
+	d.notifyServiceRecords(slices.Collect(maps.Keys(d.expandedConfig)))
+
 	// Run any post-start hooks.
 	err = d.runHooks(postStartHooks)
 	if err != nil {
@@ -2373,200 +2516,882 @@ func (d *qemu) deviceAttachPath(deviceName string) (mountTag string, err error)
 		return "", errors.New("Virtiofsd isn't running")
 	}
 
-	// Check if the agent is running.
+	// Check if the agent is running.
+	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler())
+	if err != nil {
+		return "", fmt.Errorf("Failed to connect to QMP monitor: %w", err)
+	}
+
+	// Open a file descriptor to the socket file through O_PATH to avoid acessing the file descriptor to the sockfs inode.
+	socketFile, err := os.OpenFile(virtiofsdSockPath, unix.O_PATH|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return "", fmt.Errorf("Failed to open device socket file %q: %w", virtiofsdSockPath, err)
+	}
+
+	defer func() {
+		err := socketFile.Close()
+		if err != nil {
+			d.logger.Warn("Failed closing device socket file", logger.Ctx{"device": deviceName, "file": virtiofsdSockPath, "err": err})
+		}
+	}()
+
+	shortPath := fmt.Sprintf("/dev/fd/%d", socketFile.Fd())
+
+	addr, err := net.ResolveUnixAddr("unix", shortPath)
+	if err != nil {
+		return "", err
+	}
+
+	virtiofsSock, err := net.DialUnix("unix", nil, addr)
+	if err != nil {
+		return "", fmt.Errorf("Error connecting to virtiofs socket %q: %w", virtiofsdSockPath, err)
+	}
+
+	defer func() { _ = virtiofsSock.Close() }() // Close file after device has been added.
+
+	virtiofsFile, err := virtiofsSock.File()
+	if err != nil {
+		return "", fmt.Errorf("Error opening virtiofs socket %q: %w", virtiofsdSockPath, err)
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	err = monitor.SendFile(virtiofsdSockPath, virtiofsFile)
+	if err != nil {
+		return "", fmt.Errorf("Failed to send virtiofs file descriptor: %w", err)
+	}
+
+	reverter.Add(func() { _ = monitor.CloseFile(virtiofsdSockPath) })
+
+	err = monitor.AddCharDevice(map[string]any{
+		"id": deviceID,
+		"backend": map[string]any{
+			"type": "socket",
+			"data": map[string]any{
+				"addr": map[string]any{
+					"type": "fd",
+					"data": map[string]any{
+						"str": virtiofsdSockPath,
+					},
+				},
+				"server": false,
+			},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("Failed to add the character device: %w", err)
+	}
+
+	reverter.Add(func() { _ = monitor.RemoveCharDevice(deviceID) })
+
+	// Try to get a PCI address for hotplugging.
+	busCleanup, busName, busAddr, _, err := d.busAllocatePCIeHotplug(deviceName, false)
+	if err != nil {
+		return "", err
+	}
+
+	if busCleanup != nil {
+		reverter.Add(busCleanup)
+	}
+
+	qemuDev := map[string]any{
+		"driver":  "vhost-user-fs-pci",
+		"bus":     busName,
+		"addr":    busAddr,
+		"tag":     mountTag,
+		"chardev": deviceID,
+		"id":      deviceID,
+	}
+
+	err = monitor.AddDevice(qemuDev)
+	if err != nil {
+		return "", fmt.Errorf("Failed to add the virtiofs device to port %q: %w", busName, err)
+	}
+
+	reverter.Success()
+	return mountTag, nil
+}
+
+func (d *qemu) deviceAttachBlockDevice(mount deviceConfig.MountEntryItem) error {
+	// Check if the agent is running.
+	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler())
+	if err != nil {
+		return fmt.Errorf("Failed to connect to QMP monitor: %w", err)
+	}
+
+	monHook, err := d.addDriveConfig(d.busAllocatePCIeHotplug, nil, mount)
+	if err != nil {
+		return fmt.Errorf("Failed to add drive config: %w", err)
+	}
+
+	err = monHook(monitor)
+	if err != nil {
+		return fmt.Errorf("Failed setting up device via monitor: %w", err)
+	}
+
+	return nil
+}
+
+func (d *qemu) deviceDetachPath(deviceName string) error {
+	deviceID := qemuDeviceNameOrID(qemuDeviceIDPrefix, deviceName, "-virtio-fs", qemuDeviceIDMaxLength)
+
+	// Check if the agent is running.
+	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler())
+	if err != nil {
+		return err
+	}
+
+	err = monitor.RemoveDevice(deviceID)
+	if err != nil {
+		return err
+	}
+
+	waitDuration := time.Second * 10
+	waitUntil := time.Now().Add(waitDuration)
+	for {
+		err = monitor.RemoveCharDevice(deviceID)
+		if err == nil {
+			break
+		}
+
+		if api.StatusErrorCheck(err, http.StatusLocked) {
+			time.Sleep(time.Second * 2)
+			continue
+		}
+
+		if time.Now().After(waitUntil) {
+			return fmt.Errorf("Failed to detach path device after %v", waitDuration)
+		}
+	}
+
+	return nil
+}
+
+func (d *qemu) deviceDetachBlockDevice(deviceName string) error {
+	d.logger.Debug("Detaching block device", logger.Ctx{"device": deviceName})
+	defer d.logger.Debug("Finished detaching block device", logger.Ctx{"device": deviceName})
+
+	// Check if the agent is running.
+	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler())
+	if err != nil {
+		return err
+	}
+
+	deviceID := qemuDeviceIDPrefix + filesystem.PathNameEncode(deviceName)
+
+	// The device may be backed by the mirror node if its volume was moved while running.
+	blockDevNames := []string{
+		qemuDeviceNameOrID(qemuDeviceNamePrefix, deviceName, "", qemuDeviceNameMaxLength),
+		qemuDeviceNameOrID(qemuDeviceNamePrefix, deviceName, qemuDeviceMirrorSuffix, qemuDeviceNameMaxLength),
+	}
+
+	for _, blockDevName := range blockDevNames {
+		err = monitor.RemoveFDFromFDSet(blockDevName)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = monitor.RemoveDevice(deviceID)
+	if err != nil {
+		return err
+	}
+
+	waitDuration := time.Second * 10
+	waitUntil := time.Now().Add(waitDuration)
+	for _, blockDevName := range blockDevNames {
+		for {
+			err = monitor.RemoveBlockDevice(blockDevName)
+			if err == nil {
+				break
+			}
+
+			if time.Now().After(waitUntil) {
+				return fmt.Errorf("Failed to detach block device after %v: %w", waitDuration, err)
+			}
+
+			if api.StatusErrorCheck(err, http.StatusLocked) {
+				time.Sleep(time.Second * 2)
+				continue
+			}
+		}
+	}
+
+	return nil
+}
+
+// diskDeviceBlockNodeName returns the name of the block node currently backing the disk device and the name
+// of the alternate node used when mirroring it.
+func (d *qemu) diskDeviceBlockNodeName(monitor *qmp.Monitor, deviceName string) (string, string, error) {
+	baseName := qemuDeviceNameOrID(qemuDeviceNamePrefix, deviceName, "", qemuDeviceNameMaxLength)
+	mirrorName := qemuDeviceNameOrID(qemuDeviceNamePrefix, deviceName, qemuDeviceMirrorSuffix, qemuDeviceNameMaxLength)
+
+	exists, err := monitor.BlockNodeExists(baseName)
+	if err != nil {
+		return "", "", err
+	}
+
+	if exists {
+		return baseName, mirrorName, nil
+	}
+
+	exists, err = monitor.BlockNodeExists(mirrorName)
+	if err != nil {
+		return "", "", err
+	}
+
+	if !exists {
+		return "", "", fmt.Errorf("No block node found for disk device %q", deviceName)
+	}
+
+	return mirrorName, baseName, nil
+}
+
+// DiskDeviceMirror copies the block volume backing a disk device of the running VM to targetPath and switches the
+// device over to it without interrupting the guest. The device is then updated to use the given pool and source.
+// The cleanup hook removes the new volume and is only run if the device couldn't be switched over to it, as the
+// guest depends on the new volume afterwards.
+func (d *qemu) DiskDeviceMirror(deviceName string, targetPath string, poolName string, source string, cleanup revert.Hook) error {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	reverter.Add(cleanup)
+
+	dev, found := d.localDevices[deviceName]
+	if !found || dev["type"] != "disk" {
+		return fmt.Errorf("Disk device %q not found", deviceName)
+	}
+
+	if !d.IsRunning() {
+		return errors.New("Disk devices can only be mirrored on running instances")
+	}
+
+	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler())
+	if err != nil {
+		return err
+	}
+
+	err = d.diskDeviceMirror(monitor, deviceName, targetPath)
+	if err != nil {
+		return err
+	}
+
+	// The guest now uses the new volume, so it must be kept whatever happens next.
+	reverter.Success()
+
+	// Record the new location of the volume.
+	newDev := dev.Clone()
+	newDev["pool"] = poolName
+	newDev["source"] = source
+
+	d.localDevices[deviceName] = newDev
+
+	err = d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		devices, err := dbCluster.APIToDevices(d.localDevices.CloneNative())
+		if err != nil {
+			return err
+		}
+
+		return dbCluster.UpdateInstanceDevices(ctx, tx.Tx(), int64(d.id), devices)
+	})
+	if err != nil {
+		d.localDevices[deviceName] = dev
+
+		d.logger.Error("Failed recording volume move of disk device of running VM, its previous volume is outdated", logger.Ctx{"device": deviceName, "pool": poolName, "source": source, "err": err})
+
+		return fmt.Errorf("Disk device %q uses volume %q on storage pool %q but recording the move failed, its previous volume is outdated: %w", deviceName, source, poolName, err)
+	}
+
+	err = d.expandConfig()
+	if err != nil {
+		return err
+	}
+
+	err = d.UpdateBackupFile()
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Failed to write backup file: %w", err)
+	}
+
+	return nil
+}
+
+// RootDiskMirror copies the root disk of the running VM to targetPath, the disk of its root volume on the given
+// pool, and switches the VM over to it without interrupting the guest. The root volume the VM was started from is
+// deleted once the VM stops.
+// The cleanup hook removes the new root volume and is only run if the VM couldn't be switched over to it, as the
+// guest depends on the new volume afterwards.
+func (d *qemu) RootDiskMirror(targetPath string, poolName string, cleanup revert.Hook) error {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	reverter.Add(cleanup)
+
+	if !d.IsRunning() {
+		return errors.New("The root disk can only be mirrored on running instances")
+	}
+
+	oldPool, err := d.getStoragePool()
+	if err != nil {
+		return err
+	}
+
+	rootDiskName, rootDev, err := d.getRootDiskDevice()
+	if err != nil {
+		return err
+	}
+
+	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler())
+	if err != nil {
+		return err
+	}
+
+	err = d.diskDeviceMirror(monitor, rootDiskName, targetPath)
+	if err != nil {
+		return err
+	}
+
+	// The guest now runs from the new root volume, so it must be kept whatever happens next.
+	reverter.Success()
+
+	// The dirty bitmap was left behind on the previous block node.
+	err = d.setupChangedBlockTracking(monitor)
+	if err != nil {
+		d.logger.Warn("Failed setting up changed block tracking on mirrored root disk", logger.Ctx{"err": err})
+	}
+
+	// Record the new pool as a local override of the root disk device.
+	newRootDev := maps.Clone(rootDev)
+	newRootDev["pool"] = poolName
+
+	oldLocalRootDev, hadLocalRootDev := d.localDevices[rootDiskName]
+	d.localDevices[rootDiskName] = newRootDev
+
+	// Only the volume the VM was started from is still in use by QEMU, any intermediate one can go right away.
+	sourcePool := d.localConfig["volatile.move.source_pool"]
+
+	err = d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		devices, err := dbCluster.APIToDevices(d.localDevices.CloneNative())
+		if err != nil {
+			return err
+		}
+
+		err = dbCluster.UpdateInstanceDevices(ctx, tx.Tx(), int64(d.id), devices)
+		if err != nil {
+			return err
+		}
+
+		err = tx.RemoveStoragePoolVolume(ctx, d.project.Name, d.name, dbCluster.StoragePoolVolumeTypeVM, oldPool.ID())
+		if err != nil {
+			return err
+		}
+
+		if sourcePool == "" {
+			return tx.UpdateInstanceConfig(d.id, map[string]string{"volatile.move.source_pool": oldPool.Name()})
+		}
+
+		return nil
+	})
+	if err != nil {
+		if hadLocalRootDev {
+			d.localDevices[rootDiskName] = oldLocalRootDev
+		} else {
+			delete(d.localDevices, rootDiskName)
+		}
+
+		d.logger.Error("Failed recording root volume move of running VM, its previous root volume is outdated", logger.Ctx{"pool": poolName, "previousPool": oldPool.Name(), "err": err})
+
+		return fmt.Errorf("The VM runs from its new root volume on storage pool %q but recording the move failed, its root volume on storage pool %q is outdated: %w", poolName, oldPool.Name(), err)
+	}
+
+	if sourcePool == "" {
+		d.localConfig["volatile.move.source_pool"] = oldPool.Name()
+	}
+
+	d.storagePool = nil
+
+	err = d.expandConfig()
+	if err != nil {
+		return err
+	}
+
+	if sourcePool != "" {
+		err = oldPool.DeleteMovedInstance(d, nil)
+		if err != nil {
+			d.logger.Warn("Failed deleting previous root volume", logger.Ctx{"pool": oldPool.Name(), "err": err})
+		}
+	}
+
+	err = d.UpdateBackupFile()
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Failed to write backup file: %w", err)
+	}
+
+	return nil
+}
+
+// diskDeviceMirror mirrors the block node currently backing the disk device to targetPath and switches the guest
+// over to it once both are in sync.
+func (d *qemu) diskDeviceMirror(monitor *qmp.Monitor, deviceName string, targetPath string) error {
+	nodeName, targetNodeName, err := d.diskDeviceBlockNodeName(monitor, deviceName)
+	if err != nil {
+		return err
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// Use direct I/O for the target where supported, matching what is used for newly attached disks.
+	directCache := true
+	f, err := os.OpenFile(targetPath, unix.O_RDWR|unix.O_DIRECT, 0)
+	if err != nil {
+		directCache = false
+
+		f, err = os.OpenFile(targetPath, unix.O_RDWR, 0)
+		if err != nil {
+			return fmt.Errorf("Failed opening mirror target %q: %w", targetPath, err)
+		}
+	}
+
+	defer func() { _ = f.Close() }()
+
+	fInfo, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	info, err := monitor.SendFileWithFDSet(targetNodeName, f, false)
+	if err != nil {
+		return fmt.Errorf("Failed sending file descriptor of %q for disk device %q: %w", targetPath, deviceName, err)
+	}
+
+	reverter.Add(func() { _ = monitor.RemoveFDFromFDSet(targetNodeName) })
+
+	blockDev := map[string]any{
+		"aio": "threads",
+		"cache": map[string]any{
+			"direct":   directCache,
+			"no-flush": false,
+		},
+		"discard":   "unmap",
+		"driver":    "file",
+		"filename":  fmt.Sprintf("/dev/fdset/%d", info.ID),
+		"locking":   "off",
+		"node-name": targetNodeName,
+		"read-only": false,
+	}
+
+	if shared.IsBlockdev(fInfo.Mode()) {
+		blockDev["driver"] = "host_device"
+	}
+
+	err = monitor.AddBlockDevice(blockDev, nil)
+	if err != nil {
+		return fmt.Errorf("Failed adding mirror target for disk device %q: %w", deviceName, err)
+	}
+
+	reverter.Add(func() { _ = monitor.RemoveBlockDevice(targetNodeName) })
+
+	d.logger.Debug("Mirroring disk device", logger.Ctx{"device": deviceName, "target": targetPath})
+
+	err = monitor.BlockDevMirrorFull(d.state.ShutdownCtx, nodeName, targetNodeName)
+	if err != nil {
+		return fmt.Errorf("Failed mirroring disk device %q: %w", deviceName, err)
+	}
+
+	// The guest is now using the target, the original node can't be used to revert anymore.
+	reverter.Success()
+
+	err = monitor.RemoveBlockDevice(nodeName)
+	if err != nil {
+		d.logger.Warn("Failed removing previous block node of mirrored disk device", logger.Ctx{"device": deviceName, "err": err})
+	}
+
+	err = monitor.RemoveFDFromFDSet(nodeName)
+	if err != nil {
+		d.logger.Warn("Failed removing previous file descriptor of mirrored disk device", logger.Ctx{"device": deviceName, "err": err})
+	}
+
+	return nil
+}
+
+// changedBlocksStorePath returns the path of the qcow2 image the changed blocks bitmap is stored in while the VM is
+// stopped, as raw root disks can't store persistent bitmaps themselves.
+func (d *qemu) changedBlocksStorePath() string {
+	return filepath.Join(d.Path(), "changed-blocks.qcow2")
+}
+
+// setupChangedBlockTracking adds the dirty bitmap tracking the changed blocks of the root disk if enabled.
+// The changed blocks stored when the VM was last stopped are restored into it so that the checkpoint remains
+// valid. If they can't be restored, the checkpoint is discarded and the next backup must be a full one.
+func (d *qemu) setupChangedBlockTracking(monitor *qmp.Monitor) error {
+	// The stored bitmap only matches the root disk as it was when the VM stopped.
+	storePath := d.changedBlocksStorePath()
+	defer func() { _ = os.Remove(storePath) }()
+
+	checkpoint := d.localConfig["volatile.backup.checkpoint"]
+
+	if shared.IsFalseOrEmpty(d.expandedConfig["backups.changed_block_tracking"]) {
+		if checkpoint != "" {
+			return d.VolatileSet(map[string]string{"volatile.backup.checkpoint": ""})
+		}
+
+		return nil
+	}
+
+	rootDiskName, _, err := d.getRootDiskDevice()
+	if err != nil {
+		return err
+	}
+
+	nodeName, _, err := d.diskDeviceBlockNodeName(monitor, rootDiskName)
+	if err != nil {
+		return err
+	}
+
+	err = monitor.BlockDirtyBitmapAdd(nodeName, qemuChangedBlocksBitmapName, qemuChangedBlocksGranularity, false)
+	if err != nil {
+		return err
+	}
+
+	if checkpoint == "" {
+		return nil
+	}
+
+	if !shared.PathExists(storePath) {
+		err = errors.New("No stored changed blocks found")
+	} else {
+		err = d.changedBlocksRestore(monitor, nodeName)
+	}
+
+	if err != nil {
+		d.logger.Warn("Discarding changed block tracking checkpoint", logger.Ctx{"checkpoint": checkpoint, "err": err})
+		return d.VolatileSet(map[string]string{"volatile.backup.checkpoint": ""})
+	}
+
+	return nil
+}
+
+// changedBlocksStoreAdd adds the image storing the changed blocks bitmap as a block node.
+// The returned function removes the block node, which writes its persistent bitmaps to the image.
+func (d *qemu) changedBlocksStoreAdd(monitor *qmp.Monitor, readonly bool) (func() error, error) {
+	permissions := os.O_RDWR
+	if readonly {
+		permissions = os.O_RDONLY
+	}
+
+	f, err := os.OpenFile(d.changedBlocksStorePath(), permissions, 0)
+	if err != nil {
+		return nil, fmt.Errorf("Failed opening changed blocks store: %w", err)
+	}
+
+	defer func() { _ = f.Close() }()
+
+	info, err := monitor.SendFileWithFDSet(qemuChangedBlocksStoreNodeName, f, readonly)
+	if err != nil {
+		return nil, fmt.Errorf("Failed sending file descriptor of %q: %w", f.Name(), err)
+	}
+
+	blockDev := map[string]any{
+		"driver":    "qcow2",
+		"node-name": qemuChangedBlocksStoreNodeName,
+		"read-only": readonly,
+		"file": map[string]any{
+			"driver":   "file",
+			"filename": fmt.Sprintf("/dev/fdset/%d", info.ID),
+		},
+	}
+
+	err = monitor.AddBlockDevice(blockDev, nil)
+	if err != nil {
+		_ = monitor.RemoveFDFromFDSet(qemuChangedBlocksStoreNodeName)
+		return nil, fmt.Errorf("Failed adding changed blocks store: %w", err)
+	}
+
+	remove := func() error {
+		err := monitor.RemoveBlockDevice(qemuChangedBlocksStoreNodeName)
+		_ = monitor.RemoveFDFromFDSet(qemuChangedBlocksStoreNodeName)
+
+		return err
+	}
+
+	return remove, nil
+}
+
+// changedBlocksRestore marks the changed blocks stored when the VM was last stopped in the dirty bitmap of the
+// root disk block node.
+func (d *qemu) changedBlocksRestore(monitor *qmp.Monitor, nodeName string) error {
+	remove, err := d.changedBlocksStoreAdd(monitor, true)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = remove() }()
+
+	err = monitor.BlockDirtyBitmapMerge(nodeName, qemuChangedBlocksBitmapName, qemuChangedBlocksStoreNodeName, qemuChangedBlocksBitmapName)
+	if err != nil {
+		return fmt.Errorf("Failed restoring stored changed blocks: %w", err)
+	}
+
+	return nil
+}
+
+// changedBlocksPersist stores the dirty bitmap of the root disk in a persistent bitmap next to it so that the
+// checkpoint remains valid once the VM is stopped. The VM is paused first so that no write is missed, so this must
+// only be called when stopping it.
+func (d *qemu) changedBlocksPersist(monitor *qmp.Monitor) error {
+	if shared.IsFalseOrEmpty(d.expandedConfig["backups.changed_block_tracking"]) || d.localConfig["volatile.backup.checkpoint"] == "" {
+		return nil
+	}
+
+	err := monitor.Pause()
+	if err != nil {
+		return err
+	}
+
+	rootDiskName, _, err := d.getRootDiskDevice()
+	if err != nil {
+		return err
+	}
+
+	nodeName, _, err := d.diskDeviceBlockNodeName(monitor, rootDiskName)
+	if err != nil {
+		return err
+	}
+
+	sizeBytes, err := monitor.BlockNodeSize(nodeName)
+	if err != nil {
+		return err
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	storePath := d.changedBlocksStorePath()
+
+	// Don't depend on the shutdown context as VMs are stopped when LXD shuts down.
+	_, err = shared.RunCommandContext(context.Background(), "qemu-img", "create", "-f", "qcow2", storePath, strconv.FormatInt(sizeBytes, 10))
+	if err != nil {
+		return fmt.Errorf("Failed creating changed blocks store %q: %w", storePath, err)
+	}
+
+	reverter.Add(func() { _ = os.Remove(storePath) })
+
+	remove, err := d.changedBlocksStoreAdd(monitor, false)
+	if err != nil {
+		return err
+	}
+
+	reverter.Add(func() { _ = remove() })
+
+	err = monitor.BlockDirtyBitmapAdd(qemuChangedBlocksStoreNodeName, qemuChangedBlocksBitmapName, qemuChangedBlocksGranularity, true)
+	if err != nil {
+		return fmt.Errorf("Failed adding persistent changed blocks bitmap: %w", err)
+	}
+
+	err = monitor.BlockDirtyBitmapMerge(qemuChangedBlocksStoreNodeName, qemuChangedBlocksBitmapName, nodeName, qemuChangedBlocksBitmapName)
+	if err != nil {
+		return fmt.Errorf("Failed storing changed blocks: %w", err)
+	}
+
+	// Removing the block node writes the persistent bitmap to the image.
+	err = remove()
+	if err != nil {
+		return fmt.Errorf("Failed writing changed blocks store: %w", err)
+	}
+
+	reverter.Success()
+
+	return nil
+}
+
+// changedBlocksShutdown stores the changed blocks bitmap of a VM whose guest shut down and then stops QEMU, which
+// is left paused on guest shutdown while changed blocks are tracked.
+func (d *qemu) changedBlocksShutdown() {
+	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler())
+	if err != nil {
+		d.logger.Warn("Failed connecting to monitor to store changed blocks", logger.Ctx{"err": err})
+		return
+	}
+
+	err = d.changedBlocksPersist(monitor)
+	if err != nil {
+		d.logger.Warn("Failed storing changed blocks, the next backup must be a full backup", logger.Ctx{"err": err})
+	}
+
+	err = monitor.Quit()
+	if err != nil {
+		d.logger.Warn("Failed sending monitor quit command, forcing stop", logger.Ctx{"err": err})
+
+		err = d.forceStop()
+		if err != nil {
+			d.logger.Error("Failed stopping instance after guest shutdown", logger.Ctx{"err": err})
+		}
+	}
+}
+
+// changedBlocksMonitor returns a monitor connection and the root disk block node name of a running VM that tracks
+// the changed blocks of its root disk.
+func (d *qemu) changedBlocksMonitor() (*qmp.Monitor, string, error) {
+	if shared.IsFalseOrEmpty(d.expandedConfig["backups.changed_block_tracking"]) {
+		return nil, "", api.StatusErrorf(http.StatusBadRequest, "Changed block tracking isn't enabled for the instance")
+	}
+
+	if !d.IsRunning() {
+		return nil, "", api.StatusErrorf(http.StatusBadRequest, "Changed block tracking requires the instance to be running")
+	}
+
 	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler())
 	if err != nil {
-		return "", fmt.Errorf("Failed to connect to QMP monitor: %w", err)
+		return nil, "", err
 	}
 
-	// Open a file descriptor to the socket file through O_PATH to avoid acessing the file descriptor to the sockfs inode.
-	socketFile, err := os.OpenFile(virtiofsdSockPath, unix.O_PATH|unix.O_CLOEXEC, 0)
+	rootDiskName, _, err := d.getRootDiskDevice()
 	if err != nil {
-		return "", fmt.Errorf("Failed to open device socket file %q: %w", virtiofsdSockPath, err)
+		return nil, "", err
 	}
 
-	defer func() {
-		err := socketFile.Close()
-		if err != nil {
-			d.logger.Warn("Failed closing device socket file", logger.Ctx{"device": deviceName, "file": virtiofsdSockPath, "err": err})
-		}
-	}()
+	nodeName, _, err := d.diskDeviceBlockNodeName(monitor, rootDiskName)
+	if err != nil {
+		return nil, "", err
+	}
 
-	shortPath := fmt.Sprintf("/dev/fd/%d", socketFile.Fd())
+	return monitor, nodeName, nil
+}
 
-	addr, err := net.ResolveUnixAddr("unix", shortPath)
+// ChangedBlocksCheckpoint starts a new changed block tracking checkpoint of the root disk and then runs the given
+// backup function. The checkpoint is recorded under the given name only if the backup function succeeds.
+func (d *qemu) ChangedBlocksCheckpoint(name string, backup func() error) error {
+	monitor, nodeName, err := d.changedBlocksMonitor()
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	virtiofsSock, err := net.DialUnix("unix", nil, addr)
+	pid, err := d.pid()
 	if err != nil {
-		return "", fmt.Errorf("Error connecting to virtiofs socket %q: %w", virtiofsdSockPath, err)
+		return err
 	}
 
-	defer func() { _ = virtiofsSock.Close() }() // Close file after device has been added.
-
-	virtiofsFile, err := virtiofsSock.File()
+	// Discard the previous checkpoint first, as it isn't valid anymore once the bitmap is cleared.
+	err = d.VolatileSet(map[string]string{"volatile.backup.checkpoint": ""})
 	if err != nil {
-		return "", fmt.Errorf("Error opening virtiofs socket %q: %w", virtiofsdSockPath, err)
+		return err
 	}
 
-	reverter := revert.New()
-	defer reverter.Fail()
-
-	err = monitor.SendFile(virtiofsdSockPath, virtiofsFile)
+	err = monitor.BlockDirtyBitmapClear(nodeName, qemuChangedBlocksBitmapName)
 	if err != nil {
-		return "", fmt.Errorf("Failed to send virtiofs file descriptor: %w", err)
+		return fmt.Errorf("Failed clearing changed blocks bitmap: %w", err)
 	}
 
-	reverter.Add(func() { _ = monitor.CloseFile(virtiofsdSockPath) })
-
-	err = monitor.AddCharDevice(map[string]any{
-		"id": deviceID,
-		"backend": map[string]any{
-			"type": "socket",
-			"data": map[string]any{
-				"addr": map[string]any{
-					"type": "fd",
-					"data": map[string]any{
-						"str": virtiofsdSockPath,
-					},
-				},
-				"server": false,
-			},
-		},
-	})
+	err = backup()
 	if err != nil {
-		return "", fmt.Errorf("Failed to add the character device: %w", err)
+		return err
 	}
 
-	reverter.Add(func() { _ = monitor.RemoveCharDevice(deviceID) })
+	return d.changedBlocksCheckpointSet(pid, name)
+}
 
-	// Try to get a PCI address for hotplugging.
-	busCleanup, busName, busAddr, _, err := d.busAllocatePCIeHotplug(deviceName, false)
-	if err != nil {
-		return "", err
+// changedBlocksCheckpointSet records the checkpoint name if the VM hasn't been restarted since the given PID.
+func (d *qemu) changedBlocksCheckpointSet(pid int, name string) error {
+	// If the VM was restarted, the changed blocks are tracked from a new bitmap which doesn't match the checkpoint.
+	newPID, err := d.pid()
+	if err != nil || newPID != pid {
+		d.logger.Warn("Not recording changed block tracking checkpoint as the instance was restarted", logger.Ctx{"checkpoint": name})
+		return nil
 	}
 
-	if busCleanup != nil {
-		reverter.Add(busCleanup)
-	}
+	return d.VolatileSet(map[string]string{"volatile.backup.checkpoint": name})
+}
 
-	qemuDev := map[string]any{
-		"driver":  "vhost-user-fs-pci",
-		"bus":     busName,
-		"addr":    busAddr,
-		"tag":     mountTag,
-		"chardev": deviceID,
-		"id":      deviceID,
+// ChangedBlocksBackup writes the blocks of the root disk that changed since the parent checkpoint to a new qcow2 image
+// at targetPath. Blocks that didn't change are left unallocated in the image.
+// On success the new checkpoint is recorded under the given name.
+func (d *qemu) ChangedBlocksBackup(parent string, name string, targetPath string) error {
+	if d.localConfig["volatile.backup.checkpoint"] != parent {
+		return api.StatusErrorf(http.StatusBadRequest, "Backup %q isn't the latest changed block tracking checkpoint of the instance", parent)
 	}
 
-	err = monitor.AddDevice(qemuDev)
+	monitor, nodeName, err := d.changedBlocksMonitor()
 	if err != nil {
-		return "", fmt.Errorf("Failed to add the virtiofs device to port %q: %w", busName, err)
+		return err
 	}
 
-	reverter.Success()
-	return mountTag, nil
-}
-
-func (d *qemu) deviceAttachBlockDevice(mount deviceConfig.MountEntryItem) error {
-	// Check if the agent is running.
-	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler())
+	pid, err := d.pid()
 	if err != nil {
-		return fmt.Errorf("Failed to connect to QMP monitor: %w", err)
+		return err
 	}
 
-	monHook, err := d.addDriveConfig(d.busAllocatePCIeHotplug, nil, mount)
+	sizeBytes, err := monitor.BlockNodeSize(nodeName)
 	if err != nil {
-		return fmt.Errorf("Failed to add drive config: %w", err)
+		return err
 	}
 
-	err = monHook(monitor)
+	_, err = shared.RunCommandContext(d.state.ShutdownCtx, "qemu-img", "create", "-f", "qcow2", targetPath, strconv.FormatInt(sizeBytes, 10))
 	if err != nil {
-		return fmt.Errorf("Failed setting up device via monitor: %w", err)
+		return fmt.Errorf("Failed creating changed blocks image %q: %w", targetPath, err)
 	}
 
-	return nil
-}
-
-func (d *qemu) deviceDetachPath(deviceName string) error {
-	deviceID := qemuDeviceNameOrID(qemuDeviceIDPrefix, deviceName, "-virtio-fs", qemuDeviceIDMaxLength)
-
-	// Check if the agent is running.
-	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler())
+	f, err := os.OpenFile(targetPath, os.O_RDWR, 0)
 	if err != nil {
-		return err
+		return fmt.Errorf("Failed opening changed blocks image %q: %w", targetPath, err)
 	}
 
-	err = monitor.RemoveDevice(deviceID)
+	defer func() { _ = f.Close() }()
+
+	info, err := monitor.SendFileWithFDSet(qemuChangedBlocksNodeName, f, false)
 	if err != nil {
-		return err
+		return fmt.Errorf("Failed sending file descriptor of %q: %w", targetPath, err)
 	}
 
-	waitDuration := time.Second * 10
-	waitUntil := time.Now().Add(waitDuration)
-	for {
-		err = monitor.RemoveCharDevice(deviceID)
-		if err == nil {
-			break
-		}
+	defer func() { _ = monitor.RemoveFDFromFDSet(qemuChangedBlocksNodeName) }()
 
-		if api.StatusErrorCheck(err, http.StatusLocked) {
-			time.Sleep(time.Second * 2)
-			continue
-		}
+	blockDev := map[string]any{
+		"driver":    "qcow2",
+		"node-name": qemuChangedBlocksNodeName,
+		"file": map[string]any{
+			"driver":   "file",
+			"filename": fmt.Sprintf("/dev/fdset/%d", info.ID),
+		},
+	}
 
-		if time.Now().After(waitUntil) {
-			return fmt.Errorf("Failed to detach path device after %v", waitDuration)
-		}
+	err = monitor.AddBlockDevice(blockDev, nil)
+	if err != nil {
+		return fmt.Errorf("Failed adding changed blocks image: %w", err)
 	}
 
-	return nil
-}
+	defer func() { _ = monitor.RemoveBlockDevice(qemuChangedBlocksNodeName) }()
 
-func (d *qemu) deviceDetachBlockDevice(deviceName string) error {
-	d.logger.Debug("Detaching block device", logger.Ctx{"device": deviceName})
-	defer d.logger.Debug("Finished detaching block device", logger.Ctx{"device": deviceName})
+	d.logger.Debug("Backing up changed blocks of root disk", logger.Ctx{"parent": parent, "target": targetPath})
 
-	// Check if the agent is running.
-	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler())
+	// The bitmap is only cleared if the backup succeeds, so the parent checkpoint remains valid on failure.
+	ctx, cancel := context.WithTimeout(d.state.ShutdownCtx, qemuChangedBlocksBackupTimeout)
+	defer cancel()
+
+	err = monitor.BlockDevBackupIncremental(ctx, nodeName, qemuChangedBlocksNodeName, qemuChangedBlocksBitmapName)
 	if err != nil {
-		return err
+		return fmt.Errorf("Failed backing up changed blocks: %w", err)
 	}
 
-	deviceID := qemuDeviceIDPrefix + filesystem.PathNameEncode(deviceName)
-	blockDevName := qemuDeviceNameOrID(qemuDeviceNamePrefix, deviceName, "", qemuDeviceNameMaxLength)
+	return d.changedBlocksCheckpointSet(pid, name)
+}
 
-	err = monitor.RemoveFDFromFDSet(blockDevName)
-	if err != nil {
-		return err
+// DiskDeviceResize notifies the running VM that the block volume backing a disk device has grown.
+func (d *qemu) DiskDeviceResize(deviceName string, sizeBytes int64) error {
+	if !d.IsRunning() {
+		return nil
 	}
 
-	err = monitor.RemoveDevice(deviceID)
+	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler())
 	if err != nil {
 		return err
 	}
 
-	waitDuration := time.Second * 10
-	waitUntil := time.Now().Add(waitDuration)
-	for {
-		err = monitor.RemoveBlockDevice(blockDevName)
-		if err == nil {
-			break
-		}
-
-		if time.Now().After(waitUntil) {
-			return fmt.Errorf("Failed to detach block device after %v: %w", waitDuration, err)
-		}
-
-		if api.StatusErrorCheck(err, http.StatusLocked) {
-			time.Sleep(time.Second * 2)
-			continue
-		}
+	nodeName, _, err := d.diskDeviceBlockNodeName(monitor, deviceName)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	return monitor.BlockResize(nodeName, sizeBytes)
 }
 
 // deviceAttachNIC live attaches a NIC device to the instance.
@@ -2892,6 +3717,14 @@ func (d *qemu) spiceCmdlineConfig() string {
 	return "unix=on,disable-ticketing=on,addr=" + d.spicePath()
 }
 
+func (d *qemu) vncPath() string {
+	return filepath.Join(d.LogPath(), "qemu.vnc")
+}
+
+func (d *qemu) vncCmdlineConfig() string {
+	return "unix:" + d.vncPath()
+}
+
 // generateConfigShare generates the config share directory that will be exported to the VM via
 // a 9P share. Due to the unknown size of templates inside the images this directory is created
 // inside the VM's config volume so that it can be restricted by quota.
@@ -3944,8 +4777,20 @@ func (d *qemu) addCPUMemoryConfig(cfg *[]cfgSection, cpuInfo *cpuTopology) error
 	nodeMemory := int64(memSizeMB / int64(len(hostNodes)))
 	cpuOpts.memory = nodeMemory
 
+	memOpts := qemuMemoryOpts{memSizeMB: memSizeMB}
+
+	if d.expandedConfig["limits.memory.hotplug"] != "" && d.architectureSupportsMemoryHotplug() {
+		maxMemSizeBytes, err := units.ParseByteSizeString(d.expandedConfig["limits.memory.hotplug"])
+		if err != nil {
+			return fmt.Errorf("limits.memory.hotplug invalid: %w", err)
+		}
+
+		memOpts.maxMemSizeMB = maxMemSizeBytes / 1024 / 1024
+		memOpts.slots = qemuMemoryHotplugSlots
+	}
+
 	if cfg != nil {
-		*cfg = append(*cfg, qemuMemory(&qemuMemoryOpts{memSizeMB})...)
+		*cfg = append(*cfg, qemuMemory(&memOpts)...)
 		*cfg = append(*cfg, qemuCPU(&cpuOpts, cpuPinning)...)
 	}
 
@@ -5278,6 +6123,12 @@ func (d *qemu) Stop(stateful bool) error {
 		}
 	}
 
+	// Keep the changed blocks across the stop.
+	err = d.changedBlocksPersist(monitor)
+	if err != nil {
+		d.logger.Warn("Failed storing changed blocks, the next backup must be a full backup", logger.Ctx{"err": err})
+	}
+
 	// Get the wait channel.
 	chDisconnect, err := monitor.Wait()
 	if err != nil {
@@ -5449,6 +6300,15 @@ func (d *qemu) Restore(source instance.Instance, stateful bool, diskVolumesMode
 		return err
 	}
 
+	// The changed blocks tracked so far don't apply to the restored root disk.
+	if d.localConfig["volatile.backup.checkpoint"] != "" {
+		err = d.VolatileSet(map[string]string{"volatile.backup.checkpoint": ""})
+		if err != nil {
+			op.Done(err)
+			return err
+		}
+	}
+
 	d.stateful = stateful
 
 	// Restart the instance.
@@ -6201,6 +7061,9 @@ func (d *qemu) Update(args db.InstanceArgs, userRequested bool) error {
 	}
 
 	if isRunning {
+		// Publish the changed service records.
+		d.notifyServiceRecords(changedConfig)
+
 		// Send devlxd notifications only for user.* key changes
 		for _, key := range changedConfig {
 			if !strings.HasPrefix(key, "user.") {
@@ -6240,6 +7103,7 @@ func (d *qemu) Update(args db.InstanceArgs, userRequested bool) error {
 }
 
 // updateMemoryLimit live updates the VM's memory limit by reszing the balloon device.
+// If memory hotplug is configured, memory beyond the current size is hot-added first.
 func (d *qemu) updateMemoryLimit(newLimit string) error {
 	if newLimit == "" {
 		return nil
@@ -6270,6 +7134,13 @@ func (d *qemu) updateMemoryLimit(newLimit string) error {
 
 	baseSizeMB := baseSizeBytes / 1024 / 1024
 
+	pluggedSizeBytes, err := monitor.GetMemoryPluggedSizeBytes()
+	if err != nil {
+		return err
+	}
+
+	totalSizeMB := (baseSizeBytes + pluggedSizeBytes) / 1024 / 1024
+
 	curSizeBytes, err := monitor.GetMemoryBalloonSizeBytes()
 	if err != nil {
 		return err
@@ -6279,8 +7150,15 @@ func (d *qemu) updateMemoryLimit(newLimit string) error {
 
 	if curSizeMB == newSizeMB {
 		return nil
-	} else if baseSizeMB < newSizeMB {
-		return fmt.Errorf("Cannot increase memory size beyond boot time size when VM is running (Boot time size %dMiB, new size %dMiB)", baseSizeMB, newSizeMB)
+	} else if totalSizeMB < newSizeMB {
+		if d.expandedConfig["limits.memory.hotplug"] == "" || !d.architectureSupportsMemoryHotplug() {
+			return fmt.Errorf("Cannot increase memory size beyond boot time size when VM is running (Boot time size %dMiB, new size %dMiB)", baseSizeMB, newSizeMB)
+		}
+
+		err = d.hotplugMemory(monitor, totalSizeMB, newSizeMB)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Set effective memory size.
@@ -6316,6 +7194,85 @@ func (d *qemu) updateMemoryLimit(newLimit string) error {
 	return fmt.Errorf("Failed setting memory to %dMiB (currently %dMiB) as it was taking too long", newSizeMB, curSizeMB)
 }
 
+// hotplugMemory hot-adds a memory device to grow the VM's memory from curSizeMB to at least newSizeMB.
+// The added size is rounded up to qemuMemoryHotplugAlignMB and any excess is left to the balloon to reclaim.
+func (d *qemu) hotplugMemory(monitor *qmp.Monitor, curSizeMB int64, newSizeMB int64) error {
+	maxSizeBytes, err := units.ParseByteSizeString(d.expandedConfig["limits.memory.hotplug"])
+	if err != nil {
+		return fmt.Errorf("Invalid memory hotplug size: %w", err)
+	}
+
+	maxSizeMB := maxSizeBytes / 1024 / 1024
+	if maxSizeMB < newSizeMB {
+		return fmt.Errorf("Cannot increase memory size beyond limits.memory.hotplug when VM is running (Hotplug size %dMiB, new size %dMiB)", maxSizeMB, newSizeMB)
+	}
+
+	addSizeMB := qemuMemoryHotplugSize(curSizeMB, newSizeMB, maxSizeMB)
+
+	memDevices, err := monitor.QueryMemoryDevices()
+	if err != nil {
+		return err
+	}
+
+	devID, err := qemuMemoryHotplugDeviceID(memDevices)
+	if err != nil {
+		return err
+	}
+
+	memoryBackend := map[string]any{
+		"qom-type": "memory-backend-memfd",
+		"id":       "mem" + devID,
+		"size":     addSizeMB * 1024 * 1024,
+		"share":    true,
+	}
+
+	memoryDevice := map[string]any{
+		"driver": "pc-dimm",
+		"id":     devID,
+		"memdev": "mem" + devID,
+	}
+
+	err = monitor.AddMemoryDevice(memoryBackend, memoryDevice)
+	if err != nil {
+		return err
+	}
+
+	d.logger.Debug("Hotplugged memory", logger.Ctx{"device": devID, "sizeMiB": addSizeMB})
+
+	return nil
+}
+
+// qemuMemoryHotplugSize returns the size in MiB of the memory device to add to grow the memory from curSizeMB to
+// newSizeMB. The size is rounded up to the alignment, without exceeding the reserved address space of maxSizeMB.
+func qemuMemoryHotplugSize(curSizeMB int64, newSizeMB int64, maxSizeMB int64) int64 {
+	addSizeMB := newSizeMB - curSizeMB
+	addSizeMB = ((addSizeMB + qemuMemoryHotplugAlignMB - 1) / qemuMemoryHotplugAlignMB) * qemuMemoryHotplugAlignMB
+
+	return min(addSizeMB, maxSizeMB-curSizeMB)
+}
+
+// qemuMemoryHotplugDeviceID returns the first unused ID for a new memory device.
+// Returns an error if all the memory hotplug slots are used.
+func qemuMemoryHotplugDeviceID(memDevices []qmp.MemoryDevice) (string, error) {
+	if len(memDevices) >= qemuMemoryHotplugSlots {
+		return "", errors.New("No memory hotplug slots left, the VM must be restarted to increase its memory further")
+	}
+
+	usedIDs := make(map[string]struct{}, len(memDevices))
+	for _, memDevice := range memDevices {
+		usedIDs[memDevice.Data.ID] = struct{}{}
+	}
+
+	for i := 0; ; i++ {
+		devID := fmt.Sprintf("dimm%d", i)
+
+		_, found := usedIDs[devID]
+		if !found {
+			return devID, nil
+		}
+	}
+}
+
 func (d *qemu) cleanup() {
 	// Unmount any leftovers
 	_ = d.removeUnixDevices()
@@ -7048,7 +8005,17 @@ func (d *qemu) migrateSendLive(pool storagePools.Pool, clusterMoveSourceName str
 		return err
 	}
 
-	rootDiskName := "lxd_root"                  // Name of source disk device to sync from
+	rootDevName, _, err := d.getRootDiskDevice()
+	if err != nil {
+		return err
+	}
+
+	// Name of source disk device to sync from. This changes after the root disk was mirrored.
+	rootDiskName, _, err := d.diskDeviceBlockNodeName(monitor, rootDevName)
+	if err != nil {
+		return err
+	}
+
 	nbdTargetDiskName := "lxd_root_nbd"         // Name of NBD disk device added to local VM to sync to.
 	rootSnapshotDiskName := "lxd_root_snapshot" // Name of snapshot disk device to use.
 
@@ -8040,6 +9007,8 @@ func (d *qemu) Console(protocol string) (*os.File, chan error, error) {
 		path = d.consolePath()
 	case instance.ConsoleTypeVGA:
 		path = d.spicePath()
+	case instance.ConsoleTypeVNC:
+		path = d.vncPath()
 	default:
 		return nil, nil, fmt.Errorf("Unknown protocol %q", protocol)
 	}
@@ -8333,6 +9302,14 @@ func (d *qemu) renderState(statusCode api.StatusCode) (*api.InstanceState, error
 				}
 			}
 		}
+
+		// Report hotplugged memory.
+		if d.expandedConfig["limits.memory.hotplug"] != "" {
+			err = d.memoryHotplugState(&status.Memory)
+			if err != nil {
+				d.logger.Warn("Could not get VM hotplugged memory", logger.Ctx{"err": err})
+			}
+		}
 	}
 
 	status.Pid = int64(pid)
@@ -8346,6 +9323,33 @@ func (d *qemu) renderState(statusCode api.StatusCode) (*api.InstanceState, error
 	return status, nil
 }
 
+// memoryHotplugState populates the hotplugged memory size of a running VM.
+// The total memory size is only populated if not already reported by the agent.
+func (d *qemu) memoryHotplugState(memory *api.InstanceStateMemory) error {
+	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler())
+	if err != nil {
+		return err
+	}
+
+	pluggedSizeBytes, err := monitor.GetMemoryPluggedSizeBytes()
+	if err != nil {
+		return err
+	}
+
+	memory.Hotplugged = pluggedSizeBytes
+
+	if memory.Total == 0 {
+		baseSizeBytes, err := monitor.GetMemorySizeBytes()
+		if err != nil {
+			return err
+		}
+
+		memory.Total = baseSizeBytes + pluggedSizeBytes
+	}
+
+	return nil
+}
+
 // RenderState returns just state info about the instance.
 func (d *qemu) RenderState(_ []net.Interface) (*api.InstanceState, error) {
 	return d.renderState(d.statusCode())
@@ -9532,6 +10536,11 @@ func (d *qemu) architectureSupportsCPUHotplug() bool {
 	return found
 }
 
+// architectureSupportsMemoryHotplug returns whether memory devices can be hotplugged on the VM's architecture.
+func (d *qemu) architectureSupportsMemoryHotplug() bool {
+	return slices.Contains([]int{osarch.ARCH_64BIT_INTEL_X86, osarch.ARCH_64BIT_ARMV8_LITTLE_ENDIAN}, d.architecture)
+}
+
 // addFileDescriptor adds a file path to the list of files to open and pass file descriptor to other processes.
 // Returns the file descriptor number that the other process will receive.
 func (d *qemu) addFileDescriptor(fdFiles *[]*os.File, file *os.File) int {
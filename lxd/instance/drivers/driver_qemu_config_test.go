@@ -98,15 +98,27 @@ func TestQemuConfigTemplates(t *testing.T) {
 			opts     qemuMemoryOpts
 			expected string
 		}{{
-			qemuMemoryOpts{4096},
+			qemuMemoryOpts{memSizeMB: 4096},
 			`# Memory
 			[memory]
 			size = "4096M"`,
 		}, {
-			qemuMemoryOpts{8192},
+			qemuMemoryOpts{memSizeMB: 8192},
 			`# Memory
 			[memory]
 			size = "8192M"`,
+		}, {
+			qemuMemoryOpts{memSizeMB: 4096, maxMemSizeMB: 16384, slots: 16},
+			`# Memory
+			[memory]
+			size = "4096M"
+			slots = "16"
+			maxmem = "16384M"`,
+		}, {
+			qemuMemoryOpts{memSizeMB: 4096, maxMemSizeMB: 4096, slots: 16},
+			`# Memory
+			[memory]
+			size = "4096M"`,
 		}}
 		for _, tc := range testCases {
 			runTest(tc.expected, qemuMemory(&tc.opts))
@@ -142,14 +142,27 @@ func qemuBase(opts *qemuBaseOpts) []cfgSection {
 }
 
 type qemuMemoryOpts struct {
-	memSizeMB int64
+	memSizeMB    int64
+	maxMemSizeMB int64
+	slots        int
 }
 
 func qemuMemory(opts *qemuMemoryOpts) []cfgSection {
+	entries := []cfgEntry{{key: "size", value: fmt.Sprintf("%dM", opts.memSizeMB)}}
+
+	// Reserve the address space and slots for memory hotplug.
+	if opts.maxMemSizeMB > opts.memSizeMB && opts.slots > 0 {
+		entries = append(entries, cfgEntry{
+			key: "slots", value: strconv.Itoa(opts.slots),
+		}, cfgEntry{
+			key: "maxmem", value: fmt.Sprintf("%dM", opts.maxMemSizeMB),
+		})
+	}
+
 	return []cfgSection{{
 		name:    "memory",
 		comment: "Memory",
-		entries: []cfgEntry{{key: "size", value: fmt.Sprintf("%dM", opts.memSizeMB)}},
+		entries: entries,
 	}}
 }
 
@@ -0,0 +1,109 @@
+package drivers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/lxd/instance/drivers/qmp"
+)
+
+func Test_qemuMemoryHotplugSize(t *testing.T) {
+	tests := []struct {
+		name      string
+		curSizeMB int64
+		newSizeMB int64
+		maxSizeMB int64
+		want      int64
+	}{
+		{
+			name:      "Aligned size",
+			curSizeMB: 1024,
+			newSizeMB: 2048,
+			maxSizeMB: 4096,
+			want:      1024,
+		},
+		{
+			name:      "Rounded up to the alignment",
+			curSizeMB: 1024,
+			newSizeMB: 1100,
+			maxSizeMB: 4096,
+			want:      128,
+		},
+		{
+			name:      "Rounded up to the next alignment",
+			curSizeMB: 1024,
+			newSizeMB: 1153,
+			maxSizeMB: 4096,
+			want:      256,
+		},
+		{
+			name:      "Rounding limited by the reserved address space",
+			curSizeMB: 1024,
+			newSizeMB: 1100,
+			maxSizeMB: 1100,
+			want:      76,
+		},
+		{
+			name:      "Growing up to the reserved address space",
+			curSizeMB: 1024,
+			newSizeMB: 4096,
+			maxSizeMB: 4096,
+			want:      3072,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, qemuMemoryHotplugSize(tt.curSizeMB, tt.newSizeMB, tt.maxSizeMB))
+		})
+	}
+}
+
+// testMemoryDevices returns memory devices with the IDs.
+func testMemoryDevices(ids ...string) []qmp.MemoryDevice {
+	memDevices := make([]qmp.MemoryDevice, 0, len(ids))
+	for i, id := range ids {
+		memDevice := qmp.MemoryDevice{Type: "dimm"}
+		memDevice.Data.ID = id
+		memDevice.Data.Slot = i
+		memDevices = append(memDevices, memDevice)
+	}
+
+	return memDevices
+}
+
+func Test_qemuMemoryHotplugDeviceID(t *testing.T) {
+	// The first unused ID is selected.
+	devID, err := qemuMemoryHotplugDeviceID(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "dimm0", devID)
+
+	devID, err = qemuMemoryHotplugDeviceID(testMemoryDevices("dimm0", "dimm1"))
+	require.NoError(t, err)
+	assert.Equal(t, "dimm2", devID)
+
+	// IDs freed by removed devices are reused.
+	devID, err = qemuMemoryHotplugDeviceID(testMemoryDevices("dimm0", "dimm2"))
+	require.NoError(t, err)
+	assert.Equal(t, "dimm1", devID)
+
+	// Devices not added by LXD still use a slot.
+	devID, err = qemuMemoryHotplugDeviceID(testMemoryDevices("", "dimm0"))
+	require.NoError(t, err)
+	assert.Equal(t, "dimm1", devID)
+
+	// All the slots are used.
+	ids := make([]string, 0, qemuMemoryHotplugSlots)
+	for i := range qemuMemoryHotplugSlots {
+		ids = append(ids, fmt.Sprintf("dimm%d", i))
+	}
+
+	_, err = qemuMemoryHotplugDeviceID(testMemoryDevices(ids...))
+	assert.ErrorContains(t, err, "No memory hotplug slots left")
+
+	_, err = qemuMemoryHotplugDeviceID(testMemoryDevices(ids[:qemuMemoryHotplugSlots-1]...))
+	assert.NoError(t, err)
+}
@@ -62,6 +62,20 @@ type HotpluggableCPU struct {
 	Props CPUInstanceProperties `json:"props"`
 }
 
+// MemoryDevice contains information about a memory device.
+type MemoryDevice struct {
+	Type string `json:"type"`
+
+	Data struct {
+		ID           string `json:"id,omitempty"`
+		Size         int64  `json:"size"`
+		Slot         int    `json:"slot"`
+		Memdev       string `json:"memdev"`
+		Hotplugged   bool   `json:"hotplugged"`
+		Hotpluggable bool   `json:"hotpluggable"`
+	} `json:"data"`
+}
+
 // QueryCPUs returns a list of CPUs.
 func (m *Monitor) QueryCPUs() ([]CPU, error) {
 	// Prepare the response.
@@ -462,6 +476,65 @@ func (m *Monitor) SetMemoryBalloonSizeBytes(sizeBytes int64) error {
 	return m.run("balloon", args, nil)
 }
 
+// GetMemoryPluggedSizeBytes returns the size of the hotplugged memory in bytes.
+func (m *Monitor) GetMemoryPluggedSizeBytes() (int64, error) {
+	// Prepare the response.
+	var resp struct {
+		Return struct {
+			PluggedMemory int64 `json:"plugged-memory"`
+		} `json:"return"`
+	}
+
+	err := m.run("query-memory-size-summary", nil, &resp)
+	if err != nil {
+		return -1, err
+	}
+
+	return resp.Return.PluggedMemory, nil
+}
+
+// QueryMemoryDevices returns a list of memory devices.
+func (m *Monitor) QueryMemoryDevices() ([]MemoryDevice, error) {
+	// Prepare the response.
+	var resp struct {
+		Return []MemoryDevice `json:"return"`
+	}
+
+	err := m.run("query-memory-devices", nil, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query memory devices: %w", err)
+	}
+
+	return resp.Return, nil
+}
+
+// AddMemoryDevice adds a memory backend object and the memory device using it.
+func (m *Monitor) AddMemoryDevice(memoryBackend map[string]any, device map[string]any) error {
+	revert := revert.New()
+	defer revert.Fail()
+
+	err := m.run("object-add", memoryBackend, nil)
+	if err != nil {
+		return fmt.Errorf("Failed adding memory backend: %w", err)
+	}
+
+	revert.Add(func() {
+		memoryBackendDel := map[string]any{
+			"id": memoryBackend["id"],
+		}
+
+		_ = m.run("object-del", memoryBackendDel, nil)
+	})
+
+	err = m.AddDevice(device)
+	if err != nil {
+		return fmt.Errorf("Failed adding memory device: %w", err)
+	}
+
+	revert.Success()
+	return nil
+}
+
 // AddBlockDevice adds a block device.
 func (m *Monitor) AddBlockDevice(blockDev map[string]any, device map[string]any) error {
 	revert := revert.New()
@@ -964,6 +1037,328 @@ func (m *Monitor) BlockDevMirror(deviceNodeName string, targetNodeName string) e
 	return nil
 }
 
+// BlockDevMirrorFull copies the whole device to the target device and then switches the users of the device over to
+// the target device.
+// The mirror job is cancelled if ctx is done before it has concluded.
+func (m *Monitor) BlockDevMirrorFull(ctx context.Context, deviceNodeName string, targetNodeName string) error {
+	var args struct {
+		Device      string `json:"device"`
+		Target      string `json:"target"`
+		Sync        string `json:"sync"`
+		JobID       string `json:"job-id"`
+		CopyMode    string `json:"copy-mode"`
+		AutoDismiss bool   `json:"auto-dismiss"`
+	}
+
+	args.Device = deviceNodeName
+	args.Target = targetNodeName
+	args.JobID = deviceNodeName
+	args.Sync = "full"
+
+	// Write guest writes to both devices so that the job converges even with a busy guest.
+	args.CopyMode = "write-blocking"
+
+	// Keep the job around once it has concluded so that its result can be checked.
+	args.AutoDismiss = false
+
+	err := m.run("blockdev-mirror", args, nil)
+	if err != nil {
+		return err
+	}
+
+	err = m.jobWaitReady(ctx, args.JobID)
+	if err == nil {
+		err = m.BlockJobComplete(args.JobID)
+	}
+
+	if err != nil {
+		// Cancel the job and dismiss it once it has concluded.
+		cancelCtx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_ = m.jobWaitConcluded(cancelCtx, args.JobID)
+
+		return err
+	}
+
+	return m.jobWaitConcluded(ctx, args.JobID)
+}
+
+// BlockNodeExists returns whether a block node with the given name exists.
+func (m *Monitor) BlockNodeExists(nodeName string) (bool, error) {
+	var resp struct {
+		Return []struct {
+			NodeName string `json:"node-name"`
+		} `json:"return"`
+	}
+
+	args := map[string]any{"flat": true}
+
+	err := m.run("query-named-block-nodes", args, &resp)
+	if err != nil {
+		return false, err
+	}
+
+	for _, node := range resp.Return {
+		if node.NodeName == nodeName {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// BlockResize notifies the block node that the size of its backing device changed.
+func (m *Monitor) BlockResize(nodeName string, sizeBytes int64) error {
+	var args struct {
+		NodeName string `json:"node-name"`
+		Size     int64  `json:"size"`
+	}
+
+	args.NodeName = nodeName
+	args.Size = sizeBytes
+
+	return m.run("block_resize", args, nil)
+}
+
+// BlockNodeSize returns the virtual size in bytes of the block node with the given name.
+func (m *Monitor) BlockNodeSize(nodeName string) (int64, error) {
+	var resp struct {
+		Return []struct {
+			NodeName string `json:"node-name"`
+			Image    struct {
+				VirtualSize int64 `json:"virtual-size"`
+			} `json:"image"`
+		} `json:"return"`
+	}
+
+	args := map[string]any{"flat": true}
+
+	err := m.run("query-named-block-nodes", args, &resp)
+	if err != nil {
+		return -1, err
+	}
+
+	for _, node := range resp.Return {
+		if node.NodeName == nodeName {
+			return node.Image.VirtualSize, nil
+		}
+	}
+
+	return -1, fmt.Errorf("Block node %q not found", nodeName)
+}
+
+// BlockDirtyBitmapAdd adds a dirty bitmap that tracks the writes to the block node.
+// Each bit of the bitmap covers granularity bytes. Persistent bitmaps are stored in the image of the block node
+// when it is closed, which requires a format supporting it such as qcow2.
+func (m *Monitor) BlockDirtyBitmapAdd(nodeName string, bitmapName string, granularity int, persistent bool) error {
+	var args struct {
+		Node        string `json:"node"`
+		Name        string `json:"name"`
+		Granularity int    `json:"granularity"`
+		Persistent  bool   `json:"persistent"`
+	}
+
+	args.Node = nodeName
+	args.Name = bitmapName
+	args.Granularity = granularity
+	args.Persistent = persistent
+
+	return m.run("block-dirty-bitmap-add", args, nil)
+}
+
+// BlockDirtyBitmapMerge marks the bits set in a dirty bitmap of the source block node in a dirty bitmap of the
+// block node. Both bitmaps must cover the same size.
+func (m *Monitor) BlockDirtyBitmapMerge(nodeName string, bitmapName string, sourceNodeName string, sourceBitmapName string) error {
+	type bitmap struct {
+		Node string `json:"node"`
+		Name string `json:"name"`
+	}
+
+	var args struct {
+		Node    string   `json:"node"`
+		Target  string   `json:"target"`
+		Bitmaps []bitmap `json:"bitmaps"`
+	}
+
+	args.Node = nodeName
+	args.Target = bitmapName
+	args.Bitmaps = []bitmap{{Node: sourceNodeName, Name: sourceBitmapName}}
+
+	return m.run("block-dirty-bitmap-merge", args, nil)
+}
+
+// BlockDirtyBitmapClear clears a dirty bitmap of the block node.
+func (m *Monitor) BlockDirtyBitmapClear(nodeName string, bitmapName string) error {
+	var args struct {
+		Node string `json:"node"`
+		Name string `json:"name"`
+	}
+
+	args.Node = nodeName
+	args.Name = bitmapName
+
+	return m.run("block-dirty-bitmap-clear", args, nil)
+}
+
+// BlockDevBackupIncremental copies the blocks of the device marked in the dirty bitmap to the target device.
+// The copied blocks are cleared from the bitmap once the backup succeeds.
+// The backup job is cancelled if ctx is done before it has concluded.
+func (m *Monitor) BlockDevBackupIncremental(ctx context.Context, deviceNodeName string, targetNodeName string, bitmapName string) error {
+	var args struct {
+		Device      string `json:"device"`
+		Target      string `json:"target"`
+		Sync        string `json:"sync"`
+		JobID       string `json:"job-id"`
+		Bitmap      string `json:"bitmap"`
+		BitmapMode  string `json:"bitmap-mode"`
+		AutoDismiss bool   `json:"auto-dismiss"`
+	}
+
+	args.Device = deviceNodeName
+	args.Target = targetNodeName
+	args.Sync = "incremental"
+	args.JobID = targetNodeName
+	args.Bitmap = bitmapName
+	args.BitmapMode = "on-success"
+
+	// Keep the job around once it has concluded so that its result can be checked.
+	args.AutoDismiss = false
+
+	err := m.run("blockdev-backup", args, nil)
+	if err != nil {
+		return err
+	}
+
+	return m.jobWaitConcluded(ctx, args.JobID)
+}
+
+// jobWaitReady waits until the specified jobID is ready to be completed.
+// Jobs that concluded before being ready are left for the caller to dismiss.
+func (m *Monitor) jobWaitReady(ctx context.Context, jobID string) error {
+	for {
+		var resp struct {
+			Return []struct {
+				ID     string `json:"id"`
+				Status string `json:"status"`
+				Error  string `json:"error"`
+			} `json:"return"`
+		}
+
+		err := m.run("query-jobs", nil, &resp)
+		if err != nil {
+			return err
+		}
+
+		found := false
+		for _, job := range resp.Return {
+			if job.ID != jobID {
+				continue
+			}
+
+			switch job.Status {
+			case "ready":
+				return nil
+			case "concluded":
+				if job.Error != "" {
+					return fmt.Errorf("Failed job: %s", job.Error)
+				}
+
+				return errors.New("Job concluded before being ready")
+			}
+
+			found = true
+		}
+
+		if !found {
+			return errors.New("Specified job not found")
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Job cancelled: %w", ctx.Err())
+		case <-time.After(1 * time.Second):
+		}
+	}
+}
+
+// jobCancelTimeout is how long a cancelled job is given to conclude.
+const jobCancelTimeout = 10 * time.Second
+
+// jobWaitConcluded waits until the specified jobID has concluded and then dismisses it.
+// If ctx is done before then, the job is cancelled and waited for up to jobCancelTimeout.
+// Returns nil if the job succeeded, otherwise an error.
+func (m *Monitor) jobWaitConcluded(ctx context.Context, jobID string) error {
+	var cancelledAt time.Time
+
+	for {
+		var resp struct {
+			Return []struct {
+				ID     string `json:"id"`
+				Status string `json:"status"`
+				Error  string `json:"error"`
+			} `json:"return"`
+		}
+
+		err := m.run("query-jobs", nil, &resp)
+		if err != nil {
+			return err
+		}
+
+		found := false
+		for _, job := range resp.Return {
+			if job.ID != jobID {
+				continue
+			}
+
+			found = true
+			if job.Status != "concluded" {
+				break
+			}
+
+			err = m.run("job-dismiss", map[string]string{"id": jobID}, nil)
+			if err != nil {
+				return err
+			}
+
+			if !cancelledAt.IsZero() {
+				return fmt.Errorf("Job cancelled: %w", ctx.Err())
+			}
+
+			if job.Error != "" {
+				return fmt.Errorf("Failed job: %s", job.Error)
+			}
+
+			return nil
+		}
+
+		if !found {
+			return errors.New("Specified job not found")
+		}
+
+		if ctx.Err() != nil {
+			if cancelledAt.IsZero() {
+				err = m.run("job-cancel", map[string]string{"id": jobID}, nil)
+				if err != nil {
+					return err
+				}
+
+				cancelledAt = time.Now()
+			} else if time.Since(cancelledAt) > jobCancelTimeout {
+				return fmt.Errorf("Job %q didn't conclude after being cancelled: %w", jobID, ctx.Err())
+			}
+
+			time.Sleep(100 * time.Millisecond)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(1 * time.Second):
+		}
+	}
+}
+
 // BlockJobCancel cancels an ongoing block job.
 func (m *Monitor) BlockJobCancel(deviceNodeName string) error {
 	var args struct {
@@ -0,0 +1,412 @@
+package qmp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// mockCommand is a command received by the mock monitor.
+type mockCommand struct {
+	Execute   string         `json:"execute"`
+	Arguments map[string]any `json:"arguments"`
+}
+
+// mockMonitor returns a monitor whose commands are answered by handler.
+// The commands received by the mock monitor are returned by the second return value.
+func mockMonitor(t *testing.T, handler func(cmd mockCommand) (any, error)) (*Monitor, func() []mockCommand) {
+	t.Helper()
+
+	var received []mockCommand
+	var receivedLock sync.Mutex
+
+	eg := &errgroup.Group{}
+	p := &qemuMachineProtocol{}
+	mockMonitorServer(t, eg, p, func(nc net.Conn) error {
+		dec := json.NewDecoder(nc)
+		enc := json.NewEncoder(nc)
+
+		for {
+			var req struct {
+				mockCommand
+
+				ID uint32 `json:"id"`
+			}
+
+			err := dec.Decode(&req)
+			if err != nil {
+				// The client disconnected.
+				return nil
+			}
+
+			receivedLock.Lock()
+			received = append(received, req.mockCommand)
+			receivedLock.Unlock()
+
+			resp := map[string]any{"id": req.ID}
+
+			ret, err := handler(req.mockCommand)
+			if err != nil {
+				resp["error"] = qmpError{Class: "GenericError", Desc: err.Error()}
+			} else {
+				if ret == nil {
+					ret = map[string]any{}
+				}
+
+				resp["return"] = ret
+			}
+
+			err = enc.Encode(resp)
+			if err != nil {
+				return err
+			}
+		}
+	})
+
+	err := p.connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = p.disconnect()
+		_ = eg.Wait()
+	})
+
+	return &Monitor{qmp: p}, func() []mockCommand {
+		receivedLock.Lock()
+		defer receivedLock.Unlock()
+
+		return append([]mockCommand(nil), received...)
+	}
+}
+
+// mockCommandNames returns the names of the commands.
+func mockCommandNames(cmds []mockCommand) []string {
+	names := make([]string, 0, len(cmds))
+	for _, cmd := range cmds {
+		names = append(names, cmd.Execute)
+	}
+
+	return names
+}
+
+func TestBlockNodeExists(t *testing.T) {
+	m, _ := mockMonitor(t, func(cmd mockCommand) (any, error) {
+		if cmd.Execute != "query-named-block-nodes" {
+			return nil, errors.New("Unexpected command")
+		}
+
+		return []map[string]any{{"node-name": "lxd_root"}, {"node-name": "lxd_vol1"}}, nil
+	})
+
+	for nodeName, expected := range map[string]bool{"lxd_vol1": true, "lxd_vol2": false} {
+		exists, err := m.BlockNodeExists(nodeName)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if exists != expected {
+			t.Fatalf("%s: expected %v, got %v", nodeName, expected, exists)
+		}
+	}
+}
+
+func TestBlockResize(t *testing.T) {
+	m, received := mockMonitor(t, func(cmd mockCommand) (any, error) {
+		return nil, nil
+	})
+
+	err := m.BlockResize("lxd_vol1", 10*1024*1024*1024)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []mockCommand{{Execute: "block_resize", Arguments: map[string]any{"node-name": "lxd_vol1", "size": float64(10 * 1024 * 1024 * 1024)}}}
+	got := received()
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected commands:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+func TestBlockDirtyBitmapAdd(t *testing.T) {
+	m, received := mockMonitor(t, func(cmd mockCommand) (any, error) {
+		return nil, nil
+	})
+
+	err := m.BlockDirtyBitmapAdd("lxd_store", "lxd-changed-blocks", 65536, true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []mockCommand{{Execute: "block-dirty-bitmap-add", Arguments: map[string]any{"node": "lxd_store", "name": "lxd-changed-blocks", "granularity": float64(65536), "persistent": true}}}
+	got := received()
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected commands:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+func TestBlockDirtyBitmapMerge(t *testing.T) {
+	m, received := mockMonitor(t, func(cmd mockCommand) (any, error) {
+		return nil, nil
+	})
+
+	err := m.BlockDirtyBitmapMerge("lxd_store", "lxd-changed-blocks", "lxd_root", "lxd-changed-blocks")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []mockCommand{{Execute: "block-dirty-bitmap-merge", Arguments: map[string]any{
+		"node":    "lxd_store",
+		"target":  "lxd-changed-blocks",
+		"bitmaps": []any{map[string]any{"node": "lxd_root", "name": "lxd-changed-blocks"}},
+	}}}
+
+	got := received()
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected commands:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+func TestBlockDevMirrorFull(t *testing.T) {
+	completed := false
+
+	m, received := mockMonitor(t, func(cmd mockCommand) (any, error) {
+		switch cmd.Execute {
+		case "blockdev-mirror":
+			if cmd.Arguments["sync"] != "full" || cmd.Arguments["copy-mode"] != "write-blocking" || cmd.Arguments["auto-dismiss"] != false {
+				return nil, errors.New("Unexpected mirror arguments")
+			}
+
+			return nil, nil
+		case "query-jobs":
+			if completed {
+				return []map[string]any{{"id": "lxd_vol1", "status": "concluded"}}, nil
+			}
+
+			return []map[string]any{{"id": "lxd_vol1", "status": "ready"}}, nil
+		case "block-job-complete":
+			completed = true
+			return nil, nil
+		case "job-dismiss":
+			return nil, nil
+		}
+
+		return nil, errors.New("Unexpected command")
+	})
+
+	err := m.BlockDevMirrorFull(context.Background(), "lxd_vol1", "lxd_vol1_new")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []string{"blockdev-mirror", "query-jobs", "block-job-complete", "query-jobs", "job-dismiss"}
+	got := mockCommandNames(received())
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected commands:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+func TestBlockDevMirrorFullFailed(t *testing.T) {
+	m, received := mockMonitor(t, func(cmd mockCommand) (any, error) {
+		if cmd.Execute == "query-jobs" {
+			return []map[string]any{{"id": "lxd_vol1", "status": "concluded", "error": "No space left on device"}}, nil
+		}
+
+		return nil, nil
+	})
+
+	err := m.BlockDevMirrorFull(context.Background(), "lxd_vol1", "lxd_vol1_new")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+
+	// The failed job is dismissed.
+	want := []string{"blockdev-mirror", "query-jobs", "query-jobs", "job-dismiss"}
+	got := mockCommandNames(received())
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected commands:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+func TestBlockDevMirrorFullFailedAfterComplete(t *testing.T) {
+	completed := false
+
+	m, received := mockMonitor(t, func(cmd mockCommand) (any, error) {
+		switch cmd.Execute {
+		case "query-jobs":
+			if completed {
+				return []map[string]any{{"id": "lxd_vol1", "status": "concluded", "error": "Input/output error"}}, nil
+			}
+
+			return []map[string]any{{"id": "lxd_vol1", "status": "ready"}}, nil
+		case "block-job-complete":
+			completed = true
+		}
+
+		return nil, nil
+	})
+
+	// A job that fails while switching over isn't reported as a success even though it is no longer running.
+	err := m.BlockDevMirrorFull(context.Background(), "lxd_vol1", "lxd_vol1_new")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+
+	want := []string{"blockdev-mirror", "query-jobs", "block-job-complete", "query-jobs", "job-dismiss"}
+	got := mockCommandNames(received())
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected commands:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+func TestBlockDevMirrorFullCancelled(t *testing.T) {
+	cancelled := false
+
+	m, received := mockMonitor(t, func(cmd mockCommand) (any, error) {
+		switch cmd.Execute {
+		case "query-jobs":
+			if cancelled {
+				return []map[string]any{{"id": "lxd_vol1", "status": "concluded", "error": "Operation cancelled"}}, nil
+			}
+
+			return []map[string]any{{"id": "lxd_vol1", "status": "running"}}, nil
+		case "job-cancel":
+			cancelled = true
+		}
+
+		return nil, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := m.BlockDevMirrorFull(ctx, "lxd_vol1", "lxd_vol1_new")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected a cancellation error, got: %v", err)
+	}
+
+	// The job is cancelled and dismissed once it has concluded.
+	want := []string{"blockdev-mirror", "query-jobs", "query-jobs", "job-cancel", "query-jobs", "job-dismiss"}
+	got := mockCommandNames(received())
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected commands:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+func TestBlockDevBackupIncremental(t *testing.T) {
+	queries := 0
+
+	m, received := mockMonitor(t, func(cmd mockCommand) (any, error) {
+		switch cmd.Execute {
+		case "blockdev-backup":
+			if cmd.Arguments["sync"] != "incremental" || cmd.Arguments["bitmap"] != "lxd-bitmap" || cmd.Arguments["auto-dismiss"] != false {
+				return nil, errors.New("Unexpected backup arguments")
+			}
+
+			return nil, nil
+		case "query-jobs":
+			queries++
+			if queries == 1 {
+				return []map[string]any{{"id": "lxd_target", "status": "running"}}, nil
+			}
+
+			return []map[string]any{{"id": "lxd_target", "status": "concluded"}}, nil
+		case "job-dismiss":
+			return nil, nil
+		}
+
+		return nil, errors.New("Unexpected command")
+	})
+
+	err := m.BlockDevBackupIncremental(context.Background(), "lxd_root", "lxd_target", "lxd-bitmap")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []string{"blockdev-backup", "query-jobs", "query-jobs", "job-dismiss"}
+	got := mockCommandNames(received())
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected commands:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+func TestBlockDevBackupIncrementalFailed(t *testing.T) {
+	m, received := mockMonitor(t, func(cmd mockCommand) (any, error) {
+		if cmd.Execute == "query-jobs" {
+			return []map[string]any{{"id": "lxd_target", "status": "concluded", "error": "No space left on device"}}, nil
+		}
+
+		return nil, nil
+	})
+
+	err := m.BlockDevBackupIncremental(context.Background(), "lxd_root", "lxd_target", "lxd-bitmap")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+
+	// The failed job is still dismissed.
+	want := []string{"blockdev-backup", "query-jobs", "job-dismiss"}
+	got := mockCommandNames(received())
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected commands:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+func TestBlockDevBackupIncrementalNotFound(t *testing.T) {
+	m, _ := mockMonitor(t, func(cmd mockCommand) (any, error) {
+		if cmd.Execute == "query-jobs" {
+			return []map[string]any{{"id": "other", "status": "running"}}, nil
+		}
+
+		return nil, nil
+	})
+
+	err := m.BlockDevBackupIncremental(context.Background(), "lxd_root", "lxd_target", "lxd-bitmap")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+}
+
+func TestBlockDevBackupIncrementalCancelled(t *testing.T) {
+	cancelled := false
+
+	m, received := mockMonitor(t, func(cmd mockCommand) (any, error) {
+		switch cmd.Execute {
+		case "query-jobs":
+			if cancelled {
+				return []map[string]any{{"id": "lxd_target", "status": "concluded", "error": "Operation cancelled"}}, nil
+			}
+
+			return []map[string]any{{"id": "lxd_target", "status": "running"}}, nil
+		case "job-cancel":
+			if cmd.Arguments["id"] != "lxd_target" {
+				return nil, errors.New("Unexpected job")
+			}
+
+			cancelled = true
+		}
+
+		return nil, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := m.BlockDevBackupIncremental(ctx, "lxd_root", "lxd_target", "lxd-bitmap")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected a cancellation error, got: %v", err)
+	}
+
+	want := []string{"blockdev-backup", "query-jobs", "job-cancel", "query-jobs", "job-dismiss"}
+	got := mockCommandNames(received())
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected commands:\n- want: %v\n-  got: %v", want, got)
+	}
+}
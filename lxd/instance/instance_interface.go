@@ -23,6 +23,7 @@ import (
 	"github.com/canonical/lxd/lxd/operations"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/ioprogress"
+	"github.com/canonical/lxd/shared/revert"
 )
 
 // HookStart hook used when instance has started.
@@ -41,6 +42,7 @@ const HookStop = "onstop"
 const (
 	ConsoleTypeConsole = "console"
 	ConsoleTypeVGA     = "vga"
+	ConsoleTypeVNC     = "vnc"
 )
 
 // TemplateTrigger trigger name.
@@ -150,6 +152,7 @@ type Instance interface {
 	// Paths.
 	Path() string
 	ExecOutputPath() string
+	ConsoleRecordingPath() string
 	RootfsPath() string
 	TemplatesPath() string
 	StatePath() string
@@ -202,6 +205,15 @@ type VM interface {
 	// UEFI vars handling.
 	UEFIVars() (*api.InstanceUEFIVars, error)
 	UEFIVarsUpdate(newUEFIVarsSet api.InstanceUEFIVars) error
+
+	// Live disk device handling.
+	DiskDeviceMirror(deviceName string, targetPath string, poolName string, source string, cleanup revert.Hook) error
+	DiskDeviceResize(deviceName string, sizeBytes int64) error
+	RootDiskMirror(targetPath string, poolName string, cleanup revert.Hook) error
+
+	// Changed block tracking.
+	ChangedBlocksCheckpoint(name string, backup func() error) error
+	ChangedBlocksBackup(parent string, name string, targetPath string) error
 }
 
 // CriuMigrationArgs arguments for CRIU migration.
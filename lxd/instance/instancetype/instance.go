@@ -114,6 +114,46 @@ var HugePageSizeSuffix = [...]string{"64KB", "1MB", "2MB", "1GB"}
 
 // InstanceConfigKeysAny is a map of config key to validator. (keys applying to containers AND virtual machines).
 var InstanceConfigKeysAny = map[string]func(value string) error{
+	// lxdmeta:generate(entities=instance; group=backups; key=backups.schedule)
+	// Specify either a cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or leave empty to disable automatic backups.
+	//
+	// ---
+	//  type: string
+	//  defaultdesc: empty
+	//  liveupdate: no
+	//  shortdesc: Schedule for automatic instance backups
+	"backups.schedule": validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly"})),
+
+	// lxdmeta:generate(entities=instance; group=backups; key=backups.expiry)
+	// Specify an expression like `1M 2H 3d 4w 5m 6y`.
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  shortdesc: When scheduled backups are to be deleted
+	"backups.expiry": func(value string) error {
+		// Validate expression
+		_, err := shared.GetExpiry(time.Time{}, value)
+		return err
+	},
+
+	// lxdmeta:generate(entities=instance; group=backups; key=backups.retain)
+	// When a scheduled backup is created, the oldest scheduled backups are deleted so that at most this number of them is kept.
+	// ---
+	//  type: integer
+	//  defaultdesc: unlimited
+	//  liveupdate: no
+	//  shortdesc: Maximum number of scheduled backups to keep
+	"backups.retain": validate.Optional(validate.IsInRange(1, 2147483647)),
+
+	// lxdmeta:generate(entities=instance; group=backups; key=backups.optimized)
+	// Only supported by storage pool drivers that support optimized backups.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: no
+	//  shortdesc: Whether scheduled backups use the storage driver's optimized format
+	"backups.optimized": validate.Optional(validate.IsBool),
+
 	// lxdmeta:generate(entities=instance; group=boot; key=boot.autostart)
 	// If set to `true`, the instance will always be auto-started, unless `security.protection.start` is also enabled.
 	// If set to `false`, the instance will not be started on LXD start up.
@@ -236,6 +276,29 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: What to do when evacuating the instance
 	"cluster.evacuate": validate.Optional(validate.IsOneOf(api.ClusterEvacuateModeAuto, api.ClusterEvacuateModeMigrate, api.ClusterEvacuateModeLiveMigrate, api.ClusterEvacuateModeStop)),
 
+	// lxdmeta:generate(entities=instance; group=miscellaneous; key=console.recording)
+	// When enabled, the output of interactive text console sessions is recorded in the asciicast v2 format.
+	//
+	// See {ref}`instances-console-recording` for more information.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: yes
+	//  shortdesc: Whether to record text console sessions
+	"console.recording": validate.Optional(validate.IsBool),
+
+	// lxdmeta:generate(entities=instance; group=miscellaneous; key=console.recording.input)
+	// When enabled, the input typed into recorded text console sessions is recorded as well.
+	// Note that the input can contain passwords.
+	//
+	// See {ref}`instances-console-recording` for more information.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: yes
+	//  shortdesc: Whether to record the input of text console sessions
+	"console.recording.input": validate.Optional(validate.IsBool),
+
 	// lxdmeta:generate(entities=instance; group=resource-limits; key=limits.cpu)
 	// A number or a specific range of CPUs to expose to the instance.
 	//
@@ -490,6 +553,14 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	"volatile.last_state.power": validate.IsAny,
 	"volatile.last_state.ready": validate.IsBool,
 	"volatile.apply_quota":      validate.IsAny,
+
+	// lxdmeta:generate(entities=instance; group=volatile; key=volatile.replica.source)
+	// Set on instances created by storage pool replication. Replicas can't be started until they are promoted.
+	// ---
+	//  type: string
+	//  shortdesc: The server and project of the replicated source instance
+	"volatile.replica.source": validate.IsAny,
+
 	// lxdmeta:generate(entities=instance; group=volatile; key=volatile.uuid)
 	// The instance UUID is globally unique across all servers and projects.
 	// ---
@@ -1067,6 +1138,20 @@ var InstanceConfigKeysContainer = map[string]func(value string) error{
 
 // InstanceConfigKeysVM is a map of config key to validator. (keys applying to VM only).
 var InstanceConfigKeysVM = map[string]func(value string) error{
+	// lxdmeta:generate(entities=instance; group=backups; key=backups.changed_block_tracking)
+	// When enabled, LXD tracks the blocks that change on the root disk while the VM is running.
+	// This allows incremental backups of VMs on storage pools that don't support optimized incremental backups.
+	//
+	// The changed blocks are kept when the VM is stopped, but the next backup must be a full backup if the VM crashes,
+	// is live migrated or is restored from a snapshot.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: no
+	//  condition: virtual machine
+	//  shortdesc: Whether to track changed blocks of the root disk for incremental backups
+	"backups.changed_block_tracking": validate.Optional(validate.IsBool),
+
 	// lxdmeta:generate(entities=instance; group=resource-limits; key=limits.memory.hugepages)
 	// If this option is set to `false`, regular system memory is used.
 	// ---
@@ -1077,6 +1162,20 @@ var InstanceConfigKeysVM = map[string]func(value string) error{
 	//  shortdesc: Whether to back the instance using huge pages
 	"limits.memory.hugepages": validate.Optional(validate.IsBool),
 
+	// lxdmeta:generate(entities=instance; group=resource-limits; key=limits.memory.hotplug)
+	// When set, LXD reserves address space for this amount of memory when the VM starts.
+	// Increasing {config:option}`instance-resource-limits:limits.memory` while the VM is running then hot-adds memory up to this size.
+	// This option cannot be combined with {config:option}`instance-resource-limits:limits.memory.hugepages` or {config:option}`instance-migration:migration.stateful`.
+	//
+	// See {ref}`instance-options-limits-memory-vm` for more information.
+	// ---
+	//  type: string
+	//  defaultdesc: empty
+	//  liveupdate: no
+	//  condition: virtual machine
+	//  shortdesc: Maximum memory size that a running VM can be grown to
+	"limits.memory.hotplug": validate.Optional(validate.IsSize),
+
 	// lxdmeta:generate(entities=instance; group=resource-limits; key=limits.cpu.pin_strategy)
 	// Specify the strategy for VM CPU auto pinning.
 	// Possible values: `none` (disables CPU auto pinning) and `auto` (enables CPU auto pinning).
@@ -1214,6 +1313,13 @@ var InstanceConfigKeysVM = map[string]func(value string) error{
 	//  shortdesc: Whether to regenerate VM NVRAM the next time the instance starts
 	"volatile.apply_nvram": validate.Optional(validate.IsBool),
 
+	// lxdmeta:generate(entities=instance; group=volatile; key=volatile.backup.checkpoint)
+	//
+	// ---
+	//  type: string
+	//  shortdesc: Backup that the changed block tracking of the root disk is relative to
+	"volatile.backup.checkpoint": validate.IsAny,
+
 	// lxdmeta:generate(entities=instance; group=volatile; key=volatile.bus.mode)
 	// Set to `persistent` when persistent bus allocation mode is enabled.
 	// ---
@@ -1221,6 +1327,14 @@ var InstanceConfigKeysVM = map[string]func(value string) error{
 	//  shortdesc: Device bus allocation mode
 	"volatile.bus.mode": validate.Optional(validate.IsOneOf("persistent")),
 
+	// lxdmeta:generate(entities=instance; group=volatile; key=volatile.move.source_pool)
+	// Set when the root disk of the running VM was moved to another storage pool.
+	// The root volume on this pool is deleted when the VM stops.
+	// ---
+	//  type: string
+	//  shortdesc: Storage pool of the root volume the VM was started from
+	"volatile.move.source_pool": validate.IsAny,
+
 	// lxdmeta:generate(entities=instance; group=volatile; key=volatile.vsock_id)
 	//
 	// ---
@@ -1444,6 +1558,23 @@ func ConfigKeyChecker(key string, instanceType Type) (func(value string) error,
 		return validate.IsAny, nil
 	}
 
+	// lxdmeta:generate(entities=instance; group=miscellaneous; key=user.dns.srv.<service>)
+	// Publishes `SRV` records for the service in the forward network zones the instance has address records in,
+	// while the instance is running.
+	// Specify a comma-separated list of `<port>/<protocol>` entries, where the protocol is `tcp`, `udp` or `sctp`.
+	// See {ref}`network-zones-service-records` for more information.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Service published in the network zones
+	service, found := strings.CutPrefix(key, "user.dns.srv.")
+	if found {
+		return func(value string) error {
+			_, err := ParseServiceEndpoints(service, value)
+			return err
+		}, nil
+	}
+
 	knownPrefixes := append(ConfigKeyPrefixesAny, ConfigKeyPrefixesContainer...)
 	if shared.StringHasPrefix(key, knownPrefixes...) {
 		return validate.IsAny, nil
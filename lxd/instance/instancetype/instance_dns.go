@@ -0,0 +1,50 @@
+package instancetype
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/canonical/lxd/shared"
+)
+
+// ServiceEndpoint represents a port and protocol an instance provides a service on.
+type ServiceEndpoint struct {
+	Port     uint64
+	Protocol string
+}
+
+// ParseServiceEndpoints parses a comma separated list of service endpoints in the `<port>/<protocol>` format.
+func ParseServiceEndpoints(service string, value string) ([]ServiceEndpoint, error) {
+	if service == "" || len(service) > 63 || strings.HasPrefix(service, "-") || strings.HasSuffix(service, "-") {
+		return nil, fmt.Errorf("Invalid service name %q", service)
+	}
+
+	for _, r := range service {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '-' {
+			return nil, fmt.Errorf("Invalid service name %q", service)
+		}
+	}
+
+	endpoints := []ServiceEndpoint{}
+	for _, entry := range shared.SplitNTrimSpace(value, ",", -1, true) {
+		portStr, protocol, found := strings.Cut(entry, "/")
+		if !found {
+			return nil, fmt.Errorf("Invalid service endpoint %q, must be in the form <port>/<protocol>", entry)
+		}
+
+		port, err := strconv.ParseUint(portStr, 10, 16)
+		if err != nil || port == 0 {
+			return nil, fmt.Errorf("Invalid port in service endpoint %q", entry)
+		}
+
+		if !slices.Contains([]string{"tcp", "udp", "sctp"}, protocol) {
+			return nil, fmt.Errorf("Invalid protocol in service endpoint %q, must be one of tcp, udp or sctp", entry)
+		}
+
+		endpoints = append(endpoints, ServiceEndpoint{Port: port, Protocol: protocol})
+	}
+
+	return endpoints, nil
+}
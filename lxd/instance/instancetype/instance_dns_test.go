@@ -0,0 +1,97 @@
+package instancetype
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseServiceEndpoints(t *testing.T) {
+	tests := []struct {
+		name    string
+		service string
+		value   string
+		want    []ServiceEndpoint
+		wantErr string
+	}{
+		{
+			name:    "Single endpoint",
+			service: "http",
+			value:   "80/tcp",
+			want:    []ServiceEndpoint{{Port: 80, Protocol: "tcp"}},
+		},
+		{
+			name:    "Multiple endpoints",
+			service: "dns",
+			value:   "53/udp, 53/tcp,5353/sctp",
+			want:    []ServiceEndpoint{{Port: 53, Protocol: "udp"}, {Port: 53, Protocol: "tcp"}, {Port: 5353, Protocol: "sctp"}},
+		},
+		{
+			name:    "Empty value",
+			service: "http",
+			value:   "",
+			want:    []ServiceEndpoint{},
+		},
+		{
+			name:    "Empty service name",
+			service: "",
+			value:   "80/tcp",
+			wantErr: `Invalid service name ""`,
+		},
+		{
+			name:    "Service name with a leading hyphen",
+			service: "-http",
+			value:   "80/tcp",
+			wantErr: `Invalid service name "-http"`,
+		},
+		{
+			name:    "Service name with an invalid character",
+			service: "http.alt",
+			value:   "80/tcp",
+			wantErr: `Invalid service name "http.alt"`,
+		},
+		{
+			name:    "Missing protocol",
+			service: "http",
+			value:   "80",
+			wantErr: `Invalid service endpoint "80", must be in the form <port>/<protocol>`,
+		},
+		{
+			name:    "Invalid port",
+			service: "http",
+			value:   "http/tcp",
+			wantErr: `Invalid port in service endpoint "http/tcp"`,
+		},
+		{
+			name:    "Port out of range",
+			service: "http",
+			value:   "65536/tcp",
+			wantErr: `Invalid port in service endpoint "65536/tcp"`,
+		},
+		{
+			name:    "Port zero",
+			service: "http",
+			value:   "0/tcp",
+			wantErr: `Invalid port in service endpoint "0/tcp"`,
+		},
+		{
+			name:    "Invalid protocol",
+			service: "http",
+			value:   "80/icmp",
+			wantErr: `Invalid protocol in service endpoint "80/icmp"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseServiceEndpoints(tt.service, tt.value)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -296,37 +297,25 @@ func instanceBackupsPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
-	if req.Name == "" {
+	if req.Name == "" && req.Target != "" {
+		// Backups on a backup target aren't recorded in the database, so base the name on the stored objects.
+		req.Name, err = backupTargetNextName(r.Context(), s, req.Target, name, projectName, "instances", name)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	} else if req.Name == "" {
 		// come up with a name.
 		backups, err := inst.Backups()
 		if err != nil {
 			return response.BadRequest(err)
 		}
 
-		base := name + shared.SnapshotDelimiter + "backup"
-		length := len(base)
-		backupNo := 0
-
-		// Iterate over previous backups to autoincrement the backup number.
-		for _, backup := range backups {
-			// Ignore backups not containing base.
-			if !strings.HasPrefix(backup.Name(), base) {
-				continue
-			}
-
-			substr := backup.Name()[length:]
-			var num int
-			count, err := fmt.Sscanf(substr, "%d", &num)
-			if err != nil || count != 1 {
-				continue
-			}
-
-			if num >= backupNo {
-				backupNo = num + 1
-			}
+		backupNames := make([]string, 0, len(backups))
+		for _, b := range backups {
+			backupNames = append(backupNames, b.Name())
 		}
 
-		req.Name = fmt.Sprintf("backup%d", backupNo)
+		req.Name = backup.NextBackupName(name, "backup", backupNames)
 	}
 
 	// In case no version was selected for the backup format use the globally set format by default.
@@ -348,6 +337,35 @@ func instanceBackupsPost(d *Daemon, r *http.Request) response.Response {
 	// We keep the req.ContainerOnly for backward compatibility.
 	instanceOnly := req.InstanceOnly || req.ContainerOnly //nolint:staticcheck,unused
 
+	enc, err := backupEncryption(req.EncryptionRecipients, req.EncryptionPassphrase)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var parent *backupParent
+	if req.Parent != "" {
+		if enc != nil {
+			return response.BadRequest(errors.New("Incremental backups cannot be encrypted"))
+		}
+
+		if req.Target != "" {
+			return response.BadRequest(errors.New("Incremental backups cannot be pushed to a backup target"))
+		}
+
+		parent, err = backupLoadParent(s, inst, req.Parent)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		if parent.changedBlocks {
+			if req.OptimizedStorage || !instanceOnly {
+				return response.BadRequest(errors.New("Incremental backups based on changed block tracking must not be optimized or include snapshots"))
+			}
+		} else if !req.OptimizedStorage || instanceOnly {
+			return response.BadRequest(errors.New("Incremental backups must be optimized and include snapshots"))
+		}
+	}
+
 	backup := func(op *operations.Operation) error {
 		args := db.InstanceBackup{
 			Name:                 fullName,
@@ -359,7 +377,21 @@ func instanceBackupsPost(d *Daemon, r *http.Request) response.Response {
 			CompressionAlgorithm: req.CompressionAlgorithm,
 		}
 
-		err := backupCreate(s, args, inst, req.Version, op)
+		if req.Target != "" {
+			target, err := backupTargetLoad(s.ShutdownCtx, s, req.Target)
+			if err != nil {
+				return err
+			}
+
+			key, err := backupCreateOnTarget(s, target, args, inst, req.Version, enc, op)
+			if err != nil {
+				return fmt.Errorf("Create backup: %w", err)
+			}
+
+			return op.ExtendMetadata(map[string]any{"backup_target": target.Name, "backup_object": key})
+		}
+
+		err := backupCreate(s, args, inst, req.Version, parent, enc, op)
 		if err != nil {
 			return fmt.Errorf("Create backup: %w", err)
 		}
@@ -374,7 +406,10 @@ func instanceBackupsPost(d *Daemon, r *http.Request) response.Response {
 		resources["containers"] = resources["instances"]
 	}
 
-	resources["backups"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", name, "backups", backupName)}
+	// Backups pushed to a backup target aren't stored on the server.
+	if req.Target == "" {
+		resources["backups"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", name, "backups", backupName)}
+	}
 
 	op, err := operations.OperationCreate(r.Context(), s, projectName, operations.OperationClassTask,
 		operationtype.BackupCreate, resources, nil, backup, nil, nil)
@@ -737,3 +772,120 @@ func instanceBackupExportGet(d *Daemon, r *http.Request) response.Response {
 
 	return response.FileResponse([]response.FileResponseEntry{ent}, nil)
 }
+
+// swagger:operation GET /1.0/instances/{name}/backups/{backup}/info instances instance_backup_info_get
+//
+//	Get the backup contents
+//
+//	Verifies the integrity of the backup file and returns information about its contents.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: Backup information
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/BackupInfo"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceBackupInfoGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	instanceType, err := urlInstanceTypeDetect(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	projectName := request.ProjectParam(r)
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if shared.IsSnapshot(name) {
+		return response.BadRequest(errors.New("Invalid instance name"))
+	}
+
+	backupName, err := url.PathUnescape(mux.Vars(r)["backupName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Handle requests targeted to a container on a different node
+	resp, err := forwardedResponseIfInstanceIsRemote(r.Context(), s, projectName, name, instanceType)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if resp != nil {
+		return resp
+	}
+
+	fullName := name + shared.SnapshotDelimiter + backupName
+	b, err := instance.BackupLoadByName(s, projectName, fullName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	backupPath := filepath.Join(s.BackupsStoragePath(projectName), "instances", project.Instance(projectName, b.Name()))
+	backupFile, err := os.Open(backupPath)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	defer func() { _ = backupFile.Close() }()
+
+	// The server doesn't keep the keys of encrypted backups.
+	encrypted, err := backup.IsEncrypted(backupFile)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if encrypted {
+		return response.BadRequest(fmt.Errorf("Backup %q is encrypted", backupName))
+	}
+
+	verified, err := backup.Verify(s, backupFile, backupPath)
+	if err != nil {
+		return response.InternalError(fmt.Errorf("Backup verification failed: %w", err))
+	}
+
+	bInfo, err := backup.GetInfo(s, backupFile, backupPath)
+	if err != nil {
+		return response.InternalError(fmt.Errorf("Failed reading backup: %w", err))
+	}
+
+	// Backups created before the backup name was recorded in the index.
+	if bInfo.Backup == "" {
+		bInfo.Backup = backupName
+	}
+
+	return response.SyncResponse(true, bInfo.ToAPI(verified))
+}
@@ -62,9 +62,12 @@ type consoleWs struct {
 	// terminal height
 	height int
 
-	// channel type (either console or vga)
+	// channel type (console, vga or vnc)
 	protocol string
 
+	// read-only attach to the active console session
+	observer bool
+
 	// track either server or client disconnected
 	consoleDone cancel.Canceller
 }
@@ -93,7 +96,7 @@ func (s *consoleWs) Connect(op *operations.Operation, r *http.Request, w http.Re
 	switch s.protocol {
 	case instance.ConsoleTypeConsole:
 		return s.connectConsole(r, w)
-	case instance.ConsoleTypeVGA:
+	case instance.ConsoleTypeVGA, instance.ConsoleTypeVNC:
 		return s.connectVGA(r, w)
 	default:
 		return fmt.Errorf("Unknown protocol %q", s.protocol)
@@ -143,18 +146,30 @@ func (s *consoleWs) connectConsole(r *http.Request, w http.ResponseWriter) error
 	return os.ErrPermission
 }
 
+// consoleWebsocketResponseHeader returns the header for upgrading the websocket request of the console protocol.
+// Browser based VNC clients such as noVNC request the "binary" subprotocol and fail if it isn't selected.
+func consoleWebsocketResponseHeader(protocol string, r *http.Request) http.Header {
+	if protocol != instance.ConsoleTypeVNC || !slices.Contains(websocket.Subprotocols(r), "binary") {
+		return nil
+	}
+
+	return http.Header{"Sec-Websocket-Protocol": []string{"binary"}}
+}
+
 func (s *consoleWs) connectVGA(r *http.Request, w http.ResponseWriter) error {
 	secret := r.FormValue("secret")
 	if secret == "" {
 		return errors.New("missing secret")
 	}
 
+	responseHeader := consoleWebsocketResponseHeader(s.protocol, r)
+
 	for fd, fdSecret := range s.fds {
 		if secret != fdSecret {
 			continue
 		}
 
-		conn, err := ws.Upgrader.Upgrade(w, r, nil)
+		conn, err := ws.Upgrader.Upgrade(w, r, responseHeader)
 		if err != nil {
 			return err
 		}
@@ -174,7 +189,7 @@ func (s *consoleWs) connectVGA(r *http.Request, w http.ResponseWriter) error {
 
 		logger.Debug("VGA dynamic websocket connected")
 
-		console, _, err := s.instance.Console("vga")
+		console, _, err := s.instance.Console(s.protocol)
 		if err != nil {
 			_ = conn.Close()
 			return err
@@ -216,21 +231,33 @@ func (s *consoleWs) connectVGA(r *http.Request, w http.ResponseWriter) error {
 }
 
 // Do connects to the websocket and executes the operation.
-func (s *consoleWs) Do(_ *operations.Operation) error {
+func (s *consoleWs) Do(op *operations.Operation) error {
 	switch s.protocol {
 	case instance.ConsoleTypeConsole:
-		return s.doConsole()
-	case instance.ConsoleTypeVGA:
+		if s.observer {
+			return s.doConsoleObserve()
+		}
+
+		return s.doConsole(op)
+	case instance.ConsoleTypeVGA, instance.ConsoleTypeVNC:
 		return s.doVGA()
 	default:
 		return fmt.Errorf("Unknown protocol %q", s.protocol)
 	}
 }
 
-func (s *consoleWs) doConsole() error {
+func (s *consoleWs) doConsole(op *operations.Operation) error {
 	defer logger.Debug("Console websocket finished")
 	<-s.allConnected
 
+	// Register the session so that it can be recorded and observed.
+	session, err := consoleSessionStart(s.instance, "console_"+op.ID()+".cast", s.width, s.height)
+	if err != nil {
+		return err
+	}
+
+	defer session.End()
+
 	// Get console from instance.
 	console, consoleDisconnectCh, err := s.instance.Console(s.protocol)
 	if err != nil {
@@ -298,6 +325,7 @@ func (s *consoleWs) doConsole() error {
 				}
 
 				logger.Debugf("Set window size to: %dx%d", winchWidth, winchHeight)
+				session.Resize(winchWidth, winchHeight)
 			}
 		}
 	}()
@@ -313,7 +341,8 @@ func (s *consoleWs) doConsole() error {
 		defer l.Debug("Finished mirroring websocket to console")
 
 		l.Debug("Started mirroring websocket")
-		readDone, writeDone := ws.Mirror(conn, console)
+		readDone := ws.MirrorRead(conn, io.TeeReader(console, session))
+		writeDone := ws.MirrorWrite(conn, io.MultiWriter(console, session.Input()))
 
 		<-readDone
 		l.Debug("Finished mirroring console to websocket")
@@ -360,6 +389,70 @@ func (s *consoleWs) doConsole() error {
 	return nil
 }
 
+func (s *consoleWs) doConsoleObserve() error {
+	defer logger.Debug("Console observer websocket finished")
+	<-s.allConnected
+
+	s.connsLock.Lock()
+	consoleConn := s.conns[0]
+	s.connsLock.Unlock()
+
+	defer func() {
+		s.connsLock.Lock()
+		ctrlConn := s.conns[-1]
+		s.connsLock.Unlock()
+
+		_ = consoleConn.Close()
+
+		if ctrlConn != nil {
+			_ = ctrlConn.Close()
+		}
+	}()
+
+	session := consoleSessionGet(s.instance)
+	if session == nil {
+		return errors.New("The console session has ended")
+	}
+
+	// The control socket is only used to terminate the operation.
+	go func() {
+		defer logger.Debug("Console observer control websocket finished")
+		res := <-s.controlConnected
+		if !res {
+			return
+		}
+
+		s.connsLock.Lock()
+		conn := s.conns[-1]
+		s.connsLock.Unlock()
+
+		for {
+			_, _, err := conn.NextReader()
+			if err != nil {
+				s.consoleDone.Cancel()
+				return
+			}
+		}
+	}()
+
+	outputDone := session.AddObserver(consoleConn)
+	defer session.RemoveObserver(consoleConn)
+
+	// Observers are read-only, discard anything they send.
+	inputDone := ws.MirrorWrite(consoleConn, io.Discard)
+
+	// Wait until either the session, the websocket or the control socket is done.
+	select {
+	case <-outputDone:
+	case <-inputDone:
+	case <-s.consoleDone.Done():
+	}
+
+	// Indicate to the control socket go routine to end if not already.
+	close(s.controlConnected)
+	return nil
+}
+
 func (s *consoleWs) doVGA() error {
 	defer logger.Debug("VGA websocket finished")
 
@@ -390,9 +483,17 @@ func (s *consoleWs) doVGA() error {
 	s.connsLock.Lock()
 	control := s.conns[-1]
 	s.connsLock.Unlock()
-	err := control.Close()
+
+	// Browser based VNC clients may only connect to the data websocket.
+	var err error
+	if control != nil {
+		err = control.Close()
+	}
 
 	// Close all dynamic connections.
+	s.connsLock.Lock()
+	defer s.connsLock.Unlock()
+
 	for conn, console := range s.dynamic {
 		_ = conn.Close()
 		_ = console.Close()
@@ -492,7 +593,7 @@ func instanceConsolePost(d *Daemon, r *http.Request) response.Response {
 	}
 
 	// Basic parameter validation.
-	if !slices.Contains([]string{instance.ConsoleTypeConsole, instance.ConsoleTypeVGA}, post.Type) {
+	if !slices.Contains([]string{instance.ConsoleTypeConsole, instance.ConsoleTypeVGA, instance.ConsoleTypeVNC}, post.Type) {
 		return response.BadRequest(fmt.Errorf("Unknown console type %q", post.Type))
 	}
 
@@ -505,6 +606,14 @@ func instanceConsolePost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(errors.New("VGA console is only supported by virtual machines"))
 	}
 
+	if post.Type == instance.ConsoleTypeVNC && inst.Type() != instancetype.VM {
+		return response.BadRequest(errors.New("VNC console is only supported by virtual machines"))
+	}
+
+	if post.Observer && post.Type != instance.ConsoleTypeConsole {
+		return response.BadRequest(errors.New("Observers are only supported by the text console"))
+	}
+
 	if !inst.IsRunning() {
 		return response.BadRequest(errors.New("Instance is not running"))
 	}
@@ -513,6 +622,16 @@ func instanceConsolePost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(errors.New("Instance is frozen"))
 	}
 
+	// Only one client can interact with the text console, others can attach as observers.
+	if post.Type == instance.ConsoleTypeConsole {
+		active := consoleSessionActive(inst)
+		if post.Observer && !active {
+			return response.BadRequest(errors.New("The instance has no active console session to observe"))
+		} else if !post.Observer && active {
+			return response.Conflict(errors.New("The instance console is already in use, attach as an observer to watch it"))
+		}
+	}
+
 	ws := &consoleWs{}
 	ws.fds = map[int]string{}
 	ws.conns = map[int]*websocket.Conn{}
@@ -533,6 +652,7 @@ func instanceConsolePost(d *Daemon, r *http.Request) response.Response {
 	ws.width = post.Width
 	ws.height = post.Height
 	ws.protocol = post.Type
+	ws.observer = post.Observer
 
 	resources := map[string][]api.URL{}
 	resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", ws.instance.Name())}
@@ -0,0 +1,360 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/canonical/lxd/lxd/asciicast"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/project"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// consoleObserverBacklog is the number of console output chunks that are queued for an observer before it is
+// disconnected for being too slow, so that observers never hold up the console session.
+const consoleObserverBacklog = 256
+
+// consoleRecordingMaxSize is the maximum size of a console recording. Recording stops once it is reached.
+const consoleRecordingMaxSize = 100 * 1024 * 1024
+
+// consoleRecordingMaxCount is the maximum number of console recordings kept per instance. The oldest recordings
+// are removed when a new session is recorded.
+const consoleRecordingMaxCount = 10
+
+// errConsoleRecordingFull is returned when writing to a console recording that reached its maximum size.
+var errConsoleRecordingFull = errors.New("Console recording reached its maximum size")
+
+// consoleSessions tracks the active text console session of each instance on this member.
+var consoleSessions = map[string]*consoleSession{}
+var consoleSessionsLock sync.Mutex
+
+// consoleSession is an active text console session. Its output is recorded if enabled and copied to any
+// read-only observers.
+type consoleSession struct {
+	key string
+
+	// Recording of the session output, nil if recording isn't enabled.
+	recording     *asciicast.Writer
+	recordingFile *consoleRecordingFile
+
+	// Whether the session input is recorded as well.
+	recordInput bool
+
+	// Output queues of the observers.
+	observers map[*websocket.Conn]chan []byte
+
+	lock sync.Mutex
+}
+
+// consoleSessionKey returns the key of the console session of the instance.
+func consoleSessionKey(inst instance.Instance) string {
+	return project.Instance(inst.Project().Name, inst.Name())
+}
+
+// consoleSessionActive returns whether the instance has an active console session.
+func consoleSessionActive(inst instance.Instance) bool {
+	consoleSessionsLock.Lock()
+	defer consoleSessionsLock.Unlock()
+
+	_, found := consoleSessions[consoleSessionKey(inst)]
+	return found
+}
+
+// consoleSessionStart registers a new console session for the instance and starts recording it if
+// `console.recording` is enabled, including its input if `console.recording.input` is enabled too.
+// Only a single session can be active per instance.
+func consoleSessionStart(inst instance.Instance, recordingName string, width int, height int) (*consoleSession, error) {
+	key := consoleSessionKey(inst)
+
+	consoleSessionsLock.Lock()
+	defer consoleSessionsLock.Unlock()
+
+	_, found := consoleSessions[key]
+	if found {
+		return nil, errors.New("The instance console is already in use")
+	}
+
+	session := &consoleSession{
+		key:       key,
+		observers: map[*websocket.Conn]chan []byte{},
+	}
+
+	if shared.IsTrue(inst.ExpandedConfig()["console.recording"]) {
+		err := session.startRecording(inst, recordingName, width, height)
+		if err != nil {
+			return nil, err
+		}
+
+		session.recordInput = shared.IsTrue(inst.ExpandedConfig()["console.recording.input"])
+	}
+
+	consoleSessions[key] = session
+
+	return session, nil
+}
+
+// consoleSessionGet returns the active console session of the instance or nil.
+func consoleSessionGet(inst instance.Instance) *consoleSession {
+	consoleSessionsLock.Lock()
+	defer consoleSessionsLock.Unlock()
+
+	return consoleSessions[consoleSessionKey(inst)]
+}
+
+// startRecording creates the recording file of the session, removing the oldest recordings of the instance
+// beyond consoleRecordingMaxCount.
+func (cs *consoleSession) startRecording(inst instance.Instance, name string, width int, height int) error {
+	recordingDir := inst.ConsoleRecordingPath()
+	err := os.MkdirAll(recordingDir, 0700)
+	if err != nil {
+		return err
+	}
+
+	err = consoleRecordingsRotate(recordingDir, consoleRecordingMaxCount-1)
+	if err != nil {
+		return fmt.Errorf("Failed removing old console recordings: %w", err)
+	}
+
+	f, err := os.OpenFile(filepath.Join(recordingDir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("Failed creating console recording: %w", err)
+	}
+
+	recordingFile := &consoleRecordingFile{File: f, remaining: consoleRecordingMaxSize}
+
+	recording, err := asciicast.NewWriter(recordingFile, asciicast.Header{
+		Width:     width,
+		Height:    height,
+		Timestamp: time.Now().Unix(),
+		Title:     inst.Name(),
+	})
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	cs.recording = recording
+	cs.recordingFile = recordingFile
+
+	return nil
+}
+
+// Write records the console output and queues it for the observers. It never fails so that the console session
+// isn't affected by recording or observer errors.
+func (cs *consoleSession) Write(p []byte) (int, error) {
+	data := bytes.Clone(p)
+
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+
+	if cs.recording != nil {
+		err := cs.recording.Output(data)
+		if err != nil {
+			logger.Warn("Failed recording console output, stopping recording", logger.Ctx{"instance": cs.key, "err": err})
+			cs.stopRecording()
+		}
+	}
+
+	for conn, queue := range cs.observers {
+		select {
+		case queue <- data:
+		default:
+			logger.Debug("Disconnecting slow console observer", logger.Ctx{"instance": cs.key, "address": conn.RemoteAddr().String()})
+			close(queue)
+			delete(cs.observers, conn)
+		}
+	}
+
+	return len(p), nil
+}
+
+// Input returns a writer that records the console input if enabled. Like Write, it never fails.
+func (cs *consoleSession) Input() io.Writer {
+	return consoleSessionInput{session: cs}
+}
+
+// writeInput records the console input if enabled.
+func (cs *consoleSession) writeInput(p []byte) {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+
+	if cs.recording == nil || !cs.recordInput {
+		return
+	}
+
+	err := cs.recording.Input(p)
+	if err != nil {
+		logger.Warn("Failed recording console input, stopping recording", logger.Ctx{"instance": cs.key, "err": err})
+		cs.stopRecording()
+	}
+}
+
+// Resize records a change of the console size.
+func (cs *consoleSession) Resize(width int, height int) {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+
+	if cs.recording == nil {
+		return
+	}
+
+	err := cs.recording.Resize(width, height)
+	if err != nil {
+		logger.Warn("Failed recording console resize, stopping recording", logger.Ctx{"instance": cs.key, "err": err})
+		cs.stopRecording()
+	}
+}
+
+// AddObserver copies the console output to the websocket until the session ends. The returned channel is closed
+// once no more output is sent to the observer.
+func (cs *consoleSession) AddObserver(conn *websocket.Conn) chan struct{} {
+	queue := make(chan []byte, consoleObserverBacklog)
+	done := make(chan struct{})
+
+	cs.lock.Lock()
+	cs.observers[conn] = queue
+	cs.lock.Unlock()
+
+	go func() {
+		defer close(done)
+
+		for data := range queue {
+			err := conn.WriteMessage(websocket.BinaryMessage, data)
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	return done
+}
+
+// RemoveObserver stops copying the console output to the websocket.
+func (cs *consoleSession) RemoveObserver(conn *websocket.Conn) {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+
+	queue, found := cs.observers[conn]
+	if found {
+		close(queue)
+		delete(cs.observers, conn)
+	}
+}
+
+// End unregisters the session, disconnects the observers and finishes the recording.
+func (cs *consoleSession) End() {
+	consoleSessionsLock.Lock()
+	delete(consoleSessions, cs.key)
+	consoleSessionsLock.Unlock()
+
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+
+	for conn, queue := range cs.observers {
+		close(queue)
+		delete(cs.observers, conn)
+	}
+
+	cs.stopRecording()
+}
+
+// stopRecording closes the recording file. Must be called with the lock held.
+func (cs *consoleSession) stopRecording() {
+	if cs.recordingFile == nil {
+		return
+	}
+
+	err := cs.recordingFile.Close()
+	if err != nil {
+		logger.Warn("Failed closing console recording", logger.Ctx{"instance": cs.key, "err": err})
+	}
+
+	cs.recording = nil
+	cs.recordingFile = nil
+}
+
+// consoleSessionInput records the input of a console session.
+type consoleSessionInput struct {
+	session *consoleSession
+}
+
+// Write records the input.
+func (i consoleSessionInput) Write(p []byte) (int, error) {
+	i.session.writeInput(p)
+
+	return len(p), nil
+}
+
+// consoleRecordingsRotate removes the oldest console recordings in dir until at most keep of them are left.
+func consoleRecordingsRotate(dir string, keep int) error {
+	dents, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	recordings := make([]fs.FileInfo, 0, len(dents))
+	for _, dent := range dents {
+		if !validConsoleRecordingFileName(dent.Name()) {
+			continue
+		}
+
+		info, err := dent.Info()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue // Removed in the meantime.
+			}
+
+			return err
+		}
+
+		recordings = append(recordings, info)
+	}
+
+	if len(recordings) <= keep {
+		return nil
+	}
+
+	slices.SortFunc(recordings, func(a fs.FileInfo, b fs.FileInfo) int {
+		return a.ModTime().Compare(b.ModTime())
+	})
+
+	for _, recording := range recordings[:len(recordings)-keep] {
+		err := os.Remove(filepath.Join(dir, recording.Name()))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// consoleRecordingFile is a console recording file that rejects writes beyond its maximum size.
+// Writes are rejected as a whole so that the recording remains valid.
+type consoleRecordingFile struct {
+	*os.File
+
+	remaining int64
+}
+
+// Write writes p to the file unless that would exceed its maximum size.
+func (f *consoleRecordingFile) Write(p []byte) (int, error) {
+	if int64(len(p)) > f.remaining {
+		return 0, errConsoleRecordingFull
+	}
+
+	n, err := f.File.Write(p)
+	f.remaining -= int64(n)
+
+	return n, err
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/ws"
+)
+
+// consoleSessionInstance is an instance with the fields used by console sessions.
+type consoleSessionInstance struct {
+	instance.Instance
+
+	name   string
+	config map[string]string
+	path   string
+}
+
+func (i *consoleSessionInstance) Name() string {
+	return i.name
+}
+
+func (i *consoleSessionInstance) Project() api.Project {
+	return api.Project{Name: "default"}
+}
+
+func (i *consoleSessionInstance) ExpandedConfig() map[string]string {
+	return i.config
+}
+
+func (i *consoleSessionInstance) ConsoleRecordingPath() string {
+	return filepath.Join(i.path, "console-recordings")
+}
+
+// consoleSessionObserver returns the server side of a websocket connection and the client side reading from it.
+func consoleSessionObserver(t *testing.T) (server *websocket.Conn, client *websocket.Conn) {
+	conns := make(chan *websocket.Conn, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := ws.Upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+
+		conns <- conn
+	}))
+	t.Cleanup(srv.Close)
+
+	client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	require.NoError(t, err)
+
+	server = <-conns
+	t.Cleanup(func() {
+		_ = client.Close()
+		_ = server.Close()
+	})
+
+	return server, client
+}
+
+func Test_consoleSessionStartEnd(t *testing.T) {
+	inst := &consoleSessionInstance{name: "c1", path: t.TempDir()}
+
+	session, err := consoleSessionStart(inst, "console_1.cast", 80, 24)
+	require.NoError(t, err)
+	assert.True(t, consoleSessionActive(inst))
+	assert.Equal(t, session, consoleSessionGet(inst))
+
+	// Only a single session can be active.
+	_, err = consoleSessionStart(inst, "console_2.cast", 80, 24)
+	assert.Error(t, err)
+
+	// Sessions of other instances are independent.
+	other := &consoleSessionInstance{name: "c2", path: t.TempDir()}
+	otherSession, err := consoleSessionStart(other, "console_1.cast", 80, 24)
+	require.NoError(t, err)
+	otherSession.End()
+
+	// Recording isn't enabled.
+	n, err := session.Write([]byte("hello"))
+	require.NoError(t, err)
+	assert.Equal(t, 5, n)
+	assert.NoDirExists(t, inst.ConsoleRecordingPath())
+
+	session.End()
+	assert.False(t, consoleSessionActive(inst))
+	assert.Nil(t, consoleSessionGet(inst))
+
+	// A new session can start once the previous one ended.
+	session, err = consoleSessionStart(inst, "console_2.cast", 80, 24)
+	require.NoError(t, err)
+	session.End()
+}
+
+func Test_consoleSessionRecording(t *testing.T) {
+	tests := []struct {
+		name   string
+		config map[string]string
+		events []string
+	}{
+		{
+			name:   "Output only",
+			config: map[string]string{"console.recording": "true"},
+			events: []string{`"o","$ "`, `"r","100x40"`, `"o","ls\r\n"`},
+		},
+		{
+			name:   "Output and input",
+			config: map[string]string{"console.recording": "true", "console.recording.input": "true"},
+			events: []string{`"o","$ "`, `"r","100x40"`, `"i","ls\r"`, `"o","ls\r\n"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inst := &consoleSessionInstance{name: "c1", path: t.TempDir(), config: tt.config}
+
+			session, err := consoleSessionStart(inst, "console_1.cast", 80, 24)
+			require.NoError(t, err)
+
+			_, err = session.Write([]byte("$ "))
+			require.NoError(t, err)
+			session.Resize(100, 40)
+			_, err = session.Input().Write([]byte("ls\r"))
+			require.NoError(t, err)
+			_, err = session.Write([]byte("ls\r\n"))
+			require.NoError(t, err)
+			session.End()
+
+			content, err := os.ReadFile(filepath.Join(inst.ConsoleRecordingPath(), "console_1.cast"))
+			require.NoError(t, err)
+
+			lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+			require.Len(t, lines, len(tt.events)+1)
+			assert.Contains(t, lines[0], `"title":"c1"`)
+			for i, event := range tt.events {
+				assert.Contains(t, lines[i+1], event)
+			}
+		})
+	}
+}
+
+func Test_consoleRecordingsRotate(t *testing.T) {
+	inst := &consoleSessionInstance{name: "c1", path: t.TempDir(), config: map[string]string{"console.recording": "true"}}
+
+	dir := inst.ConsoleRecordingPath()
+	require.NoError(t, os.Mkdir(dir, 0700))
+
+	now := time.Now()
+	for i := range consoleRecordingMaxCount + 2 {
+		path := filepath.Join(dir, fmt.Sprintf("console_%d.cast", i))
+		require.NoError(t, os.WriteFile(path, nil, 0600))
+
+		modTime := now.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+
+	// Other files are left alone.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other"), nil, 0600))
+
+	session, err := consoleSessionStart(inst, "console_new.cast", 80, 24)
+	require.NoError(t, err)
+	session.End()
+
+	dents, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	names := []string{}
+	for _, dent := range dents {
+		names = append(names, dent.Name())
+	}
+
+	// The oldest recordings were removed to make room for the new one.
+	assert.Len(t, names, consoleRecordingMaxCount+1)
+	assert.Contains(t, names, "console_new.cast")
+	assert.Contains(t, names, "other")
+	assert.NotContains(t, names, "console_0.cast")
+	assert.NotContains(t, names, "console_1.cast")
+	assert.NotContains(t, names, "console_2.cast")
+	assert.Contains(t, names, "console_3.cast")
+}
+
+func Test_consoleRecordingFile(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "console.cast"))
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+
+	recordingFile := &consoleRecordingFile{File: f, remaining: 10}
+
+	n, err := recordingFile.Write([]byte("0123456"))
+	require.NoError(t, err)
+	assert.Equal(t, 7, n)
+
+	// Writes exceeding the maximum size are rejected as a whole.
+	n, err = recordingFile.Write([]byte("7890"))
+	assert.ErrorIs(t, err, errConsoleRecordingFull)
+	assert.Equal(t, 0, n)
+
+	n, err = recordingFile.Write([]byte("789"))
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	content, err := os.ReadFile(f.Name())
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789", string(content))
+}
+
+func Test_consoleSessionObserver(t *testing.T) {
+	inst := &consoleSessionInstance{name: "c1", path: t.TempDir()}
+
+	session, err := consoleSessionStart(inst, "console_1.cast", 80, 24)
+	require.NoError(t, err)
+
+	server, client := consoleSessionObserver(t)
+	done := session.AddObserver(server)
+
+	_, err = session.Write([]byte("hello"))
+	require.NoError(t, err)
+
+	_, data, err := client.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	// Ending the session stops copying the output to the observer.
+	session.End()
+	<-done
+}
+
+func Test_consoleSessionSlowObserver(t *testing.T) {
+	inst := &consoleSessionInstance{name: "c1", path: t.TempDir()}
+
+	session, err := consoleSessionStart(inst, "console_1.cast", 80, 24)
+	require.NoError(t, err)
+	defer session.End()
+
+	// Fill the output queue of an observer that never sends it, as if its websocket was blocked.
+	slowConn, _ := consoleSessionObserver(t)
+	slowQueue := make(chan []byte, consoleObserverBacklog)
+	for range consoleObserverBacklog {
+		slowQueue <- []byte("x")
+	}
+
+	session.observers[slowConn] = slowQueue
+
+	fastConn, client := consoleSessionObserver(t)
+	fastDone := session.AddObserver(fastConn)
+
+	// The session output never blocks on the slow observer.
+	_, err = session.Write([]byte("y"))
+	require.NoError(t, err)
+
+	// The slow observer is disconnected and its queue closed.
+	session.lock.Lock()
+	_, found := session.observers[slowConn]
+	session.lock.Unlock()
+	assert.False(t, found)
+
+	received := 0
+	for range slowQueue {
+		received++
+	}
+
+	assert.Equal(t, consoleObserverBacklog, received)
+
+	// The other observer keeps receiving the output.
+	_, data, err := client.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, "y", string(data))
+
+	session.RemoveObserver(fastConn)
+	<-fastDone
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/shared/ws"
+)
+
+func Test_consoleWebsocketResponseHeader(t *testing.T) {
+	tests := []struct {
+		name         string
+		protocol     string
+		subprotocols string
+		want         http.Header
+	}{
+		{
+			name:         "VNC with binary",
+			protocol:     instance.ConsoleTypeVNC,
+			subprotocols: "binary",
+			want:         http.Header{"Sec-Websocket-Protocol": []string{"binary"}},
+		},
+		{
+			name:         "VNC with binary among others",
+			protocol:     instance.ConsoleTypeVNC,
+			subprotocols: "base64, binary",
+			want:         http.Header{"Sec-Websocket-Protocol": []string{"binary"}},
+		},
+		{
+			name:     "VNC without subprotocols",
+			protocol: instance.ConsoleTypeVNC,
+		},
+		{
+			name:         "VNC without binary",
+			protocol:     instance.ConsoleTypeVNC,
+			subprotocols: "base64",
+		},
+		{
+			name:         "VGA with binary",
+			protocol:     instance.ConsoleTypeVGA,
+			subprotocols: "binary",
+		},
+		{
+			name:         "Console with binary",
+			protocol:     instance.ConsoleTypeConsole,
+			subprotocols: "binary",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/websocket", nil)
+			if tt.subprotocols != "" {
+				r.Header.Set("Sec-Websocket-Protocol", tt.subprotocols)
+			}
+
+			assert.Equal(t, tt.want, consoleWebsocketResponseHeader(tt.protocol, r))
+		})
+	}
+}
+
+func Test_consoleWebsocketResponseHeader_upgrade(t *testing.T) {
+	tests := []struct {
+		name         string
+		protocol     string
+		subprotocols []string
+		want         string
+	}{
+		{
+			name:         "VNC selects binary",
+			protocol:     instance.ConsoleTypeVNC,
+			subprotocols: []string{"binary"},
+			want:         "binary",
+		},
+		{
+			name:         "VNC without binary",
+			protocol:     instance.ConsoleTypeVNC,
+			subprotocols: []string{"base64"},
+		},
+		{
+			name:         "VGA doesn't select binary",
+			protocol:     instance.ConsoleTypeVGA,
+			subprotocols: []string{"binary"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				conn, err := ws.Upgrader.Upgrade(w, r, consoleWebsocketResponseHeader(tt.protocol, r))
+				if err != nil {
+					return
+				}
+
+				_ = conn.Close()
+			}))
+			defer server.Close()
+
+			dialer := websocket.Dialer{Subprotocols: tt.subprotocols}
+			conn, _, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+			require.NoError(t, err)
+			defer func() { _ = conn.Close() }()
+
+			assert.Equal(t, tt.want, conn.Subprotocol())
+		})
+	}
+}
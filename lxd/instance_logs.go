@@ -3,6 +3,7 @@ package main
 import (
 	"errors"
 	"fmt"
+	"io/fs"
 	"net/http"
 	"net/url"
 	"os"
@@ -74,6 +75,31 @@ var instanceExecOutputsCmd = APIEndpoint{
 	Get: APIEndpointAction{Handler: instanceExecOutputsGet, AccessHandler: allowPermission(entity.TypeInstance, auth.EntitlementCanExec, "name")},
 }
 
+var instanceConsoleRecordingCmd = APIEndpoint{
+	Name:        "instanceConsoleRecording",
+	Path:        "instances/{name}/logs/console-recordings/{file}",
+	MetricsType: entity.TypeInstance,
+	Aliases: []APIEndpointAlias{
+		{Name: "containerConsoleRecording", Path: "containers/{name}/logs/console-recordings/{file}"},
+		{Name: "vmConsoleRecording", Path: "virtual-machines/{name}/logs/console-recordings/{file}"},
+	},
+
+	Delete: APIEndpointAction{Handler: instanceConsoleRecordingDelete, AccessHandler: allowPermission(entity.TypeInstance, auth.EntitlementCanEdit, "name")},
+	Get:    APIEndpointAction{Handler: instanceConsoleRecordingGet, AccessHandler: allowPermission(entity.TypeInstance, auth.EntitlementCanAccessConsole, "name")},
+}
+
+var instanceConsoleRecordingsCmd = APIEndpoint{
+	Name:        "instanceConsoleRecordings",
+	Path:        "instances/{name}/logs/console-recordings",
+	MetricsType: entity.TypeInstance,
+	Aliases: []APIEndpointAlias{
+		{Name: "containerConsoleRecordings", Path: "containers/{name}/logs/console-recordings"},
+		{Name: "vmConsoleRecordings", Path: "virtual-machines/{name}/logs/console-recordings"},
+	},
+
+	Get: APIEndpointAction{Handler: instanceConsoleRecordingsGet, AccessHandler: allowPermission(entity.TypeInstance, auth.EntitlementCanAccessConsole, "name")},
+}
+
 // swagger:operation GET /1.0/instances/{name}/logs instances instance_logs_get
 //
 //	Get the log files
@@ -663,6 +689,272 @@ func instanceExecOutputDelete(d *Daemon, r *http.Request) response.Response {
 	return response.EmptySyncResponse
 }
 
+// swagger:operation GET /1.0/instances/{name}/logs/console-recordings instances instance_console-recordings_get
+//
+//	Get the console recording files
+//
+//	Returns a list of console recording files (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/instances/foo/logs/console-recordings/console_d0a89537-0617-4ed6-a79b-c2e88a970965.cast"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceConsoleRecordingsGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	instanceType, err := urlInstanceTypeDetect(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	projectName := request.ProjectParam(r)
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if shared.IsSnapshot(name) {
+		return response.BadRequest(errors.New("Invalid instance name"))
+	}
+
+	// Handle requests targeted to a container on a different node
+	resp, err := forwardedResponseIfInstanceIsRemote(r.Context(), s, projectName, name, instanceType)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if resp != nil {
+		return resp
+	}
+
+	// Ensure instance exists.
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Read console recording files, the directory only exists once a session was recorded.
+	result := []string{}
+
+	dents, err := os.ReadDir(inst.ConsoleRecordingPath())
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return response.SmartError(err)
+	}
+
+	for _, f := range dents {
+		if !validConsoleRecordingFileName(f.Name()) {
+			continue
+		}
+
+		result = append(result, api.NewURL().Path(version.APIVersion, "instances", name, "logs", "console-recordings", f.Name()).String())
+	}
+
+	return response.SyncResponse(true, result)
+}
+
+// swagger:operation GET /1.0/instances/{name}/logs/console-recordings/{filename} instances instance_console-recording_get
+//
+//	Get the console recording file
+//
+//	Gets the console recording file in the asciicast v2 format.
+//
+//	---
+//	produces:
+//	  - application/json
+//	  - application/octet-stream
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	     description: Raw file
+//	     content:
+//	       application/octet-stream:
+//	         schema:
+//	           type: string
+//	           example: some-text
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceConsoleRecordingGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	instanceType, err := urlInstanceTypeDetect(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	projectName := request.ProjectParam(r)
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if shared.IsSnapshot(name) {
+		return response.BadRequest(errors.New("Invalid instance name"))
+	}
+
+	// Handle requests targeted to a container on a different node
+	resp, err := forwardedResponseIfInstanceIsRemote(r.Context(), s, projectName, name, instanceType)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if resp != nil {
+		return resp
+	}
+
+	// Ensure instance exists.
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	file, err := url.PathUnescape(mux.Vars(r)["file"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !validConsoleRecordingFileName(file) {
+		return response.BadRequest(fmt.Errorf("Console recording file name %q not valid", file))
+	}
+
+	ent := response.FileResponseEntry{
+		Path:     filepath.Join(inst.ConsoleRecordingPath(), file),
+		Filename: file,
+	}
+
+	s.Events.SendLifecycle(projectName, lifecycle.InstanceLogRetrieved.Event(file, inst, request.CreateRequestor(r.Context()), nil))
+
+	return response.FileResponse([]response.FileResponseEntry{ent}, nil)
+}
+
+// swagger:operation DELETE /1.0/instances/{name}/logs/console-recordings/{filename} instances instance_console-recording_delete
+//
+//	Delete the console recording file
+//
+//	Removes the console recording file.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceConsoleRecordingDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	instanceType, err := urlInstanceTypeDetect(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	projectName := request.ProjectParam(r)
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if shared.IsSnapshot(name) {
+		return response.BadRequest(errors.New("Invalid instance name"))
+	}
+
+	// Handle requests targeted to a container on a different node
+	resp, err := forwardedResponseIfInstanceIsRemote(r.Context(), s, projectName, name, instanceType)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if resp != nil {
+		return resp
+	}
+
+	// Ensure instance exists.
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	file, err := url.PathUnescape(mux.Vars(r)["file"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !validConsoleRecordingFileName(file) {
+		return response.BadRequest(fmt.Errorf("Console recording file name %q not valid", file))
+	}
+
+	err = os.Remove(filepath.Join(inst.ConsoleRecordingPath(), file))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	s.Events.SendLifecycle(projectName, lifecycle.InstanceLogDeleted.Event(file, inst, request.CreateRequestor(r.Context()), nil))
+
+	return response.EmptySyncResponse
+}
+
 func validLogFileName(fname string) bool {
 	if !shared.IsFileName(fname) {
 		return false
@@ -692,3 +984,11 @@ func validExecOutputFileName(fName string) bool {
 	return (strings.HasSuffix(fName, ".stdout") || strings.HasSuffix(fName, ".stderr")) &&
 		strings.HasPrefix(fName, "exec_")
 }
+
+func validConsoleRecordingFileName(fName string) bool {
+	if !shared.IsFileName(fName) {
+		return false
+	}
+
+	return strings.HasSuffix(fName, ".cast") && strings.HasPrefix(fName, "console_")
+}
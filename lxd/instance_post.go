@@ -10,6 +10,7 @@ import (
 	"maps"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
 
 	"github.com/gorilla/mux"
@@ -319,6 +320,18 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 	}
 
 	if req.Migration {
+		// Moves between cluster members keep the instance on the same storage pool.
+		if req.Pool != "" && targetMemberInfo != nil && targetMemberInfo.Name != inst.Location() {
+			poolName, err := inst.StoragePool()
+			if err != nil {
+				return response.SmartError(err)
+			}
+
+			if req.Pool != poolName {
+				return response.BadRequest(errors.New("Instances can't be moved to another cluster member and storage pool at the same time"))
+			}
+		}
+
 		// Server-side instance migration.
 		if req.Pool != "" || req.Project != "" {
 			// Check if user has access to target project.
@@ -469,6 +482,19 @@ func instancePostMigration(s *state.State, inst instance.Instance, req api.Insta
 			return api.StatusErrorf(http.StatusBadRequest, "Instance must be stopped to move between pools statelessly")
 		}
 
+		// Running VMs can have their root disk mirrored to another pool without being stopped.
+		vm, isVM := inst.(instance.VM)
+		if isVM {
+			liveMove, err := instancePostLivePoolMovable(inst, req)
+			if err != nil {
+				return err
+			}
+
+			if liveMove {
+				return instancePostLivePoolMove(s, vm, req.Pool, op)
+			}
+		}
+
 		statefulStart = true
 		err := inst.Stop(true)
 		if err != nil {
@@ -648,6 +674,77 @@ func instancePostMigration(s *state.State, inst instance.Instance, req api.Insta
 	return nil
 }
 
+// instancePostLivePoolMovable returns whether the move request only changes the storage pool of the running
+// instance, in which case its root disk can be moved live.
+func instancePostLivePoolMovable(inst instance.Instance, req api.InstancePost) (bool, error) {
+	if req.Pool == "" || req.Name != inst.Name() || req.Project != inst.Project().Name {
+		return false, nil
+	}
+
+	poolName, err := inst.StoragePool()
+	if err != nil {
+		return false, err
+	}
+
+	if req.Pool == poolName {
+		return false, nil
+	}
+
+	localConfig := inst.LocalConfig()
+	for key, value := range req.Config {
+		if localConfig[key] != value {
+			return false, nil
+		}
+	}
+
+	localDevices := inst.LocalDevices()
+	for devName, dev := range req.Devices {
+		if !maps.Equal(localDevices[devName], dev) {
+			return false, nil
+		}
+	}
+
+	if req.Profiles != nil {
+		profileNames := make([]string, 0, len(inst.Profiles()))
+		for _, p := range inst.Profiles() {
+			profileNames = append(profileNames, p.Name)
+		}
+
+		if !slices.Equal(req.Profiles, profileNames) {
+			return false, nil
+		}
+	}
+
+	// Snapshots can't be moved live and need the instance to be stopped.
+	snapshots, err := inst.Snapshots()
+	if err != nil {
+		return false, err
+	}
+
+	return len(snapshots) == 0, nil
+}
+
+// instancePostLivePoolMove moves the root volume of a running VM to another storage pool by mirroring its root
+// disk. The volume on the source pool is deleted once the VM stops.
+func instancePostLivePoolMove(s *state.State, vm instance.VM, poolName string, op *operations.Operation) error {
+	if vm.LocalConfig()["volatile.move.source_pool"] == poolName {
+		return api.StatusErrorf(http.StatusBadRequest, "Instance must be restarted before it can be moved back to storage pool %q", poolName)
+	}
+
+	pool, err := storagePools.LoadByName(s, poolName)
+	if err != nil {
+		return err
+	}
+
+	diskPath, cleanup, err := pool.CreateInstanceFromLiveMove(vm, op)
+	if err != nil {
+		return fmt.Errorf("Failed creating root volume on storage pool %q: %w", poolName, err)
+	}
+
+	// The new root volume is only removed by RootDiskMirror while the VM hasn't switched over to it yet.
+	return vm.RootDiskMirror(diskPath, pool.Name(), cleanup)
+}
+
 // Migrate an instance to another cluster node (supports both local and remote storage).
 // Source and target members must be online.
 func instancePostClusteringMigrate(ctx context.Context, s *state.State, srcPool storagePools.Pool, srcInst instance.Instance, newInstName string, srcMember db.NodeInfo, newMember db.NodeInfo, targetGroupName string, stateful bool, allowInconsistent bool) (func(op *operations.Operation) error, error) {
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	deviceConfig "github.com/canonical/lxd/lxd/device/config"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// livePoolMoveInstance is an instance with the fields checked by instancePostLivePoolMovable.
+type livePoolMoveInstance struct {
+	instance.Instance
+
+	snapshots []instance.Instance
+}
+
+func (i *livePoolMoveInstance) Name() string {
+	return "c1"
+}
+
+func (i *livePoolMoveInstance) Project() api.Project {
+	return api.Project{Name: "default"}
+}
+
+func (i *livePoolMoveInstance) StoragePool() (string, error) {
+	return "pool1", nil
+}
+
+func (i *livePoolMoveInstance) LocalConfig() map[string]string {
+	return map[string]string{"limits.cpu": "2"}
+}
+
+func (i *livePoolMoveInstance) LocalDevices() deviceConfig.Devices {
+	return deviceConfig.Devices{"eth0": {"type": "nic", "network": "lxdbr0"}}
+}
+
+func (i *livePoolMoveInstance) Profiles() []api.Profile {
+	return []api.Profile{{Name: "default"}, {Name: "vm"}}
+}
+
+func (i *livePoolMoveInstance) Snapshots() ([]instance.Instance, error) {
+	return i.snapshots, nil
+}
+
+func Test_instancePostLivePoolMovable(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       api.InstancePost
+		snapshots []instance.Instance
+		want      bool
+	}{
+		{
+			name: "Pool only",
+			req:  api.InstancePost{Name: "c1", Project: "default", Pool: "pool2"},
+			want: true,
+		},
+		{
+			name: "Unchanged config, devices and profiles",
+			req: api.InstancePost{
+				Name:     "c1",
+				Project:  "default",
+				Pool:     "pool2",
+				Config:   map[string]string{"limits.cpu": "2"},
+				Devices:  map[string]map[string]string{"eth0": {"type": "nic", "network": "lxdbr0"}},
+				Profiles: []string{"default", "vm"},
+			},
+			want: true,
+		},
+		{
+			name: "No pool",
+			req:  api.InstancePost{Name: "c1", Project: "default"},
+			want: false,
+		},
+		{
+			name: "Same pool",
+			req:  api.InstancePost{Name: "c1", Project: "default", Pool: "pool1"},
+			want: false,
+		},
+		{
+			name: "Renamed",
+			req:  api.InstancePost{Name: "c2", Project: "default", Pool: "pool2"},
+			want: false,
+		},
+		{
+			name: "Other project",
+			req:  api.InstancePost{Name: "c1", Project: "p1", Pool: "pool2"},
+			want: false,
+		},
+		{
+			name: "Changed config",
+			req:  api.InstancePost{Name: "c1", Project: "default", Pool: "pool2", Config: map[string]string{"limits.cpu": "4"}},
+			want: false,
+		},
+		{
+			name: "Changed device",
+			req:  api.InstancePost{Name: "c1", Project: "default", Pool: "pool2", Devices: map[string]map[string]string{"eth0": {"type": "nic", "network": "lxdbr1"}}},
+			want: false,
+		},
+		{
+			name: "Added device",
+			req:  api.InstancePost{Name: "c1", Project: "default", Pool: "pool2", Devices: map[string]map[string]string{"eth1": {"type": "nic", "network": "lxdbr0"}}},
+			want: false,
+		},
+		{
+			name: "Reordered profiles",
+			req:  api.InstancePost{Name: "c1", Project: "default", Pool: "pool2", Profiles: []string{"vm", "default"}},
+			want: false,
+		},
+		{
+			name: "No profiles",
+			req:  api.InstancePost{Name: "c1", Project: "default", Pool: "pool2", Profiles: []string{}},
+			want: false,
+		},
+		{
+			name:      "Snapshots",
+			req:       api.InstancePost{Name: "c1", Project: "default", Pool: "pool2"},
+			snapshots: []instance.Instance{&livePoolMoveInstance{}},
+			want:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inst := &livePoolMoveInstance{snapshots: tt.snapshots}
+
+			got, err := instancePostLivePoolMovable(inst, tt.req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
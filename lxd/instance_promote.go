@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+
+	"github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/lifecycle"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/project"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	storagePools "github.com/canonical/lxd/lxd/storage"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/version"
+)
+
+// swagger:operation POST /1.0/instances/{name}/promote instances instance_promote_post
+//
+//	Promote a replica
+//
+//	Turns an instance created by storage pool replication into a regular instance that can be started.
+//	Further replication runs from the source instance leave the promoted instance untouched.
+//	The replicated custom volumes attached to the instance are promoted along with it.
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instancePromotePost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if shared.IsSnapshot(name) {
+		return response.BadRequest(errors.New("Invalid instance name"))
+	}
+
+	instanceType, err := urlInstanceTypeDetect(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Handle requests targeted to an instance on a different node.
+	resp, err := forwardedResponseIfInstanceIsRemote(r.Context(), s, projectName, name, instanceType)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if resp != nil {
+		return resp
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	replicaSource := inst.LocalConfig()[instanceReplicaSourceKey]
+	if replicaSource == "" {
+		return response.BadRequest(fmt.Errorf("Instance %q isn't a replica", name))
+	}
+
+	run := func(op *operations.Operation) error {
+		// Promote the replicated custom volumes attached to the instance first so that they can be used
+		// once the instance is started.
+		instProject := inst.Project()
+		volProjectName := project.StorageVolumeProjectFromRecord(&instProject, cluster.StoragePoolVolumeTypeCustom)
+
+		for _, vol := range instanceReplicaVolumes(inst.ExpandedDevices()) {
+			pool, err := storagePools.LoadByName(s, vol.pool)
+			if err != nil {
+				return fmt.Errorf("Failed loading storage pool %q: %w", vol.pool, err)
+			}
+
+			err = storageVolumePromote(pool, volProjectName, vol.name, op)
+			if err != nil {
+				return err
+			}
+		}
+
+		err := inst.VolatileSet(map[string]string{instanceReplicaSourceKey: ""})
+		if err != nil {
+			return fmt.Errorf("Failed promoting replica: %w", err)
+		}
+
+		s.Events.SendLifecycle(projectName, lifecycle.InstanceUpdated.Event(inst, map[string]any{"promoted_from": replicaSource}))
+
+		return nil
+	}
+
+	resources := map[string][]api.URL{}
+	resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", name)}
+
+	if inst.Type() == instancetype.Container {
+		resources["containers"] = resources["instances"]
+	}
+
+	op, err := operations.OperationCreate(r.Context(), s, projectName, operations.OperationClassTask, operationtype.InstancePromote, resources, nil, run, nil, nil)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
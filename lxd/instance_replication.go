@@ -0,0 +1,509 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	lxd "github.com/canonical/lxd/client"
+	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	deviceConfig "github.com/canonical/lxd/lxd/device/config"
+	"github.com/canonical/lxd/lxd/device/filters"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/project"
+	"github.com/canonical/lxd/lxd/state"
+	storagePools "github.com/canonical/lxd/lxd/storage"
+	storageDrivers "github.com/canonical/lxd/lxd/storage/drivers"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/version"
+)
+
+// instanceReplicaSourceKey is the config key marking an instance as a replica that hasn't been promoted yet.
+const instanceReplicaSourceKey = "volatile.replica.source"
+
+// autoReplicateInstancesTask replicates the local instances of storage pools that have a replication target
+// and a replication schedule.
+func autoReplicateInstancesTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := stateFunc()
+
+		pools := map[string]storagePools.Pool{}
+		var instances []instance.Instance
+
+		// Get list of instances on the local member that are due to be replicated.
+		filter := dbCluster.InstanceFilter{Node: &s.ServerName}
+
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
+				inst, err := instance.Load(s, dbInst, p)
+				if err != nil {
+					return fmt.Errorf("Failed loading instance %q (project %q) for replication task: %w", dbInst.Name, dbInst.Project, err)
+				}
+
+				// Replicas are never replicated any further.
+				if inst.LocalConfig()[instanceReplicaSourceKey] != "" {
+					return nil
+				}
+
+				poolName, err := inst.StoragePool()
+				if err != nil {
+					return nil
+				}
+
+				pool, found := pools[poolName]
+				if !found {
+					pool, err = storagePools.LoadByName(s, poolName)
+					if err != nil {
+						return fmt.Errorf("Failed loading storage pool %q for replication task: %w", poolName, err)
+					}
+
+					pools[poolName] = pool
+				}
+
+				config := pool.Driver().Config()
+				if config["replication.target"] == "" || config["replication.schedule"] == "" {
+					return nil
+				}
+
+				// Check if replication is scheduled.
+				if !snapshotIsScheduledNow(config["replication.schedule"], pool.ID()) {
+					return nil
+				}
+
+				logger.Debug("Scheduling instance replication", logger.Ctx{"instance": inst.Name(), "project": inst.Project().Name, "pool": poolName})
+				instances = append(instances, inst)
+
+				return nil
+			}, filter)
+		})
+		if err != nil {
+			logger.Error("Failed getting instance replication schedule info", logger.Ctx{"err": err})
+			return
+		}
+
+		if len(instances) == 0 {
+			return
+		}
+
+		opRun := func(op *operations.Operation) error {
+			return autoReplicateInstances(ctx, s, pools, instances, op)
+		}
+
+		op, err := operations.OperationCreate(context.Background(), s, "", operations.OperationClassTask, operationtype.InstanceReplicate, nil, nil, opRun, nil, nil)
+		if err != nil {
+			logger.Error("Failed creating scheduled instance replication operation", logger.Ctx{"err": err})
+			return
+		}
+
+		logger.Info("Replicating instances")
+
+		err = op.Start()
+		if err != nil {
+			logger.Error("Failed starting scheduled instance replication operation", logger.Ctx{"err": err})
+			return
+		}
+
+		err = op.Wait(ctx)
+		if err != nil {
+			logger.Error("Failed scheduled instance replication", logger.Ctx{"err": err})
+			return
+		}
+
+		logger.Info("Done replicating instances")
+	}
+
+	first := true
+	schedule := func() (time.Duration, error) {
+		interval := time.Minute
+
+		if first {
+			first = false
+			return interval, task.ErrSkip
+		}
+
+		return interval, nil
+	}
+
+	return f, schedule
+}
+
+// autoReplicateInstances replicates each of the given instances to the target of its storage pool.
+// A failure to replicate an instance doesn't prevent the replication of the other instances.
+func autoReplicateInstances(ctx context.Context, s *state.State, pools map[string]storagePools.Pool, instances []instance.Instance, op *operations.Operation) error {
+	var failed int
+
+	for _, inst := range instances {
+		err := ctx.Err()
+		if err != nil {
+			return err // Stop if context is cancelled.
+		}
+
+		poolName, err := inst.StoragePool()
+		if err != nil {
+			return err
+		}
+
+		err = instanceReplicate(ctx, s, pools[poolName].Driver().Config(), inst, op)
+		if err != nil {
+			logger.Error("Failed replicating instance", logger.Ctx{"instance": inst.Name(), "project": inst.Project().Name, "pool": poolName, "err": err})
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("Failed replicating %d out of %d instances", failed, len(instances))
+	}
+
+	return nil
+}
+
+// instanceReplicaSource returns the value of the replica marker for replicas of the given instance.
+func instanceReplicaSource(s *state.State, inst instance.Instance) string {
+	return s.ServerName + "/" + inst.Project().Name + "/" + inst.Name()
+}
+
+// instanceReplicaVolume is a custom volume attached to an instance.
+type instanceReplicaVolume struct {
+	pool string
+	name string
+}
+
+// instanceReplicaVolumes returns the custom volumes attached to an instance through the given devices.
+// These volumes are replicated along with the instance so that its disk devices can be used on the target.
+func instanceReplicaVolumes(devices deviceConfig.Devices) []instanceReplicaVolume {
+	var volumes []instanceReplicaVolume
+
+	for _, dev := range devices.Sorted() {
+		if !filters.IsCustomVolumeDisk(dev.Config) {
+			continue
+		}
+
+		if dev.Config["source.type"] != "" && dev.Config["source.type"] != dbCluster.StoragePoolVolumeTypeNameCustom {
+			continue
+		}
+
+		vol := instanceReplicaVolume{pool: dev.Config["pool"], name: dev.Config["source"]}
+		if !slices.Contains(volumes, vol) {
+			volumes = append(volumes, vol)
+		}
+	}
+
+	return volumes
+}
+
+// replicaState checks the replica marker in the config of an existing replica against the expected replica source.
+// It returns whether the replica needs to be refreshed and whether it must be skipped as it has been promoted.
+func replicaState(found bool, config map[string]string, replicaSource string) (refresh bool, skip bool, err error) {
+	if !found {
+		return false, false, nil
+	}
+
+	source := config[instanceReplicaSourceKey]
+	if source == "" {
+		return false, true, nil
+	}
+
+	if source != replicaSource {
+		return false, false, fmt.Errorf("It is a replica of %q", source)
+	}
+
+	return true, false, nil
+}
+
+// replicationTargetSecrets returns the migration secrets from the metadata of a replication target operation.
+func replicationTargetSecrets(metadata map[string]any) map[string]string {
+	secrets := map[string]string{}
+	for k, v := range metadata {
+		vStr, ok := v.(string)
+		if !ok {
+			continue
+		}
+
+		secrets[k] = vStr
+	}
+
+	return secrets
+}
+
+// instanceReplicationTarget connects to the replication target of a storage pool.
+// It returns the client for the target, the target URL and the certificate of the target.
+func instanceReplicationTarget(ctx context.Context, s *state.State, poolConfig map[string]string) (lxd.InstanceServer, string, string, error) {
+	target := poolConfig["replication.target"]
+
+	// Remote LXD server.
+	if strings.HasPrefix(target, "https://") {
+		serverCert := s.ServerCert()
+
+		args := &lxd.ConnectionArgs{
+			TLSServerCert: poolConfig["replication.target.certificate"],
+			TLSClientCert: string(serverCert.PublicKey()),
+			TLSClientKey:  string(serverCert.PrivateKey()),
+			UserAgent:     version.UserAgent,
+			Proxy:         s.Proxy,
+		}
+
+		dest, err := lxd.ConnectLXD(target, args)
+		if err != nil {
+			return nil, "", "", fmt.Errorf("Failed connecting to replication target %q: %w", target, err)
+		}
+
+		return dest, strings.TrimSuffix(target, "/"), poolConfig["replication.target.certificate"], nil
+	}
+
+	// Other cluster member.
+	if target == s.ServerName {
+		return nil, "", "", errors.New("Replication target cannot be the local cluster member")
+	}
+
+	var member db.NodeInfo
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		member, err = tx.GetNodeByName(ctx, target)
+
+		return err
+	})
+	if err != nil {
+		return nil, "", "", fmt.Errorf("Failed loading replication target cluster member %q: %w", target, err)
+	}
+
+	if member.IsOffline(s.GlobalConfig.OfflineThreshold()) {
+		return nil, "", "", fmt.Errorf("Replication target cluster member %q is offline", target)
+	}
+
+	networkCert := s.Endpoints.NetworkCert()
+
+	dest, err := cluster.Connect(ctx, member.Address, networkCert, s.ServerCert(), false)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("Failed connecting to replication target cluster member %q: %w", target, err)
+	}
+
+	return dest.UseTarget(member.Name), "https://" + member.Address, string(networkCert.PublicKey()), nil
+}
+
+// instanceReplicate copies the given instance and its snapshots to the replication target of its storage pool.
+// The first replication creates the replica and later ones refresh it. Replicas that have been promoted are
+// left alone.
+func instanceReplicate(ctx context.Context, s *state.State, poolConfig map[string]string, inst instance.Instance, op *operations.Operation) error {
+	dest, targetURL, targetCert, err := instanceReplicationTarget(ctx, s, poolConfig)
+	if err != nil {
+		return err
+	}
+
+	projectName := poolConfig["replication.project"]
+	if projectName == "" {
+		projectName = inst.Project().Name
+	}
+
+	dest = dest.UseProject(projectName)
+	replicaSource := instanceReplicaSource(s, inst)
+
+	// Check for an existing replica.
+	replica, _, err := dest.GetInstance(inst.Name())
+	if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return fmt.Errorf("Failed checking for existing replica: %w", err)
+	}
+
+	var replicaConfig map[string]string
+	if replica != nil {
+		replicaConfig = replica.Config
+	}
+
+	refresh, skip, err := replicaState(replica != nil, replicaConfig, replicaSource)
+	if err != nil {
+		return fmt.Errorf("Invalid instance %q on the replication target: %w", inst.Name(), err)
+	}
+
+	if skip {
+		logger.Warn("Skipping replication as the replica has been promoted", logger.Ctx{"instance": inst.Name(), "project": inst.Project().Name, "target": poolConfig["replication.target"]})
+		return nil
+	}
+
+	// Replicate the attached custom volumes first as the replica's disk devices refer to them.
+	// Volumes on remote storage pools are already available to other cluster members.
+	remoteTarget := strings.HasPrefix(poolConfig["replication.target"], "https://")
+	instProject := inst.Project()
+	volProjectName := project.StorageVolumeProjectFromRecord(&instProject, dbCluster.StoragePoolVolumeTypeCustom)
+
+	for _, vol := range instanceReplicaVolumes(inst.ExpandedDevices()) {
+		volPool, err := storagePools.LoadByName(s, vol.pool)
+		if err != nil {
+			return fmt.Errorf("Failed loading storage pool %q: %w", vol.pool, err)
+		}
+
+		if !remoteTarget && volPool.Driver().Info().Remote {
+			continue
+		}
+
+		err = instanceReplicateVolume(s, dest, targetURL, targetCert, volPool, volProjectName, vol.name, op)
+		if err != nil {
+			return fmt.Errorf("Failed replicating storage volume %q on storage pool %q: %w", vol.name, vol.pool, err)
+		}
+	}
+
+	renderRes, _, err := inst.Render()
+	if err != nil {
+		return fmt.Errorf("Failed getting instance info: %w", err)
+	}
+
+	instInfo, ok := renderRes.(*api.Instance)
+	if !ok {
+		return errors.New("Unexpected result from instance render")
+	}
+
+	// The replica uses the expanded config and devices as the profiles may not exist on the target.
+	config := make(map[string]string, len(instInfo.ExpandedConfig)+1)
+	for k, v := range instInfo.ExpandedConfig {
+		if instancetype.InstanceIncludeWhenCopying(k, true) {
+			config[k] = v
+		}
+	}
+
+	config[instanceReplicaSourceKey] = replicaSource
+
+	destOp, err := dest.CreateInstance(api.InstancesPost{
+		Name: inst.Name(),
+		InstancePut: api.InstancePut{
+			Architecture: instInfo.Architecture,
+			Config:       config,
+			Devices:      instInfo.ExpandedDevices,
+			Ephemeral:    instInfo.Ephemeral,
+			Profiles:     []string{},
+			Stateful:     instInfo.Stateful,
+			Description:  instInfo.Description,
+		},
+		Type: api.InstanceType(instInfo.Type),
+		Source: api.InstanceSource{
+			Type:              api.SourceTypeMigration,
+			Mode:              "push",
+			Refresh:           refresh,
+			AllowInconsistent: true,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("Failed requesting replica creation on target: %w", err)
+	}
+
+	destOpAPI := destOp.Get()
+
+	srcMigration, err := newMigrationSource(inst, false, false, true, "", &api.InstancePostTarget{
+		Operation:   targetURL + "/1.0/operations/" + url.PathEscape(destOpAPI.ID),
+		Websockets:  replicationTargetSecrets(destOpAPI.Metadata),
+		Certificate: targetCert,
+	})
+	if err != nil {
+		_ = destOp.Cancel()
+		return fmt.Errorf("Failed setting up instance migration on source: %w", err)
+	}
+
+	err = srcMigration.Do(s, op)
+	if err != nil {
+		_ = destOp.Cancel()
+		return fmt.Errorf("Instance replication failed on source: %w", err)
+	}
+
+	err = destOp.Wait()
+	if err != nil {
+		return fmt.Errorf("Instance replication failed on target: %w", err)
+	}
+
+	return nil
+}
+
+// instanceReplicateVolume copies a custom volume and its snapshots to the replication target.
+// The first replication creates the replica and later ones refresh it. Replicas that have been promoted are
+// left alone.
+func instanceReplicateVolume(s *state.State, dest lxd.InstanceServer, targetURL string, targetCert string, pool storagePools.Pool, projectName string, volName string, op *operations.Operation) error {
+	dbVol, err := storagePools.VolumeDBGet(pool, projectName, volName, storageDrivers.VolumeTypeCustom)
+	if err != nil {
+		return err
+	}
+
+	replicaSource := s.ServerName + "/" + projectName + "/" + pool.Name() + "/" + volName
+
+	// Check for an existing replica.
+	replica, _, err := dest.GetStoragePoolVolume(pool.Name(), dbCluster.StoragePoolVolumeTypeNameCustom, volName)
+	if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return fmt.Errorf("Failed checking for existing replica: %w", err)
+	}
+
+	var replicaConfig map[string]string
+	if replica != nil {
+		replicaConfig = replica.Config
+	}
+
+	refresh, skip, err := replicaState(replica != nil, replicaConfig, replicaSource)
+	if err != nil {
+		return fmt.Errorf("Invalid storage volume on the replication target: %w", err)
+	}
+
+	if skip {
+		logger.Warn("Skipping replication as the replica has been promoted", logger.Ctx{"volume": volName, "project": projectName, "pool": pool.Name()})
+		return nil
+	}
+
+	// Volatile keys are specific to the source volume.
+	config := make(map[string]string, len(dbVol.Config)+1)
+	for k, v := range dbVol.Config {
+		if !strings.HasPrefix(k, "volatile.") {
+			config[k] = v
+		}
+	}
+
+	config[instanceReplicaSourceKey] = replicaSource
+
+	destOp, err := dest.CreateStoragePoolVolume(pool.Name(), api.StorageVolumesPost{
+		Name:        volName,
+		Type:        dbCluster.StoragePoolVolumeTypeNameCustom,
+		ContentType: dbVol.ContentType,
+		StorageVolumePut: api.StorageVolumePut{
+			Config:      config,
+			Description: dbVol.Description,
+		},
+		Source: api.StorageVolumeSource{
+			Type:    api.SourceTypeMigration,
+			Mode:    "push",
+			Refresh: refresh,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("Failed requesting replica creation on target: %w", err)
+	}
+
+	destOpAPI := destOp.Get()
+
+	srcMigration, err := newStorageMigrationSource(false, &api.StorageVolumePostTarget{
+		Operation:   targetURL + "/1.0/operations/" + url.PathEscape(destOpAPI.ID),
+		Websockets:  replicationTargetSecrets(destOpAPI.Metadata),
+		Certificate: targetCert,
+	})
+	if err != nil {
+		_ = destOp.Cancel()
+		return fmt.Errorf("Failed setting up storage volume migration on source: %w", err)
+	}
+
+	err = srcMigration.DoStorage(s, projectName, pool.Name(), volName, op)
+	if err != nil {
+		_ = destOp.Cancel()
+		return fmt.Errorf("Storage volume replication failed on source: %w", err)
+	}
+
+	err = destOp.Wait()
+	if err != nil {
+		return fmt.Errorf("Storage volume replication failed on target: %w", err)
+	}
+
+	return nil
+}
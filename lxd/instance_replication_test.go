@@ -0,0 +1,80 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	deviceConfig "github.com/canonical/lxd/lxd/device/config"
+)
+
+func Test_instanceReplicaVolumes(t *testing.T) {
+	devices := deviceConfig.Devices{
+		"root":  {"type": "disk", "path": "/", "pool": "default"},
+		"data":  {"type": "disk", "path": "/data", "pool": "default", "source": "data"},
+		"block": {"type": "disk", "pool": "fast", "source": "block"},
+		"again": {"type": "disk", "path": "/again", "pool": "default", "source": "data"},
+		"snap":  {"type": "disk", "path": "/snap", "pool": "fast", "source": "snap", "source.snapshot": "snap0"},
+		"other": {"type": "disk", "path": "/other", "pool": "default", "source": "other", "source.type": "custom"},
+		"vm":    {"type": "disk", "pool": "default", "source": "v1", "source.type": "virtual-machine"},
+		"host":  {"type": "disk", "path": "/host", "source": "/srv/host"},
+		"eth0":  {"type": "nic", "network": "lxdbr0"},
+	}
+
+	want := []instanceReplicaVolume{
+		{pool: "fast", name: "block"},
+		{pool: "default", name: "data"},
+		{pool: "default", name: "other"},
+		{pool: "fast", name: "snap"},
+	}
+
+	assert.Equal(t, want, instanceReplicaVolumes(devices))
+	assert.Empty(t, instanceReplicaVolumes(deviceConfig.Devices{"root": {"type": "disk", "path": "/", "pool": "default"}}))
+}
+
+func Test_replicaState(t *testing.T) {
+	tests := []struct {
+		name        string
+		found       bool
+		config      map[string]string
+		wantRefresh bool
+		wantSkip    bool
+		wantErr     bool
+	}{
+		{
+			name: "No replica",
+		},
+		{
+			name:        "Replica",
+			found:       true,
+			config:      map[string]string{instanceReplicaSourceKey: "lxd01/default/c1"},
+			wantRefresh: true,
+		},
+		{
+			name:     "Promoted replica",
+			found:    true,
+			config:   map[string]string{"limits.cpu": "2"},
+			wantSkip: true,
+		},
+		{
+			name:    "Replica of another source",
+			found:   true,
+			config:  map[string]string{instanceReplicaSourceKey: "lxd02/default/c1"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refresh, skip, err := replicaState(tt.found, tt.config, "lxd01/default/c1")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantRefresh, refresh)
+			assert.Equal(t, tt.wantSkip, skip)
+		})
+	}
+}
@@ -68,6 +68,18 @@ var instanceUEFIVarsCmd = APIEndpoint{
 	Put: APIEndpointAction{Handler: instanceUEFIVarsPut, AccessHandler: allowPermission(entity.TypeInstance, auth.EntitlementCanEdit, "name")},
 }
 
+var instancePromoteCmd = APIEndpoint{
+	Name:        "instancePromote",
+	Path:        "instances/{name}/promote",
+	MetricsType: entity.TypeInstance,
+	Aliases: []APIEndpointAlias{
+		{Name: "containerPromote", Path: "containers/{name}/promote"},
+		{Name: "vmPromote", Path: "virtual-machines/{name}/promote"},
+	},
+
+	Post: APIEndpointAction{Handler: instancePromotePost, AccessHandler: allowPermission(entity.TypeInstance, auth.EntitlementCanEdit, "name")},
+}
+
 var instanceRebuildCmd = APIEndpoint{
 	Name:        "instanceRebuild",
 	Path:        "instances/{name}/rebuild",
@@ -242,6 +254,18 @@ var instanceBackupExportCmd = APIEndpoint{
 	Get: APIEndpointAction{Handler: instanceBackupExportGet, AccessHandler: allowPermission(entity.TypeInstanceBackup, auth.EntitlementCanView, "name", "backupName")},
 }
 
+var instanceBackupInfoCmd = APIEndpoint{
+	Name:        "instanceBackupInfo",
+	Path:        "instances/{name}/backups/{backupName}/info",
+	MetricsType: entity.TypeInstance,
+	Aliases: []APIEndpointAlias{
+		{Name: "containerBackupInfo", Path: "containers/{name}/backups/{backupName}/info"},
+		{Name: "vmBackupInfo", Path: "virtual-machines/{name}/backups/{backupName}/info"},
+	},
+
+	Get: APIEndpointAction{Handler: instanceBackupInfoGet, AccessHandler: allowPermission(entity.TypeInstanceBackup, auth.EntitlementCanView, "name", "backupName")},
+}
+
 type instanceAutostartList []instance.Instance
 
 func (slice instanceAutostartList) Len() int {
@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"mime"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"os"
@@ -648,7 +650,10 @@ func createFromCopy(ctx context.Context, s *state.State, projectName string, pro
 	return operations.OperationResponse(op)
 }
 
-func createFromBackup(s *state.State, r *http.Request, projectName string, data io.Reader, pool string, instanceName string, devices map[string]map[string]string) response.Response {
+// createFromBackupSpool stores uploaded backup data in a temporary file, converting squashfs backups to a tarball.
+// Encrypted backups are decrypted with the given keys.
+// The returned file is already unlinked and must be closed by the caller.
+func createFromBackupSpool(s *state.State, projectName string, data io.Reader, dec *backup.Decryption) (*os.File, error) {
 	revert := revert.New()
 	defer revert.Fail()
 
@@ -657,27 +662,32 @@ func createFromBackup(s *state.State, r *http.Request, projectName string, data
 	// Create temporary file to store uploaded backup data.
 	backupFile, err := os.CreateTemp(backupsPath, backup.WorkingDirPrefix+"_")
 	if err != nil {
-		return response.InternalError(err)
+		return nil, err
 	}
 
 	defer func() { _ = os.Remove(backupFile.Name()) }()
 	revert.Add(func() { _ = backupFile.Close() })
 
-	// Stream uploaded backup data into temporary file.
+	// Stream uploaded backup data into temporary file, decrypting it if needed.
+	data, err = backup.DecryptReader(data, dec)
+	if err != nil {
+		return nil, err
+	}
+
 	_, err = io.Copy(backupFile, data)
 	if err != nil {
-		return response.InternalError(err)
+		return nil, err
 	}
 
 	// Detect squashfs compression and convert to tarball.
 	_, err = backupFile.Seek(0, io.SeekStart)
 	if err != nil {
-		return response.InternalError(err)
+		return nil, err
 	}
 
 	_, algo, decomArgs, err := shared.DetectCompressionFile(backupFile)
 	if err != nil {
-		return response.InternalError(err)
+		return nil, err
 	}
 
 	if algo == ".squashfs" {
@@ -687,25 +697,60 @@ func createFromBackup(s *state.State, r *http.Request, projectName string, data
 		// Create temporary file to store the decompressed tarball in.
 		tarFile, err := os.CreateTemp(backupsPath, backup.WorkingDirPrefix+"_decompress_")
 		if err != nil {
-			return response.InternalError(err)
+			return nil, err
 		}
 
 		defer func() { _ = os.Remove(tarFile.Name()) }()
+		revert.Add(func() { _ = tarFile.Close() })
 
 		// Decompress to tarFile temporary file.
 		err = archive.ExtractWithFds(s, decomArgs[0], decomArgs[1:], nil, nil, tarFile)
 		if err != nil {
-			return response.InternalError(err)
+			return nil, err
 		}
 
 		// We don't need the original squashfs file anymore.
 		_ = backupFile.Close()
-		_ = os.Remove(backupFile.Name())
 
 		// Replace the backup file handle with the handle to the tar file.
 		backupFile = tarFile
 	}
 
+	revert.Success()
+	return backupFile, nil
+}
+
+// createFromBackup creates an instance from uploaded backup data.
+// For incremental backups, parentFiles holds the spooled parent backups, oldest first.
+// The parent files are closed once the restore has finished.
+// If the request has the X-LXD-dry-run header set, the backup is only verified and its contents returned.
+func createFromBackup(s *state.State, r *http.Request, projectName string, data io.Reader, parentFiles []*os.File, pool string, instanceName string, devices map[string]map[string]string) response.Response {
+	revert := revert.New()
+	defer revert.Fail()
+
+	for _, parentFile := range parentFiles {
+		revert.Add(func() { _ = parentFile.Close() })
+	}
+
+	dec, err := backupDecryption(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	backupFile, err := createFromBackupSpool(s, projectName, data, dec)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	revert.Add(func() { _ = backupFile.Close() })
+
+	// Verify the integrity of the backup before any storage is touched.
+	logger.Debug("Verifying backup file")
+	verified, err := backup.Verify(s, backupFile, backupFile.Name())
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Backup verification failed: %w", err))
+	}
+
 	// Parse the backup information.
 	_, err = backupFile.Seek(0, io.SeekStart)
 	if err != nil {
@@ -726,6 +771,38 @@ func createFromBackup(s *state.State, r *http.Request, projectName string, data
 		return response.BadRequest(errors.New("Instance definition in backup config is missing"))
 	}
 
+	// Incremental backups can only be restored together with the chain of parent backups.
+	if bInfo.Parent != "" || len(parentFiles) > 0 {
+		chain := make([]*backup.Info, 0, len(parentFiles)+1)
+		for _, parentFile := range parentFiles {
+			parentVerified, err := backup.Verify(s, parentFile, parentFile.Name())
+			if err != nil {
+				return response.BadRequest(fmt.Errorf("Parent backup verification failed: %w", err))
+			}
+
+			verified = verified && parentVerified
+
+			parentInfo, err := backup.GetInfo(s, parentFile, parentFile.Name())
+			if err != nil {
+				return response.BadRequest(fmt.Errorf("Failed reading parent backup: %w", err))
+			}
+
+			bInfo.Parents = append(bInfo.Parents, backup.ParentBackup{Info: parentInfo, Data: parentFile})
+			chain = append(chain, parentInfo)
+		}
+
+		chain = append(chain, bInfo)
+		err = backup.ValidateChain(chain)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+	}
+
+	// For dry runs, only report the backup's contents.
+	if shared.IsTrue(r.Header.Get("X-LXD-dry-run")) {
+		return response.SyncResponse(true, bInfo.ToAPI(verified))
+	}
+
 	// Check project permissions.
 	var req api.InstancesPost
 	err = s.DB.Cluster.Transaction(s.ShutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
@@ -779,6 +856,7 @@ func createFromBackup(s *state.State, r *http.Request, projectName string, data
 		"pool":      bInfo.Pool,
 		"optimized": *bInfo.OptimizedStorage,
 		"snapshots": bInfo.Snapshots,
+		"parent":    bInfo.Parent,
 	})
 
 	err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
@@ -823,6 +901,12 @@ func createFromBackup(s *state.State, r *http.Request, projectName string, data
 
 	run := func(_ *operations.Operation) error {
 		defer func() { _ = backupFile.Close() }()
+		defer func() {
+			for _, parentFile := range parentFiles {
+				_ = parentFile.Close()
+			}
+		}()
+
 		defer runRevert.Fail()
 
 		pool, err := storagePools.LoadByName(s, bInfo.Pool)
@@ -948,6 +1032,81 @@ func setupInstanceArgs(s *state.State, instType instancetype.Type, projectName s
 	return storagePool, &args, nil
 }
 
+// createFromBackupChain creates an instance from an incremental backup uploaded as multipart data.
+// The request contains a "parent" part for each parent backup, oldest first, followed by a "backup" part.
+func createFromBackupChain(s *state.State, r *http.Request, projectName string, boundary string, pool string, instanceName string, devices map[string]map[string]string) response.Response {
+	revert := revert.New()
+	defer revert.Fail()
+
+	dec, err := backupDecryption(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var parentFiles []*os.File
+	mr := multipart.NewReader(r.Body, boundary)
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return response.BadRequest(errors.New("Missing backup in multipart request"))
+		}
+
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		switch part.FormName() {
+		case "parent":
+			parentFile, err := createFromBackupSpool(s, projectName, part, dec)
+			if err != nil {
+				return response.SmartError(err)
+			}
+
+			revert.Add(func() { _ = parentFile.Close() })
+			parentFiles = append(parentFiles, parentFile)
+		case "backup":
+			// The parent files are now owned by createFromBackup.
+			revert.Success()
+			return createFromBackup(s, r, projectName, part, parentFiles, pool, instanceName, devices)
+		default:
+			return response.BadRequest(fmt.Errorf("Invalid multipart backup part %q", part.FormName()))
+		}
+	}
+}
+
+// createFromBackupTarget creates an instance from a backup object stored on a backup target.
+func createFromBackupTarget(s *state.State, r *http.Request, projectName string, req *api.InstancesPost) response.Response {
+	if req.Source.BackupTarget == "" || req.Source.BackupObject == "" {
+		return response.BadRequest(errors.New("Must specify a backup target and backup object"))
+	}
+
+	target, err := backupTargetLoad(r.Context(), s, req.Source.BackupTarget)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Only allow restoring backups that were pushed from the same project.
+	if !target.InProject(projectName, req.Source.BackupObject) {
+		return response.NotFound(fmt.Errorf("Backup %q not found on backup target %q", req.Source.BackupObject, target.Name))
+	}
+
+	data, err := target.Open(r.Context(), req.Source.BackupObject)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	defer func() { _ = data.Close() }()
+
+	// Allow the storage pool to be overridden through the root disk device.
+	var pool string
+	_, rootDev, err := instancetype.GetRootDiskDevice(req.Devices)
+	if err == nil {
+		pool = rootDev["pool"]
+	}
+
+	return createFromBackup(s, r, projectName, data, nil, pool, req.Name, req.Devices)
+}
+
 // swagger:operation POST /1.0/instances instances instances_post
 //
 //	Create a new instance
@@ -961,6 +1120,7 @@ func setupInstanceArgs(s *state.State, instType instancetype.Type, projectName s
 //	consumes:
 //	  - application/json
 //	  - application/octet-stream
+//	  - multipart/form-data
 //	produces:
 //	  - application/json
 //	parameters:
@@ -1008,7 +1168,8 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 	logger.Debug("Responding to instance create")
 
 	// If we're getting binary content, process separately
-	if r.Header.Get("Content-Type") == "application/octet-stream" {
+	contentType, contentTypeParams, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if contentType == "application/octet-stream" || contentType == "multipart/form-data" {
 		deviceMap := map[string]map[string]string{}
 
 		if r.Header.Get("X-LXD-devices") != "" {
@@ -1031,7 +1192,12 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 			}
 		}
 
-		return createFromBackup(s, r, targetProjectName, r.Body, r.Header.Get("X-LXD-pool"), r.Header.Get("X-LXD-name"), deviceMap)
+		// Incremental backups are uploaded as multipart data along with their parent backups.
+		if contentType == "multipart/form-data" {
+			return createFromBackupChain(s, r, targetProjectName, contentTypeParams["boundary"], r.Header.Get("X-LXD-pool"), r.Header.Get("X-LXD-name"), deviceMap)
+		}
+
+		return createFromBackup(s, r, targetProjectName, r.Body, nil, r.Header.Get("X-LXD-pool"), r.Header.Get("X-LXD-name"), deviceMap)
 	}
 
 	// Parse the request
@@ -1041,6 +1207,11 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	// Restoring from a backup target is handled the same way as an uploaded backup file.
+	if req.Source.Type == api.SourceTypeBackup {
+		return createFromBackupTarget(s, r, targetProjectName, &req)
+	}
+
 	// Set type from URL if missing
 	urlType, err := urlInstanceTypeDetect(r)
 	if err != nil {
@@ -4,6 +4,8 @@ package instancewriter
 
 import (
 	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"os"
@@ -14,11 +16,19 @@ import (
 	"github.com/canonical/lxd/shared/logger"
 )
 
+// FileChecksum represents the checksum of a regular file written to the tarball.
+type FileChecksum struct {
+	Name   string
+	SHA256 string
+}
+
 // InstanceTarWriter provides a TarWriter implementation that handles ID shifting and hardlink tracking.
 type InstanceTarWriter struct {
-	tarWriter *tar.Writer
-	idmapSet  *idmap.IdmapSet
-	linkMap   map[uint64]string
+	tarWriter       *tar.Writer
+	idmapSet        *idmap.IdmapSet
+	linkMap         map[uint64]string
+	recordChecksums bool
+	checksums       []FileChecksum
 }
 
 // NewInstanceTarWriter returns a ContainerTarWriter for the provided target Writer and id map.
@@ -36,6 +46,34 @@ func (ctw *InstanceTarWriter) ResetHardLinkMap() {
 	ctw.linkMap = map[uint64]string{}
 }
 
+// EnableChecksums makes the writer record the checksums of the regular files written to the tarball from now on.
+func (ctw *InstanceTarWriter) EnableChecksums() {
+	ctw.recordChecksums = true
+}
+
+// Checksums returns the checksums of the regular files written to the tarball so far, in write order.
+// Checksums are only recorded once enabled with EnableChecksums.
+func (ctw *InstanceTarWriter) Checksums() []FileChecksum {
+	return ctw.checksums
+}
+
+// writeContent copies the content of a regular file into the tarball and records its checksum if enabled.
+func (ctw *InstanceTarWriter) writeContent(name string, r io.Reader) error {
+	if !ctw.recordChecksums {
+		_, err := io.Copy(ctw.tarWriter, r)
+		return err
+	}
+
+	h := sha256.New()
+	_, err := io.Copy(io.MultiWriter(ctw.tarWriter, h), r)
+	if err != nil {
+		return err
+	}
+
+	ctw.checksums = append(ctw.checksums, FileChecksum{Name: name, SHA256: hex.EncodeToString(h.Sum(nil))})
+	return nil
+}
+
 // WriteFile adds a file to the tarball with the specified name using the srcPath file as the contents of the file.
 // The ignoreGrowth argument indicates whether to error if the srcPath file increases in size beyond the size in fi
 // during the write. If false the write will return an error. If true, no error is returned, instead only the size
@@ -158,7 +196,7 @@ func (ctw *InstanceTarWriter) WriteFile(name string, srcPath string, fi os.FileI
 			r = io.LimitReader(r, fi.Size())
 		}
 
-		_, err = io.Copy(ctw.tarWriter, r)
+		err = ctw.writeContent(hdr.Name, r)
 		if err != nil {
 			return fmt.Errorf("Failed to copy file content %q: %w", srcPath, err)
 		}
@@ -185,8 +223,12 @@ func (ctw *InstanceTarWriter) WriteFileFromReader(src io.Reader, fi os.FileInfo)
 		return fmt.Errorf("Failed to write tar header: %w", err)
 	}
 
-	_, err = io.Copy(ctw.tarWriter, src)
-	return err
+	if hdr.Typeflag != tar.TypeReg {
+		_, err = io.Copy(ctw.tarWriter, src)
+		return err
+	}
+
+	return ctw.writeContent(hdr.Name, src)
 }
 
 // Close finishes writing the tarball.
@@ -0,0 +1,177 @@
+package ip
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"golang.zx2c4.com/wireguard/wgctrl"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+)
+
+// Wireguard represents arguments for link of type wireguard.
+type Wireguard struct {
+	Link
+	PrivateKey string
+	ListenPort uint64
+	Peers      []WireguardPeer
+}
+
+// WireguardPeer represents a remote peer of a wireguard link.
+type WireguardPeer struct {
+	PublicKey  string
+	Endpoint   string
+	AllowedIPs []string
+}
+
+// WireguardState represents the runtime state of a wireguard link.
+type WireguardState struct {
+	PublicKey  string
+	ListenPort uint64
+	Peers      map[string]WireguardPeerState // Keyed by public key.
+}
+
+// WireguardPeerState represents the runtime state of a remote peer of a wireguard link.
+type WireguardPeerState struct {
+	Endpoint        string
+	LatestHandshake time.Time
+	BytesReceived   uint64
+	BytesSent       uint64
+}
+
+// Add adds new virtual link.
+func (w *Wireguard) Add() error {
+	return w.add("wireguard", nil)
+}
+
+// config returns the device configuration of the link, replacing any existing peers.
+func (w *Wireguard) config() (*wgtypes.Config, error) {
+	privateKey, err := wgtypes.ParseKey(w.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid wireguard private key: %w", err)
+	}
+
+	config := &wgtypes.Config{
+		PrivateKey:   &privateKey,
+		ReplacePeers: true,
+		Peers:        make([]wgtypes.PeerConfig, 0, len(w.Peers)),
+	}
+
+	if w.ListenPort > 0 {
+		listenPort := int(w.ListenPort)
+		config.ListenPort = &listenPort
+	}
+
+	for _, peer := range w.Peers {
+		publicKey, err := wgtypes.ParseKey(peer.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid wireguard public key %q: %w", peer.PublicKey, err)
+		}
+
+		peerConfig := wgtypes.PeerConfig{
+			PublicKey:         publicKey,
+			ReplaceAllowedIPs: true,
+			AllowedIPs:        make([]net.IPNet, 0, len(peer.AllowedIPs)),
+		}
+
+		if peer.Endpoint != "" {
+			peerConfig.Endpoint, err = net.ResolveUDPAddr("udp", peer.Endpoint)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid wireguard endpoint %q: %w", peer.Endpoint, err)
+			}
+		}
+
+		for _, allowedIP := range peer.AllowedIPs {
+			_, subnet, err := net.ParseCIDR(allowedIP)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid wireguard allowed IP %q: %w", allowedIP, err)
+			}
+
+			peerConfig.AllowedIPs = append(peerConfig.AllowedIPs, *subnet)
+		}
+
+		config.Peers = append(config.Peers, peerConfig)
+	}
+
+	return config, nil
+}
+
+// Configure sets the private key and listen port of the link and replaces its peers.
+func (w *Wireguard) Configure() error {
+	config, err := w.config()
+	if err != nil {
+		return err
+	}
+
+	client, err := wgctrl.New()
+	if err != nil {
+		return fmt.Errorf("Failed connecting to wireguard: %w", err)
+	}
+
+	defer func() { _ = client.Close() }()
+
+	err = client.ConfigureDevice(w.Name, *config)
+	if err != nil {
+		return fmt.Errorf("Failed configuring wireguard link: %w", err)
+	}
+
+	return nil
+}
+
+// State returns the runtime state of the link.
+// The returned error matches os.ErrNotExist if the link doesn't exist.
+func (w *Wireguard) State() (*WireguardState, error) {
+	client, err := wgctrl.New()
+	if err != nil {
+		return nil, fmt.Errorf("Failed connecting to wireguard: %w", err)
+	}
+
+	defer func() { _ = client.Close() }()
+
+	device, err := client.Device(w.Name)
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting wireguard link state: %w", err)
+	}
+
+	state := &WireguardState{
+		PublicKey:  device.PublicKey.String(),
+		ListenPort: uint64(device.ListenPort),
+		Peers:      make(map[string]WireguardPeerState, len(device.Peers)),
+	}
+
+	for _, peer := range device.Peers {
+		peerState := WireguardPeerState{
+			LatestHandshake: peer.LastHandshakeTime,
+			BytesReceived:   uint64(peer.ReceiveBytes),
+			BytesSent:       uint64(peer.TransmitBytes),
+		}
+
+		if peer.Endpoint != nil {
+			peerState.Endpoint = peer.Endpoint.String()
+		}
+
+		state.Peers[peer.PublicKey.String()] = peerState
+	}
+
+	return state, nil
+}
+
+// WireguardGenerateKey generates a new wireguard private key.
+func WireguardGenerateKey() (string, error) {
+	key, err := wgtypes.GeneratePrivateKey()
+	if err != nil {
+		return "", fmt.Errorf("Failed generating wireguard key: %w", err)
+	}
+
+	return key.String(), nil
+}
+
+// WireguardPublicKey returns the public key of a wireguard private key.
+func WireguardPublicKey(privateKey string) (string, error) {
+	key, err := wgtypes.ParseKey(privateKey)
+	if err != nil {
+		return "", fmt.Errorf("Invalid wireguard private key: %w", err)
+	}
+
+	return key.PublicKey().String(), nil
+}
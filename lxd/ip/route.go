@@ -29,7 +29,11 @@ func (r *Route) Add() error {
 		cmd = append(cmd, "via", r.Via)
 	}
 
-	cmd = append(cmd, r.Route, "dev", r.DevName)
+	cmd = append(cmd, r.Route)
+	if r.DevName != "" {
+		cmd = append(cmd, "dev", r.DevName)
+	}
+
 	if r.Src != "" {
 		cmd = append(cmd, "src", r.Src)
 	}
@@ -72,7 +76,14 @@ func (r *Route) Flush() error {
 		cmd = append(cmd, "via", r.Via)
 	}
 
-	cmd = append(cmd, "dev", r.DevName)
+	if r.DevName != "" {
+		cmd = append(cmd, "dev", r.DevName)
+	}
+
+	if r.Table != "" {
+		cmd = append(cmd, "table", r.Table)
+	}
+
 	if r.Proto != "" {
 		cmd = append(cmd, "proto", r.Proto)
 	}
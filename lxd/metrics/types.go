@@ -146,8 +146,16 @@ const (
 	OperationsTotal
 	// ProcsTotal represents the number of running processes.
 	ProcsTotal
+	// ProjectDiskProvisionedBytes represents the provisioned size of a project's volumes on a storage pool.
+	ProjectDiskProvisionedBytes
+	// ProjectDiskUsedBytes represents the space used by a project's volumes on a storage pool.
+	ProjectDiskUsedBytes
 	// UptimeSeconds represents the daemon uptime in seconds.
 	UptimeSeconds
+	// VolumeProvisionedBytes represents the provisioned size of a storage volume.
+	VolumeProvisionedBytes
+	// VolumeUsedBytes represents the space used by a storage volume.
+	VolumeUsedBytes
 	// WarningsTotal represents the number of active warnings.
 	WarningsTotal
 )
@@ -218,7 +226,11 @@ var MetricNames = map[MetricType]string{
 	NetworkTransmitPacketsTotal: "lxd_network_transmit_packets_total",
 	OperationsTotal:             "lxd_operations_total",
 	ProcsTotal:                  "lxd_procs_total",
+	ProjectDiskProvisionedBytes: "lxd_project_disk_provisioned_bytes",
+	ProjectDiskUsedBytes:        "lxd_project_disk_used_bytes",
 	UptimeSeconds:               "lxd_uptime_seconds",
+	VolumeProvisionedBytes:      "lxd_volume_provisioned_bytes",
+	VolumeUsedBytes:             "lxd_volume_used_bytes",
 	WarningsTotal:               "lxd_warnings_total",
 	Instances:                   "lxd_instances",
 }
@@ -289,7 +301,11 @@ var MetricHeaders = map[MetricType]string{
 	NetworkTransmitPacketsTotal: "# HELP lxd_network_transmit_packets_total The amount of transmitted packets on a given interface.",
 	OperationsTotal:             "# HELP lxd_operations_total The number of running operations",
 	ProcsTotal:                  "# HELP lxd_procs_total The number of running processes.",
+	ProjectDiskProvisionedBytes: "# HELP lxd_project_disk_provisioned_bytes The provisioned size in bytes of the project's volumes on a storage pool.",
+	ProjectDiskUsedBytes:        "# HELP lxd_project_disk_used_bytes The space used in bytes by the project's volumes and snapshots on a storage pool.",
 	UptimeSeconds:               "# HELP lxd_uptime_seconds The daemon uptime in seconds.",
+	VolumeProvisionedBytes:      "# HELP lxd_volume_provisioned_bytes The provisioned size of the storage volume in bytes.",
+	VolumeUsedBytes:             "# HELP lxd_volume_used_bytes The space used by the storage volume in bytes.",
 	WarningsTotal:               "# HELP lxd_warnings_total The number of active warnings.",
 	Instances:                   "# HELP lxd_instances The number of instances.",
 }
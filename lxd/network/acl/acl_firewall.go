@@ -3,6 +3,9 @@ package acl
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/canonical/lxd/lxd/db"
 	firewallDrivers "github.com/canonical/lxd/lxd/firewall/drivers"
@@ -17,24 +20,82 @@ func FirewallApplyACLRules(s *state.State, logger logger.Logger, aclProjectName
 	var dropRules []firewallDrivers.ACLRule
 	var rejectRules []firewallDrivers.ACLRule
 	var allowRules []firewallDrivers.ACLRule
+	var addressSetIDs map[string]int64
+
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		// Get map of address set names to DB IDs (used for generating firewall set names).
+		addressSetIDs, err = tx.GetNetworkAddressSetIDsByNames(ctx, aclProjectName)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed getting network address sets for network %q: %w", aclNet.Name, err)
+	}
+
+	// Names of the address sets referenced by the rules.
+	var addressSetNames []string
+
+	// convertSubjects replaces the address set references with the name of their firewall sets.
+	convertSubjects := func(subjects string) (string, error) {
+		if !strings.Contains(subjects, addressSetSubjectPrefix) {
+			return subjects, nil
+		}
+
+		converted := shared.SplitNTrimSpace(subjects, ",", -1, false)
+		for i, subject := range converted {
+			addressSetName, found := strings.CutPrefix(subject, addressSetSubjectPrefix)
+			if !found {
+				continue
+			}
+
+			addressSetID, found := addressSetIDs[addressSetName]
+			if !found {
+				return "", fmt.Errorf("Cannot find address set ID for %q", addressSetName)
+			}
+
+			if !slices.Contains(addressSetNames, addressSetName) {
+				addressSetNames = append(addressSetNames, addressSetName)
+			}
+
+			converted[i] = addressSetSubjectPrefix + firewallAddressSetName(addressSetID)
+		}
+
+		return strings.Join(converted, ","), nil
+	}
+
+	now := time.Now()
 
 	// convertACLRules converts the ACL rules to Firewall ACL rules.
-	convertACLRules := func(direction string, logPrefix string, rules ...api.NetworkACLRule) error {
+	// Disabled rules and rules outside of their time bounds are skipped.
+	convertACLRules := func(aclID int64, direction string, logPrefix string, rules ...api.NetworkACLRule) error {
 		for ruleIndex, rule := range rules {
-			if rule.State == "disabled" {
+			if rule.State == "disabled" || !ruleActive(rule, now) {
 				continue
 			}
 
+			source, err := convertSubjects(rule.Source)
+			if err != nil {
+				return err
+			}
+
+			destination, err := convertSubjects(rule.Destination)
+			if err != nil {
+				return err
+			}
+
 			firewallACLRule := firewallDrivers.ACLRule{
 				Direction:       direction,
 				Action:          rule.Action,
-				Source:          rule.Source,
-				Destination:     rule.Destination,
+				Source:          source,
+				Destination:     destination,
 				Protocol:        rule.Protocol,
 				SourcePort:      rule.SourcePort,
 				DestinationPort: rule.DestinationPort,
 				ICMPType:        rule.ICMPType,
 				ICMPCode:        rule.ICMPCode,
+				Comment:         ruleName(aclID, direction, ruleIndex),
 			}
 
 			if rule.State == "logged" {
@@ -62,12 +123,13 @@ func FirewallApplyACLRules(s *state.State, logger logger.Logger, aclProjectName
 
 	// Load ACLs specified by network.
 	for _, aclName := range shared.SplitNTrimSpace(aclNet.Config["security.acls"], ",", -1, true) {
+		var aclID int64
 		var aclInfo *api.NetworkACL
 
 		err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 			var err error
 
-			_, aclInfo, err = tx.GetNetworkACL(ctx, aclProjectName, aclName)
+			aclID, aclInfo, err = tx.GetNetworkACL(ctx, aclProjectName, aclName)
 
 			return err
 		})
@@ -75,17 +137,46 @@ func FirewallApplyACLRules(s *state.State, logger logger.Logger, aclProjectName
 			return fmt.Errorf("Failed loading ACL %q for network %q: %w", aclName, aclNet.Name, err)
 		}
 
-		err = convertACLRules("ingress", logPrefix, aclInfo.Ingress...)
+		err = convertACLRules(aclID, "ingress", logPrefix, aclInfo.Ingress...)
 		if err != nil {
 			return fmt.Errorf("Failed converting ACL %q ingress rules for network %q: %w", aclInfo.Name, aclNet.Name, err)
 		}
 
-		err = convertACLRules("egress", logPrefix, aclInfo.Egress...)
+		err = convertACLRules(aclID, "egress", logPrefix, aclInfo.Egress...)
 		if err != nil {
 			return fmt.Errorf("Failed converting ACL %q egress rules for network %q: %w", aclInfo.Name, aclNet.Name, err)
 		}
 	}
 
+	// Create or refresh the firewall sets of the referenced address sets before the rules using them.
+	if len(addressSetNames) > 0 {
+		addressSets := make([]firewallDrivers.AddressSet, 0, len(addressSetNames))
+
+		err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			for _, addressSetName := range addressSetNames {
+				addressSetID, addressSetInfo, err := tx.GetNetworkAddressSet(ctx, aclProjectName, addressSetName)
+				if err != nil {
+					return fmt.Errorf("Failed loading address set %q: %w", addressSetName, err)
+				}
+
+				addressSets = append(addressSets, firewallDrivers.AddressSet{
+					Name:      firewallAddressSetName(addressSetID),
+					Addresses: addressSetInfo.Addresses,
+				})
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		err = s.Firewall.NetworkApplyAddressSets(addressSets)
+		if err != nil {
+			return fmt.Errorf("Failed applying address sets for network %q: %w", aclNet.Name, err)
+		}
+	}
+
 	var rules []firewallDrivers.ACLRule
 	rules = append(rules, dropRules...)
 	rules = append(rules, rejectRules...)
@@ -129,3 +220,26 @@ func firewallACLDefaults(netConfig map[string]string, direction string) (string,
 
 	return defaults[fmt.Sprintf("security.acls.default.%s.action", direction)], shared.IsTrue(defaults[fmt.Sprintf("security.acls.default.%s.logged", direction)])
 }
+
+// firewallACLRuleCounters adds the counters of the ACL rules applied to the network's firewall on this member
+// to counters.
+func firewallACLRuleCounters(s *state.State, aclNet NetworkACLUsage, counters map[string]api.NetworkACLRuleState) error {
+	// The rules only exist on the members where the network is started.
+	if !shared.PathExists("/sys/class/net/" + aclNet.Name) {
+		return nil
+	}
+
+	netCounters, err := s.Firewall.NetworkACLRuleCounters(aclNet.Name)
+	if err != nil {
+		return err
+	}
+
+	for name, netRuleCounters := range netCounters {
+		ruleCounters := counters[name]
+		ruleCounters.Packets += netRuleCounters.Packets
+		ruleCounters.Bytes += netRuleCounters.Bytes
+		counters[name] = ruleCounters
+	}
+
+	return nil
+}
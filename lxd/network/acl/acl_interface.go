@@ -22,6 +22,10 @@ type NetworkACL interface {
 
 	// GetLog.
 	GetLog(ctx context.Context, clientType request.ClientType) (string, error)
+	FollowLog(ctx context.Context, clientType request.ClientType, handler func(entry api.NetworkACLLogEntry) error) error
+
+	// State.
+	GetState(clientType request.ClientType) (*api.NetworkACLState, error)
 
 	// Internal validation.
 	validateName(name string) error
@@ -1,6 +1,7 @@
 package acl
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -8,6 +9,8 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"os"
+	"os/exec"
 	"slices"
 	"strconv"
 	"strings"
@@ -47,6 +50,11 @@ func OVNACLPortGroupName(networkACLID int64) openvswitch.OVNPortGroup {
 	return openvswitch.OVNPortGroup(fmt.Sprintf("%s%d", ovnACLPortGroupPrefix, networkACLID))
 }
 
+// ruleName returns the name identifying the rule of a Network ACL in the firewall and in OVN.
+func ruleName(networkACLID int64, direction string, ruleIndex int) string {
+	return fmt.Sprintf("%s-%s-%d", OVNACLPortGroupName(networkACLID), direction, ruleIndex)
+}
+
 // OVNACLNetworkPortGroupName returns the port group name for a Network ACL ID and Network ID.
 func OVNACLNetworkPortGroupName(networkACLID int64, networkID int64) openvswitch.OVNPortGroup {
 	// OVN doesn't match port groups that have a "-" in them. So use an "_" for the separator.
@@ -109,6 +117,16 @@ func OVNEnsureACLs(s *state.State, l logger.Logger, client *openvswitch.OVN, acl
 		return nil, fmt.Errorf("Failed getting peer connection mappings: %w", err)
 	}
 
+	var addressSetIDs map[string]int64
+	err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		addressSetIDs, err = tx.GetNetworkAddressSetIDsByNames(ctx, aclProjectName)
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting network address sets: %w", err)
+	}
+
 	// First check all ACL Names map to IDs in supplied aclNameIDs.
 	for _, aclName := range aclNames {
 		_, found := aclNameIDs[aclName]
@@ -236,6 +254,42 @@ func OVNEnsureACLs(s *state.State, l logger.Logger, client *openvswitch.OVN, acl
 		}
 	}
 
+	// Create or refresh the OVN address sets referenced by the rules we are about to apply, so that the rules
+	// don't reference missing address sets.
+	referencedAddressSets := []string{}
+	for _, aclStatus := range append(createACLPortGroups, existingACLPortGroups...) {
+		if aclStatus.aclInfo == nil {
+			continue
+		}
+
+		for _, addressSetName := range addressSetReferences(aclStatus.aclInfo) {
+			if !slices.Contains(referencedAddressSets, addressSetName) {
+				referencedAddressSets = append(referencedAddressSets, addressSetName)
+			}
+		}
+	}
+
+	for _, addressSetName := range referencedAddressSets {
+		var addressSetID int64
+		var addressSetInfo *api.NetworkAddressSet
+
+		err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			addressSetID, addressSetInfo, err = tx.GetNetworkAddressSet(ctx, aclProjectName, addressSetName)
+
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading address set %q: %w", addressSetName, err)
+		}
+
+		l.Debug("Applying OVN address set", logger.Ctx{"networkAddressSet": addressSetName, "addressSet": OVNAddressSetPrefix(addressSetID)})
+
+		err = ovnAddressSetApply(client, addressSetID, addressSetInfo.Addresses)
+		if err != nil {
+			return nil, fmt.Errorf("Failed applying OVN address set for address set %q: %w", addressSetName, err)
+		}
+	}
+
 	// Create the needed port groups and then apply ACL rules to new port groups.
 	for _, aclStatus := range createACLPortGroups {
 		portGroupName := OVNACLPortGroupName(aclNameIDs[aclStatus.name])
@@ -263,7 +317,7 @@ func OVNEnsureACLs(s *state.State, l logger.Logger, client *openvswitch.OVN, acl
 		}
 
 		// Now apply our ACL rules to port group (and any per-ACL-per-network port groups needed).
-		err = ovnApplyToPortGroup(l, client, aclStatus.aclInfo, portGroupName, aclNameIDs, aclNets, peerTargetNetIDs)
+		err = ovnApplyToPortGroup(l, client, aclStatus.aclInfo, portGroupName, aclNameIDs, aclNets, peerTargetNetIDs, addressSetIDs)
 		if err != nil {
 			return nil, fmt.Errorf("Failed applying ACL rules to port group %q for security ACL %q setup: %w", portGroupName, aclStatus.name, err)
 		}
@@ -293,7 +347,7 @@ func OVNEnsureACLs(s *state.State, l logger.Logger, client *openvswitch.OVN, acl
 		if aclStatus.aclInfo != nil {
 			l.Debug("Applying ACL rules to OVN port group", logger.Ctx{"networkACL": aclStatus.name, "portGroup": portGroupName})
 
-			err := ovnApplyToPortGroup(l, client, aclStatus.aclInfo, portGroupName, aclNameIDs, aclNets, peerTargetNetIDs)
+			err := ovnApplyToPortGroup(l, client, aclStatus.aclInfo, portGroupName, aclNameIDs, aclNets, peerTargetNetIDs, addressSetIDs)
 			if err != nil {
 				return nil, fmt.Errorf("Failed applying ACL rules to port group %q for security ACL %q setup: %w", portGroupName, aclStatus.name, err)
 			}
@@ -318,6 +372,10 @@ func ovnAddReferencedACLs(info *api.NetworkACL, referencedACLNames map[string]st
 				continue // Skip special reserved subjects that are not ACL names.
 			}
 
+			if strings.HasPrefix(subject, addressSetSubjectPrefix) {
+				continue // Skip address set references.
+			}
+
 			if validate.IsNetworkAddressCIDR(subject) == nil || validate.IsNetworkRange(subject) == nil {
 				continue // Skip if the subject is an IP CIDR or IP range.
 			}
@@ -338,27 +396,31 @@ func ovnAddReferencedACLs(info *api.NetworkACL, referencedACLNames map[string]st
 }
 
 // ovnApplyToPortGroup applies the rules in the specified ACL to the specified port group.
-func ovnApplyToPortGroup(l logger.Logger, client *openvswitch.OVN, aclInfo *api.NetworkACL, portGroupName openvswitch.OVNPortGroup, aclNameIDs map[string]int64, aclNets map[string]NetworkACLUsage, peerTargetNetIDs map[db.NetworkPeer]int64) error {
+func ovnApplyToPortGroup(l logger.Logger, client *openvswitch.OVN, aclInfo *api.NetworkACL, portGroupName openvswitch.OVNPortGroup, aclNameIDs map[string]int64, aclNets map[string]NetworkACLUsage, peerTargetNetIDs map[db.NetworkPeer]int64, addressSetIDs map[string]int64) error {
 	// Create slice for port group rules that has the capacity for ingress and egress rules, plus default rule.
 	portGroupRules := make([]openvswitch.OVNACLRule, 0, len(aclInfo.Ingress)+len(aclInfo.Egress)+1)
 	networkRules := make([]openvswitch.OVNACLRule, 0)
 	networkPeersNeeded := make([]db.NetworkPeer, 0)
 
+	now := time.Now()
+
 	// convertACLRules converts the ACL rules to OVN ACL rules.
+	// Disabled rules and rules outside of their time bounds are skipped.
 	convertACLRules := func(direction string, rules ...api.NetworkACLRule) error {
 		for ruleIndex, rule := range rules {
-			if rule.State == "disabled" {
+			if rule.State == "disabled" || !ruleActive(rule, now) {
 				continue
 			}
 
-			ovnACLRule, networkSpecific, networkPeers, err := ovnRuleCriteriaToOVNACLRule(direction, &rule, portGroupName, aclNameIDs, peerTargetNetIDs)
+			ovnACLRule, networkSpecific, networkPeers, err := ovnRuleCriteriaToOVNACLRule(direction, &rule, portGroupName, aclNameIDs, peerTargetNetIDs, addressSetIDs)
 			if err != nil {
 				return err
 			}
 
+			// Always name the rule so its counters can be retrieved.
+			ovnACLRule.LogName = fmt.Sprintf("%s-%s-%d", portGroupName, direction, ruleIndex)
 			if rule.State == "logged" {
 				ovnACLRule.Log = true
-				ovnACLRule.LogName = fmt.Sprintf("%s-%s-%d", portGroupName, direction, ruleIndex)
 			}
 
 			if networkSpecific {
@@ -434,7 +496,7 @@ func ovnApplyToPortGroup(l logger.Logger, client *openvswitch.OVN, aclInfo *api.
 
 // ovnRuleCriteriaToOVNACLRule converts a LXD ACL rule into an OVNACLRule for an OVN port group or network.
 // Returns a bool indicating if any of the rule subjects are network specific.
-func ovnRuleCriteriaToOVNACLRule(direction string, rule *api.NetworkACLRule, portGroupName openvswitch.OVNPortGroup, aclNameIDs map[string]int64, peerTargetNetIDs map[db.NetworkPeer]int64) (openvswitch.OVNACLRule, bool, []db.NetworkPeer, error) {
+func ovnRuleCriteriaToOVNACLRule(direction string, rule *api.NetworkACLRule, portGroupName openvswitch.OVNPortGroup, aclNameIDs map[string]int64, peerTargetNetIDs map[db.NetworkPeer]int64, addressSetIDs map[string]int64) (openvswitch.OVNACLRule, bool, []db.NetworkPeer, error) {
 	networkSpecific := false
 	networkPeersNeeded := make([]db.NetworkPeer, 0)
 	portGroupRule := openvswitch.OVNACLRule{
@@ -468,7 +530,7 @@ func ovnRuleCriteriaToOVNACLRule(direction string, rule *api.NetworkACLRule, por
 
 	// Add subject filters.
 	if rule.Source != "" {
-		match, netSpecificMatch, networkPeers, err := ovnRuleSubjectToOVNACLMatch("src", aclNameIDs, peerTargetNetIDs, shared.SplitNTrimSpace(rule.Source, ",", -1, false)...)
+		match, netSpecificMatch, networkPeers, err := ovnRuleSubjectToOVNACLMatch("src", aclNameIDs, peerTargetNetIDs, addressSetIDs, shared.SplitNTrimSpace(rule.Source, ",", -1, false)...)
 		if err != nil {
 			return openvswitch.OVNACLRule{}, false, nil, err
 		}
@@ -482,7 +544,7 @@ func ovnRuleCriteriaToOVNACLRule(direction string, rule *api.NetworkACLRule, por
 	}
 
 	if rule.Destination != "" {
-		match, netSpecificMatch, networkPeers, err := ovnRuleSubjectToOVNACLMatch("dst", aclNameIDs, peerTargetNetIDs, shared.SplitNTrimSpace(rule.Destination, ",", -1, false)...)
+		match, netSpecificMatch, networkPeers, err := ovnRuleSubjectToOVNACLMatch("dst", aclNameIDs, peerTargetNetIDs, addressSetIDs, shared.SplitNTrimSpace(rule.Destination, ",", -1, false)...)
 		if err != nil {
 			return openvswitch.OVNACLRule{}, false, nil, err
 		}
@@ -543,7 +605,7 @@ func ovnRulePortToOVNACLMatch(protocol string, direction string, portCriteria ..
 
 // ovnRuleSubjectToOVNACLMatch converts direction (src/dst) and subject criteria list into an OVN match statement.
 // Returns a bool indicating if any of the subjects are network specific.
-func ovnRuleSubjectToOVNACLMatch(direction string, aclNameIDs map[string]int64, peerTargetNetIDs map[db.NetworkPeer]int64, subjectCriteria ...string) (string, bool, []db.NetworkPeer, error) {
+func ovnRuleSubjectToOVNACLMatch(direction string, aclNameIDs map[string]int64, peerTargetNetIDs map[db.NetworkPeer]int64, addressSetIDs map[string]int64, subjectCriteria ...string) (string, bool, []db.NetworkPeer, error) {
 	fieldParts := make([]string, 0, len(subjectCriteria))
 	networkSpecific := false
 	networkPeersNeeded := make([]db.NetworkPeer, 0)
@@ -594,6 +656,19 @@ func ovnRuleSubjectToOVNACLMatch(direction string, aclNameIDs map[string]int64,
 					// Convert deprecated #external to non-deprecated @external if needed.
 					subjectPortSelector = openvswitch.OVNPortGroup(ruleSubjectExternal)
 					networkSpecific = true
+				} else if strings.HasPrefix(subjectCriterion, addressSetSubjectPrefix) {
+					// Subject is a network address set reference. Convert to address set criteria.
+					addressSetName := strings.TrimPrefix(subjectCriterion, addressSetSubjectPrefix)
+					addressSetID, found := addressSetIDs[addressSetName]
+					if !found {
+						return "", false, nil, fmt.Errorf("Cannot find address set ID for %q", addressSetName)
+					}
+
+					addrSetPrefix := OVNAddressSetPrefix(addressSetID)
+
+					fieldParts = append(fieldParts, fmt.Sprintf("ip6.%s == $%s_ip6 || ip4.%s == $%s_ip4", direction, addrSetPrefix, direction, addrSetPrefix))
+
+					continue // Not a port based selector.
 				} else if strings.HasPrefix(subjectCriterion, "@") {
 					// Subject is a network peer name. Convert to address set criteria.
 					peerParts := strings.SplitN(strings.TrimPrefix(subjectCriterion, "@"), "/", 2)
@@ -1033,6 +1108,52 @@ func OVNApplyInstanceNICDefaultRules(client *openvswitch.OVN, switchPortGroup op
 	return nil
 }
 
+// ovnACLRuleCounters adds the counters of the OVN ACL rules of the Network ACL to counters, from the flows
+// installed on this member.
+func ovnACLRuleCounters(s *state.State, aclID int64, counters map[string]api.NetworkACLRuleState) error {
+	ovs := openvswitch.NewOVS()
+	if !ovs.Installed() {
+		return nil
+	}
+
+	client, err := openvswitch.NewOVN(s.GlobalConfig.NetworkOVNNorthboundConnection(), s.GlobalConfig.NetworkOVNSSL)
+	if err != nil {
+		return fmt.Errorf("Failed to get OVN client: %w", err)
+	}
+
+	ruleCookies, err := client.ACLFlowCookies(string(OVNACLPortGroupName(aclID)) + "-")
+	if err != nil {
+		return fmt.Errorf("Failed getting OVN ACL flows: %w", err)
+	}
+
+	if len(ruleCookies) == 0 {
+		return nil
+	}
+
+	flowCounters, err := ovs.BridgeFlowCounters(s.GlobalConfig.NetworkOVNIntegrationBridge())
+	if err != nil {
+		return fmt.Errorf("Failed getting OVN integration bridge flows: %w", err)
+	}
+
+	ovnAddFlowCounters(ruleCookies, flowCounters, counters)
+
+	return nil
+}
+
+// ovnAddFlowCounters adds the counters of the flows generated from each OVN ACL rule to counters, indexed by
+// rule name.
+func ovnAddFlowCounters(ruleCookies map[string][]uint64, flowCounters map[uint64]openvswitch.OVSFlowCounters, counters map[string]api.NetworkACLRuleState) {
+	for name, cookies := range ruleCookies {
+		ruleCounters := counters[name]
+		for _, cookie := range cookies {
+			ruleCounters.Packets += flowCounters[cookie].Packets
+			ruleCounters.Bytes += flowCounters[cookie].Bytes
+		}
+
+		counters[name] = ruleCounters
+	}
+}
+
 // ovnLogEntry is the type used for the JSON encoded entries on the log endpoint (when coming from OVN).
 type ovnLogEntry struct {
 	Time     string `json:"time"`
@@ -1050,6 +1171,34 @@ type ovnLogEntry struct {
 // and expected ACL prefix and returns a re-formated log entry if matching.
 // The 'timestamp' string is in microseconds format. If empty, the timestamp is extracted from the log entry.
 func ovnParseLogEntry(logline string, syslogTimestamp string, prefix string) string {
+	entry, _ := ovnParseLogRecord(logline, syslogTimestamp, prefix)
+	if entry == nil {
+		return ""
+	}
+
+	out, err := json.Marshal(&ovnLogEntry{
+		Time:     entry.Time.Format(time.RFC3339),
+		Proto:    entry.Protocol,
+		Src:      entry.Source,
+		Dst:      entry.Destination,
+		SrcPort:  entry.SourcePort,
+		DstPort:  entry.DestinationPort,
+		ICMPType: entry.ICMPType,
+		ICMPCode: entry.ICMPCode,
+		Action:   entry.Action,
+	})
+	if err != nil {
+		return ""
+	}
+
+	return string(out)
+}
+
+// ovnParseLogRecord takes a log line (that comes from either an ovn controller log file or from the syslogs)
+// and expected ACL prefix and returns the structured log entry if matching, along with the MAC address of the
+// instance side of the packet.
+// The 'timestamp' string is in microseconds format. If empty, the timestamp is extracted from the log entry.
+func ovnParseLogRecord(logline string, syslogTimestamp string, prefix string) (*api.NetworkACLLogEntry, string) {
 	parseLogTimeFromFields := func(fields []string) (time.Time, error) {
 		return time.Parse(time.RFC3339, fields[0])
 	}
@@ -1069,12 +1218,12 @@ func ovnParseLogEntry(logline string, syslogTimestamp string, prefix string) str
 
 	// Skip unknown formatting.
 	if len(fields) != 5 {
-		return ""
+		return nil, ""
 	}
 
 	// We only care about ACLs.
 	if !strings.HasPrefix(fields[2], "acl_log") {
-		return ""
+		return nil, ""
 	}
 
 	// Parse the ACL log entry.
@@ -1089,8 +1238,9 @@ func ovnParseLogEntry(logline string, syslogTimestamp string, prefix string) str
 	}
 
 	// Filter for our ACL.
-	if !strings.HasPrefix(aclEntry["name"], prefix) {
-		return ""
+	ruleName, found := strings.CutPrefix(aclEntry["name"], prefix)
+	if !found {
+		return nil, ""
 	}
 
 	var logTime time.Time
@@ -1102,13 +1252,13 @@ func ovnParseLogEntry(logline string, syslogTimestamp string, prefix string) str
 	}
 
 	if err != nil {
-		return ""
+		return nil, ""
 	}
 
 	// Get the protocol.
 	directionFields := strings.Split(aclEntry["direction"], " ")
 	if len(directionFields) != 2 {
-		return ""
+		return nil, ""
 	}
 
 	protocol := directionFields[1]
@@ -1118,7 +1268,7 @@ func ovnParseLogEntry(logline string, syslogTimestamp string, prefix string) str
 	if !ok {
 		srcAddr, ok = aclEntry["ipv6_src"]
 		if !ok {
-			return ""
+			return nil, ""
 		}
 	}
 
@@ -1126,38 +1276,202 @@ func ovnParseLogEntry(logline string, syslogTimestamp string, prefix string) str
 	if !ok {
 		dstAddr, ok = aclEntry["ipv6_dst"]
 		if !ok {
-			return ""
+			return nil, ""
 		}
 	}
 
 	// Prepare the core log entry.
-	newEntry := ovnLogEntry{
-		Time:     logTime.UTC().Format(time.RFC3339),
-		Proto:    protocol,
-		Src:      srcAddr,
-		Dst:      dstAddr,
-		ICMPType: aclEntry["icmp_type"],
-		ICMPCode: aclEntry["icmp_code"],
-		Action:   aclEntry["verdict"],
+	newEntry := &api.NetworkACLLogEntry{
+		Time:        logTime.UTC(),
+		Protocol:    protocol,
+		Source:      srcAddr,
+		Destination: dstAddr,
+		ICMPType:    aclEntry["icmp_type"],
+		ICMPCode:    aclEntry["icmp_code"],
+		Action:      aclEntry["verdict"],
 	}
 
 	// Add the source and destination ports.
 	srcPort, ok := aclEntry["tp_src"]
 	if ok {
-		newEntry.SrcPort = srcPort
+		newEntry.SourcePort = srcPort
 	}
 
 	dstPort, ok := aclEntry["tp_dst"]
 	if ok {
-		newEntry.DstPort = dstPort
+		newEntry.DestinationPort = dstPort
+	}
+
+	// Get the rule from the remaining "<direction>-<index>" part of the name.
+	direction, index, _ := strings.Cut(ruleName, "-")
+	newEntry.Direction = direction
+	newEntry.Rule, _ = strconv.Atoi(index)
+
+	// The instance receives the packets matched by ingress rules and sends the ones matched by egress rules.
+	instanceMAC := aclEntry["dl_dst"]
+	if direction == "egress" {
+		instanceMAC = aclEntry["dl_src"]
 	}
 
-	out, err := json.Marshal(&newEntry)
+	return newEntry, instanceMAC
+}
+
+// ovnFollowLogEntries calls handler with the entries logged by the OVN ACL rules whose name starts with the
+// prefix on this member, along with the MAC address of the instance side of the packet, until the context is done.
+func ovnFollowLogEntries(ctx context.Context, prefix string, handler func(entry *api.NetworkACLLogEntry, instanceMAC string) error) error {
+	if shared.IsMicroOVNUsed() {
+		return ovnFollowJournald(ctx, "snap.microovn.chassis.service", prefix, func(message string, timestamp string) error {
+			entry, instanceMAC := ovnParseLogRecord(message, timestamp, prefix)
+			if entry == nil {
+				return nil
+			}
+
+			return handler(entry, instanceMAC)
+		})
+	}
+
+	// Else, if the current LXD deployment does not use MicroOVN,
+	// then try to follow the OVN controller log file directly.
+	logPath := shared.HostPath("/var/log/ovn/ovn-controller.log")
+	if !shared.PathExists(logPath) {
+		return errors.New("Only OVN log entries may be retrieved at this time")
+	}
+
+	return ovnFollowLogFile(ctx, logPath, func(line string) error {
+		entry, instanceMAC := ovnParseLogRecord(line, "", prefix)
+		if entry == nil {
+			return nil
+		}
+
+		return handler(entry, instanceMAC)
+	})
+}
+
+// ovnFollowJournald calls handler with the messages (and their timestamp in microseconds) added to the systemd
+// journal by the unit and matching the filter, until the context is done.
+func ovnFollowJournald(ctx context.Context, systemdUnitName string, filter string, handler func(message string, timestamp string) error) error {
+	cmd := exec.CommandContext(ctx,
+		"journalctl",
+		"--unit", systemdUnitName,
+		"--no-pager",
+		"--follow",
+		"--lines", "0",
+		"--case-sensitive",
+		"--grep", filter,
+		"--output-fields", "MESSAGE",
+		"-o", "json",
+	)
+
+	stdout, err := cmd.StdoutPipe()
 	if err != nil {
-		return ""
+		return err
 	}
 
-	return string(out)
+	err = cmd.Start()
+	if err != nil {
+		return fmt.Errorf("Failed to run journalctl to follow OVN ACL logs: %w", err)
+	}
+
+	defer func() { _ = cmd.Wait() }()
+
+	// journalctl is killed once the context is done.
+	decoder := json.NewDecoder(stdout)
+	for {
+		var sdLogEntry map[string]any
+		err = decoder.Decode(&sdLogEntry)
+		if ctx.Err() != nil {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("Failed to parse log entry: %w", err)
+		}
+
+		message, ok := sdLogEntry["MESSAGE"].(string)
+		if !ok {
+			continue
+		}
+
+		timestamp, ok := sdLogEntry["__REALTIME_TIMESTAMP"].(string)
+		if !ok {
+			continue
+		}
+
+		err = handler(message, timestamp)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// ovnFollowLogFile calls handler with the lines added to the log file until the context is done.
+// The log file is opened again when it gets rotated.
+func ovnFollowLogFile(ctx context.Context, logPath string, handler func(line string) error) error {
+	logFile, err := os.Open(logPath)
+	if err != nil {
+		return fmt.Errorf("Failed to open OVN log file: %w", err)
+	}
+
+	defer func() { _ = logFile.Close() }()
+
+	// Only follow the new lines.
+	offset, err := logFile.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("Failed to read OVN log file: %w", err)
+	}
+
+	reader := bufio.NewReader(logFile)
+	partial := ""
+
+	for {
+		line, err := reader.ReadString('\n')
+		offset += int64(len(line))
+
+		if err == nil {
+			err = handler(partial + strings.TrimSuffix(line, "\n"))
+			if err != nil {
+				return err
+			}
+
+			partial = ""
+			continue
+		} else if err != io.EOF {
+			return fmt.Errorf("Failed to read OVN log file: %w", err)
+		}
+
+		// Keep the incomplete line until the rest of it is written.
+		partial += line
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Second):
+		}
+
+		// Check whether the log file got rotated.
+		pathInfo, err := os.Stat(logPath)
+		if err != nil {
+			continue
+		}
+
+		fileInfo, err := logFile.Stat()
+		if err != nil {
+			return fmt.Errorf("Failed to read OVN log file: %w", err)
+		}
+
+		if os.SameFile(pathInfo, fileInfo) && pathInfo.Size() >= offset {
+			continue
+		}
+
+		newLogFile, err := os.Open(logPath)
+		if err != nil {
+			continue
+		}
+
+		_ = logFile.Close()
+		logFile = newLogFile
+		reader.Reset(logFile)
+		offset = 0
+		partial = ""
+	}
 }
 
 // ovnParseLogEntriesFromJournald reads the OVN log entries from the systemd journal and returns them as a list of string entries.
@@ -1,7 +1,18 @@
 package acl
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/network/openvswitch"
+	"github.com/canonical/lxd/shared/api"
 )
 
 func Test_ovnRulePortToOVNACLMatch(t *testing.T) {
@@ -50,3 +61,216 @@ func Benchmark_ovnRulePortToOVNACLMatch(b *testing.B) {
 		ovnRulePortToOVNACLMatch("tcp", "dst", "8080", "9090", "8000-8080")
 	}
 }
+
+func Test_ovnParseLogRecord(t *testing.T) {
+	prefix := string(OVNACLPortGroupName(12)) + "-"
+
+	tests := []struct {
+		name            string
+		logline         string
+		syslogTimestamp string
+		wantEntry       *api.NetworkACLLogEntry
+		wantMAC         string
+	}{
+		{
+			name:    "Ingress rule",
+			logline: `2024-05-10T13:10:41.871Z|00012|acl_log(ovn_pinctrl0)|INFO|name="lxd_acl12-ingress-3", verdict=drop, severity=info, direction=to-lport: tcp,vlan_tci=0x0000,dl_src=00:16:3e:00:00:01,dl_dst=00:16:3e:00:00:02,nw_src=10.0.0.1,nw_dst=10.0.0.2,nw_tos=0,nw_ecn=0,nw_ttl=64,tp_src=43210,tp_dst=80,tcp_flags=syn`,
+			wantEntry: &api.NetworkACLLogEntry{
+				Time:            time.Date(2024, time.May, 10, 13, 10, 41, 871000000, time.UTC),
+				Direction:       "ingress",
+				Rule:            3,
+				Protocol:        "tcp",
+				Source:          "10.0.0.1",
+				Destination:     "10.0.0.2",
+				SourcePort:      "43210",
+				DestinationPort: "80",
+				Action:          "drop",
+			},
+			wantMAC: "00:16:3e:00:00:02",
+		},
+		{
+			name:            "Egress rule from the journal",
+			logline:         `2024-05-10T13:10:41.871Z|00013|acl_log(ovn_pinctrl0)|INFO|name="lxd_acl12-egress-0", verdict=allow, severity=info, direction=from-lport: icmp6,vlan_tci=0x0000,dl_src=00:16:3e:00:00:01,dl_dst=00:16:3e:00:00:02,ipv6_src=fd42::1,ipv6_dst=fd42::2,ipv6_label=0x00000,nw_tos=0,nw_ecn=0,nw_ttl=64,icmp_type=128,icmp_code=0`,
+			syslogTimestamp: "1715346642000000",
+			wantEntry: &api.NetworkACLLogEntry{
+				Time:        time.Date(2024, time.May, 10, 13, 10, 42, 0, time.UTC),
+				Direction:   "egress",
+				Rule:        0,
+				Protocol:    "icmp6",
+				Source:      "fd42::1",
+				Destination: "fd42::2",
+				ICMPType:    "128",
+				ICMPCode:    "0",
+				Action:      "allow",
+			},
+			wantMAC: "00:16:3e:00:00:01",
+		},
+		{
+			name:    "Rule of another ACL",
+			logline: `2024-05-10T13:10:41.871Z|00014|acl_log(ovn_pinctrl0)|INFO|name="lxd_acl123-ingress-0", verdict=drop, severity=info, direction=to-lport: tcp,dl_src=00:16:3e:00:00:01,dl_dst=00:16:3e:00:00:02,nw_src=10.0.0.1,nw_dst=10.0.0.2`,
+		},
+		{
+			name:    "Not an ACL log entry",
+			logline: `2024-05-10T13:10:41.871Z|00015|binding|INFO|Claiming lport lxd-net1-instance-1 for this chassis.`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, mac := ovnParseLogRecord(tt.logline, tt.syslogTimestamp, prefix)
+			assert.Equal(t, tt.wantEntry, entry)
+			assert.Equal(t, tt.wantMAC, mac)
+		})
+	}
+}
+
+func Test_ovnFollowLogFile(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "ovn-controller.log")
+	require.NoError(t, os.WriteFile(logPath, []byte("old line\n"), 0o600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	lines := make(chan string, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- ovnFollowLogFile(ctx, logPath, func(line string) error {
+			lines <- line
+			return nil
+		})
+	}()
+
+	// appendLog appends data to the log file.
+	appendLog := func(data string) {
+		f, err := os.OpenFile(logPath, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0o600)
+		require.NoError(t, err)
+		_, err = f.WriteString(data)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+	}
+
+	// waitLine returns the next line passed to the handler.
+	waitLine := func() string {
+		select {
+		case line := <-lines:
+			return line
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for a log line")
+			return ""
+		}
+	}
+
+	// Let the file be opened before writing to it, so that only the new lines are followed.
+	time.Sleep(100 * time.Millisecond)
+
+	// Incomplete lines are only passed once complete.
+	appendLog("first ")
+	time.Sleep(1500 * time.Millisecond)
+	appendLog("line\nsecond line\n")
+	assert.Equal(t, "first line", waitLine())
+	assert.Equal(t, "second line", waitLine())
+
+	// The log file is followed across rotations.
+	require.NoError(t, os.Rename(logPath, logPath+".1"))
+	appendLog("rotated line\n")
+	assert.Equal(t, "rotated line", waitLine())
+
+	cancel()
+	assert.NoError(t, <-done)
+	assert.Empty(t, lines)
+}
+
+func Test_ovnAddFlowCounters(t *testing.T) {
+	counters := map[string]api.NetworkACLRuleState{
+		"lxd_acl12-ingress-0": {Packets: 1, Bytes: 100},
+	}
+
+	ruleCookies := map[string][]uint64{
+		"lxd_acl12-ingress-0": {0x1a, 0x2b},
+		"lxd_acl12-egress-0":  {0x3c},
+		"lxd_acl12-egress-1":  {0x4d},
+	}
+
+	flowCounters := map[uint64]openvswitch.OVSFlowCounters{
+		0x1a: {Packets: 2, Bytes: 200},
+		0x2b: {Packets: 3, Bytes: 300},
+		0x3c: {Packets: 4, Bytes: 400},
+	}
+
+	ovnAddFlowCounters(ruleCookies, flowCounters, counters)
+
+	want := map[string]api.NetworkACLRuleState{
+		"lxd_acl12-ingress-0": {Packets: 6, Bytes: 600},
+		"lxd_acl12-egress-0":  {Packets: 4, Bytes: 400},
+		"lxd_acl12-egress-1":  {},
+	}
+
+	assert.Equal(t, want, counters)
+}
+
+func Test_ovnRuleSubjectToOVNACLMatch(t *testing.T) {
+	aclNameIDs := map[string]int64{"web": 12}
+	peerTargetNetIDs := map[db.NetworkPeer]int64{{NetworkName: "ovn1", PeerName: "peer1"}: 5}
+	addressSetIDs := map[string]int64{"allowlist": 3, "blocklist": 4}
+
+	tests := []struct {
+		name                string
+		direction           string
+		subjects            []string
+		wantMatch           string
+		wantNetworkSpecific bool
+		wantPeers           []db.NetworkPeer
+		wantErr             string
+	}{
+		{
+			name:      "Address set",
+			direction: "src",
+			subjects:  []string{"$allowlist"},
+			wantMatch: "ip6.src == $lxd_addrset3_ip6 || ip4.src == $lxd_addrset3_ip4",
+			wantPeers: []db.NetworkPeer{},
+		},
+		{
+			name:      "Address sets mixed with other subjects",
+			direction: "dst",
+			subjects:  []string{"192.0.2.0/24", "$allowlist", "web", "$blocklist"},
+			wantMatch: "ip4.dst == 192.0.2.0/24 || ip6.dst == $lxd_addrset3_ip6 || ip4.dst == $lxd_addrset3_ip4 || outport == @lxd_acl12 || ip6.dst == $lxd_addrset4_ip6 || ip4.dst == $lxd_addrset4_ip4",
+			wantPeers: []db.NetworkPeer{},
+		},
+		{
+			name:                "Address set with a network specific subject",
+			direction:           "src",
+			subjects:            []string{"$blocklist", "@internal"},
+			wantMatch:           "ip6.src == $lxd_addrset4_ip6 || ip4.src == $lxd_addrset4_ip4 || inport == @@internal",
+			wantNetworkSpecific: true,
+			wantPeers:           []db.NetworkPeer{},
+		},
+		{
+			name:      "Address set with a network peer",
+			direction: "src",
+			subjects:  []string{"$allowlist", "@ovn1/peer1"},
+			wantMatch: "ip6.src == $lxd_addrset3_ip6 || ip4.src == $lxd_addrset3_ip4 || ip6.src == $lxd_net5_routes_ip6 || ip4.src == $lxd_net5_routes_ip4",
+			wantPeers: []db.NetworkPeer{{NetworkName: "ovn1", PeerName: "peer1"}},
+		},
+		{
+			name:      "Unknown address set",
+			direction: "src",
+			subjects:  []string{"$missing"},
+			wantErr:   `Cannot find address set ID for "missing"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, networkSpecific, peers, err := ovnRuleSubjectToOVNACLMatch(tt.direction, aclNameIDs, peerTargetNetIDs, addressSetIDs, tt.subjects...)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantMatch, match)
+			assert.Equal(t, tt.wantNetworkSpecific, networkSpecific)
+			assert.Equal(t, tt.wantPeers, peers)
+		})
+	}
+}
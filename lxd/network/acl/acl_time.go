@@ -0,0 +1,116 @@
+package acl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// ruleTimeBounds returns the times from and until which the rule applies. A zero time means the rule isn't bound
+// on that side.
+func ruleTimeBounds(rule api.NetworkACLRule) (activeFrom time.Time, activeUntil time.Time, err error) {
+	if rule.ActiveFrom != "" {
+		activeFrom, err = time.Parse(time.RFC3339, rule.ActiveFrom)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("Invalid active from time %q, must be in RFC 3339 format", rule.ActiveFrom)
+		}
+	}
+
+	if rule.ActiveUntil != "" {
+		activeUntil, err = time.Parse(time.RFC3339, rule.ActiveUntil)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("Invalid active until time %q, must be in RFC 3339 format", rule.ActiveUntil)
+		}
+	}
+
+	if !activeFrom.IsZero() && !activeUntil.IsZero() && !activeUntil.After(activeFrom) {
+		return time.Time{}, time.Time{}, errors.New("Active until time must be after active from time")
+	}
+
+	return activeFrom, activeUntil, nil
+}
+
+// ruleActive returns whether the rule applies at the given time according to its time bounds.
+// The time bounds are validated when the rule is set, so rules with invalid bounds are considered unbound.
+func ruleActive(rule api.NetworkACLRule, now time.Time) bool {
+	activeFrom, activeUntil, err := ruleTimeBounds(rule)
+	if err != nil {
+		return true
+	}
+
+	if !activeFrom.IsZero() && now.Before(activeFrom) {
+		return false
+	}
+
+	if !activeUntil.IsZero() && !now.Before(activeUntil) {
+		return false
+	}
+
+	return true
+}
+
+// timeBoundRulesChanged returns whether any rule of the ACL became active or inactive after since and up to now.
+func timeBoundRulesChanged(info *api.NetworkACL, since time.Time, now time.Time) bool {
+	for _, rules := range [][]api.NetworkACLRule{info.Ingress, info.Egress} {
+		for _, rule := range rules {
+			if rule.State == "disabled" {
+				continue
+			}
+
+			if ruleActive(rule, since) != ruleActive(rule, now) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// RefreshTimeBoundRules applies the ACLs again whose rules became active or inactive after since and up to now.
+// The ACLs are applied to the networks using them on all cluster members, so this must only be run on one member.
+func RefreshTimeBoundRules(s *state.State, since time.Time, now time.Time) error {
+	var projectACLs map[string][]string
+
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		projectACLs, err = tx.GetNetworkACLsAllProjects(ctx)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading network ACLs: %w", err)
+	}
+
+	for projectName, aclNames := range projectACLs {
+		for _, aclName := range aclNames {
+			netACL, err := LoadByName(s, projectName, aclName)
+			if err != nil {
+				logger.Warn("Failed loading network ACL", logger.Ctx{"project": projectName, "networkACL": aclName, "err": err})
+				continue
+			}
+
+			if !timeBoundRulesChanged(netACL.Info(), since, now) {
+				continue
+			}
+
+			config := netACL.Info().Writable()
+			err = netACL.Update(&config, request.ClientTypeNormal)
+			if err != nil {
+				logger.Warn("Failed applying time-bound network ACL rules", logger.Ctx{"project": projectName, "networkACL": aclName, "err": err})
+				continue
+			}
+
+			logger.Debug("Applied time-bound network ACL rules", logger.Ctx{"project": projectName, "networkACL": aclName})
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package acl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+func Test_ruleTimeBounds(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    api.NetworkACLRule
+		wantErr string
+	}{
+		{
+			name: "Unbound",
+			rule: api.NetworkACLRule{},
+		},
+		{
+			name: "Both bounds",
+			rule: api.NetworkACLRule{ActiveFrom: "2026-01-01T08:00:00Z", ActiveUntil: "2026-01-01T18:00:00+02:00"},
+		},
+		{
+			name:    "Invalid active from",
+			rule:    api.NetworkACLRule{ActiveFrom: "2026-01-01"},
+			wantErr: `Invalid active from time "2026-01-01"`,
+		},
+		{
+			name:    "Invalid active until",
+			rule:    api.NetworkACLRule{ActiveUntil: "tomorrow"},
+			wantErr: `Invalid active until time "tomorrow"`,
+		},
+		{
+			name:    "Active until before active from",
+			rule:    api.NetworkACLRule{ActiveFrom: "2026-01-01T18:00:00Z", ActiveUntil: "2026-01-01T08:00:00Z"},
+			wantErr: "Active until time must be after active from time",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ruleTimeBounds(tt.rule)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_ruleActive(t *testing.T) {
+	rule := api.NetworkACLRule{ActiveFrom: "2026-01-01T08:00:00Z", ActiveUntil: "2026-01-01T18:00:00Z"}
+
+	assert.False(t, ruleActive(rule, time.Date(2026, 1, 1, 7, 59, 59, 0, time.UTC)))
+	assert.True(t, ruleActive(rule, time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)))
+	assert.True(t, ruleActive(rule, time.Date(2026, 1, 1, 17, 59, 59, 0, time.UTC)))
+	assert.False(t, ruleActive(rule, time.Date(2026, 1, 1, 18, 0, 0, 0, time.UTC)))
+
+	// Rules without time bounds always apply.
+	assert.True(t, ruleActive(api.NetworkACLRule{}, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func Test_timeBoundRulesChanged(t *testing.T) {
+	info := &api.NetworkACL{
+		Ingress: []api.NetworkACLRule{
+			{Action: "allow", State: "enabled"},
+			{Action: "allow", State: "disabled", ActiveFrom: "2026-01-01T06:00:00Z"},
+		},
+		Egress: []api.NetworkACLRule{
+			{Action: "drop", State: "enabled", ActiveFrom: "2026-01-01T08:00:00Z", ActiveUntil: "2026-01-01T18:00:00Z"},
+		},
+	}
+
+	at := func(hour int, minute int) time.Time {
+		return time.Date(2026, 1, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	assert.False(t, timeBoundRulesChanged(info, at(5, 59), at(6, 0)), "Disabled rules are ignored")
+	assert.False(t, timeBoundRulesChanged(info, at(7, 58), at(7, 59)))
+	assert.True(t, timeBoundRulesChanged(info, at(7, 59), at(8, 0)))
+	assert.False(t, timeBoundRulesChanged(info, at(8, 0), at(8, 1)))
+	assert.True(t, timeBoundRulesChanged(info, at(17, 59), at(18, 0)))
+
+	// A rule that became active and inactive again in between didn't change.
+	assert.False(t, timeBoundRulesChanged(info, at(7, 0), at(19, 0)))
+}
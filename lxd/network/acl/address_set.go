@@ -0,0 +1,538 @@
+package acl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/canonical/lxd/client"
+	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/config"
+	"github.com/canonical/lxd/lxd/db"
+	firewallDrivers "github.com/canonical/lxd/lxd/firewall/drivers"
+	"github.com/canonical/lxd/lxd/network/openvswitch"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/revert"
+	"github.com/canonical/lxd/shared/validate"
+	"github.com/canonical/lxd/shared/version"
+)
+
+// addressSetSubjectPrefix is the prefix used to reference an address set in the rule subjects.
+const addressSetSubjectPrefix = "$"
+
+// addressSetFirewall is the part of the firewall holding the contents of the address sets.
+type addressSetFirewall interface {
+	NetworkApplyAddressSets(sets []firewallDrivers.AddressSet) error
+	NetworkDeleteAddressSets(names []string) error
+}
+
+// ovnAddressSetClient is the part of the OVN client holding the contents of the address sets.
+type ovnAddressSetClient interface {
+	AddressSetSet(addressSetPrefix openvswitch.OVNAddressSet, addresses ...net.IPNet) error
+	AddressSetDelete(addressSetPrefix openvswitch.OVNAddressSet) error
+}
+
+// AddressSet represents a network address set.
+type AddressSet struct {
+	logger      logger.Logger
+	state       *state.State
+	id          int64
+	projectName string
+	info        *api.NetworkAddressSet
+}
+
+// AddressSetValidName checks the address set name is valid.
+func AddressSetValidName(name string) error {
+	if name == "" {
+		return errors.New("Name is required")
+	}
+
+	// Ensures the "$" prefix of the address set references in rules cannot be ambiguous.
+	err := validate.IsHostname(name)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// LoadAddressSetByName loads a network address set from the database by project and name.
+func LoadAddressSetByName(s *state.State, projectName string, name string) (*AddressSet, error) {
+	var id int64
+	var info *api.NetworkAddressSet
+
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		id, info, err = tx.GetNetworkAddressSet(ctx, projectName, name)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	addrSet := &AddressSet{
+		logger:      logger.AddContext(logger.Ctx{"project": projectName, "networkAddressSet": name}),
+		state:       s,
+		id:          id,
+		projectName: projectName,
+		info:        info,
+	}
+
+	return addrSet, nil
+}
+
+// CreateAddressSet validates supplied record and creates new network address set record in the database.
+func CreateAddressSet(s *state.State, projectName string, info *api.NetworkAddressSetsPost) error {
+	err := AddressSetValidName(info.Name)
+	if err != nil {
+		return err
+	}
+
+	err = validateAddressSet(&info.NetworkAddressSetPut)
+	if err != nil {
+		return err
+	}
+
+	return s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, err := tx.CreateNetworkAddressSet(ctx, projectName, info)
+
+		return err
+	})
+}
+
+// validateAddressSet checks the config and addresses are valid.
+func validateAddressSet(info *api.NetworkAddressSetPut) error {
+	for k := range info.Config {
+		// User keys are not validated.
+		if !config.IsUserConfig(k) {
+			return fmt.Errorf("Invalid config option %q", k)
+		}
+	}
+
+	for i, address := range info.Addresses {
+		if net.ParseIP(address) == nil && validate.IsNetwork(address) != nil {
+			return fmt.Errorf("Invalid address %q: Must be an IP address or a CIDR subnet", address)
+		}
+
+		if slices.Contains(info.Addresses[:i], address) {
+			return fmt.Errorf("Duplicate address %q", address)
+		}
+	}
+
+	return nil
+}
+
+// ID returns the network address set ID.
+func (a *AddressSet) ID() int64 {
+	return a.id
+}
+
+// Project returns the project name.
+func (a *AddressSet) Project() string {
+	return a.projectName
+}
+
+// Info returns copy of internal info for the network address set.
+func (a *AddressSet) Info() *api.NetworkAddressSet {
+	// Copy internal info to prevent modification externally.
+	info := api.NetworkAddressSet{}
+	info.Name = a.info.Name
+	info.Description = a.info.Description
+	info.Addresses = append(make([]string, 0, len(a.info.Addresses)), a.info.Addresses...)
+	info.Config = util.CopyConfig(a.info.Config)
+	info.UsedBy = nil // To indicate its not populated (use UsedBy() function to populate).
+	info.Project = a.projectName
+
+	return &info
+}
+
+// Etag returns the values used for etag generation.
+func (a *AddressSet) Etag() []any {
+	return []any{a.info.Name, a.info.Description, a.info.Addresses, a.info.Config}
+}
+
+// usedByACLs returns the names of the network ACLs whose rules reference the address set.
+// If firstOnly is true then search stops at first result.
+func (a *AddressSet) usedByACLs(firstOnly bool) ([]string, error) {
+	aclNames := []string{}
+
+	err := a.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		projectACLNames, err := tx.GetNetworkACLs(ctx, a.projectName)
+		if err != nil {
+			return err
+		}
+
+		for _, aclName := range projectACLNames {
+			_, aclInfo, err := tx.GetNetworkACL(ctx, a.projectName, aclName)
+			if err != nil {
+				return err
+			}
+
+			if !slices.Contains(addressSetReferences(aclInfo), a.info.Name) {
+				continue
+			}
+
+			aclNames = append(aclNames, aclName)
+
+			if firstOnly {
+				break
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting address set usage: %w", err)
+	}
+
+	return aclNames, nil
+}
+
+// UsedBy returns a list of API endpoints referencing this address set.
+func (a *AddressSet) UsedBy() ([]string, error) {
+	aclNames, err := a.usedByACLs(false)
+	if err != nil {
+		return nil, err
+	}
+
+	usedBy := make([]string, 0, len(aclNames))
+	for _, aclName := range aclNames {
+		usedBy = append(usedBy, api.NewURL().Path(version.APIVersion, "network-acls", aclName).Project(a.projectName).String())
+	}
+
+	return usedBy, nil
+}
+
+// isUsed returns whether or not the address set is in use.
+func (a *AddressSet) isUsed() (bool, error) {
+	aclNames, err := a.usedByACLs(true)
+	if err != nil {
+		return false, err
+	}
+
+	return len(aclNames) > 0, nil
+}
+
+// Update applies the supplied config to the address set.
+// The new addresses are pushed to the firewall sets and OVN address sets in use without reapplying the rules.
+func (a *AddressSet) Update(config *api.NetworkAddressSetPut, clientType request.ClientType) error {
+	err := validateAddressSet(config)
+	if err != nil {
+		return err
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	if clientType == request.ClientTypeNormal {
+		oldConfig := a.info.Writable()
+
+		err = a.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpdateNetworkAddressSet(ctx, a.id, *config)
+		})
+		if err != nil {
+			return err
+		}
+
+		a.info.SetWritable(*config)
+
+		revert.Add(func() {
+			_ = a.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+				return tx.UpdateNetworkAddressSet(ctx, a.id, oldConfig)
+			})
+
+			a.info.SetWritable(oldConfig)
+		})
+	} else {
+		a.info.SetWritable(*config)
+	}
+
+	// Get a list of networks that are using ACLs referencing this address set.
+	aclNames, err := a.usedByACLs(false)
+	if err != nil {
+		return err
+	}
+
+	aclNets := map[string]NetworkACLUsage{}
+	if len(aclNames) > 0 {
+		err = NetworkUsage(a.state, a.projectName, aclNames, aclNets)
+		if err != nil {
+			return fmt.Errorf("Failed getting ACL network usage: %w", err)
+		}
+	}
+
+	hasFirewallNets, hasOVNNets, err := addressSetNetworkTypes(aclNets)
+	if err != nil {
+		return err
+	}
+
+	// Replace the contents of the firewall sets on this member.
+	var firewall addressSetFirewall
+	if hasFirewallNets {
+		firewall = a.state.Firewall
+	}
+
+	// OVN address sets are shared by all members, so only update them once.
+	var ovnClient ovnAddressSetClient
+	if hasOVNNets && clientType == request.ClientTypeNormal {
+		ovnClient, err = openvswitch.NewOVN(a.state.GlobalConfig.NetworkOVNNorthboundConnection(), a.state.GlobalConfig.NetworkOVNSSL)
+		if err != nil {
+			return fmt.Errorf("Failed to get OVN client: %w", err)
+		}
+	}
+
+	err = a.applyAddresses(firewall, ovnClient)
+	if err != nil {
+		return err
+	}
+
+	// Replace the contents of the firewall sets on the other cluster members.
+	if hasFirewallNets && clientType == request.ClientTypeNormal {
+		notifier, err := cluster.NewNotifier(a.state, a.state.Endpoints.NetworkCert(), a.state.ServerCert(), cluster.NotifyAll)
+		if err != nil {
+			return err
+		}
+
+		err = notifier(func(member db.NodeInfo, client lxd.InstanceServer) error {
+			return client.UseProject(a.projectName).UpdateNetworkAddressSet(a.info.Name, a.info.Writable(), "")
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	revert.Success()
+	return nil
+}
+
+// Rename renames the address set if not in use.
+func (a *AddressSet) Rename(newName string) error {
+	_, err := LoadAddressSetByName(a.state, a.projectName, newName)
+	if err == nil {
+		return errors.New("An address set by that name exists already")
+	}
+
+	isUsed, err := a.isUsed()
+	if err != nil {
+		return err
+	}
+
+	if isUsed {
+		return errors.New("Cannot rename an address set that is in use")
+	}
+
+	err = AddressSetValidName(newName)
+	if err != nil {
+		return err
+	}
+
+	err = a.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.RenameNetworkAddressSet(ctx, a.id, newName)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Apply changes internally.
+	a.info.Name = newName
+
+	return nil
+}
+
+// Delete deletes the address set if not in use.
+// Notifications from other cluster members only remove the local firewall sets.
+func (a *AddressSet) Delete(clientType request.ClientType) error {
+	if clientType != request.ClientTypeNormal {
+		return a.removeAddresses(a.state.Firewall, nil)
+	}
+
+	isUsed, err := a.isUsed()
+	if err != nil {
+		return err
+	}
+
+	if isUsed {
+		return errors.New("Cannot delete an address set that is in use")
+	}
+
+	// Remove the sets left behind by ACL rules that used to reference the address set.
+	notifier, err := cluster.NewNotifier(a.state, a.state.Endpoints.NetworkCert(), a.state.ServerCert(), cluster.NotifyAll)
+	if err != nil {
+		return err
+	}
+
+	err = notifier(func(member db.NodeInfo, client lxd.InstanceServer) error {
+		return client.UseProject(a.projectName).DeleteNetworkAddressSet(a.info.Name)
+	})
+	if err != nil {
+		return err
+	}
+
+	// The OVN address sets can only exist if the project has OVN networks.
+	var hasOVNNets bool
+	err = a.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		networks, err := tx.GetCreatedNetworksByProject(ctx, a.projectName)
+		if err != nil {
+			return err
+		}
+
+		for _, network := range networks {
+			if network.Type == "ovn" {
+				hasOVNNets = true
+				break
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var ovnClient ovnAddressSetClient
+	if hasOVNNets {
+		ovnClient, err = openvswitch.NewOVN(a.state.GlobalConfig.NetworkOVNNorthboundConnection(), a.state.GlobalConfig.NetworkOVNSSL)
+		if err != nil {
+			return fmt.Errorf("Failed to get OVN client: %w", err)
+		}
+	}
+
+	err = a.removeAddresses(a.state.Firewall, ovnClient)
+	if err != nil {
+		return err
+	}
+
+	return a.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.DeleteNetworkAddressSet(ctx, a.id)
+	})
+}
+
+// addressSetNetworkTypes returns whether the networks using the address set hold its addresses in firewall sets
+// and in OVN address sets.
+func addressSetNetworkTypes(aclNets map[string]NetworkACLUsage) (hasFirewallNets bool, hasOVNNets bool, err error) {
+	for _, aclNet := range aclNets {
+		switch aclNet.Type {
+		case "ovn":
+			hasOVNNets = true
+		case "bridge":
+			hasFirewallNets = true
+		default:
+			return false, false, fmt.Errorf("Unsupported network ACL type %q", aclNet.Type)
+		}
+	}
+
+	return hasFirewallNets, hasOVNNets, nil
+}
+
+// applyAddresses replaces the contents of the firewall sets and of the OVN address sets of the address set.
+// A nil firewall or OVN client is skipped.
+func (a *AddressSet) applyAddresses(firewall addressSetFirewall, ovnClient ovnAddressSetClient) error {
+	if firewall != nil {
+		err := firewall.NetworkApplyAddressSets([]firewallDrivers.AddressSet{a.firewallAddressSet()})
+		if err != nil {
+			return err
+		}
+	}
+
+	if ovnClient != nil {
+		err := ovnAddressSetApply(ovnClient, a.id, a.info.Addresses)
+		if err != nil {
+			return fmt.Errorf("Failed updating OVN address set: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// removeAddresses removes the firewall sets and the OVN address sets of the address set.
+// Failing to remove the firewall sets is only logged, as they may not exist. A nil OVN client is skipped.
+func (a *AddressSet) removeAddresses(firewall addressSetFirewall, ovnClient ovnAddressSetClient) error {
+	err := firewall.NetworkDeleteAddressSets([]string{firewallAddressSetName(a.id)})
+	if err != nil {
+		a.logger.Warn("Failed removing address set from firewall", logger.Ctx{"err": err})
+	}
+
+	if ovnClient != nil {
+		err = ovnClient.AddressSetDelete(OVNAddressSetPrefix(a.id))
+		if err != nil {
+			return fmt.Errorf("Failed removing OVN address set: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// firewallAddressSet returns the firewall representation of the address set.
+func (a *AddressSet) firewallAddressSet() firewallDrivers.AddressSet {
+	return firewallDrivers.AddressSet{
+		Name:      firewallAddressSetName(a.id),
+		Addresses: a.info.Addresses,
+	}
+}
+
+// firewallAddressSetName returns the name of the firewall sets used for the address set ID.
+func firewallAddressSetName(addressSetID int64) string {
+	return fmt.Sprintf("lxd_addrset%d", addressSetID)
+}
+
+// OVNAddressSetPrefix returns the prefix of the OVN address sets used for the address set ID.
+func OVNAddressSetPrefix(addressSetID int64) openvswitch.OVNAddressSet {
+	return openvswitch.OVNAddressSet(fmt.Sprintf("lxd_addrset%d", addressSetID))
+}
+
+// ovnAddressSetApply replaces the contents of the OVN address sets used for the address set ID.
+func ovnAddressSetApply(client ovnAddressSetClient, addressSetID int64, addresses []string) error {
+	ipNets := make([]net.IPNet, 0, len(addresses))
+	for _, address := range addresses {
+		ip := net.ParseIP(address)
+		if ip != nil {
+			bits := 32
+			if ip.To4() == nil {
+				bits = 128
+			}
+
+			ipNets = append(ipNets, net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, ipNet, err := net.ParseCIDR(address)
+		if err != nil {
+			return err
+		}
+
+		ipNets = append(ipNets, *ipNet)
+	}
+
+	return client.AddressSetSet(OVNAddressSetPrefix(addressSetID), ipNets...)
+}
+
+// addressSetReferences returns the names of the address sets referenced by the rules of the ACL.
+func addressSetReferences(info *api.NetworkACL) []string {
+	var names []string
+
+	addNamesFrom := func(rules []api.NetworkACLRule) {
+		for _, rule := range rules {
+			for _, subject := range shared.SplitNTrimSpace(rule.Source+","+rule.Destination, ",", -1, true) {
+				name, found := strings.CutPrefix(subject, addressSetSubjectPrefix)
+				if found && !slices.Contains(names, name) {
+					names = append(names, name)
+				}
+			}
+		}
+	}
+
+	addNamesFrom(info.Ingress)
+	addNamesFrom(info.Egress)
+
+	return names
+}
@@ -0,0 +1,181 @@
+package acl
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	firewallDrivers "github.com/canonical/lxd/lxd/firewall/drivers"
+	"github.com/canonical/lxd/lxd/network/openvswitch"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// testAddressSetFirewall records the address sets applied to the firewall.
+type testAddressSetFirewall struct {
+	sets      map[string][]string
+	deleteErr error
+}
+
+// NetworkApplyAddressSets replaces the contents of the sets.
+func (f *testAddressSetFirewall) NetworkApplyAddressSets(sets []firewallDrivers.AddressSet) error {
+	for _, set := range sets {
+		f.sets[set.Name] = set.Addresses
+	}
+
+	return nil
+}
+
+// NetworkDeleteAddressSets removes the sets.
+func (f *testAddressSetFirewall) NetworkDeleteAddressSets(names []string) error {
+	for _, name := range names {
+		delete(f.sets, name)
+	}
+
+	return f.deleteErr
+}
+
+// testOVNAddressSetClient records the OVN address sets.
+type testOVNAddressSetClient struct {
+	sets map[openvswitch.OVNAddressSet][]net.IPNet
+}
+
+// AddressSetSet replaces the contents of the address sets.
+func (c *testOVNAddressSetClient) AddressSetSet(addressSetPrefix openvswitch.OVNAddressSet, addresses ...net.IPNet) error {
+	c.sets[addressSetPrefix] = addresses
+	return nil
+}
+
+// AddressSetDelete removes the address sets.
+func (c *testOVNAddressSetClient) AddressSetDelete(addressSetPrefix openvswitch.OVNAddressSet) error {
+	_, found := c.sets[addressSetPrefix]
+	if !found {
+		return errors.New("Address set not found")
+	}
+
+	delete(c.sets, addressSetPrefix)
+	return nil
+}
+
+// testAddressSet returns an address set with the addresses.
+func testAddressSet(id int64, addresses ...string) *AddressSet {
+	return &AddressSet{
+		logger:      logger.AddContext(logger.Ctx{"networkAddressSet": "allowlist"}),
+		id:          id,
+		projectName: "default",
+		info: &api.NetworkAddressSet{
+			Name:      "allowlist",
+			Addresses: addresses,
+		},
+	}
+}
+
+func Test_addressSetNetworkTypes(t *testing.T) {
+	tests := []struct {
+		name         string
+		aclNets      map[string]NetworkACLUsage
+		wantFirewall bool
+		wantOVN      bool
+		wantErr      bool
+	}{
+		{
+			name:    "Unused",
+			aclNets: map[string]NetworkACLUsage{},
+		},
+		{
+			name:         "Bridge networks",
+			aclNets:      map[string]NetworkACLUsage{"lxdbr0": {Name: "lxdbr0", Type: "bridge"}},
+			wantFirewall: true,
+		},
+		{
+			name:         "Bridge and OVN networks",
+			aclNets:      map[string]NetworkACLUsage{"lxdbr0": {Name: "lxdbr0", Type: "bridge"}, "ovn1": {Name: "ovn1", Type: "ovn"}},
+			wantFirewall: true,
+			wantOVN:      true,
+		},
+		{
+			name:    "Unsupported network type",
+			aclNets: map[string]NetworkACLUsage{"phys0": {Name: "phys0", Type: "physical"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hasFirewallNets, hasOVNNets, err := addressSetNetworkTypes(tt.aclNets)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFirewall, hasFirewallNets)
+			assert.Equal(t, tt.wantOVN, hasOVNNets)
+		})
+	}
+}
+
+func Test_AddressSet_applyAddresses(t *testing.T) {
+	firewall := &testAddressSetFirewall{sets: map[string][]string{"lxd_addrset4": {"198.51.100.1"}}}
+	ovnClient := &testOVNAddressSetClient{sets: map[openvswitch.OVNAddressSet][]net.IPNet{}}
+
+	a := testAddressSet(3, "192.0.2.1", "192.0.2.0/24", "2001:db8::1", "2001:db8:1::/64")
+
+	// The updated addresses replace the contents of the sets.
+	require.NoError(t, a.applyAddresses(firewall, ovnClient))
+	assert.Equal(t, []string{"192.0.2.1", "192.0.2.0/24", "2001:db8::1", "2001:db8:1::/64"}, firewall.sets["lxd_addrset3"])
+	assert.Equal(t, []string{"198.51.100.1"}, firewall.sets["lxd_addrset4"])
+	assert.Equal(t, []net.IPNet{
+		{IP: net.ParseIP("192.0.2.1"), Mask: net.CIDRMask(32, 32)},
+		{IP: net.IPv4(192, 0, 2, 0).To4(), Mask: net.CIDRMask(24, 32)},
+		{IP: net.ParseIP("2001:db8::1"), Mask: net.CIDRMask(128, 128)},
+		{IP: net.ParseIP("2001:db8:1::"), Mask: net.CIDRMask(64, 128)},
+	}, ovnClient.sets["lxd_addrset3"])
+
+	a.info.Addresses = []string{"192.0.2.2"}
+	require.NoError(t, a.applyAddresses(firewall, ovnClient))
+	assert.Equal(t, []string{"192.0.2.2"}, firewall.sets["lxd_addrset3"])
+	assert.Len(t, ovnClient.sets["lxd_addrset3"], 1)
+
+	// Only the firewall sets are updated when the networks don't use OVN or the OVN address sets were
+	// already updated by another member.
+	a.info.Addresses = []string{"192.0.2.3"}
+	require.NoError(t, a.applyAddresses(firewall, nil))
+	assert.Equal(t, []string{"192.0.2.3"}, firewall.sets["lxd_addrset3"])
+	assert.Equal(t, []net.IPNet{{IP: net.ParseIP("192.0.2.2"), Mask: net.CIDRMask(32, 32)}}, ovnClient.sets["lxd_addrset3"])
+
+	// Only the OVN address sets are updated when no network uses the firewall.
+	a.info.Addresses = []string{"192.0.2.4"}
+	require.NoError(t, a.applyAddresses(nil, ovnClient))
+	assert.Equal(t, []string{"192.0.2.3"}, firewall.sets["lxd_addrset3"])
+	assert.Equal(t, []net.IPNet{{IP: net.ParseIP("192.0.2.4"), Mask: net.CIDRMask(32, 32)}}, ovnClient.sets["lxd_addrset3"])
+}
+
+func Test_AddressSet_removeAddresses(t *testing.T) {
+	firewall := &testAddressSetFirewall{sets: map[string][]string{"lxd_addrset3": {"192.0.2.1"}, "lxd_addrset4": {"198.51.100.1"}}}
+	ovnClient := &testOVNAddressSetClient{sets: map[openvswitch.OVNAddressSet][]net.IPNet{"lxd_addrset3": {}, "lxd_addrset4": {}}}
+
+	a := testAddressSet(3, "192.0.2.1")
+
+	require.NoError(t, a.removeAddresses(firewall, ovnClient))
+	assert.Equal(t, map[string][]string{"lxd_addrset4": {"198.51.100.1"}}, firewall.sets)
+	assert.Equal(t, map[openvswitch.OVNAddressSet][]net.IPNet{"lxd_addrset4": {}}, ovnClient.sets)
+
+	// Failing to remove the firewall sets doesn't prevent removing the OVN address sets.
+	firewall.deleteErr = errors.New("Set not found")
+	a = testAddressSet(4)
+	require.NoError(t, a.removeAddresses(firewall, ovnClient))
+	assert.Empty(t, ovnClient.sets)
+
+	// Failing to remove the OVN address sets is an error.
+	assert.ErrorContains(t, a.removeAddresses(firewall, ovnClient), "Failed removing OVN address set")
+
+	// Cluster members only remove their firewall sets.
+	firewall.deleteErr = nil
+	firewall.sets["lxd_addrset4"] = []string{"198.51.100.1"}
+	require.NoError(t, a.removeAddresses(firewall, nil))
+	assert.Empty(t, firewall.sets)
+}
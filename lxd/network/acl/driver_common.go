@@ -3,6 +3,7 @@ package acl
 import (
 	"bufio"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
@@ -11,6 +12,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/canonical/lxd/client"
 	"github.com/canonical/lxd/lxd/cluster"
@@ -18,6 +20,7 @@ import (
 	"github.com/canonical/lxd/lxd/db"
 	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
 	"github.com/canonical/lxd/lxd/network/openvswitch"
+	"github.com/canonical/lxd/lxd/project"
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/state"
 	"github.com/canonical/lxd/lxd/util"
@@ -283,13 +286,25 @@ func (d *common) validateRule(direction ruleDirection, rule api.NetworkACLRule)
 		return fmt.Errorf("State must be one of: %s", strings.Join(validStates, ", "))
 	}
 
+	// Validate ActiveFrom and ActiveUntil fields.
+	_, _, err := ruleTimeBounds(rule)
+	if err != nil {
+		return err
+	}
+
 	var acls map[string]int64
+	var addressSetNames []string
 
-	err := d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+	err = d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 		var err error
 
 		// Get map of ACL names to DB IDs (used for generating OVN port group names).
 		acls, err = tx.GetNetworkACLIDsByNames(ctx, d.Project())
+		if err != nil {
+			return err
+		}
+
+		addressSetNames, err = tx.GetNetworkAddressSets(ctx, d.Project())
 
 		return err
 	})
@@ -310,7 +325,7 @@ func (d *common) validateRule(direction ruleDirection, rule api.NetworkACLRule)
 
 	// Validate Source field.
 	if rule.Source != "" {
-		srcHasName, srcHasIPv4, srcHasIPv6, err = d.validateRuleSubjects("Source", direction, shared.SplitNTrimSpace(rule.Source, ",", -1, false), validSubjectNames)
+		srcHasName, srcHasIPv4, srcHasIPv6, err = d.validateRuleSubjects("Source", direction, shared.SplitNTrimSpace(rule.Source, ",", -1, false), validSubjectNames, addressSetNames)
 		if err != nil {
 			return fmt.Errorf("Invalid Source: %w", err)
 		}
@@ -318,7 +333,7 @@ func (d *common) validateRule(direction ruleDirection, rule api.NetworkACLRule)
 
 	// Validate Destination field.
 	if rule.Destination != "" {
-		dstHasName, dstHasIPv4, dstHasIPv6, err = d.validateRuleSubjects("Destination", direction, shared.SplitNTrimSpace(rule.Destination, ",", -1, false), validSubjectNames)
+		dstHasName, dstHasIPv4, dstHasIPv6, err = d.validateRuleSubjects("Destination", direction, shared.SplitNTrimSpace(rule.Destination, ",", -1, false), validSubjectNames, addressSetNames)
 		if err != nil {
 			return fmt.Errorf("Invalid Destination: %w", err)
 		}
@@ -433,9 +448,10 @@ func (d *common) validateRule(direction ruleDirection, rule api.NetworkACLRule)
 }
 
 // validateRuleSubjects checks that the source or destination subjects for a rule are valid.
-// Accepts a validSubjectNames list of valid ACL or special classifier names.
-// Returns whether the subjects include names, IPv4 and IPv6 addresses respectively.
-func (d *common) validateRuleSubjects(fieldName string, direction ruleDirection, subjects []string, validSubjectNames []string) (hasName bool, hasIPv4 bool, hasIPv6 bool, err error) {
+// Accepts a validSubjectNames list of valid ACL or special classifier names and a validAddressSetNames list of
+// address sets that can be referenced using the "$<name>" format in either field.
+// Returns whether the subjects include names (or address sets), IPv4 and IPv6 addresses respectively.
+func (d *common) validateRuleSubjects(fieldName string, direction ruleDirection, subjects []string, validSubjectNames []string, validAddressSetNames []string) (hasName bool, hasIPv4 bool, hasIPv6 bool, err error) {
 	// Check if named subjects are allowed in field/direction combination.
 	allowSubjectNames := (fieldName == "Source" && direction == ruleDirectionIngress) || (fieldName == "Destination" && direction == ruleDirectionEgress)
 
@@ -503,6 +519,16 @@ func (d *common) validateRuleSubjects(fieldName string, direction ruleDirection,
 			}
 		}
 
+		// Check if it references an address set (which can contain addresses of either IP family).
+		addressSetName, found := strings.CutPrefix(subject, addressSetSubjectPrefix)
+		if found {
+			if slices.Contains(validAddressSetNames, addressSetName) {
+				return 0, nil // Found valid subject.
+			}
+
+			return 0, fmt.Errorf("Address set %q not found", addressSetName)
+		}
+
 		// Check if it is one of the valid subject names.
 		for _, n := range validSubjectNames {
 			if subject == n {
@@ -832,3 +858,244 @@ func (d *common) GetLog(ctx context.Context, clientType request.ClientType) (str
 
 	return strings.Join(logEntries, "\n") + "\n", nil
 }
+
+// GetState gets the counters of the ACL rules.
+func (d *common) GetState(clientType request.ClientType) (*api.NetworkACLState, error) {
+	aclNets := map[string]NetworkACLUsage{}
+	err := NetworkUsage(d.state, d.projectName, []string{d.info.Name}, aclNets)
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting ACL network usage: %w", err)
+	}
+
+	// Get the counters of the rules applied to the networks on this member.
+	counters := map[string]api.NetworkACLRuleState{}
+	usedByOVN := false
+	for _, aclNet := range aclNets {
+		if aclNet.Type == "ovn" {
+			usedByOVN = true
+			continue
+		}
+
+		err = firewallACLRuleCounters(d.state, aclNet, counters)
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting ACL rule counters for network %q: %w", aclNet.Name, err)
+		}
+	}
+
+	// OVN networks share the ACL rules, so only get their counters once.
+	if usedByOVN {
+		err = ovnACLRuleCounters(d.state, d.id, counters)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	aclState := ruleStates(d.id, d.info, counters)
+
+	// Add the counters from the rest of the cluster.
+	if clientType == request.ClientTypeNormal && len(aclNets) > 0 {
+		notifier, err := cluster.NewNotifier(d.state, d.state.Endpoints.NetworkCert(), d.state.ServerCert(), cluster.NotifyAll)
+		if err != nil {
+			return nil, err
+		}
+
+		mu := sync.Mutex{}
+		err = notifier(func(member db.NodeInfo, client lxd.InstanceServer) error {
+			memberState, err := client.UseProject(d.projectName).GetNetworkACLState(d.info.Name)
+			if err != nil {
+				return err
+			}
+
+			// Prevent concurrent writes to the counters.
+			mu.Lock()
+			defer mu.Unlock()
+
+			addRuleCounters(aclState.Egress, memberState.Egress)
+			addRuleCounters(aclState.Ingress, memberState.Ingress)
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return aclState, nil
+}
+
+// ruleStates returns the state of the rules of the ACL from the counters indexed by rule name.
+func ruleStates(aclID int64, info *api.NetworkACL, counters map[string]api.NetworkACLRuleState) *api.NetworkACLState {
+	aclState := &api.NetworkACLState{
+		Egress:  make([]api.NetworkACLRuleState, len(info.Egress)),
+		Ingress: make([]api.NetworkACLRuleState, len(info.Ingress)),
+	}
+
+	for i := range aclState.Egress {
+		aclState.Egress[i] = counters[ruleName(aclID, string(ruleDirectionEgress), i)]
+	}
+
+	for i := range aclState.Ingress {
+		aclState.Ingress[i] = counters[ruleName(aclID, string(ruleDirectionIngress), i)]
+	}
+
+	return aclState
+}
+
+// addRuleCounters adds the rule counters of a cluster member to the total.
+// Counters of rules the member doesn't know about yet are ignored.
+func addRuleCounters(total []api.NetworkACLRuleState, member []api.NetworkACLRuleState) {
+	for i := range min(len(total), len(member)) {
+		total[i].Packets += member[i].Packets
+		total[i].Bytes += member[i].Bytes
+	}
+}
+
+// FollowLog calls handler with the entries logged by the ACL rules until the context is done.
+// The entries logged on the rest of the cluster are also followed if clientType is normal.
+func (d *common) FollowLog(ctx context.Context, clientType request.ClientType, handler func(entry api.NetworkACLLogEntry) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Prevent concurrent calls to the handler.
+	mu := sync.Mutex{}
+	send := func(entry api.NetworkACLLogEntry) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return handler(entry)
+	}
+
+	// Follow the entries from the rest of the cluster in the background.
+	remoteErr := make(chan error, 1)
+	if clientType == request.ClientTypeNormal {
+		notifier, err := cluster.NewNotifier(d.state, d.state.Endpoints.NetworkCert(), d.state.ServerCert(), cluster.NotifyAll)
+		if err != nil {
+			return err
+		}
+
+		go func() {
+			remoteErr <- notifier(func(member db.NodeInfo, client lxd.InstanceServer) error {
+				// Stop following the local entries too rather than silently missing the member's ones.
+				stream, err := client.UseProject(d.projectName).GetNetworkACLLogStream(d.info.Name)
+				if err != nil {
+					cancel()
+					return fmt.Errorf("Failed following ACL log entries from member %q: %w", member.Name, err)
+				}
+
+				// Stop following the member's entries when done.
+				context.AfterFunc(ctx, func() { _ = stream.Close() })
+
+				decoder := json.NewDecoder(stream)
+				for {
+					var entry api.NetworkACLLogEntry
+					err := decoder.Decode(&entry)
+					if ctx.Err() != nil {
+						return nil
+					} else if err != nil {
+						cancel()
+						return fmt.Errorf("Failed reading ACL log entries from member %q: %w", member.Name, err)
+					}
+
+					err = send(entry)
+					if err != nil {
+						cancel()
+						return err
+					}
+				}
+			})
+		}()
+	} else {
+		remoteErr <- nil
+	}
+
+	// Follow the entries of this member.
+	instances := newLogInstances(d.state, d.projectName)
+	prefix := fmt.Sprintf("lxd_acl%d-", d.id)
+
+	err := ovnFollowLogEntries(ctx, prefix, func(entry *api.NetworkACLLogEntry, instanceMAC string) error {
+		if d.state.ServerClustered {
+			entry.Location = d.state.ServerName
+		}
+
+		entry.Project, entry.Instance = instances.lookup(ctx, instanceMAC)
+
+		return send(*entry)
+	})
+
+	cancel()
+
+	// Wait for the rest of the cluster to be done.
+	remoteDoneErr := <-remoteErr
+	if err != nil {
+		return err
+	}
+
+	return remoteDoneErr
+}
+
+// logInstances resolves the MAC addresses found in the ACL log entries to the instances using them.
+type logInstances struct {
+	state       *state.State
+	projectName string
+
+	macs     map[string][2]string // Project and instance names indexed by MAC address.
+	loadedAt time.Time
+}
+
+// newLogInstances returns a resolver for the instances using the networks of the project.
+func newLogInstances(s *state.State, projectName string) *logInstances {
+	return &logInstances{
+		state:       s,
+		projectName: projectName,
+	}
+}
+
+// lookup returns the project and name of the instance using the MAC address.
+// The instances are loaded again at most every 10s when the MAC address is unknown.
+func (l *logInstances) lookup(ctx context.Context, mac string) (string, string) {
+	hwaddr, err := net.ParseMAC(mac)
+	if err != nil {
+		return "", ""
+	}
+
+	mac = hwaddr.String()
+
+	inst, found := l.macs[mac]
+	if found || time.Since(l.loadedAt) < 10*time.Second {
+		return inst[0], inst[1]
+	}
+
+	macs := map[string][2]string{}
+	err = l.state.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.InstanceList(ctx, func(inst db.InstanceArgs, p api.Project) error {
+			// Skip instances whose effective network project doesn't match this Network ACL's project.
+			if project.NetworkProjectFromRecord(&p) != l.projectName {
+				return nil
+			}
+
+			for key, value := range inst.Config {
+				if !strings.HasPrefix(key, "volatile.") || !strings.HasSuffix(key, ".hwaddr") {
+					continue
+				}
+
+				hwaddr, err := net.ParseMAC(value)
+				if err == nil {
+					macs[hwaddr.String()] = [2]string{inst.Project, inst.Name}
+				}
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		logger.Warn("Failed loading instances for ACL log entries", logger.Ctx{"project": l.projectName, "err": err})
+		return "", ""
+	}
+
+	l.macs = macs
+	l.loadedAt = time.Now()
+
+	inst = l.macs[mac]
+
+	return inst[0], inst[1]
+}
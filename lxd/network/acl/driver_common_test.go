@@ -0,0 +1,42 @@
+package acl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+func Test_ruleStates(t *testing.T) {
+	info := &api.NetworkACL{
+		Ingress: []api.NetworkACLRule{{Action: "allow"}, {Action: "drop"}},
+		Egress:  []api.NetworkACLRule{{Action: "reject"}},
+	}
+
+	counters := map[string]api.NetworkACLRuleState{
+		ruleName(12, "ingress", 1): {Packets: 2, Bytes: 200},
+		ruleName(12, "egress", 0):  {Packets: 3, Bytes: 300},
+		ruleName(13, "ingress", 0): {Packets: 4, Bytes: 400}, // Rule of another ACL.
+		ruleName(12, "egress", 1):  {Packets: 5, Bytes: 500}, // Rule deleted since.
+	}
+
+	want := &api.NetworkACLState{
+		Ingress: []api.NetworkACLRuleState{{}, {Packets: 2, Bytes: 200}},
+		Egress:  []api.NetworkACLRuleState{{Packets: 3, Bytes: 300}},
+	}
+
+	assert.Equal(t, want, ruleStates(12, info, counters))
+}
+
+func Test_addRuleCounters(t *testing.T) {
+	total := []api.NetworkACLRuleState{{Packets: 1, Bytes: 100}, {Packets: 2, Bytes: 200}}
+
+	// A member not knowing about the latest rules yet.
+	addRuleCounters(total, []api.NetworkACLRuleState{{Packets: 3, Bytes: 300}})
+	assert.Equal(t, []api.NetworkACLRuleState{{Packets: 4, Bytes: 400}, {Packets: 2, Bytes: 200}}, total)
+
+	// A member knowing about rules that were deleted since.
+	addRuleCounters(total, []api.NetworkACLRuleState{{Packets: 1, Bytes: 10}, {Packets: 1, Bytes: 10}, {Packets: 1, Bytes: 10}})
+	assert.Equal(t, []api.NetworkACLRuleState{{Packets: 5, Bytes: 410}, {Packets: 3, Bytes: 210}}, total)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"io/fs"
 	"maps"
 	"net"
 	"net/http"
@@ -21,6 +22,7 @@ import (
 	lxd "github.com/canonical/lxd/client"
 	"github.com/canonical/lxd/lxd/apparmor"
 	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/config"
 	"github.com/canonical/lxd/lxd/daemon"
 	"github.com/canonical/lxd/lxd/db"
 	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
@@ -71,6 +73,8 @@ func (n *bridge) DBType() db.NetworkType {
 func (n *bridge) Info() Info {
 	info := n.common.Info()
 	info.AddressForwards = true
+	info.LoadBalancers = true
+	info.Peering = true
 
 	return info
 }
@@ -234,6 +238,38 @@ func (n *bridge) Validate(config map[string]string) error {
 		//  shortdesc: Peer session hold time
 		//  scope: global
 
+		// lxdmeta:generate(entities=network-bridge; group=network-conf; key=bgp.peers.NAME.keepalive)
+		// Specify the interval in seconds between the keepalive messages sent to the peer.
+		// Lower values, along with a lower hold time, let the peer detect a failed session faster.
+		// ---
+		//  type: integer
+		//  condition: BGP server
+		//  defaultdesc: a third of the hold time
+		//  required: no
+		//  shortdesc: Peer session keepalive interval
+		//  scope: global
+
+		// lxdmeta:generate(entities=network-bridge; group=network-conf; key=bgp.peers.NAME.graceful_restart)
+		// When enabled, the peer keeps the routes advertised by LXD while LXD restarts.
+		// LXD only establishes the sessions again after its networks and instances have started, so that the peer doesn't withdraw the routes of the instances that aren't advertised yet.
+		// ---
+		//  type: bool
+		//  condition: BGP server
+		//  defaultdesc: `true`
+		//  required: no
+		//  shortdesc: Whether to enable graceful restart for the peer session
+		//  scope: global
+
+		// lxdmeta:generate(entities=network-bridge; group=network-conf; key=bgp.peers.NAME.graceful_restart_time)
+		// Specify the time in seconds.
+		// ---
+		//  type: integer
+		//  condition: BGP server
+		//  defaultdesc: `3600`
+		//  required: no
+		//  shortdesc: Time for which the peer keeps the routes while waiting for the session to be re-established
+		//  scope: global
+
 		// lxdmeta:generate(entities=network-bridge; group=network-conf; key=bgp.ipv4.nexthop)
 		//
 		// ---
@@ -252,6 +288,60 @@ func (n *bridge) Validate(config map[string]string) error {
 		//  shortdesc: Override the IPv6 next-hop for advertised prefixes
 		//  scope: local
 		"bgp.ipv6.nexthop": validate.Optional(validate.IsNetworkAddressV6),
+		// lxdmeta:generate(entities=network-bridge; group=network-conf; key=bgp.export.communities)
+		// Specify a comma-separated list of standard communities in the `ASN:VALUE` format.
+		// ---
+		//  type: string
+		//  condition: BGP server
+		//  shortdesc: Communities attached to the prefixes advertised for the network
+		//  scope: global
+		"bgp.export.communities": validate.Optional(validate.IsListOf(bgpValidateCommunity)),
+		// lxdmeta:generate(entities=network-bridge; group=network-conf; key=bgp.export.med)
+		// Routers prefer the prefixes advertised with the lowest value.
+		// ---
+		//  type: integer
+		//  condition: BGP server
+		//  defaultdesc: (not advertised)
+		//  shortdesc: Multi-exit discriminator of the prefixes advertised for the network
+		//  scope: global
+		"bgp.export.med": validate.Optional(validate.IsUint32),
+		// lxdmeta:generate(entities=network-bridge; group=network-conf; key=bgp.export.prepend)
+		// Routers usually prefer the prefixes advertised with the shortest AS path.
+		// ---
+		//  type: integer
+		//  condition: BGP server
+		//  defaultdesc: `0`
+		//  shortdesc: Number of times the local ASN is prepended to the AS path of the prefixes advertised for the network
+		//  scope: global
+		"bgp.export.prepend": validate.Optional(validate.IsInRange(0, 32)),
+		// lxdmeta:generate(entities=network-bridge; group=network-conf; key=bgp.import)
+		// The routes received from the peers of the network are installed in the routing table of the host, through the interface of the network.
+		// ---
+		//  type: bool
+		//  condition: BGP server
+		//  defaultdesc: `false`
+		//  shortdesc: Whether to import the routes received from the BGP peers
+		//  scope: global
+		"bgp.import": validate.Optional(validate.IsBool),
+		// lxdmeta:generate(entities=network-bridge; group=network-conf; key=bgp.import.prefixes)
+		// Specify a comma-separated list of subnets.
+		// Only the routes that are part of one of these subnets are imported, and it must be set when {config:option}`network-bridge-network-conf:bgp.import` is enabled.
+		// The default route is only imported if `0.0.0.0/0` or `::/0` is listed.
+		// ---
+		//  type: string
+		//  condition: BGP server
+		//  shortdesc: Subnets the imported routes must be part of
+		//  scope: global
+		"bgp.import.prefixes": validate.Optional(validate.IsListOf(validate.IsNetwork)),
+		// lxdmeta:generate(entities=network-bridge; group=network-conf; key=bgp.import.table)
+		// Specify the numeric ID of the routing table.
+		// ---
+		//  type: integer
+		//  condition: BGP server
+		//  defaultdesc: main routing table
+		//  shortdesc: Routing table the imported routes are installed in
+		//  scope: global
+		"bgp.import.table": validate.Optional(validate.IsInRange(1, 4294967295)),
 		// lxdmeta:generate(entities=network-bridge; group=network-conf; key=bridge.driver)
 		// Possible values are `native` and `openvswitch`.
 		// ---
@@ -423,6 +513,23 @@ func (n *bridge) Validate(config map[string]string) error {
 		//  shortdesc: IPv4 ranges to use for DHCP
 		//  scope: global
 		"ipv4.dhcp.ranges": validate.Optional(validate.IsListOf(validate.IsNetworkRangeV4)),
+		// lxdmeta:generate(entities=network-bridge; group=network-conf; key=ipv4.dhcp.boot.filename)
+		// This is the boot file name that PXE clients load from the server set in {config:option}`network-bridge-network-conf:ipv4.dhcp.boot.next_server`.
+		// ---
+		//  type: string
+		//  condition: IPv4 DHCP
+		//  shortdesc: Boot file name for network booting
+		//  scope: global
+		"ipv4.dhcp.boot.filename": validate.Optional(validateDHCPBootFilename),
+		// lxdmeta:generate(entities=network-bridge; group=network-conf; key=ipv4.dhcp.boot.next_server)
+		//
+		// ---
+		//  type: string
+		//  condition: IPv4 DHCP
+		//  defaultdesc: the bridge address
+		//  shortdesc: Address of the server to load the boot file from
+		//  scope: global
+		"ipv4.dhcp.boot.next_server": validate.Optional(validate.IsNetworkAddressV4),
 		// lxdmeta:generate(entities=network-bridge; group=network-conf; key=ipv4.routes)
 		// Specify a comma-separated list of IPv4 CIDR subnets.
 		// ---
@@ -674,6 +781,16 @@ func (n *bridge) Validate(config map[string]string) error {
 		//  shortdesc: Whether to log egress traffic that doesn’t match any ACL rule
 		//  scope: global
 		"security.acls.default.egress.logged": validate.Optional(validate.IsBool),
+		// lxdmeta:generate(entities=network-bridge; group=network-conf; key=wireguard.port)
+		// The WireGuard interface of the network listens on this port.
+		// It must be set to add WireGuard peers to the network, and each network must use a different port.
+		//
+		// See {ref}`network-bridge-wireguard`.
+		// ---
+		//  type: integer
+		//  shortdesc: UDP port for the WireGuard peers of the network
+		//  scope: global
+		"wireguard.port": validate.Optional(validate.IsInRange(1, 65535)),
 
 		// lxdmeta:generate(entities=network-bridge; group=network-conf; key=user.*)
 		//
@@ -775,6 +892,75 @@ func (n *bridge) Validate(config map[string]string) error {
 		}
 	}
 
+	// Add the DHCP options and reservations validation rules.
+	for k := range config {
+		if strings.HasPrefix(k, "ipv4.dhcp.option.") || strings.HasPrefix(k, "ipv6.dhcp.option.") {
+			// lxdmeta:generate(entities=network-bridge; group=network-conf; key=ipv4.dhcp.option.NAME)
+			// Replace `NAME` with the option number or the `dnsmasq` name of the option, for example `42` or `ntp-server`.
+			// The options of the instance NICs take precedence over the options of the network.
+			// ---
+			//  type: string
+			//  condition: IPv4 DHCP
+			//  shortdesc: Value of a custom DHCPv4 option
+			//  scope: global
+
+			// lxdmeta:generate(entities=network-bridge; group=network-conf; key=ipv6.dhcp.option.NAME)
+			// Replace `NAME` with the option number or the `dnsmasq` name of the option, for example `31` or `sntp-server`.
+			// The options of the instance NICs take precedence over the options of the network.
+			// ---
+			//  type: string
+			//  condition: IPv6 DHCP
+			//  shortdesc: Value of a custom DHCPv6 option
+			//  scope: global
+			name := k[len("ipv4.dhcp.option."):]
+			rules[k] = func(value string) error {
+				return dnsmasq.ValidateDHCPOption(name, value)
+			}
+
+			continue
+		}
+
+		name, ok := strings.CutPrefix(k, "dhcp.reservations.")
+		if !ok {
+			continue
+		}
+
+		name, reservationKey, _ := strings.Cut(name, ".")
+		if validate.IsHostname(name) != nil {
+			return fmt.Errorf("Invalid DHCP reservation name %q", name)
+		}
+
+		switch reservationKey {
+		case "hwaddr":
+			// lxdmeta:generate(entities=network-bridge; group=network-conf; key=dhcp.reservations.NAME.hwaddr)
+			// `NAME` is used as the host name of the reservation.
+			// ---
+			//  type: string
+			//  condition: DHCP
+			//  shortdesc: MAC address of the host the addresses are reserved for
+			//  scope: global
+			rules[k] = validate.IsNetworkMAC
+		case "ipv4.address":
+			// lxdmeta:generate(entities=network-bridge; group=network-conf; key=dhcp.reservations.NAME.ipv4.address)
+			//
+			// ---
+			//  type: string
+			//  condition: IPv4 DHCP
+			//  shortdesc: IPv4 address reserved for the host
+			//  scope: global
+			rules[k] = validate.Optional(validate.IsNetworkAddressV4)
+		case "ipv6.address":
+			// lxdmeta:generate(entities=network-bridge; group=network-conf; key=dhcp.reservations.NAME.ipv6.address)
+			//
+			// ---
+			//  type: string
+			//  condition: IPv6 stateful DHCP
+			//  shortdesc: IPv6 address reserved for the host
+			//  scope: global
+			rules[k] = validate.Optional(validate.IsNetworkAddressV6)
+		}
+	}
+
 	// Add the BGP validation rules.
 	bgpRules, err := n.bgpValidationRules(config)
 	if err != nil {
@@ -804,6 +990,24 @@ func (n *bridge) Validate(config map[string]string) error {
 		return err
 	}
 
+	// Check the DHCP reservations fit the network.
+	err = n.validateDHCPReservations(config)
+	if err != nil {
+		return err
+	}
+
+	// Check the DHCP reservations don't use the static addresses of the NICs.
+	err = n.validateDHCPReservationsNICs(config)
+	if err != nil {
+		return err
+	}
+
+	// Check the WireGuard interface name and port.
+	err = n.validateWireguard(config)
+	if err != nil {
+		return err
+	}
+
 	// Validate network name when used in fan mode.
 	bridgeMode := config["bridge.mode"]
 	if bridgeMode == "fan" && len(n.name) > 11 {
@@ -1101,7 +1305,7 @@ func (n *bridge) getDnsmasqArgs(bridge *ip.Bridge) ([]string, error) {
 		dnsmasqCmd = append(dnsmasqCmd, "--listen-address="+ipv4Address.String())
 		if n.DHCPv4Subnet() != nil {
 			if !slices.Contains(dnsmasqCmd, "--dhcp-no-override") {
-				dnsmasqCmd = append(dnsmasqCmd, "--dhcp-no-override", "--dhcp-authoritative", "--dhcp-leasefile="+shared.VarPath("networks", n.name, "dnsmasq.leases"), "--dhcp-hostsfile="+shared.VarPath("networks", n.name, "dnsmasq.hosts"))
+				dnsmasqCmd = append(dnsmasqCmd, "--dhcp-no-override", "--dhcp-authoritative", "--dhcp-leasefile="+shared.VarPath("networks", n.name, "dnsmasq.leases"), "--dhcp-hostsfile="+shared.VarPath("networks", n.name, "dnsmasq.hosts"), "--dhcp-optsdir="+shared.VarPath("networks", n.name, "dnsmasq.opts"))
 			}
 
 			if n.config["ipv4.dhcp.gateway"] != "" {
@@ -1117,6 +1321,19 @@ func (n *bridge) getDnsmasqArgs(bridge *ip.Bridge) ([]string, error) {
 				dnsmasqCmd = append(dnsmasqCmd, "--dhcp-option-force=119,"+strings.Trim(dnsSearch, " "))
 			}
 
+			for _, option := range dnsmasq.DHCPOptions(n.config, 4) {
+				dnsmasqCmd = append(dnsmasqCmd, "--dhcp-option="+option)
+			}
+
+			if n.config["ipv4.dhcp.boot.filename"] != "" {
+				nextServer := n.config["ipv4.dhcp.boot.next_server"]
+				if nextServer == "" {
+					nextServer = ipv4Address.String()
+				}
+
+				dnsmasqCmd = append(dnsmasqCmd, fmt.Sprintf("--dhcp-boot=%s,,%s", n.config["ipv4.dhcp.boot.filename"], nextServer))
+			}
+
 			expiry := "1h"
 			if n.config["ipv4.dhcp.expiry"] != "" {
 				expiry = n.config["ipv4.dhcp.expiry"]
@@ -1148,7 +1365,11 @@ func (n *bridge) getDnsmasqArgs(bridge *ip.Bridge) ([]string, error) {
 		if n.DHCPv6Subnet() != nil {
 			// Build DHCP configuration.
 			if !slices.Contains(dnsmasqCmd, "--dhcp-no-override") {
-				dnsmasqCmd = append(dnsmasqCmd, "--dhcp-no-override", "--dhcp-authoritative", "--dhcp-leasefile="+shared.VarPath("networks", n.name, "dnsmasq.leases"), "--dhcp-hostsfile="+shared.VarPath("networks", n.name, "dnsmasq.hosts"))
+				dnsmasqCmd = append(dnsmasqCmd, "--dhcp-no-override", "--dhcp-authoritative", "--dhcp-leasefile="+shared.VarPath("networks", n.name, "dnsmasq.leases"), "--dhcp-hostsfile="+shared.VarPath("networks", n.name, "dnsmasq.hosts"), "--dhcp-optsdir="+shared.VarPath("networks", n.name, "dnsmasq.opts"))
+			}
+
+			for _, option := range dnsmasq.DHCPOptions(n.config, 6) {
+				dnsmasqCmd = append(dnsmasqCmd, "--dhcp-option="+option)
 			}
 
 			expiry := "1h"
@@ -1173,6 +1394,13 @@ func (n *bridge) getDnsmasqArgs(bridge *ip.Bridge) ([]string, error) {
 		}
 	}
 
+	// Add the DHCP reservations of the hosts that aren't instances.
+	if slices.Contains(dnsmasqCmd, "--dhcp-no-override") {
+		for _, reservation := range dnsmasq.DHCPReservations(n.config) {
+			dnsmasqCmd = append(dnsmasqCmd, "--dhcp-host="+dnsmasq.DHCPReservationHost(reservation, n.config))
+		}
+	}
+
 	return dnsmasqCmd, nil
 }
 
@@ -1194,6 +1422,7 @@ func (n *bridge) addDnsmasqFanArgs(args []string, address string, fanMTU uint32)
 		fmt.Sprintf("--dhcp-option-force=26,%d", fanMTU),
 		"--dhcp-leasefile="+shared.VarPath("networks", n.name, "dnsmasq.leases"),
 		"--dhcp-hostsfile="+shared.VarPath("networks", n.name, "dnsmasq.hosts"),
+		"--dhcp-optsdir="+shared.VarPath("networks", n.name, "dnsmasq.opts"),
 		"--dhcp-range", fmt.Sprintf("%s,%s,%s", dhcpalloc.GetIP(hostSubnet, 2).String(), dhcpalloc.GetIP(hostSubnet, -2).String(), expiry))
 
 	return args, nil
@@ -1235,12 +1464,14 @@ func (n *bridge) startDnsmasq(dnsmasqCmd []string, dnsClustered bool, dnsCluster
 		dnsmasqCmd = append(dnsmasqCmd, "-g", n.state.OS.UnprivGroup)
 	}
 
-	// Create DHCP hosts directory.
-	dnsmasqHostDir := shared.VarPath("networks", n.name, "dnsmasq.hosts")
-	if !shared.PathExists(dnsmasqHostDir) {
-		err = os.MkdirAll(dnsmasqHostDir, 0755)
-		if err != nil {
-			return err
+	// Create DHCP hosts and options directories.
+	for _, dnsmasqDir := range []string{"dnsmasq.hosts", "dnsmasq.opts"} {
+		dnsmasqDirPath := shared.VarPath("networks", n.name, dnsmasqDir)
+		if !shared.PathExists(dnsmasqDirPath) {
+			err = os.MkdirAll(dnsmasqDirPath, 0755)
+			if err != nil {
+				return err
+			}
 		}
 	}
 
@@ -1695,8 +1926,9 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 			}
 
 			fwOpts.SNATV4 = &firewallDrivers.SNATOpts{
-				SNATAddress: srcIP,
-				Subnet:      subnet,
+				SNATAddress:       srcIP,
+				Subnet:            subnet,
+				ExcludeInterfaces: n.wireguardInterfaces(),
 			}
 
 			if n.config["ipv4.nat.order"] == "after" {
@@ -1860,8 +2092,9 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 			}
 
 			fwOpts.SNATV6 = &firewallDrivers.SNATOpts{
-				SNATAddress: srcIP,
-				Subnet:      subnet,
+				SNATAddress:       srcIP,
+				Subnet:            subnet,
+				ExcludeInterfaces: n.wireguardInterfaces(),
 			}
 
 			if n.config["ipv6.nat.order"] == "after" {
@@ -2184,6 +2417,12 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 		}
 	}
 
+	// Bring up the WireGuard interface.
+	err = n.wireguardSetup()
+	if err != nil {
+		return err
+	}
+
 	// Generate and load apparmor profiles.
 	err = apparmor.NetworkLoad(n.state.OS, n)
 	if err != nil {
@@ -2280,11 +2519,17 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 		return err
 	}
 
+	// Setup network load balancers.
+	err = n.loadBalancerSetupFirewall()
+	if err != nil {
+		return err
+	}
+
 	nodeEvacuated := n.state.DB.Cluster.LocalNodeIsEvacuated()
 
 	// Setup BGP.
 	if !nodeEvacuated {
-		err = n.bgpSetup(oldConfig)
+		err = n.bgpSetup(oldConfig, n.name)
 		if err != nil {
 			return err
 		}
@@ -2308,6 +2553,9 @@ func (n *bridge) Stop() error {
 		return err
 	}
 
+	// Stop checking the health of the load balancer backends.
+	loadBalancerHealthMonitorStop(n.name)
+
 	// Kill any existing dnsmasq and forkdns daemon for this network
 	err = dnsmasq.Kill(n.name, false)
 	if err != nil {
@@ -2392,7 +2640,7 @@ func (n *bridge) Restore() error {
 	n.logger.Debug("Restore")
 
 	// Setup BGP.
-	return n.bgpSetup(nil)
+	return n.bgpSetup(nil, n.name)
 }
 
 // Update updates the network. Accepts notification boolean indicating if this update request is coming from a
@@ -2621,611 +2869,1676 @@ func (n *bridge) getTunnels() []string {
 	return tunnels
 }
 
-// bootRoutesV4 returns a list of IPv4 boot routes on the network's device.
-func (n *bridge) bootRoutesV4() ([]string, error) {
-	r := &ip.Route{
-		DevName: n.name,
-		Proto:   "boot",
-		Family:  ip.FamilyV4,
-	}
+// wireguardInterfaceName returns the name of the WireGuard interface of the network.
+func (n *bridge) wireguardInterfaceName() string {
+	return n.name + "-wg"
+}
 
-	routes, err := r.Show()
-	if err != nil {
-		return nil, err
+// wireguardInterfaces returns the interfaces whose traffic is routed to the WireGuard peers rather than translated.
+func (n *bridge) wireguardInterfaces() []string {
+	if n.config["wireguard.port"] == "" {
+		return nil
 	}
 
-	return routes, nil
+	return []string{n.wireguardInterfaceName()}
 }
 
-// bootRoutesV6 returns a list of IPv6 boot routes on the network's device.
-func (n *bridge) bootRoutesV6() ([]string, error) {
-	r := &ip.Route{
-		DevName: n.name,
-		Proto:   "boot",
-		Family:  ip.FamilyV6,
+// wireguardPrivateKey returns the private key of the WireGuard interface, generating it if requested.
+// The private key is kept on the local server and never leaves it.
+func (n *bridge) wireguardPrivateKey(generate bool) (string, error) {
+	keyPath := shared.VarPath("networks", n.name, "wireguard.key")
+
+	content, err := os.ReadFile(keyPath)
+	if err == nil {
+		return strings.TrimSpace(string(content)), nil
 	}
 
-	routes, err := r.Show()
+	if !errors.Is(err, fs.ErrNotExist) || !generate {
+		return "", err
+	}
+
+	key, err := ip.WireguardGenerateKey()
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	return routes, nil
-}
+	err = os.WriteFile(keyPath, []byte(key+"\n"), 0600)
+	if err != nil {
+		return "", fmt.Errorf("Failed writing WireGuard key: %w", err)
+	}
 
-// applyBootRoutesV4 applies a list of IPv4 boot routes to the network's device.
-func (n *bridge) applyBootRoutesV4(routes []string) {
-	for _, route := range routes {
-		r := &ip.Route{
-			DevName: n.name,
-			Proto:   "boot",
-			Family:  ip.FamilyV4,
-		}
+	return key, nil
+}
 
-		err := r.Replace(strings.Fields(route))
-		if err != nil {
-			// If it fails, then we can't stop as the route has already gone, so just log and continue.
-			n.logger.Error("Failed to restore route", logger.Ctx{"err": err})
-		}
+// wireguardPeerConfigs returns the WireGuard configuration of the peers, sorted by name.
+// Peers without the public key of their remote end are skipped.
+func (n *bridge) wireguardPeerConfigs(peers map[int64]*api.NetworkPeer) []ip.WireguardPeer {
+	peerNames := make([]string, 0, len(peers))
+	peersByName := make(map[string]*api.NetworkPeer, len(peers))
+	for _, peer := range peers {
+		peerNames = append(peerNames, peer.Name)
+		peersByName[peer.Name] = peer
 	}
-}
 
-// applyBootRoutesV6 applies a list of IPv6 boot routes to the network's device.
-func (n *bridge) applyBootRoutesV6(routes []string) {
-	for _, route := range routes {
-		r := &ip.Route{
-			DevName: n.name,
-			Proto:   "boot",
-			Family:  ip.FamilyV6,
-		}
+	slices.Sort(peerNames)
 
-		err := r.Replace(strings.Fields(route))
-		if err != nil {
-			// If it fails, then we can't stop as the route has already gone, so just log and continue.
-			n.logger.Error("Failed to restore route", logger.Ctx{"err": err})
+	peerConfigs := make([]ip.WireguardPeer, 0, len(peers))
+	for _, peerName := range peerNames {
+		peer := peersByName[peerName]
+		if peer.Config["wireguard.public_key"] == "" {
+			continue
 		}
+
+		peerConfigs = append(peerConfigs, ip.WireguardPeer{
+			PublicKey:  peer.Config["wireguard.public_key"],
+			Endpoint:   peer.Config["wireguard.endpoint"],
+			AllowedIPs: shared.SplitNTrimSpace(peer.Config["wireguard.routes"], ",", -1, true),
+		})
 	}
+
+	return peerConfigs
 }
 
-func (n *bridge) fanAddress(underlay *net.IPNet, overlay *net.IPNet) (cidr string, dev string, ipStr string, err error) {
-	// Quick checks.
-	underlaySize, _ := underlay.Mask.Size()
-	if underlaySize != 16 && underlaySize != 24 {
-		return "", "", "", errors.New("Only /16 or /24 underlays are supported at this time")
-	}
+// wireguardSetup brings up the WireGuard interface of the network with its peers and routes their subnets through it.
+// The interface is removed if wireguard.port isn't set.
+func (n *bridge) wireguardSetup() error {
+	wgName := n.wireguardInterfaceName()
+	wgExists := InterfaceExists(wgName)
 
-	overlaySize, _ := overlay.Mask.Size()
-	if overlaySize != 8 && overlaySize != 16 {
-		return "", "", "", errors.New("Only /8 or /16 overlays are supported at this time")
+	if n.config["wireguard.port"] == "" {
+		if wgExists {
+			wgLink := &ip.Link{Name: wgName}
+			return wgLink.Delete()
+		}
+
+		return nil
 	}
 
-	if overlaySize+(32-underlaySize)+8 > 32 {
-		return "", "", "", errors.New("Underlay or overlay networks too large to accommodate the FAN")
+	privateKey, err := n.wireguardPrivateKey(true)
+	if err != nil {
+		return err
 	}
 
-	// Get the IP
-	ip, dev, err := n.addressForSubnet(underlay)
+	listenPort, err := strconv.ParseUint(n.config["wireguard.port"], 10, 16)
 	if err != nil {
-		return "", "", "", err
+		return fmt.Errorf("Invalid WireGuard port: %w", err)
 	}
 
-	ipStr = ip.String()
+	var peers map[int64]*api.NetworkPeer
 
-	// Force into IPv4 format
-	ipBytes := ip.To4()
-	if ipBytes == nil {
-		return "", "", "", fmt.Errorf("Invalid IPv4: %s", ip)
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		peers, err = tx.GetNetworkPeers(ctx, n.ID())
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading network peers: %w", err)
 	}
 
-	// Compute the IP
-	ipBytes[0] = overlay.IP[0]
-	if overlaySize == 16 {
-		ipBytes[1] = overlay.IP[1]
-		ipBytes[2] = ipBytes[3]
-	} else if underlaySize == 24 {
-		ipBytes[1] = ipBytes[3]
-		ipBytes[2] = 0
-	} else if underlaySize == 16 {
-		ipBytes[1] = ipBytes[2]
-		ipBytes[2] = ipBytes[3]
+	wg := &ip.Wireguard{
+		Link:       ip.Link{Name: wgName},
+		PrivateKey: privateKey,
+		ListenPort: listenPort,
+		Peers:      n.wireguardPeerConfigs(peers),
 	}
 
-	ipBytes[3] = 1
+	if !wgExists {
+		err = wg.Add()
+		if err != nil {
+			return err
+		}
+	}
 
-	return ipBytes.String() + "/" + strconv.Itoa(overlaySize), dev, ipStr, err
-}
+	err = wg.Configure()
+	if err != nil {
+		return err
+	}
 
-func (n *bridge) addressForSubnet(subnet *net.IPNet) (net.IP, string, error) {
-	ifaces, err := net.Interfaces()
+	err = wg.SetUp()
 	if err != nil {
-		return net.IP{}, "", err
+		return err
 	}
 
-	for _, iface := range ifaces {
-		// Skip addresses on lo interface in case VIPs are being used on that interface that are part of
-		// the underlay subnet as is unlikely to be the actual intended underlay subnet interface.
-		if iface.Name == "lo" {
-			continue
+	// Route the subnets of the peers through the interface, replacing the routes of the previous peers.
+	for _, family := range []string{ip.FamilyV4, ip.FamilyV6} {
+		r := &ip.Route{
+			DevName: wgName,
+			Proto:   "static",
+			Family:  family,
 		}
 
-		addrs, err := iface.Addrs()
+		err = r.Flush()
 		if err != nil {
-			continue
+			return err
 		}
+	}
 
-		for _, addr := range addrs {
-			ip, _, err := net.ParseCIDR(addr.String())
-			if err != nil {
-				continue
+	for _, peer := range wg.Peers {
+		for _, route := range peer.AllowedIPs {
+			family := ip.FamilyV4
+			if validate.IsNetworkV6(route) == nil {
+				family = ip.FamilyV6
 			}
 
-			if subnet.Contains(ip) {
-				return ip, iface.Name, nil
+			r := &ip.Route{
+				DevName: wgName,
+				Route:   route,
+				Proto:   "static",
+				Family:  family,
+			}
+
+			err = r.Add()
+			if err != nil {
+				return err
 			}
 		}
 	}
 
-	return net.IP{}, "", errors.New("No address found in subnet")
+	return nil
 }
 
-func (n *bridge) killForkDNS() error {
-	// Check if we have a running forkdns at all
-	pidPath := shared.VarPath("networks", n.name, "forkdns.pid")
+// validateWireguard checks that the WireGuard interface of the network can be created with the port in the config.
+func (n *bridge) validateWireguard(config map[string]string) error {
+	if config["wireguard.port"] == "" {
+		if n.config["wireguard.port"] == "" {
+			return nil
+		}
 
-	// If the pid file doesn't exist, there is no process to kill.
-	if !shared.PathExists(pidPath) {
-		return nil
-	}
+		// The peers can't be reached without the WireGuard interface.
+		var peerNames map[int64]string
 
-	p, err := subprocess.ImportProcess(pidPath)
-	if err != nil {
-		return fmt.Errorf("Could not read pid file: %s", err)
+		err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			var err error
+
+			peerNames, err = tx.GetNetworkPeerNames(ctx, n.ID())
+
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("Failed loading network peers: %w", err)
+		}
+
+		if len(peerNames) > 0 {
+			return errors.New(`The "wireguard.port" option can't be unset while the network has WireGuard peers`)
+		}
+
+		return nil
 	}
 
-	err = p.Stop()
-	if err != nil && err != subprocess.ErrNotRunning {
-		return fmt.Errorf("Unable to kill dnsmasq: %s", err)
+	if len(n.wireguardInterfaceName()) > 15 {
+		return fmt.Errorf("Network name too long for WireGuard interface: %s", n.wireguardInterfaceName())
 	}
 
-	return nil
-}
+	for k := range config {
+		if strings.HasPrefix(k, "tunnel.wg.") {
+			return errors.New(`Tunnel name "wg" is reserved for the WireGuard interface`)
+		}
+	}
 
-// updateForkdnsServersFile takes a list of node addresses and writes them atomically to
-// the forkdns.servers file ready for forkdns to notice and re-apply its config.
-func (n *bridge) updateForkdnsServersFile(addresses []string) error {
-	// We don't want to race with ourselves here
-	forkdnsServersLock.Lock()
-	defer forkdnsServersLock.Unlock()
+	var projectNetworks map[string]map[int64]api.Network
 
-	permName := shared.VarPath("networks", n.name, ForkdnsServersListPath+"/"+ForkdnsServersListFile)
-	tmpName := permName + ".tmp"
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		projectNetworks, err = tx.GetCreatedNetworks(ctx)
 
-	// Open tmp file and truncate
-	tmpFile, err := os.Create(tmpName)
-	if err != nil {
 		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to load all networks: %w", err)
 	}
 
-	defer func() { _ = tmpFile.Close() }()
+	// Each WireGuard interface needs its own port.
+	for projectName, networks := range n.bridgeProjectNetworks(projectNetworks) {
+		for _, network := range networks {
+			if projectName == n.project && network.Name == n.name {
+				continue
+			}
 
-	for _, address := range addresses {
-		_, err := tmpFile.WriteString(address + "\n")
-		if err != nil {
-			return err
+			if network.Config["wireguard.port"] == config["wireguard.port"] {
+				return fmt.Errorf("WireGuard port %s is already used by network %q in project %q", config["wireguard.port"], network.Name, projectName)
+			}
 		}
 	}
 
-	err = tmpFile.Close()
-	if err != nil {
-		return err
-	}
+	return nil
+}
 
-	// Atomically rename finished file into permanent location so forkdns can pick it up.
-	err = os.Rename(tmpName, permName)
+// peerValidate validates the peer request.
+// Bridge network peers are WireGuard peers on a remote server.
+func (n *bridge) peerValidate(peerName string, peer *api.NetworkPeerPut) error {
+	err := acl.ValidName(peerName)
 	if err != nil {
 		return err
 	}
 
-	return nil
-}
+	if slices.Contains(acl.ReservedNetworkSubects, peerName) {
+		return fmt.Errorf("Name cannot be one of the reserved network subjects: %v", acl.ReservedNetworkSubects)
+	}
 
-// hasIPv4Firewall indicates whether the network has IPv4 firewall enabled.
-func (n *bridge) hasIPv4Firewall() bool {
-	// IPv4 firewall is only enabled if there is a bridge ipv4.address or fan mode, and ipv4.firewall enabled.
-	// When using fan bridge.mode, there can be an empty ipv4.address, so we assume it is active.
-	if (n.config["bridge.mode"] == "fan" || !slices.Contains([]string{"", "none"}, n.config["ipv4.address"])) && shared.IsTrueOrEmpty(n.config["ipv4.firewall"]) {
-		return true
+	rules := map[string]func(value string) error{
+		// lxdmeta:generate(entities=network-peering; group=peering-wireguard; key=wireguard.public_key)
+		// The public key of the remote end is shown by `lxc network info` on the remote server.
+		// The peer only exchanges traffic once this key is set.
+		// ---
+		//  type: string
+		//  shortdesc: Public key of the remote end
+		"wireguard.public_key": validate.Optional(validateWireguardKey),
+		// lxdmeta:generate(entities=network-peering; group=peering-wireguard; key=wireguard.endpoint)
+		// Specify the address and the {config:option}`network-bridge-network-conf:wireguard.port` of the remote server, in the `<address>:<port>` format.
+		// If not set, LXD waits for the remote end to connect.
+		// ---
+		//  type: string
+		//  shortdesc: Address and port of the remote end
+		"wireguard.endpoint": validate.Optional(validateWireguardEndpoint),
+		// lxdmeta:generate(entities=network-peering; group=peering-wireguard; key=wireguard.routes)
+		// Specify a comma-separated list of subnets.
+		// Traffic to these subnets is routed to the remote end without NAT, and traffic from them is accepted from the remote end.
+		// ---
+		//  type: string
+		//  required: yes
+		//  shortdesc: Subnets reachable through the remote end
+		"wireguard.routes": validate.Required(validate.IsListOf(validate.IsNetwork)),
 	}
 
-	return false
-}
+	for k, v := range peer.Config {
+		// User keys are not validated.
+		if config.IsUserConfig(k) {
+			continue
+		}
 
-// hasIPv6Firewall indicates whether the network has IPv6 firewall enabled.
-func (n *bridge) hasIPv6Firewall() bool {
-	// IPv6 firewall is only enabled if there is a bridge ipv6.address and ipv6.firewall enabled.
-	if !slices.Contains([]string{"", "none"}, n.config["ipv6.address"]) && shared.IsTrueOrEmpty(n.config["ipv6.firewall"]) {
-		return true
-	}
+		validator, found := rules[k]
+		if !found {
+			return fmt.Errorf("Invalid option %q", k)
+		}
 
-	return false
-}
+		err := validator(v)
+		if err != nil {
+			return fmt.Errorf("Invalid value for option %q: %w", k, err)
+		}
+	}
 
-// hasDHCPv4 indicates whether the network has DHCPv4 enabled.
-// An empty ipv4.dhcp setting indicates enabled by default.
-func (n *bridge) hasDHCPv4() bool {
-	return shared.IsTrueOrEmpty(n.config["ipv4.dhcp"])
-}
+	err = rules["wireguard.routes"](peer.Config["wireguard.routes"])
+	if err != nil {
+		return fmt.Errorf(`Invalid value for option "wireguard.routes": %w`, err)
+	}
 
-// hasDHCPv6 indicates whether the network has DHCPv6 enabled.
-// An empty ipv6.dhcp setting indicates enabled by default.
-func (n *bridge) hasDHCPv6() bool {
-	return shared.IsTrueOrEmpty(n.config["ipv6.dhcp"])
+	return nil
 }
 
-// DHCPv4Subnet returns the DHCPv4 subnet (if DHCP is enabled on network).
-func (n *bridge) DHCPv4Subnet() *net.IPNet {
-	// DHCP is disabled on this network.
-	if !n.hasDHCPv4() {
-		return nil
+// peerValidateRoutes checks that the routes of the peer don't overlap with the subnets of the network or the
+// routes of its other peers, as traffic to a subnet can only be routed to one peer.
+func (n *bridge) peerValidateRoutes(peerName string, peer *api.NetworkPeerPut, peers map[int64]*api.NetworkPeer) error {
+	routes, err := SubnetParseAppend(nil, shared.SplitNTrimSpace(peer.Config["wireguard.routes"], ",", -1, true)...)
+	if err != nil {
+		return err
 	}
 
-	// Fan mode. Extract DHCP subnet from fan bridge address. Only detectable once network has started.
-	// But if there is no address on the fan bridge then DHCP won't work anyway.
-	if n.config["bridge.mode"] == "fan" {
-		iface, err := net.InterfaceByName(n.name)
+	for _, key := range []string{"ipv4.address", "ipv6.address"} {
+		_, subnet, err := net.ParseCIDR(n.config[key])
 		if err != nil {
-			return nil
+			continue
 		}
 
-		addrs, err := iface.Addrs()
-		if err != nil {
-			return nil
+		for _, route := range routes {
+			if SubnetContains(route, subnet) || SubnetContains(subnet, route) {
+				return fmt.Errorf("Route %q overlaps with the network subnet %q", route.String(), subnet.String())
+			}
 		}
+	}
 
-		for _, addr := range addrs {
-			ip, subnet, err := net.ParseCIDR(addr.String())
-			if err != nil {
-				continue
-			}
+	for _, otherPeer := range peers {
+		if otherPeer.Name == peerName {
+			continue
+		}
 
-			if ip != nil && ip.To4() != nil && ip.IsGlobalUnicast() {
-				return subnet // Use first IPv4 unicast address on host for DHCP subnet.
-			}
+		otherRoutes, err := SubnetParseAppend(nil, shared.SplitNTrimSpace(otherPeer.Config["wireguard.routes"], ",", -1, true)...)
+		if err != nil {
+			return err
 		}
 
-		return nil // No addresses found, means DHCP must be disabled.
+		for _, route := range routes {
+			for _, otherRoute := range otherRoutes {
+				if SubnetContains(route, otherRoute) || SubnetContains(otherRoute, route) {
+					return fmt.Errorf("Route %q overlaps with route %q of peer %q", route.String(), otherRoute.String(), otherPeer.Name)
+				}
+			}
+		}
 	}
 
-	// Non-fan mode. Return configured bridge subnet directly.
-	_, subnet, err := net.ParseCIDR(n.config["ipv4.address"])
-	if err != nil {
-		return nil
+	return nil
+}
+
+// PeerCreate creates a WireGuard peer.
+func (n *bridge) PeerCreate(peer api.NetworkPeersPost) error {
+	if n.state.ServerClustered {
+		return api.StatusErrorf(http.StatusBadRequest, "WireGuard peers are only supported on standalone servers")
 	}
 
-	return subnet
-}
+	if peer.TargetNetwork != "" || peer.TargetProject != "" {
+		return api.StatusErrorf(http.StatusBadRequest, "Bridge network peers can't target another network")
+	}
 
-// DHCPv6Subnet returns the DHCPv6 subnet (if DHCP or SLAAC is enabled on network).
-func (n *bridge) DHCPv6Subnet() *net.IPNet {
-	// DHCP is disabled on this network.
-	if !n.hasDHCPv6() {
-		return nil
+	if n.config["wireguard.port"] == "" {
+		return api.StatusErrorf(http.StatusBadRequest, `The "wireguard.port" option of the network must be set to add WireGuard peers`)
 	}
 
-	_, subnet, err := net.ParseCIDR(n.config["ipv6.address"])
+	err := n.peerValidate(peer.Name, &peer.NetworkPeerPut)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	return subnet
-}
+	var peers map[int64]*api.NetworkPeer
 
-// forwardConvertToFirewallForward converts forwards into format compatible with the firewall package.
-func (n *bridge) forwardConvertToFirewallForwards(listenAddress net.IP, defaultTargetAddress net.IP, portMaps []*forwardPortMap) []firewallDrivers.AddressForward {
-	vips := make([]firewallDrivers.AddressForward, 0, len(portMaps)+1)
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		peers, err = tx.GetNetworkPeers(ctx, n.ID())
 
-	if defaultTargetAddress != nil {
-		vips = append(vips, firewallDrivers.AddressForward{
-			ListenAddress: listenAddress,
-			TargetAddress: defaultTargetAddress,
-		})
+		return err
+	})
+	if err != nil {
+		return err
 	}
 
-	for _, portMap := range portMaps {
-		vips = append(vips, firewallDrivers.AddressForward{
-			ListenAddress: listenAddress,
-			Protocol:      portMap.protocol,
-			TargetAddress: portMap.target.address,
-			ListenPorts:   portMap.listenPorts,
-			TargetPorts:   portMap.target.ports,
-		})
+	for _, existingPeer := range peers {
+		if peer.Name == existingPeer.Name {
+			return api.StatusErrorf(http.StatusConflict, "A peer for that name already exists")
+		}
+
+		if peer.Config["wireguard.public_key"] != "" && peer.Config["wireguard.public_key"] == existingPeer.Config["wireguard.public_key"] {
+			return api.StatusErrorf(http.StatusConflict, "A peer for that public key already exists")
+		}
 	}
 
-	return vips
-}
+	err = n.peerValidateRoutes(peer.Name, &peer.NetworkPeerPut, peers)
+	if err != nil {
+		return err
+	}
 
-// bridgeProjectNetworks takes a map of all networks in all projects and returns a filtered map of bridge networks.
-func (n *bridge) bridgeProjectNetworks(projectNetworks map[string]map[int64]api.Network) map[string][]*api.Network {
-	bridgeProjectNetworks := make(map[string][]*api.Network)
-	for netProject, networks := range projectNetworks {
-		for _, ni := range networks {
-			network := ni // Local var creating pointer to rather than iterator.
+	revert := revert.New()
+	defer revert.Fail()
 
-			// Skip non-bridge networks.
-			if network.Type != "bridge" {
-				continue
-			}
+	var peerID int64
 
-			if bridgeProjectNetworks[netProject] == nil {
-				bridgeProjectNetworks[netProject] = []*api.Network{&network}
-			} else {
-				bridgeProjectNetworks[netProject] = append(bridgeProjectNetworks[netProject], &network)
-			}
-		}
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		peerID, _, err = tx.CreateNetworkPeer(ctx, n.ID(), &peer)
+
+		return err
+	})
+	if err != nil {
+		return err
 	}
 
-	return bridgeProjectNetworks
-}
+	revert.Add(func() {
+		_ = n.state.DB.Cluster.DeleteNetworkPeer(n.ID(), peerID)
+		_ = n.peerApply()
+	})
 
-// bridgeNetworkExternalSubnets returns a list of external subnets used by bridge networks. Networks are considered
-// to be using external subnets for their ipv4.address and/or ipv6.address if they have NAT disabled, and/or if
-// they have external NAT addresses specified.
-func (n *bridge) bridgeNetworkExternalSubnets(bridgeProjectNetworks map[string][]*api.Network) ([]externalSubnetUsage, error) {
-	externalSubnets := make([]externalSubnetUsage, 0)
-	for netProject, networks := range bridgeProjectNetworks {
-		for _, netInfo := range networks {
-			for _, keyPrefix := range []string{"ipv4", "ipv6"} {
-				// If NAT is disabled, then network subnet is an external subnet.
-				if shared.IsFalseOrEmpty(netInfo.Config[keyPrefix+".nat"]) {
-					key := keyPrefix + ".address"
+	err = n.peerApply()
+	if err != nil {
+		return err
+	}
 
-					_, ipNet, err := net.ParseCIDR(netInfo.Config[key])
-					if err != nil {
-						continue // Skip invalid/unspecified network addresses.
-					}
+	revert.Success()
+	return nil
+}
 
-					externalSubnets = append(externalSubnets, externalSubnetUsage{
-						subnet:         *ipNet,
-						networkProject: netProject,
-						networkName:    netInfo.Name,
-						usageType:      subnetUsageNetwork,
-					})
-				}
+// PeerUpdate updates a WireGuard peer.
+func (n *bridge) PeerUpdate(peerName string, req api.NetworkPeerPut) error {
+	var curPeerID int64
+	var curPeer *api.NetworkPeer
+	var peers map[int64]*api.NetworkPeer
 
-				// Find any external subnets used for network SNAT.
-				if netInfo.Config[keyPrefix+".nat.address"] != "" {
-					key := keyPrefix + ".nat.address"
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
 
-					subnetSize := 128
-					if keyPrefix == "ipv4" {
-						subnetSize = 32
-					}
+		curPeerID, curPeer, err = tx.GetNetworkPeer(ctx, n.ID(), peerName)
+		if err != nil {
+			return err
+		}
 
-					_, ipNet, err := net.ParseCIDR(fmt.Sprintf("%s/%d", netInfo.Config[key], subnetSize))
-					if err != nil {
-						return nil, fmt.Errorf("Failed parsing %q of %q in project %q: %w", key, netInfo.Name, netProject, err)
-					}
+		peers, err = tx.GetNetworkPeers(ctx, n.ID())
 
-					externalSubnets = append(externalSubnets, externalSubnetUsage{
-						subnet:         *ipNet,
-						networkProject: netProject,
-						networkName:    netInfo.Name,
-						usageType:      subnetUsageNetworkSNAT,
-					})
-				}
+		return err
+	})
+	if err != nil {
+		return err
+	}
 
-				// Find any routes being used by the network.
-				for _, cidr := range shared.SplitNTrimSpace(netInfo.Config[keyPrefix+".routes"], ",", -1, true) {
-					_, ipNet, err := net.ParseCIDR(cidr)
-					if err != nil {
-						continue // Skip invalid/unspecified network addresses.
-					}
+	err = n.peerValidate(peerName, &req)
+	if err != nil {
+		return err
+	}
 
-					externalSubnets = append(externalSubnets, externalSubnetUsage{
-						subnet:         *ipNet,
-						networkProject: netProject,
-						networkName:    netInfo.Name,
-						usageType:      subnetUsageNetwork,
-					})
-				}
-			}
+	for _, existingPeer := range peers {
+		if existingPeer.Name != peerName && req.Config["wireguard.public_key"] != "" && req.Config["wireguard.public_key"] == existingPeer.Config["wireguard.public_key"] {
+			return api.StatusErrorf(http.StatusConflict, "A peer for that public key already exists")
 		}
 	}
 
-	return externalSubnets, nil
-}
-
-// bridgedNICExternalRoutes returns a list of external routes currently used by bridged NICs that are connected to
-// networks specified.
-func (n *bridge) bridgedNICExternalRoutes(bridgeProjectNetworks map[string][]*api.Network) ([]externalSubnetUsage, error) {
-	externalRoutes := make([]externalSubnetUsage, 0)
+	err = n.peerValidateRoutes(peerName, &req, peers)
+	if err != nil {
+		return err
+	}
 
-	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-		return tx.InstanceList(ctx, func(inst db.InstanceArgs, p api.Project) error {
-			// Get the instance's effective network project name.
-			instNetworkProject := project.NetworkProjectFromRecord(&p)
+	curPeerEtagHash, err := util.EtagHash(curPeer.Etag())
+	if err != nil {
+		return err
+	}
 
-			if instNetworkProject != api.ProjectDefaultName {
-				return nil // Managed bridge networks can only exist in default project.
-			}
+	newPeer := api.NetworkPeer{
+		Name: curPeer.Name,
+	}
 
-			devices := instancetype.ExpandInstanceDevices(inst.Devices, inst.Profiles)
+	newPeer.SetWritable(req)
 
-			// Iterate through each of the instance's devices, looking for bridged NICs that are linked to
-			// networks specified.
-			for devName, devConfig := range devices {
-				if devConfig["type"] != "nic" {
-					continue
-				}
+	newPeerEtagHash, err := util.EtagHash(newPeer.Etag())
+	if err != nil {
+		return err
+	}
 
-				// Check whether the NIC device references one of the networks supplied.
-				if !NICUsesNetwork(devConfig, bridgeProjectNetworks[instNetworkProject]...) {
-					continue
-				}
+	if curPeerEtagHash == newPeerEtagHash {
+		return nil // Nothing has changed.
+	}
 
-				// For bridged NICs that are connected to networks specified, check if they have any
-				// routes or external routes configured, and if so add them to the list to return.
-				for _, key := range []string{"ipv4.routes", "ipv6.routes", "ipv4.routes.external", "ipv6.routes.external"} {
-					for _, cidr := range shared.SplitNTrimSpace(devConfig[key], ",", -1, true) {
-						_, ipNet, _ := net.ParseCIDR(cidr)
-						if ipNet == nil {
-							// Skip if NIC device doesn't have a valid route.
-							continue
-						}
+	revert := revert.New()
+	defer revert.Fail()
 
-						externalRoutes = append(externalRoutes, externalSubnetUsage{
-							subnet:          *ipNet,
-							networkProject:  instNetworkProject,
-							networkName:     devConfig["network"],
-							instanceProject: inst.Project,
-							instanceName:    inst.Name,
-							instanceDevice:  devName,
-							usageType:       subnetUsageInstance,
-						})
-					}
-				}
-			}
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.UpdateNetworkPeer(ctx, n.ID(), curPeerID, newPeer.Writable())
+	})
+	if err != nil {
+		return err
+	}
 
-			return nil
+	revert.Add(func() {
+		_ = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpdateNetworkPeer(ctx, n.ID(), curPeerID, curPeer.Writable())
 		})
+
+		_ = n.peerApply()
 	})
+
+	err = n.peerApply()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return externalRoutes, nil
+	revert.Success()
+	return nil
 }
 
-// getExternalSubnetInUse returns information about usage of external subnets by bridge networks (and NICs
-// connected to them) on this member.
-func (n *bridge) getExternalSubnetInUse() ([]externalSubnetUsage, error) {
-	var err error
-	var projectNetworks map[string]map[int64]api.Network
-	var projectNetworksForwardsOnUplink map[string]map[int64][]string
-	var externalSubnets []externalSubnetUsage
+// PeerDelete deletes a WireGuard peer.
+func (n *bridge) PeerDelete(peerName string) error {
+	var peerID int64
+	var peer *api.NetworkPeer
 
-	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-		// Get all managed networks across all projects.
-		projectNetworks, err = tx.GetCreatedNetworks(ctx)
-		if err != nil {
-			return fmt.Errorf("Failed to load all networks: %w", err)
-		}
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
 
-		// Get all network forward listen addresses for forwards assigned to this specific cluster member.
-		projectNetworksForwardsOnUplink, err = tx.GetProjectNetworkForwardListenAddressesOnMember(ctx)
-		if err != nil {
-			return fmt.Errorf("Failed loading network forward listen addresses: %w", err)
-		}
+		peerID, peer, err = tx.GetNetworkPeer(ctx, n.ID(), peerName)
 
-		externalSubnets, err = n.common.getExternalSubnetInUse(ctx, tx, n.name, true)
-		if err != nil {
-			return fmt.Errorf("Failed getting external subnets in use: %w", err)
-		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	isUsed, err := n.peerIsUsed(peer.Name)
+	if err != nil {
+		return err
+	}
+
+	if isUsed {
+		return errors.New("Cannot delete a Peer that is in use")
+	}
+
+	err = n.state.DB.Cluster.DeleteNetworkPeer(n.ID(), peerID)
+	if err != nil {
+		return err
+	}
+
+	return n.peerApply()
+}
 
+// peerApply applies the WireGuard peers to the running network.
+func (n *bridge) peerApply() error {
+	if !n.isRunning() {
 		return nil
-	})
+	}
+
+	return n.wireguardSetup()
+}
+
+// State returns the network state, including the state of the WireGuard interface.
+func (n *bridge) State() (*api.NetworkState, error) {
+	state, err := n.common.State()
 	if err != nil {
 		return nil, err
 	}
 
-	// Get managed bridge networks.
-	bridgeProjectNetworks := n.bridgeProjectNetworks(projectNetworks)
+	if n.config["wireguard.port"] == "" {
+		return state, nil
+	}
 
-	// Get external subnets used by other managed bridge networks.
-	bridgeNetworkExternalSubnets, err := n.bridgeNetworkExternalSubnets(bridgeProjectNetworks)
+	privateKey, err := n.wireguardPrivateKey(false)
 	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return state, nil // The key is generated when the network starts.
+		}
+
 		return nil, err
 	}
 
-	// Get external routes configured on bridged NICs.
-	bridgedNICExternalRoutes, err := n.bridgedNICExternalRoutes(bridgeProjectNetworks)
+	publicKey, err := ip.WireguardPublicKey(privateKey)
 	if err != nil {
 		return nil, err
 	}
 
-	externalSubnets = append(externalSubnets, bridgeNetworkExternalSubnets...)
-	externalSubnets = append(externalSubnets, bridgedNICExternalRoutes...)
+	listenPort, err := strconv.ParseUint(n.config["wireguard.port"], 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid WireGuard port: %w", err)
+	}
 
-	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-		// Detect if there are any conflicting proxy devices on all instances with the to be created network forward
-		return tx.InstanceList(ctx, func(inst db.InstanceArgs, p api.Project) error {
-			devices := instancetype.ExpandInstanceDevices(inst.Devices, inst.Profiles)
+	state.WireGuard = &api.NetworkStateWireGuard{
+		PublicKey:  publicKey,
+		ListenPort: listenPort,
+		Peers:      map[string]api.NetworkStateWireGuardPeer{},
+	}
 
-			for devName, devConfig := range devices {
-				if devConfig["type"] != "proxy" {
-					continue
-				}
+	// The interface doesn't exist while the network is stopped.
+	wg := &ip.Wireguard{Link: ip.Link{Name: n.wireguardInterfaceName()}}
+	wgState, err := wg.State()
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return state, nil
+		}
 
-				proxyListenAddr, err := ProxyParseAddr(devConfig["listen"])
-				if err != nil {
-					return err
-				}
+		return nil, err
+	}
 
-				proxySubnet, err := ParseIPToNet(proxyListenAddr.Address)
-				if err != nil {
-					continue // If proxy listen isn't a valid IP it can't conflict.
-				}
+	var peers map[int64]*api.NetworkPeer
 
-				externalSubnets = append(externalSubnets, externalSubnetUsage{
-					usageType:       subnetUsageProxy,
-					subnet:          *proxySubnet,
-					instanceProject: inst.Project,
-					instanceName:    inst.Name,
-					instanceDevice:  devName,
-				})
-			}
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		peers, err = tx.GetNetworkPeers(ctx, n.ID())
 
-			return nil
-		})
+		return err
 	})
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("Failed loading network peers: %w", err)
 	}
 
-	// Add forward listen addresses to this list.
-	for projectName, networks := range projectNetworksForwardsOnUplink {
-		for networkID, listenAddresses := range networks {
-			for _, listenAddress := range listenAddresses {
-				// Convert listen address to subnet.
-				listenAddressNet, err := ParseIPToNet(listenAddress)
-				if err != nil {
-					return nil, fmt.Errorf("Invalid existing forward listen address %q", listenAddress)
-				}
+	state.WireGuard.Peers = n.wireguardPeersState(peers, wgState)
 
-				// Create an externalSubnetUsage for the listen address by using the network ID
-				// of the listen address to retrieve the already loaded network name from the
-				// projectNetworks map.
-				externalSubnets = append(externalSubnets, externalSubnetUsage{
-					subnet:         *listenAddressNet,
-					networkProject: projectName,
-					networkName:    projectNetworks[projectName][networkID].Name,
-					usageType:      subnetUsageNetworkForward,
-				})
-			}
+	return state, nil
+}
+
+// wireguardPeersState returns the runtime state of the WireGuard peers, keyed by peer name.
+func (n *bridge) wireguardPeersState(peers map[int64]*api.NetworkPeer, wgState *ip.WireguardState) map[string]api.NetworkStateWireGuardPeer {
+	peersState := make(map[string]api.NetworkStateWireGuardPeer, len(peers))
+
+	for _, peer := range peers {
+		peerState, found := wgState.Peers[peer.Config["wireguard.public_key"]]
+		if !found {
+			continue
+		}
+
+		peersState[peer.Name] = api.NetworkStateWireGuardPeer{
+			Endpoint:        peerState.Endpoint,
+			LatestHandshake: peerState.LatestHandshake,
+			BytesReceived:   peerState.BytesReceived,
+			BytesSent:       peerState.BytesSent,
 		}
 	}
 
-	return externalSubnets, nil
+	return peersState
+}
+
+// bootRoutesV4 returns a list of IPv4 boot routes on the network's device.
+func (n *bridge) bootRoutesV4() ([]string, error) {
+	r := &ip.Route{
+		DevName: n.name,
+		Proto:   "boot",
+		Family:  ip.FamilyV4,
+	}
+
+	routes, err := r.Show()
+	if err != nil {
+		return nil, err
+	}
+
+	return routes, nil
+}
+
+// bootRoutesV6 returns a list of IPv6 boot routes on the network's device.
+func (n *bridge) bootRoutesV6() ([]string, error) {
+	r := &ip.Route{
+		DevName: n.name,
+		Proto:   "boot",
+		Family:  ip.FamilyV6,
+	}
+
+	routes, err := r.Show()
+	if err != nil {
+		return nil, err
+	}
+
+	return routes, nil
+}
+
+// applyBootRoutesV4 applies a list of IPv4 boot routes to the network's device.
+func (n *bridge) applyBootRoutesV4(routes []string) {
+	for _, route := range routes {
+		r := &ip.Route{
+			DevName: n.name,
+			Proto:   "boot",
+			Family:  ip.FamilyV4,
+		}
+
+		err := r.Replace(strings.Fields(route))
+		if err != nil {
+			// If it fails, then we can't stop as the route has already gone, so just log and continue.
+			n.logger.Error("Failed to restore route", logger.Ctx{"err": err})
+		}
+	}
+}
+
+// applyBootRoutesV6 applies a list of IPv6 boot routes to the network's device.
+func (n *bridge) applyBootRoutesV6(routes []string) {
+	for _, route := range routes {
+		r := &ip.Route{
+			DevName: n.name,
+			Proto:   "boot",
+			Family:  ip.FamilyV6,
+		}
+
+		err := r.Replace(strings.Fields(route))
+		if err != nil {
+			// If it fails, then we can't stop as the route has already gone, so just log and continue.
+			n.logger.Error("Failed to restore route", logger.Ctx{"err": err})
+		}
+	}
+}
+
+func (n *bridge) fanAddress(underlay *net.IPNet, overlay *net.IPNet) (cidr string, dev string, ipStr string, err error) {
+	// Quick checks.
+	underlaySize, _ := underlay.Mask.Size()
+	if underlaySize != 16 && underlaySize != 24 {
+		return "", "", "", errors.New("Only /16 or /24 underlays are supported at this time")
+	}
+
+	overlaySize, _ := overlay.Mask.Size()
+	if overlaySize != 8 && overlaySize != 16 {
+		return "", "", "", errors.New("Only /8 or /16 overlays are supported at this time")
+	}
+
+	if overlaySize+(32-underlaySize)+8 > 32 {
+		return "", "", "", errors.New("Underlay or overlay networks too large to accommodate the FAN")
+	}
+
+	// Get the IP
+	ip, dev, err := n.addressForSubnet(underlay)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	ipStr = ip.String()
+
+	// Force into IPv4 format
+	ipBytes := ip.To4()
+	if ipBytes == nil {
+		return "", "", "", fmt.Errorf("Invalid IPv4: %s", ip)
+	}
+
+	// Compute the IP
+	ipBytes[0] = overlay.IP[0]
+	if overlaySize == 16 {
+		ipBytes[1] = overlay.IP[1]
+		ipBytes[2] = ipBytes[3]
+	} else if underlaySize == 24 {
+		ipBytes[1] = ipBytes[3]
+		ipBytes[2] = 0
+	} else if underlaySize == 16 {
+		ipBytes[1] = ipBytes[2]
+		ipBytes[2] = ipBytes[3]
+	}
+
+	ipBytes[3] = 1
+
+	return ipBytes.String() + "/" + strconv.Itoa(overlaySize), dev, ipStr, err
+}
+
+func (n *bridge) addressForSubnet(subnet *net.IPNet) (net.IP, string, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return net.IP{}, "", err
+	}
+
+	for _, iface := range ifaces {
+		// Skip addresses on lo interface in case VIPs are being used on that interface that are part of
+		// the underlay subnet as is unlikely to be the actual intended underlay subnet interface.
+		if iface.Name == "lo" {
+			continue
+		}
+
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+
+		for _, addr := range addrs {
+			ip, _, err := net.ParseCIDR(addr.String())
+			if err != nil {
+				continue
+			}
+
+			if subnet.Contains(ip) {
+				return ip, iface.Name, nil
+			}
+		}
+	}
+
+	return net.IP{}, "", errors.New("No address found in subnet")
+}
+
+func (n *bridge) killForkDNS() error {
+	// Check if we have a running forkdns at all
+	pidPath := shared.VarPath("networks", n.name, "forkdns.pid")
+
+	// If the pid file doesn't exist, there is no process to kill.
+	if !shared.PathExists(pidPath) {
+		return nil
+	}
+
+	p, err := subprocess.ImportProcess(pidPath)
+	if err != nil {
+		return fmt.Errorf("Could not read pid file: %s", err)
+	}
+
+	err = p.Stop()
+	if err != nil && err != subprocess.ErrNotRunning {
+		return fmt.Errorf("Unable to kill dnsmasq: %s", err)
+	}
+
+	return nil
+}
+
+// updateForkdnsServersFile takes a list of node addresses and writes them atomically to
+// the forkdns.servers file ready for forkdns to notice and re-apply its config.
+func (n *bridge) updateForkdnsServersFile(addresses []string) error {
+	// We don't want to race with ourselves here
+	forkdnsServersLock.Lock()
+	defer forkdnsServersLock.Unlock()
+
+	permName := shared.VarPath("networks", n.name, ForkdnsServersListPath+"/"+ForkdnsServersListFile)
+	tmpName := permName + ".tmp"
+
+	// Open tmp file and truncate
+	tmpFile, err := os.Create(tmpName)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = tmpFile.Close() }()
+
+	for _, address := range addresses {
+		_, err := tmpFile.WriteString(address + "\n")
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tmpFile.Close()
+	if err != nil {
+		return err
+	}
+
+	// Atomically rename finished file into permanent location so forkdns can pick it up.
+	err = os.Rename(tmpName, permName)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// hasIPv4Firewall indicates whether the network has IPv4 firewall enabled.
+func (n *bridge) hasIPv4Firewall() bool {
+	// IPv4 firewall is only enabled if there is a bridge ipv4.address or fan mode, and ipv4.firewall enabled.
+	// When using fan bridge.mode, there can be an empty ipv4.address, so we assume it is active.
+	if (n.config["bridge.mode"] == "fan" || !slices.Contains([]string{"", "none"}, n.config["ipv4.address"])) && shared.IsTrueOrEmpty(n.config["ipv4.firewall"]) {
+		return true
+	}
+
+	return false
+}
+
+// hasIPv6Firewall indicates whether the network has IPv6 firewall enabled.
+func (n *bridge) hasIPv6Firewall() bool {
+	// IPv6 firewall is only enabled if there is a bridge ipv6.address and ipv6.firewall enabled.
+	if !slices.Contains([]string{"", "none"}, n.config["ipv6.address"]) && shared.IsTrueOrEmpty(n.config["ipv6.firewall"]) {
+		return true
+	}
+
+	return false
+}
+
+// hasDHCPv4 indicates whether the network has DHCPv4 enabled.
+// An empty ipv4.dhcp setting indicates enabled by default.
+func (n *bridge) hasDHCPv4() bool {
+	return shared.IsTrueOrEmpty(n.config["ipv4.dhcp"])
+}
+
+// hasDHCPv6 indicates whether the network has DHCPv6 enabled.
+// An empty ipv6.dhcp setting indicates enabled by default.
+func (n *bridge) hasDHCPv6() bool {
+	return shared.IsTrueOrEmpty(n.config["ipv6.dhcp"])
+}
+
+// DHCPv4Subnet returns the DHCPv4 subnet (if DHCP is enabled on network).
+func (n *bridge) DHCPv4Subnet() *net.IPNet {
+	// DHCP is disabled on this network.
+	if !n.hasDHCPv4() {
+		return nil
+	}
+
+	// Fan mode. Extract DHCP subnet from fan bridge address. Only detectable once network has started.
+	// But if there is no address on the fan bridge then DHCP won't work anyway.
+	if n.config["bridge.mode"] == "fan" {
+		iface, err := net.InterfaceByName(n.name)
+		if err != nil {
+			return nil
+		}
+
+		addrs, err := iface.Addrs()
+		if err != nil {
+			return nil
+		}
+
+		for _, addr := range addrs {
+			ip, subnet, err := net.ParseCIDR(addr.String())
+			if err != nil {
+				continue
+			}
+
+			if ip != nil && ip.To4() != nil && ip.IsGlobalUnicast() {
+				return subnet // Use first IPv4 unicast address on host for DHCP subnet.
+			}
+		}
+
+		return nil // No addresses found, means DHCP must be disabled.
+	}
+
+	// Non-fan mode. Return configured bridge subnet directly.
+	_, subnet, err := net.ParseCIDR(n.config["ipv4.address"])
+	if err != nil {
+		return nil
+	}
+
+	return subnet
+}
+
+// DHCPv6Subnet returns the DHCPv6 subnet (if DHCP or SLAAC is enabled on network).
+func (n *bridge) DHCPv6Subnet() *net.IPNet {
+	// DHCP is disabled on this network.
+	if !n.hasDHCPv6() {
+		return nil
+	}
+
+	_, subnet, err := net.ParseCIDR(n.config["ipv6.address"])
+	if err != nil {
+		return nil
+	}
+
+	return subnet
+}
+
+// forwardConvertToFirewallForward converts forwards into format compatible with the firewall package.
+func (n *bridge) forwardConvertToFirewallForwards(listenAddress net.IP, defaultTargetAddress net.IP, portMaps []*forwardPortMap) []firewallDrivers.AddressForward {
+	vips := make([]firewallDrivers.AddressForward, 0, len(portMaps)+1)
+
+	if defaultTargetAddress != nil {
+		vips = append(vips, firewallDrivers.AddressForward{
+			ListenAddress: listenAddress,
+			TargetAddress: defaultTargetAddress,
+		})
+	}
+
+	for _, portMap := range portMaps {
+		vips = append(vips, firewallDrivers.AddressForward{
+			ListenAddress: listenAddress,
+			Protocol:      portMap.protocol,
+			TargetAddress: portMap.target.address,
+			ListenPorts:   portMap.listenPorts,
+			TargetPorts:   portMap.target.ports,
+		})
+	}
+
+	return vips
+}
+
+// bridgeProjectNetworks takes a map of all networks in all projects and returns a filtered map of bridge networks.
+func (n *bridge) bridgeProjectNetworks(projectNetworks map[string]map[int64]api.Network) map[string][]*api.Network {
+	bridgeProjectNetworks := make(map[string][]*api.Network)
+	for netProject, networks := range projectNetworks {
+		for _, ni := range networks {
+			network := ni // Local var creating pointer to rather than iterator.
+
+			// Skip non-bridge networks.
+			if network.Type != "bridge" {
+				continue
+			}
+
+			if bridgeProjectNetworks[netProject] == nil {
+				bridgeProjectNetworks[netProject] = []*api.Network{&network}
+			} else {
+				bridgeProjectNetworks[netProject] = append(bridgeProjectNetworks[netProject], &network)
+			}
+		}
+	}
+
+	return bridgeProjectNetworks
+}
+
+// bridgeNetworkExternalSubnets returns a list of external subnets used by bridge networks. Networks are considered
+// to be using external subnets for their ipv4.address and/or ipv6.address if they have NAT disabled, and/or if
+// they have external NAT addresses specified.
+func (n *bridge) bridgeNetworkExternalSubnets(bridgeProjectNetworks map[string][]*api.Network) ([]externalSubnetUsage, error) {
+	externalSubnets := make([]externalSubnetUsage, 0)
+	for netProject, networks := range bridgeProjectNetworks {
+		for _, netInfo := range networks {
+			for _, keyPrefix := range []string{"ipv4", "ipv6"} {
+				// If NAT is disabled, then network subnet is an external subnet.
+				if shared.IsFalseOrEmpty(netInfo.Config[keyPrefix+".nat"]) {
+					key := keyPrefix + ".address"
+
+					_, ipNet, err := net.ParseCIDR(netInfo.Config[key])
+					if err != nil {
+						continue // Skip invalid/unspecified network addresses.
+					}
+
+					externalSubnets = append(externalSubnets, externalSubnetUsage{
+						subnet:         *ipNet,
+						networkProject: netProject,
+						networkName:    netInfo.Name,
+						usageType:      subnetUsageNetwork,
+					})
+				}
+
+				// Find any external subnets used for network SNAT.
+				if netInfo.Config[keyPrefix+".nat.address"] != "" {
+					key := keyPrefix + ".nat.address"
+
+					subnetSize := 128
+					if keyPrefix == "ipv4" {
+						subnetSize = 32
+					}
+
+					_, ipNet, err := net.ParseCIDR(fmt.Sprintf("%s/%d", netInfo.Config[key], subnetSize))
+					if err != nil {
+						return nil, fmt.Errorf("Failed parsing %q of %q in project %q: %w", key, netInfo.Name, netProject, err)
+					}
+
+					externalSubnets = append(externalSubnets, externalSubnetUsage{
+						subnet:         *ipNet,
+						networkProject: netProject,
+						networkName:    netInfo.Name,
+						usageType:      subnetUsageNetworkSNAT,
+					})
+				}
+
+				// Find any routes being used by the network.
+				for _, cidr := range shared.SplitNTrimSpace(netInfo.Config[keyPrefix+".routes"], ",", -1, true) {
+					_, ipNet, err := net.ParseCIDR(cidr)
+					if err != nil {
+						continue // Skip invalid/unspecified network addresses.
+					}
+
+					externalSubnets = append(externalSubnets, externalSubnetUsage{
+						subnet:         *ipNet,
+						networkProject: netProject,
+						networkName:    netInfo.Name,
+						usageType:      subnetUsageNetwork,
+					})
+				}
+			}
+		}
+	}
+
+	return externalSubnets, nil
+}
+
+// bridgedNICExternalRoutes returns a list of external routes currently used by bridged NICs that are connected to
+// networks specified.
+func (n *bridge) bridgedNICExternalRoutes(bridgeProjectNetworks map[string][]*api.Network) ([]externalSubnetUsage, error) {
+	externalRoutes := make([]externalSubnetUsage, 0)
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.InstanceList(ctx, func(inst db.InstanceArgs, p api.Project) error {
+			// Get the instance's effective network project name.
+			instNetworkProject := project.NetworkProjectFromRecord(&p)
+
+			if instNetworkProject != api.ProjectDefaultName {
+				return nil // Managed bridge networks can only exist in default project.
+			}
+
+			devices := instancetype.ExpandInstanceDevices(inst.Devices, inst.Profiles)
+
+			// Iterate through each of the instance's devices, looking for bridged NICs that are linked to
+			// networks specified.
+			for devName, devConfig := range devices {
+				if devConfig["type"] != "nic" {
+					continue
+				}
+
+				// Check whether the NIC device references one of the networks supplied.
+				if !NICUsesNetwork(devConfig, bridgeProjectNetworks[instNetworkProject]...) {
+					continue
+				}
+
+				// For bridged NICs that are connected to networks specified, check if they have any
+				// routes or external routes configured, and if so add them to the list to return.
+				for _, key := range []string{"ipv4.routes", "ipv6.routes", "ipv4.routes.external", "ipv6.routes.external"} {
+					for _, cidr := range shared.SplitNTrimSpace(devConfig[key], ",", -1, true) {
+						_, ipNet, _ := net.ParseCIDR(cidr)
+						if ipNet == nil {
+							// Skip if NIC device doesn't have a valid route.
+							continue
+						}
+
+						externalRoutes = append(externalRoutes, externalSubnetUsage{
+							subnet:          *ipNet,
+							networkProject:  instNetworkProject,
+							networkName:     devConfig["network"],
+							instanceProject: inst.Project,
+							instanceName:    inst.Name,
+							instanceDevice:  devName,
+							usageType:       subnetUsageInstance,
+						})
+					}
+				}
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return externalRoutes, nil
+}
+
+// getExternalSubnetInUse returns information about usage of external subnets by bridge networks (and NICs
+// connected to them) on this member.
+func (n *bridge) getExternalSubnetInUse() ([]externalSubnetUsage, error) {
+	var err error
+	var projectNetworks map[string]map[int64]api.Network
+	var projectNetworksForwardsOnUplink map[string]map[int64][]string
+	var projectNetworksLoadBalancersOnUplink map[string]map[int64][]string
+	var externalSubnets []externalSubnetUsage
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Get all managed networks across all projects.
+		projectNetworks, err = tx.GetCreatedNetworks(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed to load all networks: %w", err)
+		}
+
+		// Get all network forward listen addresses for forwards assigned to this specific cluster member.
+		projectNetworksForwardsOnUplink, err = tx.GetProjectNetworkForwardListenAddressesOnMember(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed loading network forward listen addresses: %w", err)
+		}
+
+		// Get all network load balancer listen addresses for load balancers assigned to this specific cluster member.
+		projectNetworksLoadBalancersOnUplink, err = tx.GetProjectNetworkLoadBalancerListenAddressesOnMember(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed loading network load balancer listen addresses: %w", err)
+		}
+
+		externalSubnets, err = n.common.getExternalSubnetInUse(ctx, tx, n.name, true)
+		if err != nil {
+			return fmt.Errorf("Failed getting external subnets in use: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Get managed bridge networks.
+	bridgeProjectNetworks := n.bridgeProjectNetworks(projectNetworks)
+
+	// Get external subnets used by other managed bridge networks.
+	bridgeNetworkExternalSubnets, err := n.bridgeNetworkExternalSubnets(bridgeProjectNetworks)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get external routes configured on bridged NICs.
+	bridgedNICExternalRoutes, err := n.bridgedNICExternalRoutes(bridgeProjectNetworks)
+	if err != nil {
+		return nil, err
+	}
+
+	externalSubnets = append(externalSubnets, bridgeNetworkExternalSubnets...)
+	externalSubnets = append(externalSubnets, bridgedNICExternalRoutes...)
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Detect if there are any conflicting proxy devices on all instances with the to be created network forward
+		return tx.InstanceList(ctx, func(inst db.InstanceArgs, p api.Project) error {
+			devices := instancetype.ExpandInstanceDevices(inst.Devices, inst.Profiles)
+
+			for devName, devConfig := range devices {
+				if devConfig["type"] != "proxy" {
+					continue
+				}
+
+				proxyListenAddr, err := ProxyParseAddr(devConfig["listen"])
+				if err != nil {
+					return err
+				}
+
+				proxySubnet, err := ParseIPToNet(proxyListenAddr.Address)
+				if err != nil {
+					continue // If proxy listen isn't a valid IP it can't conflict.
+				}
+
+				externalSubnets = append(externalSubnets, externalSubnetUsage{
+					usageType:       subnetUsageProxy,
+					subnet:          *proxySubnet,
+					instanceProject: inst.Project,
+					instanceName:    inst.Name,
+					instanceDevice:  devName,
+				})
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Add forward listen addresses to this list.
+	for projectName, networks := range projectNetworksForwardsOnUplink {
+		for networkID, listenAddresses := range networks {
+			for _, listenAddress := range listenAddresses {
+				// Convert listen address to subnet.
+				listenAddressNet, err := ParseIPToNet(listenAddress)
+				if err != nil {
+					return nil, fmt.Errorf("Invalid existing forward listen address %q", listenAddress)
+				}
+
+				// Create an externalSubnetUsage for the listen address by using the network ID
+				// of the listen address to retrieve the already loaded network name from the
+				// projectNetworks map.
+				externalSubnets = append(externalSubnets, externalSubnetUsage{
+					subnet:         *listenAddressNet,
+					networkProject: projectName,
+					networkName:    projectNetworks[projectName][networkID].Name,
+					usageType:      subnetUsageNetworkForward,
+				})
+			}
+		}
+	}
+
+	// Add load balancer listen addresses to this list.
+	for projectName, networks := range projectNetworksLoadBalancersOnUplink {
+		for networkID, listenAddresses := range networks {
+			for _, listenAddress := range listenAddresses {
+				// Convert listen address to subnet.
+				listenAddressNet, err := ParseIPToNet(listenAddress)
+				if err != nil {
+					return nil, fmt.Errorf("Invalid existing load balancer listen address %q", listenAddress)
+				}
+
+				externalSubnets = append(externalSubnets, externalSubnetUsage{
+					subnet:         *listenAddressNet,
+					networkProject: projectName,
+					networkName:    projectNetworks[projectName][networkID].Name,
+					usageType:      subnetUsageNetworkLoadBalancer,
+				})
+			}
+		}
+	}
+
+	return externalSubnets, nil
+}
+
+// listenAddressNotInUse checks the listen address subnet doesn't fall within any existing network external subnets.
+func (n *bridge) listenAddressNotInUse(listenAddressNet *net.IPNet) (bool, error) {
+	externalSubnetsInUse, err := n.getExternalSubnetInUse()
+	if err != nil {
+		return false, err
+	}
+
+	for _, externalSubnetUser := range externalSubnetsInUse {
+		// Check if usage is from our own network.
+		if externalSubnetUser.networkProject == n.project && externalSubnetUser.networkName == n.name {
+			// Skip checking conflict with our own network's subnet or SNAT address.
+			// But do not allow other conflict with other usage types within our own network.
+			if externalSubnetUser.usageType == subnetUsageNetwork || externalSubnetUser.usageType == subnetUsageNetworkSNAT {
+				continue
+			}
+		}
+
+		if SubnetContains(&externalSubnetUser.subnet, listenAddressNet) || SubnetContains(listenAddressNet, &externalSubnetUser.subnet) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// hairpinSetup enables hairpin mode on the active NIC bridge ports when the first forward or load balancer is
+// added to the bridge.
+func (n *bridge) hairpinSetup() error {
+	if n.config["bridge.driver"] == "openvswitch" {
+		return nil
+	}
+
+	brNetfilterEnabled := false
+	for _, ipVersion := range []uint{4, 6} {
+		if BridgeNetfilterEnabled(ipVersion) == nil {
+			brNetfilterEnabled = true
+			break
+		}
+	}
+
+	// If br_netfilter is enabled and bridge has forwards or load balancers, we enable hairpin mode on each
+	// NIC's bridge port in case any of the forwards target the NIC and the instance attempts to connect to
+	// the forward's listener. Without hairpin mode on the target of the forward will not be able to
+	// connect to the listener.
+	if !brNetfilterEnabled {
+		return nil
+	}
+
+	var forwardListenAddresses map[int64]string
+	var loadBalancerListenAddresses map[int64]string
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		forwardListenAddresses, err = tx.GetNetworkForwardListenAddresses(ctx, n.ID(), true)
+		if err != nil {
+			return fmt.Errorf("Failed loading network forwards: %w", err)
+		}
+
+		loadBalancerListenAddresses, err = tx.GetNetworkLoadBalancerListenAddresses(ctx, n.ID(), true)
+		if err != nil {
+			return fmt.Errorf("Failed loading network load balancers: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// If we are not the first forward or load balancer on this bridge, hairpin mode is already enabled.
+	if len(forwardListenAddresses)+len(loadBalancerListenAddresses) > 1 {
+		return nil
+	}
+
+	filter := dbCluster.InstanceFilter{Node: &n.state.ServerName}
+
+	return n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.InstanceList(ctx, func(inst db.InstanceArgs, p api.Project) error {
+			// Get the instance's effective network project name.
+			instNetworkProject := project.NetworkProjectFromRecord(&p)
+
+			if instNetworkProject != api.ProjectDefaultName {
+				return nil // Managed bridge networks can only exist in default project.
+			}
+
+			devices := instancetype.ExpandInstanceDevices(inst.Devices.Clone(), inst.Profiles)
+
+			// Iterate through each of the instance's devices, looking for bridged NICs
+			// that are linked to this network.
+			for devName, devConfig := range devices {
+				if devConfig["type"] != "nic" {
+					continue
+				}
+
+				// Check whether the NIC device references our network..
+				if !NICUsesNetwork(devConfig, &api.Network{Name: n.Name()}) {
+					continue
+				}
+
+				hostName := inst.Config[fmt.Sprintf("volatile.%s.host_name", devName)]
+				if InterfaceExists(hostName) {
+					link := &ip.Link{Name: hostName}
+					err := link.BridgeLinkSetHairpin(true)
+					if err != nil {
+						return fmt.Errorf("Error enabling hairpin mode on bridge port %q: %w", link.Name, err)
+					}
+
+					n.logger.Debug("Enabled hairpin mode on NIC bridge port", logger.Ctx{"inst": inst.Name, "project": inst.Project, "device": devName, "dev": link.Name})
+				}
+			}
+
+			return nil
+		}, filter)
+	})
+}
+
+// forwardValidate validates the forward request.
+func (n *bridge) forwardValidate(listenAddress net.IP, forward api.NetworkForwardPut) ([]*forwardPortMap, error) {
+	err := n.checkAddressNotInOVNRange(listenAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return n.common.forwardValidate(listenAddress, forward)
+}
+
+// ForwardCreate creates a network forward.
+func (n *bridge) ForwardCreate(forward api.NetworkForwardsPost, clientType request.ClientType) (net.IP, error) {
+	memberSpecific := true // bridge supports per-member forwards.
+
+	// Convert listen address to subnet so we can check its valid and can be used.
+	listenAddressNet, err := ParseIPToNet(forward.ListenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing address forward listen address %q: %w", forward.ListenAddress, err)
+	}
+
+	if listenAddressNet.IP.IsUnspecified() {
+		return nil, api.StatusErrorf(http.StatusNotImplemented, "Automatic listen address allocation not supported for drivers of type %q", n.netType)
+	}
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Check if there is an existing forward using the same listen address.
+		_, _, err := tx.GetNetworkForward(ctx, n.ID(), memberSpecific, forward.ListenAddress)
+
+		return err
+	})
+	if err == nil {
+		return nil, api.StatusErrorf(http.StatusConflict, "A forward for that listen address already exists")
+	}
+
+	_, err = n.forwardValidate(listenAddressNet.IP, forward.NetworkForwardPut)
+	if err != nil {
+		return nil, err
+	}
+
+	isValid, err := n.listenAddressNotInUse(listenAddressNet)
+	if err != nil {
+		return nil, err
+	} else if !isValid {
+		// This error is purposefully vague so that it doesn't reveal any names of
+		// resources potentially outside of the network.
+		return nil, fmt.Errorf("Forward listen address %q overlaps with another network or NIC", listenAddressNet.String())
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	var forwardID int64
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Create forward DB record.
+		forwardID, err = tx.CreateNetworkForward(ctx, n.ID(), memberSpecific, &forward)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	revert.Add(func() {
+		_ = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.DeleteNetworkForward(ctx, n.ID(), forwardID)
+		})
+		_ = n.forwardSetupFirewall()
+		_ = n.forwardBGPSetupPrefixes()
+	})
+
+	err = n.forwardSetupFirewall()
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if hairpin mode needs to be enabled on active NIC bridge ports.
+	err = n.hairpinSetup()
+	if err != nil {
+		return nil, err
+	}
+
+	// Refresh exported BGP prefixes on local member.
+	err = n.forwardBGPSetupPrefixes()
+	if err != nil {
+		return nil, fmt.Errorf("Failed applying BGP prefixes for address forwards: %w", err)
+	}
+
+	revert.Success()
+	return listenAddressNet.IP, nil
+}
+
+// ForwardUpdate updates a network forward.
+func (n *bridge) ForwardUpdate(listenAddress string, req api.NetworkForwardPut, clientType request.ClientType) error {
+	memberSpecific := true // bridge supports per-member forwards.
+
+	var curForwardID int64
+	var curForward *api.NetworkForward
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		curForwardID, curForward, err = tx.GetNetworkForward(ctx, n.ID(), memberSpecific, listenAddress)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = n.forwardValidate(net.ParseIP(curForward.ListenAddress), req)
+	if err != nil {
+		return err
+	}
+
+	curForwardEtagHash, err := util.EtagHash(curForward.Etag())
+	if err != nil {
+		return err
+	}
+
+	newForward := api.NetworkForward{
+		ListenAddress: curForward.ListenAddress,
+		Description:   req.Description,
+		Config:        req.Config,
+		Ports:         req.Ports,
+	}
+
+	newForwardEtagHash, err := util.EtagHash(newForward.Etag())
+	if err != nil {
+		return err
+	}
+
+	if curForwardEtagHash == newForwardEtagHash {
+		return nil // Nothing has changed.
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.UpdateNetworkForward(ctx, n.ID(), curForwardID, newForward.Writable())
+	})
+	if err != nil {
+		return err
+	}
+
+	revert.Add(func() {
+		_ = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpdateNetworkForward(ctx, n.ID(), curForwardID, curForward.Writable())
+		})
+		_ = n.forwardSetupFirewall()
+		_ = n.forwardBGPSetupPrefixes()
+	})
+
+	err = n.forwardSetupFirewall()
+	if err != nil {
+		return err
+	}
+
+	revert.Success()
+	return nil
+}
+
+// ForwardDelete deletes a network forward.
+func (n *bridge) ForwardDelete(listenAddress string, clientType request.ClientType) error {
+	memberSpecific := true // bridge supports per-member forwards.
+	var forwardID int64
+	var forward *api.NetworkForward
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		forwardID, forward, err = tx.GetNetworkForward(ctx, n.ID(), memberSpecific, listenAddress)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.DeleteNetworkForward(ctx, n.ID(), forwardID)
+	})
+	if err != nil {
+		return err
+	}
+
+	revert.Add(func() {
+		newForward := api.NetworkForwardsPost{
+			NetworkForwardPut: forward.Writable(),
+			ListenAddress:     forward.ListenAddress,
+		}
+
+		_ = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			_, _ = tx.CreateNetworkForward(ctx, n.ID(), memberSpecific, &newForward)
+
+			return nil
+		})
+
+		_ = n.forwardSetupFirewall()
+		_ = n.forwardBGPSetupPrefixes()
+	})
+
+	err = n.forwardSetupFirewall()
+	if err != nil {
+		return err
+	}
+
+	// Refresh exported BGP prefixes on local member.
+	err = n.forwardBGPSetupPrefixes()
+	if err != nil {
+		return fmt.Errorf("Failed applying BGP prefixes for address forwards: %w", err)
+	}
+
+	revert.Success()
+	return nil
+}
+
+// forwardSetupFirewall applies all network address forwards defined for this network and this member.
+func (n *bridge) forwardSetupFirewall() error {
+	memberSpecific := true // Get all forwards for this cluster member.
+
+	var forwards map[int64]*api.NetworkForward
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		forwards, err = tx.GetNetworkForwards(ctx, n.ID(), memberSpecific)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading network forwards: %w", err)
+	}
+
+	var fwForwards []firewallDrivers.AddressForward
+	ipVersions := make(map[uint]struct{})
+
+	for _, forward := range forwards {
+		// Convert listen address to subnet so we can check its valid and can be used.
+		listenAddressNet, err := ParseIPToNet(forward.ListenAddress)
+		if err != nil {
+			return fmt.Errorf("Failed parsing address forward listen address %q: %w", forward.ListenAddress, err)
+		}
+
+		// Track which IP versions we are using.
+		if listenAddressNet.IP.To4() == nil {
+			ipVersions[6] = struct{}{}
+		} else {
+			ipVersions[4] = struct{}{}
+		}
+
+		portMaps, err := n.forwardValidate(listenAddressNet.IP, forward.Writable())
+		if err != nil {
+			return fmt.Errorf("Failed validating firewall address forward for listen address %q: %w", forward.ListenAddress, err)
+		}
+
+		fwForwards = append(fwForwards, n.forwardConvertToFirewallForwards(listenAddressNet.IP, net.ParseIP(forward.Config["target_address"]), portMaps)...)
+	}
+
+	if len(forwards) > 0 {
+		// Check if br_netfilter is enabled to, and warn if not.
+		brNetfilterWarning := false
+		for ipVersion := range ipVersions {
+			err = BridgeNetfilterEnabled(ipVersion)
+			if err != nil {
+				brNetfilterWarning = true
+				msg := fmt.Sprintf("IPv%d bridge netfilter not enabled. Instances using the bridge will not be able to connect to the forward listen IPs", ipVersion)
+				n.logger.Warn(msg, logger.Ctx{"err": err})
+				err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+					return tx.UpsertWarningLocalNode(ctx, n.project, entity.TypeNetwork, int(n.id), warningtype.ProxyBridgeNetfilterNotEnabled, fmt.Sprintf("%s: %v", msg, err))
+				})
+				if err != nil {
+					n.logger.Warn("Failed to create warning", logger.Ctx{"err": err})
+				}
+			}
+		}
+
+		if !brNetfilterWarning {
+			err = warnings.ResolveWarningsByLocalNodeAndProjectAndTypeAndEntity(n.state.DB.Cluster, n.project, warningtype.ProxyBridgeNetfilterNotEnabled, entity.TypeNetwork, int(n.id))
+			if err != nil {
+				n.logger.Warn("Failed to resolve warning", logger.Ctx{"err": err})
+			}
+		}
+	}
+
+	err = n.state.Firewall.NetworkApplyForwards(n.name, fwForwards)
+	if err != nil {
+		return fmt.Errorf("Failed applying firewall address forwards: %w", err)
+	}
+
+	return nil
 }
 
-// forwardValidate validates the forward request.
-func (n *bridge) forwardValidate(listenAddress net.IP, forward api.NetworkForwardPut) ([]*forwardPortMap, error) {
+// loadBalancerValidate validates the load balancer request.
+func (n *bridge) loadBalancerValidate(listenAddress net.IP, loadBalancer api.NetworkLoadBalancerPut) ([]*loadBalancerPortMap, error) {
 	err := n.checkAddressNotInOVNRange(listenAddress)
 	if err != nil {
 		return nil, err
 	}
 
-	return n.common.forwardValidate(listenAddress, forward)
+	return n.common.loadBalancerValidate(listenAddress, loadBalancer)
 }
 
-// ForwardCreate creates a network forward.
-func (n *bridge) ForwardCreate(forward api.NetworkForwardsPost, clientType request.ClientType) (net.IP, error) {
-	memberSpecific := true // bridge supports per-member forwards.
+// LoadBalancerCreate creates a network load balancer.
+func (n *bridge) LoadBalancerCreate(loadBalancer api.NetworkLoadBalancersPost, clientType request.ClientType) (net.IP, error) {
+	memberSpecific := true // bridge supports per-member load balancers.
 
 	// Convert listen address to subnet so we can check its valid and can be used.
-	listenAddressNet, err := ParseIPToNet(forward.ListenAddress)
+	listenAddressNet, err := ParseIPToNet(loadBalancer.ListenAddress)
 	if err != nil {
-		return nil, fmt.Errorf("Failed parsing address forward listen address %q: %w", forward.ListenAddress, err)
+		return nil, fmt.Errorf("Failed parsing load balancer listen address %q: %w", loadBalancer.ListenAddress, err)
 	}
 
 	if listenAddressNet.IP.IsUnspecified() {
@@ -3233,62 +4546,37 @@ func (n *bridge) ForwardCreate(forward api.NetworkForwardsPost, clientType reque
 	}
 
 	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-		// Check if there is an existing forward using the same listen address.
-		_, _, err := tx.GetNetworkForward(ctx, n.ID(), memberSpecific, forward.ListenAddress)
+		// Check if there is an existing load balancer using the same listen address.
+		_, _, err := tx.GetNetworkLoadBalancer(ctx, n.ID(), memberSpecific, loadBalancer.ListenAddress)
 
 		return err
 	})
 	if err == nil {
-		return nil, api.StatusErrorf(http.StatusConflict, "A forward for that listen address already exists")
-	}
-
-	_, err = n.forwardValidate(listenAddressNet.IP, forward.NetworkForwardPut)
-	if err != nil {
-		return nil, err
+		return nil, api.StatusErrorf(http.StatusConflict, "A load balancer for that listen address already exists")
 	}
 
-	externalSubnetsInUse, err := n.getExternalSubnetInUse()
+	_, err = n.loadBalancerValidate(listenAddressNet.IP, loadBalancer.NetworkLoadBalancerPut)
 	if err != nil {
 		return nil, err
 	}
 
-	checkAddressNotInUse := func(netip *net.IPNet) (bool, error) {
-		// Check the listen address subnet doesn't fall within any existing network external subnets.
-		for _, externalSubnetUser := range externalSubnetsInUse {
-			// Check if usage is from our own network.
-			if externalSubnetUser.networkProject == n.project && externalSubnetUser.networkName == n.name {
-				// Skip checking conflict with our own network's subnet or SNAT address.
-				// But do not allow other conflict with other usage types within our own network.
-				if externalSubnetUser.usageType == subnetUsageNetwork || externalSubnetUser.usageType == subnetUsageNetworkSNAT {
-					continue
-				}
-			}
-
-			if SubnetContains(&externalSubnetUser.subnet, netip) || SubnetContains(netip, &externalSubnetUser.subnet) {
-				return false, nil
-			}
-		}
-
-		return true, nil
-	}
-
-	isValid, err := checkAddressNotInUse(listenAddressNet)
+	isValid, err := n.listenAddressNotInUse(listenAddressNet)
 	if err != nil {
 		return nil, err
 	} else if !isValid {
 		// This error is purposefully vague so that it doesn't reveal any names of
 		// resources potentially outside of the network.
-		return nil, fmt.Errorf("Forward listen address %q overlaps with another network or NIC", listenAddressNet.String())
+		return nil, fmt.Errorf("Load balancer listen address %q overlaps with another network or NIC", listenAddressNet.String())
 	}
 
 	revert := revert.New()
 	defer revert.Fail()
 
-	var forwardID int64
+	var loadBalancerID int64
 
 	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-		// Create forward DB record.
-		forwardID, err = tx.CreateNetworkForward(ctx, n.ID(), memberSpecific, &forward)
+		// Create load balancer DB record.
+		loadBalancerID, err = tx.CreateNetworkLoadBalancer(ctx, n.ID(), memberSpecific, &loadBalancer)
 
 		return err
 	})
@@ -3298,113 +4586,44 @@ func (n *bridge) ForwardCreate(forward api.NetworkForwardsPost, clientType reque
 
 	revert.Add(func() {
 		_ = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-			return tx.DeleteNetworkForward(ctx, n.ID(), forwardID)
+			return tx.DeleteNetworkLoadBalancer(ctx, n.ID(), loadBalancerID)
 		})
-		_ = n.forwardSetupFirewall()
-		_ = n.forwardBGPSetupPrefixes()
+		_ = n.loadBalancerSetupFirewall()
+		_ = n.loadBalancerBGPSetupPrefixes()
 	})
 
-	err = n.forwardSetupFirewall()
+	err = n.loadBalancerSetupFirewall()
 	if err != nil {
 		return nil, err
 	}
 
 	// Check if hairpin mode needs to be enabled on active NIC bridge ports.
-	if n.config["bridge.driver"] != "openvswitch" {
-		brNetfilterEnabled := false
-		for _, ipVersion := range []uint{4, 6} {
-			if BridgeNetfilterEnabled(ipVersion) == nil {
-				brNetfilterEnabled = true
-				break
-			}
-		}
-
-		// If br_netfilter is enabled and bridge has forwards, we enable hairpin mode on each NIC's bridge
-		// port in case any of the forwards target the NIC and the instance attempts to connect to the
-		// forward's listener. Without hairpin mode on the target of the forward will not be able to
-		// connect to the listener.
-		if brNetfilterEnabled {
-			var listenAddresses map[int64]string
-
-			err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-				listenAddresses, err = tx.GetNetworkForwardListenAddresses(ctx, n.ID(), true)
-
-				return err
-			})
-			if err != nil {
-				return nil, fmt.Errorf("Failed loading network forwards: %w", err)
-			}
-
-			// If we are the first forward on this bridge, enable hairpin mode on active NIC ports.
-			if len(listenAddresses) <= 1 {
-				filter := dbCluster.InstanceFilter{Node: &n.state.ServerName}
-
-				err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-					return tx.InstanceList(ctx, func(inst db.InstanceArgs, p api.Project) error {
-						// Get the instance's effective network project name.
-						instNetworkProject := project.NetworkProjectFromRecord(&p)
-
-						if instNetworkProject != api.ProjectDefaultName {
-							return nil // Managed bridge networks can only exist in default project.
-						}
-
-						devices := instancetype.ExpandInstanceDevices(inst.Devices.Clone(), inst.Profiles)
-
-						// Iterate through each of the instance's devices, looking for bridged NICs
-						// that are linked to this network.
-						for devName, devConfig := range devices {
-							if devConfig["type"] != "nic" {
-								continue
-							}
-
-							// Check whether the NIC device references our network..
-							if !NICUsesNetwork(devConfig, &api.Network{Name: n.Name()}) {
-								continue
-							}
-
-							hostName := inst.Config[fmt.Sprintf("volatile.%s.host_name", devName)]
-							if InterfaceExists(hostName) {
-								link := &ip.Link{Name: hostName}
-								err = link.BridgeLinkSetHairpin(true)
-								if err != nil {
-									return fmt.Errorf("Error enabling hairpin mode on bridge port %q: %w", link.Name, err)
-								}
-
-								n.logger.Debug("Enabled hairpin mode on NIC bridge port", logger.Ctx{"inst": inst.Name, "project": inst.Project, "device": devName, "dev": link.Name})
-							}
-						}
-
-						return nil
-					}, filter)
-				})
-				if err != nil {
-					return nil, err
-				}
-			}
-		}
+	err = n.hairpinSetup()
+	if err != nil {
+		return nil, err
 	}
 
 	// Refresh exported BGP prefixes on local member.
-	err = n.forwardBGPSetupPrefixes()
+	err = n.loadBalancerBGPSetupPrefixes()
 	if err != nil {
-		return nil, fmt.Errorf("Failed applying BGP prefixes for address forwards: %w", err)
+		return nil, fmt.Errorf("Failed applying BGP prefixes for load balancers: %w", err)
 	}
 
 	revert.Success()
 	return listenAddressNet.IP, nil
 }
 
-// ForwardUpdate updates a network forward.
-func (n *bridge) ForwardUpdate(listenAddress string, req api.NetworkForwardPut, clientType request.ClientType) error {
-	memberSpecific := true // bridge supports per-member forwards.
+// LoadBalancerUpdate updates a network load balancer.
+func (n *bridge) LoadBalancerUpdate(listenAddress string, req api.NetworkLoadBalancerPut, clientType request.ClientType) error {
+	memberSpecific := true // bridge supports per-member load balancers.
 
-	var curForwardID int64
-	var curForward *api.NetworkForward
+	var curLoadBalancerID int64
+	var curLoadBalancer *api.NetworkLoadBalancer
 
 	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 		var err error
 
-		curForwardID, curForward, err = tx.GetNetworkForward(ctx, n.ID(), memberSpecific, listenAddress)
+		curLoadBalancerID, curLoadBalancer, err = tx.GetNetworkLoadBalancer(ctx, n.ID(), memberSpecific, listenAddress)
 
 		return err
 	})
@@ -3412,29 +4631,30 @@ func (n *bridge) ForwardUpdate(listenAddress string, req api.NetworkForwardPut,
 		return err
 	}
 
-	_, err = n.forwardValidate(net.ParseIP(curForward.ListenAddress), req)
+	_, err = n.loadBalancerValidate(net.ParseIP(curLoadBalancer.ListenAddress), req)
 	if err != nil {
 		return err
 	}
 
-	curForwardEtagHash, err := util.EtagHash(curForward.Etag())
+	curLoadBalancerEtagHash, err := util.EtagHash(curLoadBalancer.Etag())
 	if err != nil {
 		return err
 	}
 
-	newForward := api.NetworkForward{
-		ListenAddress: curForward.ListenAddress,
+	newLoadBalancer := api.NetworkLoadBalancer{
+		ListenAddress: curLoadBalancer.ListenAddress,
 		Description:   req.Description,
 		Config:        req.Config,
+		Backends:      req.Backends,
 		Ports:         req.Ports,
 	}
 
-	newForwardEtagHash, err := util.EtagHash(newForward.Etag())
+	newLoadBalancerEtagHash, err := util.EtagHash(newLoadBalancer.Etag())
 	if err != nil {
 		return err
 	}
 
-	if curForwardEtagHash == newForwardEtagHash {
+	if curLoadBalancerEtagHash == newLoadBalancerEtagHash {
 		return nil // Nothing has changed.
 	}
 
@@ -3442,7 +4662,7 @@ func (n *bridge) ForwardUpdate(listenAddress string, req api.NetworkForwardPut,
 	defer revert.Fail()
 
 	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-		return tx.UpdateNetworkForward(ctx, n.ID(), curForwardID, newForward.Writable())
+		return tx.UpdateNetworkLoadBalancer(ctx, n.ID(), curLoadBalancerID, newLoadBalancer.Writable())
 	})
 	if err != nil {
 		return err
@@ -3450,13 +4670,12 @@ func (n *bridge) ForwardUpdate(listenAddress string, req api.NetworkForwardPut,
 
 	revert.Add(func() {
 		_ = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-			return tx.UpdateNetworkForward(ctx, n.ID(), curForwardID, curForward.Writable())
+			return tx.UpdateNetworkLoadBalancer(ctx, n.ID(), curLoadBalancerID, curLoadBalancer.Writable())
 		})
-		_ = n.forwardSetupFirewall()
-		_ = n.forwardBGPSetupPrefixes()
+		_ = n.loadBalancerSetupFirewall()
 	})
 
-	err = n.forwardSetupFirewall()
+	err = n.loadBalancerSetupFirewall()
 	if err != nil {
 		return err
 	}
@@ -3465,16 +4684,16 @@ func (n *bridge) ForwardUpdate(listenAddress string, req api.NetworkForwardPut,
 	return nil
 }
 
-// ForwardDelete deletes a network forward.
-func (n *bridge) ForwardDelete(listenAddress string, clientType request.ClientType) error {
-	memberSpecific := true // bridge supports per-member forwards.
-	var forwardID int64
-	var forward *api.NetworkForward
+// LoadBalancerDelete deletes a network load balancer.
+func (n *bridge) LoadBalancerDelete(listenAddress string, clientType request.ClientType) error {
+	memberSpecific := true // bridge supports per-member load balancers.
+	var loadBalancerID int64
+	var loadBalancer *api.NetworkLoadBalancer
 
 	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 		var err error
 
-		forwardID, forward, err = tx.GetNetworkForward(ctx, n.ID(), memberSpecific, listenAddress)
+		loadBalancerID, loadBalancer, err = tx.GetNetworkLoadBalancer(ctx, n.ID(), memberSpecific, listenAddress)
 
 		return err
 	})
@@ -3486,119 +4705,233 @@ func (n *bridge) ForwardDelete(listenAddress string, clientType request.ClientTy
 	defer revert.Fail()
 
 	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-		return tx.DeleteNetworkForward(ctx, n.ID(), forwardID)
+		return tx.DeleteNetworkLoadBalancer(ctx, n.ID(), loadBalancerID)
 	})
 	if err != nil {
 		return err
 	}
 
 	revert.Add(func() {
-		newForward := api.NetworkForwardsPost{
-			NetworkForwardPut: forward.Writable(),
-			ListenAddress:     forward.ListenAddress,
+		newLoadBalancer := api.NetworkLoadBalancersPost{
+			NetworkLoadBalancerPut: loadBalancer.Writable(),
+			ListenAddress:          loadBalancer.ListenAddress,
 		}
 
 		_ = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-			_, _ = tx.CreateNetworkForward(ctx, n.ID(), memberSpecific, &newForward)
+			_, _ = tx.CreateNetworkLoadBalancer(ctx, n.ID(), memberSpecific, &newLoadBalancer)
 
 			return nil
 		})
 
-		_ = n.forwardSetupFirewall()
-		_ = n.forwardBGPSetupPrefixes()
+		_ = n.loadBalancerSetupFirewall()
+		_ = n.loadBalancerBGPSetupPrefixes()
 	})
 
-	err = n.forwardSetupFirewall()
+	err = n.loadBalancerSetupFirewall()
 	if err != nil {
 		return err
 	}
 
 	// Refresh exported BGP prefixes on local member.
-	err = n.forwardBGPSetupPrefixes()
+	err = n.loadBalancerBGPSetupPrefixes()
 	if err != nil {
-		return fmt.Errorf("Failed applying BGP prefixes for address forwards: %w", err)
+		return fmt.Errorf("Failed applying BGP prefixes for load balancers: %w", err)
 	}
 
 	revert.Success()
 	return nil
 }
 
-// forwardSetupFirewall applies all network address forwards defined for this network and this member.
-func (n *bridge) forwardSetupFirewall() error {
-	memberSpecific := true // Get all forwards for this cluster member.
+// loadBalancerConvertToFirewallLoadBalancers converts load balancers into format compatible with the firewall
+// package. Each listen port is converted into its own firewall load balancer.
+func (n *bridge) loadBalancerConvertToFirewallLoadBalancers(listenAddress net.IP, portMaps []*loadBalancerPortMap) []firewallDrivers.LoadBalancer {
+	var fwLoadBalancers []firewallDrivers.LoadBalancer
 
-	var forwards map[int64]*api.NetworkForward
+	for _, portMap := range portMaps {
+		for i, listenPort := range portMap.listenPorts {
+			fwLoadBalancer := firewallDrivers.LoadBalancer{
+				ListenAddress: listenAddress,
+				Protocol:      portMap.protocol,
+				ListenPort:    listenPort,
+				Targets:       make([]firewallDrivers.LoadBalancerTarget, 0, len(portMap.targets)),
+			}
+
+			for _, target := range portMap.targets {
+				targetPort := listenPort // Default to using same port as listen port for target port.
+				targetPortsLen := len(target.ports)
+
+				if targetPortsLen == 1 {
+					// If a single target port is specified, forward all listen ports to it.
+					targetPort = target.ports[0]
+				} else if targetPortsLen > 1 {
+					// If more than one target port is specified, use the target port at the same
+					// index as the listen port.
+					targetPort = target.ports[i]
+				}
+
+				fwLoadBalancer.Targets = append(fwLoadBalancer.Targets, firewallDrivers.LoadBalancerTarget{
+					Address: target.address,
+					Port:    targetPort,
+				})
+			}
+
+			fwLoadBalancers = append(fwLoadBalancers, fwLoadBalancer)
+		}
+	}
+
+	return fwLoadBalancers
+}
+
+// loadBalancerSetupFirewall applies all network load balancers defined for this network and this member.
+// Backends failing their health check are left out until they pass it again.
+func (n *bridge) loadBalancerSetupFirewall() error {
+	memberSpecific := true // Get all load balancers for this cluster member.
+
+	var loadBalancers map[int64]*api.NetworkLoadBalancer
 
 	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 		var err error
 
-		forwards, err = tx.GetNetworkForwards(ctx, n.ID(), memberSpecific)
+		loadBalancers, err = tx.GetNetworkLoadBalancers(ctx, n.ID(), memberSpecific)
 
 		return err
 	})
 	if err != nil {
-		return fmt.Errorf("Failed loading network forwards: %w", err)
+		return fmt.Errorf("Failed loading network load balancers: %w", err)
 	}
 
-	var fwForwards []firewallDrivers.AddressForward
+	s := n.state
+	networkProject := n.project
+	networkName := n.name
+
+	monitor := loadBalancerHealthMonitorGet(n.name, func() {
+		// Reload the network as its config may have changed since the monitor was started.
+		netw, err := LoadByName(s, networkProject, networkName)
+		if err != nil {
+			logger.Warn("Failed loading network to apply load balancer backend health", logger.Ctx{"project": networkProject, "network": networkName, "err": err})
+			return
+		}
+
+		b, ok := netw.(*bridge)
+		if !ok {
+			return
+		}
+
+		err = b.loadBalancerSetupFirewall()
+		if err != nil {
+			b.logger.Warn("Failed applying load balancer backend health", logger.Ctx{"err": err})
+		}
+	})
+
+	var fwLoadBalancers []firewallDrivers.LoadBalancer
+	var healthTargets []loadBalancerHealthTarget
 	ipVersions := make(map[uint]struct{})
 
-	for _, forward := range forwards {
-		// Convert listen address to subnet so we can check its valid and can be used.
-		listenAddressNet, err := ParseIPToNet(forward.ListenAddress)
+	for _, loadBalancer := range loadBalancers {
+		listenAddress := net.ParseIP(loadBalancer.ListenAddress)
+
+		portMaps, err := n.loadBalancerValidate(listenAddress, loadBalancer.Writable())
 		if err != nil {
-			return fmt.Errorf("Failed parsing address forward listen address %q: %w", forward.ListenAddress, err)
+			return fmt.Errorf("Failed validating firewall load balancer for listen address %q: %w", loadBalancer.ListenAddress, err)
 		}
 
 		// Track which IP versions we are using.
-		if listenAddressNet.IP.To4() == nil {
+		if listenAddress.To4() == nil {
 			ipVersions[6] = struct{}{}
 		} else {
 			ipVersions[4] = struct{}{}
 		}
 
-		portMaps, err := n.forwardValidate(listenAddressNet.IP, forward.Writable())
-		if err != nil {
-			return fmt.Errorf("Failed validating firewall address forward for listen address %q: %w", forward.ListenAddress, err)
+		// Backends sharing a target address have the same health check settings (checked by validation).
+		healthChecks := make(map[string]*loadBalancerHealthCheck, len(loadBalancer.Backends))
+		for _, backend := range loadBalancer.Backends {
+			targetAddress := net.ParseIP(backend.TargetAddress)
+			if targetAddress == nil {
+				continue
+			}
+
+			healthChecks[targetAddress.String()] = n.loadBalancerHealthCheckConfig(loadBalancer.Config, backend.Config)
 		}
 
-		fwForwards = append(fwForwards, n.forwardConvertToFirewallForwards(listenAddressNet.IP, net.ParseIP(forward.Config["target_address"]), portMaps)...)
-	}
+		for _, fwLoadBalancer := range n.loadBalancerConvertToFirewallLoadBalancers(listenAddress, portMaps) {
+			// Only TCP target ports can be checked, UDP ones always receive traffic.
+			if fwLoadBalancer.Protocol == "tcp" {
+				healthyTargets := make([]firewallDrivers.LoadBalancerTarget, 0, len(fwLoadBalancer.Targets))
+				for _, target := range fwLoadBalancer.Targets {
+					healthCheck := healthChecks[target.Address.String()]
+					if healthCheck == nil {
+						healthyTargets = append(healthyTargets, target)
+						continue
+					}
 
-	if len(forwards) > 0 {
-		// Check if br_netfilter is enabled to, and warn if not.
-		brNetfilterWarning := false
-		for ipVersion := range ipVersions {
-			err = BridgeNetfilterEnabled(ipVersion)
-			if err != nil {
-				brNetfilterWarning = true
-				msg := fmt.Sprintf("IPv%d bridge netfilter not enabled. Instances using the bridge will not be able to connect to the forward listen IPs", ipVersion)
-				n.logger.Warn(msg, logger.Ctx{"err": err})
-				err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-					return tx.UpsertWarningLocalNode(ctx, n.project, entity.TypeNetwork, int(n.id), warningtype.ProxyBridgeNetfilterNotEnabled, fmt.Sprintf("%s: %v", msg, err))
-				})
-				if err != nil {
-					n.logger.Warn("Failed to create warning", logger.Ctx{"err": err})
+					healthTargets = append(healthTargets, loadBalancerHealthTarget{
+						address:     target.Address,
+						port:        target.Port,
+						healthCheck: *healthCheck,
+					})
+
+					if monitor.status(target.Address, target.Port) != "offline" {
+						healthyTargets = append(healthyTargets, target)
+					}
 				}
+
+				fwLoadBalancer.Targets = healthyTargets
+			}
+
+			// Skip listen ports without any backend to send the traffic to.
+			if len(fwLoadBalancer.Targets) == 0 {
+				continue
 			}
+
+			fwLoadBalancers = append(fwLoadBalancers, fwLoadBalancer)
 		}
+	}
 
-		if !brNetfilterWarning {
-			err = warnings.ResolveWarningsByLocalNodeAndProjectAndTypeAndEntity(n.state.DB.Cluster, n.project, warningtype.ProxyBridgeNetfilterNotEnabled, entity.TypeNetwork, int(n.id))
+	// Check if br_netfilter is enabled to, and warn if not.
+	for ipVersion := range ipVersions {
+		err = BridgeNetfilterEnabled(ipVersion)
+		if err != nil {
+			msg := fmt.Sprintf("IPv%d bridge netfilter not enabled. Instances using the bridge will not be able to connect to the load balancer listen IPs", ipVersion)
+			n.logger.Warn(msg, logger.Ctx{"err": err})
+			err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+				return tx.UpsertWarningLocalNode(ctx, n.project, entity.TypeNetwork, int(n.id), warningtype.ProxyBridgeNetfilterNotEnabled, fmt.Sprintf("%s: %v", msg, err))
+			})
 			if err != nil {
-				n.logger.Warn("Failed to resolve warning", logger.Ctx{"err": err})
+				n.logger.Warn("Failed to create warning", logger.Ctx{"err": err})
 			}
 		}
 	}
 
-	err = n.state.Firewall.NetworkApplyForwards(n.name, fwForwards)
+	err = n.state.Firewall.NetworkApplyLoadBalancers(n.name, fwLoadBalancers)
 	if err != nil {
-		return fmt.Errorf("Failed applying firewall address forwards: %w", err)
+		return fmt.Errorf("Failed applying firewall load balancers: %w", err)
+	}
+
+	// Start or stop checking the health of the backends.
+	if len(healthTargets) > 0 {
+		monitor.update(healthTargets)
+	} else {
+		loadBalancerHealthMonitorStop(n.name)
 	}
 
 	return nil
 }
 
+// LoadBalancerState returns the health of the load balancer backends as checked by this member.
+func (n *bridge) LoadBalancerState(loadBalancer api.NetworkLoadBalancer) (*api.NetworkLoadBalancerState, error) {
+	loadBalancerHealthMonitorsMu.Lock()
+	monitor := loadBalancerHealthMonitors[n.name]
+	loadBalancerHealthMonitorsMu.Unlock()
+
+	return n.loadBalancerState(loadBalancer, func(protocol string, address string, port uint64) string {
+		if monitor == nil || protocol != "tcp" {
+			return ""
+		}
+
+		return monitor.status(net.ParseIP(address), port)
+	})
+}
+
 // Leases returns a list of leases for the bridged network. It will reach out to other cluster members as needed.
 // The projectName passed here refers to the initial project from the API request which may differ from the network's project.
 // If projectName is empty, get leases from all projects.
@@ -3634,6 +4967,23 @@ func (n *bridge) Leases(projectName string, clientType request.ClientType) ([]ap
 				return nil, err
 			}
 
+			// Add the DHCP reservations of the hosts that aren't instances.
+			for _, reservation := range dnsmasq.DHCPReservations(n.config) {
+				projectMacs = append(projectMacs, reservation.MAC.String())
+
+				for _, ip := range []net.IP{reservation.IPv4, reservation.IPv6} {
+					if ip != nil {
+						leases = append(leases, api.NetworkLease{
+							Hostname: reservation.Name,
+							Address:  ip.String(),
+							Hwaddr:   reservation.MAC.String(),
+							Type:     "static",
+							Project:  n.project,
+						})
+					}
+				}
+			}
+
 			// Look for networks using the current network as an uplink.
 			for projectName, networks := range projectNetworks {
 				for _, network := range networks {
@@ -3748,16 +5098,21 @@ func (n *bridge) Leases(projectName string, clientType request.ClientType) ([]ap
 				macStr = fields[4][len(fields[4])-17:]
 			}
 
-			// Look for an existing static entry.
-			found := false
-			for _, entry := range leases {
-				if entry.Hwaddr == macStr && entry.Address == fields[2] {
-					found = true
-					break
-				}
+			// Get the lease expiry (0 means the lease never expires) and the hostname sent by the client.
+			var expiresAt *time.Time
+			expiry, err := strconv.ParseInt(fields[0], 10, 64)
+			if err == nil && expiry > 0 {
+				expiryTime := time.Unix(expiry, 0).UTC()
+				expiresAt = &expiryTime
 			}
 
-			if found {
+			clientHostname := fields[3]
+			if clientHostname == "*" {
+				clientHostname = ""
+			}
+
+			// Look for an existing entry and add the lease details to it.
+			if addNetworkLeaseDetails(leases, macStr, fields[2], expiresAt, clientHostname) {
 				continue
 			}
 
@@ -3776,12 +5131,14 @@ func (n *bridge) Leases(projectName string, clientType request.ClientType) ([]ap
 
 			// Add the lease to the list.
 			leases = append(leases, api.NetworkLease{
-				Hostname: fields[3],
-				Address:  fields[2],
-				Hwaddr:   macStr,
-				Type:     "dynamic",
-				Location: n.state.ServerName,
-				Project:  instanceProjects[fields[3]],
+				Hostname:       fields[3],
+				Address:        fields[2],
+				Hwaddr:         macStr,
+				Type:           "dynamic",
+				Location:       n.state.ServerName,
+				Project:        instanceProjects[fields[3]],
+				ExpiresAt:      expiresAt,
+				ClientHostname: clientHostname,
 			})
 		}
 	}
@@ -3798,6 +5155,11 @@ func (n *bridge) Leases(projectName string, clientType request.ClientType) ([]ap
 		var wg sync.WaitGroup
 		wg.Go(func() {
 			for lease := range leasesCh {
+				// Leases of static entries are reported as dynamic leases by the other members.
+				if addNetworkLeaseDetails(leases, lease.Hwaddr, lease.Address, lease.ExpiresAt, lease.ClientHostname) {
+					continue
+				}
+
 				leases = append(leases, lease)
 			}
 		})
@@ -3830,6 +5192,129 @@ func (n *bridge) Leases(projectName string, clientType request.ClientType) ([]ap
 	return leases, nil
 }
 
+// validateDHCPReservationsNICs checks that the addresses of the DHCP reservations aren't used as static addresses by
+// the bridged NICs connected to the network with another MAC address.
+func (n *bridge) validateDHCPReservationsNICs(config map[string]string) error {
+	reservations := dnsmasq.DHCPReservations(config)
+	if len(reservations) == 0 {
+		return nil
+	}
+
+	return UsedByInstanceDevices(n.state, n.project, n.name, n.netType, func(inst db.InstanceArgs, nicName string, nicConfig map[string]string) error {
+		nicMAC, _ := net.ParseMAC(nicConfig["hwaddr"])
+		if nicMAC == nil {
+			nicMAC, _ = net.ParseMAC(inst.Config[fmt.Sprintf("volatile.%s.hwaddr", nicName)])
+		}
+
+		for _, key := range []string{"ipv4.address", "ipv6.address"} {
+			err := dnsmasq.DHCPReservationConflict(reservations, nicMAC, net.ParseIP(nicConfig[key]))
+			if err != nil {
+				return fmt.Errorf("%w and used by NIC %q of instance %q in project %q", err, nicName, inst.Name, inst.Project)
+			}
+		}
+
+		return nil
+	})
+}
+
+// validateDHCPReservations checks that the DHCP reservations have a MAC address and that their addresses are
+// unique and part of the DHCP subnets of the network.
+func (n *bridge) validateDHCPReservations(config map[string]string) error {
+	// Check that every reservation has a MAC address.
+	for k := range config {
+		name, ok := strings.CutPrefix(k, "dhcp.reservations.")
+		if !ok {
+			continue
+		}
+
+		name, _, _ = strings.Cut(name, ".")
+		if config["dhcp.reservations."+name+".hwaddr"] == "" {
+			return fmt.Errorf("DHCP reservation %q requires a MAC address", name)
+		}
+	}
+
+	reservations := dnsmasq.DHCPReservations(config)
+	if len(reservations) == 0 {
+		return nil
+	}
+
+	var subnetV4, subnetV6 *net.IPNet
+	var gatewayV4, gatewayV6 net.IP
+
+	if !slices.Contains([]string{"", "none"}, config["ipv4.address"]) && shared.IsTrueOrEmpty(config["ipv4.dhcp"]) {
+		gatewayV4, subnetV4, _ = net.ParseCIDR(config["ipv4.address"])
+	}
+
+	if !slices.Contains([]string{"", "none"}, config["ipv6.address"]) && shared.IsTrueOrEmpty(config["ipv6.dhcp"]) && shared.IsTrue(config["ipv6.dhcp.stateful"]) {
+		gatewayV6, subnetV6, _ = net.ParseCIDR(config["ipv6.address"])
+	}
+
+	usedMACs := make(map[string]string, len(reservations))
+	usedIPs := make(map[string]string, len(reservations))
+	for _, reservation := range reservations {
+		if reservation.IPv4 == nil && reservation.IPv6 == nil {
+			return fmt.Errorf("DHCP reservation %q requires an IPv4 or IPv6 address", reservation.Name)
+		}
+
+		otherName, found := usedMACs[reservation.MAC.String()]
+		if found {
+			return fmt.Errorf("DHCP reservations %q and %q use the same MAC address", otherName, reservation.Name)
+		}
+
+		usedMACs[reservation.MAC.String()] = reservation.Name
+
+		for _, address := range []struct {
+			ip      net.IP
+			subnet  *net.IPNet
+			gateway net.IP
+			key     string
+		}{
+			{ip: reservation.IPv4, subnet: subnetV4, gateway: gatewayV4, key: "ipv4.address"},
+			{ip: reservation.IPv6, subnet: subnetV6, gateway: gatewayV6, key: "ipv6.address"},
+		} {
+			if address.ip == nil {
+				continue
+			}
+
+			if address.subnet == nil {
+				return fmt.Errorf(`Cannot set "dhcp.reservations.%s.%s" when DHCP is disabled for the network`, reservation.Name, address.key)
+			}
+
+			if !address.subnet.Contains(address.ip) {
+				return fmt.Errorf("DHCP reservation %q address %q is not within the network subnet", reservation.Name, address.ip.String())
+			}
+
+			if address.ip.Equal(address.gateway) {
+				return fmt.Errorf("DHCP reservation %q address %q is the address of the network", reservation.Name, address.ip.String())
+			}
+
+			otherName, found := usedIPs[address.ip.String()]
+			if found {
+				return fmt.Errorf("DHCP reservations %q and %q use the same address %q", otherName, reservation.Name, address.ip.String())
+			}
+
+			usedIPs[address.ip.String()] = reservation.Name
+		}
+	}
+
+	return nil
+}
+
+// addNetworkLeaseDetails adds the expiry and client hostname of a DHCP lease to the existing entry with the same MAC
+// and IP address. Returns whether a matching entry was found.
+func addNetworkLeaseDetails(leases []api.NetworkLease, hwaddr string, address string, expiresAt *time.Time, clientHostname string) bool {
+	for i, entry := range leases {
+		if entry.Hwaddr == hwaddr && entry.Address == address {
+			leases[i].ExpiresAt = expiresAt
+			leases[i].ClientHostname = clientHostname
+
+			return true
+		}
+	}
+
+	return false
+}
+
 // UsesDNSMasq indicates if network's config indicates if it needs to use dnsmasq.
 func (n *bridge) UsesDNSMasq() bool {
 	return n.config["bridge.mode"] == "fan" || !slices.Contains([]string{"", "none"}, n.config["ipv4.address"]) || !slices.Contains([]string{"", "none"}, n.config["ipv6.address"])
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"maps"
+	"math"
 	"net"
 	"os"
 	"slices"
@@ -59,6 +60,16 @@ type loadBalancerPortMap struct {
 	targets     []forwardTarget
 }
 
+// loadBalancerHealthCheck represents the health check settings of a load balancer.
+type loadBalancerHealthCheck struct {
+	checkType    string
+	httpPath     string
+	interval     uint64
+	timeout      uint64
+	successCount uint64
+	failureCount uint64
+}
+
 // subnetUsageType indicates the type of use for a subnet.
 type subnetUsageType uint
 
@@ -731,19 +742,62 @@ func (n *common) bgpValidationRules(config map[string]string) (map[string]func(v
 			rules[k] = validate.IsAny
 		case "holdtime":
 			rules[k] = validate.Optional(validate.IsInRange(9, 65535))
+		case "keepalive":
+			peerName := fields[2]
+			rules[k] = validate.Optional(func(value string) error {
+				err := validate.IsInRange(1, 21845)(value)
+				if err != nil {
+					return err
+				}
+
+				// The keepalive messages must be sent more often than the hold time.
+				holdTime := uint64(bgp.DefaultHoldTime)
+				holdTimeValue := config[fmt.Sprintf("bgp.peers.%s.holdtime", peerName)]
+				if holdTimeValue != "" {
+					holdTime, _ = strconv.ParseUint(holdTimeValue, 10, 64)
+				}
+
+				keepalive, _ := strconv.ParseUint(value, 10, 64)
+				if keepalive >= holdTime {
+					return errors.New("Keepalive interval must be lower than the hold time")
+				}
+
+				return nil
+			})
+		case "graceful_restart":
+			rules[k] = validate.Optional(validate.IsBool)
+		case "graceful_restart_time":
+			rules[k] = validate.Optional(validate.IsInRange(1, 4095))
 		}
 	}
 
+	// No route is imported unless it's part of the listed prefixes.
+	if shared.IsTrue(config["bgp.import"]) && config["bgp.import.prefixes"] == "" {
+		return nil, errors.New(`"bgp.import.prefixes" must be set when "bgp.import" is enabled`)
+	}
+
 	return rules, nil
 }
 
+// bgpValidateCommunity validates a BGP standard community.
+func bgpValidateCommunity(value string) error {
+	_, err := bgp.ParseCommunity(value)
+	return err
+}
+
 // bgpSetup initializes BGP peers and prefixes.
-func (n *common) bgpSetup(oldConfig map[string]string) error {
+// The routes imported from the peers are installed on importDevice, empty if the network doesn't import routes.
+func (n *common) bgpSetup(oldConfig map[string]string, importDevice string) error {
 	err := n.bgpSetupPeers(oldConfig)
 	if err != nil {
 		return fmt.Errorf("Failed setting up BGP peers: %w", err)
 	}
 
+	err = n.bgpSetupPolicies(importDevice)
+	if err != nil {
+		return fmt.Errorf("Failed setting up BGP policies: %w", err)
+	}
+
 	err = n.bgpSetupPrefixes(oldConfig)
 	if err != nil {
 		return fmt.Errorf("Failed setting up BGP prefixes: %w", err)
@@ -755,6 +809,11 @@ func (n *common) bgpSetup(oldConfig map[string]string) error {
 		return fmt.Errorf("Failed applying BGP prefixes for address forwards: %w", err)
 	}
 
+	err = n.loadBalancerBGPSetupPrefixes()
+	if err != nil {
+		return fmt.Errorf("Failed applying BGP prefixes for load balancers: %w", err)
+	}
+
 	return nil
 }
 
@@ -778,6 +837,93 @@ func (n *common) bgpClear(config map[string]string) error {
 		return err
 	}
 
+	// Clear existing load balancer prefixes for network.
+	err = n.state.BGP.RemovePrefixByOwner(fmt.Sprintf("network_%d_load_balancer", n.id))
+	if err != nil {
+		return err
+	}
+
+	// Clear the policies.
+	err = n.state.BGP.SetExportPolicy(fmt.Sprintf("network_%d", n.id), nil)
+	if err != nil {
+		return err
+	}
+
+	n.state.BGP.SetImportPolicy(fmt.Sprintf("network_%d", n.id), nil)
+
+	return nil
+}
+
+// bgpSetupPolicies updates the BGP export policy of the prefixes advertised for the network and the import
+// policy of the routes received from its peers.
+func (n *common) bgpSetupPolicies(importDevice string) error {
+	policyName := fmt.Sprintf("network_%d", n.id)
+
+	// Setup the export policy.
+	var exportPolicy *bgp.ExportPolicy
+	if n.config["bgp.export.communities"] != "" || n.config["bgp.export.med"] != "" || n.config["bgp.export.prepend"] != "" {
+		exportPolicy = &bgp.ExportPolicy{}
+
+		for _, value := range shared.SplitNTrimSpace(n.config["bgp.export.communities"], ",", -1, true) {
+			community, err := bgp.ParseCommunity(value)
+			if err != nil {
+				return err
+			}
+
+			exportPolicy.Communities = append(exportPolicy.Communities, community)
+		}
+
+		if n.config["bgp.export.med"] != "" {
+			med, err := strconv.ParseUint(n.config["bgp.export.med"], 10, 32)
+			if err != nil {
+				return err
+			}
+
+			exportPolicy.MED = uint32(med)
+		}
+
+		if n.config["bgp.export.prepend"] != "" {
+			prepend, err := strconv.ParseUint(n.config["bgp.export.prepend"], 10, 32)
+			if err != nil {
+				return err
+			}
+
+			exportPolicy.Prepend = uint32(prepend)
+		}
+	}
+
+	err := n.state.BGP.SetExportPolicy(policyName, exportPolicy)
+	if err != nil {
+		return err
+	}
+
+	// Setup the import policy.
+	if !shared.IsTrue(n.config["bgp.import"]) {
+		n.state.BGP.SetImportPolicy(policyName, nil)
+		return nil
+	}
+
+	importPolicy := &bgp.ImportPolicy{
+		Device: importDevice,
+		Table:  n.config["bgp.import.table"],
+	}
+
+	for _, peer := range n.bgpGetPeers(n.config) {
+		fields := strings.Split(peer, ",")
+		importPolicy.Peers = append(importPolicy.Peers, net.ParseIP(fields[0]))
+	}
+
+	for _, prefix := range shared.SplitNTrimSpace(n.config["bgp.import.prefixes"], ",", -1, true) {
+		_, subnet, err := net.ParseCIDR(prefix)
+		if err != nil {
+			return err
+		}
+
+		importPolicy.Prefixes = append(importPolicy.Prefixes, *subnet)
+	}
+
+	n.state.BGP.SetImportPolicy(policyName, importPolicy)
+
 	return nil
 }
 
@@ -829,15 +975,35 @@ func (n *common) bgpSetupPeers(oldConfig map[string]string) error {
 			return err
 		}
 
-		var holdTime uint64
+		opts := bgp.PeerOptions{
+			GracefulRestart:     shared.IsTrueOrEmpty(fields[5]),
+			GracefulRestartTime: 3600,
+		}
+
 		if fields[3] != "" {
-			holdTime, err = strconv.ParseUint(fields[3], 10, 32)
+			opts.HoldTime, err = strconv.ParseUint(fields[3], 10, 32)
+			if err != nil {
+				return err
+			}
+		}
+
+		if fields[4] != "" {
+			opts.KeepaliveInterval, err = strconv.ParseUint(fields[4], 10, 32)
+			if err != nil {
+				return err
+			}
+		}
+
+		if fields[6] != "" {
+			restartTime, err := strconv.ParseUint(fields[6], 10, 32)
 			if err != nil {
 				return err
 			}
+
+			opts.GracefulRestartTime = uint32(restartTime)
 		}
 
-		err = n.state.BGP.AddPeer(net.ParseIP(fields[0]), uint32(asn), fields[2], holdTime)
+		err = n.state.BGP.AddPeer(net.ParseIP(fields[0]), uint32(asn), fields[2], opts)
 		if err != nil {
 			return err
 		}
@@ -893,7 +1059,7 @@ func (n *common) bgpSetupPrefixes(oldConfig map[string]string) error {
 					return err
 				}
 
-				err = n.state.BGP.AddPrefix(*subnet, nextHopAddr, bgpOwner)
+				err = n.state.BGP.AddPrefix(*subnet, nextHopAddr, bgpOwner, fmt.Sprintf("network_%d", n.id))
 				if err != nil {
 					return err
 				}
@@ -906,7 +1072,7 @@ func (n *common) bgpSetupPrefixes(oldConfig map[string]string) error {
 				return fmt.Errorf("Failed parsing network address %q: %w", netAddress, err)
 			}
 
-			err = n.state.BGP.AddPrefix(*subnet, nextHopAddr, bgpOwner)
+			err = n.state.BGP.AddPrefix(*subnet, nextHopAddr, bgpOwner, fmt.Sprintf("network_%d", n.id))
 			if err != nil {
 				return err
 			}
@@ -938,9 +1104,12 @@ func (n *common) bgpGetPeers(config map[string]string) []string {
 		peerASN := config[fmt.Sprintf("bgp.peers.%s.asn", peerName)]
 		peerPassword := config[fmt.Sprintf("bgp.peers.%s.password", peerName)]
 		peerHoldTime := config[fmt.Sprintf("bgp.peers.%s.holdtime", peerName)]
+		peerKeepalive := config[fmt.Sprintf("bgp.peers.%s.keepalive", peerName)]
+		peerGracefulRestart := config[fmt.Sprintf("bgp.peers.%s.graceful_restart", peerName)]
+		peerGracefulRestartTime := config[fmt.Sprintf("bgp.peers.%s.graceful_restart_time", peerName)]
 
 		if peerAddress != "" && peerASN != "" {
-			peers = append(peers, fmt.Sprintf("%s,%s,%s,%s", peerAddress, peerASN, peerPassword, peerHoldTime))
+			peers = append(peers, fmt.Sprintf("%s,%s,%s,%s,%s,%s,%s", peerAddress, peerASN, peerPassword, peerHoldTime, peerKeepalive, peerGracefulRestart, peerGracefulRestartTime))
 		}
 	}
 
@@ -1226,7 +1395,7 @@ func (n *common) forwardBGPSetupPrefixes() error {
 				return err
 			}
 
-			err = n.state.BGP.AddPrefix(*ipRouteSubnet, nextHopAddr, bgpOwner)
+			err = n.state.BGP.AddPrefix(*ipRouteSubnet, nextHopAddr, bgpOwner, fmt.Sprintf("network_%d", n.id))
 			if err != nil {
 				return err
 			}
@@ -1330,14 +1499,24 @@ func (n *common) loadBalancerValidate(listenAddress net.IP, forward api.NetworkL
 		}
 	}
 
-	// Look for any unknown config fields.
-	for k := range forward.Config {
+	rules := n.loadBalancerHealthCheckRules()
+
+	// Validate config fields.
+	for k, v := range forward.Config {
 		// User keys are not validated.
 		if config.IsUserConfig(k) {
 			continue
 		}
 
-		return nil, fmt.Errorf("Invalid option %q", k)
+		validator, found := rules[k]
+		if !found {
+			return nil, fmt.Errorf("Invalid option %q", k)
+		}
+
+		err = validator(v)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid value for option %q: %w", k, err)
+		}
 	}
 
 	// Validate port rules.
@@ -1363,11 +1542,41 @@ func (n *common) loadBalancerValidate(listenAddress net.IP, forward api.NetworkL
 			return nil, fmt.Errorf("Duplicate name %q in backend specification %d", backendSpec.Name, backendSpecID)
 		}
 
+		for k, v := range backendSpec.Config {
+			// User keys are not validated.
+			if config.IsUserConfig(k) {
+				continue
+			}
+
+			validator, found := rules[k]
+			if !found {
+				return nil, fmt.Errorf("Invalid option %q for backend %q", k, backendSpec.Name)
+			}
+
+			err = validator(v)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid value for option %q for backend %q: %w", k, backendSpec.Name, err)
+			}
+		}
+
 		targetAddress := net.ParseIP(backendSpec.TargetAddress)
 		if targetAddress == nil {
 			return nil, fmt.Errorf("Invalid target address for backend %q", backendSpec.Name)
 		}
 
+		// Target ports are checked once per address, so backends sharing an address must be checked the same way.
+		healthCheck := n.loadBalancerHealthCheckConfig(forward.Config, backendSpec.Config)
+		for _, otherSpec := range forward.Backends[:backendSpecID] {
+			if !net.ParseIP(otherSpec.TargetAddress).Equal(targetAddress) {
+				continue
+			}
+
+			otherHealthCheck := n.loadBalancerHealthCheckConfig(forward.Config, otherSpec.Config)
+			if (healthCheck == nil) != (otherHealthCheck == nil) || (healthCheck != nil && *healthCheck != *otherHealthCheck) {
+				return nil, fmt.Errorf("Backends %q and %q share their target address but have different health check settings", otherSpec.Name, backendSpec.Name)
+			}
+		}
+
 		targetIsIP4 := targetAddress.To4() != nil
 		if listenIsIP4 != targetIsIP4 {
 			return nil, fmt.Errorf("Cannot mix IP versions in listen address and backend %q target address", backendSpec.Name)
@@ -1468,6 +1677,119 @@ func (n *common) loadBalancerValidate(listenAddress net.IP, forward api.NetworkL
 	return portMaps, err
 }
 
+// loadBalancerHealthCheckRules returns the validation rules of the health check options, which can be set on the
+// load balancer and on each of its backends.
+func (n *common) loadBalancerHealthCheckRules() map[string]func(value string) error {
+	return map[string]func(value string) error{
+		// lxdmeta:generate(entities=network-load-balancer; group=load-balancer-health-check; key=healthcheck)
+		// When enabled, backends that fail the health check stop receiving traffic until they pass it again.
+		// ---
+		//  type: bool
+		//  defaultdesc: `false`
+		//  shortdesc: Whether to check the health of the backends
+		"healthcheck": validate.Optional(validate.IsBool),
+		// lxdmeta:generate(entities=network-load-balancer; group=load-balancer-health-check; key=healthcheck.type)
+		// Possible values are `tcp` (check that a connection to the target port can be established) and `http`
+		// (check that an HTTP request to the target port returns a `2xx` or `3xx` status code).
+		// The `http` type is only supported on bridge networks.
+		// ---
+		//  type: string
+		//  defaultdesc: `tcp`
+		//  shortdesc: Type of health check
+		"healthcheck.type": validate.Optional(validate.IsOneOf("tcp", "http")),
+		// lxdmeta:generate(entities=network-load-balancer; group=load-balancer-health-check; key=healthcheck.http.path)
+		// Only used with `healthcheck.type` set to `http`.
+		// ---
+		//  type: string
+		//  defaultdesc: `/`
+		//  shortdesc: Path requested by HTTP health checks
+		"healthcheck.http.path": validate.Optional(func(value string) error {
+			if !strings.HasPrefix(value, "/") {
+				return errors.New("Path must start with /")
+			}
+
+			return nil
+		}),
+		// lxdmeta:generate(entities=network-load-balancer; group=load-balancer-health-check; key=healthcheck.interval)
+		//
+		// ---
+		//  type: integer
+		//  defaultdesc: `10`
+		//  shortdesc: Seconds between two health checks of a backend
+		"healthcheck.interval": validate.Optional(validate.IsInRange(1, math.MaxInt32)),
+		// lxdmeta:generate(entities=network-load-balancer; group=load-balancer-health-check; key=healthcheck.timeout)
+		//
+		// ---
+		//  type: integer
+		//  defaultdesc: `30`
+		//  shortdesc: Seconds to wait for a backend to respond to a health check
+		"healthcheck.timeout": validate.Optional(validate.IsInRange(1, math.MaxInt32)),
+		// lxdmeta:generate(entities=network-load-balancer; group=load-balancer-health-check; key=healthcheck.success_count)
+		//
+		// ---
+		//  type: integer
+		//  defaultdesc: `3`
+		//  shortdesc: Successful health checks needed to consider a backend healthy
+		"healthcheck.success_count": validate.Optional(validate.IsInRange(1, math.MaxInt32)),
+		// lxdmeta:generate(entities=network-load-balancer; group=load-balancer-health-check; key=healthcheck.failure_count)
+		//
+		// ---
+		//  type: integer
+		//  defaultdesc: `3`
+		//  shortdesc: Failed health checks needed to consider a backend unhealthy
+		"healthcheck.failure_count": validate.Optional(validate.IsInRange(1, math.MaxInt32)),
+	}
+}
+
+// loadBalancerHealthCheckConfig returns the health check settings of a backend from the load balancer config and
+// the backend config, or nil if health checks aren't enabled for the backend.
+func (n *common) loadBalancerHealthCheckConfig(loadBalancerConfig map[string]string, backendConfig map[string]string) *loadBalancerHealthCheck {
+	config := make(map[string]string, len(loadBalancerConfig)+len(backendConfig))
+	for k, v := range loadBalancerConfig {
+		if strings.HasPrefix(k, "healthcheck") {
+			config[k] = v
+		}
+	}
+
+	for k, v := range backendConfig {
+		if strings.HasPrefix(k, "healthcheck") {
+			config[k] = v
+		}
+	}
+
+	if shared.IsFalseOrEmpty(config["healthcheck"]) {
+		return nil
+	}
+
+	value := func(key string, defaultValue uint64) uint64 {
+		v, err := strconv.ParseUint(config[key], 10, 64)
+		if err != nil {
+			return defaultValue
+		}
+
+		return v
+	}
+
+	healthCheck := &loadBalancerHealthCheck{
+		checkType:    config["healthcheck.type"],
+		httpPath:     config["healthcheck.http.path"],
+		interval:     value("healthcheck.interval", 10),
+		timeout:      value("healthcheck.timeout", 30),
+		successCount: value("healthcheck.success_count", 3),
+		failureCount: value("healthcheck.failure_count", 3),
+	}
+
+	if healthCheck.checkType == "" {
+		healthCheck.checkType = "tcp"
+	}
+
+	if healthCheck.httpPath == "" {
+		healthCheck.httpPath = "/"
+	}
+
+	return healthCheck
+}
+
 // LoadBalancerCreate returns ErrNotImplemented for drivers that do not support load balancers.
 func (n *common) LoadBalancerCreate(loadBalancer api.NetworkLoadBalancersPost, clientType request.ClientType) (net.IP, error) {
 	return nil, ErrNotImplemented
@@ -1483,6 +1805,94 @@ func (n *common) LoadBalancerDelete(listenAddress string, clientType request.Cli
 	return ErrNotImplemented
 }
 
+// LoadBalancerState returns ErrNotImplemented for drivers that do not support load balancers.
+func (n *common) LoadBalancerState(loadBalancer api.NetworkLoadBalancer) (*api.NetworkLoadBalancerState, error) {
+	return nil, ErrNotImplemented
+}
+
+// loadBalancerState returns the health of each target port of the load balancer backends. The status function
+// returns the health check status of a backend target port or an empty string if it is not known.
+func (n *common) loadBalancerState(loadBalancer api.NetworkLoadBalancer, status func(protocol string, address string, port uint64) string) (*api.NetworkLoadBalancerState, error) {
+	lbState := api.NetworkLoadBalancerState{
+		BackendHealth: make(map[string]api.NetworkLoadBalancerStateBackendHealth, len(loadBalancer.Backends)),
+	}
+
+	for _, backend := range loadBalancer.Backends {
+		lbState.BackendHealth[backend.Name] = api.NetworkLoadBalancerStateBackendHealth{
+			Address: backend.TargetAddress,
+			Ports:   []api.NetworkLoadBalancerStateBackendHealthPort{},
+		}
+	}
+
+	// Work out which target ports of each backend receive traffic from the load balancer.
+	for _, portSpec := range loadBalancer.Ports {
+		listenPorts := []int64{}
+		for _, portRange := range shared.SplitNTrimSpace(portSpec.ListenPort, ",", -1, true) {
+			portFirst, portCount, err := ParsePortRange(portRange)
+			if err != nil {
+				return nil, err
+			}
+
+			for i := range portCount {
+				listenPorts = append(listenPorts, portFirst+i)
+			}
+		}
+
+		for _, backendName := range portSpec.TargetBackend {
+			backendHealth, found := lbState.BackendHealth[backendName]
+			if !found {
+				continue
+			}
+
+			backendIdx := slices.IndexFunc(loadBalancer.Backends, func(backend api.NetworkLoadBalancerBackend) bool {
+				return backend.Name == backendName
+			})
+
+			healthCheck := n.loadBalancerHealthCheckConfig(loadBalancer.Config, loadBalancer.Backends[backendIdx].Config)
+
+			targetPorts := []int64{}
+			for _, portRange := range shared.SplitNTrimSpace(loadBalancer.Backends[backendIdx].TargetPort, ",", -1, true) {
+				portFirst, portCount, err := ParsePortRange(portRange)
+				if err != nil {
+					return nil, err
+				}
+
+				for i := range portCount {
+					targetPorts = append(targetPorts, portFirst+i)
+				}
+			}
+
+			for i, listenPort := range listenPorts {
+				targetPort := listenPort // Default to using same port as listen port for target port.
+				if len(targetPorts) == 1 {
+					targetPort = targetPorts[0]
+				} else if len(targetPorts) > 1 && i < len(targetPorts) {
+					targetPort = targetPorts[i]
+				}
+
+				portStatus := ""
+				if healthCheck != nil {
+					portStatus = status(portSpec.Protocol, backendHealth.Address, uint64(targetPort))
+				}
+
+				if portStatus == "" {
+					portStatus = "unknown"
+				}
+
+				backendHealth.Ports = append(backendHealth.Ports, api.NetworkLoadBalancerStateBackendHealthPort{
+					Protocol: portSpec.Protocol,
+					Port:     int(targetPort),
+					Status:   portStatus,
+				})
+			}
+
+			lbState.BackendHealth[backendName] = backendHealth
+		}
+	}
+
+	return &lbState, nil
+}
+
 // loadBalancerBGPSetupPrefixes exports external load balancer addresses as prefixes.
 func (n *common) loadBalancerBGPSetupPrefixes() error {
 	var listenAddresses map[int64]string
@@ -1547,7 +1957,7 @@ func (n *common) loadBalancerBGPSetupPrefixes() error {
 				return err
 			}
 
-			err = n.state.BGP.AddPrefix(*ipRouteSubnet, nextHopAddr, bgpOwner)
+			err = n.state.BGP.AddPrefix(*ipRouteSubnet, nextHopAddr, bgpOwner, fmt.Sprintf("network_%d", n.id))
 			if err != nil {
 				return err
 			}
@@ -410,6 +410,29 @@ func (n *ovn) Validate(config map[string]string) error {
 		//  type: string
 		//  shortdesc: Physical function interfaces to allocate virtual functions from for hardware acceleration
 		"acceleration.parent": validate.Optional(validate.IsListOf(validate.IsInterfaceName)),
+		// lxdmeta:generate(entities=network-ovn; group=network-conf; key=bgp.export.communities)
+		// Specify a comma-separated list of standard communities in the `ASN:VALUE` format.
+		// ---
+		//  type: string
+		//  condition: BGP server
+		//  shortdesc: Communities attached to the prefixes advertised for the network
+		"bgp.export.communities": validate.Optional(validate.IsListOf(bgpValidateCommunity)),
+		// lxdmeta:generate(entities=network-ovn; group=network-conf; key=bgp.export.med)
+		// Routers prefer the prefixes advertised with the lowest value.
+		// ---
+		//  type: integer
+		//  condition: BGP server
+		//  defaultdesc: (not advertised)
+		//  shortdesc: Multi-exit discriminator of the prefixes advertised for the network
+		"bgp.export.med": validate.Optional(validate.IsUint32),
+		// lxdmeta:generate(entities=network-ovn; group=network-conf; key=bgp.export.prepend)
+		// Routers usually prefer the prefixes advertised with the shortest AS path.
+		// ---
+		//  type: integer
+		//  condition: BGP server
+		//  defaultdesc: `0`
+		//  shortdesc: Number of times the local ASN is prepended to the AS path of the prefixes advertised for the network
+		"bgp.export.prepend": validate.Optional(validate.IsInRange(0, 32)),
 		// lxdmeta:generate(entities=network-ovn; group=network-conf; key=bridge.hwaddr)
 		//
 		// ---
@@ -3254,7 +3277,7 @@ func (n *ovn) Start() error {
 
 	// Setup BGP.
 	if !nodeEvacuated {
-		err = n.bgpSetup(nil)
+		err = n.bgpSetup(nil, "")
 		if err != nil {
 			return err
 		}
@@ -3331,7 +3354,7 @@ func (n *ovn) Restore() error {
 	}
 
 	// Setup BGP.
-	return n.bgpSetup(nil)
+	return n.bgpSetup(nil, "")
 }
 
 // instanceNICGetRoutes returns list of routes defined in nicConfig.
@@ -3658,7 +3681,7 @@ func (n *ovn) Update(newNetwork api.NetworkPut, targetNode string, clientType re
 		}
 	} else {
 		// Setup BGP.
-		err = n.bgpSetup(oldNetwork.Config)
+		err = n.bgpSetup(oldNetwork.Config, "")
 		if err != nil {
 			return err
 		}
@@ -4049,6 +4072,12 @@ func (n *ovn) InstanceDevicePortStart(opts *OVNInstanceNICSetupOpts, securityACL
 
 	revert.Add(func() { _ = client.LogicalSwitchPortDeleteDNS(n.getIntSwitchName(), dnsUUID, false) })
 
+	// Start checking the health of the NIC's addresses used as load balancer backends.
+	err = n.loadBalancerHealthCheckRefresh(client, dnsIPs)
+	if err != nil {
+		n.logger.Warn("Failed refreshing load balancer health checks", logger.Ctx{"port": instancePortName, "err": err})
+	}
+
 	// Publish NIC's IPs on uplink network if NAT is disabled and using l2proxy ingress mode on uplink.
 	if slices.Contains([]string{"l2proxy", ""}, opts.UplinkConfig["ovn.ingress_mode"]) {
 		for _, k := range []string{"ipv4.nat", "ipv6.nat"} {
@@ -5285,6 +5314,20 @@ func (n *ovn) loadBalancerValidate(listenAddress net.IP, forward api.NetworkLoad
 		return nil, err
 	}
 
+	// OVN service monitors only check that the backend port accepts connections.
+	if forward.Config["healthcheck.type"] == "http" {
+		return nil, errors.New(`Health checks of type "http" are not supported on OVN networks`)
+	}
+
+	// OVN applies the same health check settings to all the backends of a load balancer.
+	for _, backend := range forward.Backends {
+		for k := range backend.Config {
+			if strings.HasPrefix(k, "healthcheck") {
+				return nil, fmt.Errorf("Health check settings of backend %q are not supported on OVN networks, set them on the load balancer instead", backend.Name)
+			}
+		}
+	}
+
 	return n.common.loadBalancerValidate(listenAddress, forward)
 }
 
@@ -5350,6 +5393,11 @@ func (n *ovn) LoadBalancerCreate(loadBalancer api.NetworkLoadBalancersPost, clie
 			return nil, fmt.Errorf("Failed applying OVN load balancer: %w", err)
 		}
 
+		err = n.loadBalancerHealthCheckApply(client, loadBalancer.ListenAddress, loadBalancer.Config, vips)
+		if err != nil {
+			return nil, fmt.Errorf("Failed applying OVN load balancer health checks: %w", err)
+		}
+
 		// Notify all other members to refresh their BGP prefixes.
 		notifier, err := cluster.NewNotifier(n.state, n.state.Endpoints.NetworkCert(), n.state.ServerCert(), cluster.NotifyAll)
 		if err != nil {
@@ -5439,10 +5487,16 @@ func (n *ovn) LoadBalancerUpdate(listenAddress string, req api.NetworkLoadBalanc
 			if err == nil {
 				vips := n.loadBalancerFlattenVIPs(net.ParseIP(curLoadBalancer.ListenAddress), portMaps)
 				_ = client.LoadBalancerApply(n.getLoadBalancerName(curLoadBalancer.ListenAddress), []openvswitch.OVNRouter{n.getRouterName()}, []openvswitch.OVNSwitch{n.getIntSwitchName()}, vips...)
+				_ = n.loadBalancerHealthCheckApply(client, curLoadBalancer.ListenAddress, curLoadBalancer.Config, vips)
 				_ = n.forwardBGPSetupPrefixes()
 			}
 		})
 
+		err = n.loadBalancerHealthCheckApply(client, newLoadBalancer.ListenAddress, newLoadBalancer.Config, vips)
+		if err != nil {
+			return fmt.Errorf("Failed applying OVN load balancer health checks: %w", err)
+		}
+
 		err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 			return tx.UpdateNetworkLoadBalancer(ctx, n.ID(), curLoadBalancerID, newLoadBalancer.Writable())
 		})
@@ -5539,6 +5593,184 @@ func (n *ovn) LoadBalancerDelete(listenAddress string, clientType request.Client
 	return nil
 }
 
+// loadBalancerHealthCheckApply applies the health check settings of a load balancer to its OVN load balancer.
+// Backends are checked through the logical switch port currently using their target address, from the router's
+// internal address. Backends whose address isn't in use by any port yet aren't checked.
+func (n *ovn) loadBalancerHealthCheckApply(client *openvswitch.OVN, listenAddress string, config map[string]string, vips []openvswitch.OVNLoadBalancerVIP) error {
+	healthCheck := n.loadBalancerHealthCheckConfig(config, nil)
+	if healthCheck == nil {
+		return client.LoadBalancerHealthCheckApply(n.getLoadBalancerName(listenAddress), nil, vips...)
+	}
+
+	routerIntPortIPv4, _, err := n.parseRouterIntPortIPv4Net()
+	if err != nil {
+		return err
+	}
+
+	routerIntPortIPv6, _, err := n.parseRouterIntPortIPv6Net()
+	if err != nil {
+		return err
+	}
+
+	portNames, err := n.loadBalancerBackendPortNames(client)
+	if err != nil {
+		return err
+	}
+
+	ovnHealthCheck := openvswitch.OVNLoadBalancerHealthCheck{
+		Interval:     healthCheck.interval,
+		Timeout:      healthCheck.timeout,
+		SuccessCount: healthCheck.successCount,
+		FailureCount: healthCheck.failureCount,
+	}
+
+	seenAddresses := make(map[string]struct{})
+	for _, vip := range vips {
+		for _, target := range vip.Targets {
+			_, seen := seenAddresses[target.Address.String()]
+			if seen {
+				continue
+			}
+
+			seenAddresses[target.Address.String()] = struct{}{}
+
+			portName, found := portNames[target.Address.String()]
+			if !found {
+				continue
+			}
+
+			sourceAddress := routerIntPortIPv4
+			if target.Address.To4() == nil {
+				sourceAddress = routerIntPortIPv6
+			}
+
+			if sourceAddress == nil {
+				continue
+			}
+
+			ovnHealthCheck.Targets = append(ovnHealthCheck.Targets, openvswitch.OVNLoadBalancerHealthCheckTarget{
+				Address:       target.Address,
+				PortName:      portName,
+				SourceAddress: sourceAddress,
+			})
+		}
+	}
+
+	return client.LoadBalancerHealthCheckApply(n.getLoadBalancerName(listenAddress), &ovnHealthCheck, vips...)
+}
+
+// loadBalancerBackendPortNames returns the names of the logical switch ports of the network keyed by address.
+func (n *ovn) loadBalancerBackendPortNames(client *openvswitch.OVN) (map[string]openvswitch.OVNSwitchPort, error) {
+	portIPs, err := client.LogicalSwitchIPs(n.getIntSwitchName())
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting logical switch port addresses: %w", err)
+	}
+
+	portNames := make(map[string]openvswitch.OVNSwitchPort)
+	for portName, ips := range portIPs {
+		for _, ip := range ips {
+			portNames[ip.String()] = portName
+		}
+	}
+
+	return portNames, nil
+}
+
+// loadBalancerHealthCheckRefresh reapplies the health checks of the load balancers having a backend on any of the
+// specified addresses, so that the logical switch port now using the address gets checked.
+func (n *ovn) loadBalancerHealthCheckRefresh(client *openvswitch.OVN, addresses []net.IP) error {
+	var loadBalancers map[int64]*api.NetworkLoadBalancer
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		loadBalancers, err = tx.GetNetworkLoadBalancers(ctx, n.ID(), false)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading network load balancers: %w", err)
+	}
+
+	for _, loadBalancer := range loadBalancers {
+		if n.loadBalancerHealthCheckConfig(loadBalancer.Config, nil) == nil {
+			continue
+		}
+
+		usesAddress := slices.ContainsFunc(loadBalancer.Backends, func(backend api.NetworkLoadBalancerBackend) bool {
+			return slices.ContainsFunc(addresses, func(address net.IP) bool {
+				return address.Equal(net.ParseIP(backend.TargetAddress))
+			})
+		})
+
+		if !usesAddress {
+			continue
+		}
+
+		portMaps, err := n.loadBalancerValidate(net.ParseIP(loadBalancer.ListenAddress), loadBalancer.Writable())
+		if err != nil {
+			return err
+		}
+
+		vips := n.loadBalancerFlattenVIPs(net.ParseIP(loadBalancer.ListenAddress), portMaps)
+
+		err = n.loadBalancerHealthCheckApply(client, loadBalancer.ListenAddress, loadBalancer.Config, vips)
+		if err != nil {
+			return fmt.Errorf("Failed applying health checks of load balancer %q: %w", loadBalancer.ListenAddress, err)
+		}
+	}
+
+	return nil
+}
+
+// LoadBalancerState returns the health of the load balancer backends as reported by OVN.
+func (n *ovn) LoadBalancerState(loadBalancer api.NetworkLoadBalancer) (*api.NetworkLoadBalancerState, error) {
+	healthCheck := n.loadBalancerHealthCheckConfig(loadBalancer.Config, nil)
+
+	// Get the status of the checked backend ports.
+	statuses := make(map[string]string)
+	if healthCheck != nil {
+		client, err := openvswitch.NewOVN(n.state.GlobalConfig.NetworkOVNNorthboundConnection(), n.state.GlobalConfig.NetworkOVNSSL)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get OVN client: %w", err)
+		}
+
+		portNames, err := n.loadBalancerBackendPortNames(client)
+		if err != nil {
+			return nil, err
+		}
+
+		backendPortNames := make([]openvswitch.OVNSwitchPort, 0, len(loadBalancer.Backends))
+		for _, backend := range loadBalancer.Backends {
+			portName, found := portNames[backend.TargetAddress]
+			if found && !slices.Contains(backendPortNames, portName) {
+				backendPortNames = append(backendPortNames, portName)
+			}
+		}
+
+		monitors, err := client.LoadBalancerServiceMonitors(backendPortNames...)
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting OVN service monitors: %w", err)
+		}
+
+		for _, monitor := range monitors {
+			status := "unknown"
+			switch monitor.Status {
+			case "online":
+				status = "online"
+			case "offline", "error":
+				status = "offline"
+			}
+
+			statuses[monitor.Protocol+"/"+monitor.Address.String()+"/"+strconv.FormatUint(monitor.Port, 10)] = status
+		}
+	}
+
+	return n.loadBalancerState(loadBalancer, func(protocol string, address string, port uint64) string {
+		return statuses[protocol+"/"+address+"/"+strconv.FormatUint(port, 10)]
+	})
+}
+
 // Leases returns a list of leases for the OVN network. Those are directly extracted from the OVN database.
 // If projectName is empty, get leases from all projects.
 func (n *ovn) Leases(projectName string, clientType request.ClientType) ([]api.NetworkLease, error) {
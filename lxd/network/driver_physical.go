@@ -151,6 +151,34 @@ func (n *physical) Validate(config map[string]string) error {
 		//  shortdesc: DNS server IPs on physical network
 		//  scope: global
 		"dns.nameservers": validate.Optional(validate.IsListOf(validate.IsNetworkAddress)),
+		// lxdmeta:generate(entities=network-physical; group=network-conf; key=bgp.import)
+		// The routes received from the peers of the network are installed in the routing table of the host, through the interface of the network.
+		// ---
+		//  type: bool
+		//  condition: BGP server
+		//  defaultdesc: `false`
+		//  shortdesc: Whether to import the routes received from the BGP peers
+		//  scope: global
+		"bgp.import": validate.Optional(validate.IsBool),
+		// lxdmeta:generate(entities=network-physical; group=network-conf; key=bgp.import.prefixes)
+		// Specify a comma-separated list of subnets.
+		// Only the routes that are part of one of these subnets are imported, and it must be set when {config:option}`network-physical-network-conf:bgp.import` is enabled.
+		// The default route is only imported if `0.0.0.0/0` or `::/0` is listed.
+		// ---
+		//  type: string
+		//  condition: BGP server
+		//  shortdesc: Subnets the imported routes must be part of
+		//  scope: global
+		"bgp.import.prefixes": validate.Optional(validate.IsListOf(validate.IsNetwork)),
+		// lxdmeta:generate(entities=network-physical; group=network-conf; key=bgp.import.table)
+		// Specify the numeric ID of the routing table.
+		// ---
+		//  type: integer
+		//  condition: BGP server
+		//  defaultdesc: main routing table
+		//  shortdesc: Routing table the imported routes are installed in
+		//  scope: global
+		"bgp.import.table": validate.Optional(validate.IsInRange(1, 4294967295)),
 		// lxdmeta:generate(entities=network-physical; group=network-conf; key=ovn.ingress_mode)
 		// Possible values are `l2proxy` (proxy ARP/NDP) and `routed`.
 		// ---
@@ -207,6 +235,38 @@ func (n *physical) Validate(config map[string]string) error {
 	//  required: no
 	//  shortdesc: Peer session hold time
 	//  scope: global
+
+	// lxdmeta:generate(entities=network-physical; group=network-conf; key=bgp.peers.NAME.keepalive)
+	// Specify the interval in seconds between the keepalive messages sent to the peer.
+	// Lower values, along with a lower hold time, let the peer detect a failed session faster.
+	// ---
+	//  type: integer
+	//  condition: BGP server
+	//  defaultdesc: a third of the hold time
+	//  required: no
+	//  shortdesc: Peer session keepalive interval
+	//  scope: global
+
+	// lxdmeta:generate(entities=network-physical; group=network-conf; key=bgp.peers.NAME.graceful_restart)
+	// When enabled, the peer keeps the routes advertised by LXD while LXD restarts.
+	// LXD only establishes the sessions again after its networks and instances have started, so that the peer doesn't withdraw the routes of the instances that aren't advertised yet.
+	// ---
+	//  type: bool
+	//  condition: BGP server
+	//  defaultdesc: `true`
+	//  required: no
+	//  shortdesc: Whether to enable graceful restart for the peer session
+	//  scope: global
+
+	// lxdmeta:generate(entities=network-physical; group=network-conf; key=bgp.peers.NAME.graceful_restart_time)
+	// Specify the time in seconds.
+	// ---
+	//  type: integer
+	//  condition: BGP server
+	//  defaultdesc: `3600`
+	//  required: no
+	//  shortdesc: Time for which the peer keeps the routes while waiting for the session to be re-established
+	//  scope: global
 	bgpRules, err := n.bgpValidationRules(config)
 	if err != nil {
 		return err
@@ -415,7 +475,7 @@ func (n *physical) setup(oldConfig map[string]string) error {
 
 	// Setup BGP.
 	if !nodeEvacuated {
-		err = n.bgpSetup(oldConfig)
+		err = n.bgpSetup(oldConfig, GetHostDevice(n.config["parent"], n.config["vlan"]))
 		if err != nil {
 			return err
 		}
@@ -480,7 +540,7 @@ func (n *physical) Restore() error {
 	n.logger.Debug("Restore")
 
 	// Setup BGP.
-	return n.bgpSetup(nil)
+	return n.bgpSetup(nil, GetHostDevice(n.config["parent"], n.config["vlan"]))
 }
 
 // Update updates the network. Accepts notification boolean indicating if this update request is coming from a
@@ -69,6 +69,7 @@ type Network interface {
 	LoadBalancerCreate(loadBalancer api.NetworkLoadBalancersPost, clientType request.ClientType) (net.IP, error)
 	LoadBalancerUpdate(listenAddress string, newLoadBalancer api.NetworkLoadBalancerPut, clientType request.ClientType) error
 	LoadBalancerDelete(listenAddress string, clientType request.ClientType) error
+	LoadBalancerState(loadBalancer api.NetworkLoadBalancer) (*api.NetworkLoadBalancerState, error)
 
 	// Peerings.
 	PeerCreate(forward api.NetworkPeersPost) error
@@ -0,0 +1,249 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// loadBalancerHealthMonitors contains the load balancer backend health monitors keyed by network name.
+var loadBalancerHealthMonitors = make(map[string]*loadBalancerHealthMonitor)
+var loadBalancerHealthMonitorsMu sync.Mutex
+
+// loadBalancerHealthTarget represents a load balancer backend target port to check.
+type loadBalancerHealthTarget struct {
+	address     net.IP
+	port        uint64
+	healthCheck loadBalancerHealthCheck
+}
+
+// key returns the key identifying the target port in the monitor.
+func (t loadBalancerHealthTarget) key() string {
+	return net.JoinHostPort(t.address.String(), strconv.FormatUint(t.port, 10))
+}
+
+// loadBalancerHealthProbe tracks the health of a single target port.
+type loadBalancerHealthProbe struct {
+	target    loadBalancerHealthTarget
+	cancel    context.CancelFunc
+	status    string
+	successes uint64
+	failures  uint64
+}
+
+// loadBalancerHealthMonitor periodically checks the health of the load balancer backends of a network that
+// cannot rely on its networking stack to do so. The onChange function is called each time a target port goes
+// offline or comes back online.
+type loadBalancerHealthMonitor struct {
+	networkName string
+	onChange    func()
+	onChangeMu  sync.Mutex
+
+	mu     sync.Mutex
+	probes map[string]*loadBalancerHealthProbe
+}
+
+// loadBalancerHealthMonitorGet returns the health monitor of the network, creating it if needed.
+func loadBalancerHealthMonitorGet(networkName string, onChange func()) *loadBalancerHealthMonitor {
+	loadBalancerHealthMonitorsMu.Lock()
+	defer loadBalancerHealthMonitorsMu.Unlock()
+
+	monitor, found := loadBalancerHealthMonitors[networkName]
+	if !found {
+		monitor = &loadBalancerHealthMonitor{
+			networkName: networkName,
+			onChange:    onChange,
+			probes:      make(map[string]*loadBalancerHealthProbe),
+		}
+
+		loadBalancerHealthMonitors[networkName] = monitor
+	}
+
+	return monitor
+}
+
+// loadBalancerHealthMonitorStop stops the health monitor of the network if running.
+func loadBalancerHealthMonitorStop(networkName string) {
+	loadBalancerHealthMonitorsMu.Lock()
+	monitor, found := loadBalancerHealthMonitors[networkName]
+	delete(loadBalancerHealthMonitors, networkName)
+	loadBalancerHealthMonitorsMu.Unlock()
+
+	if found {
+		monitor.update(nil)
+	}
+}
+
+// update starts checking the targets not already checked and stops checking the ones no longer present.
+// When a target port is used by several load balancers, it is only checked once.
+func (m *loadBalancerHealthMonitor) update(targets []loadBalancerHealthTarget) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	wanted := make(map[string][]loadBalancerHealthTarget, len(targets))
+	for _, target := range targets {
+		wanted[target.key()] = append(wanted[target.key()], target)
+	}
+
+	// Stop the probes of removed targets or whose settings have changed.
+	for key, probe := range m.probes {
+		keep := false
+		for _, target := range wanted[key] {
+			if target.healthCheck == probe.target.healthCheck {
+				keep = true
+				break
+			}
+		}
+
+		if !keep {
+			probe.cancel()
+			delete(m.probes, key)
+		}
+	}
+
+	// Start the probes of the new targets.
+	for key, keyTargets := range wanted {
+		_, found := m.probes[key]
+		if found {
+			continue
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		probe := &loadBalancerHealthProbe{
+			target: keyTargets[0],
+			cancel: cancel,
+			status: "unknown",
+		}
+
+		m.probes[key] = probe
+		go m.run(ctx, probe)
+	}
+}
+
+// status returns the health of the target port or an empty string if it isn't checked.
+func (m *loadBalancerHealthMonitor) status(address net.IP, port uint64) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	probe, found := m.probes[loadBalancerHealthTarget{address: address, port: port}.key()]
+	if !found {
+		return ""
+	}
+
+	return probe.status
+}
+
+// run checks the target port of the probe until its context is cancelled.
+func (m *loadBalancerHealthMonitor) run(ctx context.Context, probe *loadBalancerHealthProbe) {
+	ticker := time.NewTicker(time.Duration(probe.target.healthCheck.interval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		err := loadBalancerHealthCheckTarget(ctx, probe.target)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if m.record(probe, err) {
+			m.onChangeMu.Lock()
+			m.onChange()
+			m.onChangeMu.Unlock()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// record records the result of a health check and returns whether the target port went offline or came back.
+func (m *loadBalancerHealthMonitor) record(probe *loadBalancerHealthProbe, checkErr error) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Ignore the results of stopped probes.
+	if m.probes[probe.target.key()] != probe {
+		return false
+	}
+
+	if checkErr == nil {
+		probe.successes++
+		probe.failures = 0
+	} else {
+		probe.failures++
+		probe.successes = 0
+	}
+
+	status := probe.status
+	if probe.successes >= probe.target.healthCheck.successCount {
+		status = "online"
+	} else if probe.failures >= probe.target.healthCheck.failureCount {
+		status = "offline"
+	}
+
+	if status == probe.status {
+		return false
+	}
+
+	logCtx := logger.Ctx{"network": m.networkName, "target": probe.target.key(), "status": status}
+	if checkErr != nil {
+		logCtx["err"] = checkErr
+	}
+
+	logger.Info("Load balancer backend health changed", logCtx)
+
+	// Backends with an unknown health keep receiving traffic, so only going offline or coming back matters.
+	changed := (status == "offline") != (probe.status == "offline")
+	probe.status = status
+
+	return changed
+}
+
+// loadBalancerHealthCheckTarget checks the target port, returning an error if it isn't healthy.
+func loadBalancerHealthCheckTarget(ctx context.Context, target loadBalancerHealthTarget) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(target.healthCheck.timeout)*time.Second)
+	defer cancel()
+
+	if target.healthCheck.checkType == "http" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+target.key()+target.healthCheck.httpPath, nil)
+		if err != nil {
+			return err
+		}
+
+		client := &http.Client{
+			Transport: &http.Transport{DisableKeepAlives: true},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+
+		_ = resp.Body.Close()
+
+		if resp.StatusCode >= http.StatusBadRequest {
+			return fmt.Errorf("Unexpected HTTP status code %d", resp.StatusCode)
+		}
+
+		return nil
+	}
+
+	dialer := net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", target.key())
+	if err != nil {
+		return err
+	}
+
+	return conn.Close()
+}
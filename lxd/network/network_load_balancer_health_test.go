@@ -0,0 +1,143 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_loadBalancerHealthMonitor_record(t *testing.T) {
+	target := loadBalancerHealthTarget{
+		address:     net.ParseIP("10.0.0.10"),
+		port:        80,
+		healthCheck: loadBalancerHealthCheck{successCount: 2, failureCount: 3},
+	}
+
+	probe := &loadBalancerHealthProbe{target: target}
+	m := &loadBalancerHealthMonitor{
+		networkName: "lxdbr0",
+		probes:      map[string]*loadBalancerHealthProbe{target.key(): probe},
+	}
+
+	checkErr := errors.New("Connection refused")
+
+	steps := []struct {
+		checkErr    error
+		wantStatus  string
+		wantChanged bool
+	}{
+		{checkErr: nil, wantStatus: ""},
+		{checkErr: nil, wantStatus: "online"}, // Unknown to online doesn't change the traffic.
+		{checkErr: checkErr, wantStatus: "online"},
+		{checkErr: checkErr, wantStatus: "online"},
+		{checkErr: checkErr, wantStatus: "offline", wantChanged: true},
+		{checkErr: nil, wantStatus: "offline"},
+		{checkErr: checkErr, wantStatus: "offline"}, // A failure resets the successes.
+		{checkErr: nil, wantStatus: "offline"},
+		{checkErr: nil, wantStatus: "online", wantChanged: true},
+	}
+
+	for i, step := range steps {
+		changed := m.record(probe, step.checkErr)
+		assert.Equal(t, step.wantChanged, changed, "step %d", i)
+		assert.Equal(t, step.wantStatus, m.status(target.address, target.port), "step %d", i)
+	}
+
+	// Results of stopped probes are ignored.
+	delete(m.probes, target.key())
+	assert.False(t, m.record(probe, checkErr))
+	assert.Equal(t, "online", probe.status)
+}
+
+func Test_loadBalancerHealthCheckTarget(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/healthz":
+			w.WriteHeader(http.StatusOK)
+		case "/moved":
+			http.Redirect(w, r, "/elsewhere", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	host, portStr, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+
+	port, err := strconv.ParseUint(portStr, 10, 64)
+	require.NoError(t, err)
+
+	// A port that nothing listens on.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	closedPort := uint64(listener.Addr().(*net.TCPAddr).Port)
+	_ = listener.Close()
+
+	tests := []struct {
+		name        string
+		port        uint64
+		healthCheck loadBalancerHealthCheck
+		wantErr     bool
+	}{
+		{
+			name:        "TCP",
+			port:        port,
+			healthCheck: loadBalancerHealthCheck{checkType: "tcp"},
+		},
+		{
+			name:        "TCP closed port",
+			port:        closedPort,
+			healthCheck: loadBalancerHealthCheck{checkType: "tcp"},
+			wantErr:     true,
+		},
+		{
+			name:        "HTTP",
+			port:        port,
+			healthCheck: loadBalancerHealthCheck{checkType: "http", httpPath: "/healthz"},
+		},
+		{
+			name:        "HTTP redirect",
+			port:        port,
+			healthCheck: loadBalancerHealthCheck{checkType: "http", httpPath: "/moved"},
+		},
+		{
+			name:        "HTTP error status",
+			port:        port,
+			healthCheck: loadBalancerHealthCheck{checkType: "http", httpPath: "/"},
+			wantErr:     true,
+		},
+		{
+			name:        "HTTP closed port",
+			port:        closedPort,
+			healthCheck: loadBalancerHealthCheck{checkType: "http", httpPath: "/"},
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.healthCheck.timeout = 5
+
+			err := loadBalancerHealthCheckTarget(context.Background(), loadBalancerHealthTarget{
+				address:     net.ParseIP(host),
+				port:        tt.port,
+				healthCheck: tt.healthCheck,
+			})
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package network
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	firewallDrivers "github.com/canonical/lxd/lxd/firewall/drivers"
+	"github.com/canonical/lxd/shared/api"
+)
+
+func Test_common_loadBalancerValidate(t *testing.T) {
+	n := &common{config: map[string]string{"ipv4.address": "10.0.0.1/24"}}
+
+	backends := func(backends ...api.NetworkLoadBalancerBackend) api.NetworkLoadBalancerPut {
+		return api.NetworkLoadBalancerPut{
+			Config:   map[string]string{"healthcheck": "true"},
+			Backends: backends,
+			Ports:    []api.NetworkLoadBalancerPort{{Protocol: "tcp", ListenPort: "80", TargetBackend: []string{backends[0].Name}}},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		forward api.NetworkLoadBalancerPut
+		wantErr string
+	}{
+		{
+			name: "Health check config",
+			forward: api.NetworkLoadBalancerPut{
+				Config: map[string]string{"healthcheck": "true", "healthcheck.type": "http", "healthcheck.http.path": "/healthz", "healthcheck.interval": "5"},
+			},
+		},
+		{
+			name:    "Invalid health check type",
+			forward: api.NetworkLoadBalancerPut{Config: map[string]string{"healthcheck.type": "icmp"}},
+			wantErr: `Invalid value for option "healthcheck.type"`,
+		},
+		{
+			name:    "Invalid health check interval",
+			forward: api.NetworkLoadBalancerPut{Config: map[string]string{"healthcheck.interval": "0"}},
+			wantErr: `Invalid value for option "healthcheck.interval"`,
+		},
+		{
+			name:    "Unknown config key",
+			forward: api.NetworkLoadBalancerPut{Config: map[string]string{"foo": "bar"}},
+			wantErr: `Invalid option "foo"`,
+		},
+		{
+			name: "Backend health check config",
+			forward: backends(
+				api.NetworkLoadBalancerBackend{Name: "web1", TargetAddress: "10.0.0.10", Config: map[string]string{"healthcheck.type": "http", "healthcheck.http.path": "/healthz"}},
+				api.NetworkLoadBalancerBackend{Name: "web2", TargetAddress: "10.0.0.11", Config: map[string]string{"healthcheck": "false"}},
+			),
+		},
+		{
+			name: "Invalid backend health check value",
+			forward: backends(
+				api.NetworkLoadBalancerBackend{Name: "web1", TargetAddress: "10.0.0.10", Config: map[string]string{"healthcheck.failure_count": "none"}},
+			),
+			wantErr: `Invalid value for option "healthcheck.failure_count" for backend "web1"`,
+		},
+		{
+			name: "Unknown backend config key",
+			forward: backends(
+				api.NetworkLoadBalancerBackend{Name: "web1", TargetAddress: "10.0.0.10", Config: map[string]string{"foo": "bar"}},
+			),
+			wantErr: `Invalid option "foo" for backend "web1"`,
+		},
+		{
+			name: "Same target address with the same health check",
+			forward: backends(
+				api.NetworkLoadBalancerBackend{Name: "web1", TargetAddress: "10.0.0.10", TargetPort: "80"},
+				api.NetworkLoadBalancerBackend{Name: "web2", TargetAddress: "10.0.0.10", TargetPort: "8080", Config: map[string]string{"healthcheck.interval": "10"}},
+			),
+		},
+		{
+			name: "Same target address with different health checks",
+			forward: backends(
+				api.NetworkLoadBalancerBackend{Name: "web1", TargetAddress: "10.0.0.10", TargetPort: "80"},
+				api.NetworkLoadBalancerBackend{Name: "web2", TargetAddress: "10.0.0.10", TargetPort: "8080", Config: map[string]string{"healthcheck.type": "http"}},
+			),
+			wantErr: `Backends "web1" and "web2" share their target address but have different health check settings`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := n.loadBalancerValidate(net.ParseIP("192.0.2.1"), tt.forward)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_common_loadBalancerHealthCheckConfig(t *testing.T) {
+	n := &common{}
+
+	tests := []struct {
+		name               string
+		loadBalancerConfig map[string]string
+		backendConfig      map[string]string
+		want               *loadBalancerHealthCheck
+	}{
+		{
+			name:               "Disabled",
+			loadBalancerConfig: map[string]string{"healthcheck.interval": "5"},
+		},
+		{
+			name:               "Defaults",
+			loadBalancerConfig: map[string]string{"healthcheck": "true"},
+			want:               &loadBalancerHealthCheck{checkType: "tcp", httpPath: "/", interval: 10, timeout: 30, successCount: 3, failureCount: 3},
+		},
+		{
+			name:               "Load balancer settings",
+			loadBalancerConfig: map[string]string{"healthcheck": "true", "healthcheck.type": "http", "healthcheck.http.path": "/healthz", "healthcheck.interval": "5", "healthcheck.timeout": "2", "healthcheck.success_count": "1", "healthcheck.failure_count": "2"},
+			want:               &loadBalancerHealthCheck{checkType: "http", httpPath: "/healthz", interval: 5, timeout: 2, successCount: 1, failureCount: 2},
+		},
+		{
+			name:               "Backend overrides",
+			loadBalancerConfig: map[string]string{"healthcheck": "true", "healthcheck.interval": "5", "user.foo": "bar"},
+			backendConfig:      map[string]string{"healthcheck.type": "http", "healthcheck.interval": "20"},
+			want:               &loadBalancerHealthCheck{checkType: "http", httpPath: "/", interval: 20, timeout: 30, successCount: 3, failureCount: 3},
+		},
+		{
+			name:               "Enabled by the backend",
+			loadBalancerConfig: map[string]string{},
+			backendConfig:      map[string]string{"healthcheck": "true"},
+			want:               &loadBalancerHealthCheck{checkType: "tcp", httpPath: "/", interval: 10, timeout: 30, successCount: 3, failureCount: 3},
+		},
+		{
+			name:               "Disabled by the backend",
+			loadBalancerConfig: map[string]string{"healthcheck": "true"},
+			backendConfig:      map[string]string{"healthcheck": "false"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, n.loadBalancerHealthCheckConfig(tt.loadBalancerConfig, tt.backendConfig))
+		})
+	}
+}
+
+func Test_common_loadBalancerState(t *testing.T) {
+	n := &common{}
+
+	loadBalancer := api.NetworkLoadBalancer{
+		ListenAddress: "192.0.2.1",
+		Config:        map[string]string{"healthcheck": "true"},
+		Backends: []api.NetworkLoadBalancerBackend{
+			{Name: "web1", TargetAddress: "10.0.0.10", TargetPort: "8080"},
+			{Name: "web2", TargetAddress: "10.0.0.11"},
+			{Name: "web3", TargetAddress: "10.0.0.12", Config: map[string]string{"healthcheck": "false"}},
+			{Name: "unused", TargetAddress: "10.0.0.13"},
+		},
+		Ports: []api.NetworkLoadBalancerPort{
+			{Protocol: "tcp", ListenPort: "80,81", TargetBackend: []string{"web1", "web2", "web3"}},
+			{Protocol: "udp", ListenPort: "53", TargetBackend: []string{"web2"}},
+		},
+	}
+
+	status := func(protocol string, address string, port uint64) string {
+		if protocol != "tcp" {
+			return ""
+		}
+
+		if address == "10.0.0.11" && port == 81 {
+			return "offline"
+		}
+
+		return "online"
+	}
+
+	state, err := n.loadBalancerState(loadBalancer, status)
+	assert.NoError(t, err)
+
+	want := map[string]api.NetworkLoadBalancerStateBackendHealth{
+		"web1": {
+			Address: "10.0.0.10",
+			Ports: []api.NetworkLoadBalancerStateBackendHealthPort{
+				{Protocol: "tcp", Port: 8080, Status: "online"},
+				{Protocol: "tcp", Port: 8080, Status: "online"},
+			},
+		},
+		"web2": {
+			Address: "10.0.0.11",
+			Ports: []api.NetworkLoadBalancerStateBackendHealthPort{
+				{Protocol: "tcp", Port: 80, Status: "online"},
+				{Protocol: "tcp", Port: 81, Status: "offline"},
+				{Protocol: "udp", Port: 53, Status: "unknown"},
+			},
+		},
+		"web3": {
+			Address: "10.0.0.12",
+			Ports: []api.NetworkLoadBalancerStateBackendHealthPort{
+				{Protocol: "tcp", Port: 80, Status: "unknown"},
+				{Protocol: "tcp", Port: 81, Status: "unknown"},
+			},
+		},
+		"unused": {
+			Address: "10.0.0.13",
+			Ports:   []api.NetworkLoadBalancerStateBackendHealthPort{},
+		},
+	}
+
+	assert.Equal(t, want, state.BackendHealth)
+}
+
+func Test_bridge_loadBalancerConvertToFirewallLoadBalancers(t *testing.T) {
+	n := &bridge{}
+
+	listenAddress := net.ParseIP("192.0.2.1")
+	portMaps := []*loadBalancerPortMap{
+		{
+			listenPorts: []uint64{80, 81},
+			protocol:    "tcp",
+			targets: []forwardTarget{
+				{address: net.ParseIP("10.0.0.10")},                              // Same port as the listen port.
+				{address: net.ParseIP("10.0.0.11"), ports: []uint64{8080}},       // All listen ports to one port.
+				{address: net.ParseIP("10.0.0.12"), ports: []uint64{8080, 8081}}, // Listen ports to target ports by index.
+			},
+		},
+		{
+			listenPorts: []uint64{53},
+			protocol:    "udp",
+			targets:     []forwardTarget{{address: net.ParseIP("10.0.0.13")}},
+		},
+	}
+
+	want := []firewallDrivers.LoadBalancer{
+		{
+			ListenAddress: listenAddress,
+			Protocol:      "tcp",
+			ListenPort:    80,
+			Targets: []firewallDrivers.LoadBalancerTarget{
+				{Address: net.ParseIP("10.0.0.10"), Port: 80},
+				{Address: net.ParseIP("10.0.0.11"), Port: 8080},
+				{Address: net.ParseIP("10.0.0.12"), Port: 8080},
+			},
+		},
+		{
+			ListenAddress: listenAddress,
+			Protocol:      "tcp",
+			ListenPort:    81,
+			Targets: []firewallDrivers.LoadBalancerTarget{
+				{Address: net.ParseIP("10.0.0.10"), Port: 81},
+				{Address: net.ParseIP("10.0.0.11"), Port: 8080},
+				{Address: net.ParseIP("10.0.0.12"), Port: 8081},
+			},
+		},
+		{
+			ListenAddress: listenAddress,
+			Protocol:      "udp",
+			ListenPort:    53,
+			Targets: []firewallDrivers.LoadBalancerTarget{
+				{Address: net.ParseIP("10.0.0.13"), Port: 53},
+			},
+		},
+	}
+
+	assert.Equal(t, want, n.loadBalancerConvertToFirewallLoadBalancers(listenAddress, portMaps))
+}
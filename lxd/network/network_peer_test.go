@@ -0,0 +1,201 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/canonical/lxd/lxd/ip"
+	"github.com/canonical/lxd/shared/api"
+)
+
+const (
+	testWireguardKey1 = "hOz8d/1TGeX0NjvJu/2RGyyfu7fSgzGyNyRoxxwEzk8="
+	testWireguardKey2 = "xTIBA5rboUvnH4htodjb6e697QjLERt1NAB4mZqp8Dg="
+)
+
+func Test_bridge_peerValidate(t *testing.T) {
+	n := &bridge{}
+
+	tests := []struct {
+		name     string
+		peerName string
+		config   map[string]string
+		wantErr  string
+	}{
+		{
+			name:     "WireGuard peer",
+			peerName: "site2",
+			config:   map[string]string{"wireguard.public_key": testWireguardKey1, "wireguard.endpoint": "198.51.100.10:51820", "wireguard.routes": "10.2.0.0/24,fd42:2::/64", "user.foo": "bar"},
+		},
+		{
+			name:     "Waiting for the public key and the remote end",
+			peerName: "site2",
+			config:   map[string]string{"wireguard.routes": "10.2.0.0/24"},
+		},
+		{
+			name:     "Host name endpoint",
+			peerName: "site2",
+			config:   map[string]string{"wireguard.endpoint": "site2.example.net:51820", "wireguard.routes": "10.2.0.0/24"},
+		},
+		{
+			name:     "Reserved name",
+			peerName: "internal",
+			config:   map[string]string{"wireguard.routes": "10.2.0.0/24"},
+			wantErr:  "Name cannot be one of the reserved network subjects",
+		},
+		{
+			name:     "Missing routes",
+			peerName: "site2",
+			config:   map[string]string{"wireguard.public_key": testWireguardKey1},
+			wantErr:  `Invalid value for option "wireguard.routes"`,
+		},
+		{
+			name:     "Invalid route",
+			peerName: "site2",
+			config:   map[string]string{"wireguard.routes": "10.2.0.1"},
+			wantErr:  `Invalid value for option "wireguard.routes"`,
+		},
+		{
+			name:     "Invalid public key",
+			peerName: "site2",
+			config:   map[string]string{"wireguard.public_key": "foo", "wireguard.routes": "10.2.0.0/24"},
+			wantErr:  `Invalid value for option "wireguard.public_key"`,
+		},
+		{
+			name:     "Endpoint without port",
+			peerName: "site2",
+			config:   map[string]string{"wireguard.endpoint": "198.51.100.10", "wireguard.routes": "10.2.0.0/24"},
+			wantErr:  `Invalid value for option "wireguard.endpoint"`,
+		},
+		{
+			name:     "Endpoint with invalid port",
+			peerName: "site2",
+			config:   map[string]string{"wireguard.endpoint": "198.51.100.10:0", "wireguard.routes": "10.2.0.0/24"},
+			wantErr:  `Invalid value for option "wireguard.endpoint"`,
+		},
+		{
+			name:     "Unknown config key",
+			peerName: "site2",
+			config:   map[string]string{"wireguard.routes": "10.2.0.0/24", "target_address": "198.51.100.10"},
+			wantErr:  `Invalid option "target_address"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := n.peerValidate(tt.peerName, &api.NetworkPeerPut{Config: tt.config})
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_bridge_peerValidateRoutes(t *testing.T) {
+	n := &bridge{common: common{config: map[string]string{"ipv4.address": "10.1.0.1/24", "ipv6.address": "fd42:1::1/64"}}}
+
+	peers := map[int64]*api.NetworkPeer{
+		1: {Name: "site2", Config: map[string]string{"wireguard.routes": "10.2.0.0/24,fd42:2::/64"}},
+		2: {Name: "site3", Config: map[string]string{"wireguard.routes": "10.3.0.0/24"}},
+	}
+
+	tests := []struct {
+		name     string
+		peerName string
+		routes   string
+		wantErr  string
+	}{
+		{
+			name:     "Distinct routes",
+			peerName: "site4",
+			routes:   "10.4.0.0/24,fd42:4::/64",
+		},
+		{
+			name:     "Updating the routes of a peer",
+			peerName: "site2",
+			routes:   "10.2.0.0/23",
+		},
+		{
+			name:     "Same route as another peer",
+			peerName: "site4",
+			routes:   "10.3.0.0/24",
+			wantErr:  `Route "10.3.0.0/24" overlaps with route "10.3.0.0/24" of peer "site3"`,
+		},
+		{
+			name:     "Route containing the network subnet",
+			peerName: "site4",
+			routes:   "fd42::/16",
+			wantErr:  `Route "fd42::/16" overlaps with the network subnet "fd42:1::/64"`,
+		},
+		{
+			name:     "Route within the route of another peer",
+			peerName: "site4",
+			routes:   "10.2.0.128/25",
+			wantErr:  `Route "10.2.0.128/25" overlaps with route "10.2.0.0/24" of peer "site2"`,
+		},
+		{
+			name:     "Route within the network subnet",
+			peerName: "site4",
+			routes:   "10.1.0.0/25",
+			wantErr:  `Route "10.1.0.0/25" overlaps with the network subnet "10.1.0.0/24"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := n.peerValidateRoutes(tt.peerName, &api.NetworkPeerPut{Config: map[string]string{"wireguard.routes": tt.routes}}, peers)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_bridge_wireguardPeerConfigs(t *testing.T) {
+	n := &bridge{}
+
+	peers := map[int64]*api.NetworkPeer{
+		1: {Name: "site3", Config: map[string]string{"wireguard.public_key": testWireguardKey2, "wireguard.routes": "10.3.0.0/24"}},
+		2: {Name: "site2", Config: map[string]string{"wireguard.public_key": testWireguardKey1, "wireguard.endpoint": "198.51.100.10:51820", "wireguard.routes": "10.2.0.0/24, fd42:2::/64"}},
+		3: {Name: "site4", Config: map[string]string{"wireguard.routes": "10.4.0.0/24"}},
+	}
+
+	want := []ip.WireguardPeer{
+		{PublicKey: testWireguardKey1, Endpoint: "198.51.100.10:51820", AllowedIPs: []string{"10.2.0.0/24", "fd42:2::/64"}},
+		{PublicKey: testWireguardKey2, AllowedIPs: []string{"10.3.0.0/24"}},
+	}
+
+	assert.Equal(t, want, n.wireguardPeerConfigs(peers))
+}
+
+func Test_bridge_wireguardPeersState(t *testing.T) {
+	n := &bridge{}
+
+	handshake := time.Date(2026, time.January, 2, 3, 4, 5, 0, time.UTC)
+
+	peers := map[int64]*api.NetworkPeer{
+		1: {Name: "site2", Config: map[string]string{"wireguard.public_key": testWireguardKey1}},
+		2: {Name: "site3", Config: map[string]string{"wireguard.public_key": testWireguardKey2}},
+		3: {Name: "site4", Config: map[string]string{}},
+	}
+
+	wgState := &ip.WireguardState{
+		Peers: map[string]ip.WireguardPeerState{
+			testWireguardKey1: {Endpoint: "198.51.100.10:51820", LatestHandshake: handshake, BytesReceived: 10, BytesSent: 20},
+			testWireguardKey2: {},
+		},
+	}
+
+	want := map[string]api.NetworkStateWireGuardPeer{
+		"site2": {Endpoint: "198.51.100.10:51820", LatestHandshake: handshake, BytesReceived: 10, BytesSent: 20},
+		"site3": {},
+	}
+
+	assert.Equal(t, want, n.wireguardPeersState(peers, wgState))
+}
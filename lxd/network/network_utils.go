@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	cryptorand "crypto/rand"
+	"encoding/base64"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -56,6 +57,44 @@ func networkValidPort(value string) error {
 	return nil
 }
 
+// validateWireguardKey validates a base64 encoded WireGuard key.
+func validateWireguardKey(value string) error {
+	key, err := base64.StdEncoding.DecodeString(value)
+	if err != nil || len(key) != 32 {
+		return fmt.Errorf("Invalid WireGuard key: %s", value)
+	}
+
+	return nil
+}
+
+// validateDHCPBootFilename validates a DHCP boot file name.
+func validateDHCPBootFilename(value string) error {
+	if strings.ContainsAny(value, ",\n\r") {
+		return fmt.Errorf("Invalid boot file name %q", value)
+	}
+
+	return nil
+}
+
+// validateWireguardEndpoint validates a WireGuard endpoint in the <address>:<port> format.
+func validateWireguardEndpoint(value string) error {
+	host, port, err := net.SplitHostPort(value)
+	if err != nil {
+		return fmt.Errorf("Invalid WireGuard endpoint %q: %w", value, err)
+	}
+
+	if host == "" {
+		return fmt.Errorf("Invalid WireGuard endpoint %q: Address is required", value)
+	}
+
+	portNum, err := strconv.ParseUint(port, 10, 16)
+	if err != nil || portNum == 0 {
+		return fmt.Errorf("Invalid WireGuard endpoint %q: Invalid port %q", value, port)
+	}
+
+	return nil
+}
+
 // RandomDevName returns a random device name with prefix.
 // If the random string combined with the prefix exceeds 13 characters then empty string is returned.
 // This is to ensure we support buggy dhclient applications: https://bugs.debian.org/cgi-bin/bugreport.cgi?bug=858580
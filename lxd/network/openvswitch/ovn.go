@@ -132,7 +132,7 @@ type OVNACLRule struct {
 	Match     string // Match criteria. See OVN Southbound database's Logical_Flow table match column usage.
 	Priority  int    // Priority (between 0 and 32767, inclusive). Higher values take precedence.
 	Log       bool   // Whether or not to log matched packets.
-	LogName   string // Log label name (requires Log be true).
+	LogName   string // Rule name, used as log label name if Log is true.
 }
 
 // OVNLoadBalancerTarget represents an OVN load balancer Virtual IP target.
@@ -149,6 +149,31 @@ type OVNLoadBalancerVIP struct {
 	Targets       []OVNLoadBalancerTarget
 }
 
+// OVNLoadBalancerHealthCheck represents the health check settings of an OVN load balancer.
+type OVNLoadBalancerHealthCheck struct {
+	Interval     uint64 // Seconds between checks.
+	Timeout      uint64 // Seconds to wait for a response.
+	SuccessCount uint64 // Successful checks needed to consider a backend online.
+	FailureCount uint64 // Failed checks needed to consider a backend offline.
+	Targets      []OVNLoadBalancerHealthCheckTarget
+}
+
+// OVNLoadBalancerHealthCheckTarget represents the logical switch port of a load balancer backend address and the
+// source address used to check it.
+type OVNLoadBalancerHealthCheckTarget struct {
+	Address       net.IP
+	PortName      OVNSwitchPort
+	SourceAddress net.IP
+}
+
+// OVNServiceMonitor represents the health of a load balancer backend as reported by OVN.
+type OVNServiceMonitor struct {
+	Address  net.IP
+	Port     uint64
+	Protocol string // Either "tcp" or "udp".
+	Status   string // Either "online", "offline", "error" or empty if not checked yet.
+}
+
 // OVNRouterRoute represents a static route added to a logical router.
 type OVNRouterRoute struct {
 	Prefix  net.IPNet
@@ -1761,10 +1786,10 @@ func (o *OVN) aclRuleAddAppendArgs(args []string, entityTable string, entityName
 
 		if rule.Log {
 			args = append(args, "log=true")
+		}
 
-			if rule.LogName != "" {
-				args = append(args, "name="+rule.LogName)
-			}
+		if rule.LogName != "" {
+			args = append(args, "name="+rule.LogName)
 		}
 
 		for k, v := range externalIDs {
@@ -1839,6 +1864,45 @@ func (o *OVN) PortGroupPortClearACLRules(portGroupName OVNPortGroup, portName OV
 	return nil
 }
 
+// ACLFlowCookies returns the OpenFlow cookies of the logical flows generated from the ACL rules whose name
+// starts with the prefix, indexed by ACL rule name.
+func (o *OVN) ACLFlowCookies(namePrefix string) (map[string][]uint64, error) {
+	output, err := o.nbctl("--format=csv", "--no-headings", "--data=bare", "--columns=_uuid,name", "list", "acl")
+	if err != nil {
+		return nil, err
+	}
+
+	cookies := map[string][]uint64{}
+	for _, line := range shared.SplitNTrimSpace(strings.TrimSpace(output), "\n", -1, true) {
+		aclUUID, aclName, found := strings.Cut(line, ",")
+		if !found || len(aclUUID) < 8 || !strings.HasPrefix(aclName, namePrefix) {
+			continue
+		}
+
+		// The logical flows generated from an ACL are tagged with the first part of the ACL UUID.
+		flows, err := o.sbctl("--format=csv", "--no-headings", "--data=bare", "--columns=_uuid", "find", "logical_flow", "external_ids:stage-hint="+aclUUID[:8])
+		if err != nil {
+			return nil, err
+		}
+
+		// The OpenFlow flows generated from a logical flow use the first part of its UUID as cookie.
+		for _, flowUUID := range shared.SplitNTrimSpace(strings.TrimSpace(flows), "\n", -1, true) {
+			if len(flowUUID) < 8 {
+				continue
+			}
+
+			cookie, err := strconv.ParseUint(flowUUID[:8], 16, 64)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid logical flow UUID %q: %w", flowUUID, err)
+			}
+
+			cookies[aclName] = append(cookies[aclName], cookie)
+		}
+	}
+
+	return cookies, nil
+}
+
 // loadBalancerUUIDs returns list of UUID records for named load balancer.
 func (o *OVN) loadBalancerUUIDs(loadBalancerName OVNLoadBalancer) ([]string, error) {
 	lbTCPName := string(loadBalancerName) + "-tcp"
@@ -1881,15 +1945,6 @@ func (o *OVN) LoadBalancerApply(loadBalancerName OVNLoadBalancer, routers []OVNR
 		args = append(args, "--if-exists", "destroy", "load_balancer", lbUUID)
 	}
 
-	// ipToString wraps IPv6 addresses in square brackets.
-	ipToString := func(ip net.IP) string {
-		if ip.To4() == nil {
-			return "[" + ip.String() + "]"
-		}
-
-		return ip.String()
-	}
-
 	// Build up the commands to add VIPs to the load balancer.
 	for _, r := range vips {
 		if r.ListenAddress == nil {
@@ -1918,21 +1973,21 @@ func (o *OVN) LoadBalancerApply(loadBalancerName OVNLoadBalancer, routers []OVNR
 			}
 
 			if r.ListenPort > 0 {
-				targetArgs = append(targetArgs, ipToString(target.Address)+":"+strconv.FormatUint(target.Port, 10))
+				targetArgs = append(targetArgs, loadBalancerIPToString(target.Address)+":"+strconv.FormatUint(target.Port, 10))
 			} else {
-				targetArgs = append(targetArgs, ipToString(target.Address))
+				targetArgs = append(targetArgs, loadBalancerIPToString(target.Address))
 			}
 		}
 
 		if r.ListenPort > 0 {
 			args = append(args,
-				ipToString(r.ListenAddress)+":"+strconv.FormatUint(r.ListenPort, 10),
+				loadBalancerIPToString(r.ListenAddress)+":"+strconv.FormatUint(r.ListenPort, 10),
 				strings.Join(targetArgs, ","),
 				r.Protocol,
 			)
 		} else {
 			args = append(args,
-				ipToString(r.ListenAddress),
+				loadBalancerIPToString(r.ListenAddress),
 				strings.Join(targetArgs, ","),
 			)
 		}
@@ -2016,6 +2071,112 @@ func (o *OVN) LoadBalancerDelete(loadBalancerNames ...OVNLoadBalancer) error {
 	return nil
 }
 
+// loadBalancerIPToString wraps IPv6 addresses in square brackets.
+func loadBalancerIPToString(ip net.IP) string {
+	if ip.To4() == nil {
+		return "[" + ip.String() + "]"
+	}
+
+	return ip.String()
+}
+
+// LoadBalancerHealthCheckApply sets the health checks of the port based VIPs of an existing load balancer.
+// OVN stops sending traffic to the backends failing the health check. Providing a nil health check removes any
+// existing health checks.
+func (o *OVN) LoadBalancerHealthCheckApply(loadBalancerName OVNLoadBalancer, healthCheck *OVNLoadBalancerHealthCheck, vips ...OVNLoadBalancerVIP) error {
+	for _, protocol := range []string{"tcp", "udp"} {
+		lbName := string(loadBalancerName) + "-" + protocol
+
+		// Use find command in order to workaround OVN bug where duplicate records of same name can exist.
+		output, err := o.nbctl("--format=csv", "--no-headings", "--data=bare", "--columns=_uuid", "find", "load_balancer", `name="`+lbName+`"`)
+		if err != nil {
+			return err
+		}
+
+		for _, lbUUID := range shared.SplitNTrimSpace(strings.TrimSpace(output), "\n", -1, true) {
+			// Clearing the health checks deletes them as they aren't referenced from anywhere else.
+			args := []string{"clear", "load_balancer", lbUUID, "health_check", "--", "clear", "load_balancer", lbUUID, "ip_port_mappings"}
+
+			if healthCheck != nil {
+				for i, vip := range vips {
+					vipProtocol := vip.Protocol
+					if vipProtocol == "" {
+						vipProtocol = "tcp"
+					}
+
+					if vipProtocol != protocol || vip.ListenPort == 0 {
+						continue
+					}
+
+					healthCheckID := "@hc" + strconv.Itoa(i)
+					args = append(args, "--", "--id="+healthCheckID, "create", "load_balancer_health_check",
+						"vip="+strconv.Quote(loadBalancerIPToString(vip.ListenAddress)+":"+strconv.FormatUint(vip.ListenPort, 10)),
+						"options:interval="+strconv.FormatUint(healthCheck.Interval, 10),
+						"options:timeout="+strconv.FormatUint(healthCheck.Timeout, 10),
+						"options:success_count="+strconv.FormatUint(healthCheck.SuccessCount, 10),
+						"options:failure_count="+strconv.FormatUint(healthCheck.FailureCount, 10),
+					)
+
+					args = append(args, "--", "add", "load_balancer", lbUUID, "health_check", healthCheckID)
+				}
+
+				for _, target := range healthCheck.Targets {
+					key := loadBalancerIPToString(target.Address)
+					value := string(target.PortName) + ":" + loadBalancerIPToString(target.SourceAddress)
+					args = append(args, "--", "set", "load_balancer", lbUUID, "ip_port_mappings:"+strconv.Quote(key)+"="+strconv.Quote(value))
+				}
+			}
+
+			_, err = o.nbctl(args...)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// LoadBalancerServiceMonitors returns the health of the load balancer backends on the specified logical switch
+// ports as reported by OVN.
+func (o *OVN) LoadBalancerServiceMonitors(portNames ...OVNSwitchPort) ([]OVNServiceMonitor, error) {
+	monitors := []OVNServiceMonitor{}
+
+	for _, portName := range portNames {
+		output, err := o.sbctl("--format=csv", "--no-headings", "--data=bare", "--columns=ip,port,protocol,status", "find", "service_monitor", `logical_port="`+string(portName)+`"`)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, line := range shared.SplitNTrimSpace(strings.TrimSpace(output), "\n", -1, true) {
+			fields := strings.Split(line, ",")
+			if len(fields) != 4 {
+				return nil, fmt.Errorf("Unexpected service monitor output %q", line)
+			}
+
+			port, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid service monitor port %q: %w", fields[1], err)
+			}
+
+			monitor := OVNServiceMonitor{
+				Address:  net.ParseIP(fields[0]),
+				Port:     port,
+				Protocol: fields[2],
+				Status:   fields[3],
+			}
+
+			if monitor.Protocol == "" {
+				monitor.Protocol = "tcp"
+			}
+
+			monitors = append(monitors, monitor)
+		}
+	}
+
+	return monitors, nil
+}
+
 // AddressSetCreate creates address sets for IP versions 4 and 6 in the format "<addressSetPrefix>_ip<IP version>".
 // Populates them with the relevant addresses supplied.
 func (o *OVN) AddressSetCreate(addressSetPrefix OVNAddressSet, addresses ...net.IPNet) error {
@@ -2120,6 +2281,50 @@ func (o *OVN) AddressSetRemove(addressSetPrefix OVNAddressSet, addresses ...net.
 	return nil
 }
 
+// AddressSetSet replaces the addresses of the address sets for IP versions 4 and 6 in a single transaction,
+// creating the address sets if needed.
+// The address set name used is "<addressSetPrefix>_ip<IP version>", e.g. "foo_ip4".
+func (o *OVN) AddressSetSet(addressSetPrefix OVNAddressSet, addresses ...net.IPNet) error {
+	familyAddresses := map[uint][]string{4: {}, 6: {}}
+
+	for _, address := range addresses {
+		var ipVersion uint = 4
+		if address.IP.To4() == nil {
+			ipVersion = 6
+		}
+
+		familyAddresses[ipVersion] = append(familyAddresses[ipVersion], fmt.Sprintf(`"%s"`, address.String()))
+	}
+
+	args := make([]string, 0, 9)
+	for _, ipVersion := range []uint{4, 6} {
+		if len(args) > 0 {
+			args = append(args, "--")
+		}
+
+		args = append(args, "set", "address_set", fmt.Sprintf("%s_ip%d", addressSetPrefix, ipVersion), fmt.Sprintf("addresses=[%s]", strings.Join(familyAddresses[ipVersion], ",")))
+	}
+
+	// Optimistically assume the address sets exist.
+	_, err := o.nbctl(args...)
+	if err != nil {
+		// Try creating the address sets one at a time, but ignore errors here in case some of the
+		// address sets already exist. If there was a problem creating the address set it will be
+		// revealead when we run the original command again next.
+		for _, ipVersion := range []uint{4, 6} {
+			_, _ = o.nbctl("create", "address_set", fmt.Sprintf("name=%s_ip%d", addressSetPrefix, ipVersion))
+		}
+
+		// Try original command again.
+		_, err := o.nbctl(args...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // AddressSetDelete deletes address sets for IP versions 4 and 6 in the format "<addressSetPrefix>_ip<IP version>".
 func (o *OVN) AddressSetDelete(addressSetPrefix OVNAddressSet) error {
 	_, err := o.nbctl(
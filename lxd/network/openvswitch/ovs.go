@@ -191,6 +191,54 @@ func (o *OVS) ChassisID() (string, error) {
 	return chassisID, nil
 }
 
+// OVSFlowCounters represents the traffic matched by the OpenFlow flows sharing a cookie.
+type OVSFlowCounters struct {
+	Packets uint64
+	Bytes   uint64
+}
+
+// BridgeFlowCounters returns the counters of the OpenFlow flows of the bridge, indexed by flow cookie.
+func (o *OVS) BridgeFlowCounters(bridgeName string) (map[uint64]OVSFlowCounters, error) {
+	output, err := shared.RunCommandContext(context.TODO(), "ovs-ofctl", "dump-flows", bridgeName)
+	if err != nil {
+		return nil, err
+	}
+
+	counters := map[uint64]OVSFlowCounters{}
+	for line := range strings.SplitSeq(output, "\n") {
+		// Each flow is described by a list of fields such as "cookie=0x1a2b3c4d, n_packets=2, n_bytes=196".
+		var cookie uint64
+		var flowCounters OVSFlowCounters
+
+		for field := range strings.SplitSeq(line, ",") {
+			key, value, found := strings.Cut(strings.TrimSpace(field), "=")
+			if !found {
+				continue
+			}
+
+			switch key {
+			case "cookie":
+				cookie, _ = strconv.ParseUint(strings.TrimPrefix(value, "0x"), 16, 64)
+			case "n_packets":
+				flowCounters.Packets, _ = strconv.ParseUint(value, 10, 64)
+			case "n_bytes":
+				flowCounters.Bytes, _ = strconv.ParseUint(value, 10, 64)
+			}
+		}
+
+		if cookie == 0 {
+			continue
+		}
+
+		total := counters[cookie]
+		total.Packets += flowCounters.Packets
+		total.Bytes += flowCounters.Bytes
+		counters[cookie] = total
+	}
+
+	return counters, nil
+}
+
 // OVNEncapIP returns the enscapsulation IP used for OVN underlay tunnels.
 func (o *OVS) OVNEncapIP() (net.IP, error) {
 	// ovs-vsctl's get command doesn't support its --format flag, so we always get the output quoted.
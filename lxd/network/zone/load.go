@@ -116,6 +116,9 @@ func Create(s *state.State, projectName string, zoneInfo *api.NetworkZonesPost)
 		return err
 	}
 
+	// Start answering queries for the new zone.
+	s.DNS.Invalidate(zoneInfo.Name)
+
 	// Trigger a refresh of the TSIG entries.
 	err = s.DNS.UpdateTSIG()
 	if err != nil {
@@ -64,6 +64,9 @@ func (d *zone) AddRecord(req api.NetworkZoneRecordsPost) error {
 		return err
 	}
 
+	// Let the peers know about the new record.
+	d.state.DNS.Notify(d.info.Name)
+
 	return nil
 }
 
@@ -157,6 +160,9 @@ func (d *zone) UpdateRecord(name string, req api.NetworkZoneRecordPut, clientTyp
 		return err
 	}
 
+	// Let the peers know about the record change.
+	d.state.DNS.Notify(d.info.Name)
+
 	return nil
 }
 
@@ -183,6 +189,9 @@ func (d *zone) DeleteRecord(name string) error {
 		return err
 	}
 
+	// Let the peers know about the record change.
+	d.state.DNS.Notify(d.info.Name)
+
 	return nil
 }
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"net"
 	"slices"
 	"strconv"
@@ -14,6 +15,9 @@ import (
 	"github.com/canonical/lxd/lxd/cluster"
 	"github.com/canonical/lxd/lxd/config"
 	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
 	"github.com/canonical/lxd/lxd/network"
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
@@ -316,6 +320,13 @@ func (d *zone) Update(config *api.NetworkZonePut, clientType request.ClientType)
 		return err
 	}
 
+	// Let the peers know about the change.
+	if clientType == request.ClientTypeNormal {
+		d.state.DNS.Notify(d.info.Name)
+	} else {
+		d.state.DNS.Invalidate(d.info.Name)
+	}
+
 	revert.Success()
 	return nil
 }
@@ -341,6 +352,9 @@ func (d *zone) Delete() error {
 		return err
 	}
 
+	// Stop answering queries for the deleted zone.
+	d.state.DNS.Invalidate(d.info.Name)
+
 	// Trigger a refresh of the TSIG entries.
 	err = d.state.DNS.UpdateTSIG()
 	if err != nil {
@@ -355,6 +369,9 @@ func (d *zone) Content() (*strings.Builder, error) {
 	var err error
 	records := []map[string]string{}
 
+	// Names of the instances having address records in the zone.
+	instanceNames := make(map[string]struct{})
+
 	// Check if we should include NAT records.
 	includeNAT := shared.IsTrueOrEmpty(d.info.Config["network.nat"])
 
@@ -493,11 +510,25 @@ func (d *zone) Content() (*strings.Builder, error) {
 					}
 
 					records = append(records, record)
+
+					if lease.Type == "static" || lease.Type == "dynamic" {
+						instanceNames[lease.Hostname] = struct{}{}
+					}
 				}
 			}
 		}
 	}
 
+	// Add the service records published by the instances.
+	if len(instanceNames) > 0 {
+		serviceRecords, err := d.serviceRecords(instanceNames)
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, serviceRecords...)
+	}
+
 	// Add the extra records.
 	extraRecords, err := d.GetRecords()
 	if err != nil {
@@ -553,6 +584,68 @@ func (d *zone) Content() (*strings.Builder, error) {
 	return sb, nil
 }
 
+// serviceRecords returns the SRV records published through the `user.dns.srv.*` config keys of the running
+// instances having address records in the zone.
+func (d *zone) serviceRecords(instanceNames map[string]struct{}) ([]map[string]string, error) {
+	records := []map[string]string{}
+
+	err := d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		filter := dbCluster.InstanceFilter{Project: &d.projectName}
+
+		return tx.InstanceList(ctx, func(inst db.InstanceArgs, p api.Project) error {
+			_, found := instanceNames[inst.Name]
+			if !found {
+				return nil
+			}
+
+			// Stopped instances don't provide any service.
+			if inst.Config["volatile.last_state.power"] != instance.PowerStateRunning {
+				return nil
+			}
+
+			instConfig := instancetype.ExpandInstanceConfig(nil, inst.Config, inst.Profiles)
+			records = append(records, instanceServiceRecords(d.info.Name, inst.Name, instConfig)...)
+
+			return nil
+		}, filter)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading instance service records: %w", err)
+	}
+
+	return records, nil
+}
+
+// instanceServiceRecords returns the SRV records published through the `user.dns.srv.*` keys of the instance
+// config. The keys are validated when set, so invalid keys can only come from before that and are skipped.
+func instanceServiceRecords(zoneName string, instanceName string, config map[string]string) []map[string]string {
+	records := []map[string]string{}
+
+	keys := slices.Sorted(maps.Keys(config))
+	for _, key := range keys {
+		service, found := strings.CutPrefix(key, "user.dns.srv.")
+		if !found {
+			continue
+		}
+
+		endpoints, err := instancetype.ParseServiceEndpoints(service, config[key])
+		if err != nil {
+			continue
+		}
+
+		for _, endpoint := range endpoints {
+			records = append(records, map[string]string{
+				"ttl":   "300",
+				"type":  "SRV",
+				"name":  fmt.Sprintf("_%s._%s", service, endpoint.Protocol),
+				"value": fmt.Sprintf("0 0 %d %s.%s.", endpoint.Port, instanceName, zoneName),
+			})
+		}
+	}
+
+	return records
+}
+
 // SOA returns just the DNS zone SOA record.
 func (d *zone) SOA() (*strings.Builder, error) {
 	// Get the nameservers.
@@ -0,0 +1,26 @@
+package zone
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_instanceServiceRecords(t *testing.T) {
+	config := map[string]string{
+		"limits.cpu":        "2",
+		"user.dns.srv.http": "80/tcp,8080/tcp",
+		"user.dns.srv.dns":  "53/udp",
+		"user.dns.srv.ldap": "389",
+		"user.dns.srv.":     "80/tcp",
+		"user.dns.other":    "80/tcp",
+	}
+
+	records := instanceServiceRecords("lxd.example.net", "c1", config)
+
+	assert.Equal(t, []map[string]string{
+		{"ttl": "300", "type": "SRV", "name": "_dns._udp", "value": "0 0 53 c1.lxd.example.net."},
+		{"ttl": "300", "type": "SRV", "name": "_http._tcp", "value": "0 0 80 c1.lxd.example.net."},
+		{"ttl": "300", "type": "SRV", "name": "_http._tcp", "value": "0 0 8080 c1.lxd.example.net."},
+	}, records)
+}
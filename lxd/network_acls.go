@@ -20,7 +20,9 @@ import (
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
 	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/lxd/task"
 	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/entity"
 	"github.com/canonical/lxd/shared/logger"
@@ -53,6 +55,13 @@ var networkACLLogCmd = APIEndpoint{
 	Get: APIEndpointAction{Handler: networkACLLogGet, AccessHandler: allowPermission(entity.TypeNetworkACL, auth.EntitlementCanView, "name")},
 }
 
+var networkACLStateCmd = APIEndpoint{
+	Path:        "network-acls/{name}/state",
+	MetricsType: entity.TypeNetwork,
+
+	Get: APIEndpointAction{Handler: networkACLStateGet, AccessHandler: allowPermission(entity.TypeNetworkACL, auth.EntitlementCanView, "name")},
+}
+
 // API endpoints.
 
 // swagger:operation GET /1.0/network-acls network-acls network_acls_get
@@ -679,6 +688,9 @@ func networkACLPost(d *Daemon, r *http.Request) response.Response {
 //
 //	Gets a specific network ACL log entries.
 //
+//	When following the log, the new entries are streamed as JSON encoded
+//	NetworkACLLogEntry objects, one per line.
+//
 //	---
 //	produces:
 //	  - application/octet-stream
@@ -688,6 +700,11 @@ func networkACLPost(d *Daemon, r *http.Request) response.Response {
 //	    description: Project name
 //	    type: string
 //	    example: default
+//	  - in: query
+//	    name: follow
+//	    description: Whether to stream the new log entries
+//	    type: boolean
+//	    example: true
 //	responses:
 //	  "200":
 //	     description: Raw log file
@@ -723,6 +740,10 @@ func networkACLLogGet(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	if shared.IsTrue(request.QueryParam(r, "follow")) {
+		return networkACLLogFollow(r, netACL, requestor.ClientType())
+	}
+
 	log, err := netACL.GetLog(r.Context(), requestor.ClientType())
 	if err != nil {
 		return response.SmartError(err)
@@ -735,3 +756,134 @@ func networkACLLogGet(d *Daemon, r *http.Request) response.Response {
 
 	return response.FileResponse([]response.FileResponseEntry{ent}, nil)
 }
+
+// networkACLLogFollow streams the entries logged by the network ACL rules until the client disconnects.
+func networkACLLogFollow(r *http.Request, netACL acl.NetworkACL, clientType request.ClientType) response.Response {
+	return response.ManualResponse(func(w http.ResponseWriter) error {
+		f, ok := w.(http.Flusher)
+		if !ok {
+			return errors.New("http.ResponseWriter is not type http.Flusher")
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		f.Flush()
+
+		encoder := json.NewEncoder(w)
+
+		return netACL.FollowLog(r.Context(), clientType, func(entry api.NetworkACLLogEntry) error {
+			err := encoder.Encode(entry)
+			if err != nil {
+				return err
+			}
+
+			f.Flush()
+
+			return nil
+		})
+	})
+}
+
+// swagger:operation GET /1.0/network-acls/{name}/state network-acls network_acl_state_get
+//
+//	Get the network ACL state
+//
+//	Returns the number of packets and bytes matched by each of the network ACL rules.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/NetworkACLState"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func networkACLStateGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName, _, err := project.NetworkProject(s.DB.Cluster, request.ProjectParam(r))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	aclName, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	netACL, err := acl.LoadByName(s, projectName, aclName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	requestor, err := request.GetRequestor(r.Context())
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	aclState, err := netACL.GetState(requestor.ClientType())
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, aclState)
+}
+
+// networkACLTimeBoundRulesTask applies the network ACLs again when their time-bound rules become active or
+// inactive. Only the leader applies the ACLs, as this updates the networks on all cluster members.
+func networkACLTimeBoundRulesTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
+	since := time.Now()
+
+	f := func(ctx context.Context) {
+		s := stateFunc()
+
+		leaderInfo, err := s.LeaderInfo()
+		if err != nil {
+			logger.Error("Failed to get leader cluster member address", logger.Ctx{"err": err})
+			return
+		}
+
+		// Other members don't move since forward, so that the rules becoming active or inactive while the
+		// leader changes are caught up with once they become the leader.
+		if !leaderInfo.Leader {
+			return
+		}
+
+		now := time.Now()
+		err = acl.RefreshTimeBoundRules(s, since, now)
+		if err != nil {
+			logger.Error("Failed applying time-bound network ACL rules", logger.Ctx{"err": err})
+			return
+		}
+
+		since = now
+	}
+
+	return f, task.Every(time.Minute)
+}
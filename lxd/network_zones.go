@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/gorilla/mux"
 
@@ -18,9 +19,11 @@ import (
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
 	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/lxd/task"
 	"github.com/canonical/lxd/lxd/util"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
 	"github.com/canonical/lxd/shared/version"
 )
 
@@ -645,3 +648,25 @@ func networkZonePut(d *Daemon, r *http.Request) response.Response {
 
 	return response.EmptySyncResponse
 }
+
+// networkZonesRefreshTask refreshes the network zones cached by the DNS server and notifies the DNS peers of the
+// network zones whose records changed, including the records generated from the networks using them.
+// Only the leader sends the notifications.
+func networkZonesRefreshTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := stateFunc()
+
+		leaderInfo, err := s.LeaderInfo()
+		if err != nil {
+			logger.Error("Failed to get leader cluster member address", logger.Ctx{"err": err})
+			return
+		}
+
+		err = s.DNS.RefreshZones(ctx, leaderInfo.Leader)
+		if err != nil {
+			logger.Error("Failed refreshing network zones", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Every(time.Minute)
+}
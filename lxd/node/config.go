@@ -83,6 +83,11 @@ func (c *Config) DNSAddress() string {
 	return c.m.GetString("core.dns_address")
 }
 
+// DNSAuthoritative returns whether the DNS server answers regular queries for the records of the network zones.
+func (c *Config) DNSAuthoritative() bool {
+	return c.m.GetBool("core.dns_authoritative")
+}
+
 // MetricsAddress returns the address and port to setup the metrics listener on.
 func (c *Config) MetricsAddress() string {
 	metricsAddress := c.m.GetString("core.metrics_address")
@@ -239,6 +244,19 @@ var ConfigSchema = config.Schema{
 		//  shortdesc: Address to bind the authoritative DNS server to
 		"core.dns_address": {Validator: validate.Optional(validate.IsListenAddress(true, true, false))},
 
+		// Whether the DNS server answers regular queries
+
+		// lxdmeta:generate(entities=server; group=core; key=core.dns_authoritative)
+		// By default, the DNS server only allows the peers of the network zones to transfer them.
+		// When enabled, it also answers queries for the records of all network zones from any client.
+		// See {ref}`network-dns-server`.
+		// ---
+		//  type: bool
+		//  scope: local
+		//  defaultdesc: `false`
+		//  shortdesc: Whether the DNS server answers regular queries
+		"core.dns_authoritative": {Validator: validate.Optional(validate.IsBool), Type: config.Bool},
+
 		// Network address for the metrics server
 
 		// lxdmeta:generate(entities=server; group=core; key=core.metrics_address)
@@ -0,0 +1,131 @@
+package placement
+
+import (
+	"context"
+	"slices"
+	"strconv"
+
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// getMemberDomains returns a map of cluster member ID to the failure domain the member belongs to for the given domain key.
+//
+// The domain key can be one of [api.PlacementDomainMember] (or empty), [api.PlacementDomainClusterGroup],
+// [api.PlacementDomainFailureDomain] or a cluster member configuration key (e.g. "user.rack").
+// Members that do not have a value for a member configuration key are treated as their own failure domain.
+func getMemberDomains(ctx context.Context, tx *db.ClusterTx, domainKey string) (map[int]string, error) {
+	members, err := tx.GetNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var memberFailureDomains map[string]uint64
+	var failureDomainNames map[uint64]string
+	if domainKey == api.PlacementDomainFailureDomain {
+		memberFailureDomains, err = tx.GetNodesFailureDomains(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		failureDomainNames, err = tx.GetFailureDomainsNames(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	memberToDomain := make(map[int]string, len(members))
+	for _, member := range members {
+		memberToDomain[int(member.ID)] = memberDomain(member, domainKey, memberFailureDomains, failureDomainNames)
+	}
+
+	return memberToDomain, nil
+}
+
+// memberDomain returns the failure domain of the given cluster member for the given domain key.
+func memberDomain(member db.NodeInfo, domainKey string, memberFailureDomains map[string]uint64, failureDomainNames map[uint64]string) string {
+	switch domainKey {
+	case "", api.PlacementDomainMember:
+		return member.Name
+	case api.PlacementDomainClusterGroup:
+		// Use the first non-default cluster group (in alphabetical order) so that the result is stable.
+		groups := slices.Clone(member.Groups)
+		slices.Sort(groups)
+		for _, group := range groups {
+			if group != "default" {
+				return group
+			}
+		}
+
+		return "default"
+	case api.PlacementDomainFailureDomain:
+		name, ok := failureDomainNames[memberFailureDomains[member.Address]]
+		if !ok {
+			return "default"
+		}
+
+		return name
+	default:
+		value := member.Config[domainKey]
+		if value == "" {
+			return member.Name
+		}
+
+		return value
+	}
+}
+
+// domainOf returns the failure domain of the cluster member with the given ID.
+// Members that are unknown to memberToDomain are treated as their own failure domain.
+func domainOf(memberToDomain map[int]string, memberID int) string {
+	domain, ok := memberToDomain[memberID]
+	if !ok {
+		return strconv.Itoa(memberID)
+	}
+
+	return domain
+}
+
+// InstanceDomains returns a map of instance URL to the failure domain of the cluster member the instance is located on
+// for all instances in the given [api.PlacementGroup].
+func InstanceDomains(ctx context.Context, tx *db.ClusterTx, apiPlacementGroup api.PlacementGroup) (map[string]string, error) {
+	memberToInst, err := cluster.GetInstancesInPlacementGroup(ctx, tx.Tx(), cluster.PlacementGroupFilter{Project: &apiPlacementGroup.Project, Name: &apiPlacementGroup.Name})
+	if err != nil {
+		return nil, err
+	}
+
+	domains := make(map[string]string)
+	if len(memberToInst) == 0 {
+		return domains, nil
+	}
+
+	memberToDomain, err := getMemberDomains(ctx, tx, apiPlacementGroup.Config["domain"])
+	if err != nil {
+		return nil, err
+	}
+
+	instances, err := cluster.GetInstances(ctx, tx.Tx(), cluster.InstanceFilter{Project: &apiPlacementGroup.Project})
+	if err != nil {
+		return nil, err
+	}
+
+	instanceNames := make(map[int]string, len(instances))
+	for _, inst := range instances {
+		instanceNames[inst.ID] = inst.Name
+	}
+
+	for memberID, instIDs := range memberToInst {
+		for _, instID := range instIDs {
+			name, ok := instanceNames[instID]
+			if !ok {
+				continue
+			}
+
+			u := api.NewURL().Project(apiPlacementGroup.Project).Path("1.0", "instances", name)
+			domains[u.String()] = domainOf(memberToDomain, memberID)
+		}
+	}
+
+	return domains, nil
+}
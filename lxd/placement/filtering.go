@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 	"slices"
+	"strconv"
 
 	"github.com/canonical/lxd/lxd/db"
 	"github.com/canonical/lxd/lxd/db/cluster"
@@ -17,6 +18,11 @@ func Filter(ctx context.Context, tx *db.ClusterTx, candidates []db.NodeInfo, api
 	policy := apiPlacementGroup.Config["policy"]
 	rigor := apiPlacementGroup.Config["rigor"]
 
+	maxPerDomain, err := getMaxPerDomain(apiPlacementGroup)
+	if err != nil {
+		return nil, err
+	}
+
 	pgFilter := cluster.PlacementGroupFilter{Project: &apiPlacementGroup.Project, Name: &apiPlacementGroup.Name}
 
 	// If this is an evacuation request, exclude instances on the source cluster member.
@@ -33,8 +39,14 @@ func Filter(ctx context.Context, tx *db.ClusterTx, candidates []db.NodeInfo, api
 		return nil, err
 	}
 
+	// Get the failure domain of each cluster member.
+	memberToDomain, err := getMemberDomains(ctx, tx, apiPlacementGroup.Config["domain"])
+	if err != nil {
+		return nil, err
+	}
+
 	// Get compliant cluster members using the placement group.
-	filteredCandidates, err := getCompliantMembers(policy, rigor, candidates, memberToInst)
+	filteredCandidates, err := getCompliantMembers(policy, rigor, maxPerDomain, candidates, memberToInst, memberToDomain)
 	if err != nil {
 		return nil, api.StatusErrorf(http.StatusConflict, "Failed filtering candidate cluster members using placement group %q with %q policy and %q rigor: %w", apiPlacementGroup.Name, policy, rigor, err)
 	}
@@ -42,17 +54,50 @@ func Filter(ctx context.Context, tx *db.ClusterTx, candidates []db.NodeInfo, api
 	return filteredCandidates, nil
 }
 
+// getMaxPerDomain returns the maximum number of instances per failure domain of the given [api.PlacementGroup].
+// Zero means the policy default is used.
+func getMaxPerDomain(apiPlacementGroup api.PlacementGroup) (int, error) {
+	value := apiPlacementGroup.Config["domain.max_instances"]
+	if value == "" {
+		return 0, nil
+	}
+
+	maxPerDomain, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, api.StatusErrorf(http.StatusBadRequest, "Invalid placement group %q maximum instances per domain: %w", apiPlacementGroup.Name, err)
+	}
+
+	return maxPerDomain, nil
+}
+
 // getCompliantMembers gets compliant cluster members from the provided candidates based on the given placement policy and rigor.
-func getCompliantMembers(policy string, rigor string, candidates []db.NodeInfo, memberToInst map[int][]int) ([]db.NodeInfo, error) {
+//
+// Instances are counted per failure domain using memberToDomain. A maxPerDomain of zero means the policy default is used,
+// which is one instance per domain for the spread policy and no limit for the compact policy.
+func getCompliantMembers(policy string, rigor string, maxPerDomain int, candidates []db.NodeInfo, memberToInst map[int][]int, memberToDomain map[int]string) ([]db.NodeInfo, error) {
 	var compliantCandidates []db.NodeInfo
 
+	// Count the instances in each failure domain.
+	domainToCount := make(map[string]int)
+	for memberID, instances := range memberToInst {
+		domainToCount[domainOf(memberToDomain, memberID)] += len(instances)
+	}
+
+	candidateDomain := func(c db.NodeInfo) string {
+		return domainOf(memberToDomain, int(c.ID))
+	}
+
 	switch {
 	case policy == api.PlacementPolicySpread && rigor == api.PlacementRigorStrict:
-		// Spread + Strict: Place at most one instance per cluster member.
-		// Filter out candidates that already have instances.
+		// Spread + Strict: Place at most maxPerDomain instances (one by default) per failure domain.
+		// Filter out candidates whose failure domain is already full.
+		limit := maxPerDomain
+		if limit == 0 {
+			limit = 1
+		}
+
 		for _, c := range candidates {
-			_, hasInst := memberToInst[int(c.ID)]
-			if !hasInst {
+			if domainToCount[candidateDomain(c)] < limit {
 				compliantCandidates = append(compliantCandidates, c)
 			}
 		}
@@ -64,13 +109,13 @@ func getCompliantMembers(policy string, rigor string, candidates []db.NodeInfo,
 		return compliantCandidates, nil
 
 	case policy == api.PlacementPolicySpread && rigor == api.PlacementRigorPermissive:
-		// Spread + Permissive: Prefer spreading instances evenly across cluster members.
-		// The number of instances per cluster member differs by at most one.
+		// Spread + Permissive: Prefer spreading instances evenly across failure domains.
+		// The number of instances per failure domain differs by at most one.
 
-		// Find the minimum instance count among candidates.
+		// Find the minimum instance count among candidate failure domains.
 		counts := make([]int, 0, len(candidates))
 		for _, c := range candidates {
-			counts = append(counts, len(memberToInst[int(c.ID)]))
+			counts = append(counts, domainToCount[candidateDomain(c)])
 		}
 
 		minInstances := 0
@@ -78,11 +123,26 @@ func getCompliantMembers(policy string, rigor string, candidates []db.NodeInfo,
 			minInstances = slices.Min(counts)
 		}
 
-		// Filter candidates to only those with at most minInstances instances.
-		// This ensures the number of instances per cluster member differs by at most one.
+		// Filter candidates to only those in failure domains with at most minInstances instances.
+		// This ensures the number of instances per failure domain differs by at most one.
+		var domainCandidates []db.NodeInfo
 		for _, c := range candidates {
+			if domainToCount[candidateDomain(c)] <= minInstances {
+				domainCandidates = append(domainCandidates, c)
+			}
+		}
+
+		// Within the selected failure domains, prefer the cluster members with the fewest instances.
+		minMemberInstances := -1
+		for _, c := range domainCandidates {
 			instanceCount := len(memberToInst[int(c.ID)])
-			if instanceCount <= minInstances {
+			if minMemberInstances < 0 || instanceCount < minMemberInstances {
+				minMemberInstances = instanceCount
+			}
+		}
+
+		for _, c := range domainCandidates {
+			if len(memberToInst[int(c.ID)]) <= minMemberInstances {
 				compliantCandidates = append(compliantCandidates, c)
 			}
 		}
@@ -94,29 +154,29 @@ func getCompliantMembers(policy string, rigor string, candidates []db.NodeInfo,
 		return compliantCandidates, nil
 
 	case policy == api.PlacementPolicyCompact && rigor == api.PlacementRigorStrict:
-		// Compact + Strict: Place all instances on the same cluster member.
-		// The member with the most instances determines the cluster member.
-		if len(memberToInst) == 0 {
-			// No instances yet.
-			// All candidates are valid (first instance determines the member).
-			return candidates, nil
-		}
-
-		// Find which member has the most instances from this placement group.
-		var targetMemberID int
-		maxInstances := -1
-		for memberID, instances := range memberToInst {
-			if len(instances) > maxInstances {
-				maxInstances = len(instances)
-				targetMemberID = memberID
+		// Compact + Strict: Place all instances in the same failure domain, up to maxPerDomain instances (unlimited by default).
+		// The failure domain with the most instances that is not full determines the failure domain.
+		targetDomain, found := getCompactDomain(domainToCount, maxPerDomain)
+		if !found {
+			// No instances yet or all occupied failure domains are full.
+			// All candidates in failure domains that are not full are valid (next instance determines the domain).
+			for _, c := range candidates {
+				if maxPerDomain == 0 || domainToCount[candidateDomain(c)] < maxPerDomain {
+					compliantCandidates = append(compliantCandidates, c)
+				}
+			}
+
+			if len(compliantCandidates) == 0 {
+				return nil, errors.New("No eligible cluster members available")
 			}
+
+			return compliantCandidates, nil
 		}
 
-		// Filter candidates to only include the node with the most instances.
+		// Filter candidates to only include members in the failure domain with the most instances.
 		for _, c := range candidates {
-			if int(c.ID) == targetMemberID {
+			if candidateDomain(c) == targetDomain {
 				compliantCandidates = append(compliantCandidates, c)
-				break
 			}
 		}
 
@@ -127,35 +187,58 @@ func getCompliantMembers(policy string, rigor string, candidates []db.NodeInfo,
 		return compliantCandidates, nil
 
 	case policy == api.PlacementPolicyCompact && rigor == api.PlacementRigorPermissive:
-		// Compact + Permissive: Prefer to place all instances on the same cluster member.
-		if len(memberToInst) == 0 {
-			// No instances yet.
-			// All candidates are valid (first instance determines preferred member).
-			return candidates, nil
-		}
-
-		// Find which member has the most instances from this placement group.
-		var preferredMemberID int
-		maxInstances := -1
-		for memberID, instances := range memberToInst {
-			if len(instances) > maxInstances {
-				maxInstances = len(instances)
-				preferredMemberID = memberID
+		// Compact + Permissive: Prefer to place all instances in the same failure domain.
+		preferredDomain, found := getCompactDomain(domainToCount, maxPerDomain)
+
+		// Check if members of the preferred failure domain are in candidates.
+		for _, c := range candidates {
+			if found && candidateDomain(c) == preferredDomain {
+				// Preferred failure domain is available.
+				compliantCandidates = append(compliantCandidates, c)
 			}
 		}
 
-		// Check if preferred member is in candidates.
-		for _, c := range candidates {
-			if int(c.ID) == preferredMemberID {
-				// Preferred member is available.
-				return []db.NodeInfo{c}, nil
+		if len(compliantCandidates) > 0 {
+			return compliantCandidates, nil
+		}
+
+		// Preferred failure domain is not available - fall back to candidates in failure domains that are not full.
+		if maxPerDomain > 0 {
+			for _, c := range candidates {
+				if domainToCount[candidateDomain(c)] < maxPerDomain {
+					compliantCandidates = append(compliantCandidates, c)
+				}
+			}
+
+			if len(compliantCandidates) > 0 {
+				return compliantCandidates, nil
 			}
 		}
 
-		// Preferred node is not available - fall back to all candidates.
+		// Fall back to all candidates.
 		return candidates, nil
 
 	default:
 		return nil, errors.New("Invalid placement group")
 	}
 }
+
+// getCompactDomain returns the failure domain with the most instances that has fewer than maxPerDomain instances.
+// A maxPerDomain of zero means there is no limit. Ties are broken using the domain name so that the result is stable.
+// Returns false if no failure domain has instances or all occupied failure domains are full.
+func getCompactDomain(domainToCount map[string]int, maxPerDomain int) (string, bool) {
+	var targetDomain string
+	maxInstances := 0
+	for domain, count := range domainToCount {
+		if maxPerDomain > 0 && count >= maxPerDomain {
+			continue
+		}
+
+		if count > maxInstances || (count == maxInstances && count > 0 && domain < targetDomain) {
+			maxInstances = count
+			targetDomain = domain
+		}
+	}
+
+	return targetDomain, maxInstances > 0
+}
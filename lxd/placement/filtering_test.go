@@ -609,3 +609,115 @@ func (s *filteringSuite) TestFilter() {
 		}
 	}
 }
+
+func (s *filteringSuite) TestGetCompliantMembersDomains() {
+	candidates := []db.NodeInfo{
+		{ID: 1, Name: "member01"},
+		{ID: 2, Name: "member02"},
+		{ID: 3, Name: "member03"},
+		{ID: 4, Name: "member04"},
+	}
+
+	// Two members per rack.
+	memberToDomain := map[int]string{
+		1: "rack1",
+		2: "rack1",
+		3: "rack2",
+		4: "rack2",
+	}
+
+	candidatesOnly := func(ids ...int64) []db.NodeInfo {
+		filteredCandidates := make([]db.NodeInfo, 0, len(ids))
+		for _, candidate := range candidates {
+			if slices.Contains(ids, candidate.ID) {
+				filteredCandidates = append(filteredCandidates, candidate)
+			}
+		}
+
+		return filteredCandidates
+	}
+
+	tests := []struct {
+		name         string
+		policy       string
+		rigor        string
+		maxPerDomain int
+		memberToInst map[int][]int
+		want         []db.NodeInfo
+		wantErr      bool
+	}{
+		{
+			name:         "spread/strict: rack occupied",
+			policy:       api.PlacementPolicySpread,
+			rigor:        api.PlacementRigorStrict,
+			memberToInst: map[int][]int{1: {1}},
+			want:         candidatesOnly(3, 4),
+		},
+		{
+			name:         "spread/strict: all racks occupied",
+			policy:       api.PlacementPolicySpread,
+			rigor:        api.PlacementRigorStrict,
+			memberToInst: map[int][]int{1: {1}, 3: {2}},
+			wantErr:      true,
+		},
+		{
+			name:         "spread/strict: rack below max instances",
+			policy:       api.PlacementPolicySpread,
+			rigor:        api.PlacementRigorStrict,
+			maxPerDomain: 2,
+			memberToInst: map[int][]int{1: {1}, 3: {2, 3}},
+			want:         candidatesOnly(1, 2),
+		},
+		{
+			name:         "spread/permissive: least loaded member in least loaded rack",
+			policy:       api.PlacementPolicySpread,
+			rigor:        api.PlacementRigorPermissive,
+			memberToInst: map[int][]int{1: {1}, 3: {2}, 4: {3}},
+			want:         candidatesOnly(2),
+		},
+		{
+			name:         "compact/strict: rack with most instances",
+			policy:       api.PlacementPolicyCompact,
+			rigor:        api.PlacementRigorStrict,
+			memberToInst: map[int][]int{1: {1}, 3: {2}, 4: {3}},
+			want:         candidatesOnly(3, 4),
+		},
+		{
+			name:         "compact/strict: full rack moves to next rack",
+			policy:       api.PlacementPolicyCompact,
+			rigor:        api.PlacementRigorStrict,
+			maxPerDomain: 2,
+			memberToInst: map[int][]int{3: {1}, 4: {2}},
+			want:         candidatesOnly(1, 2),
+		},
+		{
+			name:         "compact/strict: all racks full",
+			policy:       api.PlacementPolicyCompact,
+			rigor:        api.PlacementRigorStrict,
+			maxPerDomain: 1,
+			memberToInst: map[int][]int{1: {1}, 3: {2}},
+			wantErr:      true,
+		},
+		{
+			name:         "compact/permissive: all racks full falls back to all candidates",
+			policy:       api.PlacementPolicyCompact,
+			rigor:        api.PlacementRigorPermissive,
+			maxPerDomain: 1,
+			memberToInst: map[int][]int{1: {1}, 3: {2}},
+			want:         candidates,
+		},
+	}
+
+	for i, tt := range tests {
+		s.T().Logf("Case %d: %s", i, tt.name)
+
+		got, err := getCompliantMembers(tt.policy, tt.rigor, tt.maxPerDomain, candidates, tt.memberToInst, memberToDomain)
+		if tt.wantErr {
+			s.Error(err)
+			continue
+		}
+
+		s.Require().NoError(err)
+		s.ElementsMatch(tt.want, got)
+	}
+}
@@ -0,0 +1,274 @@
+package placement
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// RebalanceMember represents a cluster member considered when planning cluster rebalancing.
+type RebalanceMember struct {
+	Info        db.NodeInfo
+	TotalMemory uint64
+	UsedMemory  uint64
+}
+
+// load returns the memory usage of the cluster member in percent.
+func (m *RebalanceMember) load() float64 {
+	if m.TotalMemory == 0 {
+		return 0
+	}
+
+	return float64(m.UsedMemory) * 100 / float64(m.TotalMemory)
+}
+
+// RebalanceGroup represents a placement group considered when planning cluster rebalancing.
+type RebalanceGroup struct {
+	PlacementGroup api.PlacementGroup
+	MaxPerDomain   int
+	MemberToDomain map[int]string
+}
+
+// NewRebalanceGroup returns a [RebalanceGroup] for the given [api.PlacementGroup].
+func NewRebalanceGroup(ctx context.Context, tx *db.ClusterTx, apiPlacementGroup api.PlacementGroup) (*RebalanceGroup, error) {
+	maxPerDomain, err := getMaxPerDomain(apiPlacementGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	memberToDomain, err := getMemberDomains(ctx, tx, apiPlacementGroup.Config["domain"])
+	if err != nil {
+		return nil, err
+	}
+
+	return &RebalanceGroup{
+		PlacementGroup: apiPlacementGroup,
+		MaxPerDomain:   maxPerDomain,
+		MemberToDomain: memberToDomain,
+	}, nil
+}
+
+// RebalanceInstance represents an instance considered when planning cluster rebalancing.
+type RebalanceInstance struct {
+	ID       int
+	Name     string
+	Project  string
+	MemberID int
+
+	// Estimated memory usage of the instance in bytes.
+	Memory uint64
+
+	// Whether the instance can be moved at all and whether it can be live-migrated.
+	Movable bool
+	Live    bool
+
+	// Placement group of the instance (nil if the instance isn't in a placement group).
+	Group *RebalanceGroup
+
+	// Cluster members the instance is allowed to be moved to.
+	Candidates []db.NodeInfo
+}
+
+// PlanRebalance returns the instance moves needed to fix placement group violations and to reduce the difference in
+// memory usage between the provided cluster members to no more than threshold percent.
+//
+// Placement group violations are fixed first. Each instance is moved at most once.
+func PlanRebalance(members []RebalanceMember, instances []RebalanceInstance, threshold float64) []api.ClusterRebalanceMove {
+	p := &rebalancePlan{
+		members:   make(map[int]*RebalanceMember, len(members)),
+		instances: slices.Clone(instances),
+		moved:     make(map[int]bool),
+	}
+
+	for i := range members {
+		p.members[int(members[i].Info.ID)] = &members[i]
+	}
+
+	// Sort the instances so that plans are stable.
+	slices.SortFunc(p.instances, func(a RebalanceInstance, b RebalanceInstance) int {
+		return cmp.Or(cmp.Compare(a.Project, b.Project), cmp.Compare(a.Name, b.Name))
+	})
+
+	p.planPlacement()
+	p.planLoad(threshold)
+
+	return p.moves
+}
+
+type rebalancePlan struct {
+	members   map[int]*RebalanceMember
+	instances []RebalanceInstance
+	moved     map[int]bool
+	moves     []api.ClusterRebalanceMove
+}
+
+// groupMemberToInst returns a map of cluster member ID to instance IDs for the instances in the given placement group,
+// excluding the instance with the given ID.
+func (p *rebalancePlan) groupMemberToInst(group *RebalanceGroup, excludeID int) map[int][]int {
+	memberToInst := make(map[int][]int)
+	for _, inst := range p.instances {
+		if inst.Group != group || inst.ID == excludeID {
+			continue
+		}
+
+		memberToInst[inst.MemberID] = append(memberToInst[inst.MemberID], inst.ID)
+	}
+
+	return memberToInst
+}
+
+// compliantMembers returns the cluster members the given instance can be located on without violating its
+// placement group. The instance's current cluster member is always considered.
+func (p *rebalancePlan) compliantMembers(inst RebalanceInstance) ([]db.NodeInfo, error) {
+	candidates := slices.Clone(inst.Candidates)
+	if !slices.ContainsFunc(candidates, func(c db.NodeInfo) bool { return int(c.ID) == inst.MemberID }) {
+		member, ok := p.members[inst.MemberID]
+		if ok {
+			candidates = append(candidates, member.Info)
+		}
+	}
+
+	group := inst.Group
+	return getCompliantMembers(group.PlacementGroup.Config["policy"], group.PlacementGroup.Config["rigor"], group.MaxPerDomain, candidates, p.groupMemberToInst(group, inst.ID), group.MemberToDomain)
+}
+
+// move records the move of the instance at the given index to the given cluster member.
+func (p *rebalancePlan) move(i int, target *RebalanceMember, reason string) {
+	inst := &p.instances[i]
+	source := p.members[inst.MemberID]
+
+	sourceName := ""
+	if source != nil {
+		sourceName = source.Info.Name
+		source.UsedMemory -= min(source.UsedMemory, inst.Memory)
+	}
+
+	target.UsedMemory += inst.Memory
+
+	p.moves = append(p.moves, api.ClusterRebalanceMove{
+		Instance: inst.Name,
+		Project:  inst.Project,
+		Source:   sourceName,
+		Target:   target.Info.Name,
+		Live:     inst.Live,
+		Reason:   reason,
+	})
+
+	inst.MemberID = int(target.Info.ID)
+	p.moved[inst.ID] = true
+}
+
+// planPlacement plans moves for instances that are located on cluster members that violate their placement group.
+func (p *rebalancePlan) planPlacement() {
+	for i, inst := range p.instances {
+		if !inst.Movable || inst.Group == nil || p.moved[inst.ID] {
+			continue
+		}
+
+		compliant, err := p.compliantMembers(inst)
+		if err != nil {
+			// The violation can't be fixed with the available cluster members.
+			continue
+		}
+
+		if slices.ContainsFunc(compliant, func(c db.NodeInfo) bool { return int(c.ID) == inst.MemberID }) {
+			continue
+		}
+
+		// Move the instance to the least loaded compliant cluster member.
+		var target *RebalanceMember
+		for _, c := range compliant {
+			member, ok := p.members[int(c.ID)]
+			if !ok {
+				continue
+			}
+
+			if target == nil || member.load() < target.load() {
+				target = member
+			}
+		}
+
+		if target == nil {
+			continue
+		}
+
+		p.move(i, target, api.ClusterRebalanceReasonPlacement)
+	}
+}
+
+// planLoad plans moves from the most loaded to the least loaded cluster member until the difference in memory usage
+// between them is no more than threshold percent or no instance can be moved to reduce it.
+func (p *rebalancePlan) planLoad(threshold float64) {
+	for range p.instances {
+		var busiest, idlest *RebalanceMember
+		for _, member := range p.members {
+			if member.TotalMemory == 0 {
+				continue
+			}
+
+			if busiest == nil || member.load() > busiest.load() || (member.load() == busiest.load() && member.Info.ID < busiest.Info.ID) {
+				busiest = member
+			}
+
+			if idlest == nil || member.load() < idlest.load() || (member.load() == idlest.load() && member.Info.ID < idlest.Info.ID) {
+				idlest = member
+			}
+		}
+
+		if busiest == nil || busiest == idlest || busiest.load()-idlest.load() <= threshold {
+			return
+		}
+
+		imbalance := busiest.load() - idlest.load()
+
+		// Try the largest instances first to minimise the number of moves.
+		indexes := make([]int, 0, len(p.instances))
+		for i, inst := range p.instances {
+			if inst.MemberID == int(busiest.Info.ID) {
+				indexes = append(indexes, i)
+			}
+		}
+
+		slices.SortStableFunc(indexes, func(a int, b int) int {
+			return cmp.Compare(p.instances[b].Memory, p.instances[a].Memory)
+		})
+
+		found := false
+		for _, i := range indexes {
+			inst := p.instances[i]
+			if !inst.Movable || p.moved[inst.ID] || inst.Memory == 0 {
+				continue
+			}
+
+			if !slices.ContainsFunc(inst.Candidates, func(c db.NodeInfo) bool { return c.ID == idlest.Info.ID }) {
+				continue
+			}
+
+			// Only move the instance if it reduces the imbalance.
+			sourceLoad := float64(busiest.UsedMemory-min(busiest.UsedMemory, inst.Memory)) * 100 / float64(busiest.TotalMemory)
+			targetLoad := float64(idlest.UsedMemory+inst.Memory) * 100 / float64(idlest.TotalMemory)
+			if max(sourceLoad, targetLoad)-min(sourceLoad, targetLoad) >= imbalance {
+				continue
+			}
+
+			// Don't break the instance's placement group.
+			if inst.Group != nil {
+				compliant, err := p.compliantMembers(inst)
+				if err != nil || !slices.ContainsFunc(compliant, func(c db.NodeInfo) bool { return c.ID == idlest.Info.ID }) {
+					continue
+				}
+			}
+
+			p.move(i, idlest, api.ClusterRebalanceReasonLoad)
+			found = true
+			break
+		}
+
+		if !found {
+			return
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package placement
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/shared/api"
+)
+
+func TestPlanRebalance(t *testing.T) {
+	const gib = 1024 * 1024 * 1024
+
+	nodes := []db.NodeInfo{
+		{ID: 1, Name: "member01"},
+		{ID: 2, Name: "member02"},
+		{ID: 3, Name: "member03"},
+	}
+
+	spreadGroup := &RebalanceGroup{
+		PlacementGroup: api.PlacementGroup{Name: "pg1", Project: "default", Config: map[string]string{"policy": api.PlacementPolicySpread, "rigor": api.PlacementRigorStrict}},
+		MemberToDomain: map[int]string{1: "member01", 2: "member02", 3: "member03"},
+	}
+
+	tests := []struct {
+		name      string
+		usedGiB   []uint64
+		instances []RebalanceInstance
+		want      []api.ClusterRebalanceMove
+	}{
+		{
+			name:    "balanced cluster",
+			usedGiB: []uint64{4, 4, 4},
+			instances: []RebalanceInstance{
+				{ID: 1, Name: "c1", Project: "default", MemberID: 1, Memory: 4 * gib, Movable: true, Candidates: nodes},
+				{ID: 2, Name: "c2", Project: "default", MemberID: 2, Memory: 4 * gib, Movable: true, Candidates: nodes},
+				{ID: 3, Name: "c3", Project: "default", MemberID: 3, Memory: 4 * gib, Movable: true, Candidates: nodes},
+			},
+		},
+		{
+			name:    "spread placement group violation",
+			usedGiB: []uint64{4, 0, 2},
+			instances: []RebalanceInstance{
+				{ID: 1, Name: "c1", Project: "default", MemberID: 1, Memory: 2 * gib, Movable: true, Group: spreadGroup, Candidates: nodes},
+				{ID: 2, Name: "c2", Project: "default", MemberID: 1, Memory: 2 * gib, Movable: true, Group: spreadGroup, Candidates: nodes},
+				{ID: 3, Name: "c3", Project: "default", MemberID: 3, Memory: 2 * gib, Movable: true, Candidates: nodes},
+			},
+			want: []api.ClusterRebalanceMove{
+				{Instance: "c1", Project: "default", Source: "member01", Target: "member02", Reason: api.ClusterRebalanceReasonPlacement},
+			},
+		},
+		{
+			name:    "load imbalance",
+			usedGiB: []uint64{8, 2, 2},
+			instances: []RebalanceInstance{
+				{ID: 1, Name: "c1", Project: "default", MemberID: 1, Memory: 4 * gib, Movable: true, Live: true, Candidates: nodes},
+				{ID: 2, Name: "c2", Project: "default", MemberID: 1, Memory: 2 * gib, Movable: true, Candidates: nodes},
+				{ID: 3, Name: "c3", Project: "default", MemberID: 1, Memory: 2 * gib, Movable: true, Candidates: nodes},
+			},
+			want: []api.ClusterRebalanceMove{
+				{Instance: "c1", Project: "default", Source: "member01", Target: "member02", Live: true, Reason: api.ClusterRebalanceReasonLoad},
+			},
+		},
+		{
+			name:    "load imbalance without movable instances",
+			usedGiB: []uint64{8, 2, 2},
+			instances: []RebalanceInstance{
+				{ID: 1, Name: "c1", Project: "default", MemberID: 1, Memory: 4 * gib, Movable: false, Candidates: nodes},
+			},
+		},
+		{
+			name:    "load move doesn't break placement group",
+			usedGiB: []uint64{8, 2, 2},
+			instances: []RebalanceInstance{
+				{ID: 1, Name: "c1", Project: "default", MemberID: 1, Memory: 2 * gib, Movable: true, Group: spreadGroup, Candidates: nodes},
+				{ID: 2, Name: "c2", Project: "default", MemberID: 2, Memory: 2 * gib, Movable: true, Group: spreadGroup, Candidates: nodes},
+				{ID: 3, Name: "c3", Project: "default", MemberID: 3, Memory: 2 * gib, Movable: true, Group: spreadGroup, Candidates: nodes},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			members := make([]RebalanceMember, 0, len(nodes))
+			for i, node := range nodes {
+				members = append(members, RebalanceMember{Info: node, TotalMemory: 16 * gib, UsedMemory: tt.usedGiB[i] * gib})
+			}
+
+			got := PlanRebalance(members, tt.instances, 20)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
 
 	"github.com/gorilla/mux"
@@ -15,6 +17,7 @@ import (
 	"github.com/canonical/lxd/lxd/db"
 	"github.com/canonical/lxd/lxd/db/cluster"
 	"github.com/canonical/lxd/lxd/lifecycle"
+	"github.com/canonical/lxd/lxd/placement"
 	"github.com/canonical/lxd/lxd/project"
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
@@ -222,6 +225,12 @@ func placementGroupsGet(d *Daemon, r *http.Request) response.Response {
 			}
 
 			apiGroup.UsedBy = usedBy
+
+			apiGroup.Domains, err = placement.InstanceDomains(ctx, tx, *apiGroup)
+			if err != nil {
+				return err
+			}
+
 			apiGroups = append(apiGroups, apiGroup)
 			entitlementReportingMap[u] = apiGroup
 		}
@@ -238,6 +247,7 @@ func placementGroupsGet(d *Daemon, r *http.Request) response.Response {
 
 	for _, pg := range apiGroups {
 		pg.UsedBy = project.FilterUsedBy(r.Context(), s.Authorizer, pg.UsedBy)
+		pg.Domains = placementGroupFilterDomains(r.Context(), s.Authorizer, pg.Domains)
 	}
 
 	if len(withEntitlements) > 0 {
@@ -475,6 +485,11 @@ func placementGroupGet(d *Daemon, r *http.Request) response.Response {
 
 		placementGroup.UsedBy = usedBy
 
+		placementGroup.Domains, err = placement.InstanceDomains(ctx, tx, *placementGroup)
+		if err != nil {
+			return err
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -483,6 +498,7 @@ func placementGroupGet(d *Daemon, r *http.Request) response.Response {
 
 	etag := *placementGroup
 	placementGroup.UsedBy = project.FilterUsedBy(r.Context(), s.Authorizer, placementGroup.UsedBy)
+	placementGroup.Domains = placementGroupFilterDomains(r.Context(), s.Authorizer, placementGroup.Domains)
 	if len(withEntitlements) > 0 {
 		err = reportEntitlements(r.Context(), s.Authorizer, entity.TypePlacementGroup, withEntitlements, map[*api.URL]auth.EntitlementReporter{entity.PlacementGroupURL(projectName, placementGroupName): placementGroup})
 		if err != nil {
@@ -733,6 +749,18 @@ func placementGroupPost(d *Daemon, r *http.Request) response.Response {
 	return response.SyncResponseLocation(true, nil, entity.PlacementGroupURL(projectName, placementGroupName).String())
 }
 
+// placementGroupFilterDomains returns the entries of the given instance URL to failure domain map that the caller is allowed to view.
+func placementGroupFilterDomains(ctx context.Context, authorizer auth.Authorizer, domains map[string]string) map[string]string {
+	allowed := project.FilterUsedBy(ctx, authorizer, slices.Collect(maps.Keys(domains)))
+
+	filtered := make(map[string]string, len(allowed))
+	for _, u := range allowed {
+		filtered[u] = domains[u]
+	}
+
+	return filtered
+}
+
 // placementGroupValidateConfig validates the configuration keys/values for placement groups.
 func placementGroupValidateConfig(config map[string]string) error {
 	placementGroupConfigKeys := map[string]func(value string) error{
@@ -758,6 +786,38 @@ func placementGroupValidateConfig(config map[string]string) error {
 		//  required: "yes"
 		//  shortdesc: Enforcement level of the placement policy
 		"rigor": validate.IsOneOf(api.PlacementRigorStrict, api.PlacementRigorPermissive),
+
+		// lxdmeta:generate(entities=placement-group; group=placement-group; key=domain)
+		// Determines how cluster members are grouped into failure domains when applying the policy.
+		//
+		// Possible values are `member` (each cluster member is its own failure domain),
+		// `cluster_group` (members are grouped by their first non-default cluster group),
+		// `failure_domain` (members are grouped by their cluster failure domain),
+		// or a cluster member `user.*` configuration key (for example, `user.rack`).
+		// Cluster members without a value for the configuration key are treated as their own failure domain.
+		// ---
+		//  type: string
+		//  defaultdesc: `member`
+		//  shortdesc: Failure domain used by the placement policy
+		"domain": validate.Optional(func(value string) error {
+			if strings.HasPrefix(value, "user.") && value != "user." {
+				return nil
+			}
+
+			return validate.IsOneOf(api.PlacementDomainMember, api.PlacementDomainClusterGroup, api.PlacementDomainFailureDomain)(value)
+		}),
+
+		// lxdmeta:generate(entities=placement-group; group=placement-group; key=domain.max_instances)
+		// Maximum number of instances from the placement group in a single failure domain.
+		//
+		// With the `spread` policy, this defaults to one instance per failure domain.
+		// With the `compact` policy, instances are packed into a failure domain until this limit is
+		// reached before the next failure domain is used. There is no limit by default.
+		// ---
+		//  type: integer
+		//  defaultdesc: `1` for `spread`, unlimited for `compact`
+		//  shortdesc: Maximum number of instances per failure domain
+		"domain.max_instances": validate.Optional(validate.IsInRange(1, 2147483647)),
 	}
 
 	for k, v := range config {
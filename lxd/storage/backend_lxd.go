@@ -834,6 +834,10 @@ func (b *lxdBackend) CreateInstanceFromBackup(srcBackup backup.Info, srcData io.
 		}
 	}
 
+	if srcBackup.Parent != "" && !srcBackup.ChangedBlocks && !b.driver.Info().IncrementalBackups {
+		return nil, nil, fmt.Errorf("Storage driver %q doesn't support incremental backups", b.driver.Info().Name)
+	}
+
 	// Get the volume name on storage.
 	volStorageName := project.Instance(srcBackup.Project, srcBackup.Name)
 
@@ -897,8 +901,27 @@ func (b *lxdBackend) CreateInstanceFromBackup(srcBackup backup.Info, srcData io.
 
 	volCopy := drivers.NewVolumeCopy(vol, sourceSnapshots...)
 
+	// Changed block tracking backups are restored by unpacking the full backup at the start of the chain
+	// and then applying the changed blocks of each incremental backup on top of it.
+	driverBackup := srcBackup
+	driverData := srcData
+	if srcBackup.ChangedBlocks {
+		if len(srcBackup.Parents) == 0 {
+			return nil, nil, errors.New("Missing parent backups for changed blocks backup")
+		}
+
+		full := srcBackup.Parents[0]
+		driverBackup.Parent = ""
+		driverBackup.ChangedBlocks = false
+		driverBackup.Parents = nil
+		driverBackup.OptimizedStorage = full.Info.OptimizedStorage
+		driverBackup.OptimizedHeader = full.Info.OptimizedHeader
+		driverBackup.Backend = full.Info.Backend
+		driverData = full.Data
+	}
+
 	// Unpack the backup into the new storage volume(s).
-	volPostHook, revertHook, err := b.driver.CreateVolumeFromBackup(volCopy, srcBackup, srcData, op)
+	volPostHook, revertHook, err := b.driver.CreateVolumeFromBackup(volCopy, driverBackup, driverData, op)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -907,6 +930,19 @@ func (b *lxdBackend) CreateInstanceFromBackup(srcBackup backup.Info, srcData io.
 		importRevert.Add(revertHook)
 	}
 
+	if srcBackup.ChangedBlocks {
+		increments := make([]backup.ParentBackup, 0, len(srcBackup.Parents))
+		increments = append(increments, srcBackup.Parents[1:]...)
+		increments = append(increments, backup.ParentBackup{Info: &srcBackup, Data: srcData})
+
+		for _, increment := range increments {
+			err = b.applyChangedBlocksBackup(vol, increment, op)
+			if err != nil {
+				return nil, nil, fmt.Errorf("Failed applying changed blocks of backup %q: %w", increment.Info.Backup, err)
+			}
+		}
+	}
+
 	err = b.ensureInstanceSymlink(instanceType, srcBackup.Project, srcBackup.Name, vol.MountPath())
 	if err != nil {
 		return nil, nil, err
@@ -1112,6 +1148,128 @@ func (b *lxdBackend) CreateInstanceFromBackup(srcBackup backup.Info, srcData io.
 	return postHook, revertHook, nil
 }
 
+// applyChangedBlocksBackup writes the changed blocks held by an incremental changed block tracking backup to
+// the root disk of the VM volume.
+func (b *lxdBackend) applyChangedBlocksBackup(vol drivers.Volume, increment backup.ParentBackup, op *operations.Operation) error {
+	_, err := increment.Data.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	_, _, unpacker, err := shared.DetectCompressionFile(increment.Data)
+	if err != nil {
+		return err
+	}
+
+	_, err = increment.Data.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	backupsPath := b.state.BackupsStoragePath(increment.Info.Project)
+
+	// Extract the changed blocks image to a temporary file.
+	imgFile, err := os.CreateTemp(backupsPath, backup.WorkingDirPrefix+"_")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = imgFile.Close()
+		_ = os.Remove(imgFile.Name())
+	}()
+
+	tr, cancelFunc, err := archive.CompressedTarReader(b.state, context.Background(), increment.Data, unpacker, backupsPath)
+	if err != nil {
+		return err
+	}
+
+	defer cancelFunc()
+
+	found := false
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return fmt.Errorf("Error reading backup tarball: %w", err)
+		}
+
+		if hdr.Name != backup.ChangedBlocksFile {
+			continue
+		}
+
+		_, err = io.Copy(imgFile, tr)
+		if err != nil {
+			return fmt.Errorf("Error extracting changed blocks: %w", err)
+		}
+
+		found = true
+		break
+	}
+
+	cancelFunc()
+
+	if !found {
+		return fmt.Errorf("Could not find %q in backup", backup.ChangedBlocksFile)
+	}
+
+	format, size, err := qemuImageInfo(b.state.OS, imgFile.Name(), nil)
+	if err != nil {
+		return err
+	}
+
+	if format != "qcow2" {
+		return fmt.Errorf("Unexpected changed blocks image format %q", format)
+	}
+
+	cmd := []string{
+		"prlimit", "--cpu=2", "--as=1073741824",
+		"qemu-img", "map", "--output=json", "-f", "qcow2", imgFile.Name(),
+	}
+
+	out, err := apparmor.QemuImg(b.state.OS, cmd, imgFile.Name(), "", nil)
+	if err != nil {
+		return fmt.Errorf("qemu-img map: %w", err)
+	}
+
+	extents, err := backup.ParseChangedBlocksMap([]byte(out))
+	if err != nil {
+		return err
+	}
+
+	return vol.MountTask(func(_ string, op *operations.Operation) error {
+		// The disk may have been resized since the previous backup in the chain.
+		err := b.driver.SetVolumeQuota(vol, strconv.FormatInt(size, 10), true, op)
+		if err != nil {
+			return err
+		}
+
+		diskPath, err := b.driver.GetVolumeDiskPath(vol)
+		if err != nil {
+			return err
+		}
+
+		to, err := os.OpenFile(diskPath, os.O_WRONLY, 0)
+		if err != nil {
+			return fmt.Errorf("Error opening file for writing %q: %w", diskPath, err)
+		}
+
+		defer func() { _ = to.Close() }()
+
+		b.logger.Debug("Applying changed blocks", logger.Ctx{"backup": increment.Info.Backup, "target": diskPath, "extents": len(extents)})
+
+		err = backup.ApplyChangedBlocks(extents, imgFile, to)
+		if err != nil {
+			return err
+		}
+
+		return to.Close()
+	}, op)
+}
+
 // CreateInstanceFromCopy copies an instance volume and optionally its snapshots to new volume(s).
 func (b *lxdBackend) CreateInstanceFromCopy(inst instance.Instance, src instance.Instance, snapshots bool, allowInconsistent bool, op *operations.Operation) error {
 	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "src": src.Name(), "snapshots": snapshots})
@@ -2822,6 +2980,132 @@ func (b *lxdBackend) CreateInstanceFromConversion(inst instance.Instance, conn i
 	return nil
 }
 
+// CreateInstanceFromLiveMove creates an empty root volume of the same size as the one of a running VM on this
+// pool so that its root disk can be mirrored onto it, and copies the config filesystem of the VM into it.
+// The new volume is mounted and the path of its disk is returned along with a revert hook that removes it again
+// and restores the instance symlink to the source pool's volume.
+func (b *lxdBackend) CreateInstanceFromLiveMove(inst instance.Instance, op *operations.Operation) (string, revert.Hook, error) {
+	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
+	l.Debug("CreateInstanceFromLiveMove started")
+	defer l.Debug("CreateInstanceFromLiveMove finished")
+
+	err := b.isStatusReady()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if inst.Type() != instancetype.VM {
+		return "", nil, drivers.ErrNotSupported
+	}
+
+	srcPool, err := LoadByInstance(b.state, inst)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if srcPool.Name() == b.Name() {
+		return "", nil, errors.New("Instance is already on the target storage pool")
+	}
+
+	srcPoolBackend, ok := srcPool.(*lxdBackend)
+	if !ok {
+		return "", nil, errors.New("Source pool is not a lxdBackend")
+	}
+
+	volType, err := InstanceTypeToVolumeType(inst.Type())
+	if err != nil {
+		return "", nil, err
+	}
+
+	contentType := InstanceContentType(inst)
+
+	// The new root disk must be able to hold all the blocks of the source root disk.
+	srcVolumeSize, err := InstanceDiskBlockSize(srcPool, inst, op)
+	if err != nil {
+		return "", nil, fmt.Errorf("Failed getting source disk size: %w", err)
+	}
+
+	srcDiskPath, err := srcPoolBackend.getInstanceDisk(inst)
+	if err != nil {
+		return "", nil, err
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	// Creating the volume points the instance symlink to it, so restore it if the move fails.
+	volStorageName := project.Instance(inst.Project().Name, inst.Name())
+	srcMountPath := drivers.GetVolumeMountPath(srcPool.Name(), drivers.VolumeTypeVM, volStorageName)
+	revert.Add(func() { _ = b.ensureInstanceSymlink(inst.Type(), inst.Project().Name, inst.Name(), srcMountPath) })
+
+	// Generate the effective root device volume for instance.
+	vol := b.GetNewVolume(volType, contentType, volStorageName, map[string]string{})
+
+	err = b.applyInstanceRootDiskInitialValues(inst, vol.Config())
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Validate config and create database entry for new storage volume.
+	err = VolumeDBCreate(b, inst.Project().Name, inst.Name(), "", volType, false, vol.Config(), inst.CreationDate(), time.Time{}, contentType, true, true)
+	if err != nil {
+		return "", nil, err
+	}
+
+	revert.Add(func() { _ = VolumeDBDelete(b, inst.Project().Name, inst.Name(), volType) })
+
+	err = b.applyInstanceRootDiskOverrides(inst, &vol)
+	if err != nil {
+		return "", nil, err
+	}
+
+	l.Debug("Setting volume size to source disk size", logger.Ctx{"size": srcVolumeSize})
+	vol.SetConfigSize(strconv.FormatInt(srcVolumeSize, 10))
+
+	// The root disk contents are mirrored once the volume exists, so only create it empty.
+	err = b.driver.CreateVolume(vol, nil, op)
+	if err != nil {
+		return "", nil, err
+	}
+
+	revert.Add(func() { _ = b.DeleteInstance(inst, op) })
+
+	err = b.ensureInstanceSymlink(inst.Type(), inst.Project().Name, inst.Name(), vol.MountPath())
+	if err != nil {
+		return "", nil, err
+	}
+
+	_, err = b.MountInstance(inst, op)
+	if err != nil {
+		return "", nil, err
+	}
+
+	revert.Add(func() { _ = b.UnmountInstance(inst, op) })
+
+	// Copy the config filesystem of the VM, leaving out the root disk if it is stored as a file within it.
+	var rsyncArgs []string
+	if filepath.Dir(srcDiskPath) == srcMountPath {
+		rsyncArgs = append(rsyncArgs, "--exclude", filepath.Base(srcDiskPath))
+	}
+
+	_, err = rsync.LocalCopy(srcMountPath, vol.MountPath(), b.driver.Config()["rsync.bwlimit"], true, rsyncArgs...)
+
+	// The config filesystem of a running VM may change during the copy, so ignore vanished files.
+	status, _ := shared.ExitStatus(err)
+	if err != nil && status != 24 {
+		return "", nil, fmt.Errorf("Failed copying config filesystem: %w", err)
+	}
+
+	diskPath, err := b.getInstanceDisk(inst)
+	if err != nil {
+		return "", nil, err
+	}
+
+	cleanup := revert.Clone().Fail
+	revert.Success()
+	return diskPath, cleanup, nil
+}
+
 // RenameInstance renames the instance's root volume and any snapshot volumes.
 func (b *lxdBackend) RenameInstance(inst instance.Instance, newName string, op *operations.Operation) error {
 	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "newName": newName})
@@ -3045,6 +3329,43 @@ func (b *lxdBackend) DeleteInstance(inst instance.Instance, op *operations.Opera
 	return nil
 }
 
+// DeleteMovedInstance removes the root volume a VM was using before its root disk was moved live to another
+// pool. The volume's database record and the instance symlinks were already moved to the new pool.
+func (b *lxdBackend) DeleteMovedInstance(inst instance.Instance, op *operations.Operation) error {
+	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
+	l.Debug("DeleteMovedInstance started")
+	defer l.Debug("DeleteMovedInstance finished")
+
+	volType, err := InstanceTypeToVolumeType(inst.Type())
+	if err != nil {
+		return err
+	}
+
+	volStorageName := project.Instance(inst.Project().Name, inst.Name())
+	vol := b.GetVolume(volType, InstanceContentType(inst), volStorageName, nil)
+
+	volExists, err := b.driver.HasVolume(vol)
+	if err != nil {
+		return err
+	}
+
+	if !volExists {
+		return nil
+	}
+
+	_, err = b.driver.UnmountVolume(vol, false, op)
+	if err != nil {
+		return err
+	}
+
+	err = b.driver.DeleteVolume(vol, op)
+	if err != nil {
+		return fmt.Errorf("Error deleting storage volume: %w", err)
+	}
+
+	return nil
+}
+
 // UpdateInstance updates an instance volume's config.
 func (b *lxdBackend) UpdateInstance(inst instance.Instance, newDesc string, newConfig map[string]string, op *operations.Operation) error {
 	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "newDesc": newDesc, "newConfig": newConfig})
@@ -3374,11 +3695,21 @@ func (b *lxdBackend) CleanupInstancePaths(inst instance.Instance, _ *operations.
 }
 
 // BackupInstance creates an instance backup.
-func (b *lxdBackend) BackupInstance(inst instance.Instance, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, version uint32, op *operations.Operation) error {
-	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "optimized": optimized, "snapshots": snapshots})
+func (b *lxdBackend) BackupInstance(inst instance.Instance, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, base string, version uint32, op *operations.Operation) error {
+	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "optimized": optimized, "snapshots": snapshots, "base": base})
 	l.Debug("BackupInstance started")
 	defer l.Debug("BackupInstance finished")
 
+	if base != "" {
+		if !b.driver.Info().IncrementalBackups {
+			return fmt.Errorf("Storage driver %q doesn't support incremental backups", b.driver.Info().Name)
+		}
+
+		if !optimized || !snapshots {
+			return errors.New("Incremental backups must be optimized and include snapshots")
+		}
+	}
+
 	volType, err := InstanceTypeToVolumeType(inst.Type())
 	if err != nil {
 		return err
@@ -3437,9 +3768,13 @@ func (b *lxdBackend) BackupInstance(inst instance.Instance, tarWriter *instancew
 		}
 	}
 
+	if base != "" && !slices.Contains(snapNames, base) {
+		return api.StatusErrorf(http.StatusBadRequest, "Base snapshot %q of incremental backup doesn't exist", base)
+	}
+
 	volCopy := drivers.NewVolumeCopy(vol, sourceSnapshots...)
 
-	err = b.driver.BackupVolume(volCopy, inst.Project().Name, tarWriter, optimized, snapNames, op)
+	err = b.driver.BackupVolume(volCopy, inst.Project().Name, tarWriter, optimized, snapNames, base, op)
 	if err != nil {
 		return err
 	}
@@ -6282,6 +6617,61 @@ func allowRemoveSecurityShared(s *state.State, projectName string, volume *api.S
 	return nil
 }
 
+// growCustomVolumeOnline grows a custom block volume that is attached to running VMs and notifies the VMs of its
+// new size. Shrinking a block volume in use isn't allowed.
+func (b *lxdBackend) growCustomVolumeOnline(vol drivers.Volume, newSize string, runningVMDevices map[instance.VM][]string, op *operations.Operation) error {
+	newSizeBytes, err := units.ParseByteSizeString(newSize)
+	if err != nil {
+		return err
+	}
+
+	err = vol.MountTask(func(_ string, _ *operations.Operation) error {
+		diskPath, err := b.driver.GetVolumeDiskPath(vol)
+		if err != nil {
+			return err
+		}
+
+		oldSizeBytes, err := block.DiskSizeBytes(diskPath)
+		if err != nil {
+			return err
+		}
+
+		if newSizeBytes < oldSizeBytes {
+			return api.NewStatusError(http.StatusBadRequest, "Block volumes cannot be shrunk while in use by running instances")
+		}
+
+		// The driver's safety checks are skipped as growing doesn't affect the data seen by the guest.
+		err = b.driver.SetVolumeQuota(vol, newSize, true, op)
+		if err != nil {
+			return err
+		}
+
+		sizeBytes, err := block.DiskSizeBytes(diskPath)
+		if err != nil {
+			return err
+		}
+
+		// QEMU expects the size of the block node to be a multiple of the sector size.
+		sizeBytes -= sizeBytes % 512
+
+		for vm, devNames := range runningVMDevices {
+			for _, devName := range devNames {
+				err = vm.DiskDeviceResize(devName, sizeBytes)
+				if err != nil {
+					return fmt.Errorf("Failed resizing disk device %q of instance %q: %w", devName, vm.Name(), err)
+				}
+			}
+		}
+
+		return nil
+	}, op)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // UpdateCustomVolume applies the supplied config to the custom volume.
 func (b *lxdBackend) UpdateCustomVolume(projectName string, volName string, newDesc string, newConfig map[string]string, op *operations.Operation) error {
 	l := b.logger.AddContext(logger.Ctx{"project": projectName, "volName": volName, "newDesc": newDesc, "newConfig": newConfig})
@@ -6325,7 +6715,8 @@ func (b *lxdBackend) UpdateCustomVolume(projectName string, volName string, newD
 	}
 
 	var instances []instance.Instance
-	err = VolumeUsedByInstanceDevices(b.state, b.name, projectName, &curVol.StorageVolume, true, func(dbInst db.InstanceArgs, project api.Project, _ []string) error {
+	runningVMDevices := map[instance.VM][]string{}
+	err = VolumeUsedByInstanceDevices(b.state, b.name, projectName, &curVol.StorageVolume, true, func(dbInst db.InstanceArgs, project api.Project, usedByDevices []string) error {
 		inst, err := instance.Load(b.state, dbInst, project)
 		if err != nil {
 			return err
@@ -6337,6 +6728,11 @@ func (b *lxdBackend) UpdateCustomVolume(projectName string, volName string, newD
 			return api.NewStatusError(http.StatusBadRequest, "Cannot modify shifting with running instances using the volume")
 		}
 
+		vm, isVM := inst.(instance.VM)
+		if isVM && inst.IsRunning() {
+			runningVMDevices[vm] = usedByDevices
+		}
+
 		instances = append(instances, inst)
 		return nil
 	})
@@ -6371,7 +6767,19 @@ func (b *lxdBackend) UpdateCustomVolume(projectName string, volName string, newD
 		}
 
 		curVol := b.GetVolume(drivers.VolumeTypeCustom, contentType, volStorageName, curVol.Config)
-		if !userOnly {
+
+		// Grow block volumes attached to running VMs online.
+		newSize, ok := changedConfig["size"]
+		if ok && contentType == drivers.ContentTypeBlock && len(runningVMDevices) > 0 {
+			err = b.growCustomVolumeOnline(curVol, newSize, runningVMDevices, op)
+			if err != nil {
+				return err
+			}
+
+			delete(changedConfig, "size")
+		}
+
+		if !userOnly && len(changedConfig) > 0 {
 			err = b.driver.UpdateVolume(curVol, changedConfig)
 			if err != nil {
 				if errors.Is(err, drivers.ErrInUse) {
@@ -8420,7 +8828,7 @@ func (b *lxdBackend) BackupCustomVolume(projectName string, volName string, tarW
 
 	volCopy := drivers.NewVolumeCopy(vol, sourceSnapshots...)
 
-	err = b.driver.BackupVolume(volCopy, projectName, tarWriter, optimized, snapNames, op)
+	err = b.driver.BackupVolume(volCopy, projectName, tarWriter, optimized, snapNames, "", op)
 	if err != nil {
 		return err
 	}
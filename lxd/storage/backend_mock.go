@@ -160,6 +160,11 @@ func (b *mockBackend) CreateInstanceFromConversion(inst instance.Instance, conn
 	return nil
 }
 
+// CreateInstanceFromLiveMove ...
+func (b *mockBackend) CreateInstanceFromLiveMove(inst instance.Instance, op *operations.Operation) (string, revert.Hook, error) {
+	return "", nil, nil
+}
+
 // RenameInstance ...
 func (b *mockBackend) RenameInstance(inst instance.Instance, newName string, op *operations.Operation) error {
 	return nil
@@ -170,6 +175,11 @@ func (b *mockBackend) DeleteInstance(inst instance.Instance, op *operations.Oper
 	return nil
 }
 
+// DeleteMovedInstance ...
+func (b *mockBackend) DeleteMovedInstance(inst instance.Instance, op *operations.Operation) error {
+	return nil
+}
+
 // UpdateInstance ...
 func (b *mockBackend) UpdateInstance(inst instance.Instance, newDesc string, newConfig map[string]string, op *operations.Operation) error {
 	return nil
@@ -236,7 +246,7 @@ func (b *mockBackend) RefreshInstance(inst instance.Instance, src instance.Insta
 }
 
 // BackupInstance ...
-func (b *mockBackend) BackupInstance(inst instance.Instance, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, version uint32, op *operations.Operation) error {
+func (b *mockBackend) BackupInstance(inst instance.Instance, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, base string, version uint32, op *operations.Operation) error {
 	return nil
 }
 
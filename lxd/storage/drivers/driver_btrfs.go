@@ -98,6 +98,7 @@ func (d *btrfs) Info() Info {
 		OptimizedImages:              true,
 		OptimizedBackups:             true,
 		OptimizedBackupHeader:        true,
+		IncrementalBackups:           true,
 		PreservesInodes:              !d.state.OS.RunningInUserNS,
 		Remote:                       d.isRemote(),
 		VolumeTypes:                  []VolumeType{VolumeTypeBucket, VolumeTypeCustom, VolumeTypeImage, VolumeTypeContainer, VolumeTypeVM},
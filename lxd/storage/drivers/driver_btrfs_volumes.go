@@ -166,13 +166,25 @@ func (d *btrfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, sr
 		return nil, nil, errors.New("Cannot restore volume, already exists on target")
 	}
 
+	// Build the list of backups to unpack from, oldest first.
+	// For incremental backups this is the chain of parent backups followed by the backup itself.
+	chain := make([]backup.ParentBackup, 0, len(srcBackup.Parents)+1)
+	chain = append(chain, srcBackup.Parents...)
+	chain = append(chain, backup.ParentBackup{Info: &srcBackup, Data: srcData})
+
+	// Collect all snapshots included in the chain.
+	var snapshots []string
+	for _, b := range chain {
+		snapshots = append(snapshots, b.Info.IncludedSnapshots()...)
+	}
+
 	revert := revert.New()
 	defer revert.Fail()
 
 	// Define a revert function that will be used both to revert if an error occurs inside this
 	// function but also return it for use from the calling functions if no error internally.
 	revertHook := func() {
-		for _, snapName := range srcBackup.Snapshots {
+		for _, snapName := range snapshots {
 			fullSnapshotName := GetSnapshotVolumeName(vol.name, snapName)
 			snapVol := NewVolume(d, d.name, vol.volType, vol.contentType, fullSnapshotName, vol.config, vol.poolConfig)
 			_ = d.DeleteVolumeSnapshot(snapVol, op)
@@ -184,43 +196,48 @@ func (d *btrfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, sr
 	// Only execute the revert function if we have had an error internally.
 	revert.Add(revertHook)
 
-	// Find the compression algorithm used for backup source data.
-	_, err = srcData.Seek(0, io.SeekStart)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	_, _, unpacker, err := shared.DetectCompressionFile(srcData)
-	if err != nil {
-		return nil, nil, err
-	}
+	// loadHeader finds the compression algorithm and loads the optimized header of a backup tarball file.
+	loadHeader := func(b backup.Info, r io.ReadSeeker) ([]string, *BTRFSMetaDataHeader, error) {
+		// Find the compression algorithm used for backup source data.
+		_, err := r.Seek(0, io.SeekStart)
+		if err != nil {
+			return nil, nil, err
+		}
 
-	// Load optimized backup header file if specified.
-	var optimizedHeader *BTRFSMetaDataHeader
-	if *srcBackup.OptimizedHeader {
-		optimizedHeader, err = d.loadOptimizedBackupHeader(srcData, GetVolumeMountPath(d.name, vol.volType, ""))
+		_, _, unpacker, err := shared.DetectCompressionFile(r)
 		if err != nil {
 			return nil, nil, err
 		}
-	}
 
-	// Populate optimized header with pseudo data for unified handling when backup doesn't contain the
-	// optimized header file. This approach can only be used to restore root subvolumes (not sub-subvolumes).
-	if optimizedHeader == nil {
-		optimizedHeader = &BTRFSMetaDataHeader{}
-		for _, snapName := range srcBackup.Snapshots {
+		// Load optimized backup header file if specified.
+		var optimizedHeader *BTRFSMetaDataHeader
+		if b.OptimizedHeader != nil && *b.OptimizedHeader {
+			optimizedHeader, err = d.loadOptimizedBackupHeader(r, GetVolumeMountPath(d.name, vol.volType, ""))
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		// Populate optimized header with pseudo data for unified handling when backup doesn't contain the
+		// optimized header file. This approach can only be used to restore root subvolumes (not sub-subvolumes).
+		if optimizedHeader == nil {
+			optimizedHeader = &BTRFSMetaDataHeader{}
+			for _, snapName := range b.Snapshots {
+				optimizedHeader.Subvolumes = append(optimizedHeader.Subvolumes, BTRFSSubVolume{
+					Snapshot: snapName,
+					Path:     string(filepath.Separator),
+					Readonly: true, // Snapshots are made readonly.
+				})
+			}
+
 			optimizedHeader.Subvolumes = append(optimizedHeader.Subvolumes, BTRFSSubVolume{
-				Snapshot: snapName,
+				Snapshot: "",
 				Path:     string(filepath.Separator),
-				Readonly: true, // Snapshots are made readonly.
+				Readonly: false,
 			})
 		}
 
-		optimizedHeader.Subvolumes = append(optimizedHeader.Subvolumes, BTRFSSubVolume{
-			Snapshot: "",
-			Path:     string(filepath.Separator),
-			Readonly: false,
-		})
+		return unpacker, optimizedHeader, nil
 	}
 
 	// Create a temporary directory to unpack the backup into.
@@ -277,7 +294,7 @@ func (d *btrfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, sr
 	var copyOps []btrfsCopyOp
 
 	// unpackVolume unpacks all subvolumes in a LXD volume from a backup tarball file.
-	unpackVolume := func(v Volume, srcFilePrefix string) error {
+	unpackVolume := func(v Volume, optimizedHeader *BTRFSMetaDataHeader, srcData io.ReadSeeker, unpacker []string, srcFilePrefix string) error {
 		_, snapName, _ := api.GetParentAndSnapshotName(v.name)
 
 		for _, subVol := range optimizedHeader.Subvolumes {
@@ -320,15 +337,24 @@ func (d *btrfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, sr
 		return nil
 	}
 
-	if len(srcBackup.Snapshots) > 0 {
+	if len(snapshots) > 0 {
 		// Create new snapshots directory.
 		err := createParentSnapshotDirIfMissing(d.name, vol.volType, vol.name)
 		if err != nil {
 			return nil, nil, err
 		}
+	}
+
+	var unpacker []string
+	var optimizedHeader *BTRFSMetaDataHeader
+	for _, b := range chain {
+		unpacker, optimizedHeader, err = loadHeader(*b.Info, b.Data)
+		if err != nil {
+			return nil, nil, err
+		}
 
 		// Restore backup snapshots from oldest to newest.
-		for _, snapName := range srcBackup.Snapshots {
+		for _, snapName := range b.Info.IncludedSnapshots() {
 			// Defend against path traversal attacks.
 			err := instancetype.ValidSnapName(snapName)
 			if err != nil {
@@ -350,14 +376,14 @@ func (d *btrfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, sr
 			}
 
 			srcFilePrefix = filepath.Join(snapDir, srcFilePrefix)
-			err = unpackVolume(snapVol, srcFilePrefix)
+			err = unpackVolume(snapVol, optimizedHeader, b.Data, unpacker, srcFilePrefix)
 			if err != nil {
 				return nil, nil, err
 			}
 		}
 	}
 
-	// Extract main volume.
+	// Extract main volume from the last backup of the chain.
 	srcFilePrefix := "container"
 	switch vol.volType {
 	case VolumeTypeVM:
@@ -371,7 +397,7 @@ func (d *btrfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, sr
 		srcFilePrefix = "volume"
 	}
 
-	err = unpackVolume(vol.Volume, srcFilePrefix)
+	err = unpackVolume(vol.Volume, optimizedHeader, srcData, unpacker, srcFilePrefix)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -392,6 +418,19 @@ func (d *btrfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, sr
 		}
 	}
 
+	// Remove any snapshots deleted since a parent backup was taken.
+	for _, snapName := range snapshots {
+		if slices.Contains(srcBackup.Snapshots, snapName) {
+			continue
+		}
+
+		snapVol, _ := vol.NewSnapshot(snapName)
+		err = d.DeleteVolumeSnapshot(snapVol, op)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	// Restore readonly property on subvolumes that need it.
 	for _, subVol := range optimizedHeader.Subvolumes {
 		if !subVol.Readonly {
@@ -1413,7 +1452,7 @@ func (d *btrfs) migrateVolumeOptimized(vol Volume, conn io.ReadWriteCloser, volS
 
 // BackupVolume copies a volume (and optionally its snapshots) to a specified target path.
 // This driver does not support optimized backups.
-func (d *btrfs) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, op *operations.Operation) error {
+func (d *btrfs) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, base string, op *operations.Operation) error {
 	// Handle the non-optimized tarballs through the generic packer.
 	if !optimized {
 		// Because the generic backup method will not take a consistent backup if files are being modified
@@ -1584,9 +1623,21 @@ func (d *btrfs) BackupVolume(vol VolumeCopy, projectName string, tarWriter *inst
 		return nil
 	}
 
-	// Backup snapshots if populated.
+	// For incremental backups, skip the snapshots already included in the parent backup.
 	lastVolPath := "" // Used as parent for differential exports.
-	for _, snapName := range snapshots {
+	skip := 0
+	if base != "" {
+		skip = slices.Index(snapshots, base) + 1
+		if skip < 1 {
+			return fmt.Errorf("Base snapshot %q not found", base)
+		}
+
+		baseVol, _ := vol.NewSnapshot(base)
+		lastVolPath = baseVol.MountPath()
+	}
+
+	// Backup snapshots if populated.
+	for _, snapName := range snapshots[skip:] {
 		snapVol, _ := vol.NewSnapshot(snapName)
 
 		// Make a binary btrfs backup.
@@ -24,6 +24,7 @@ import (
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/logger"
 	"github.com/canonical/lxd/shared/revert"
+	"github.com/canonical/lxd/shared/validate"
 )
 
 type common struct {
@@ -104,6 +105,78 @@ func (d *common) validatePool(config map[string]string, driverRules map[string]f
 	return nil
 }
 
+// replicationPoolRules returns the pool config rules for drivers supporting instance replication.
+func (d *common) replicationPoolRules() map[string]func(value string) error {
+	return map[string]func(value string) error{
+		// lxdmeta:generate(entities=storage-dir,storage-lvm,storage-zfs; group=pool-conf; key=replication.target)
+		// Specify either the name of another cluster member or the `https://` URL of a remote LXD server.
+		// Instances whose root disk is on this pool are replicated to the target according to `replication.schedule`.
+		// ---
+		//  type: string
+		//  defaultdesc: empty
+		//  shortdesc: Target for instance replication
+		//  scope: local
+		"replication.target": validate.Optional(func(value string) error {
+			if strings.Contains(value, "://") {
+				if !strings.HasPrefix(value, "https://") {
+					return errors.New("Remote replication targets must use https://")
+				}
+
+				return validate.IsRequestURL(value)
+			}
+
+			return validate.IsHostname(value)
+		}),
+		// lxdmeta:generate(entities=storage-dir,storage-lvm,storage-zfs; group=pool-conf; key=replication.target.certificate)
+		// Only used when `replication.target` is the URL of a remote LXD server.
+		// The local server certificate must be trusted by the remote server.
+		// ---
+		//  type: string
+		//  defaultdesc: empty
+		//  shortdesc: PEM encoded server certificate of the replication target
+		//  scope: local
+		"replication.target.certificate": validate.Optional(validate.IsX509Certificate),
+		// lxdmeta:generate(entities=storage-dir,storage-lvm,storage-zfs; group=pool-conf; key=replication.project)
+		// Required when `replication.target` is a cluster member, as replicas can't share the project of their source instance.
+		// ---
+		//  type: string
+		//  defaultdesc: project of the source instance
+		//  shortdesc: Project in which replicas are created on the target
+		//  scope: global
+		"replication.project": validate.IsAny,
+		// lxdmeta:generate(entities=storage-dir,storage-lvm,storage-zfs; group=pool-conf; key=replication.schedule)
+		// Specify either a cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or leave empty to disable replication.
+		// ---
+		//  type: string
+		//  defaultdesc: empty
+		//  shortdesc: Schedule for instance replication
+		//  scope: global
+		"replication.schedule": validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly"})),
+	}
+}
+
+// validateReplicationConfig checks the combination of the replication pool config keys.
+func (d *common) validateReplicationConfig(config map[string]string) error {
+	target := config["replication.target"]
+	if target == "" {
+		return nil
+	}
+
+	if strings.HasPrefix(target, "https://") {
+		if config["replication.target.certificate"] == "" {
+			return errors.New("The key replication.target.certificate is required when replicating to a remote server")
+		}
+
+		return nil
+	}
+
+	if config["replication.project"] == "" {
+		return errors.New("The key replication.project is required when replicating to a cluster member")
+	}
+
+	return nil
+}
+
 // fillVolumeConfig populates volume config with defaults from pool.
 // excludeKeys allow exclude some keys from copying to volume config.
 // Sometimes that can be useful when copying is dependant from specific conditions
@@ -459,7 +532,7 @@ func (d *common) MigrateVolume(vol VolumeCopy, conn io.ReadWriteCloser, volSrcAr
 }
 
 // BackupVolume creates an exported version of a volume.
-func (d *common) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, op *operations.Operation) error {
+func (d *common) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, _ string, op *operations.Operation) error {
 	return ErrNotSupported
 }
 
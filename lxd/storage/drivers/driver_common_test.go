@@ -0,0 +1,86 @@
+package drivers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test replicationPoolRules.
+func Test_common_replicationPoolRules(t *testing.T) {
+	d := &common{}
+	rules := d.replicationPoolRules()
+
+	tests := []struct {
+		key     string
+		value   string
+		isValid bool
+	}{
+		{key: "replication.target", value: "", isValid: true},
+		{key: "replication.target", value: "member02", isValid: true},
+		{key: "replication.target", value: "https://lxd02.example.com:8443", isValid: true},
+		{key: "replication.target", value: "http://lxd02.example.com:8443", isValid: false},
+		{key: "replication.target", value: "member 02", isValid: false},
+		{key: "replication.target.certificate", value: "", isValid: true},
+		{key: "replication.target.certificate", value: "not a certificate", isValid: false},
+		{key: "replication.schedule", value: "", isValid: true},
+		{key: "replication.schedule", value: "@daily", isValid: true},
+		{key: "replication.schedule", value: "0 */6 * * *", isValid: true},
+		{key: "replication.schedule", value: "@sometimes", isValid: false},
+	}
+
+	for _, test := range tests {
+		err := rules[test.key](test.value)
+		if test.isValid {
+			assert.NoError(t, err, "%s=%q", test.key, test.value)
+		} else {
+			assert.Error(t, err, "%s=%q", test.key, test.value)
+		}
+	}
+}
+
+// Test validateReplicationConfig.
+func Test_common_validateReplicationConfig(t *testing.T) {
+	d := &common{}
+
+	tests := []struct {
+		name    string
+		config  map[string]string
+		isValid bool
+	}{
+		{
+			name:    "Replication disabled",
+			config:  map[string]string{},
+			isValid: true,
+		},
+		{
+			name:    "Cluster member with project",
+			config:  map[string]string{"replication.target": "member02", "replication.project": "replicas"},
+			isValid: true,
+		},
+		{
+			name:    "Cluster member without project",
+			config:  map[string]string{"replication.target": "member02"},
+			isValid: false,
+		},
+		{
+			name:    "Remote server with certificate",
+			config:  map[string]string{"replication.target": "https://lxd02.example.com:8443", "replication.target.certificate": "cert"},
+			isValid: true,
+		},
+		{
+			name:    "Remote server without certificate",
+			config:  map[string]string{"replication.target": "https://lxd02.example.com:8443", "replication.project": "replicas"},
+			isValid: false,
+		},
+	}
+
+	for _, test := range tests {
+		err := d.validateReplicationConfig(test.config)
+		if test.isValid {
+			assert.NoError(t, err, test.name)
+		} else {
+			assert.Error(t, err, test.name)
+		}
+	}
+}
@@ -144,7 +144,12 @@ func (d *dir) Delete(op *operations.Operation) error {
 
 // Validate checks that all provide keys are supported and that no conflicting or missing configuration is present.
 func (d *dir) Validate(config map[string]string) error {
-	return d.validatePool(config, nil, nil)
+	err := d.validatePool(config, d.replicationPoolRules(), nil)
+	if err != nil {
+		return err
+	}
+
+	return d.validateReplicationConfig(config)
 }
 
 // Update applies any driver changes required from a configuration change.
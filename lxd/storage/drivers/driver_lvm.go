@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
 	"os"
 	"os/exec"
@@ -583,11 +584,18 @@ func (d *lvm) Validate(config map[string]string) error {
 		"lvm.vg.force_reuse": validate.Optional(validate.IsBool),
 	}
 
+	maps.Copy(rules, d.replicationPoolRules())
+
 	err := d.validatePool(config, rules, d.commonVolumeRules())
 	if err != nil {
 		return err
 	}
 
+	err = d.validateReplicationConfig(config)
+	if err != nil {
+		return err
+	}
+
 	if shared.IsFalse(config["lvm.use_thinpool"]) {
 		if config["lvm.thinpool_name"] != "" {
 			return errors.New("The key lvm.use_thinpool cannot be set to false when lvm.thinpool_name is set")
@@ -778,7 +778,7 @@ func (d *powerflex) MigrateVolume(vol VolumeCopy, conn io.ReadWriteCloser, volSr
 }
 
 // BackupVolume creates an exported version of a volume.
-func (d *powerflex) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, op *operations.Operation) error {
+func (d *powerflex) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, _ string, op *operations.Operation) error {
 	return genericVFSBackupVolume(d, vol, tarWriter, snapshots, op)
 }
 
@@ -35,6 +35,9 @@ type Info struct {
 	// Whether driver generates an optimised backup header file in backup.
 	OptimizedBackupHeader bool
 
+	// Whether driver supports incremental optimized backups based on a parent backup.
+	IncrementalBackups bool
+
 	// Whether driver preserves inodes when volumes are moved hosts.
 	PreservesInodes bool
 
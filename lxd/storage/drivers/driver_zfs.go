@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -129,6 +130,7 @@ func (d *zfs) Info() Info {
 		DefaultVMBlockFilesystemSize: d.defaultVMBlockFilesystemSize(),
 		OptimizedImages:              true,
 		OptimizedBackups:             true,
+		IncrementalBackups:           true,
 		PreservesInodes:              true,
 		Remote:                       d.isRemote(),
 		VolumeTypes:                  []VolumeType{VolumeTypeBucket, VolumeTypeCustom, VolumeTypeImage, VolumeTypeContainer, VolumeTypeVM},
@@ -502,7 +504,14 @@ func (d *zfs) Validate(config map[string]string) error {
 		"zfs.export": validate.Optional(validate.IsBool),
 	}
 
-	return d.validatePool(config, rules, d.commonVolumeRules())
+	maps.Copy(rules, d.replicationPoolRules())
+
+	err := d.validatePool(config, rules, d.commonVolumeRules())
+	if err != nil {
+		return err
+	}
+
+	return d.validateReplicationConfig(config)
 }
 
 // Update applies any driver changes required from a configuration change.
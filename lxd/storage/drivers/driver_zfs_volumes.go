@@ -352,13 +352,25 @@ func (d *zfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, srcD
 		return nil, nil, errors.New("Cannot restore volume, already exists on target")
 	}
 
+	// Build the list of backups to unpack from, oldest first.
+	// For incremental backups this is the chain of parent backups followed by the backup itself.
+	chain := make([]backup.ParentBackup, 0, len(srcBackup.Parents)+1)
+	chain = append(chain, srcBackup.Parents...)
+	chain = append(chain, backup.ParentBackup{Info: &srcBackup, Data: srcData})
+
+	// Collect all snapshots included in the chain.
+	var snapshots []string
+	for _, b := range chain {
+		snapshots = append(snapshots, b.Info.IncludedSnapshots()...)
+	}
+
 	revert := revert.New()
 	defer revert.Fail()
 
 	// Define a revert function that will be used both to revert if an error occurs inside this
 	// function but also return it for use from the calling functions if no error internally.
 	revertHook := func() {
-		for _, snapName := range srcBackup.Snapshots {
+		for _, snapName := range snapshots {
 			fullSnapshotName := GetSnapshotVolumeName(vol.name, snapName)
 			snapVol := NewVolume(d, d.name, vol.volType, vol.contentType, fullSnapshotName, vol.config, vol.poolConfig)
 			_ = d.DeleteVolumeSnapshot(snapVol, op)
@@ -424,18 +436,7 @@ func (d *zfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, srcD
 	vols = append(vols, vol.Volume)
 
 	for _, v := range vols {
-		// Find the compression algorithm used for backup source data.
-		_, err := srcData.Seek(0, io.SeekStart)
-		if err != nil {
-			return nil, nil, err
-		}
-
-		_, _, unpacker, err := shared.DetectCompressionFile(srcData)
-		if err != nil {
-			return nil, nil, err
-		}
-
-		if len(srcBackup.Snapshots) > 0 {
+		if len(snapshots) > 0 {
 			// Create new snapshots directory.
 			err := createParentSnapshotDirIfMissing(d.name, v.volType, v.name)
 			if err != nil {
@@ -443,32 +444,46 @@ func (d *zfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, srcD
 			}
 		}
 
-		// Restore backups from oldest to newest.
-		for _, snapName := range srcBackup.Snapshots {
-			// Defend against path traversal attacks.
-			err := instancetype.ValidSnapName(snapName)
+		var unpacker []string
+		for _, b := range chain {
+			// Find the compression algorithm used for backup source data.
+			_, err := b.Data.Seek(0, io.SeekStart)
 			if err != nil {
-				return nil, nil, fmt.Errorf("Invalid snapshot name %q: %w", snapName, err)
+				return nil, nil, err
 			}
 
-			prefix := "snapshots"
-			fileName := snapName + ".bin"
-			switch v.volType {
-			case VolumeTypeVM:
-				prefix = "virtual-machine-snapshots"
-				if v.contentType == ContentTypeFS {
-					fileName = snapName + "-config.bin"
+			_, _, unpacker, err = shared.DetectCompressionFile(b.Data)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			// Restore backups from oldest to newest.
+			for _, snapName := range b.Info.IncludedSnapshots() {
+				// Defend against path traversal attacks.
+				err := instancetype.ValidSnapName(snapName)
+				if err != nil {
+					return nil, nil, fmt.Errorf("Invalid snapshot name %q: %w", snapName, err)
 				}
 
-			case VolumeTypeCustom:
-				prefix = "volume-snapshots"
-			}
+				prefix := "snapshots"
+				fileName := snapName + ".bin"
+				switch v.volType {
+				case VolumeTypeVM:
+					prefix = "virtual-machine-snapshots"
+					if v.contentType == ContentTypeFS {
+						fileName = snapName + "-config.bin"
+					}
 
-			srcFile := "backup/" + prefix + "/" + fileName
-			dstSnapshot := d.dataset(v, false) + "@snapshot-" + snapName
-			err = unpackVolume(v, srcData, unpacker, srcFile, dstSnapshot)
-			if err != nil {
-				return nil, nil, err
+				case VolumeTypeCustom:
+					prefix = "volume-snapshots"
+				}
+
+				srcFile := "backup/" + prefix + "/" + fileName
+				dstSnapshot := d.dataset(v, false) + "@snapshot-" + snapName
+				err = unpackVolume(v, b.Data, unpacker, srcFile, dstSnapshot)
+				if err != nil {
+					return nil, nil, err
+				}
 			}
 		}
 
@@ -486,6 +501,7 @@ func (d *zfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, srcD
 			fileName = "volume.bin"
 		}
 
+		// The main volume always comes from the last backup of the chain.
 		err = unpackVolume(v, srcData, unpacker, "backup/"+fileName, d.dataset(v, false))
 		if err != nil {
 			return nil, nil, err
@@ -497,9 +513,10 @@ func (d *zfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, srcD
 			return nil, nil, err
 		}
 
-		// Remove only the internal snapshots.
+		// Remove the internal snapshots and any snapshots deleted since a parent backup was taken.
 		for _, entry := range entries {
-			if strings.Contains(entry, "@snapshot-") {
+			_, snapName, found := strings.Cut(entry, "@snapshot-")
+			if found && slices.Contains(srcBackup.Snapshots, snapName) {
 				continue
 			}
 
@@ -2772,7 +2789,7 @@ func (d *zfs) readonlySnapshot(vol Volume) (string, revert.Hook, error) {
 }
 
 // BackupVolume creates an exported version of a volume.
-func (d *zfs) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, op *operations.Operation) error {
+func (d *zfs) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, base string, op *operations.Operation) error {
 	// Handle the non-optimized tarballs through the generic packer.
 	if !optimized {
 		// Because the generic backup method will not take a consistent backup if files are being modified
@@ -2807,7 +2824,7 @@ func (d *zfs) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instan
 	// Backup VM config volumes first.
 	if vol.IsVMBlock() {
 		fsVol := NewVolumeCopy(vol.NewVMBlockFilesystemVolume())
-		err := d.BackupVolume(fsVol, projectName, tarWriter, optimized, snapshots, op)
+		err := d.BackupVolume(fsVol, projectName, tarWriter, optimized, snapshots, base, op)
 		if err != nil {
 			return err
 		}
@@ -2868,7 +2885,23 @@ func (d *zfs) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instan
 	// Handle snapshots.
 	finalParent := ""
 	if len(snapshots) > 0 {
+		// For incremental backups, skip the snapshots already included in the parent backup.
+		skip := 0
+		if base != "" {
+			skip = slices.Index(snapshots, base) + 1
+			if skip < 1 {
+				return fmt.Errorf("Base snapshot %q not found", base)
+			}
+
+			baseSnapshot, _ := vol.NewSnapshot(base)
+			finalParent = d.dataset(baseSnapshot, false)
+		}
+
 		for i, snapName := range snapshots {
+			if i < skip {
+				continue
+			}
+
 			snapshot, _ := vol.NewSnapshot(snapName)
 
 			// Figure out parent and current subvolumes.
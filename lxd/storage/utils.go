@@ -679,6 +679,60 @@ func validateVolumeCommonRules(vol drivers.Volume) map[string]func(string) error
 		rules["volatile.rootfs.size"] = validate.Optional(validate.IsInt64)
 	}
 
+	// Scheduled backups are only supported for custom volumes.
+	if vol.Type() == drivers.VolumeTypeCustom {
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs,storage-powerflex,storage-pure,storage-alletra; group=volume-conf; key=backups.schedule)
+		// Specify either a cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or leave empty to disable automatic backups (the default).
+		// ---
+		//  type: string
+		//  condition: custom volume
+		//  shortdesc: Schedule for automatic volume backups
+		//  scope: global
+		rules["backups.schedule"] = validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly"}))
+
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs,storage-powerflex,storage-pure,storage-alletra; group=volume-conf; key=backups.expiry)
+		// Specify an expression like `1M 2H 3d 4w 5m 6y`.
+		// ---
+		//  type: string
+		//  condition: custom volume
+		//  shortdesc: When scheduled backups are to be deleted
+		//  scope: global
+		rules["backups.expiry"] = func(value string) error {
+			// Validate expression
+			_, err := shared.GetExpiry(time.Time{}, value)
+			return err
+		}
+
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs,storage-powerflex,storage-pure,storage-alletra; group=volume-conf; key=backups.retain)
+		// When a scheduled backup is created, the oldest scheduled backups are deleted so that at most this number of them is kept.
+		// ---
+		//  type: integer
+		//  condition: custom volume
+		//  defaultdesc: unlimited
+		//  shortdesc: Maximum number of scheduled backups to keep
+		//  scope: global
+		rules["backups.retain"] = validate.Optional(validate.IsInRange(1, 2147483647))
+
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs,storage-powerflex,storage-pure,storage-alletra; group=volume-conf; key=backups.optimized)
+		// Only supported by storage pool drivers that support optimized backups.
+		// ---
+		//  type: bool
+		//  condition: custom volume
+		//  defaultdesc: `false`
+		//  shortdesc: Whether scheduled backups use the storage driver's optimized format
+		//  scope: global
+		rules["backups.optimized"] = validate.Optional(validate.IsBool)
+
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs,storage-powerflex,storage-pure,storage-alletra; group=volume-conf; key=volatile.replica.source)
+		// This key is set on volumes created by storage pool replication and is cleared when the volume is promoted.
+		// ---
+		//  type: string
+		//  condition: custom volume
+		//  shortdesc: Source of a replicated volume that hasn't been promoted yet
+		//  scope: global
+		rules["volatile.replica.source"] = validate.IsAny
+	}
+
 	return rules
 }
 
@@ -0,0 +1,600 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	lxd "github.com/canonical/lxd/client"
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/metrics"
+	"github.com/canonical/lxd/lxd/project"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	storagePools "github.com/canonical/lxd/lxd/storage"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/units"
+)
+
+var storagePoolUsageCmd = APIEndpoint{
+	Path:        "storage-pools/{poolName}/usage",
+	MetricsType: entity.TypeStoragePool,
+
+	Get: APIEndpointAction{Handler: storagePoolUsageGet, AccessHandler: allowAuthenticated},
+}
+
+// swagger:operation GET /1.0/storage-pools/{poolName}/usage storage storage_pool_usage_get
+//
+//	Get the storage pool usage
+//
+//	Gets the actual and provisioned disk usage of the instance and custom volumes on the storage pool,
+//	per volume, per snapshot and per project. Volumes from all cluster members are included.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: all-projects
+//	    description: Retrieve the usage of volumes from all projects
+//	    type: boolean
+//	    example: true
+//	responses:
+//	  "200":
+//	    description: Storage pool usage
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/StoragePoolUsage"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func storagePoolUsageGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	poolName, err := url.PathUnescape(mux.Vars(r)["poolName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	requestProjectName, allProjects, err := request.ProjectParams(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	requestor, err := request.GetRequestor(r.Context())
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	pool, err := storagePools.LoadByName(s, poolName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	projects := map[string]api.Project{}
+	var customVolProjectName string
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbProjects, err := dbCluster.GetProjects(ctx, tx.Tx())
+		if err != nil {
+			return fmt.Errorf("Failed loading projects: %w", err)
+		}
+
+		for _, dbProject := range dbProjects {
+			p, err := dbProject.ToAPI(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			projects[p.Name] = *p
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	instanceProjectName := ""
+	if !allProjects {
+		p, ok := projects[requestProjectName]
+		if !ok {
+			return response.NotFound(fmt.Errorf("Project %q not found", requestProjectName))
+		}
+
+		instanceProjectName = requestProjectName
+
+		// The project name used for custom volumes varies based on whether the
+		// project has the features.storage.volumes feature enabled.
+		customVolProjectName = project.StorageVolumeProjectFromRecord(&p, dbCluster.StoragePoolVolumeTypeCustom)
+	}
+
+	// Volumes on remote storage pools are only reported by the member handling the request.
+	clusterNotification := requestor.IsClusterNotification()
+
+	volumes, err := storagePoolUsageVolumes(r.Context(), s, pool, instanceProjectName, customVolProjectName, !clusterNotification)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Collect the usage of the volumes located on other cluster members.
+	if !clusterNotification {
+		notifier, err := cluster.NewNotifier(s, s.Endpoints.NetworkCert(), s.ServerCert(), cluster.NotifyAlive)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		var volumesLock sync.Mutex
+
+		err = notifier(func(member db.NodeInfo, client lxd.InstanceServer) error {
+			var memberUsage *api.StoragePoolUsage
+			var err error
+
+			if allProjects {
+				memberUsage, err = client.GetStoragePoolUsageAllProjects(poolName)
+			} else {
+				memberUsage, err = client.UseProject(requestProjectName).GetStoragePoolUsage(poolName)
+			}
+
+			if err != nil {
+				return fmt.Errorf("Failed getting storage pool usage from cluster member %q: %w", member.Name, err)
+			}
+
+			volumesLock.Lock()
+			volumes = append(volumes, memberUsage.Volumes...)
+			volumesLock.Unlock()
+
+			return nil
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	userHasPermission, err := s.Authorizer.GetPermissionChecker(r.Context(), auth.EntitlementCanView, entity.TypeStorageVolume)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// The auth.PermissionChecker expects the url to contain the request project (not the effective project).
+	authCheckProject := func(volProject string) string {
+		if !allProjects {
+			return requestProjectName
+		}
+
+		return volProject
+	}
+
+	usage := api.StoragePoolUsage{
+		Volumes: make([]api.StoragePoolVolumeUsage, 0, len(volumes)),
+	}
+
+	for _, vol := range volumes {
+		if !userHasPermission(entity.StorageVolumeURL(authCheckProject(vol.Project), vol.Location, poolName, vol.Type, vol.Name)) {
+			continue
+		}
+
+		usage.Volumes = append(usage.Volumes, vol)
+	}
+
+	sort.SliceStable(usage.Volumes, func(i, j int) bool {
+		volA := usage.Volumes[i]
+		volB := usage.Volumes[j]
+
+		if volA.Project != volB.Project {
+			return volA.Project < volB.Project
+		}
+
+		if volA.Type != volB.Type {
+			return volA.Type < volB.Type
+		}
+
+		if volA.Name != volB.Name {
+			return volA.Name < volB.Name
+		}
+
+		return volA.Location < volB.Location
+	})
+
+	// Always report the requested projects, even without volumes on the pool.
+	reportProjects := map[string]api.Project{}
+	if !allProjects {
+		reportProjects[requestProjectName] = projects[requestProjectName]
+		reportProjects[customVolProjectName] = projects[customVolProjectName]
+	}
+
+	for _, vol := range usage.Volumes {
+		reportProjects[vol.Project] = projects[vol.Project]
+	}
+
+	usage.Projects = storagePoolUsageProjects(poolName, usage.Volumes, reportProjects)
+
+	// The disk limits come from the project config, so they are only reported to those allowed to view it.
+	userCanViewProject, err := s.Authorizer.GetPermissionChecker(r.Context(), auth.EntitlementCanView, entity.TypeProject)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	for i, projectUsage := range usage.Projects {
+		if !userCanViewProject(entity.ProjectURL(projectUsage.Name)) {
+			usage.Projects[i].LimitBytes = nil
+		}
+	}
+
+	return response.SyncResponse(true, usage)
+}
+
+// storagePoolUsageVolumes returns the disk usage of the instance and custom volumes of the storage pool that are
+// located on the local member. An empty project name includes the volumes of all projects.
+// Volumes on remote storage pools are included if includeRemote is true.
+func storagePoolUsageVolumes(ctx context.Context, s *state.State, pool storagePools.Pool, instanceProjectName string, customVolProjectName string, includeRemote bool) ([]api.StoragePoolVolumeUsage, error) {
+	poolID := pool.ID()
+
+	filters := make([]db.StorageVolumeFilter, 0, 3)
+	for _, volType := range []dbCluster.StoragePoolVolumeType{dbCluster.StoragePoolVolumeTypeContainer, dbCluster.StoragePoolVolumeTypeVM, dbCluster.StoragePoolVolumeTypeCustom} {
+		filter := db.StorageVolumeFilter{
+			Type:   &volType,
+			PoolID: &poolID,
+		}
+
+		projectName := instanceProjectName
+		if volType == dbCluster.StoragePoolVolumeTypeCustom {
+			projectName = customVolProjectName
+		}
+
+		if projectName != "" {
+			filter.Project = &projectName
+		}
+
+		filters = append(filters, filter)
+	}
+
+	var dbVolumes []*db.StorageVolume
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		dbVolumes, err = tx.GetStorageVolumes(ctx, true, filters...)
+		if err != nil {
+			return fmt.Errorf("Failed loading storage volumes: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Process the volumes before their snapshots.
+	sort.SliceStable(dbVolumes, func(i, j int) bool {
+		return !shared.IsSnapshot(dbVolumes[i].Name) && shared.IsSnapshot(dbVolumes[j].Name)
+	})
+
+	volumes := make([]api.StoragePoolVolumeUsage, 0, len(dbVolumes))
+	volumeIndexes := make(map[string]int, len(dbVolumes))
+
+	for _, dbVol := range dbVolumes {
+		if dbVol.Location != s.ServerName && (dbVol.Location != "" || !includeRemote) {
+			continue
+		}
+
+		var usage *storagePools.VolumeUsage
+
+		if dbVol.Type == dbCluster.StoragePoolVolumeTypeNameCustom {
+			usage, err = pool.GetCustomVolumeUsage(dbVol.Project, dbVol.Name)
+		} else {
+			var inst instance.Instance
+
+			inst, err = instance.LoadByProjectAndName(s, dbVol.Project, dbVol.Name)
+			if err == nil {
+				usage, err = pool.GetInstanceUsage(inst)
+			}
+		}
+
+		// Report the error along with the volume rather than pretending it uses no space.
+		usageErr := ""
+		if err != nil {
+			logger.Warn("Failed getting volume usage", logger.Ctx{"pool": pool.Name(), "project": dbVol.Project, "type": dbVol.Type, "volume": dbVol.Name, "err": err})
+			usage = &storagePools.VolumeUsage{}
+			usageErr = err.Error()
+		}
+
+		parentName, snapName, isSnapshot := api.GetParentAndSnapshotName(dbVol.Name)
+		if isSnapshot {
+			idx, ok := volumeIndexes[dbVol.Project+"/"+dbVol.Type+"/"+parentName]
+			if !ok {
+				continue
+			}
+
+			volumes[idx].Snapshots = append(volumes[idx].Snapshots, api.StoragePoolVolumeSnapshotUsage{
+				Name:             snapName,
+				UsedBytes:        usage.Used,
+				ProvisionedBytes: usage.Total,
+				Error:            usageErr,
+			})
+
+			continue
+		}
+
+		volumeIndexes[dbVol.Project+"/"+dbVol.Type+"/"+dbVol.Name] = len(volumes)
+		volumes = append(volumes, api.StoragePoolVolumeUsage{
+			Name:             dbVol.Name,
+			Type:             dbVol.Type,
+			Project:          dbVol.Project,
+			Location:         dbVol.Location,
+			UsedBytes:        usage.Used,
+			ProvisionedBytes: usage.Total,
+			Error:            usageErr,
+			Snapshots:        []api.StoragePoolVolumeSnapshotUsage{},
+		})
+	}
+
+	for _, vol := range volumes {
+		sort.Slice(vol.Snapshots, func(i, j int) bool {
+			return vol.Snapshots[i].Name < vol.Snapshots[j].Name
+		})
+	}
+
+	return volumes, nil
+}
+
+// storagePoolUsageProjects aggregates the usage of the volumes per project, together with the disk limit of each
+// project on the storage pool. Snapshots count towards the used space but not the provisioned space, matching how
+// project disk limits are enforced. Projects with volumes or snapshots whose usage couldn't be retrieved are
+// flagged as incomplete.
+func storagePoolUsageProjects(poolName string, volumes []api.StoragePoolVolumeUsage, projects map[string]api.Project) []api.StoragePoolProjectUsage {
+	usages := make(map[string]*api.StoragePoolProjectUsage, len(projects))
+	for name, p := range projects {
+		limit := storagePoolProjectDiskLimit(poolName, p.Config)
+
+		usages[name] = &api.StoragePoolProjectUsage{
+			Name:       name,
+			LimitBytes: &limit,
+		}
+	}
+
+	for _, vol := range volumes {
+		usage, ok := usages[vol.Project]
+		if !ok {
+			continue
+		}
+
+		usage.UsedBytes += vol.UsedBytes
+		usage.ProvisionedBytes += vol.ProvisionedBytes
+		if vol.Error != "" {
+			usage.Incomplete = true
+		}
+
+		for _, snap := range vol.Snapshots {
+			usage.UsedBytes += snap.UsedBytes
+			if snap.Error != "" {
+				usage.Incomplete = true
+			}
+		}
+	}
+
+	projectUsages := make([]api.StoragePoolProjectUsage, 0, len(usages))
+	for _, usage := range usages {
+		projectUsages = append(projectUsages, *usage)
+	}
+
+	sort.Slice(projectUsages, func(i, j int) bool {
+		return projectUsages[i].Name < projectUsages[j].Name
+	})
+
+	return projectUsages
+}
+
+// storagePoolProjectDiskLimit returns the effective disk limit of a project on a storage pool, or -1 if the
+// project has no disk limit on the pool.
+func storagePoolProjectDiskLimit(poolName string, config map[string]string) int64 {
+	limit := int64(-1)
+
+	for _, key := range []string{"limits.disk", "limits.disk.pool." + poolName} {
+		value := config[key]
+		if value == "" {
+			continue
+		}
+
+		keyLimit, err := units.ParseByteSizeString(value)
+		if err != nil {
+			continue
+		}
+
+		if limit < 0 || keyLimit < limit {
+			limit = keyLimit
+		}
+	}
+
+	return limit
+}
+
+// storagePoolUsageMetricsInterval is how often the storage volume usage metrics are refreshed.
+// Getting the usage of every volume is expensive, so it isn't done on every metrics request.
+const storagePoolUsageMetricsInterval = 5 * time.Minute
+
+// storagePoolUsageMetricsCache holds the storage volume usage metrics of the local member per project.
+var storagePoolUsageMetricsCache map[string]*metrics.MetricSet
+var storagePoolUsageMetricsCacheLock sync.Mutex
+
+// storagePoolUsageMetricsTask returns a task that refreshes the storage volume usage metrics.
+func storagePoolUsageMetricsTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := stateFunc()
+
+		usageMetrics, err := storagePoolUsageMetricsGather(ctx, s)
+		if err != nil {
+			logger.Warn("Failed getting storage usage metrics", logger.Ctx{"err": err})
+			return
+		}
+
+		storagePoolUsageMetricsCacheLock.Lock()
+		storagePoolUsageMetricsCache = usageMetrics
+		storagePoolUsageMetricsCacheLock.Unlock()
+	}
+
+	return f, task.Every(storagePoolUsageMetricsInterval)
+}
+
+// storagePoolUsageMetrics returns the last gathered storage volume usage metrics of the local member for the
+// given projects. The metric sets must not be modified.
+func storagePoolUsageMetrics(projectNames []string) map[string]*metrics.MetricSet {
+	storagePoolUsageMetricsCacheLock.Lock()
+	defer storagePoolUsageMetricsCacheLock.Unlock()
+
+	out := make(map[string]*metrics.MetricSet, len(projectNames))
+	for _, projectName := range projectNames {
+		metricSet, ok := storagePoolUsageMetricsCache[projectName]
+		if ok {
+			out[projectName] = metricSet
+		}
+	}
+
+	return out
+}
+
+// storagePoolUsageMetricsGather returns the storage volume usage metrics of the local member for all projects.
+// Volumes on remote storage pools are only reported by the cluster leader so that they aren't counted once per member.
+func storagePoolUsageMetricsGather(ctx context.Context, s *state.State) (map[string]*metrics.MetricSet, error) {
+	includeRemote := false
+	leaderInfo, err := s.LeaderInfo()
+	if err == nil {
+		includeRemote = leaderInfo.Leader
+	}
+
+	var poolNames []string
+	projects := map[string]api.Project{}
+
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		poolNames, err = tx.GetCreatedStoragePoolNames(ctx)
+		if err != nil && !response.IsNotFoundError(err) {
+			return fmt.Errorf("Failed loading storage pools: %w", err)
+		}
+
+		dbProjects, err := dbCluster.GetProjects(ctx, tx.Tx())
+		if err != nil {
+			return fmt.Errorf("Failed loading projects: %w", err)
+		}
+
+		for _, dbProject := range dbProjects {
+			p, err := dbProject.ToAPI(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			projects[p.Name] = *p
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := map[string]*metrics.MetricSet{}
+
+	for _, poolName := range poolNames {
+		pool, err := storagePools.LoadByName(s, poolName)
+		if err != nil {
+			logger.Warn("Failed loading storage pool for usage metrics", logger.Ctx{"pool": poolName, "err": err})
+			continue
+		}
+
+		if pool.LocalStatus() != api.StoragePoolStatusCreated {
+			continue
+		}
+
+		allVolumes, err := storagePoolUsageVolumes(ctx, s, pool, "", "", includeRemote)
+		if err != nil {
+			logger.Warn("Failed getting storage pool usage for metrics", logger.Ctx{"pool": poolName, "err": err})
+			continue
+		}
+
+		volumes := make([]api.StoragePoolVolumeUsage, 0, len(allVolumes))
+		poolProjects := map[string]api.Project{}
+
+		for _, vol := range allVolumes {
+			p, ok := projects[vol.Project]
+			if !ok {
+				continue
+			}
+
+			volumes = append(volumes, vol)
+			poolProjects[vol.Project] = p
+
+			if out[vol.Project] == nil {
+				out[vol.Project] = metrics.NewMetricSet(nil)
+			}
+
+			// Volumes and snapshots whose usage couldn't be retrieved have no samples rather than 0 bytes.
+			labels := map[string]string{"project": vol.Project, "pool": poolName, "type": vol.Type, "name": vol.Name}
+			if vol.Error == "" {
+				out[vol.Project].AddSamples(metrics.VolumeUsedBytes, metrics.Sample{Labels: labels, Value: float64(vol.UsedBytes)})
+				out[vol.Project].AddSamples(metrics.VolumeProvisionedBytes, metrics.Sample{Labels: labels, Value: float64(vol.ProvisionedBytes)})
+			}
+
+			for _, snap := range vol.Snapshots {
+				if snap.Error != "" {
+					continue
+				}
+
+				labels := map[string]string{"project": vol.Project, "pool": poolName, "type": vol.Type, "name": vol.Name + shared.SnapshotDelimiter + snap.Name}
+				out[vol.Project].AddSamples(metrics.VolumeUsedBytes, metrics.Sample{Labels: labels, Value: float64(snap.UsedBytes)})
+				out[vol.Project].AddSamples(metrics.VolumeProvisionedBytes, metrics.Sample{Labels: labels, Value: float64(snap.ProvisionedBytes)})
+			}
+		}
+
+		for _, projectUsage := range storagePoolUsageProjects(poolName, volumes, poolProjects) {
+			// Partial totals would show as sudden drops of the usage.
+			if projectUsage.Incomplete {
+				continue
+			}
+
+			labels := map[string]string{"project": projectUsage.Name, "pool": poolName}
+			out[projectUsage.Name].AddSamples(metrics.ProjectDiskUsedBytes, metrics.Sample{Labels: labels, Value: float64(projectUsage.UsedBytes)})
+			out[projectUsage.Name].AddSamples(metrics.ProjectDiskProvisionedBytes, metrics.Sample{Labels: labels, Value: float64(projectUsage.ProvisionedBytes)})
+		}
+	}
+
+	return out, nil
+}
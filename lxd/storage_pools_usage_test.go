@@ -0,0 +1,87 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+func Test_storagePoolProjectDiskLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   map[string]string
+		expected int64
+	}{
+		{
+			name:     "No limit",
+			config:   map[string]string{},
+			expected: -1,
+		},
+		{
+			name:     "Project limit",
+			config:   map[string]string{"limits.disk": "10GiB"},
+			expected: 10 * 1024 * 1024 * 1024,
+		},
+		{
+			name:     "Pool limit",
+			config:   map[string]string{"limits.disk.pool.pool1": "5GiB"},
+			expected: 5 * 1024 * 1024 * 1024,
+		},
+		{
+			name:     "Lowest limit applies",
+			config:   map[string]string{"limits.disk": "10GiB", "limits.disk.pool.pool1": "20GiB"},
+			expected: 10 * 1024 * 1024 * 1024,
+		},
+		{
+			name:     "Limit of another pool",
+			config:   map[string]string{"limits.disk.pool.pool2": "5GiB"},
+			expected: -1,
+		},
+		{
+			name:     "Invalid limit is ignored",
+			config:   map[string]string{"limits.disk": "lots", "limits.disk.pool.pool1": "1GiB"},
+			expected: 1024 * 1024 * 1024,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, storagePoolProjectDiskLimit("pool1", test.config), test.name)
+	}
+}
+
+func Test_storagePoolUsageProjects(t *testing.T) {
+	projects := map[string]api.Project{
+		"default": {Name: "default"},
+		"p1":      {Name: "p1", Config: map[string]string{"limits.disk.pool.pool1": "100B"}},
+	}
+
+	volumes := []api.StoragePoolVolumeUsage{
+		{
+			Project:          "default",
+			Name:             "c1",
+			UsedBytes:        10,
+			ProvisionedBytes: 20,
+			Snapshots: []api.StoragePoolVolumeSnapshotUsage{
+				{Name: "snap0", UsedBytes: 1, ProvisionedBytes: 20},
+				{Name: "snap1", UsedBytes: 2, ProvisionedBytes: 20},
+			},
+		},
+		{Project: "default", Name: "vol1", UsedBytes: 5, ProvisionedBytes: 50},
+		{Project: "p1", Name: "c1", UsedBytes: 30, ProvisionedBytes: 40},
+		{Project: "p1", Name: "c2", Error: "Failed getting usage"},
+		{Project: "p2", Name: "c1", UsedBytes: 1000, ProvisionedBytes: 1000},
+	}
+
+	noLimit := int64(-1)
+	limit := int64(100)
+
+	expected := []api.StoragePoolProjectUsage{
+		// Snapshots count towards the used space only.
+		{Name: "default", UsedBytes: 18, ProvisionedBytes: 70, LimitBytes: &noLimit},
+		{Name: "p1", UsedBytes: 30, ProvisionedBytes: 40, LimitBytes: &limit, Incomplete: true},
+	}
+
+	assert.Equal(t, expected, storagePoolUsageProjects("pool1", volumes, projects))
+}
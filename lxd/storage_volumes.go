@@ -1677,6 +1677,7 @@ func storagePoolVolumePost(d *Daemon, r *http.Request) response.Response {
 	}
 
 	// Check if a running instance is using it.
+	runningInstances := map[instance.Instance][]string{}
 	err = storagePools.VolumeUsedByInstanceDevices(s, details.pool.Name(), effectiveProjectName, &dbVolume.StorageVolume, true, func(dbInst db.InstanceArgs, project api.Project, usedByDevices []string) error {
 		inst, err := instance.Load(s, dbInst, project)
 		if err != nil {
@@ -1684,7 +1685,11 @@ func storagePoolVolumePost(d *Daemon, r *http.Request) response.Response {
 		}
 
 		if inst.IsRunning() {
-			return errors.New("Volume is still in use by running instances")
+			if !req.Live {
+				return errors.New("Volume is still in use by running instances")
+			}
+
+			runningInstances[inst] = usedByDevices
 		}
 
 		return nil
@@ -1695,13 +1700,208 @@ func storagePoolVolumePost(d *Daemon, r *http.Request) response.Response {
 
 	// Detect a rename request.
 	if (req.Pool == "" || req.Pool == details.pool.Name()) && (effectiveProjectName == targetProjectName) {
+		if req.Live {
+			return response.BadRequest(errors.New("Live moves require a different storage pool"))
+		}
+
 		return storagePoolVolumeTypePostRename(s, r, details.pool.Name(), effectiveProjectName, &dbVolume.StorageVolume, req)
 	}
 
+	// Detect a live move request.
+	if req.Live && len(runningInstances) > 0 {
+		if effectiveProjectName != targetProjectName {
+			return response.BadRequest(errors.New("Live moves between projects aren't supported"))
+		}
+
+		return storagePoolVolumeTypePostLiveMove(s, r, details.pool.Name(), effectiveProjectName, &dbVolume.StorageVolume, req, runningInstances)
+	}
+
 	// Otherwise this is a move request.
 	return storagePoolVolumeTypePostMove(s, r, details.pool.Name(), effectiveProjectName, targetProjectName, &dbVolume.StorageVolume, req)
 }
 
+// storagePoolVolumeTypePostLiveMove moves a custom volume to another storage pool while it is in use by running
+// instances. Block volumes are mirrored by the VM using them, filesystem volumes are copied and then synced again
+// with the containers using them frozen before switching the containers over to the new volume.
+func storagePoolVolumeTypePostLiveMove(s *state.State, r *http.Request, poolName string, projectName string, vol *api.StorageVolume, req api.StorageVolumePost, runningInstances map[instance.Instance][]string) response.Response {
+	newVol := *vol
+	newVol.Name = req.Name
+
+	pool, err := storagePools.LoadByName(s, poolName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	newPool, err := storagePools.LoadByName(s, req.Pool)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Devices coming from profiles can't be switched over for a single instance.
+	usedByProfiles := false
+	err = storagePools.VolumeUsedByProfileDevices(s, poolName, projectName, vol, func(_ int64, _ api.Profile, _ api.Project, _ []string) error {
+		usedByProfiles = true
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if usedByProfiles {
+		return response.BadRequest(errors.New("Volumes used by profiles cannot be moved live"))
+	}
+
+	var run func(op *operations.Operation) error
+
+	switch vol.ContentType {
+	case cluster.StoragePoolVolumeContentTypeNameBlock:
+		if len(runningInstances) > 1 {
+			return response.BadRequest(errors.New("Block volumes used by more than one running instance cannot be moved live"))
+		}
+
+		var vm instance.VM
+		var devName string
+		for inst, devNames := range runningInstances {
+			var ok bool
+
+			vm, ok = inst.(instance.VM)
+			if !ok || len(devNames) != 1 {
+				return response.BadRequest(errors.New("Block volumes can only be moved live when attached once to a running virtual machine"))
+			}
+
+			devName = devNames[0]
+		}
+
+		run = func(op *operations.Operation) error {
+			return storagePoolVolumeLiveMoveBlock(pool, newPool, projectName, vol, &newVol, vm, devName, op)
+		}
+
+	case cluster.StoragePoolVolumeContentTypeNameFS:
+		for inst := range runningInstances {
+			if inst.Type() != instancetype.Container {
+				return response.BadRequest(errors.New("Filesystem volumes used by running virtual machines cannot be moved live"))
+			}
+		}
+
+		run = func(op *operations.Operation) error {
+			return storagePoolVolumeLiveMoveFilesystem(s, pool, newPool, projectName, vol, &newVol, runningInstances, op)
+		}
+
+	default:
+		return response.BadRequest(fmt.Errorf("Volumes of content type %q cannot be moved live", vol.ContentType))
+	}
+
+	op, err := operations.OperationCreate(r.Context(), s, projectName, operations.OperationClassTask, operationtype.VolumeMove, nil, nil, run, nil, nil)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// storagePoolVolumeLiveMoveBlock moves a custom block volume attached to a running VM to another pool by having
+// the VM mirror the volume onto a copy of it on the new pool.
+func storagePoolVolumeLiveMoveBlock(pool storagePools.Pool, newPool storagePools.Pool, projectName string, vol *api.StorageVolume, newVol *api.StorageVolume, vm instance.VM, devName string, op *operations.Operation) error {
+	revert := revert.New()
+	defer revert.Fail()
+
+	// Copy the volume and its snapshots, the copied volume content is then brought up to date by the mirror.
+	err := newPool.CreateCustomVolumeFromCopy(projectName, projectName, newVol.Name, "", nil, pool.Name(), vol.Name, true, op)
+	if err != nil {
+		return err
+	}
+
+	revert.Add(func() { _ = newPool.DeleteCustomVolume(projectName, newVol.Name, op) })
+
+	// Keep the new volume mounted as it replaces the mount of the old volume held by the running VM.
+	_, err = newPool.MountCustomVolume(projectName, newVol.Name, op)
+	if err != nil {
+		return err
+	}
+
+	revert.Add(func() { _, _ = newPool.UnmountCustomVolume(projectName, newVol.Name, op) })
+
+	dbVol, err := storagePools.VolumeDBGet(newPool, projectName, newVol.Name, storageDrivers.VolumeTypeCustom)
+	if err != nil {
+		return err
+	}
+
+	volStorageName := project.StorageVolume(projectName, newVol.Name)
+	volume := newPool.GetVolume(storageDrivers.VolumeTypeCustom, storageDrivers.ContentTypeBlock, volStorageName, dbVol.Config)
+
+	diskPath, err := newPool.Driver().GetVolumeDiskPath(volume)
+	if err != nil {
+		return fmt.Errorf("Failed getting disk path of volume %q: %w", newVol.Name, err)
+	}
+
+	// The VM is responsible for removing the new volume from now on, as it must be kept once the VM uses it.
+	cleanup := revert.Clone().Fail
+	revert.Success()
+
+	err = vm.DiskDeviceMirror(devName, diskPath, newPool.Name(), newVol.Name, cleanup)
+	if err != nil {
+		return err
+	}
+
+	_, err = pool.UnmountCustomVolume(projectName, vol.Name, op)
+	if err != nil {
+		logger.Warn("Failed unmounting moved volume", logger.Ctx{"project": projectName, "pool": pool.Name(), "volume": vol.Name, "err": err})
+	}
+
+	return pool.DeleteCustomVolume(projectName, vol.Name, op)
+}
+
+// storagePoolVolumeLiveMoveFilesystem moves a custom filesystem volume used by running containers to another pool.
+// The volume is copied while in use, then synced again with the containers frozen and the containers' devices are
+// switched over to the new volume before they are unfrozen.
+func storagePoolVolumeLiveMoveFilesystem(s *state.State, pool storagePools.Pool, newPool storagePools.Pool, projectName string, vol *api.StorageVolume, newVol *api.StorageVolume, runningInstances map[instance.Instance][]string, op *operations.Operation) error {
+	revert := revert.New()
+	defer revert.Fail()
+
+	err := newPool.CreateCustomVolumeFromCopy(projectName, projectName, newVol.Name, "", nil, pool.Name(), vol.Name, true, op)
+	if err != nil {
+		return err
+	}
+
+	revert.Add(func() { _ = newPool.DeleteCustomVolume(projectName, newVol.Name, op) })
+
+	// Freeze the containers to stop changes to the volume for the final sync.
+	for inst := range runningInstances {
+		err = inst.Freeze()
+		if err != nil {
+			return fmt.Errorf("Failed freezing instance %q: %w", inst.Name(), err)
+		}
+
+		defer func() {
+			err := inst.Unfreeze()
+			if err != nil {
+				logger.Error("Failed unfreezing instance after live volume move", logger.Ctx{"project": projectName, "instance": inst.Name(), "err": err})
+			}
+		}()
+	}
+
+	err = newPool.RefreshCustomVolume(projectName, projectName, newVol.Name, "", nil, pool.Name(), vol.Name, true, op)
+	if err != nil {
+		return err
+	}
+
+	// Switch the devices over, this remounts the new volume in the running containers.
+	cleanup, err := storagePoolVolumeUpdateUsers(context.TODO(), s, projectName, pool.Name(), vol, newPool.Name(), newVol)
+	if err != nil {
+		return err
+	}
+
+	revert.Add(cleanup)
+
+	err = pool.DeleteCustomVolume(projectName, vol.Name, op)
+	if err != nil {
+		return err
+	}
+
+	revert.Success()
+	return nil
+}
+
 func migrateStorageVolume(s *state.State, r *http.Request, sourceVolumeName string, sourcePoolName string, targetNode string, projectName string, req api.StorageVolumePost, op *operations.Operation) error {
 	if targetNode == req.Source.Location {
 		return errors.New("Target must be different than storage volumes' current location")
@@ -2627,10 +2827,20 @@ func createStoragePoolVolumeFromBackup(s *state.State, r *http.Request, requestP
 	defer func() { _ = os.Remove(backupFile.Name()) }()
 	revert.Add(func() { _ = backupFile.Close() })
 
-	// Stream uploaded backup data into temporary file.
+	// Stream uploaded backup data into temporary file, decrypting it if needed.
+	dec, err := backupDecryption(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	data, err = backup.DecryptReader(data, dec)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	_, err = io.Copy(backupFile, data)
 	if err != nil {
-		return response.InternalError(err)
+		return response.SmartError(err)
 	}
 
 	// Detect squashfs compression and convert to tarball.
@@ -2670,6 +2880,13 @@ func createStoragePoolVolumeFromBackup(s *state.State, r *http.Request, requestP
 		backupFile = tarFile
 	}
 
+	// Verify the integrity of the backup before any storage is touched.
+	logger.Debug("Verifying backup file")
+	_, err = backup.Verify(s, backupFile, backupFile.Name())
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Backup verification failed: %w", err))
+	}
+
 	// Parse the backup information.
 	_, err = backupFile.Seek(0, io.SeekStart)
 	if err != nil {
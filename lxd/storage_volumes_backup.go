@@ -357,7 +357,13 @@ func storagePoolVolumeTypeCustomBackupsPost(d *Daemon, r *http.Request) response
 		return response.BadRequest(err)
 	}
 
-	if req.Name == "" {
+	if req.Name == "" && req.Target != "" {
+		// Backups on a backup target aren't recorded in the database, so base the name on the stored objects.
+		req.Name, err = backupTargetNextName(r.Context(), s, req.Target, details.volumeName, effectiveProjectName, "volumes", details.pool.Name(), details.volumeName)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	} else if req.Name == "" {
 		var backups []string
 
 		// come up with a name.
@@ -369,30 +375,7 @@ func storagePoolVolumeTypeCustomBackupsPost(d *Daemon, r *http.Request) response
 			return response.BadRequest(err)
 		}
 
-		base := details.volumeName + shared.SnapshotDelimiter + "backup"
-		length := len(base)
-		backupNo := 0
-
-		// Iterate over previous backups to autoincrement the backup number.
-		for _, backup := range backups {
-			// Ignore backups not containing base.
-			if !strings.HasPrefix(backup, base) {
-				continue
-			}
-
-			substr := backup[length:]
-			var num int
-			count, err := fmt.Sscanf(substr, "%d", &num)
-			if err != nil || count != 1 {
-				continue
-			}
-
-			if num >= backupNo {
-				backupNo = num + 1
-			}
-		}
-
-		req.Name = fmt.Sprintf("backup%d", backupNo)
+		req.Name = backup.NextBackupName(details.volumeName, "backup", backups)
 	}
 
 	// In case no version was selected for the backup format use the globally set format by default.
@@ -413,6 +396,11 @@ func storagePoolVolumeTypeCustomBackupsPost(d *Daemon, r *http.Request) response
 	fullName := details.volumeName + shared.SnapshotDelimiter + backupName
 	volumeOnly := req.VolumeOnly
 
+	enc, err := backupEncryption(req.EncryptionRecipients, req.EncryptionPassphrase)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	backup := func(op *operations.Operation) error {
 		args := db.StoragePoolVolumeBackup{
 			Name:                 fullName,
@@ -424,7 +412,21 @@ func storagePoolVolumeTypeCustomBackupsPost(d *Daemon, r *http.Request) response
 			CompressionAlgorithm: req.CompressionAlgorithm,
 		}
 
-		err := volumeBackupCreate(s, args, effectiveProjectName, details.pool.Name(), details.volumeName, req.Version)
+		if req.Target != "" {
+			backupTarget, err := backupTargetLoad(s.ShutdownCtx, s, req.Target)
+			if err != nil {
+				return err
+			}
+
+			key, err := volumeBackupCreateOnTarget(s, backupTarget, args, effectiveProjectName, details.pool.Name(), details.volumeName, req.Version, enc)
+			if err != nil {
+				return fmt.Errorf("Create volume backup: %w", err)
+			}
+
+			return op.ExtendMetadata(map[string]any{"backup_target": backupTarget.Name, "backup_object": key})
+		}
+
+		err := volumeBackupCreate(s, args, effectiveProjectName, details.pool.Name(), details.volumeName, req.Version, enc)
 		if err != nil {
 			return fmt.Errorf("Create volume backup: %w", err)
 		}
@@ -436,7 +438,11 @@ func storagePoolVolumeTypeCustomBackupsPost(d *Daemon, r *http.Request) response
 
 	resources := map[string][]api.URL{}
 	resources["storage_volumes"] = []api.URL{*api.NewURL().Path(version.APIVersion, "storage-pools", details.pool.Name(), "volumes", details.volumeTypeName, details.volumeName)}
-	resources["backups"] = []api.URL{*api.NewURL().Path(version.APIVersion, "storage-pools", details.pool.Name(), "volumes", details.volumeTypeName, details.volumeName, "backups", backupName)}
+
+	// Backups pushed to a backup target aren't stored on the server.
+	if req.Target == "" {
+		resources["backups"] = []api.URL{*api.NewURL().Path(version.APIVersion, "storage-pools", details.pool.Name(), "volumes", details.volumeTypeName, details.volumeName, "backups", backupName)}
+	}
 
 	op, err := operations.OperationCreate(r.Context(), s, requestProjectName, operations.OperationClassTask, operationtype.CustomVolumeBackupCreate, resources, nil, backup, nil, nil)
 	if err != nil {
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	storagePools "github.com/canonical/lxd/lxd/storage"
+	storageDrivers "github.com/canonical/lxd/lxd/storage/drivers"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/version"
+)
+
+var storagePoolVolumeTypePromoteCmd = APIEndpoint{
+	Path:        "storage-pools/{poolName}/volumes/{type}/{volumeName}/promote",
+	MetricsType: entity.TypeStoragePool,
+
+	Post: APIEndpointAction{Handler: storagePoolVolumeTypePromotePost, AccessHandler: storagePoolVolumeTypeAccessHandler(entity.TypeStorageVolume, auth.EntitlementCanEdit)},
+}
+
+// swagger:operation POST /1.0/storage-pools/{poolName}/volumes/{type}/{volumeName}/promote storage storage_pool_volume_type_promote_post
+//
+//	Promote a storage volume replica
+//
+//	Turns a custom volume created by storage pool replication into a regular custom volume.
+//	Further replication runs from the source volume leave the promoted volume untouched.
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: lxd01
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func storagePoolVolumeTypePromotePost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	details, err := request.GetContextValue[storageVolumeDetails](r.Context(), ctxStorageVolumeDetails)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	effectiveProjectName, err := request.GetContextValue[string](r.Context(), request.CtxEffectiveProjectName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if details.volumeType != cluster.StoragePoolVolumeTypeCustom {
+		return response.BadRequest(fmt.Errorf("Invalid storage volume type %q", details.volumeTypeName))
+	}
+
+	target := request.QueryParam(r, "target")
+	resp := forwardedResponseToNode(r.Context(), s, target)
+	if resp != nil {
+		return resp
+	}
+
+	resp = forwardedResponseIfVolumeIsRemote(r.Context(), s)
+	if resp != nil {
+		return resp
+	}
+
+	dbVolume, err := storagePools.VolumeDBGet(details.pool, effectiveProjectName, details.volumeName, storageDrivers.VolumeTypeCustom)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if dbVolume.Config[instanceReplicaSourceKey] == "" {
+		return response.BadRequest(fmt.Errorf("Storage volume %q isn't a replica", details.volumeName))
+	}
+
+	run := func(op *operations.Operation) error {
+		return storageVolumePromote(details.pool, effectiveProjectName, details.volumeName, op)
+	}
+
+	resources := map[string][]api.URL{}
+	resources["storage_volumes"] = []api.URL{*api.NewURL().Path(version.APIVersion, "storage-pools", details.pool.Name(), "volumes", "custom", details.volumeName)}
+
+	op, err := operations.OperationCreate(r.Context(), s, request.ProjectParam(r), operations.OperationClassTask, operationtype.VolumePromote, resources, nil, run, nil, nil)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// storageVolumePromote clears the replica marker of a custom volume created by storage pool replication.
+// Volumes that aren't replicas are left untouched.
+func storageVolumePromote(pool storagePools.Pool, projectName string, volName string, op *operations.Operation) error {
+	dbVolume, err := storagePools.VolumeDBGet(pool, projectName, volName, storageDrivers.VolumeTypeCustom)
+	if err != nil {
+		return err
+	}
+
+	if dbVolume.Config[instanceReplicaSourceKey] == "" {
+		return nil
+	}
+
+	config := maps.Clone(dbVolume.Config)
+	delete(config, instanceReplicaSourceKey)
+
+	err = pool.UpdateCustomVolume(projectName, volName, dbVolume.Description, config, op)
+	if err != nil {
+		return fmt.Errorf("Failed promoting storage volume %q: %w", volName, err)
+	}
+
+	return nil
+}
@@ -8,3 +8,55 @@ const (
 	// restructured fields in order to be able to track custom storage volumes attached to the instance.
 	BackupMetadataVersion2 uint32 = 2
 )
+
+// BackupInfo represents the contents of a backup file.
+//
+// swagger:model
+//
+// API extension: backup_info.
+type BackupInfo struct {
+	// Name of the backed up instance or custom volume
+	// Example: c1
+	Name string `json:"name" yaml:"name"`
+
+	// Name of the backup
+	// Example: backup0
+	Backup string `json:"backup" yaml:"backup"`
+
+	// Type of backup (container, virtual-machine or custom)
+	// Example: container
+	Type string `json:"type" yaml:"type"`
+
+	// Storage driver used to create the backup
+	// Example: zfs
+	Backend string `json:"backend" yaml:"backend"`
+
+	// Storage pool the backup was created from
+	// Example: default
+	Pool string `json:"pool" yaml:"pool"`
+
+	// Whether the backup uses the storage driver's optimized format
+	// Example: true
+	OptimizedStorage bool `json:"optimized_storage" yaml:"optimized_storage"`
+
+	// Snapshots contained in the backup
+	// Example: ["snap0", "snap1"]
+	Snapshots []string `json:"snapshots" yaml:"snapshots"`
+
+	// Name of the parent backup of an incremental backup
+	// Example: backup0
+	//
+	// API extension: backup_incremental
+	Parent string `json:"parent,omitempty" yaml:"parent,omitempty"`
+
+	// Configuration of the backed up instance
+	Instance *Instance `json:"instance,omitempty" yaml:"instance,omitempty"`
+
+	// Storage volumes contained in the backup
+	Volumes []StorageVolume `json:"volumes" yaml:"volumes"`
+
+	// Whether the checksums of all files in the backup were verified
+	// Backups created before checksums were introduced can't be verified.
+	// Example: true
+	Verified bool `json:"verified" yaml:"verified"`
+}
@@ -0,0 +1,56 @@
+package api
+
+// BackupTarget represents an S3-compatible object store that backups can be pushed to and restored from.
+//
+// swagger:model
+//
+// API extension: backup_targets.
+type BackupTarget struct {
+	// Name of the backup target.
+	// Example: offsite
+	Name string `json:"name" yaml:"name"`
+
+	// Description of the backup target.
+	// Example: Off-site backup bucket
+	Description string `json:"description" yaml:"description"`
+
+	// Backup target configuration map (refer to doc/reference/backup_targets.md)
+	// Example: {"s3.endpoint": "https://s3.example.com", "s3.bucket": "backups"}
+	Config map[string]string `json:"config" yaml:"config"`
+}
+
+// BackupTargetsPost represents the fields required to create a new backup target.
+//
+// swagger:model
+//
+// API extension: backup_targets.
+type BackupTargetsPost struct {
+	// Name of the backup target.
+	// Example: offsite
+	Name string `json:"name" yaml:"name"`
+
+	BackupTargetPut `yaml:",inline"`
+}
+
+// BackupTargetPut represents the modifiable fields of a backup target.
+//
+// swagger:model
+//
+// API extension: backup_targets.
+type BackupTargetPut struct {
+	// Description of the backup target.
+	// Example: Off-site backup bucket
+	Description string `json:"description" yaml:"description"`
+
+	// Backup target configuration map (refer to doc/reference/backup_targets.md)
+	// Example: {"s3.endpoint": "https://s3.example.com", "s3.bucket": "backups"}
+	Config map[string]string `json:"config" yaml:"config"`
+}
+
+// Writable returns the editable fields of a [BackupTarget] as [BackupTargetPut].
+func (b BackupTarget) Writable() BackupTargetPut {
+	return BackupTargetPut{
+		Description: b.Description,
+		Config:      b.Config,
+	}
+}
@@ -0,0 +1,50 @@
+package api
+
+const (
+	// ClusterRebalanceReasonPlacement indicates an instance is moved to comply with its placement group.
+	ClusterRebalanceReasonPlacement string = "placement"
+
+	// ClusterRebalanceReasonLoad indicates an instance is moved to reduce the load imbalance between cluster members.
+	ClusterRebalanceReasonLoad string = "load"
+)
+
+// ClusterRebalance represents the instance moves planned to rebalance the cluster.
+//
+// swagger:model
+//
+// API extension: cluster_rebalance.
+type ClusterRebalance struct {
+	// List of planned instance moves
+	Moves []ClusterRebalanceMove `json:"moves" yaml:"moves"`
+}
+
+// ClusterRebalanceMove represents a single planned instance move.
+//
+// swagger:model
+//
+// API extension: cluster_rebalance.
+type ClusterRebalanceMove struct {
+	// Name of the instance
+	// Example: c1
+	Instance string `json:"instance" yaml:"instance"`
+
+	// Project of the instance
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+
+	// Name of the cluster member the instance is located on
+	// Example: lxd01
+	Source string `json:"source" yaml:"source"`
+
+	// Name of the cluster member the instance is moved to
+	// Example: lxd02
+	Target string `json:"target" yaml:"target"`
+
+	// Whether the instance is live-migrated
+	// Example: false
+	Live bool `json:"live" yaml:"live"`
+
+	// Reason for the move (either "placement" or "load")
+	// Example: placement
+	Reason string `json:"reason" yaml:"reason"`
+}
@@ -2,6 +2,9 @@ package api
 
 // Define consts for all the lifecycle events.
 const (
+	EventLifecycleBackupTargetCreated               = "backup-target-created"
+	EventLifecycleBackupTargetDeleted               = "backup-target-deleted"
+	EventLifecycleBackupTargetUpdated               = "backup-target-updated"
 	EventLifecycleCertificateCreated                = "certificate-created"
 	EventLifecycleCertificateDeleted                = "certificate-deleted"
 	EventLifecycleCertificateUpdated                = "certificate-updated"
@@ -70,6 +73,10 @@ const (
 	EventLifecycleNetworkACLDeleted                 = "network-acl-deleted"
 	EventLifecycleNetworkACLRenamed                 = "network-acl-renamed"
 	EventLifecycleNetworkACLUpdated                 = "network-acl-updated"
+	EventLifecycleNetworkAddressSetCreated          = "network-address-set-created"
+	EventLifecycleNetworkAddressSetDeleted          = "network-address-set-deleted"
+	EventLifecycleNetworkAddressSetRenamed          = "network-address-set-renamed"
+	EventLifecycleNetworkAddressSetUpdated          = "network-address-set-updated"
 	EventLifecycleNetworkCreated                    = "network-created"
 	EventLifecycleNetworkDeleted                    = "network-deleted"
 	EventLifecycleNetworkForwardCreated             = "network-forward-created"
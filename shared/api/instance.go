@@ -35,6 +35,9 @@ const (
 	// SourceTypeCopy represents instance creation from a copy operation.
 	SourceTypeCopy = "copy"
 
+	// SourceTypeBackup represents instance creation from a backup stored on a backup target.
+	SourceTypeBackup = "backup"
+
 	// SourceTypeNone represents an unknown source type for instance creation.
 	SourceTypeNone = "none"
 )
@@ -483,6 +486,18 @@ type InstanceSource struct {
 	//
 	// API extension: override_snapshot_profiles_on_copy
 	OverrideSnapshotProfiles bool `json:"override_snapshot_profiles" yaml:"override_snapshot_profiles"`
+
+	// Name of the backup target to restore from (for backup)
+	// Example: offsite
+	//
+	// API extension: backup_targets
+	BackupTarget string `json:"backup_target,omitempty" yaml:"backup_target,omitempty"`
+
+	// Key of the backup object on the backup target (for backup)
+	// Example: default/instances/c1/backup0
+	//
+	// API extension: backup_targets
+	BackupObject string `json:"backup_object,omitempty" yaml:"backup_object,omitempty"`
 }
 
 // InstanceUEFIVars represents the UEFI variables of a LXD virtual machine.
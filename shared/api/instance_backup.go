@@ -43,6 +43,30 @@ type InstanceBackupsPost struct {
 	//
 	// API extension: backup_metadata_version
 	Version uint32 `json:"version" yaml:"version"`
+
+	// Name of the backup target to push the backup to instead of storing it on the server
+	// Example: offsite
+	//
+	// API extension: backup_targets
+	Target string `json:"target,omitempty" yaml:"target,omitempty"`
+
+	// Name of the parent backup to create an incremental backup from
+	// Example: backup0
+	//
+	// API extension: backup_incremental
+	Parent string `json:"parent,omitempty" yaml:"parent,omitempty"`
+
+	// age X25519 recipients (public keys) to encrypt the backup for
+	// Example: ["age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p"]
+	//
+	// API extension: backup_encryption
+	EncryptionRecipients []string `json:"encryption_recipients,omitempty" yaml:"encryption_recipients,omitempty"`
+
+	// Passphrase to encrypt the backup with, can't be combined with recipients
+	// Example: secret
+	//
+	// API extension: backup_encryption
+	EncryptionPassphrase string `json:"encryption_passphrase,omitempty" yaml:"encryption_passphrase,omitempty"`
 }
 
 // InstanceBackup represents a LXD instance backup.
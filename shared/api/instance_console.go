@@ -22,9 +22,15 @@ type InstanceConsolePost struct {
 	// Example: 24
 	Height int `json:"height" yaml:"height"`
 
-	// Type of console to attach to (console or vga)
+	// Type of console to attach to (console, vga or vnc)
 	// Example: console
 	//
 	// API extension: console_vga_type
 	Type string `json:"type" yaml:"type"`
+
+	// Whether to attach read-only to the active console session (console type only)
+	// Example: false
+	//
+	// API extension: console_sessions
+	Observer bool `json:"observer" yaml:"observer"`
 }
@@ -106,6 +106,12 @@ type InstanceStateMemory struct {
 	// API extension: instances_state_total
 	Total int64 `json:"total" yaml:"total"`
 
+	// Memory hot-added to the running VM in bytes
+	// Example: 1073741824
+	//
+	// API extension: vm_memory_hotplug
+	Hotplugged int64 `json:"hotplugged" yaml:"hotplugged"`
+
 	// SWAP usage in bytes
 	// Example: 12297557
 	SwapUsage int64 `json:"swap_usage" yaml:"swap_usage"`
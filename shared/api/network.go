@@ -1,5 +1,9 @@
 package api
 
+import (
+	"time"
+)
+
 // NetworksPost represents the fields of a new LXD network
 //
 // swagger:model
@@ -166,6 +170,18 @@ type NetworkLease struct {
 	//
 	// API extension: network_allocations_ovn_uplink
 	Project string `json:"project" yaml:"project"`
+
+	// When the DHCP lease expires (unset for leases that never expire and for records without a lease)
+	// Example: 2025-06-01T12:00:00Z
+	//
+	// API extension: network_dhcp_options
+	ExpiresAt *time.Time `json:"expires_at" yaml:"expires_at"`
+
+	// The hostname sent by the client when requesting the DHCP lease
+	// Example: c1
+	//
+	// API extension: network_dhcp_options
+	ClientHostname string `json:"client_hostname" yaml:"client_hostname"`
 }
 
 // NetworkState represents the network state
@@ -213,6 +229,11 @@ type NetworkState struct {
 	//
 	// API extension: network_state_ovn
 	OVN *NetworkStateOVN `json:"ovn" yaml:"ovn"`
+
+	// State of the WireGuard interface
+	//
+	// API extension: network_peer_wireguard
+	WireGuard *NetworkStateWireGuard `json:"wireguard" yaml:"wireguard"`
 }
 
 // NetworkStateAddress represents a network address
@@ -347,3 +368,44 @@ type NetworkStateOVN struct {
 	// OVN network chassis name
 	Chassis string `json:"chassis" yaml:"chassis"`
 }
+
+// NetworkStateWireGuard represents WireGuard specific state
+//
+// swagger:model
+//
+// API extension: network_peer_wireguard.
+type NetworkStateWireGuard struct {
+	// Public key of the network, to set on its peers
+	// Example: hOz8d/1TGeX0NjvJu/2RGyyfu7fSgzGyNyRoxxwEzk8=
+	PublicKey string `json:"public_key" yaml:"public_key"`
+
+	// Local listening port
+	// Example: 51820
+	ListenPort uint64 `json:"listen_port" yaml:"listen_port"`
+
+	// State of the WireGuard peers, keyed by peer name
+	Peers map[string]NetworkStateWireGuardPeer `json:"peers" yaml:"peers"`
+}
+
+// NetworkStateWireGuardPeer represents the state of a WireGuard peer
+//
+// swagger:model
+//
+// API extension: network_peer_wireguard.
+type NetworkStateWireGuardPeer struct {
+	// Address and port of the remote end, as last seen
+	// Example: 198.51.100.10:51820
+	Endpoint string `json:"endpoint" yaml:"endpoint"`
+
+	// Time of the latest handshake with the remote end
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	LatestHandshake time.Time `json:"latest_handshake" yaml:"latest_handshake"`
+
+	// Number of bytes received from the remote end
+	// Example: 250542118
+	BytesReceived uint64 `json:"bytes_received" yaml:"bytes_received"`
+
+	// Number of bytes sent to the remote end
+	// Example: 17524040140
+	BytesSent uint64 `json:"bytes_sent" yaml:"bytes_sent"`
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"strings"
+	"time"
 )
 
 // NetworkACLRule represents a single rule in an ACL ruleset.
@@ -23,7 +24,7 @@ type NetworkACLRule struct {
 	Action string `json:"action" yaml:"action"`
 
 	// lxdmeta:generate(entities=network-acl; group=rule-properties; key=source)
-	// Sources can be specified as CIDR or IP ranges, source subject name selectors (for ingress rules), or be left empty for any.
+	// Sources can be specified as CIDR or IP ranges, address set references (`$<name>`), source subject name selectors (for ingress rules), or be left empty for any.
 	// ---
 	//  type: string
 	//  required: no
@@ -34,7 +35,7 @@ type NetworkACLRule struct {
 	Source string `json:"source,omitempty" yaml:"source,omitempty"`
 
 	// lxdmeta:generate(entities=network-acl; group=rule-properties; key=destination)
-	// Destinations can be specified as CIDR or IP ranges, destination subject name selectors (for egress rules), or be left empty for any.
+	// Destinations can be specified as CIDR or IP ranges, address set references (`$<name>`), destination subject name selectors (for egress rules), or be left empty for any.
 	// ---
 	//  type: string
 	//  required: no
@@ -126,6 +127,34 @@ type NetworkACLRule struct {
 	// State of the rule
 	// Example: enabled
 	State string `json:"state" yaml:"state"`
+
+	// lxdmeta:generate(entities=network-acl; group=rule-properties; key=active_from)
+	// Specify a date and time in RFC 3339 format, or leave the value empty for no start time.
+	// The rule only applies from that time on.
+	// ---
+	//  type: string
+	//  required: no
+	//  shortdesc: Time from which the rule applies
+
+	// Time from which the rule applies
+	// Example: 2026-01-01T08:00:00Z
+	//
+	// API extension: network_address_sets
+	ActiveFrom string `json:"active_from,omitempty" yaml:"active_from,omitempty"`
+
+	// lxdmeta:generate(entities=network-acl; group=rule-properties; key=active_until)
+	// Specify a date and time in RFC 3339 format, or leave the value empty for no end time.
+	// The rule no longer applies from that time on.
+	// ---
+	//  type: string
+	//  required: no
+	//  shortdesc: Time until which the rule applies
+
+	// Time until which the rule applies
+	// Example: 2026-01-31T18:00:00Z
+	//
+	// API extension: network_address_sets
+	ActiveUntil string `json:"active_until,omitempty" yaml:"active_until,omitempty"`
 }
 
 // Normalise normalises the fields in the rule so that they are comparable with ones stored.
@@ -136,6 +165,8 @@ func (r *NetworkACLRule) Normalise() {
 	r.ICMPCode = strings.TrimSpace(r.ICMPCode)
 	r.Description = strings.TrimSpace(r.Description)
 	r.State = strings.TrimSpace(r.State)
+	r.ActiveFrom = strings.TrimSpace(r.ActiveFrom)
+	r.ActiveUntil = strings.TrimSpace(r.ActiveUntil)
 
 	// Remove space from Source subject list.
 	subjects := strings.Split(r.Source, ",")
@@ -302,3 +333,94 @@ type NetworkACLsPost struct {
 	NetworkACLPost `yaml:",inline"`
 	NetworkACLPut  `yaml:",inline"`
 }
+
+// NetworkACLState represents the traffic matched by the rules of an ACL.
+//
+// swagger:model
+//
+// API extension: network_acl_state.
+type NetworkACLState struct {
+	// Counters of the egress rules (in the same order as the rules)
+	Egress []NetworkACLRuleState `json:"egress" yaml:"egress"`
+
+	// Counters of the ingress rules (in the same order as the rules)
+	Ingress []NetworkACLRuleState `json:"ingress" yaml:"ingress"`
+}
+
+// NetworkACLRuleState represents the traffic matched by an ACL rule.
+//
+// swagger:model
+//
+// API extension: network_acl_state.
+type NetworkACLRuleState struct {
+	// Number of packets matched by the rule
+	// Example: 2048
+	Packets uint64 `json:"packets" yaml:"packets"`
+
+	// Number of bytes matched by the rule
+	// Example: 1437216
+	Bytes uint64 `json:"bytes" yaml:"bytes"`
+}
+
+// NetworkACLLogEntry represents a packet logged by an ACL rule.
+//
+// swagger:model
+//
+// API extension: network_acl_log_follow.
+type NetworkACLLogEntry struct {
+	// Time the packet was logged
+	// Example: 2024-05-10T13:10:41Z
+	Time time.Time `json:"time" yaml:"time"`
+
+	// Cluster member that logged the packet
+	// Example: server01
+	Location string `json:"location,omitempty" yaml:"location,omitempty"`
+
+	// Project of the instance the packet was going to or coming from
+	// Example: default
+	Project string `json:"project,omitempty" yaml:"project,omitempty"`
+
+	// Instance the packet was going to or coming from
+	// Example: c1
+	Instance string `json:"instance,omitempty" yaml:"instance,omitempty"`
+
+	// Direction of the rule that logged the packet
+	// Example: ingress
+	Direction string `json:"direction" yaml:"direction"`
+
+	// Index of the rule that logged the packet in the list of rules of its direction
+	// Example: 0
+	Rule int `json:"rule" yaml:"rule"`
+
+	// Action taken
+	// Example: allow
+	Action string `json:"action" yaml:"action"`
+
+	// Protocol of the packet
+	// Example: tcp
+	Protocol string `json:"proto" yaml:"proto"`
+
+	// Source address
+	// Example: 10.0.0.2
+	Source string `json:"src" yaml:"src"`
+
+	// Destination address
+	// Example: 10.0.0.3
+	Destination string `json:"dst" yaml:"dst"`
+
+	// Source port
+	// Example: 50424
+	SourcePort string `json:"src_port,omitempty" yaml:"src_port,omitempty"`
+
+	// Destination port
+	// Example: 80
+	DestinationPort string `json:"dst_port,omitempty" yaml:"dst_port,omitempty"`
+
+	// ICMP type
+	// Example: 8
+	ICMPType string `json:"icmp_type,omitempty" yaml:"icmp_type,omitempty"`
+
+	// ICMP code
+	// Example: 0
+	ICMPCode string `json:"icmp_code,omitempty" yaml:"icmp_code,omitempty"`
+}
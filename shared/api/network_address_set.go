@@ -0,0 +1,91 @@
+package api
+
+// NetworkAddressSetPost used for renaming a network address set.
+//
+// swagger:model
+//
+// API extension: network_address_sets.
+type NetworkAddressSetPost struct {
+	// The new name for the address set
+	// Example: trusted-hosts
+	Name string `json:"name" yaml:"name"`
+}
+
+// NetworkAddressSetPut used for updating a network address set.
+//
+// swagger:model
+//
+// API extension: network_address_sets.
+type NetworkAddressSetPut struct {
+	// Description of the address set
+	// Example: Trusted monitoring hosts
+	Description string `json:"description" yaml:"description"`
+
+	// List of IP addresses and CIDR subnets in the address set
+	// Example: ["192.0.2.10", "198.51.100.0/24", "2001:db8::/64"]
+	Addresses []string `json:"addresses" yaml:"addresses"`
+
+	// Address set configuration map (refer to doc/network-address-sets.md)
+	// Example: {"user.mykey": "foo"}
+	Config map[string]string `json:"config" yaml:"config"`
+}
+
+// NetworkAddressSet used for displaying a network address set.
+//
+// swagger:model
+//
+// API extension: network_address_sets.
+type NetworkAddressSet struct {
+	WithEntitlements `yaml:",inline"`
+
+	// The name of the address set
+	// Example: trusted-hosts
+	Name string `json:"name" yaml:"name"`
+
+	// Description of the address set
+	// Example: Trusted monitoring hosts
+	Description string `json:"description" yaml:"description"`
+
+	// List of IP addresses and CIDR subnets in the address set
+	// Example: ["192.0.2.10", "198.51.100.0/24", "2001:db8::/64"]
+	Addresses []string `json:"addresses" yaml:"addresses"`
+
+	// Address set configuration map (refer to doc/network-address-sets.md)
+	// Example: {"user.mykey": "foo"}
+	Config map[string]string `json:"config" yaml:"config"`
+
+	// List of URLs of network ACLs using this address set
+	// Read only: true
+	// Example: ["/1.0/network-acls/web"]
+	UsedBy []string `json:"used_by" yaml:"used_by"`
+
+	// Project name
+	// Example: project1
+	Project string `json:"project" yaml:"project"`
+}
+
+// Writable converts a full NetworkAddressSet struct into a NetworkAddressSetPut struct (filters read-only fields).
+func (addrSet *NetworkAddressSet) Writable() NetworkAddressSetPut {
+	return NetworkAddressSetPut{
+		Description: addrSet.Description,
+		Addresses:   addrSet.Addresses,
+		Config:      addrSet.Config,
+	}
+}
+
+// SetWritable sets applicable values from NetworkAddressSetPut struct to NetworkAddressSet struct.
+func (addrSet *NetworkAddressSet) SetWritable(put NetworkAddressSetPut) {
+	addrSet.Description = put.Description
+	addrSet.Addresses = put.Addresses
+	addrSet.Config = put.Config
+}
+
+// NetworkAddressSetsPost used for creating a network address set.
+//
+// swagger:model
+//
+// API extension: network_address_sets.
+type NetworkAddressSetsPost struct {
+	NetworkAddressSetPost `yaml:",inline"`
+	NetworkAddressSetPut  `yaml:",inline"`
+}
@@ -55,6 +55,19 @@ type NetworkLoadBalancerBackend struct {
 	// TargetAddress to forward ListenPorts to
 	// Example: 198.51.100.2
 	TargetAddress string `json:"target_address" yaml:"target_address"`
+
+	// lxdmeta:generate(entities=network-load-balancer; group=load-balancer-backend-properties; key=config)
+	// Supports the `healthcheck` and `healthcheck.*` options, which override the ones of the load balancer for this backend.
+	// ---
+	//  type: string set
+	//  required: no
+	//  shortdesc: Health check configuration of the backend
+
+	// Backend configuration map (refer to doc/network-load-balancers.md)
+	// Example: {"healthcheck.interval": "5"}
+	//
+	// API extension: network_load_balancer_health_check
+	Config map[string]string `json:"config,omitempty" yaml:"config,omitempty"`
 }
 
 // Normalise normalises the fields in the load balancer backend so that they are comparable with ones stored.
@@ -179,7 +192,8 @@ type NetworkLoadBalancerPut struct {
 	Description string `json:"description" yaml:"description"`
 
 	// lxdmeta:generate(entities=network-load-balancer; group=load-balancer-properties; key=config)
-	// The only supported keys are `user.*` custom keys.
+	// See {ref}`network-load-balancers-health-checks` for the supported `healthcheck.*` keys.
+	// Custom keys must use the `user.*` prefix.
 	// ---
 	//  type: string set
 	//  required: no
@@ -281,3 +295,46 @@ func (lb *NetworkLoadBalancer) SetWritable(put NetworkLoadBalancerPut) {
 	lb.Backends = put.Backends
 	lb.Ports = put.Ports
 }
+
+// NetworkLoadBalancerState is used for showing current state of a load balancer
+//
+// swagger:model
+//
+// API extension: network_load_balancer_health_check.
+type NetworkLoadBalancerState struct {
+	// Health of each backend, keyed by backend name
+	BackendHealth map[string]NetworkLoadBalancerStateBackendHealth `json:"backend_health" yaml:"backend_health"`
+}
+
+// NetworkLoadBalancerStateBackendHealth represents the health of a load balancer backend
+//
+// swagger:model
+//
+// API extension: network_load_balancer_health_check.
+type NetworkLoadBalancerStateBackendHealth struct {
+	// Target address of the backend
+	// Example: 198.51.100.2
+	Address string `json:"address" yaml:"address"`
+
+	// Health of each of the backend target ports
+	Ports []NetworkLoadBalancerStateBackendHealthPort `json:"ports" yaml:"ports"`
+}
+
+// NetworkLoadBalancerStateBackendHealthPort represents the health of a load balancer backend target port
+//
+// swagger:model
+//
+// API extension: network_load_balancer_health_check.
+type NetworkLoadBalancerStateBackendHealthPort struct {
+	// Protocol of the target port (either tcp or udp)
+	// Example: tcp
+	Protocol string `json:"protocol" yaml:"protocol"`
+
+	// Target port
+	// Example: 80
+	Port int `json:"port" yaml:"port"`
+
+	// Health status of the target port (either online, offline or unknown)
+	// Example: online
+	Status string `json:"status" yaml:"status"`
+}
@@ -18,6 +18,17 @@ const (
 	PlacementRigorPermissive string = "permissive"
 )
 
+const (
+	// PlacementDomainMember treats each cluster member as its own failure domain.
+	PlacementDomainMember string = "member"
+
+	// PlacementDomainClusterGroup groups cluster members into failure domains by cluster group.
+	PlacementDomainClusterGroup string = "cluster_group"
+
+	// PlacementDomainFailureDomain groups cluster members into failure domains by their cluster failure domain.
+	PlacementDomainFailureDomain string = "failure_domain"
+)
+
 // PlacementGroup represents a group of instances that should be scheduled.
 //
 // API extension: instance_placement_groups.
@@ -43,6 +54,12 @@ type PlacementGroup struct {
 	// List of URLs of objects using this placement group.
 	// Example: ["/1.0/instances/c1", "/1.0/profiles/default"]
 	UsedBy []string `json:"used_by" yaml:"used_by"`
+
+	// Failure domain of each instance in the placement group, keyed by instance URL.
+	// Example: {"/1.0/instances/c1": "rack1", "/1.0/instances/c2": "rack2"}
+	//
+	// API extension: placement_group_domains.
+	Domains map[string]string `json:"domains" yaml:"domains"`
 }
 
 // PlacementGroupsPost represents the fields required to create a new placement group.
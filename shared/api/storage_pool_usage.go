@@ -0,0 +1,104 @@
+package api
+
+// StoragePoolUsage represents the disk usage of the instance and custom volumes of a storage pool
+//
+// swagger:model
+//
+// API extension: storage_pool_usage.
+type StoragePoolUsage struct {
+	// Disk usage of each volume
+	Volumes []StoragePoolVolumeUsage `json:"volumes" yaml:"volumes"`
+
+	// Disk usage of the volumes aggregated per project
+	Projects []StoragePoolProjectUsage `json:"projects" yaml:"projects"`
+}
+
+// StoragePoolVolumeUsage represents the disk usage of a storage volume and its snapshots
+//
+// swagger:model
+//
+// API extension: storage_pool_usage.
+type StoragePoolVolumeUsage struct {
+	// Volume name
+	// Example: foo
+	Name string `json:"name" yaml:"name"`
+
+	// Volume type
+	// Example: custom
+	Type string `json:"type" yaml:"type"`
+
+	// Project the volume belongs to
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+
+	// Cluster member the volume is located on (empty for volumes on remote storage pools)
+	// Example: lxd01
+	Location string `json:"location" yaml:"location"`
+
+	// Used space in bytes. Uses 0 to indicate that the storage driver for the pool does not support retrieving volume usage.
+	// Example: 1693552640
+	UsedBytes int64 `json:"used_bytes" yaml:"used_bytes"`
+
+	// Provisioned size in bytes. Uses 0 to convey that the volume has access to the entire pool's storage.
+	// Example: 10737418240
+	ProvisionedBytes int64 `json:"provisioned_bytes" yaml:"provisioned_bytes"`
+
+	// Error retrieving the volume usage, in which case the used and provisioned space are 0
+	// Example: Failed to run: zfs get: exit status 1
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+
+	// Disk usage of the volume snapshots
+	Snapshots []StoragePoolVolumeSnapshotUsage `json:"snapshots" yaml:"snapshots"`
+}
+
+// StoragePoolVolumeSnapshotUsage represents the disk usage of a storage volume snapshot
+//
+// swagger:model
+//
+// API extension: storage_pool_usage.
+type StoragePoolVolumeSnapshotUsage struct {
+	// Snapshot name
+	// Example: snap0
+	Name string `json:"name" yaml:"name"`
+
+	// Used space in bytes. Uses 0 to indicate that the storage driver for the pool does not support retrieving snapshot usage.
+	// Example: 16384
+	UsedBytes int64 `json:"used_bytes" yaml:"used_bytes"`
+
+	// Provisioned size in bytes. Uses 0 to convey that the snapshot has access to the entire pool's storage.
+	// Example: 10737418240
+	ProvisionedBytes int64 `json:"provisioned_bytes" yaml:"provisioned_bytes"`
+
+	// Error retrieving the snapshot usage, in which case the used and provisioned space are 0
+	// Example: Failed to run: zfs get: exit status 1
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// StoragePoolProjectUsage represents the disk usage of a project on a storage pool
+//
+// swagger:model
+//
+// API extension: storage_pool_usage.
+type StoragePoolProjectUsage struct {
+	// Project name
+	// Example: default
+	Name string `json:"name" yaml:"name"`
+
+	// Space used by the project's volumes and their snapshots in bytes
+	// Example: 1693568000
+	UsedBytes int64 `json:"used_bytes" yaml:"used_bytes"`
+
+	// Sum of the provisioned sizes of the project's volumes in bytes, as counted against the project's disk limits
+	// Example: 21474836480
+	ProvisionedBytes int64 `json:"provisioned_bytes" yaml:"provisioned_bytes"`
+
+	// Disk limit of the project on the storage pool in bytes. Uses -1 to convey that the project has no disk limit.
+	// Not set if the requester isn't allowed to view the project.
+	// Example: 53687091200
+	LimitBytes *int64 `json:"limit_bytes,omitempty" yaml:"limit_bytes,omitempty"`
+
+	// Whether the usage of some of the project's volumes or snapshots couldn't be retrieved, in which case the
+	// used and provisioned space only include the other ones
+	// Example: false
+	Incomplete bool `json:"incomplete,omitempty" yaml:"incomplete,omitempty"`
+}
@@ -75,6 +75,12 @@ type StorageVolumePost struct {
 	//
 	// API extension: cluster_internal_custom_volume_copy
 	Source StorageVolumeSource `json:"source" yaml:"source"`
+
+	// Move the volume to the new pool while in use by running instances
+	// Example: false
+	//
+	// API extension: storage_volume_live_move
+	Live bool `json:"live,omitempty" yaml:"live,omitempty"`
 }
 
 // StorageVolumePostTarget represents the migration target host and operation
@@ -111,6 +111,9 @@ const (
 
 	// TypePlacementGroup represents placement group resources.
 	TypePlacementGroup Type = "placement_group"
+
+	// TypeNetworkAddressSet represents network address set resources.
+	TypeNetworkAddressSet Type = "network_address_set"
 )
 
 const (
@@ -173,6 +176,7 @@ var entityTypes = map[Type]typeInfo{
 	TypeAuthGroup:             authGroup{},
 	TypeIdentityProviderGroup: identityProviderGroup{},
 	TypePlacementGroup:        placementGroup{},
+	TypeNetworkAddressSet:     networkAddressSet{},
 }
 
 // metricsEntityTypes is the source of truth for which entity types can be used to categorize endpoints
@@ -515,3 +519,15 @@ func (placementGroup) requiresProject() bool {
 func (placementGroup) path() []string {
 	return []string{"placement-groups", pathPlaceholder}
 }
+
+type networkAddressSet struct {
+	typeInfoCommon
+}
+
+func (networkAddressSet) requiresProject() bool {
+	return true
+}
+
+func (networkAddressSet) path() []string {
+	return []string{"network-address-sets", pathPlaceholder}
+}
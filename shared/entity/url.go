@@ -274,3 +274,8 @@ func IdentityProviderGroupURL(identityProviderGroupName string) *api.URL {
 func PlacementGroupURL(projectName string, placementGroupName string) *api.URL {
 	return TypePlacementGroup.urlMust(projectName, "", placementGroupName)
 }
+
+// NetworkAddressSetURL returns an [*api.URL] to a network address set.
+func NetworkAddressSetURL(projectName string, networkAddressSetName string) *api.URL {
+	return TypeNetworkAddressSet.urlMust(projectName, "", networkAddressSetName)
+}
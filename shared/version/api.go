@@ -469,6 +469,32 @@ var APIExtensions = []string{
 	"instance_placement_groups",
 	"ovn_nic_acceleration_parent",
 	"storage_and_profile_operations",
+	"placement_group_domains",
+	"cluster_rebalance",
+	"backups_schedule",
+	"backup_targets",
+	"backup_incremental",
+	"backup_info",
+	"backup_encryption",
+	"storage_replication",
+	"storage_volume_live_move",
+	"storage_pool_usage",
+	"network_load_balancer_health_check",
+	"network_load_balancer_bridge",
+	"network_dns_authoritative",
+	"network_zones_service_records",
+	"network_peer_wireguard",
+	"network_bgp_policies",
+	"network_bgp_peer_timers",
+	"network_acl_state",
+	"network_acl_log_follow",
+	"network_address_sets",
+	"network_dhcp_options",
+	"vm_memory_hotplug",
+	"backup_changed_block_tracking",
+	"instance_live_pool_move",
+	"console_vnc_type",
+	"console_sessions",
 }
 
 // APIExtensionsCount returns the number of available API extensions.